@@ -24,12 +24,18 @@ CREATE TABLE IF NOT EXISTS  `product_skus` (
     `stock` bigint NOT NULL COMMENT '库存数量',
     `stock_limit` bigint NOT NULL COMMENT '库存限制',
     `sale_type` tinyint unsigned NOT NULL DEFAULT '1' COMMENT '销售类型: 1=无限期 2=限时促销 3=预售',
+    `sale_start` bigint NOT NULL DEFAULT '0' COMMENT '销售开始时间,UTC Unix毫秒数,0表示立刻开始',
+    `sale_end` bigint NOT NULL DEFAULT '0' COMMENT '销售结束时间,UTC Unix毫秒数,0表示不会结束',
+    `promotion_price` bigint NOT NULL DEFAULT '0' COMMENT '限时促销价格,单位为分',
+    `release_at` bigint NOT NULL DEFAULT '0' COMMENT '预售商品的发售时间,UTC Unix毫秒数',
     `status` tinyint unsigned NOT NULL DEFAULT '1' COMMENT '状态 1=下架 2=上架',
     `ctime` bigint DEFAULT NULL,
     `utime` bigint DEFAULT NULL,
     PRIMARY KEY (`id`),
     UNIQUE KEY `uniq_product_sku_sn` (`sn`),
-    KEY `idx_product_spu_id` (`product_spu_id`)
+    KEY `idx_product_spu_id` (`product_spu_id`),
+    KEY `idx_sale_start` (`sale_start`),
+    KEY `idx_sale_end` (`sale_end`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 
 
@@ -41,3 +47,17 @@ VALUES
     ('SKU004', 1, '年会员', '提供一年的会员服务', 11880, 1000, 100000000, 1, 2, UNIX_TIMESTAMP(), UNIX_TIMESTAMP()),
     ('SKU005', 2, '用户项目', '中小型面试项目', 9999, 1000, 100000000, 1, 2, UNIX_TIMESTAMP(), UNIX_TIMESTAMP()),
     ('SKU006', 2, '权限项目', '中大型面试项目', 19999, 1000, 100000000, 1, 2, UNIX_TIMESTAMP(), UNIX_TIMESTAMP());
+
+CREATE TABLE IF NOT EXISTS `stock_reservations` (
+    `id` bigint NOT NULL AUTO_INCREMENT COMMENT '库存预留记录自增ID',
+    `order_sn` varchar(255) NOT NULL COMMENT '订单序列号',
+    `sku_id` bigint NOT NULL COMMENT '商品SKU自增ID',
+    `uid` bigint NOT NULL COMMENT '购买者ID',
+    `quantity` bigint NOT NULL COMMENT '预留数量',
+    `status` tinyint unsigned NOT NULL DEFAULT '1' COMMENT '状态 1=已预留 2=已确认 3=已释放',
+    `ctime` bigint DEFAULT NULL,
+    `utime` bigint DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    UNIQUE KEY `uniq_order_sn_sku_id` (`order_sn`, `sku_id`),
+    KEY `idx_uid_sku_id` (`uid`, `sku_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,30 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "github.com/ecodeclub/webook/internal/product"
+
+// Item 购物车中的一行，同一个 SKU 在购物车中只有一行
+type Item struct {
+	SN       string
+	Quantity int64
+	Ctime    int64
+	Utime    int64
+
+	// Product 实时的商品信息，只有在展示购物车的时候才会填充
+	Product product.Product
+	// Available 商品是否上架并且在销售时间内
+	Available bool
+}
@@ -0,0 +1,28 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package errs
+
+var (
+	SystemError     = ErrorCode{Code: 511001, Msg: "系统错误"}
+	InvalidQuantity = ErrorCode{Code: 511002, Msg: "商品数量非法"}
+	CartFull        = ErrorCode{Code: 511003, Msg: "购物车已满"}
+	NotAvailable    = ErrorCode{Code: 511004, Msg: "商品已下架或者不在销售时间内"}
+	ItemNotFound    = ErrorCode{Code: 511005, Msg: "购物车中没有这个商品"}
+)
+
+type ErrorCode struct {
+	Code int
+	Msg  string
+}
@@ -0,0 +1,313 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ekit/net/httpx/httptestx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/cart/internal/errs"
+	"github.com/ecodeclub/webook/internal/cart/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/cart/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/cart/internal/web"
+	"github.com/ecodeclub/webook/internal/product"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const uid = 3501
+
+// fakeProductService 只有 SKU100 和 SKU101 可以购买，SKU102 已经下架
+type fakeProductService struct {
+	product.Service
+	offShelf map[string]bool
+}
+
+func (f *fakeProductService) FindBySN(_ context.Context, sn string) (product.Product, error) {
+	products := map[string]product.Product{
+		"SKU100": {
+			SPU: product.SPU{ID: 100, SN: "SPU100", Name: "会员服务"},
+			SKU: product.SKU{ID: 100, SN: "SKU100", Name: "月会员", Desc: "一个月的会员", Price: 990, Stock: 10, SaleType: 1},
+		},
+		"SKU101": {
+			SPU: product.SPU{ID: 100, SN: "SPU100", Name: "会员服务"},
+			SKU: product.SKU{ID: 101, SN: "SKU101", Name: "年会员", Desc: "一年的会员", Price: 9900, Stock: 10, SaleType: 1},
+		},
+	}
+	p, ok := products[sn]
+	if !ok || f.offShelf[sn] {
+		return product.Product{}, fmt.Errorf("%w: %s", product.ErrNotFound, sn)
+	}
+	return p, nil
+}
+
+func (f *fakeProductService) FindBySNs(ctx context.Context, sns []string) ([]product.Product, error) {
+	res := make([]product.Product, 0, len(sns))
+	for _, sn := range sns {
+		p, err := f.FindBySN(ctx, sn)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server     *egin.Component
+	db         *egorm.Component
+	rdb        redis.Cmdable
+	productSvc *fakeProductService
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	s.productSvc = &fakeProductService{offShelf: map[string]bool{}}
+	handler := startup.InitHandler(s.productSvc)
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid: uid,
+		}))
+	})
+	handler.PrivateRoutes(server.Engine)
+	s.server = server
+	s.db = testioc.InitDB()
+	s.rdb = testioc.InitRedis()
+}
+
+func (s *HandlerTestSuite) TearDownSuite() {
+	err := s.db.Exec("DROP TABLE `cart_items`").Error
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `cart_items`").Error
+	require.NoError(s.T(), err)
+	err = s.rdb.Del(context.Background(), fmt.Sprintf("webook:cart:%d", uid)).Err()
+	require.NoError(s.T(), err)
+	s.productSvc.offShelf = map[string]bool{}
+}
+
+func (s *HandlerTestSuite) TestAdd() {
+	testCases := []struct {
+		name     string
+		before   func(t *testing.T)
+		after    func(t *testing.T)
+		req      web.ItemReq
+		wantCode int
+		wantResp test.Result[any]
+	}{
+		{
+			name:   "新加入购物车",
+			before: func(t *testing.T) {},
+			after: func(t *testing.T) {
+				var item dao.CartItem
+				require.NoError(t, s.db.Where("uid = ? AND sn = ?", uid, "SKU100").First(&item).Error)
+				assert.Equal(t, int64(2), item.Quantity)
+			},
+			req:      web.ItemReq{SN: "SKU100", Quantity: 2},
+			wantCode: 200,
+			wantResp: test.Result[any]{Msg: "OK"},
+		},
+		{
+			name: "已经在购物车中累加数量",
+			before: func(t *testing.T) {
+				require.NoError(t, s.db.Create(&dao.CartItem{Uid: uid, SN: "SKU101", Quantity: 1}).Error)
+			},
+			after: func(t *testing.T) {
+				var items []dao.CartItem
+				require.NoError(t, s.db.Where("uid = ? AND sn = ?", uid, "SKU101").Find(&items).Error)
+				require.Len(t, items, 1)
+				assert.Equal(t, int64(4), items[0].Quantity)
+			},
+			req:      web.ItemReq{SN: "SKU101", Quantity: 3},
+			wantCode: 200,
+			wantResp: test.Result[any]{Msg: "OK"},
+		},
+		{
+			name:     "数量非法",
+			before:   func(t *testing.T) {},
+			after:    func(t *testing.T) {},
+			req:      web.ItemReq{SN: "SKU100", Quantity: 0},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: errs.InvalidQuantity.Code, Msg: errs.InvalidQuantity.Msg},
+		},
+		{
+			name:     "商品不存在",
+			before:   func(t *testing.T) {},
+			after:    func(t *testing.T) {},
+			req:      web.ItemReq{SN: "SKU999", Quantity: 1},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: errs.NotAvailable.Code, Msg: errs.NotAvailable.Msg},
+		},
+		{
+			name: "累加之后超出库存",
+			before: func(t *testing.T) {
+				require.NoError(t, s.db.Create(&dao.CartItem{Uid: uid, SN: "SKU101", Quantity: 8}).Error)
+			},
+			after: func(t *testing.T) {
+				var item dao.CartItem
+				require.NoError(t, s.db.Where("uid = ? AND sn = ?", uid, "SKU101").First(&item).Error)
+				assert.Equal(t, int64(8), item.Quantity)
+			},
+			req:      web.ItemReq{SN: "SKU101", Quantity: 3},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: errs.InvalidQuantity.Code, Msg: errs.InvalidQuantity.Msg},
+		},
+		{
+			name: "购物车已满",
+			before: func(t *testing.T) {
+				items := make([]dao.CartItem, 0, 100)
+				for i := 0; i < 100; i++ {
+					items = append(items, dao.CartItem{Uid: uid, SN: fmt.Sprintf("SKU-%d", i), Quantity: 1})
+				}
+				require.NoError(t, s.db.Create(&items).Error)
+			},
+			after:    func(t *testing.T) {},
+			req:      web.ItemReq{SN: "SKU100", Quantity: 1},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: errs.CartFull.Code, Msg: errs.CartFull.Msg},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			recorder := s.post(t, "/cart/add", tc.req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+			tc.after(t)
+			s.TearDownTest()
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestUpdateAndRemove() {
+	t := s.T()
+	recorder := s.post(t, "/cart/add", web.ItemReq{SN: "SKU100", Quantity: 1})
+	require.Equal(t, 200, recorder.Code)
+	recorder = s.post(t, "/cart/add", web.ItemReq{SN: "SKU101", Quantity: 1})
+	require.Equal(t, 200, recorder.Code)
+
+	recorder = s.post(t, "/cart/update", web.ItemReq{SN: "SKU100", Quantity: 5})
+	require.Equal(t, 200, recorder.Code)
+	recorder = s.post(t, "/cart/update", web.ItemReq{SN: "SKU999", Quantity: 5})
+	require.Equal(t, 500, recorder.Code)
+	assert.Equal(t, errs.ItemNotFound.Code, recorder.MustScan().Code)
+	recorder = s.post(t, "/cart/update", web.ItemReq{SN: "SKU100", Quantity: -1})
+	require.Equal(t, 500, recorder.Code)
+	assert.Equal(t, errs.InvalidQuantity.Code, recorder.MustScan().Code)
+	// 超出库存
+	recorder = s.post(t, "/cart/update", web.ItemReq{SN: "SKU100", Quantity: 11})
+	require.Equal(t, 500, recorder.Code)
+	assert.Equal(t, errs.InvalidQuantity.Code, recorder.MustScan().Code)
+	// 已经下架
+	s.productSvc.offShelf["SKU101"] = true
+	recorder = s.post(t, "/cart/update", web.ItemReq{SN: "SKU101", Quantity: 2})
+	require.Equal(t, 500, recorder.Code)
+	assert.Equal(t, errs.NotAvailable.Code, recorder.MustScan().Code)
+	s.productSvc.offShelf["SKU101"] = false
+
+	cart := s.list(t)
+	require.Len(t, cart.Items, 2)
+	assert.Equal(t, "SKU100", s.findItem(cart, "SKU100").SN)
+	assert.Equal(t, int64(5), s.findItem(cart, "SKU100").Quantity)
+
+	recorder = s.post(t, "/cart/remove", web.RemoveReq{SNs: []string{"SKU100"}})
+	require.Equal(t, 200, recorder.Code)
+	cart = s.list(t)
+	require.Len(t, cart.Items, 1)
+	assert.Equal(t, "SKU101", cart.Items[0].SN)
+}
+
+func (s *HandlerTestSuite) TestList() {
+	t := s.T()
+	require.NoError(t, s.db.Create(&[]dao.CartItem{
+		{Uid: uid, SN: "SKU100", Quantity: 2, Ctime: 1, Utime: 1},
+		{Uid: uid, SN: "SKU101", Quantity: 1, Ctime: 2, Utime: 2},
+	}).Error)
+
+	// 按照加入购物车的时间倒序，带有实时价格
+	cart := s.list(t)
+	assert.Equal(t, []web.Item{
+		{SN: "SKU101", Quantity: 1, Available: true, SPUSN: "SPU100", Name: "年会员", Desc: "一年的会员",
+			OriginalPrice: 9900, RealPrice: 9900, Stock: 10, Utime: 2},
+		{SN: "SKU100", Quantity: 2, Available: true, SPUSN: "SPU100", Name: "月会员", Desc: "一个月的会员",
+			OriginalPrice: 990, RealPrice: 990, Stock: 10, Utime: 1},
+	}, cart.Items)
+
+	// 缓存已经重建，直接修改数据库不会影响结果
+	exists, err := s.rdb.Exists(context.Background(), fmt.Sprintf("webook:cart:%d", uid)).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), exists)
+	require.NoError(t, s.db.Model(&dao.CartItem{}).Where("sn = ?", "SKU100").Update("quantity", 3).Error)
+	cart = s.list(t)
+	assert.Equal(t, int64(2), s.findItem(cart, "SKU100").Quantity)
+
+	// 下架的商品依旧在购物车中，但是不可购买
+	s.productSvc.offShelf["SKU101"] = true
+	cart = s.list(t)
+	require.Len(t, cart.Items, 2)
+	assert.Equal(t, web.Item{SN: "SKU101", Quantity: 1, Utime: 2}, s.findItem(cart, "SKU101"))
+	assert.True(t, s.findItem(cart, "SKU100").Available)
+}
+
+func (s *HandlerTestSuite) post(t *testing.T, path string, body any) *httptestx.JSONResponseRecorder[test.Result[any]] {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func (s *HandlerTestSuite) list(t *testing.T) web.Cart {
+	req, err := http.NewRequest(http.MethodPost, "/cart/list", iox.NewJSONReader(nil))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[web.Cart]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func (s *HandlerTestSuite) findItem(cart web.Cart, sn string) web.Item {
+	for _, item := range cart.Items {
+		if item.SN == sn {
+			return item
+		}
+	}
+	return web.Item{}
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
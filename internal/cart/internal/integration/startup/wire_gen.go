@@ -0,0 +1,23 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package startup
+
+import (
+	"github.com/ecodeclub/webook/internal/cart"
+	"github.com/ecodeclub/webook/internal/cart/internal/web"
+	"github.com/ecodeclub/webook/internal/product"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+)
+
+// Injectors from wire.go:
+
+func InitHandler(productSvc product.Service) *web.Handler {
+	db := testioc.InitDB()
+	cmdable := testioc.InitRedis()
+	handler := cart.InitHandler(db, cmdable, productSvc)
+	return handler
+}
@@ -0,0 +1,114 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/cart/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrKeyNotExist = errors.New("购物车缓存不存在")
+
+// CartCache 使用 Hash 缓存用户的购物车，字段为 SKU SN
+// markerField 用于区分"缓存不存在"和"购物车为空"
+type CartCache interface {
+	// GetItems 缓存不存在时返回 ErrKeyNotExist
+	GetItems(ctx context.Context, uid int64) ([]domain.Item, error)
+	SetItems(ctx context.Context, uid int64, items []domain.Item) error
+	Delete(ctx context.Context, uid int64) error
+}
+
+const markerField = "_"
+
+type cartRedisCache struct {
+	cmd        redis.Cmdable
+	expiration time.Duration
+}
+
+func NewCartRedisCache(cmd redis.Cmdable) CartCache {
+	return &cartRedisCache{
+		cmd:        cmd,
+		expiration: time.Hour * 24,
+	}
+}
+
+type item struct {
+	Quantity int64 `json:"quantity"`
+	Ctime    int64 `json:"ctime"`
+	Utime    int64 `json:"utime"`
+}
+
+func (c *cartRedisCache) GetItems(ctx context.Context, uid int64) ([]domain.Item, error) {
+	fields, err := c.cmd.HGetAll(ctx, c.key(uid)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fields[markerField]; !ok {
+		return nil, ErrKeyNotExist
+	}
+	res := make([]domain.Item, 0, len(fields)-1)
+	for sn, val := range fields {
+		if sn == markerField {
+			continue
+		}
+		var it item
+		if err = json.Unmarshal([]byte(val), &it); err != nil {
+			return nil, err
+		}
+		res = append(res, domain.Item{SN: sn, Quantity: it.Quantity, Ctime: it.Ctime, Utime: it.Utime})
+	}
+	// Hash 是无序的，和数据库保持一致按照加入购物车的时间倒序
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Ctime == res[j].Ctime {
+			return res[i].SN < res[j].SN
+		}
+		return res[i].Ctime > res[j].Ctime
+	})
+	return res, nil
+}
+
+func (c *cartRedisCache) SetItems(ctx context.Context, uid int64, items []domain.Item) error {
+	values := make([]any, 0, 2*len(items)+2)
+	values = append(values, markerField, 1)
+	for _, it := range items {
+		val, err := json.Marshal(item{Quantity: it.Quantity, Ctime: it.Ctime, Utime: it.Utime})
+		if err != nil {
+			return err
+		}
+		values = append(values, it.SN, val)
+	}
+	key := c.key(uid)
+	pipe := c.cmd.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.HSet(ctx, key, values...)
+	pipe.Expire(ctx, key, c.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *cartRedisCache) Delete(ctx context.Context, uid int64) error {
+	return c.cmd.Del(ctx, c.key(uid)).Err()
+}
+
+func (c *cartRedisCache) key(uid int64) string {
+	return fmt.Sprintf("webook:cart:%d", uid)
+}
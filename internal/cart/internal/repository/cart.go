@@ -0,0 +1,119 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/cart/internal/domain"
+	"github.com/ecodeclub/webook/internal/cart/internal/repository/cache"
+	"github.com/ecodeclub/webook/internal/cart/internal/repository/dao"
+	"github.com/gotomicro/ego/core/elog"
+	"gorm.io/gorm"
+)
+
+var ErrItemNotFound = errors.New("购物车中没有这个商品")
+
+type CartRepository interface {
+	Add(ctx context.Context, uid int64, item domain.Item) error
+	UpdateQuantity(ctx context.Context, uid int64, sn string, quantity int64) error
+	Delete(ctx context.Context, uid int64, sns []string) error
+	// FindItems 按照加入购物车的时间倒序排列
+	FindItems(ctx context.Context, uid int64) ([]domain.Item, error)
+}
+
+// cachedCartRepository 以 MySQL 为准，修改之后删除 Redis 中的缓存，读取的时候重建
+type cachedCartRepository struct {
+	dao    dao.CartDAO
+	cache  cache.CartCache
+	logger *elog.Component
+}
+
+func NewCachedCartRepository(d dao.CartDAO, c cache.CartCache) CartRepository {
+	return &cachedCartRepository{
+		dao:    d,
+		cache:  c,
+		logger: elog.DefaultLogger,
+	}
+}
+
+func (repo *cachedCartRepository) Add(ctx context.Context, uid int64, item domain.Item) error {
+	err := repo.dao.Add(ctx, dao.CartItem{
+		Uid:      uid,
+		SN:       item.SN,
+		Quantity: item.Quantity,
+	})
+	if err != nil {
+		return err
+	}
+	repo.invalidate(ctx, uid)
+	return nil
+}
+
+func (repo *cachedCartRepository) UpdateQuantity(ctx context.Context, uid int64, sn string, quantity int64) error {
+	err := repo.dao.UpdateQuantity(ctx, uid, sn, quantity)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrItemNotFound
+	}
+	if err != nil {
+		return err
+	}
+	repo.invalidate(ctx, uid)
+	return nil
+}
+
+func (repo *cachedCartRepository) Delete(ctx context.Context, uid int64, sns []string) error {
+	err := repo.dao.Delete(ctx, uid, sns)
+	if err != nil {
+		return err
+	}
+	repo.invalidate(ctx, uid)
+	return nil
+}
+
+func (repo *cachedCartRepository) FindItems(ctx context.Context, uid int64) ([]domain.Item, error) {
+	items, err := repo.cache.GetItems(ctx, uid)
+	if err == nil {
+		return items, nil
+	}
+	if !errors.Is(err, cache.ErrKeyNotExist) {
+		repo.logger.Error("从缓存中获取购物车失败", elog.FieldErr(err), elog.Int64("uid", uid))
+	}
+	entities, err := repo.dao.FindByUid(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	items = slice.Map(entities, func(idx int, src dao.CartItem) domain.Item {
+		return domain.Item{
+			SN:       src.SN,
+			Quantity: src.Quantity,
+			Ctime:    src.Ctime,
+			Utime:    src.Utime,
+		}
+	})
+	if err = repo.cache.SetItems(ctx, uid, items); err != nil {
+		repo.logger.Error("重建购物车缓存失败", elog.FieldErr(err), elog.Int64("uid", uid))
+	}
+	return items, nil
+}
+
+// invalidate 数据库已经修改成功，删除缓存失败只记录日志，依赖过期时间兜底
+func (repo *cachedCartRepository) invalidate(ctx context.Context, uid int64) {
+	if err := repo.cache.Delete(ctx, uid); err != nil {
+		repo.logger.Error("删除购物车缓存失败", elog.FieldErr(err), elog.Int64("uid", uid))
+	}
+}
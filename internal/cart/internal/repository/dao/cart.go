@@ -0,0 +1,89 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CartDAO interface {
+	// Add 不存在的时候新建，存在的时候累加数量
+	Add(ctx context.Context, item CartItem) error
+	// UpdateQuantity 返回 gorm.ErrRecordNotFound 表示购物车中没有这个商品
+	UpdateQuantity(ctx context.Context, uid int64, sn string, quantity int64) error
+	Delete(ctx context.Context, uid int64, sns []string) error
+	// FindByUid 按照加入购物车的时间倒序排列
+	FindByUid(ctx context.Context, uid int64) ([]CartItem, error)
+}
+
+type cartGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewCartGORMDAO(db *egorm.Component) CartDAO {
+	return &cartGORMDAO{db: db}
+}
+
+func (dao *cartGORMDAO) Add(ctx context.Context, item CartItem) error {
+	now := time.Now().UnixMilli()
+	item.Ctime, item.Utime = now, now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"quantity": gorm.Expr("quantity + ?", item.Quantity),
+			"utime":    now,
+		}),
+	}).Create(&item).Error
+}
+
+func (dao *cartGORMDAO) UpdateQuantity(ctx context.Context, uid int64, sn string, quantity int64) error {
+	res := dao.db.WithContext(ctx).Model(&CartItem{}).
+		Where("uid = ? AND sn = ?", uid, sn).
+		Updates(map[string]any{
+			"quantity": quantity,
+			"utime":    time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (dao *cartGORMDAO) Delete(ctx context.Context, uid int64, sns []string) error {
+	return dao.db.WithContext(ctx).Where("uid = ? AND sn IN ?", uid, sns).Delete(&CartItem{}).Error
+}
+
+func (dao *cartGORMDAO) FindByUid(ctx context.Context, uid int64) ([]CartItem, error) {
+	var res []CartItem
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).Order("ctime DESC, id DESC").Find(&res).Error
+	return res, err
+}
+
+// CartItem 购物车中的商品，每个用户的每个 SKU 只有一条
+type CartItem struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Uid      int64  `gorm:"not null;uniqueIndex:uniq_uid_sn;comment:用户ID"`
+	SN       string `gorm:"type:varchar(255);not null;uniqueIndex:uniq_uid_sn;comment:商品SKU序列号"`
+	Quantity int64  `gorm:"not null;comment:购买数量"`
+	Ctime    int64
+	Utime    int64
+}
@@ -0,0 +1,179 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/cart/internal/domain"
+	"github.com/ecodeclub/webook/internal/cart/internal/repository"
+	"github.com/ecodeclub/webook/internal/product"
+)
+
+var (
+	ErrInvalidQuantity = errors.New("商品数量非法")
+	ErrCartFull        = errors.New("购物车已满")
+	ErrNotAvailable    = errors.New("商品已下架或者不在销售时间内")
+	ErrItemNotFound    = repository.ErrItemNotFound
+)
+
+// maxItems 购物车中最多的商品种类
+const maxItems = 100
+
+//go:generate mockgen -source=./cart.go -destination=../../mocks/cart.mock.go -package=cartmocks -typed Service
+type Service interface {
+	// AddItem 商品已经在购物车中的时候累加数量
+	AddItem(ctx context.Context, uid int64, sn string, quantity int64) error
+	UpdateQuantity(ctx context.Context, uid int64, sn string, quantity int64) error
+	RemoveItems(ctx context.Context, uid int64, sns []string) error
+	// List 返回购物车中的所有商品，带有实时的价格和上架状态
+	List(ctx context.Context, uid int64) ([]domain.Item, error)
+	// FindItems 按照 sns 的顺序返回购物车中选中的商品，不包含商品信息
+	// 任何一个商品不在购物车中都会返回 ErrItemNotFound
+	FindItems(ctx context.Context, uid int64, sns []string) ([]domain.Item, error)
+}
+
+type service struct {
+	repo       repository.CartRepository
+	productSvc product.Service
+}
+
+func NewService(repo repository.CartRepository, productSvc product.Service) Service {
+	return &service{repo: repo, productSvc: productSvc}
+}
+
+func (s *service) AddItem(ctx context.Context, uid int64, sn string, quantity int64) error {
+	if quantity < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidQuantity, quantity)
+	}
+	items, err := s.repo.FindItems(ctx, uid)
+	if err != nil {
+		return err
+	}
+	item, exists := slice.Find(items, func(src domain.Item) bool {
+		return src.SN == sn
+	})
+	if !exists && len(items) >= maxItems {
+		return fmt.Errorf("%w: uid %d", ErrCartFull, uid)
+	}
+	// 累加之后的数量也不能超过库存
+	err = s.checkProduct(ctx, sn, item.Quantity+quantity)
+	if err != nil {
+		return err
+	}
+	return s.repo.Add(ctx, uid, domain.Item{SN: sn, Quantity: quantity})
+}
+
+func (s *service) UpdateQuantity(ctx context.Context, uid int64, sn string, quantity int64) error {
+	if quantity < 1 {
+		return fmt.Errorf("%w: %d", ErrInvalidQuantity, quantity)
+	}
+	items, err := s.repo.FindItems(ctx, uid)
+	if err != nil {
+		return err
+	}
+	_, exists := slice.Find(items, func(src domain.Item) bool {
+		return src.SN == sn
+	})
+	if !exists {
+		return fmt.Errorf("%w: %s", ErrItemNotFound, sn)
+	}
+	err = s.checkProduct(ctx, sn, quantity)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateQuantity(ctx, uid, sn, quantity)
+}
+
+// checkProduct 商品可以购买并且库存足够
+// 这里只是预检，限购数量在创建订单预留库存的时候校验
+func (s *service) checkProduct(ctx context.Context, sn string, quantity int64) error {
+	p, err := s.productSvc.FindBySN(ctx, sn)
+	if err != nil {
+		return s.productErr(sn, err)
+	}
+	if quantity > p.SKU.Stock {
+		return fmt.Errorf("%w: %s 超出库存 %d", ErrInvalidQuantity, sn, quantity)
+	}
+	return nil
+}
+
+func (s *service) RemoveItems(ctx context.Context, uid int64, sns []string) error {
+	if len(sns) == 0 {
+		return nil
+	}
+	return s.repo.Delete(ctx, uid, sns)
+}
+
+func (s *service) List(ctx context.Context, uid int64) ([]domain.Item, error) {
+	items, err := s.repo.FindItems(ctx, uid)
+	if err != nil || len(items) == 0 {
+		return items, err
+	}
+	// 大多数情况下所有商品都可以购买，一次查询就够了
+	products, err := s.productSvc.FindBySNs(ctx, slice.Map(items, func(idx int, src domain.Item) string {
+		return src.SN
+	}))
+	if err == nil {
+		for i := range items {
+			items[i].Product, items[i].Available = products[i], true
+		}
+		return items, nil
+	}
+	// 有商品已经下架或者不在销售时间内，逐个查询找出来
+	for i := range items {
+		p, er := s.productSvc.FindBySN(ctx, items[i].SN)
+		switch {
+		case er == nil:
+			items[i].Product, items[i].Available = p, true
+		case errors.Is(s.productErr(items[i].SN, er), ErrNotAvailable):
+			items[i].Available = false
+		default:
+			return nil, er
+		}
+	}
+	return items, nil
+}
+
+func (s *service) FindItems(ctx context.Context, uid int64, sns []string) ([]domain.Item, error) {
+	items, err := s.repo.FindItems(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+	itemMap := make(map[string]domain.Item, len(items))
+	for _, item := range items {
+		itemMap[item.SN] = item
+	}
+	res := make([]domain.Item, 0, len(sns))
+	for _, sn := range sns {
+		item, ok := itemMap[sn]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrItemNotFound, sn)
+		}
+		res = append(res, item)
+	}
+	return res, nil
+}
+
+// productErr 商品不存在、下架或者不在销售时间内都认为不可购买
+func (s *service) productErr(sn string, err error) error {
+	if errors.Is(err, product.ErrNotFound) || errors.Is(err, product.ErrNotOnSale) {
+		return fmt.Errorf("%w: %s", ErrNotAvailable, sn)
+	}
+	return err
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/cart/internal/domain"
+	"github.com/ecodeclub/webook/internal/cart/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc service.Service
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) PrivateRoutes(server *gin.Engine) {
+	g := server.Group("/cart")
+	g.POST("/add", ginx.BS[ItemReq](h.Add))
+	g.POST("/update", ginx.BS[ItemReq](h.Update))
+	g.POST("/remove", ginx.BS[RemoveReq](h.Remove))
+	g.POST("/list", ginx.S(h.List))
+}
+
+// Add 加入购物车，已经在购物车中的商品累加数量
+func (h *Handler) Add(ctx *ginx.Context, req ItemReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.AddItem(ctx, sess.Claims().Uid, req.SN, req.Quantity)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+// Update 修改购物车中商品的数量
+func (h *Handler) Update(ctx *ginx.Context, req ItemReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.UpdateQuantity(ctx, sess.Claims().Uid, req.SN, req.Quantity)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *Handler) Remove(ctx *ginx.Context, req RemoveReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.RemoveItems(ctx, sess.Claims().Uid, req.SNs)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *Handler) List(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	items, err := h.svc.List(ctx, sess.Claims().Uid)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	now := time.Now()
+	return ginx.Result{
+		Data: Cart{
+			Items: slice.Map(items, func(idx int, src domain.Item) Item {
+				return newItem(src, now)
+			}),
+		},
+	}, nil
+}
+
+func (h *Handler) errorResult(err error) ginx.Result {
+	switch {
+	case errors.Is(err, service.ErrInvalidQuantity):
+		return invalidQuantityResult
+	case errors.Is(err, service.ErrCartFull):
+		return cartFullResult
+	case errors.Is(err, service.ErrNotAvailable):
+		return notAvailableResult
+	case errors.Is(err, service.ErrItemNotFound):
+		return itemNotFoundResult
+	default:
+		return systemErrorResult
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"time"
+
+	"github.com/ecodeclub/webook/internal/cart/internal/domain"
+)
+
+type ItemReq struct {
+	SN       string `json:"sn"`
+	Quantity int64  `json:"quantity"`
+}
+
+type RemoveReq struct {
+	SNs []string `json:"sns"`
+}
+
+type Item struct {
+	SN       string `json:"sn"`
+	Quantity int64  `json:"quantity"`
+	// Available 为 false 的时候商品已经下架或者不在销售时间内，下面的商品信息都为空
+	Available     bool   `json:"available"`
+	SPUSN         string `json:"spuSN,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Desc          string `json:"desc,omitempty"`
+	OriginalPrice int64  `json:"originalPrice,omitempty"`
+	RealPrice     int64  `json:"realPrice,omitempty"`
+	Stock         int64  `json:"stock,omitempty"`
+	Utime         int64  `json:"utime"`
+}
+
+type Cart struct {
+	Items []Item `json:"items"`
+}
+
+func newItem(item domain.Item, now time.Time) Item {
+	res := Item{
+		SN:        item.SN,
+		Quantity:  item.Quantity,
+		Available: item.Available,
+		Utime:     item.Utime,
+	}
+	if item.Available {
+		res.SPUSN = item.Product.SPU.SN
+		res.Name = item.Product.SKU.Name
+		res.Desc = item.Product.SKU.Desc
+		res.OriginalPrice = item.Product.SKU.Price
+		res.RealPrice = item.Product.SKU.RealPrice(now.UnixMilli())
+		res.Stock = item.Product.SKU.Stock
+	}
+	return res
+}
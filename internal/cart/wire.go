@@ -0,0 +1,66 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package cart
+
+import (
+	"sync"
+
+	"github.com/ecodeclub/webook/internal/cart/internal/domain"
+	"github.com/ecodeclub/webook/internal/cart/internal/repository"
+	"github.com/ecodeclub/webook/internal/cart/internal/repository/cache"
+	"github.com/ecodeclub/webook/internal/cart/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/cart/internal/service"
+	"github.com/ecodeclub/webook/internal/cart/internal/web"
+	"github.com/ecodeclub/webook/internal/product"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitHandler(db *egorm.Component, cmd redis.Cmdable, productSvc product.Service) *Handler {
+	wire.Build(InitService, web.NewHandler)
+	return new(Handler)
+}
+
+func InitService(db *egorm.Component, cmd redis.Cmdable, productSvc product.Service) Service {
+	wire.Build(
+		initCartDAO,
+		cache.NewCartRedisCache,
+		repository.NewCachedCartRepository,
+		service.NewService,
+	)
+	return nil
+}
+
+var (
+	daoOnce = sync.Once{}
+	d       dao.CartDAO
+)
+
+func initCartDAO(db *egorm.Component) dao.CartDAO {
+	daoOnce.Do(func() {
+		_ = dao.InitTables(db)
+		d = dao.NewCartGORMDAO(db)
+	})
+	return d
+}
+
+type Handler = web.Handler
+type Service = service.Service
+type Item = domain.Item
+
+var ErrItemNotFound = service.ErrItemNotFound
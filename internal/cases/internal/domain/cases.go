@@ -21,6 +21,21 @@ type Case struct {
 	// 引导点
 	Guidance string
 
+	Status uint8
+	// 审核人
+	Reviewer int64
+	// 最近一次的审核意见
+	ReviewComment string
+
 	Ctime time.Time
 	Utime time.Time
 }
+
+const (
+	CaseStatusDraft         = iota + 1 // 草稿
+	CaseStatusPendingReview            // 待审核
+	CaseStatusApproved                 // 审核通过
+	CaseStatusRejected                 // 审核拒绝
+	CaseStatusPublished                // 已发布
+	CaseStatusUnpublished              // 已下架
+)
@@ -1,7 +1,11 @@
 package errs
 
 var (
-	SystemError = ErrorCode{Code: 505001, Msg: "系统错误"}
+	SystemError     = ErrorCode{Code: 505001, Msg: "系统错误"}
+	InvalidStatus   = ErrorCode{Code: 505002, Msg: "案例当前状态不允许该操作"}
+	CaseNotFound    = ErrorCode{Code: 505003, Msg: "案例不存在或者未发布"}
+	CaseInSkill     = ErrorCode{Code: 505004, Msg: "案例还被技能引用，请先解除引用"}
+	InvalidReviewer = ErrorCode{Code: 505005, Msg: "审核人非法，不能指定自己审核"}
 )
 
 type ErrorCode struct {
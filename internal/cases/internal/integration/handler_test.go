@@ -28,9 +28,17 @@ import (
 	"github.com/ecodeclub/ekit/iox"
 	"github.com/ecodeclub/ekit/sqlx"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/cases/internal/event"
+	evtmocks "github.com/ecodeclub/webook/internal/cases/internal/event/mocks"
 	"github.com/ecodeclub/webook/internal/cases/internal/integration/startup"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/cases/internal/web"
+	casemocks "github.com/ecodeclub/webook/internal/cases/mocks"
+	"github.com/ecodeclub/webook/internal/label"
+	labelmocks "github.com/ecodeclub/webook/internal/label/mocks"
+	"github.com/ecodeclub/webook/internal/note"
+	notemocks "github.com/ecodeclub/webook/internal/note/mocks"
 	"github.com/ecodeclub/webook/internal/pkg/middleware"
 	"github.com/ecodeclub/webook/internal/test"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
@@ -38,22 +46,32 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gotomicro/ego/core/econf"
 	"github.com/gotomicro/ego/server/egin"
+	"github.com/gotomicro/ego/task/ejob"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 const uid = 2051
 
 type HandlerTestSuite struct {
 	suite.Suite
-	server *egin.Component
-	db     *egorm.Component
-	rdb    ecache.Cache
-	dao    dao.CaseDAO
+	server     *egin.Component
+	db         *egorm.Component
+	rdb        ecache.Cache
+	dao        dao.CaseDAO
+	ctrl       *gomock.Controller
+	producer   *evtmocks.MockSyncEventProducer
+	labelSvc   *labelmocks.MockService
+	noteSvc    *notemocks.MockService
+	purgeJob   *cases.PurgeJob
+	reindexJob *cases.ReindexJob
 }
 
 func (s *HandlerTestSuite) TearDownSuite() {
+	s.ctrl.Finish()
 	err := s.db.Exec("DROP TABLE `cases`").Error
 	require.NoError(s.T(), err)
 	err = s.db.Exec("DROP TABLE `publish_cases`").Error
@@ -65,10 +83,47 @@ func (s *HandlerTestSuite) TearDownTest() {
 	require.NoError(s.T(), err)
 	err = s.db.Exec("TRUNCATE TABLE `publish_cases`").Error
 	require.NoError(s.T(), err)
+
+	// 线上库的缓存
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rdb := testioc.InitRedis()
+	keys, err := rdb.Keys(ctx, "webook:cases:*").Result()
+	require.NoError(s.T(), err)
+	if len(keys) > 0 {
+		err = rdb.Del(ctx, keys...).Err()
+		require.NoError(s.T(), err)
+	}
 }
 
 func (s *HandlerTestSuite) SetupSuite() {
-	handler, err := startup.InitHandler()
+	s.ctrl = gomock.NewController(s.T())
+	s.producer = evtmocks.NewMockSyncEventProducer(s.ctrl)
+	s.labelSvc = labelmocks.NewMockService(s.ctrl)
+	s.labelSvc.EXPECT().SaveBizLabels(gomock.Any(), "case", gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
+	s.labelSvc.EXPECT().DeleteBizLabels(gomock.Any(), "case", gomock.Any()).
+		Return(nil).AnyTimes()
+	s.noteSvc = notemocks.NewMockService(s.ctrl)
+	s.noteSvc.EXPECT().FindByBiz(gomock.Any(), int64(uid), note.BizCase, gomock.Any()).
+		Return([]note.Note{
+			{Id: 1, Uid: uid, Biz: note.BizCase, BizId: 3, Content: "我的笔记", Utime: time.UnixMilli(123)},
+		}, nil).AnyTimes()
+	// 只有 10 号案例被技能引用了
+	refSvc := casemocks.NewMockReferenceService(s.ctrl)
+	refSvc.EXPECT().Referenced(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64) (bool, error) {
+			return id == 10, nil
+		}).AnyTimes()
+	relatedSvc := casemocks.NewMockRelatedService(s.ctrl)
+	relatedSvc.EXPECT().RemoveCase(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	labelModule := &label.Module{Svc: s.labelSvc}
+	noteModule := &note.Module{Svc: s.noteSvc}
+	handler, err := startup.InitHandler(s.producer, labelModule, noteModule, refSvc, relatedSvc)
+	require.NoError(s.T(), err)
+	s.purgeJob, err = startup.InitPurgeJob(s.producer, labelModule, noteModule, refSvc, relatedSvc)
+	require.NoError(s.T(), err)
+	s.reindexJob, err = startup.InitReindexJob(s.producer, labelModule, noteModule, refSvc, relatedSvc)
 	require.NoError(s.T(), err)
 	econf.Set("server", map[string]any{"contextTimeout": "1s"})
 	server := egin.Load("server").Build()
@@ -127,6 +182,7 @@ func (s *HandlerTestSuite) TestSave() {
 					Shorthand: "mysql_shorthand",
 					Highlight: "mysql_highlight",
 					Guidance:  "mysql_guidance",
+					Status:    dao.CaseStatusDraft,
 				}, ca)
 			},
 			req: web.SaveReq{
@@ -188,6 +244,7 @@ func (s *HandlerTestSuite) TestSave() {
 					Shorthand: "mysql_shorthand",
 					Highlight: "mysql_highlight",
 					Guidance:  "mysql_guidance",
+					Status:    dao.CaseStatusDraft,
 				}, ca)
 			},
 			req: web.SaveReq{
@@ -384,63 +441,47 @@ func (s *HandlerTestSuite) TestPublish() {
 		name     string
 		before   func(t *testing.T)
 		after    func(t *testing.T)
-		req      web.SaveReq
+		req      web.CaseId
 		wantCode int
 		wantResp test.Result[int64]
 	}{
 		{
-			name: "新建并发布",
+			name: "首次发布",
 			before: func(t *testing.T) {
-
+				s.createCase(t, 1, dao.CaseStatusApproved)
+				s.producer.EXPECT().Produce(gomock.Any(), event.SyncSearchEvent{
+					Biz:      "case",
+					BizId:    1,
+					Title:    "案例1",
+					Content:  "案例1内容",
+					Keywords: "mysql_keywords",
+					Labels:   []string{"MySQL"},
+				}).Return(nil)
 			},
 			after: func(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 				defer cancel()
+				wantCase := s.buildCase(dao.CaseStatusPublished)
 				ca, err := s.dao.GetCaseByID(ctx, 1)
 				require.NoError(t, err)
-				wantCase := dao.Case{
-					Uid:     uid,
-					Title:   "案例1",
-					Content: "案例1内容",
-					Labels: sqlx.JsonColumn[[]string]{
-						Valid: true,
-						Val:   []string{"MySQL"},
-					},
-					CodeRepo:  "www.github.com",
-					Keywords:  "mysql_keywords",
-					Shorthand: "mysql_shorthand",
-					Highlight: "mysql_highlight",
-					Guidance:  "mysql_guidance",
-				}
 				s.assertCase(t, wantCase, ca)
 				publishCase, err := s.dao.GetPublishCase(ctx, 1)
 				require.NoError(t, err)
 				s.assertCase(t, wantCase, dao.Case(publishCase))
 			},
-			req: web.SaveReq{
-				Case: web.Case{
-					Title:     "案例1",
-					Content:   "案例1内容",
-					Labels:    []string{"MySQL"},
-					CodeRepo:  "www.github.com",
-					Keywords:  "mysql_keywords",
-					Shorthand: "mysql_shorthand",
-					Highlight: "mysql_highlight",
-					Guidance:  "mysql_guidance",
-				},
-			},
+			req:      web.CaseId{Cid: 1},
 			wantCode: 200,
 			wantResp: test.Result[int64]{
 				Data: 1,
 			},
 		},
 		{
-			name: "更新并发布",
-			// publish_case表的ctime不更新
+			name: "更新线上库",
 			before: func(t *testing.T) {
+				s.createCase(t, 2, dao.CaseStatusApproved)
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 				defer cancel()
-				err := s.db.WithContext(ctx).Create(&dao.Case{
+				err := s.db.WithContext(ctx).Create(&dao.PublishCase{
 					Id:      2,
 					Uid:     uid,
 					Title:   "老的案例标题",
@@ -454,138 +495,42 @@ func (s *HandlerTestSuite) TestPublish() {
 					Shorthand: "old_mysql_shorthand",
 					Highlight: "old_mysql_highlight",
 					Guidance:  "old_mysql_guidance",
+					Status:    dao.CaseStatusPublished,
 					Ctime:     123,
 					Utime:     234,
 				}).Error
 				require.NoError(t, err)
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			after: func(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 				defer cancel()
-				ca, err := s.dao.GetCaseByID(ctx, 2)
-				require.NoError(t, err)
-				wantCase := dao.Case{
-					Uid:     uid,
-					Title:   "案例2",
-					Content: "案例2内容",
-					Labels: sqlx.JsonColumn[[]string]{
-						Valid: true,
-						Val:   []string{"MySQL"},
-					},
-					CodeRepo:  "www.github.com",
-					Keywords:  "mysql_keywords",
-					Shorthand: "mysql_shorthand",
-					Highlight: "mysql_highlight",
-					Guidance:  "mysql_guidance",
-				}
-				s.assertCase(t, wantCase, ca)
 				publishCase, err := s.dao.GetPublishCase(ctx, 2)
 				require.NoError(t, err)
-				publishCase.Ctime = 123
-				s.assertCase(t, wantCase, dao.Case(publishCase))
-			},
-			req: web.SaveReq{
-				Case: web.Case{
-					Id:        2,
-					Title:     "案例2",
-					Content:   "案例2内容",
-					Labels:    []string{"MySQL"},
-					CodeRepo:  "www.github.com",
-					Keywords:  "mysql_keywords",
-					Shorthand: "mysql_shorthand",
-					Highlight: "mysql_highlight",
-					Guidance:  "mysql_guidance",
-				},
+				s.assertCase(t, s.buildCase(dao.CaseStatusPublished), dao.Case(publishCase))
 			},
+			req:      web.CaseId{Cid: 2},
 			wantCode: 200,
 			wantResp: test.Result[int64]{
 				Data: 2,
 			},
 		},
 		{
-			name: "publish表有值发布",
-			// publish_case表的ctime不更新
+			name: "没有审核通过",
 			before: func(t *testing.T) {
-				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-				defer cancel()
-				err := s.db.WithContext(ctx).Create(&dao.Case{
-					Id:      3,
-					Uid:     uid,
-					Title:   "老的案例标题",
-					Content: "老的案例内容",
-					Labels: sqlx.JsonColumn[[]string]{
-						Valid: true,
-						Val:   []string{"old-MySQL"},
-					},
-					CodeRepo:  "old-github.com",
-					Keywords:  "old_mysql_keywords",
-					Shorthand: "old_mysql_shorthand",
-					Highlight: "old_mysql_highlight",
-					Guidance:  "old_mysql_guidance",
-					Ctime:     123,
-					Utime:     234,
-				}).Error
-				require.NoError(t, err)
-				err = s.db.WithContext(ctx).Create(&dao.PublishCase{
-					Id:      3,
-					Uid:     uid,
-					Title:   "老的案例标题",
-					Content: "老的案例内容",
-					Labels: sqlx.JsonColumn[[]string]{
-						Valid: true,
-						Val:   []string{"old-MySQL"},
-					},
-					CodeRepo:  "old-github.com",
-					Keywords:  "old_mysql_keywords",
-					Shorthand: "old_mysql_shorthand",
-					Highlight: "old_mysql_highlight",
-					Guidance:  "old_mysql_guidance",
-					Ctime:     123,
-					Utime:     234,
-				}).Error
-				require.NoError(t, err)
+				s.createCase(t, 3, dao.CaseStatusDraft)
 			},
 			after: func(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 				defer cancel()
-				ca, err := s.dao.GetCaseByID(ctx, 3)
-				require.NoError(t, err)
-				wantCase := dao.Case{
-					Uid:     uid,
-					Title:   "案例2",
-					Content: "案例2内容",
-					Labels: sqlx.JsonColumn[[]string]{
-						Valid: true,
-						Val:   []string{"MySQL"},
-					},
-					CodeRepo:  "www.github.com",
-					Keywords:  "mysql_keywords",
-					Shorthand: "mysql_shorthand",
-					Highlight: "mysql_highlight",
-					Guidance:  "mysql_guidance",
-				}
-				s.assertCase(t, wantCase, ca)
-				publishCase, err := s.dao.GetPublishCase(ctx, 3)
-				require.NoError(t, err)
-				publishCase.Ctime = 123
-				s.assertCase(t, wantCase, dao.Case(publishCase))
+				_, err := s.dao.GetPublishCase(ctx, 3)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
 			},
-			req: web.SaveReq{
-				Case: web.Case{
-					Id:        3,
-					Title:     "案例2",
-					Content:   "案例2内容",
-					Labels:    []string{"MySQL"},
-					CodeRepo:  "www.github.com",
-					Keywords:  "mysql_keywords",
-					Shorthand: "mysql_shorthand",
-					Highlight: "mysql_highlight",
-					Guidance:  "mysql_guidance",
-				},
-			},
-			wantCode: 200,
+			req:      web.CaseId{Cid: 3},
+			wantCode: 500,
 			wantResp: test.Result[int64]{
-				Data: 3,
+				Code: 505002,
+				Msg:  "案例当前状态不允许该操作",
 			},
 		},
 	}
@@ -609,6 +554,368 @@ func (s *HandlerTestSuite) TestPublish() {
 	}
 }
 
+func (s *HandlerTestSuite) TestReviewWorkflow() {
+	// 审核人不能是提交人自己
+	const reviewer = uid + 1
+	s.createCase(s.T(), 1, dao.CaseStatusDraft)
+
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		path   string
+		req    any
+		// 0 表示成功
+		wantErrCode int
+		// 制作库的状态
+		wantStatus uint8
+	}{
+		{
+			name:        "草稿不能发布",
+			path:        "/case/publish",
+			req:         web.CaseId{Cid: 1},
+			wantErrCode: 505002,
+			wantStatus:  dao.CaseStatusDraft,
+		},
+		{
+			name:        "审核人不能是自己",
+			path:        "/case/submit",
+			req:         web.SubmitReq{Cid: 1, Reviewer: uid},
+			wantErrCode: 505005,
+			wantStatus:  dao.CaseStatusDraft,
+		},
+		{
+			name:        "没有指定审核人",
+			path:        "/case/submit",
+			req:         web.SubmitReq{Cid: 1, Reviewer: 0},
+			wantErrCode: 505005,
+			wantStatus:  dao.CaseStatusDraft,
+		},
+		{
+			name:       "提交审核",
+			path:       "/case/submit",
+			req:        web.SubmitReq{Cid: 1, Reviewer: reviewer},
+			wantStatus: dao.CaseStatusPendingReview,
+		},
+		{
+			name:        "重复提交审核",
+			path:        "/case/submit",
+			req:         web.SubmitReq{Cid: 1, Reviewer: reviewer},
+			wantErrCode: 505002,
+			wantStatus:  dao.CaseStatusPendingReview,
+		},
+		{
+			name: "审核拒绝",
+			before: func(t *testing.T) {
+				s.assignReviewer(t, 1, uid)
+			},
+			path:       "/case/review",
+			req:        web.ReviewReq{Cid: 1, Comment: "缺少代码仓库"},
+			wantStatus: dao.CaseStatusRejected,
+		},
+		{
+			name:       "再次提交审核，但是指定了别的审核人",
+			path:       "/case/submit",
+			req:        web.SubmitReq{Cid: 1, Reviewer: reviewer + 1},
+			wantStatus: dao.CaseStatusPendingReview,
+		},
+		{
+			name:        "不是指定的审核人",
+			path:        "/case/review",
+			req:         web.ReviewReq{Cid: 1, Approved: true},
+			wantErrCode: 505002,
+			wantStatus:  dao.CaseStatusPendingReview,
+		},
+		{
+			name: "审核通过",
+			before: func(t *testing.T) {
+				s.assignReviewer(t, 1, uid)
+			},
+			path:       "/case/review",
+			req:        web.ReviewReq{Cid: 1, Approved: true, Comment: "可以了"},
+			wantStatus: dao.CaseStatusApproved,
+		},
+		{
+			name: "发布",
+			before: func(t *testing.T) {
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			path:       "/case/publish",
+			req:        web.CaseId{Cid: 1},
+			wantStatus: dao.CaseStatusPublished,
+		},
+		{
+			name: "下架",
+			before: func(t *testing.T) {
+				s.producer.EXPECT().Produce(gomock.Any(), event.SyncSearchEvent{
+					Biz:     "case",
+					BizId:   1,
+					Deleted: true,
+				}).Return(nil)
+			},
+			path:       "/case/unpublish",
+			req:        web.CaseId{Cid: 1},
+			wantStatus: dao.CaseStatusUnpublished,
+		},
+		{
+			name:        "重复下架",
+			path:        "/case/unpublish",
+			req:         web.CaseId{Cid: 1},
+			wantErrCode: 505002,
+			wantStatus:  dao.CaseStatusUnpublished,
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			if tc.before != nil {
+				tc.before(t)
+			}
+			req, err := http.NewRequest(http.MethodPost, tc.path, iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[any]()
+			s.server.ServeHTTP(recorder, req)
+			if tc.wantErrCode != 0 {
+				require.Equal(t, 500, recorder.Code)
+				assert.Equal(t, tc.wantErrCode, recorder.MustScan().Code)
+			} else {
+				require.Equal(t, 200, recorder.Code)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			ca, err := s.dao.GetCaseByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, ca.Status)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ca, err := s.dao.GetCaseByID(ctx, 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "可以了", ca.ReviewComment)
+
+	// 下架之后线上库的数据还在，但是查询不到了
+	var pub dao.PublishCase
+	err = s.db.WithContext(ctx).Where("id = ?", 1).First(&pub).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint8(dao.CaseStatusUnpublished), pub.Status)
+	_, err = s.dao.GetPublishCase(ctx, 1)
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	cnt, err := s.dao.PublishCaseCount(ctx)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+}
+
+// assignReviewer 模拟把审核任务重新分配给当前登录的用户
+func (s *HandlerTestSuite) assignReviewer(t *testing.T, id int64, reviewer int64) {
+	err := s.db.Model(&dao.Case{}).Where("id = ?", id).
+		Update("reviewer", reviewer).Error
+	require.NoError(t, err)
+}
+
+func (s *HandlerTestSuite) TestListReview() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	data := []dao.Case{
+		{Id: 1, Uid: uid, Title: "分配给我的", Status: dao.CaseStatusPendingReview, Reviewer: uid, Utime: 123},
+		{Id: 2, Uid: uid, Title: "分配给别人的", Status: dao.CaseStatusPendingReview, Reviewer: uid + 1, Utime: 123},
+		{Id: 3, Uid: uid, Title: "已经审核过的", Status: dao.CaseStatusApproved, Reviewer: uid, Utime: 123},
+	}
+	err := s.db.WithContext(ctx).Create(&data).Error
+	require.NoError(s.T(), err)
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/case/review/list", iox.NewJSONReader(web.Page{Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[web.CasesList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	assert.Equal(s.T(), web.CasesList{
+		Total: 1,
+		Cases: []web.Case{
+			{
+				Id:     1,
+				Title:  "分配给我的",
+				Status: dao.CaseStatusPendingReview,
+				Utime:  time.UnixMilli(123).Format(time.DateTime),
+			},
+		},
+	}, recorder.MustScan().Data)
+}
+
+func (s *HandlerTestSuite) TestDelete() {
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		after  func(t *testing.T)
+		id     int64
+
+		wantCode int
+		wantResp test.Result[any]
+	}{
+		{
+			name: "删除已发布的案例",
+			before: func(t *testing.T) {
+				s.createCase(t, 1, dao.CaseStatusPublished)
+				pub := dao.PublishCase(s.buildCase(dao.CaseStatusPublished))
+				pub.Id = 1
+				err := s.db.Create(&pub).Error
+				require.NoError(t, err)
+				s.producer.EXPECT().Produce(gomock.Any(), event.SyncSearchEvent{
+					Biz:     "case",
+					BizId:   1,
+					Deleted: true,
+				}).Return(nil)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, err := s.dao.GetCaseByID(ctx, 1)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+				var ca dao.Case
+				err = s.db.WithContext(ctx).Where("id = ?", 1).First(&ca).Error
+				require.NoError(t, err)
+				assert.True(t, ca.Dtime > 0)
+				// 线上库直接删掉
+				_, err = s.dao.GetPublishCase(ctx, 1)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+			},
+			id:       1,
+			wantCode: 200,
+		},
+		{
+			name: "还被技能引用",
+			before: func(t *testing.T) {
+				s.createCase(t, 10, dao.CaseStatusDraft)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, err := s.dao.GetCaseByID(ctx, 10)
+				require.NoError(t, err)
+			},
+			id:       10,
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 505004, Msg: "案例还被技能引用，请先解除引用"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/case/delete", iox.NewJSONReader(web.CaseId{Cid: tc.id}))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[any]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+			tc.after(t)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestRecycle() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.createCase(s.T(), 1, dao.CaseStatusPublished)
+	s.createCase(s.T(), 2, dao.CaseStatusDraft)
+	s.createCase(s.T(), 3, dao.CaseStatusDraft)
+	err := s.db.WithContext(ctx).Model(&dao.Case{}).Where("id = ?", 1).
+		Update("dtime", time.Now().UnixMilli()).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Model(&dao.Case{}).Where("id = ?", 2).
+		Update("dtime", time.Now().AddDate(0, 0, -31).UnixMilli()).Error
+	require.NoError(s.T(), err)
+
+	// 回收站按照删除时间倒序
+	req, err := http.NewRequest(http.MethodPost,
+		"/case/recycle/list", iox.NewJSONReader(web.Page{Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[web.CasesList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	list := recorder.MustScan().Data
+	assert.Equal(s.T(), int64(2), list.Total)
+	assert.Equal(s.T(), []int64{1, 2}, []int64{list.Cases[0].Id, list.Cases[1].Id})
+
+	// 恢复之后回到草稿状态，需要重新发布
+	s.doPost(s.T(), "/case/recycle/restore", web.CaseId{Cid: 1}, 200)
+	ca, err := s.dao.GetCaseByID(ctx, 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint8(dao.CaseStatusDraft), ca.Status)
+	assert.Equal(s.T(), int64(0), ca.Dtime)
+	// 不在回收站里面的不能恢复
+	s.doPost(s.T(), "/case/recycle/restore", web.CaseId{Cid: 3}, 500)
+
+	// 超过三十天的彻底删除
+	err = s.purgeJob.Run()
+	require.NoError(s.T(), err)
+	var ids []int64
+	err = s.db.WithContext(ctx).Model(&dao.Case{}).Order("id ASC").Pluck("id", &ids).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []int64{1, 3}, ids)
+}
+
+// createCase 在制作库中创建一个指定状态的案例，内容和 buildCase 一致
+func (s *HandlerTestSuite) TestReindex() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := s.db.WithContext(ctx).Create(&[]dao.PublishCase{
+		{Id: 1, Uid: uid, Title: "已发布1", Content: "内容1", Keywords: "关键字1", Status: dao.CaseStatusPublished,
+			Labels: sqlx.JsonColumn[[]string]{Valid: true, Val: []string{"MySQL"}}},
+		{Id: 2, Uid: uid, Title: "已发布2", Content: "内容2", Keywords: "关键字2", Status: dao.CaseStatusPublished,
+			Labels: sqlx.JsonColumn[[]string]{Valid: true, Val: []string{"MySQL"}}},
+		{Id: 3, Uid: uid, Title: "已下架", Content: "内容3", Keywords: "关键字3", Status: dao.CaseStatusUnpublished},
+	}).Error
+	require.NoError(s.T(), err)
+
+	var evts []event.SyncSearchEvent
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, evt event.SyncSearchEvent) error {
+			evts = append(evts, evt)
+			return nil
+		}).Times(2)
+	err = s.reindexJob.Run(ejob.Context{Ctx: ctx})
+	require.NoError(s.T(), err)
+	// 下架的案例不会同步到搜索
+	assert.Equal(s.T(), []event.SyncSearchEvent{
+		{Biz: "case", BizId: 1, Title: "已发布1", Content: "内容1", Keywords: "关键字1", Labels: []string{"MySQL"}},
+		{Biz: "case", BizId: 2, Title: "已发布2", Content: "内容2", Keywords: "关键字2", Labels: []string{"MySQL"}},
+	}, evts)
+}
+
+func (s *HandlerTestSuite) createCase(t *testing.T, id int64, status uint8) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	ca := s.buildCase(status)
+	ca.Id = id
+	ca.Ctime = 123
+	ca.Utime = 234
+	err := s.db.WithContext(ctx).Create(&ca).Error
+	require.NoError(t, err)
+}
+
+func (s *HandlerTestSuite) buildCase(status uint8) dao.Case {
+	return dao.Case{
+		Uid:     uid,
+		Title:   "案例1",
+		Content: "案例1内容",
+		Labels: sqlx.JsonColumn[[]string]{
+			Valid: true,
+			Val:   []string{"MySQL"},
+		},
+		CodeRepo:  "www.github.com",
+		Keywords:  "mysql_keywords",
+		Shorthand: "mysql_shorthand",
+		Highlight: "mysql_highlight",
+		Guidance:  "mysql_guidance",
+		Status:    status,
+	}
+}
+
 func (s *HandlerTestSuite) TestPublist() {
 	data := make([]dao.PublishCase, 0, 100)
 	for idx := 0; idx < 100; idx++ {
@@ -692,6 +999,138 @@ func (s *HandlerTestSuite) TestPublist() {
 	require.NoError(s.T(), err)
 }
 
+func (s *HandlerTestSuite) TestPubListByLabels() {
+	data := make([]dao.PublishCase, 0, 4)
+	for idx := 0; idx < 4; idx++ {
+		data = append(data, dao.PublishCase{
+			Uid:     uid,
+			Title:   fmt.Sprintf("这是发布的案例标题 %d", idx),
+			Content: fmt.Sprintf("这是发布的案例内容 %d", idx),
+			Status:  dao.CaseStatusPublished,
+		})
+	}
+	// 已经下架的不会返回
+	data[1].Status = dao.CaseStatusUnpublished
+	err := s.db.Create(&data).Error
+	require.NoError(s.T(), err)
+
+	testCases := []struct {
+		name     string
+		before   func(t *testing.T)
+		req      web.PubListReq
+		wantCode int
+		wantResp test.Result[web.CasesList]
+	}{
+		{
+			name: "任意匹配",
+			before: func(t *testing.T) {
+				s.labelSvc.EXPECT().BizIDs(gomock.Any(), "case", []int64{1, 2}, false, 0, 10).
+					Return([]int64{4, 2, 1}, int64(3), nil)
+			},
+			req: web.PubListReq{
+				Limit:    10,
+				LabelIds: []int64{1, 2},
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.CasesList]{
+				Data: web.CasesList{
+					Total: 3,
+					Cases: []web.Case{
+						{
+							Id:    4,
+							Title: "这是发布的案例标题 3",
+							Utime: time.UnixMilli(0).Format(time.DateTime),
+						},
+						{
+							Id:    1,
+							Title: "这是发布的案例标题 0",
+							Utime: time.UnixMilli(0).Format(time.DateTime),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "全部匹配",
+			before: func(t *testing.T) {
+				s.labelSvc.EXPECT().BizIDs(gomock.Any(), "case", []int64{1, 2}, true, 0, 10).
+					Return([]int64{3}, int64(1), nil)
+			},
+			req: web.PubListReq{
+				Limit:    10,
+				LabelIds: []int64{1, 2},
+				MatchAll: true,
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.CasesList]{
+				Data: web.CasesList{
+					Total: 1,
+					Cases: []web.Case{
+						{
+							Id:    3,
+							Title: "这是发布的案例标题 2",
+							Utime: time.UnixMilli(0).Format(time.DateTime),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/case/pub/list", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[web.CasesList]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestPubCache() {
+	t := s.T()
+	s.createCase(t, 1, dao.CaseStatusApproved)
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	// 发布的时候预热
+	s.doPost(t, "/case/publish", web.CaseId{Cid: 1}, 200)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := s.rdb.Get(ctx, "cases:pub:1").String()
+	require.NoError(t, err)
+
+	// 绕过缓存直接修改线上库，读到的依旧是缓存中的数据
+	err = s.db.Model(&dao.PublishCase{}).Where("id = ?", 1).
+		Update("title", "修改后的标题").Error
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost,
+		"/case/pub/detail", iox.NewJSONReader(web.CaseId{Cid: 1}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[web.Case]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	assert.Equal(t, "案例1", recorder.MustScan().Data.Title)
+
+	// 下架之后缓存失效
+	s.doPost(t, "/case/unpublish", web.CaseId{Cid: 1}, 200)
+	assert.True(t, s.rdb.Get(ctx, "cases:pub:1").KeyNotFound())
+}
+
+func (s *HandlerTestSuite) doPost(t *testing.T, path string, body any, wantCode int) {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, wantCode, recorder.Code)
+}
+
 func (s *HandlerTestSuite) TestPubDetail() {
 	err := s.db.Create(&dao.PublishCase{
 		Id:  3,
@@ -735,6 +1174,9 @@ func (s *HandlerTestSuite) TestPubDetail() {
 					Highlight: "redis_highlight",
 					Guidance:  "redis_guidance",
 					Utime:     time.UnixMilli(13).Format(time.DateTime),
+					Notes: []note.NoteVO{
+						{Id: 1, Biz: note.BizCase, BizId: 3, Content: "我的笔记", Utime: time.UnixMilli(123).Format(time.DateTime)},
+					},
 				},
 			},
 		},
@@ -753,6 +1195,59 @@ func (s *HandlerTestSuite) TestPubDetail() {
 	}
 }
 
+func (s *HandlerTestSuite) TestSaveNote() {
+	err := s.db.Create(&dao.PublishCase{Id: 1, Uid: uid, Title: "已发布"}).Error
+	require.NoError(s.T(), err)
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		req    web.NoteReq
+
+		wantCode int
+		wantResp test.Result[any]
+	}{
+		{
+			name: "保存成功",
+			before: func(t *testing.T) {
+				s.noteSvc.EXPECT().Save(gomock.Any(), note.Note{
+					Uid: uid, Biz: note.BizCase, BizId: 1, Content: "亮点",
+				}).Return(nil)
+			},
+			req:      web.NoteReq{Cid: 1, Content: "亮点"},
+			wantCode: 200,
+		},
+		{
+			name:     "案例没有发布",
+			before:   func(t *testing.T) {},
+			req:      web.NoteReq{Cid: 2, Content: "亮点"},
+			wantCode: 200,
+			wantResp: test.Result[any]{Code: 505003, Msg: "案例不存在或者未发布"},
+		},
+		{
+			name: "笔记非法",
+			before: func(t *testing.T) {
+				s.noteSvc.EXPECT().Save(gomock.Any(), gomock.Any()).Return(note.ErrInvalidNote)
+			},
+			req:      web.NoteReq{Cid: 1},
+			wantCode: 200,
+			wantResp: test.Result[any]{Code: 517002, Msg: "笔记内容非法"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/case/note/save", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[any]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+		})
+	}
+}
+
 // assertCase 不比较 id
 func (s *HandlerTestSuite) assertCase(t *testing.T, expect dao.Case, ca dao.Case) {
 	assert.True(t, ca.Id > 0)
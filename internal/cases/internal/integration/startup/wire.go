@@ -18,14 +18,32 @@ package startup
 
 import (
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/cases/internal/event"
 	"github.com/ecodeclub/webook/internal/cases/internal/web"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
 
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 	"github.com/google/wire"
 )
 
-func InitHandler() (*web.Handler, error) {
-	wire.Build(testioc.BaseSet, cases.InitModule,
+func InitHandler(p event.SyncEventProducer, lm *label.Module, nm *note.Module,
+	rf cases.ReferenceService, rs cases.RelatedService) (*web.Handler, error) {
+	wire.Build(testioc.BaseSet, cases.InitModuleWithProducer,
 		wire.FieldsOf(new(*cases.Module), "Hdl"))
 	return new(web.Handler), nil
 }
+
+func InitPurgeJob(p event.SyncEventProducer, lm *label.Module, nm *note.Module,
+	rf cases.ReferenceService, rs cases.RelatedService) (*cases.PurgeJob, error) {
+	wire.Build(testioc.BaseSet, cases.InitModuleWithProducer,
+		wire.FieldsOf(new(*cases.Module), "PurgeJob"))
+	return new(cases.PurgeJob), nil
+}
+
+func InitReindexJob(p event.SyncEventProducer, lm *label.Module, nm *note.Module,
+	rf cases.ReferenceService, rs cases.RelatedService) (*cases.ReindexJob, error) {
+	wire.Build(testioc.BaseSet, cases.InitModuleWithProducer,
+		wire.FieldsOf(new(*cases.Module), "ReindexJob"))
+	return new(cases.ReindexJob), nil
+}
@@ -8,19 +8,46 @@ package startup
 
 import (
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/cases/internal/event"
+	"github.com/ecodeclub/webook/internal/cases/internal/job"
+	"github.com/ecodeclub/webook/internal/cases/internal/service"
 	"github.com/ecodeclub/webook/internal/cases/internal/web"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 )
 
 // Injectors from wire.go:
 
-func InitHandler() (*web.Handler, error) {
+func InitHandler(p event.SyncEventProducer, lm *label.Module, nm *note.Module, rf service.ReferenceService, rs service.RelatedService) (*web.Handler, error) {
 	db := testioc.InitDB()
 	cache := testioc.InitCache()
-	module, err := cases.InitModule(db, cache)
+	module, err := cases.InitModuleWithProducer(db, cache, p, lm, nm, rf, rs)
 	if err != nil {
 		return nil, err
 	}
 	handler := module.Hdl
 	return handler, nil
 }
+
+func InitPurgeJob(p event.SyncEventProducer, lm *label.Module, nm *note.Module, rf service.ReferenceService, rs service.RelatedService) (*job.PurgeJob, error) {
+	db := testioc.InitDB()
+	cache := testioc.InitCache()
+	module, err := cases.InitModuleWithProducer(db, cache, p, lm, nm, rf, rs)
+	if err != nil {
+		return nil, err
+	}
+	purgeJob := module.PurgeJob
+	return purgeJob, nil
+}
+
+func InitReindexJob(p event.SyncEventProducer, lm *label.Module, nm *note.Module, rf service.ReferenceService, rs service.RelatedService) (*job.ReindexJob, error) {
+	db := testioc.InitDB()
+	cache := testioc.InitCache()
+	module, err := cases.InitModuleWithProducer(db, cache, p, lm, nm, rf, rs)
+	if err != nil {
+		return nil, err
+	}
+	reindexJob := module.ReindexJob
+	return reindexJob, nil
+}
@@ -2,15 +2,26 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/webook/internal/cases/internal/domain"
 )
 
+const expiration = time.Minute * 30
+
 type CaseCache interface {
 	// 缓存总数
 	GetTotal(ctx context.Context) (int64, error)
 	SetTotal(ctx context.Context, total int64) error
+	DelTotal(ctx context.Context) error
+
+	// GetPubCase 线上库的案例
+	GetPubCase(ctx context.Context, id int64) (domain.Case, error)
+	SetPubCase(ctx context.Context, ca domain.Case) error
+	DelPubCase(ctx context.Context, id int64) error
 }
 
 type caseCache struct {
@@ -21,7 +32,7 @@ func NewCaseCache(ec ecache.Cache) CaseCache {
 	return &caseCache{
 		ec: &ecache.NamespaceCache{
 			C:         ec,
-			Namespace: "cases",
+			Namespace: "cases:",
 		},
 	}
 }
@@ -31,7 +42,39 @@ func (c *caseCache) GetTotal(ctx context.Context) (int64, error) {
 }
 
 func (c *caseCache) SetTotal(ctx context.Context, total int64) error {
-	return c.ec.Set(ctx, c.totalKey(), total, time.Minute*30)
+	return c.ec.Set(ctx, c.totalKey(), total, expiration)
+}
+
+func (c *caseCache) DelTotal(ctx context.Context) error {
+	_, err := c.ec.Delete(ctx, c.totalKey())
+	return err
+}
+
+func (c *caseCache) GetPubCase(ctx context.Context, id int64) (domain.Case, error) {
+	var res domain.Case
+	data, err := c.ec.Get(ctx, c.pubKey(id)).String()
+	if err != nil {
+		return res, err
+	}
+	err = json.Unmarshal([]byte(data), &res)
+	return res, err
+}
+
+func (c *caseCache) SetPubCase(ctx context.Context, ca domain.Case) error {
+	data, err := json.Marshal(ca)
+	if err != nil {
+		return err
+	}
+	return c.ec.Set(ctx, c.pubKey(ca.Id), string(data), expiration)
+}
+
+func (c *caseCache) DelPubCase(ctx context.Context, id int64) error {
+	_, err := c.ec.Delete(ctx, c.pubKey(id))
+	return err
+}
+
+func (c *caseCache) pubKey(id int64) string {
+	return fmt.Sprintf("pub:%d", id)
 }
 
 func (c *caseCache) totalKey() string {
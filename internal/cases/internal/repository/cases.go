@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ecodeclub/ekit/slice"
@@ -11,6 +13,8 @@ import (
 	"github.com/ecodeclub/webook/internal/cases/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository/dao"
 	"github.com/gotomicro/ego/core/elog"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
 )
 
 type CaseRepo interface {
@@ -19,20 +23,38 @@ type CaseRepo interface {
 	PubTotal(ctx context.Context) (int64, error)
 	GetPubByID(ctx context.Context, caseId int64) (domain.Case, error)
 	GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Case, error)
-	// Sync 保存到制作库，而后同步到线上库
-	Sync(ctx context.Context, ca *domain.Case) (int64, error)
+	PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error)
+	// Sync 将审核通过的制作库内容同步到线上库
+	Sync(ctx context.Context, id int64) error
+	Submit(ctx context.Context, id int64, reviewer int64) error
+	Review(ctx context.Context, id int64, reviewer int64, status uint8, comment string) error
+	Unpublish(ctx context.Context, id int64) error
+	ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Case, error)
+	TotalReview(ctx context.Context, reviewer int64) (int64, error)
 	// 管理端接口
 	List(ctx context.Context, offset int, limit int) ([]domain.Case, error)
 	Total(ctx context.Context) (int64, error)
 	Update(ctx context.Context, ca *domain.Case) error
 	Create(ctx context.Context, ca *domain.Case) (int64, error)
 	GetById(ctx context.Context, caseId int64) (domain.Case, error)
+
+	// Delete 放进回收站，同时删除线上库
+	Delete(ctx context.Context, id int64) error
+	ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Case, error)
+	TotalDeleted(ctx context.Context) (int64, error)
+	Restore(ctx context.Context, id int64) error
+	// Purge 彻底删除在 before 之前放进回收站的案例，返回删除的个数
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
+var ErrInvalidStatus = errors.New("案例不存在或者当前状态不允许该操作")
+
 type caseRepo struct {
 	caseDao   dao.CaseDAO
 	caseCache cache.CaseCache
 	logger    *elog.Component
+	// 缓存未命中的时候只允许一个请求回源，避免缓存击穿
+	sf singleflight.Group
 }
 
 func (c *caseRepo) PubList(ctx context.Context, offset int, limit int) ([]domain.Case, error) {
@@ -64,6 +86,30 @@ func (c *caseRepo) PubTotal(ctx context.Context) (int64, error) {
 }
 
 func (c *caseRepo) GetPubByID(ctx context.Context, caseId int64) (domain.Case, error) {
+	ca, err := c.caseCache.GetPubCase(ctx, caseId)
+	if err == nil {
+		return ca, nil
+	}
+	// 结果是所有等待的请求共享的，不能因为第一个请求被取消就让大家一起失败
+	sfCtx := context.WithoutCancel(ctx)
+	val, err, _ := c.sf.Do(fmt.Sprintf("pub:%d", caseId), func() (any, error) {
+		ca, err := c.pubCase(sfCtx, caseId)
+		if err != nil {
+			return domain.Case{}, err
+		}
+		err = c.caseCache.SetPubCase(sfCtx, ca)
+		if err != nil {
+			c.logger.Error("更新缓存中的案例失败", elog.FieldErr(err), elog.Int64("id", caseId))
+		}
+		return ca, nil
+	})
+	if err != nil {
+		return domain.Case{}, err
+	}
+	return val.(domain.Case), nil
+}
+
+func (c *caseRepo) pubCase(ctx context.Context, caseId int64) (domain.Case, error) {
 	caseInfo, err := c.caseDao.GetPublishCase(ctx, caseId)
 	if err != nil {
 		return domain.Case{}, err
@@ -78,9 +124,101 @@ func (c *caseRepo) GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Case,
 	}), err
 }
 
-func (c *caseRepo) Sync(ctx context.Context, ca *domain.Case) (int64, error) {
-	caseModel := c.toEntity(ca)
-	return c.caseDao.Sync(ctx, caseModel)
+func (c *caseRepo) PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error) {
+	return c.caseDao.PubIDs(ctx, minID, limit)
+}
+
+func (c *caseRepo) Sync(ctx context.Context, id int64) error {
+	err := c.statusErr(c.caseDao.Sync(ctx, id), id)
+	if err != nil {
+		return err
+	}
+	// 预热缓存，出错只记录日志，等过期之后自然就一致了
+	ca, err := c.pubCase(ctx, id)
+	if err == nil {
+		err = c.caseCache.SetPubCase(ctx, ca)
+	}
+	if err != nil {
+		c.logger.Error("预热缓存中的案例失败", elog.FieldErr(err), elog.Int64("id", id))
+	}
+	c.evictTotal(ctx)
+	return nil
+}
+
+func (c *caseRepo) evictTotal(ctx context.Context) {
+	if err := c.caseCache.DelTotal(ctx); err != nil {
+		c.logger.Error("删除缓存中的总数失败", elog.FieldErr(err))
+	}
+}
+
+func (c *caseRepo) Submit(ctx context.Context, id int64, reviewer int64) error {
+	return c.statusErr(c.caseDao.Submit(ctx, id, reviewer), id)
+}
+
+func (c *caseRepo) Review(ctx context.Context, id int64, reviewer int64, status uint8, comment string) error {
+	return c.statusErr(c.caseDao.Review(ctx, id, reviewer, status, comment), id)
+}
+
+func (c *caseRepo) Unpublish(ctx context.Context, id int64) error {
+	err := c.statusErr(c.caseDao.Unpublish(ctx, id), id)
+	if err != nil {
+		return err
+	}
+	if er := c.caseCache.DelPubCase(ctx, id); er != nil {
+		c.logger.Error("删除缓存中的案例失败", elog.FieldErr(er), elog.Int64("id", id))
+	}
+	c.evictTotal(ctx)
+	return nil
+}
+
+func (c *caseRepo) Delete(ctx context.Context, id int64) error {
+	err := c.statusErr(c.caseDao.Delete(ctx, id), id)
+	if err != nil {
+		return err
+	}
+	if er := c.caseCache.DelPubCase(ctx, id); er != nil {
+		c.logger.Error("删除缓存中的案例失败", elog.FieldErr(er), elog.Int64("id", id))
+	}
+	c.evictTotal(ctx)
+	return nil
+}
+
+func (c *caseRepo) ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Case, error) {
+	caseList, err := c.caseDao.ListDeleted(ctx, offset, limit)
+	return slice.Map(caseList, func(idx int, src dao.Case) domain.Case {
+		return c.toDomain(src)
+	}), err
+}
+
+func (c *caseRepo) TotalDeleted(ctx context.Context) (int64, error) {
+	return c.caseDao.CountDeleted(ctx)
+}
+
+func (c *caseRepo) Restore(ctx context.Context, id int64) error {
+	return c.statusErr(c.caseDao.Restore(ctx, id), id)
+}
+
+func (c *caseRepo) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return c.caseDao.Purge(ctx, before.UnixMilli(), limit)
+}
+
+// statusErr 审核流程中 DAO 用 gorm.ErrRecordNotFound 表示状态不对
+func (c *caseRepo) statusErr(err error, id int64) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: case %d", ErrInvalidStatus, id)
+	}
+	return err
+}
+
+func (c *caseRepo) ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Case, error) {
+	caseList, err := c.caseDao.ListReview(ctx, reviewer, offset, limit)
+	return slice.Map(caseList, func(idx int, src dao.Case) domain.Case {
+		return c.toDomain(src)
+	}), err
+}
+
+func (c *caseRepo) TotalReview(ctx context.Context, reviewer int64) (int64, error) {
+	return c.caseDao.CountReview(ctx, reviewer)
 }
 
 func (c *caseRepo) List(ctx context.Context, offset int, limit int) ([]domain.Case, error) {
@@ -146,7 +284,11 @@ func (c *caseRepo) toDomain(caseDao dao.Case) domain.Case {
 		Shorthand: caseDao.Shorthand,
 		Highlight: caseDao.Highlight,
 		Guidance:  caseDao.Guidance,
-		Utime:     time.UnixMilli(caseDao.Utime),
+
+		Status:        caseDao.Status,
+		Reviewer:      caseDao.Reviewer,
+		ReviewComment: caseDao.ReviewComment,
+		Utime:         time.UnixMilli(caseDao.Utime),
 	}
 }
 
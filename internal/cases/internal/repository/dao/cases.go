@@ -16,13 +16,35 @@ type CaseDAO interface {
 	List(ctx context.Context, offset, limit int) ([]Case, error)
 	Count(ctx context.Context) (int64, error)
 
-	Sync(ctx context.Context, c Case) (int64, error)
+	// Sync 将审核通过的制作库内容同步到线上库
+	// 如果案例不存在或者不是审核通过的状态，返回 gorm.ErrRecordNotFound
+	Sync(ctx context.Context, id int64) error
+
+	// 审核流程，状态不对的时候都返回 gorm.ErrRecordNotFound
+	Submit(ctx context.Context, id int64, reviewer int64) error
+	Review(ctx context.Context, id int64, reviewer int64, status uint8, comment string) error
+	// Unpublish 下架，线上库的数据会保留，但是不会再出现在线上库的查询结果中
+	Unpublish(ctx context.Context, id int64) error
+	ListReview(ctx context.Context, reviewer int64, offset, limit int) ([]Case, error)
+	CountReview(ctx context.Context, reviewer int64) (int64, error)
 
 	// 线上库
 	PublishCaseList(ctx context.Context, offset, limit int) ([]PublishCase, error)
 	PublishCaseCount(ctx context.Context) (int64, error)
 	GetPublishCase(ctx context.Context, caseId int64) (PublishCase, error)
 	GetPubByIDs(ctx context.Context, ids []int64) ([]PublishCase, error)
+	// PubIDs 按照 id 升序返回线上库中 id 大于 minID 的案例 id，用于批量同步
+	PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error)
+
+	// 回收站，删除的时候案例会先放到回收站里面，过一段时间之后才会彻底删除
+	// Delete 删除案例，线上库的数据会被直接删掉，案例不存在或者已经被删除的时候返回 gorm.ErrRecordNotFound
+	Delete(ctx context.Context, id int64) error
+	ListDeleted(ctx context.Context, offset, limit int) ([]Case, error)
+	CountDeleted(ctx context.Context) (int64, error)
+	// Restore 从回收站恢复，恢复之后是草稿状态，案例不在回收站里面的时候返回 gorm.ErrRecordNotFound
+	Restore(ctx context.Context, id int64) error
+	// Purge 彻底删除在 before 之前放进回收站的案例，一次最多删除 limit 个，返回删除的个数
+	Purge(ctx context.Context, before int64, limit int) (int64, error)
 }
 
 type caseDAO struct {
@@ -31,11 +53,14 @@ type caseDAO struct {
 
 func (ca *caseDAO) Count(ctx context.Context) (int64, error) {
 	var res int64
-	err := ca.db.WithContext(ctx).Model(&Case{}).Select("COUNT(id)").Count(&res).Error
+	err := ca.db.WithContext(ctx).Model(&Case{}).
+		Where("dtime = 0").
+		Select("COUNT(id)").Count(&res).Error
 	return res, err
 }
 
 func (ca *caseDAO) Create(ctx context.Context, c Case) (int64, error) {
+	c.Status = CaseStatusDraft
 	c.Ctime = time.Now().UnixMilli()
 	c.Utime = time.Now().UnixMilli()
 	err := ca.db.WithContext(ctx).Create(&c).Error
@@ -44,8 +69,10 @@ func (ca *caseDAO) Create(ctx context.Context, c Case) (int64, error) {
 
 func (ca *caseDAO) Update(ctx context.Context, c Case) error {
 	now := time.Now().UnixMilli()
+	// 修改之后需要重新审核
 	return ca.db.WithContext(ctx).
-		Model(&Case{}).Where("id = ?", c.Id).Updates(map[string]any{
+		Model(&Case{}).Where("id = ? AND dtime = 0", c.Id).Updates(map[string]any{
+		"status":    CaseStatusDraft,
 		"title":     c.Title,
 		"content":   c.Content,
 		"code_repo": c.CodeRepo,
@@ -58,33 +85,17 @@ func (ca *caseDAO) Update(ctx context.Context, c Case) error {
 	}).Error
 }
 
-func (ca *caseDAO) update(ctx context.Context, tx *gorm.DB, c Case) error {
-	now := time.Now().UnixMilli()
-	return tx.WithContext(ctx).
-		Model(&Case{}).Where("id = ?", c.Id).Updates(map[string]any{
-		"title":     c.Title,
-		"content":   c.Content,
-		"code_repo": c.CodeRepo,
-		"keywords":  c.Keywords,
-		"shorthand": c.Shorthand,
-		"highlight": c.Highlight,
-		"labels":    c.Labels,
-		"guidance":  c.Guidance,
-		"utime":     now,
-	}).Error
-
-}
-
 func (ca *caseDAO) GetCaseByID(ctx context.Context, id int64) (Case, error) {
 	var c Case
-	err := ca.db.WithContext(ctx).Where("id = ?", id).First(&c).Error
+	err := ca.db.WithContext(ctx).Where("id = ? AND dtime = 0", id).First(&c).Error
 	return c, err
 }
 
 func (ca *caseDAO) List(ctx context.Context, offset, limit int) ([]Case, error) {
 	var caseList []Case
 	err := ca.db.WithContext(ctx).
-		Select("id", "title", "content", "utime").
+		Select("id", "title", "content", "status", "utime").
+		Where("dtime = 0").
 		Order("id desc").
 		Offset(offset).
 		Limit(limit).
@@ -92,31 +103,115 @@ func (ca *caseDAO) List(ctx context.Context, offset, limit int) ([]Case, error)
 	return caseList, err
 }
 
-func (ca *caseDAO) Sync(ctx context.Context, c Case) (int64, error) {
-	id := c.Id
-	c.Utime = time.Now().UnixMilli()
-	err := ca.db.Transaction(func(tx *gorm.DB) error {
-		if c.Id == 0 {
-			c.Ctime = time.Now().UnixMilli()
-			err := tx.WithContext(ctx).Create(&c).Error
-			if err != nil {
-				return err
-			}
-			id = c.Id
-		} else {
-			err := ca.update(ctx, tx, c)
-			if err != nil {
-				return err
-			}
+func (ca *caseDAO) Sync(ctx context.Context, id int64) error {
+	return ca.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Case{}).
+			Where("id = ? AND status = ? AND dtime = 0", id, CaseStatusApproved).
+			Updates(map[string]any{
+				"status": CaseStatusPublished,
+				"utime":  time.Now().UnixMilli(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		var c Case
+		err := tx.Where("id = ?", id).First(&c).Error
+		if err != nil {
+			return err
 		}
 		return tx.Save(PublishCase(c)).Error
 	})
-	return id, err
+}
+
+func (ca *caseDAO) Submit(ctx context.Context, id int64, reviewer int64) error {
+	res := ca.db.WithContext(ctx).Model(&Case{}).
+		// 0 是引入审核流程之前的老数据，当成草稿处理
+		Where("id = ? AND dtime = 0 AND status IN ?", id, []uint8{0, CaseStatusDraft,
+			CaseStatusRejected, CaseStatusUnpublished}).
+		Updates(map[string]any{
+			"status":         CaseStatusPendingReview,
+			"reviewer":       reviewer,
+			"review_comment": "",
+			"utime":          time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (ca *caseDAO) Review(ctx context.Context, id int64, reviewer int64, status uint8, comment string) error {
+	res := ca.db.WithContext(ctx).Model(&Case{}).
+		Where("id = ? AND reviewer = ? AND status = ? AND dtime = 0", id, reviewer, CaseStatusPendingReview).
+		Updates(map[string]any{
+			"status":         status,
+			"review_comment": comment,
+			"utime":          time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (ca *caseDAO) Unpublish(ctx context.Context, id int64) error {
+	return ca.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		res := tx.Model(&PublishCase{}).
+			Where("id = ? AND status <> ?", id, CaseStatusUnpublished).
+			Updates(map[string]any{
+				"status": CaseStatusUnpublished,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		// 制作库可能已经被修改过了，这种时候保留制作库的状态
+		return tx.Model(&Case{}).
+			Where("id = ? AND status = ?", id, CaseStatusPublished).
+			Updates(map[string]any{
+				"status": CaseStatusUnpublished,
+				"utime":  now,
+			}).Error
+	})
+}
+
+func (ca *caseDAO) ListReview(ctx context.Context, reviewer int64, offset, limit int) ([]Case, error) {
+	var caseList []Case
+	err := ca.db.WithContext(ctx).
+		Select("id", "title", "content", "status", "utime").
+		Where("reviewer = ? AND status = ? AND dtime = 0", reviewer, CaseStatusPendingReview).
+		Order("id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(&caseList).Error
+	return caseList, err
+}
+
+func (ca *caseDAO) CountReview(ctx context.Context, reviewer int64) (int64, error) {
+	var res int64
+	err := ca.db.WithContext(ctx).Model(&Case{}).
+		Where("reviewer = ? AND status = ? AND dtime = 0", reviewer, CaseStatusPendingReview).
+		Count(&res).Error
+	return res, err
 }
 
 func (ca *caseDAO) PublishCaseList(ctx context.Context, offset, limit int) ([]PublishCase, error) {
 	publishCaseList := make([]PublishCase, 0, limit)
 	err := ca.db.WithContext(ctx).
+		Where("status <> ?", CaseStatusUnpublished).
 		Order("id desc").
 		Select("id", "title", "content", "utime").
 		Offset(offset).
@@ -127,24 +222,101 @@ func (ca *caseDAO) PublishCaseList(ctx context.Context, offset, limit int) ([]Pu
 
 func (ca *caseDAO) PublishCaseCount(ctx context.Context) (int64, error) {
 	var res int64
-	err := ca.db.WithContext(ctx).Model(&PublishCase{}).Select("COUNT(id)").Count(&res).Error
+	err := ca.db.WithContext(ctx).Model(&PublishCase{}).
+		Where("status <> ?", CaseStatusUnpublished).
+		Select("COUNT(id)").Count(&res).Error
 	return res, err
 }
 
 func (ca *caseDAO) GetPublishCase(ctx context.Context, caseId int64) (PublishCase, error) {
 	var c PublishCase
 	db := ca.db.WithContext(ctx)
-	err := db.Where("id = ?", caseId).First(&c).Error
+	err := db.Where("id = ? AND status <> ?", caseId, CaseStatusUnpublished).First(&c).Error
 	return c, err
 }
 
 func (ca *caseDAO) GetPubByIDs(ctx context.Context, ids []int64) ([]PublishCase, error) {
 	var c []PublishCase
 	db := ca.db.WithContext(ctx)
-	err := db.Where("id IN ?", ids).Find(&c).Error
+	err := db.Where("id IN ? AND status <> ?", ids, CaseStatusUnpublished).Find(&c).Error
 	return c, err
 }
 
+func (ca *caseDAO) PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error) {
+	var res []int64
+	err := ca.db.WithContext(ctx).Model(&PublishCase{}).
+		Where("id > ? AND status <> ?", minID, CaseStatusUnpublished).
+		Order("id ASC").Limit(limit).
+		Pluck("id", &res).Error
+	return res, err
+}
+
+func (ca *caseDAO) Delete(ctx context.Context, id int64) error {
+	return ca.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		res := tx.Model(&Case{}).
+			Where("id = ? AND dtime = 0", id).
+			Updates(map[string]any{
+				"dtime": now,
+				"utime": now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		// 线上库直接删除，恢复之后重新发布的时候会再同步过去
+		return tx.Where("id = ?", id).Delete(&PublishCase{}).Error
+	})
+}
+
+func (ca *caseDAO) ListDeleted(ctx context.Context, offset, limit int) ([]Case, error) {
+	var caseList []Case
+	err := ca.db.WithContext(ctx).
+		Select("id", "title", "content", "status", "utime").
+		Where("dtime > 0").
+		Order("dtime desc, id desc").
+		Offset(offset).
+		Limit(limit).
+		Find(&caseList).Error
+	return caseList, err
+}
+
+func (ca *caseDAO) CountDeleted(ctx context.Context) (int64, error) {
+	var res int64
+	err := ca.db.WithContext(ctx).Model(&Case{}).
+		Where("dtime > 0").
+		Select("COUNT(id)").Count(&res).Error
+	return res, err
+}
+
+func (ca *caseDAO) Restore(ctx context.Context, id int64) error {
+	res := ca.db.WithContext(ctx).Model(&Case{}).
+		Where("id = ? AND dtime > 0", id).
+		Updates(map[string]any{
+			"status": CaseStatusDraft,
+			"dtime":  0,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (ca *caseDAO) Purge(ctx context.Context, before int64, limit int) (int64, error) {
+	// 线上库的数据在删除的时候就已经删掉了，所以只需要删除制作库
+	res := ca.db.WithContext(ctx).
+		Where("dtime > 0 AND dtime < ?", before).
+		Limit(limit).
+		Delete(&Case{})
+	return res.RowsAffected, res.Error
+}
+
 func NewCaseDao(db *egorm.Component) CaseDAO {
 	return &caseDAO{
 		db: db,
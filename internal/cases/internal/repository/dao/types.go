@@ -21,8 +21,19 @@ type Case struct {
 	Highlight string
 	// 引导点
 	Guidance string
-	Ctime    int64
-	Utime    int64 `gorm:"index"`
+
+	// 状态，制作库和线上库共用这个字段
+	Status uint8 `gorm:"index"`
+	// 审核人
+	Reviewer int64 `gorm:"index"`
+	// 最近一次的审核意见
+	ReviewComment string
+
+	Ctime int64
+	Utime int64 `gorm:"index"`
+	// 删除时间，0 代表没有删除，删除之后会先放在回收站里面
+	// 线上库不使用这个字段，删除的时候线上库的数据会被直接删掉
+	Dtime int64 `gorm:"index"`
 }
 
 func (Case) TableName() string {
@@ -34,3 +45,12 @@ type PublishCase Case
 func (PublishCase) TableName() string {
 	return "publish_cases"
 }
+
+const (
+	CaseStatusDraft         = iota + 1 // 草稿
+	CaseStatusPendingReview            // 待审核
+	CaseStatusApproved                 // 审核通过
+	CaseStatusRejected                 // 审核拒绝
+	CaseStatusPublished                // 已发布
+	CaseStatusUnpublished              // 已下架
+)
@@ -2,9 +2,16 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/cases/internal/domain"
+	"github.com/ecodeclub/webook/internal/cases/internal/event"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/gotomicro/ego/core/elog"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -12,17 +19,55 @@ import (
 type Service interface {
 	// Save 保存数据，case 绝对不会为 nil
 	Save(ctx context.Context, ca *domain.Case) (int64, error)
-	Publish(ctx context.Context, ca *domain.Case) (int64, error)
+	// Publish 发布，只有审核通过的案例才能发布
+	Publish(ctx context.Context, id int64) error
+	// Submit uid 提交审核，并且指定审核人，审核人不能是 uid 自己
+	Submit(ctx context.Context, id int64, uid int64, reviewer int64) error
+	// Review 审核，只有被指定的审核人才能审核
+	Review(ctx context.Context, id int64, reviewer int64, approved bool, comment string) error
+	// Unpublish 下架，案例不会被删除，但是不会再出现在线上库中
+	Unpublish(ctx context.Context, id int64) error
+	// ListReview 分配给 reviewer 的待审核案例
+	ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Case, int64, error)
 	List(ctx context.Context, offset int, limit int) ([]domain.Case, int64, error)
 
 	PubList(ctx context.Context, offset int, limit int) ([]domain.Case, int64, error)
+	// PubListByLabels 按照标签过滤线上库，matchAll 为 true 的时候要求具备全部标签，否则具备任意一个即可
+	PubListByLabels(ctx context.Context, lids []int64, matchAll bool, offset int, limit int) ([]domain.Case, int64, error)
 	GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Case, error)
 	Detail(ctx context.Context, caseId int64) (domain.Case, error)
 	PubDetail(ctx context.Context, caseId int64) (domain.Case, error)
+
+	// Delete 删除案例，案例会先放进回收站，线上库的内容会被一并删除
+	// 被技能引用的案例不能删除
+	Delete(ctx context.Context, id int64) error
+	// ListDeleted 回收站里面的案例，按照删除时间倒序
+	ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Case, int64, error)
+	// Restore 从回收站恢复，恢复之后是草稿，需要重新审核发布
+	Restore(ctx context.Context, id int64) error
+	// Purge 彻底删除在 before 之前放进回收站的案例，一次最多 limit 个，返回删除的个数
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
+	// Reindex 将线上库中 id 大于 minID 的案例重新同步到标签和搜索，一次最多 limit 个
+	// 返回最后一个案例的 id，没有更多案例的时候返回 0
+	Reindex(ctx context.Context, minID int64, limit int) (int64, error)
 }
 
+var (
+	ErrInvalidStatus   = repository.ErrInvalidStatus
+	ErrCaseReferenced  = errors.New("案例还被技能引用")
+	ErrInvalidReviewer = errors.New("审核人非法")
+)
+
+// 在标签、搜索等模块里面代表案例
+const biz = "case"
+
 type service struct {
-	repo repository.CaseRepo
+	repo       repository.CaseRepo
+	labelSvc   label.Service
+	producer   event.SyncEventProducer
+	refSvc     ReferenceService
+	relatedSvc RelatedService
+	logger     *elog.Component
 }
 
 func (s *service) GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Case, error) {
@@ -36,8 +81,164 @@ func (s *service) Save(ctx context.Context, ca *domain.Case) (int64, error) {
 	return s.repo.Create(ctx, ca)
 }
 
-func (s *service) Publish(ctx context.Context, ca *domain.Case) (int64, error) {
-	return s.repo.Sync(ctx, ca)
+func (s *service) Publish(ctx context.Context, id int64) error {
+	err := s.repo.Sync(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 同步标签和搜索失败不影响发布本身
+	ca, err := s.repo.GetPubByID(ctx, id)
+	if err != nil {
+		s.logger.Error("同步时查询线上库失败", elog.FieldErr(err), elog.Int64("id", id))
+		return nil
+	}
+	err = s.labelSvc.SaveBizLabels(ctx, biz, id, ca.Labels)
+	if err != nil {
+		s.logger.Error("保存案例的标签失败", elog.FieldErr(err), elog.Int64("id", id))
+	}
+	s.produceSyncEvent(ctx, s.toSyncEvent(ca))
+	return nil
+}
+
+func (s *service) Reindex(ctx context.Context, minID int64, limit int) (int64, error) {
+	ids, err := s.repo.PubIDs(ctx, minID, limit)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	cs, err := s.repo.GetPubByIDs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	for _, ca := range cs {
+		err = s.labelSvc.SaveBizLabels(ctx, biz, ca.Id, ca.Labels)
+		if err != nil {
+			return 0, fmt.Errorf("保存案例的标签失败 id %d: %w", ca.Id, err)
+		}
+		err = s.producer.Produce(ctx, s.toSyncEvent(ca))
+		if err != nil {
+			return 0, fmt.Errorf("发送搜索同步消息失败 id %d: %w", ca.Id, err)
+		}
+	}
+	return ids[len(ids)-1], nil
+}
+
+func (s *service) toSyncEvent(ca domain.Case) event.SyncSearchEvent {
+	return event.SyncSearchEvent{
+		Biz:      biz,
+		BizId:    ca.Id,
+		Title:    ca.Title,
+		Content:  ca.Content,
+		Keywords: ca.Keywords,
+		Labels:   ca.Labels,
+	}
+}
+
+func (s *service) Submit(ctx context.Context, id int64, uid int64, reviewer int64) error {
+	if reviewer <= 0 || reviewer == uid {
+		return fmt.Errorf("%w: uid %d, reviewer %d", ErrInvalidReviewer, uid, reviewer)
+	}
+	return s.repo.Submit(ctx, id, reviewer)
+}
+
+func (s *service) Review(ctx context.Context, id int64, reviewer int64, approved bool, comment string) error {
+	status := uint8(domain.CaseStatusRejected)
+	if approved {
+		status = domain.CaseStatusApproved
+	}
+	return s.repo.Review(ctx, id, reviewer, status, comment)
+}
+
+func (s *service) Unpublish(ctx context.Context, id int64) error {
+	err := s.repo.Unpublish(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.removePub(ctx, id)
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, id int64) error {
+	referenced, err := s.refSvc.Referenced(ctx, id)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return fmt.Errorf("%w: case %d", ErrCaseReferenced, id)
+	}
+	err = s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.removePub(ctx, id)
+	return nil
+}
+
+func (s *service) ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Case, int64, error) {
+	var (
+		total    int64
+		caseList []domain.Case
+		eg       errgroup.Group
+	)
+	eg.Go(func() error {
+		var err error
+		caseList, err = s.repo.ListDeleted(ctx, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.repo.TotalDeleted(ctx)
+		return err
+	})
+	err := eg.Wait()
+	return caseList, total, err
+}
+
+func (s *service) Restore(ctx context.Context, id int64) error {
+	return s.repo.Restore(ctx, id)
+}
+
+func (s *service) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return s.repo.Purge(ctx, before, limit)
+}
+
+// removePub 案例从线上库消失之后，同步删除标签、相关推荐和搜索索引，失败了也不影响下架或者删除本身
+func (s *service) removePub(ctx context.Context, id int64) {
+	err := s.labelSvc.DeleteBizLabels(ctx, biz, id)
+	if err != nil {
+		s.logger.Error("删除案例的标签失败", elog.FieldErr(err), elog.Int64("id", id))
+	}
+	err = s.relatedSvc.RemoveCase(ctx, id)
+	if err != nil {
+		s.logger.Error("删除案例的相关推荐失败", elog.FieldErr(err), elog.Int64("id", id))
+	}
+	s.produceSyncEvent(ctx, event.SyncSearchEvent{Biz: biz, BizId: id, Deleted: true})
+}
+
+func (s *service) produceSyncEvent(ctx context.Context, evt event.SyncSearchEvent) {
+	err := s.producer.Produce(ctx, evt)
+	if err != nil {
+		s.logger.Error("发送搜索同步消息失败", elog.FieldErr(err), elog.Int64("id", evt.BizId))
+	}
+}
+
+func (s *service) ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Case, int64, error) {
+	var (
+		total    int64
+		caseList []domain.Case
+		eg       errgroup.Group
+	)
+	eg.Go(func() error {
+		var err error
+		caseList, err = s.repo.ListReview(ctx, reviewer, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.repo.TotalReview(ctx, reviewer)
+		return err
+	})
+	err := eg.Wait()
+	return caseList, total, err
 }
 
 func (s *service) List(ctx context.Context, offset int, limit int) ([]domain.Case, int64, error) {
@@ -62,6 +263,26 @@ func (s *service) List(ctx context.Context, offset int, limit int) ([]domain.Cas
 	return caseList, total, nil
 }
 
+func (s *service) PubListByLabels(ctx context.Context, lids []int64, matchAll bool,
+	offset int, limit int) ([]domain.Case, int64, error) {
+	ids, total, err := s.labelSvc.BizIDs(ctx, biz, lids, matchAll, offset, limit)
+	if err != nil || len(ids) == 0 {
+		return nil, total, err
+	}
+	cs, err := s.repo.GetPubByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 保持标签模块给出的顺序
+	cm := slice.ToMap(cs, func(element domain.Case) int64 {
+		return element.Id
+	})
+	return slice.FilterMap(ids, func(idx int, src int64) (domain.Case, bool) {
+		ca, ok := cm[src]
+		return ca, ok
+	}), total, nil
+}
+
 func (s *service) PubList(ctx context.Context, offset int, limit int) ([]domain.Case, int64, error) {
 
 	var (
@@ -91,8 +312,14 @@ func (s *service) PubDetail(ctx context.Context, caseId int64) (domain.Case, err
 	return s.repo.GetPubByID(ctx, caseId)
 }
 
-func NewService(repo repository.CaseRepo) Service {
+func NewService(repo repository.CaseRepo, labelSvc label.Service,
+	producer event.SyncEventProducer, refSvc ReferenceService, relatedSvc RelatedService) Service {
 	return &service{
-		repo: repo,
+		repo:       repo,
+		labelSvc:   labelSvc,
+		producer:   producer,
+		refSvc:     refSvc,
+		relatedSvc: relatedSvc,
+		logger:     elog.DefaultLogger,
 	}
 }
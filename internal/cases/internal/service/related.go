@@ -0,0 +1,25 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import "context"
+
+// RelatedService 题目详情里面的相关推荐会引用案例，案例下架或者删除之后需要去掉。
+// 推荐模块依赖案例模块，所以由推荐模块实现
+//
+//go:generate mockgen -source=./related.go -destination=../../mocks/related.mock.go -package=casemocks -typed RelatedService
+type RelatedService interface {
+	RemoveCase(ctx context.Context, cid int64) error
+}
@@ -15,6 +15,7 @@
 package web
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -24,35 +25,51 @@ import (
 	"github.com/ecodeclub/ginx/session"
 	"github.com/ecodeclub/webook/internal/cases/internal/domain"
 	"github.com/ecodeclub/webook/internal/cases/internal/service"
+	"github.com/ecodeclub/webook/internal/note"
 	"github.com/gin-gonic/gin"
 	"github.com/gotomicro/ego/core/elog"
 )
 
+// 分页查询一次最多返回的数量
+const maxLimit = 100
+
 type Handler struct {
-	svc    service.Service
-	logger *elog.Component
+	svc     service.Service
+	noteSvc note.Service
+	logger  *elog.Component
 }
 
-func NewHandler(svc service.Service) *Handler {
+func NewHandler(svc service.Service, noteSvc note.Service) *Handler {
 	return &Handler{
-		svc:    svc,
-		logger: elog.DefaultLogger,
+		svc:     svc,
+		noteSvc: noteSvc,
+		logger:  elog.DefaultLogger,
 	}
 }
 
 func (h *Handler) PublicRoutes(server *gin.Engine) {
-	server.POST("/case/pub/list", ginx.B[Page](h.PubList))
+	server.POST("/case/pub/list", ginx.B[PubListReq](h.PubList))
 }
 
 func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	server.POST("/case/save", ginx.S(h.Permission), ginx.BS[SaveReq](h.Save))
 	server.POST("/case/list", ginx.S(h.Permission), ginx.B[Page](h.List))
 	server.POST("/case/detail", ginx.S(h.Permission), ginx.B[CaseId](h.Detail))
-	server.POST("/case/publish", ginx.S(h.Permission), ginx.BS[SaveReq](h.Publish))
+	server.POST("/case/publish", ginx.S(h.Permission), ginx.B[CaseId](h.Publish))
+	server.POST("/case/unpublish", ginx.S(h.Permission), ginx.B[CaseId](h.Unpublish))
+
+	server.POST("/case/delete", ginx.S(h.Permission), ginx.B[CaseId](h.Delete))
+	server.POST("/case/recycle/list", ginx.S(h.Permission), ginx.B[Page](h.ListDeleted))
+	server.POST("/case/recycle/restore", ginx.S(h.Permission), ginx.B[CaseId](h.Restore))
+
+	server.POST("/case/submit", ginx.S(h.Permission), ginx.BS[SubmitReq](h.Submit))
+	server.POST("/case/review", ginx.S(h.Permission), ginx.BS[ReviewReq](h.Review))
+	server.POST("/case/review/list", ginx.S(h.Permission), ginx.BS[Page](h.ListReview))
 }
 
 func (h *Handler) MemberRoutes(server *gin.Engine) {
-	server.POST("/case/pub/detail", ginx.B[CaseId](h.PubDetail))
+	server.POST("/case/pub/detail", ginx.BS[CaseId](h.PubDetail))
+	server.POST("/case/note/save", ginx.BS[NoteReq](h.SaveNote))
 }
 
 func (h *Handler) Save(ctx *ginx.Context,
@@ -85,13 +102,30 @@ func (h *Handler) Detail(ctx *ginx.Context, req CaseId) (ginx.Result, error) {
 	if err != nil {
 		return systemErrorResult, err
 	}
+	ca := newCase(detail)
+	ca.Status = detail.Status
+	ca.Reviewer = detail.Reviewer
+	ca.ReviewComment = detail.ReviewComment
 	return ginx.Result{
-		Data: newCase(detail),
+		Data: ca,
 	}, err
 }
 
-func (h *Handler) PubList(ctx *ginx.Context, req Page) (ginx.Result, error) {
-	data, cnt, err := h.svc.PubList(ctx, req.Offset, req.Limit)
+func (h *Handler) PubList(ctx *ginx.Context, req PubListReq) (ginx.Result, error) {
+	req.Offset = max(req.Offset, 0)
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	var (
+		data []domain.Case
+		cnt  int64
+		err  error
+	)
+	if len(req.LabelIds) > 0 {
+		data, cnt, err = h.svc.PubListByLabels(ctx, req.LabelIds, req.MatchAll, req.Offset, req.Limit)
+	} else {
+		data, cnt, err = h.svc.PubList(ctx, req.Offset, req.Limit)
+	}
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -110,33 +144,132 @@ func (h *Handler) PubList(ctx *ginx.Context, req Page) (ginx.Result, error) {
 	}, nil
 }
 
-func (h *Handler) PubDetail(ctx *ginx.Context, req CaseId) (ginx.Result, error) {
+func (h *Handler) PubDetail(ctx *ginx.Context, req CaseId, sess session.Session) (ginx.Result, error) {
 	detail, err := h.svc.PubDetail(ctx, req.Cid)
 	if err != nil {
 		return systemErrorResult, err
 	}
+	ca := newCase(detail)
+	ca.Notes = note.BizNotes(ctx, h.noteSvc, sess.Claims().Uid, note.BizCase, req.Cid)
 	return ginx.Result{
-		Data: newCase(detail),
-	}, err
+		Data: ca,
+	}, nil
 }
 
-func (h *Handler) Publish(ctx *ginx.Context, req SaveReq, sess session.Session) (ginx.Result, error) {
-	ca := req.Case.toDomain()
-	ca.Uid = sess.Claims().Uid
-	id, err := h.svc.Publish(ctx, &ca)
+// SaveNote 给已经发布的案例写笔记，案例不区分答案的部分
+func (h *Handler) SaveNote(ctx *ginx.Context, req NoteReq, sess session.Session) (ginx.Result, error) {
+	cs, err := h.svc.GetPubByIDs(ctx, []int64{req.Cid})
 	if err != nil {
 		return systemErrorResult, err
 	}
+	if len(cs) == 0 {
+		return caseNotFoundResult, nil
+	}
+	err = h.noteSvc.Save(ctx, note.Note{
+		Uid:     sess.Claims().Uid,
+		Biz:     note.BizCase,
+		BizId:   req.Cid,
+		Content: req.Content,
+	})
+	switch {
+	case errors.Is(err, note.ErrInvalidNote):
+		return note.InvalidNoteResult, nil
+	case err != nil:
+		return systemErrorResult, err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Publish(ctx *ginx.Context, req CaseId) (ginx.Result, error) {
+	err := h.svc.Publish(ctx, req.Cid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
 	return ginx.Result{
-		Data: id,
+		Data: req.Cid,
+	}, nil
+}
+
+func (h *Handler) Unpublish(ctx *ginx.Context, req CaseId) (ginx.Result, error) {
+	err := h.svc.Unpublish(ctx, req.Cid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Submit(ctx *ginx.Context, req SubmitReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Submit(ctx, req.Cid, sess.Claims().Uid, req.Reviewer)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Review(ctx *ginx.Context, req ReviewReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Review(ctx, req.Cid, sess.Claims().Uid, req.Approved, req.Comment)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) ListReview(ctx *ginx.Context, req Page, sess session.Session) (ginx.Result, error) {
+	data, cnt, err := h.svc.ListReview(ctx, sess.Claims().Uid, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toCaseList(data, cnt),
+	}, nil
+}
+
+func (h *Handler) Delete(ctx *ginx.Context, req CaseId) (ginx.Result, error) {
+	err := h.svc.Delete(ctx, req.Cid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) ListDeleted(ctx *ginx.Context, req Page) (ginx.Result, error) {
+	data, cnt, err := h.svc.ListDeleted(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toCaseList(data, cnt),
 	}, nil
 }
 
+func (h *Handler) Restore(ctx *ginx.Context, req CaseId) (ginx.Result, error) {
+	err := h.svc.Restore(ctx, req.Cid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) errorResult(err error) ginx.Result {
+	switch {
+	case errors.Is(err, service.ErrInvalidStatus):
+		return invalidStatusResult
+	case errors.Is(err, service.ErrCaseReferenced):
+		return caseInSkillResult
+	case errors.Is(err, service.ErrInvalidReviewer):
+		return invalidReviewerResult
+	default:
+		return systemErrorResult
+	}
+}
+
 func (h *Handler) toCaseList(data []domain.Case, cnt int64) CasesList {
 	return CasesList{
 		Total: cnt,
 		Cases: slice.Map(data, func(idx int, ca domain.Case) Case {
-			return newCase(ca)
+			res := newCase(ca)
+			res.Status = ca.Status
+			return res
 		}),
 	}
 }
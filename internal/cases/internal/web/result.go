@@ -24,4 +24,20 @@ var (
 		Code: errs.SystemError.Code,
 		Msg:  errs.SystemError.Msg,
 	}
+	invalidStatusResult = ginx.Result{
+		Code: errs.InvalidStatus.Code,
+		Msg:  errs.InvalidStatus.Msg,
+	}
+	caseNotFoundResult = ginx.Result{
+		Code: errs.CaseNotFound.Code,
+		Msg:  errs.CaseNotFound.Msg,
+	}
+	caseInSkillResult = ginx.Result{
+		Code: errs.CaseInSkill.Code,
+		Msg:  errs.CaseInSkill.Msg,
+	}
+	invalidReviewerResult = ginx.Result{
+		Code: errs.InvalidReviewer.Code,
+		Msg:  errs.InvalidReviewer.Msg,
+	}
 )
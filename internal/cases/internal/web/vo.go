@@ -1,11 +1,23 @@
 package web
 
-import "github.com/ecodeclub/webook/internal/cases/internal/domain"
+import (
+	"github.com/ecodeclub/webook/internal/cases/internal/domain"
+	"github.com/ecodeclub/webook/internal/note"
+)
 
 type Page struct {
 	Offset int `json:"offset,omitempty"`
 	Limit  int `json:"limit,omitempty"`
 }
+
+type PubListReq struct {
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+	// 按照标签过滤，为空的时候不过滤
+	LabelIds []int64 `json:"labelIds,omitempty"`
+	// 为 true 的时候要求具备全部标签，否则具备任意一个即可
+	MatchAll bool `json:"matchAll,omitempty"`
+}
 type CasesList struct {
 	Cases []Case `json:"cases,omitempty"`
 	Total int64  `json:"total,omitempty"`
@@ -28,12 +40,37 @@ type Case struct {
 	// 引导点
 	Guidance string `json:"guidance,omitempty"`
 
+	// 以下三个字段只在制作库中有意义
+	Status        uint8  `json:"status,omitempty"`
+	Reviewer      int64  `json:"reviewer,omitempty"`
+	ReviewComment string `json:"reviewComment,omitempty"`
+
 	Utime string `json:"utime,omitempty"`
+
+	// 用户自己的笔记，只在线上库详情里面有
+	Notes []note.NoteVO `json:"notes,omitempty"`
 }
 
 type CaseId struct {
 	Cid int64 `json:"cid"`
 }
+
+type NoteReq struct {
+	Cid     int64  `json:"cid"`
+	Content string `json:"content"`
+}
+
+type SubmitReq struct {
+	Cid int64 `json:"cid"`
+	// 审核人
+	Reviewer int64 `json:"reviewer"`
+}
+
+type ReviewReq struct {
+	Cid      int64  `json:"cid"`
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment,omitempty"`
+}
 type SaveReq struct {
 	Case Case `json:"case,omitempty"`
 }
@@ -17,4 +17,8 @@ package cases
 type Module struct {
 	Svc Service
 	Hdl *Handler
+	// 定时清理回收站
+	PurgeJob *PurgeJob
+	// 命令行重新同步到标签和搜索
+	ReindexJob *ReindexJob
 }
@@ -4,26 +4,46 @@ package cases
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ecodeclub/webook/internal/cases/internal/domain"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/cases/internal/event"
+	"github.com/ecodeclub/webook/internal/cases/internal/job"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/cases/internal/service"
 	"github.com/ecodeclub/webook/internal/cases/internal/web"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
 	"github.com/ego-component/egorm"
 	"github.com/google/wire"
 	"gorm.io/gorm"
 )
 
-func InitModule(db *egorm.Component, ec ecache.Cache) (*Module, error) {
+func InitModule(db *egorm.Component, ec ecache.Cache, q mq.MQ,
+	labelModule *label.Module, noteModule *note.Module, refSvc ReferenceService,
+	relatedSvc RelatedService) (*Module, error) {
+	wire.Build(initSyncEventProducer, InitModuleWithProducer)
+	return new(Module), nil
+}
+
+// InitModuleWithProducer 测试的时候可以传入 mock 的 producer
+func InitModuleWithProducer(db *egorm.Component, ec ecache.Cache,
+	p event.SyncEventProducer, labelModule *label.Module, noteModule *note.Module,
+	refSvc ReferenceService, relatedSvc RelatedService) (*Module, error) {
 	wire.Build(InitCaseDAO,
+		wire.FieldsOf(new(*label.Module), "Svc"),
+		wire.FieldsOf(new(*note.Module), "Svc"),
 		cache.NewCaseCache,
 		repository.NewCaseRepo,
 		NewService,
 		web.NewHandler,
+		initPurgeJob,
+		initReindexJob,
 		wire.Struct(new(Module), "*"),
 	)
 	return new(Module), nil
@@ -40,8 +60,26 @@ func InitTableOnce(db *gorm.DB) {
 	})
 }
 
-func NewService(repo repository.CaseRepo) Service {
-	return service.NewService(repo)
+func NewService(repo repository.CaseRepo, labelSvc label.Service,
+	p event.SyncEventProducer, refSvc ReferenceService, relatedSvc RelatedService) Service {
+	return service.NewService(repo, labelSvc, p, refSvc, relatedSvc)
+}
+
+// initPurgeJob 回收站里面的案例保留 30 天
+func initPurgeJob(svc service.Service) *PurgeJob {
+	return job.NewPurgeJob(svc, 30, 100, time.Hour)
+}
+
+func initReindexJob(svc service.Service) *ReindexJob {
+	return job.NewReindexJob(svc, 100)
+}
+
+func initSyncEventProducer(q mq.MQ) event.SyncEventProducer {
+	producer, err := event.NewSyncEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
 }
 
 func InitCaseDAO(db *egorm.Component) dao.CaseDAO {
@@ -51,4 +89,8 @@ func InitCaseDAO(db *egorm.Component) dao.CaseDAO {
 
 type Handler = web.Handler
 type Service = service.Service
+type ReferenceService = service.ReferenceService
+type RelatedService = service.RelatedService
+type PurgeJob = job.PurgeJob
+type ReindexJob = job.ReindexJob
 type Case = domain.Case
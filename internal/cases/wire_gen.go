@@ -8,29 +8,51 @@ package cases
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/mq-api"
 	"github.com/ecodeclub/webook/internal/cases/internal/domain"
+	"github.com/ecodeclub/webook/internal/cases/internal/event"
+	"github.com/ecodeclub/webook/internal/cases/internal/job"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/cases/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/cases/internal/service"
 	"github.com/ecodeclub/webook/internal/cases/internal/web"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
 	"github.com/ego-component/egorm"
 	"gorm.io/gorm"
 )
 
 // Injectors from wire.go:
 
-func InitModule(db *gorm.DB, ec ecache.Cache) (*Module, error) {
+func InitModule(db *gorm.DB, ec ecache.Cache, q mq.MQ, labelModule *label.Module, noteModule *note.Module, refSvc service.ReferenceService, relatedSvc service.RelatedService) (*Module, error) {
+	syncEventProducer := initSyncEventProducer(q)
+	module, err := InitModuleWithProducer(db, ec, syncEventProducer, labelModule, noteModule, refSvc, relatedSvc)
+	if err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// InitModuleWithProducer 测试的时候可以传入 mock 的 producer
+func InitModuleWithProducer(db *gorm.DB, ec ecache.Cache, p event.SyncEventProducer, labelModule *label.Module, noteModule *note.Module, refSvc service.ReferenceService, relatedSvc service.RelatedService) (*Module, error) {
 	caseDAO := InitCaseDAO(db)
 	caseCache := cache.NewCaseCache(ec)
 	caseRepo := repository.NewCaseRepo(caseDAO, caseCache)
-	service := NewService(caseRepo)
-	handler := web.NewHandler(service)
+	serviceService := labelModule.Svc
+	service2 := NewService(caseRepo, serviceService, p, refSvc, relatedSvc)
+	service3 := noteModule.Svc
+	handler := web.NewHandler(service2, service3)
+	purgeJob := initPurgeJob(service2)
+	reindexJob := initReindexJob(service2)
 	module := &Module{
-		Svc: service,
-		Hdl: handler,
+		Svc:        service2,
+		Hdl:        handler,
+		PurgeJob:   purgeJob,
+		ReindexJob: reindexJob,
 	}
 	return module, nil
 }
@@ -48,8 +70,26 @@ func InitTableOnce(db *gorm.DB) {
 	})
 }
 
-func NewService(repo repository.CaseRepo) Service {
-	return service.NewService(repo)
+func NewService(repo repository.CaseRepo, labelSvc label.Service,
+	p event.SyncEventProducer, refSvc ReferenceService, relatedSvc RelatedService) Service {
+	return service.NewService(repo, labelSvc, p, refSvc, relatedSvc)
+}
+
+// initPurgeJob 回收站里面的案例保留 30 天
+func initPurgeJob(svc service.Service) *PurgeJob {
+	return job.NewPurgeJob(svc, 30, 100, time.Hour)
+}
+
+func initReindexJob(svc service.Service) *ReindexJob {
+	return job.NewReindexJob(svc, 100)
+}
+
+func initSyncEventProducer(q mq.MQ) event.SyncEventProducer {
+	producer, err := event.NewSyncEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
 }
 
 func InitCaseDAO(db *egorm.Component) dao.CaseDAO {
@@ -61,4 +101,12 @@ type Handler = web.Handler
 
 type Service = service.Service
 
+type ReferenceService = service.ReferenceService
+
+type RelatedService = service.RelatedService
+
+type PurgeJob = job.PurgeJob
+
+type ReindexJob = job.ReindexJob
+
 type Case = domain.Case
@@ -0,0 +1,69 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// CheckIn 一次签到记录
+type CheckIn struct {
+	ID  int64
+	Uid int64
+	// Day 签到日期，形如 20240501
+	Day int
+	// Streak 截止到本次签到的连续签到天数
+	Streak int
+	// Reward 本次签到获得的积分
+	Reward uint64
+	Ctime  int64
+}
+
+// Calendar 某个月的签到日历
+type Calendar struct {
+	Year  int
+	Month int
+	// Days 该月已签到的日期，如 [1, 2, 5]
+	Days []int
+	// Streak 截止到今天的连续签到天数
+	Streak         int
+	CheckedInToday bool
+}
+
+// RewardConfig 签到奖励配置
+type RewardConfig struct {
+	// Base 每次签到都能获得的积分
+	Base  uint64
+	Rules []RewardRule
+}
+
+// RewardRule 连续签到奖励规则，连续签到达到 Streak 天时额外奖励 Amount 积分
+type RewardRule struct {
+	Streak int
+	Amount uint64
+}
+
+func (c RewardConfig) Reward(streak int) uint64 {
+	res := c.Base
+	for _, r := range c.Rules {
+		if r.Streak == streak {
+			res += r.Amount
+		}
+	}
+	return res
+}
+
+// DayOf 将时间转化为形如 20240501 的日期
+func DayOf(t time.Time) int {
+	return t.Year()*10000 + int(t.Month())*100 + t.Day()
+}
@@ -0,0 +1,249 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/checkin/internal/domain"
+	"github.com/ecodeclub/webook/internal/checkin/internal/errs"
+	"github.com/ecodeclub/webook/internal/checkin/internal/event"
+	evtmocks "github.com/ecodeclub/webook/internal/checkin/internal/event/mocks"
+	"github.com/ecodeclub/webook/internal/checkin/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/checkin/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/checkin/internal/web"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const uid = 2601
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server   *egin.Component
+	db       *egorm.Component
+	rdb      redis.Cmdable
+	ctrl     *gomock.Controller
+	producer *evtmocks.MockIncreaseCreditsEventProducer
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	s.ctrl = gomock.NewController(s.T())
+	s.producer = evtmocks.NewMockIncreaseCreditsEventProducer(s.ctrl)
+	handler, err := startup.InitHandler(s.producer, domain.RewardConfig{
+		Base: 1,
+		Rules: []domain.RewardRule{
+			{Streak: 3, Amount: 10},
+		},
+	})
+	require.NoError(s.T(), err)
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid: uid,
+		}))
+	})
+	handler.PrivateRoutes(server.Engine)
+	s.server = server
+	s.db = testioc.InitDB()
+	s.rdb = testioc.InitRedis()
+}
+
+func (s *HandlerTestSuite) TearDownSuite() {
+	err := s.db.Exec("DROP TABLE `check_ins`").Error
+	require.NoError(s.T(), err)
+	s.ctrl.Finish()
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `check_ins`").Error
+	require.NoError(s.T(), err)
+	now := time.Now()
+	for _, t := range []time.Time{now, now.AddDate(0, -1, 0)} {
+		err = s.rdb.Del(context.Background(), s.key(t)).Err()
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *HandlerTestSuite) TestCheckIn() {
+	now := time.Now()
+	today := domain.DayOf(now)
+	testCases := []struct {
+		name     string
+		before   func(t *testing.T)
+		after    func(t *testing.T)
+		wantCode int
+		wantResp test.Result[web.CheckInResp]
+	}{
+		{
+			name: "首次签到",
+			before: func(t *testing.T) {
+				s.producer.EXPECT().Produce(gomock.Any(), event.CreditIncreaseEvent{
+					Key:    fmt.Sprintf("checkin:%d:%d", uid, today),
+					Uid:    uid,
+					Amount: 1,
+					Biz:    10,
+					BizId:  1,
+					Action: "每日签到",
+				}).Return(nil)
+			},
+			after: func(t *testing.T) {
+				var c dao.CheckIn
+				err := s.db.Where("uid = ? AND day = ?", uid, today).First(&c).Error
+				require.NoError(t, err)
+				assert.Equal(t, 1, c.Streak)
+				assert.Equal(t, uint64(1), c.Reward)
+				bit, err := s.rdb.GetBit(context.Background(), s.key(now), int64(now.Day())).Result()
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), bit)
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.CheckInResp]{
+				Data: web.CheckInResp{
+					Day:    today,
+					Streak: 1,
+					Reward: 1,
+				},
+			},
+		},
+		{
+			name: "连续签到达到奖励规则",
+			before: func(t *testing.T) {
+				s.createCheckIns(t, now.AddDate(0, 0, -2), now.AddDate(0, 0, -1))
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, evt event.CreditIncreaseEvent) error {
+						assert.Equal(t, uint64(11), evt.Amount)
+						return nil
+					})
+			},
+			after: func(t *testing.T) {
+				var c dao.CheckIn
+				err := s.db.Where("uid = ? AND day = ?", uid, today).First(&c).Error
+				require.NoError(t, err)
+				assert.Equal(t, 3, c.Streak)
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.CheckInResp]{
+				Data: web.CheckInResp{
+					Day:    today,
+					Streak: 3,
+					Reward: 11,
+				},
+			},
+		},
+		{
+			name: "连续签到中断",
+			before: func(t *testing.T) {
+				s.createCheckIns(t, now.AddDate(0, 0, -3), now.AddDate(0, 0, -2))
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			after:    func(t *testing.T) {},
+			wantCode: 200,
+			wantResp: test.Result[web.CheckInResp]{
+				Data: web.CheckInResp{
+					Day:    today,
+					Streak: 1,
+					Reward: 1,
+				},
+			},
+		},
+		{
+			name: "今日已签到",
+			before: func(t *testing.T) {
+				s.createCheckIns(t, now)
+			},
+			after:    func(t *testing.T) {},
+			wantCode: 200,
+			wantResp: test.Result[web.CheckInResp]{
+				Code: errs.AlreadyCheckedIn.Code,
+				Msg:  errs.AlreadyCheckedIn.Msg,
+			},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost, "/checkin", iox.NewJSONReader(nil))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[web.CheckInResp]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+			tc.after(t)
+			s.TearDownTest()
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestCalendar() {
+	now := time.Now()
+	yesterday := now.AddDate(0, 0, -1)
+	s.createCheckIns(s.T(), yesterday)
+
+	req, err := http.NewRequest(http.MethodPost, "/checkin/calendar", iox.NewJSONReader(web.CalendarReq{
+		Year:  yesterday.Year(),
+		Month: int(yesterday.Month()),
+	}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[web.Calendar]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	assert.Equal(s.T(), web.Calendar{
+		Year:   yesterday.Year(),
+		Month:  int(yesterday.Month()),
+		Days:   []int{yesterday.Day()},
+		Streak: 1,
+	}, recorder.MustScan().Data)
+}
+
+func (s *HandlerTestSuite) createCheckIns(t *testing.T, days ...time.Time) {
+	for _, d := range days {
+		err := s.db.Create(&dao.CheckIn{
+			Uid:   uid,
+			Day:   domain.DayOf(d),
+			Ctime: d.UnixMilli(),
+			Utime: d.UnixMilli(),
+		}).Error
+		require.NoError(t, err)
+	}
+}
+
+func (s *HandlerTestSuite) key(t time.Time) string {
+	return fmt.Sprintf("webook:checkin:%d:%s", uid, t.Format("200601"))
+}
+
+func TestCheckInHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
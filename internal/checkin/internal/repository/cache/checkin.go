@@ -0,0 +1,96 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrKeyNotExist = errors.New("签到位图不存在")
+
+// CheckInCache 使用位图记录用户每个月的签到情况
+// 第 0 位是标记位，用于区分"位图不存在"和"该月没有签到"，第 1 - 31 位分别对应每月的 1 - 31 号
+type CheckInCache interface {
+	// SetDay 标记某天已签到
+	SetDay(ctx context.Context, uid int64, day time.Time) error
+	// MonthDays 获取某个月已签到的日期，位图不存在时返回 ErrKeyNotExist
+	MonthDays(ctx context.Context, uid int64, year, month int) ([]int, error)
+	// SetMonthDays 重建某个月的位图
+	SetMonthDays(ctx context.Context, uid int64, year, month int, days []int) error
+}
+
+type checkInRedisCache struct {
+	cmd        redis.Cmdable
+	expiration time.Duration
+}
+
+func NewCheckInRedisCache(cmd redis.Cmdable) CheckInCache {
+	return &checkInRedisCache{
+		cmd: cmd,
+		// 保证上个月的位图在计算连续签到的时候大概率还在
+		expiration: time.Hour * 24 * 62,
+	}
+}
+
+func (c *checkInRedisCache) SetDay(ctx context.Context, uid int64, day time.Time) error {
+	// 位图不存在的时候这里只会设置对应日期的位，标记位依旧为 0，
+	// 所以读取的时候会被认为位图不存在，从而触发重建
+	key := c.key(uid, day.Year(), int(day.Month()))
+	pipe := c.cmd.TxPipeline()
+	pipe.SetBit(ctx, key, int64(day.Day()), 1)
+	pipe.Expire(ctx, key, c.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *checkInRedisCache) MonthDays(ctx context.Context, uid int64, year, month int) ([]int, error) {
+	const bits = 32
+	vals, err := c.cmd.BitField(ctx, c.key(uid, year, month), "GET", fmt.Sprintf("u%d", bits), 0).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(vals) == 0 || (vals[0]>>(bits-1))&1 == 0 {
+		return nil, ErrKeyNotExist
+	}
+	days := make([]int, 0, bits-1)
+	for d := 1; d < bits; d++ {
+		if (vals[0]>>(bits-1-d))&1 == 1 {
+			days = append(days, d)
+		}
+	}
+	return days, nil
+}
+
+func (c *checkInRedisCache) SetMonthDays(ctx context.Context, uid int64, year, month int, days []int) error {
+	key := c.key(uid, year, month)
+	pipe := c.cmd.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.SetBit(ctx, key, 0, 1)
+	for _, d := range days {
+		pipe.SetBit(ctx, key, int64(d), 1)
+	}
+	pipe.Expire(ctx, key, c.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *checkInRedisCache) key(uid int64, year, month int) string {
+	return fmt.Sprintf("webook:checkin:%d:%04d%02d", uid, year, month)
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/checkin/internal/domain"
+	"github.com/ecodeclub/webook/internal/checkin/internal/repository/cache"
+	"github.com/ecodeclub/webook/internal/checkin/internal/repository/dao"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+var ErrDuplicateCheckIn = dao.ErrDuplicateCheckIn
+
+type CheckInRepository interface {
+	Create(ctx context.Context, c domain.CheckIn) (int64, error)
+	// MonthDays 某个月已签到的日期
+	MonthDays(ctx context.Context, uid int64, year, month int) ([]int, error)
+}
+
+type cachedCheckInRepository struct {
+	dao    dao.CheckInDAO
+	cache  cache.CheckInCache
+	logger *elog.Component
+}
+
+func NewCachedCheckInRepository(d dao.CheckInDAO, c cache.CheckInCache) CheckInRepository {
+	return &cachedCheckInRepository{
+		dao:    d,
+		cache:  c,
+		logger: elog.DefaultLogger,
+	}
+}
+
+func (repo *cachedCheckInRepository) Create(ctx context.Context, c domain.CheckIn) (int64, error) {
+	id, err := repo.dao.Create(ctx, dao.CheckIn{
+		Uid:    c.Uid,
+		Day:    c.Day,
+		Streak: c.Streak,
+		Reward: c.Reward,
+	})
+	if err != nil {
+		return 0, err
+	}
+	day := time.Date(c.Day/10000, time.Month(c.Day/100%100), c.Day%100, 0, 0, 0, 0, time.Local)
+	if er := repo.cache.SetDay(ctx, c.Uid, day); er != nil {
+		// 位图没有更新成功的话，下一次读取的时候要从数据库重建
+		repo.logger.Error("更新签到位图失败",
+			elog.FieldErr(er),
+			elog.Int64("uid", c.Uid),
+			elog.Int("day", c.Day))
+	}
+	return id, nil
+}
+
+func (repo *cachedCheckInRepository) MonthDays(ctx context.Context, uid int64, year, month int) ([]int, error) {
+	days, err := repo.cache.MonthDays(ctx, uid, year, month)
+	if err == nil {
+		return days, nil
+	}
+	start := year*10000 + month*100
+	records, err := repo.dao.FindByDayRange(ctx, uid, start+1, start+31)
+	if err != nil {
+		return nil, err
+	}
+	days = make([]int, 0, len(records))
+	for _, r := range records {
+		days = append(days, r.Day%100)
+	}
+	if er := repo.cache.SetMonthDays(ctx, uid, year, month, days); er != nil {
+		repo.logger.Error("重建签到位图失败",
+			elog.FieldErr(er),
+			elog.Int64("uid", uid),
+			elog.Int("year", year),
+			elog.Int("month", month))
+	}
+	return days, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ego-component/egorm"
+	"github.com/go-sql-driver/mysql"
+)
+
+var ErrDuplicateCheckIn = errors.New("重复签到")
+
+type CheckInDAO interface {
+	Create(ctx context.Context, c CheckIn) (int64, error)
+	// FindByDayRange 查找 [start, end] 之间的签到记录，按照日期升序排列
+	FindByDayRange(ctx context.Context, uid int64, start, end int) ([]CheckIn, error)
+}
+
+type checkInGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewCheckInGORMDAO(db *egorm.Component) CheckInDAO {
+	return &checkInGORMDAO{db: db}
+}
+
+func (dao *checkInGORMDAO) Create(ctx context.Context, c CheckIn) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime, c.Utime = now, now
+	err := dao.db.WithContext(ctx).Create(&c).Error
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const uniqueIndexErrNo uint16 = 1062
+		if me.Number == uniqueIndexErrNo {
+			return 0, ErrDuplicateCheckIn
+		}
+	}
+	return c.Id, err
+}
+
+func (dao *checkInGORMDAO) FindByDayRange(ctx context.Context, uid int64, start, end int) ([]CheckIn, error) {
+	var res []CheckIn
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND day BETWEEN ? AND ?", uid, start, end).
+		Order("day ASC").
+		Find(&res).Error
+	return res, err
+}
+
+// CheckIn 签到记录，每个用户每天只有一条
+type CheckIn struct {
+	Id     int64  `gorm:"primaryKey,autoIncrement"`
+	Uid    int64  `gorm:"not null;uniqueIndex:idx_uid_day;comment:用户ID"`
+	Day    int    `gorm:"not null;uniqueIndex:idx_uid_day;comment:签到日期,形如20240501"`
+	Streak int    `gorm:"not null;comment:截止到本次签到的连续签到天数"`
+	Reward uint64 `gorm:"not null;default:0;comment:本次签到获得的积分"`
+	Ctime  int64
+	Utime  int64
+}
@@ -0,0 +1,158 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/checkin/internal/domain"
+	"github.com/ecodeclub/webook/internal/checkin/internal/event"
+	"github.com/ecodeclub/webook/internal/checkin/internal/repository"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+var ErrAlreadyCheckedIn = errors.New("今日已签到")
+
+// 计算连续签到天数时最多往前回溯的月份
+const maxStreakMonths = 12
+
+type Service interface {
+	// CheckIn 今日签到，返回本次签到记录
+	CheckIn(ctx context.Context, uid int64) (domain.CheckIn, error)
+	// Calendar 某个月的签到日历
+	Calendar(ctx context.Context, uid int64, year, month int) (domain.Calendar, error)
+}
+
+type service struct {
+	repo     repository.CheckInRepository
+	producer event.IncreaseCreditsEventProducer
+	cfg      domain.RewardConfig
+	logger   *elog.Component
+}
+
+func NewService(repo repository.CheckInRepository,
+	producer event.IncreaseCreditsEventProducer,
+	cfg domain.RewardConfig) Service {
+	return &service{
+		repo:     repo,
+		producer: producer,
+		cfg:      cfg,
+		logger:   elog.DefaultLogger,
+	}
+}
+
+func (s *service) CheckIn(ctx context.Context, uid int64) (domain.CheckIn, error) {
+	now := time.Now()
+	days, err := s.repo.MonthDays(ctx, uid, now.Year(), int(now.Month()))
+	if err != nil {
+		return domain.CheckIn{}, err
+	}
+	if slice.Contains(days, now.Day()) {
+		return domain.CheckIn{}, ErrAlreadyCheckedIn
+	}
+	streak, err := s.streak(ctx, uid, now.AddDate(0, 0, -1))
+	if err != nil {
+		return domain.CheckIn{}, err
+	}
+	c := domain.CheckIn{
+		Uid:    uid,
+		Day:    domain.DayOf(now),
+		Streak: streak + 1,
+	}
+	c.Reward = s.cfg.Reward(c.Streak)
+	c.ID, err = s.repo.Create(ctx, c)
+	if errors.Is(err, repository.ErrDuplicateCheckIn) {
+		return domain.CheckIn{}, ErrAlreadyCheckedIn
+	}
+	if err != nil {
+		return domain.CheckIn{}, err
+	}
+	if c.Reward > 0 {
+		evt := event.CreditIncreaseEvent{
+			// 同一天只会奖励一次
+			Key:    fmt.Sprintf("checkin:%d:%d", uid, c.Day),
+			Uid:    uid,
+			Amount: c.Reward,
+			Biz:    10,
+			BizId:  c.ID,
+			Action: "每日签到",
+		}
+		if er := s.producer.Produce(ctx, evt); er != nil {
+			s.logger.Error("发送签到奖励消息失败",
+				elog.FieldErr(er),
+				elog.Any("event", evt),
+			)
+		}
+	}
+	return c, nil
+}
+
+func (s *service) Calendar(ctx context.Context, uid int64, year, month int) (domain.Calendar, error) {
+	days, err := s.repo.MonthDays(ctx, uid, year, month)
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+	now := time.Now()
+	todayDays := days
+	if year != now.Year() || month != int(now.Month()) {
+		todayDays, err = s.repo.MonthDays(ctx, uid, now.Year(), int(now.Month()))
+		if err != nil {
+			return domain.Calendar{}, err
+		}
+	}
+	checkedInToday := slice.Contains(todayDays, now.Day())
+	// 今天还没签到的话，连续签到天数算到昨天为止
+	from := now
+	if !checkedInToday {
+		from = now.AddDate(0, 0, -1)
+	}
+	streak, err := s.streak(ctx, uid, from)
+	if err != nil {
+		return domain.Calendar{}, err
+	}
+	return domain.Calendar{
+		Year:           year,
+		Month:          month,
+		Days:           days,
+		Streak:         streak,
+		CheckedInToday: checkedInToday,
+	}, nil
+}
+
+// streak 计算截止到 from 这一天的连续签到天数
+func (s *service) streak(ctx context.Context, uid int64, from time.Time) (int, error) {
+	res := 0
+	cur := from
+	for i := 0; i < maxStreakMonths; i++ {
+		days, err := s.repo.MonthDays(ctx, uid, cur.Year(), int(cur.Month()))
+		if err != nil {
+			return 0, err
+		}
+		d := cur.Day()
+		for ; d > 0 && slice.Contains(days, d); d-- {
+			res++
+		}
+		if d > 0 {
+			return res, nil
+		}
+		// 一直连续到了这个月的第一天，继续看上个月的最后一天
+		cur = time.Date(cur.Year(), cur.Month(), 0, 0, 0, 0, 0, time.Local)
+	}
+	return res, nil
+}
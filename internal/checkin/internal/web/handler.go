@@ -0,0 +1,76 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/checkin/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc service.Service
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) PrivateRoutes(server *gin.Engine) {
+	g := server.Group("/checkin")
+	g.POST("", ginx.S(h.CheckIn))
+	g.POST("/calendar", ginx.BS[CalendarReq](h.Calendar))
+}
+
+// CheckIn 今日签到
+func (h *Handler) CheckIn(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	c, err := h.svc.CheckIn(ctx, sess.Claims().Uid)
+	switch {
+	case errors.Is(err, service.ErrAlreadyCheckedIn):
+		return alreadyCheckedInResult, nil
+	case err != nil:
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: CheckInResp{
+			Day:    c.Day,
+			Streak: c.Streak,
+			Reward: c.Reward,
+		},
+	}, nil
+}
+
+// Calendar 某个月的签到日历
+func (h *Handler) Calendar(ctx *ginx.Context, req CalendarReq, sess session.Session) (ginx.Result, error) {
+	if req.Year == 0 || req.Month == 0 {
+		now := time.Now()
+		req.Year, req.Month = now.Year(), int(now.Month())
+	}
+	if req.Month < 1 || req.Month > 12 {
+		return systemErrorResult, fmt.Errorf("月份非法 %d", req.Month)
+	}
+	c, err := h.svc.Calendar(ctx, sess.Claims().Uid, req.Year, req.Month)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: newCalendar(c),
+	}, nil
+}
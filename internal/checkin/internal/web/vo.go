@@ -0,0 +1,48 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import "github.com/ecodeclub/webook/internal/checkin/internal/domain"
+
+type CheckInResp struct {
+	// Day 签到日期，形如 20240501
+	Day    int    `json:"day"`
+	Streak int    `json:"streak"`
+	Reward uint64 `json:"reward"`
+}
+
+type CalendarReq struct {
+	// 不传的时候默认为当前年月
+	Year  int `json:"year,omitempty"`
+	Month int `json:"month,omitempty"`
+}
+
+type Calendar struct {
+	Year           int   `json:"year"`
+	Month          int   `json:"month"`
+	Days           []int `json:"days"`
+	Streak         int   `json:"streak"`
+	CheckedInToday bool  `json:"checkedInToday"`
+}
+
+func newCalendar(c domain.Calendar) Calendar {
+	return Calendar{
+		Year:           c.Year,
+		Month:          c.Month,
+		Days:           c.Days,
+		Streak:         c.Streak,
+		CheckedInToday: c.CheckedInToday,
+	}
+}
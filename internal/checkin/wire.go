@@ -0,0 +1,106 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package checkin
+
+import (
+	"sync"
+
+	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/checkin/internal/domain"
+	"github.com/ecodeclub/webook/internal/checkin/internal/event"
+	"github.com/ecodeclub/webook/internal/checkin/internal/repository"
+	"github.com/ecodeclub/webook/internal/checkin/internal/repository/cache"
+	"github.com/ecodeclub/webook/internal/checkin/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/checkin/internal/service"
+	"github.com/ecodeclub/webook/internal/checkin/internal/web"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitHandler(db *egorm.Component, cmd redis.Cmdable, q mq.MQ) (*Handler, error) {
+	wire.Build(
+		initIncreaseCreditsEventProducer,
+		initRewardConfig,
+		InitService,
+		web.NewHandler,
+	)
+	return new(Handler), nil
+}
+
+func InitService(db *egorm.Component, cmd redis.Cmdable,
+	p event.IncreaseCreditsEventProducer, cfg domain.RewardConfig) service.Service {
+	wire.Build(
+		initCheckInDAO,
+		cache.NewCheckInRedisCache,
+		repository.NewCachedCheckInRepository,
+		service.NewService,
+	)
+	return nil
+}
+
+var (
+	daoOnce = sync.Once{}
+	d       dao.CheckInDAO
+)
+
+func initCheckInDAO(db *egorm.Component) dao.CheckInDAO {
+	daoOnce.Do(func() {
+		_ = dao.InitTables(db)
+		d = dao.NewCheckInGORMDAO(db)
+	})
+	return d
+}
+
+func initIncreaseCreditsEventProducer(q mq.MQ) event.IncreaseCreditsEventProducer {
+	producer, err := event.NewIncreaseCreditsEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
+}
+
+// initRewardConfig 没有配置的时候，每次签到奖励 1 积分，连续签到 7 天和 30 天有额外奖励
+func initRewardConfig() domain.RewardConfig {
+	type Rule struct {
+		Streak int    `yaml:"streak"`
+		Amount uint64 `yaml:"amount"`
+	}
+	type Config struct {
+		Base  uint64 `yaml:"base"`
+		Rules []Rule `yaml:"rules"`
+	}
+	cfg := Config{
+		Base: 1,
+		Rules: []Rule{
+			{Streak: 7, Amount: 10},
+			{Streak: 30, Amount: 50},
+		},
+	}
+	err := econf.UnmarshalKey("checkin", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	rules := make([]domain.RewardRule, 0, len(cfg.Rules))
+	for _, r := range cfg.Rules {
+		rules = append(rules, domain.RewardRule{Streak: r.Streak, Amount: r.Amount})
+	}
+	return domain.RewardConfig{Base: cfg.Base, Rules: rules}
+}
+
+type Handler = web.Handler
@@ -0,0 +1,129 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	BizQuestion    = "question"
+	BizCase        = "case"
+	BizSkill       = "skill"
+	BizQuestionSet = "questionSet"
+
+	// MaxContentLen 单条评论的字数上限
+	MaxContentLen = 1000
+)
+
+// ValidBiz 目前只有这几类内容下面可以评论
+func ValidBiz(biz string) bool {
+	switch biz {
+	case BizQuestion, BizCase, BizSkill, BizQuestionSet:
+		return true
+	default:
+		return false
+	}
+}
+
+type Status uint8
+
+func (s Status) ToUint8() uint8 {
+	return uint8(s)
+}
+
+const (
+	StatusUnknown Status = iota
+	StatusVisible
+	// StatusHidden 被创作者隐藏了，不再展示也不计入评论数
+	StatusHidden
+)
+
+type SortBy uint8
+
+const (
+	SortByTime SortBy = iota
+	SortByLikes
+)
+
+// Comment 评论分成两层，RootId 为 0 的是直接评论在内容下面的，
+// 其余的都是回复，统一挂在 RootId 下面，ParentId 是被回复的那一条
+type Comment struct {
+	Id       int64
+	Uid      int64
+	Biz      string
+	BizId    int64
+	RootId   int64
+	ParentId int64
+	Content  string
+	Status   Status
+	LikeCnt  int64
+	// ReplyCnt 只有根评论上才有
+	ReplyCnt int64
+	Ctime    time.Time
+	Utime    time.Time
+}
+
+func (c Comment) IsRoot() bool {
+	return c.RootId == 0
+}
+
+// Cursor 游标分页，零值表示从头开始。
+// 按照时间排序的时候只用到 Id，按照点赞数排序的时候用 LikeCnt 加上 Id
+type Cursor struct {
+	LikeCnt int64
+	Id      int64
+}
+
+func NewCursor(c Comment) Cursor {
+	return Cursor{LikeCnt: c.LikeCnt, Id: c.Id}
+}
+
+func (c Cursor) IsZero() bool {
+	return c.Id == 0
+}
+
+func (c Cursor) String() string {
+	if c.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%d_%d", c.LikeCnt, c.Id)
+}
+
+func ParseCursor(s string) (Cursor, error) {
+	if s == "" {
+		return Cursor{}, nil
+	}
+	likeCnt, id, ok := strings.Cut(s, "_")
+	if !ok {
+		return Cursor{}, fmt.Errorf("非法的游标 %s", s)
+	}
+	var (
+		c   Cursor
+		err error
+	)
+	c.LikeCnt, err = strconv.ParseInt(likeCnt, 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("非法的游标 %s: %w", s, err)
+	}
+	c.Id, err = strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return Cursor{}, fmt.Errorf("非法的游标 %s: %w", s, err)
+	}
+	return c, nil
+}
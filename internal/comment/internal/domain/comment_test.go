@@ -0,0 +1,61 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor(t *testing.T) {
+	testCases := []struct {
+		name    string
+		cursor  Cursor
+		wantStr string
+	}{
+		{
+			name:    "从头开始",
+			wantStr: "",
+		},
+		{
+			name:    "没有点赞",
+			cursor:  Cursor{Id: 12},
+			wantStr: "0_12",
+		},
+		{
+			name:    "有点赞",
+			cursor:  Cursor{LikeCnt: 3, Id: 12},
+			wantStr: "3_12",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s := tc.cursor.String()
+			assert.Equal(t, tc.wantStr, s)
+			c, err := ParseCursor(s)
+			require.NoError(t, err)
+			assert.Equal(t, tc.cursor, c)
+		})
+	}
+}
+
+func TestParseCursor_Invalid(t *testing.T) {
+	for _, s := range []string{"12", "a_1", "1_b"} {
+		_, err := ParseCursor(s)
+		assert.Error(t, err, s)
+	}
+}
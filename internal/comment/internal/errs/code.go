@@ -0,0 +1,14 @@
+package errs
+
+var (
+	SystemError      = ErrorCode{Code: 518001, Msg: "系统错误"}
+	InvalidComment   = ErrorCode{Code: 518002, Msg: "评论内容非法"}
+	SensitiveContent = ErrorCode{Code: 518003, Msg: "评论包含敏感词"}
+	CommentNotFound  = ErrorCode{Code: 518004, Msg: "评论不存在"}
+	PermissionDenied = ErrorCode{Code: 518005, Msg: "只能删除自己的评论"}
+)
+
+type ErrorCode struct {
+	Code int
+	Msg  string
+}
@@ -0,0 +1,438 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/comment"
+	"github.com/ecodeclub/webook/internal/comment/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/comment/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/comment/internal/sensitive"
+	"github.com/ecodeclub/webook/internal/comment/internal/web"
+	commentmocks "github.com/ecodeclub/webook/internal/comment/mocks"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const (
+	uid = 6001
+	// notFoundBizId 被评论的内容不存在或者还没有发布
+	notFoundBizId = 404
+)
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server *egin.Component
+	db     *egorm.Component
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	contentSvc := commentmocks.NewMockContentService(ctrl)
+	contentSvc.EXPECT().Exists(gomock.Any(), gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, biz string, bizId int64) (bool, error) {
+			return bizId != notFoundBizId, nil
+		}).AnyTimes()
+	module := startup.InitModule(sensitive.NewWordFilter([]string{"广告"}), contentSvc)
+
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	module.Hdl.PublicRoutes(server.Engine)
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid:  uid,
+			Data: map[string]string{"creator": "true"},
+		}))
+	})
+	module.Hdl.PrivateRoutes(server.Engine)
+	s.server = server
+	s.db = testioc.InitDB()
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	for _, table := range []string{"comments", "comment_counts", "comment_likes"} {
+		err := s.db.Exec("TRUNCATE TABLE `" + table + "`").Error
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *HandlerTestSuite) TearDownSuite() {
+	for _, table := range []string{"comments", "comment_counts", "comment_likes"} {
+		err := s.db.Exec("DROP TABLE `" + table + "`").Error
+		require.NoError(s.T(), err)
+	}
+}
+
+func (s *HandlerTestSuite) TestCreate() {
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		after  func(t *testing.T, id int64)
+		req    web.CreateReq
+	}{
+		{
+			name:   "评论题目",
+			before: func(t *testing.T) {},
+			after: func(t *testing.T, id int64) {
+				c := s.findComment(t, id)
+				assert.Equal(t, int64(0), c.RootId)
+				assert.Equal(t, "写得不错", c.Content)
+				assert.Equal(t, int64(1), s.count(t, comment.BizQuestion, 1))
+			},
+			req: web.CreateReq{Biz: comment.BizQuestion, BizId: 1, Content: "写得不错"},
+		},
+		{
+			name: "回复根评论",
+			before: func(t *testing.T) {
+				s.createComment(t, dao.Comment{Id: 10, Uid: uid + 1, Biz: comment.BizCase, BizId: 2})
+			},
+			after: func(t *testing.T, id int64) {
+				c := s.findComment(t, id)
+				assert.Equal(t, int64(10), c.RootId)
+				assert.Equal(t, int64(10), c.ParentId)
+				assert.Equal(t, int64(1), s.findComment(t, 10).ReplyCnt)
+				assert.Equal(t, int64(1), s.count(t, comment.BizCase, 2))
+			},
+			req: web.CreateReq{Biz: comment.BizCase, BizId: 2, ParentId: 10, Content: "同意"},
+		},
+		{
+			name: "回复别人的回复",
+			before: func(t *testing.T) {
+				s.createComment(t, dao.Comment{Id: 20, Uid: uid + 1, Biz: comment.BizSkill, BizId: 3})
+				s.createComment(t, dao.Comment{Id: 21, Uid: uid + 2, Biz: comment.BizSkill, BizId: 3,
+					RootId: 20, ParentId: 20})
+			},
+			after: func(t *testing.T, id int64) {
+				c := s.findComment(t, id)
+				assert.Equal(t, int64(20), c.RootId)
+				assert.Equal(t, int64(21), c.ParentId)
+			},
+			req: web.CreateReq{Biz: comment.BizSkill, BizId: 3, ParentId: 21, Content: "不同意"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			res := post[int64](t, s.server, "/comment/create", tc.req)
+			require.Equal(t, 0, res.Code)
+			tc.after(t, res.Data)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestCreateFailed() {
+	s.createComment(s.T(), dao.Comment{Id: 1, Uid: uid, Biz: comment.BizQuestion, BizId: 1})
+	s.createComment(s.T(), dao.Comment{Id: 2, Uid: uid, Biz: comment.BizQuestion, BizId: 1, Status: 2})
+	s.createComment(s.T(), dao.Comment{Id: 3, Uid: uid, Biz: comment.BizQuestion, BizId: 1, RootId: 2, ParentId: 2})
+	testCases := []struct {
+		name     string
+		req      web.CreateReq
+		wantCode int
+	}{
+		{
+			name:     "内容为空",
+			req:      web.CreateReq{Biz: comment.BizQuestion, BizId: 1, Content: " "},
+			wantCode: 518002,
+		},
+		{
+			name:     "未知的业务",
+			req:      web.CreateReq{Biz: "order", BizId: 1, Content: "评论"},
+			wantCode: 518002,
+		},
+		{
+			name:     "被评论的内容不存在",
+			req:      web.CreateReq{Biz: comment.BizQuestion, BizId: notFoundBizId, Content: "评论"},
+			wantCode: 518002,
+		},
+		{
+			name:     "包含敏感词",
+			req:      web.CreateReq{Biz: comment.BizQuestion, BizId: 1, Content: "这里有广告"},
+			wantCode: 518003,
+		},
+		{
+			name:     "被回复的评论不存在",
+			req:      web.CreateReq{Biz: comment.BizQuestion, BizId: 1, ParentId: 100, Content: "回复"},
+			wantCode: 518004,
+		},
+		{
+			name:     "被回复的评论已经隐藏",
+			req:      web.CreateReq{Biz: comment.BizQuestion, BizId: 1, ParentId: 2, Content: "回复"},
+			wantCode: 518004,
+		},
+		{
+			name:     "根评论已经隐藏",
+			req:      web.CreateReq{Biz: comment.BizQuestion, BizId: 1, ParentId: 3, Content: "回复"},
+			wantCode: 518004,
+		},
+		{
+			name:     "被回复的评论不在同一个内容下",
+			req:      web.CreateReq{Biz: comment.BizQuestion, BizId: 2, ParentId: 1, Content: "回复"},
+			wantCode: 518002,
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			res := post[int64](t, s.server, "/comment/create", tc.req)
+			assert.Equal(t, tc.wantCode, res.Code)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestList() {
+	t := s.T()
+	likes := []int64{3, 5, 3, 0, 5}
+	for idx, cnt := range likes {
+		s.createComment(t, dao.Comment{Id: int64(idx + 1), Uid: uid, Biz: comment.BizQuestion, BizId: 1, LikeCnt: cnt})
+	}
+	// 回复、隐藏的评论和别的题目下的评论都不在列表里面
+	s.createComment(t, dao.Comment{Id: 6, Uid: uid, Biz: comment.BizQuestion, BizId: 1, RootId: 1, ParentId: 1})
+	s.createComment(t, dao.Comment{Id: 7, Uid: uid, Biz: comment.BizQuestion, BizId: 1, Status: 2})
+	s.createComment(t, dao.Comment{Id: 8, Uid: uid, Biz: comment.BizQuestion, BizId: 2})
+
+	testCases := []struct {
+		name    string
+		sort    string
+		wantIds [][]int64
+	}{
+		{
+			name:    "按照时间",
+			wantIds: [][]int64{{5, 4}, {3, 2}, {1}},
+		},
+		{
+			name:    "按照点赞数",
+			sort:    "likes",
+			wantIds: [][]int64{{5, 2}, {3, 1}, {4}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cursor string
+			for _, want := range tc.wantIds {
+				res := post[web.CommentList](t, s.server, "/comment/list", web.ListReq{
+					Biz: comment.BizQuestion, BizId: 1, Sort: tc.sort, Cursor: cursor, Limit: 2,
+				})
+				require.Equal(t, 0, res.Code)
+				assert.Equal(t, want, ids(res.Data.Comments))
+				cursor = res.Data.Next
+			}
+			assert.Empty(t, cursor)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestReplies() {
+	t := s.T()
+	s.createComment(t, dao.Comment{Id: 1, Uid: uid, Biz: comment.BizCase, BizId: 1})
+	for id := int64(2); id <= 4; id++ {
+		s.createComment(t, dao.Comment{Id: id, Uid: uid, Biz: comment.BizCase, BizId: 1, RootId: 1, ParentId: 1})
+	}
+	s.createComment(t, dao.Comment{Id: 5, Uid: uid, Biz: comment.BizCase, BizId: 1, RootId: 1, ParentId: 2, Status: 2})
+
+	res := post[web.CommentList](t, s.server, "/comment/replies", web.RepliesReq{RootId: 1, Limit: 2})
+	assert.Equal(t, []int64{2, 3}, ids(res.Data.Comments))
+	res = post[web.CommentList](t, s.server, "/comment/replies",
+		web.RepliesReq{RootId: 1, Cursor: res.Data.Next, Limit: 2})
+	assert.Equal(t, []int64{4}, ids(res.Data.Comments))
+	assert.Empty(t, res.Data.Next)
+
+	// 根评论隐藏之后，回复也看不到
+	s.createComment(t, dao.Comment{Id: 6, Uid: uid, Biz: comment.BizCase, BizId: 1, Status: 2})
+	s.createComment(t, dao.Comment{Id: 7, Uid: uid, Biz: comment.BizCase, BizId: 1, RootId: 6, ParentId: 6})
+	res = post[web.CommentList](t, s.server, "/comment/replies", web.RepliesReq{RootId: 6, Limit: 2})
+	assert.Equal(t, 518004, res.Code)
+}
+
+func (s *HandlerTestSuite) TestDelete() {
+	t := s.T()
+	s.createComment(t, dao.Comment{Id: 1, Uid: uid, Biz: comment.BizQuestion, BizId: 1})
+	s.createComment(t, dao.Comment{Id: 2, Uid: uid + 1, Biz: comment.BizQuestion, BizId: 1, RootId: 1, ParentId: 1})
+	s.createComment(t, dao.Comment{Id: 3, Uid: uid + 1, Biz: comment.BizQuestion, BizId: 1, RootId: 1, ParentId: 1, Status: 2})
+	s.createComment(t, dao.Comment{Id: 4, Uid: uid + 1, Biz: comment.BizQuestion, BizId: 1, ReplyCnt: 1})
+	s.createComment(t, dao.Comment{Id: 5, Uid: uid, Biz: comment.BizQuestion, BizId: 1, RootId: 4, ParentId: 4})
+	s.setCount(t, comment.BizQuestion, 1, 4)
+
+	// 不能删除别人的
+	res := post[any](t, s.server, "/comment/delete", web.IdReq{Id: 4})
+	assert.Equal(t, 518005, res.Code)
+
+	// 删除自己的回复
+	res = post[any](t, s.server, "/comment/delete", web.IdReq{Id: 5})
+	assert.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(0), s.findComment(t, 4).ReplyCnt)
+	assert.Equal(t, int64(3), s.count(t, comment.BizQuestion, 1))
+
+	// 删除根评论，回复一起删掉，隐藏的回复不影响评论数
+	res = post[any](t, s.server, "/comment/delete", web.IdReq{Id: 1})
+	assert.Equal(t, 0, res.Code)
+	var cnt int64
+	err := s.db.Model(&dao.Comment{}).Where("id IN ?", []int64{1, 2, 3}).Count(&cnt).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+	assert.Equal(t, int64(1), s.count(t, comment.BizQuestion, 1))
+
+	res = post[any](t, s.server, "/comment/delete", web.IdReq{Id: 1})
+	assert.Equal(t, 518004, res.Code)
+}
+
+func (s *HandlerTestSuite) TestLike() {
+	t := s.T()
+	s.createComment(t, dao.Comment{Id: 1, Uid: uid + 1, Biz: comment.BizQuestion, BizId: 1})
+
+	for i := 0; i < 2; i++ {
+		res := post[any](t, s.server, "/comment/like", web.LikeReq{Id: 1, Like: true})
+		require.Equal(t, 0, res.Code)
+	}
+	assert.Equal(t, int64(1), s.findComment(t, 1).LikeCnt)
+
+	for i := 0; i < 2; i++ {
+		res := post[any](t, s.server, "/comment/like", web.LikeReq{Id: 1})
+		require.Equal(t, 0, res.Code)
+	}
+	assert.Equal(t, int64(0), s.findComment(t, 1).LikeCnt)
+
+	res := post[any](t, s.server, "/comment/like", web.LikeReq{Id: 100, Like: true})
+	assert.Equal(t, 518004, res.Code)
+}
+
+func (s *HandlerTestSuite) TestModerate() {
+	t := s.T()
+	s.createComment(t, dao.Comment{Id: 1, Uid: uid + 1, Biz: comment.BizCase, BizId: 1, ReplyCnt: 1})
+	s.createComment(t, dao.Comment{Id: 2, Uid: uid + 1, Biz: comment.BizCase, BizId: 1, RootId: 1, ParentId: 1})
+	s.createComment(t, dao.Comment{Id: 3, Uid: uid + 2, Biz: comment.BizCase, BizId: 1})
+	s.setCount(t, comment.BizCase, 1, 3)
+
+	// 重复隐藏只扣减一次
+	for i := 0; i < 2; i++ {
+		res := post[any](t, s.server, "/comment/moderate/hide", web.HideReq{Id: 2, Hidden: true})
+		require.Equal(t, 0, res.Code)
+	}
+	assert.Equal(t, int64(0), s.findComment(t, 1).ReplyCnt)
+	assert.Equal(t, int64(2), s.count(t, comment.BizCase, 1))
+
+	res := post[any](t, s.server, "/comment/moderate/hide", web.HideReq{Id: 2})
+	require.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(1), s.findComment(t, 1).ReplyCnt)
+	assert.Equal(t, int64(3), s.count(t, comment.BizCase, 1))
+
+	// 创作者可以删除别人的评论
+	res = post[any](t, s.server, "/comment/moderate/delete", web.IdReq{Id: 3})
+	require.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(2), s.count(t, comment.BizCase, 1))
+
+	// 隐藏根评论，下面的回复也一起扣减，恢复的时候一起加回来
+	res = post[any](t, s.server, "/comment/moderate/hide", web.HideReq{Id: 1, Hidden: true})
+	require.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(0), s.count(t, comment.BizCase, 1))
+
+	// 根评论隐藏的时候，回复的变化不影响评论数
+	res = post[any](t, s.server, "/comment/moderate/hide", web.HideReq{Id: 2, Hidden: true})
+	require.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(0), s.findComment(t, 1).ReplyCnt)
+	assert.Equal(t, int64(0), s.count(t, comment.BizCase, 1))
+	res = post[any](t, s.server, "/comment/moderate/hide", web.HideReq{Id: 2})
+	require.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(1), s.findComment(t, 1).ReplyCnt)
+	assert.Equal(t, int64(0), s.count(t, comment.BizCase, 1))
+
+	res = post[any](t, s.server, "/comment/moderate/hide", web.HideReq{Id: 1})
+	require.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(2), s.count(t, comment.BizCase, 1))
+
+	// 删除隐藏的根评论不影响评论数
+	res = post[any](t, s.server, "/comment/moderate/hide", web.HideReq{Id: 1, Hidden: true})
+	require.Equal(t, 0, res.Code)
+	res = post[any](t, s.server, "/comment/moderate/delete", web.IdReq{Id: 1})
+	require.Equal(t, 0, res.Code)
+	assert.Equal(t, int64(0), s.count(t, comment.BizCase, 1))
+}
+
+func (s *HandlerTestSuite) TestCount() {
+	t := s.T()
+	s.setCount(t, comment.BizQuestion, 1, 3)
+	s.setCount(t, comment.BizCase, 2, 5)
+	res := post[[]web.Count](t, s.server, "/comment/count",
+		web.CountReq{Biz: comment.BizQuestion, BizIds: []int64{2, 1}})
+	assert.Equal(t, []web.Count{{BizId: 2}, {BizId: 1, Cnt: 3}}, res.Data)
+}
+
+// createComment 默认是可见的
+func (s *HandlerTestSuite) createComment(t *testing.T, c dao.Comment) {
+	if c.Status == 0 {
+		c.Status = 1
+	}
+	if c.Content == "" {
+		c.Content = "评论" + strconv.FormatInt(c.Id, 10)
+	}
+	now := time.Now().UnixMilli()
+	c.Ctime, c.Utime = now, now
+	require.NoError(t, s.db.Create(&c).Error)
+}
+
+func (s *HandlerTestSuite) findComment(t *testing.T, id int64) dao.Comment {
+	var c dao.Comment
+	require.NoError(t, s.db.Where("id = ?", id).First(&c).Error)
+	return c
+}
+
+func (s *HandlerTestSuite) setCount(t *testing.T, biz string, bizId int64, cnt int64) {
+	require.NoError(t, s.db.Create(&dao.CommentCount{Biz: biz, BizId: bizId, Cnt: cnt}).Error)
+}
+
+func (s *HandlerTestSuite) count(t *testing.T, biz string, bizId int64) int64 {
+	var c dao.CommentCount
+	require.NoError(t, s.db.Where("biz = ? AND biz_id = ?", biz, bizId).First(&c).Error)
+	return c.Cnt
+}
+
+func post[T any](t *testing.T, server *egin.Component, path string, body any) test.Result[T] {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[T]()
+	server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan()
+}
+
+func ids(cs []web.Comment) []int64 {
+	res := make([]int64, 0, len(cs))
+	for _, c := range cs {
+		res = append(res, c.Id)
+	}
+	return res
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,22 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package startup
+
+import (
+	"github.com/ecodeclub/webook/internal/comment"
+	"github.com/ecodeclub/webook/internal/comment/internal/sensitive"
+	"github.com/ecodeclub/webook/internal/comment/internal/service"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+)
+
+// Injectors from wire.go:
+
+func InitModule(filter sensitive.Filter, contentSvc service.ContentService) *comment.Module {
+	db := testioc.InitDB()
+	module := comment.InitModuleWithFilter(db, filter, contentSvc)
+	return module
+}
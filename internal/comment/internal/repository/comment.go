@@ -0,0 +1,128 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/comment/internal/domain"
+	"github.com/ecodeclub/webook/internal/comment/internal/repository/dao"
+)
+
+type CommentRepository interface {
+	Create(ctx context.Context, c domain.Comment) (int64, error)
+	FindById(ctx context.Context, id int64) (domain.Comment, error)
+	Delete(ctx context.Context, c domain.Comment) error
+	UpdateStatus(ctx context.Context, c domain.Comment, from, to domain.Status) (bool, error)
+	ListRoots(ctx context.Context, biz string, bizId int64, sortBy domain.SortBy, cursor domain.Cursor, limit int) ([]domain.Comment, error)
+	ListReplies(ctx context.Context, rootId int64, afterId int64, limit int) ([]domain.Comment, error)
+	Like(ctx context.Context, uid, cid int64) error
+	Unlike(ctx context.Context, uid, cid int64) error
+	Counts(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error)
+}
+
+type commentRepository struct {
+	dao dao.CommentDAO
+}
+
+func NewCommentRepository(d dao.CommentDAO) CommentRepository {
+	return &commentRepository{dao: d}
+}
+
+func (repo *commentRepository) Create(ctx context.Context, c domain.Comment) (int64, error) {
+	return repo.dao.Insert(ctx, repo.toEntity(c))
+}
+
+func (repo *commentRepository) FindById(ctx context.Context, id int64) (domain.Comment, error) {
+	c, err := repo.dao.FindById(ctx, id)
+	return repo.toDomain(c), err
+}
+
+func (repo *commentRepository) Delete(ctx context.Context, c domain.Comment) error {
+	return repo.dao.Delete(ctx, repo.toEntity(c))
+}
+
+func (repo *commentRepository) UpdateStatus(ctx context.Context, c domain.Comment, from, to domain.Status) (bool, error) {
+	return repo.dao.UpdateStatus(ctx, repo.toEntity(c), from.ToUint8(), to.ToUint8())
+}
+
+func (repo *commentRepository) ListRoots(ctx context.Context, biz string, bizId int64,
+	sortBy domain.SortBy, cursor domain.Cursor, limit int) ([]domain.Comment, error) {
+	cs, err := repo.dao.ListRoots(ctx, biz, bizId, sortBy, cursor, limit)
+	return slice.Map(cs, func(idx int, src dao.Comment) domain.Comment {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *commentRepository) ListReplies(ctx context.Context, rootId int64, afterId int64, limit int) ([]domain.Comment, error) {
+	cs, err := repo.dao.ListReplies(ctx, rootId, afterId, limit)
+	return slice.Map(cs, func(idx int, src dao.Comment) domain.Comment {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *commentRepository) Like(ctx context.Context, uid, cid int64) error {
+	return repo.dao.Like(ctx, uid, cid)
+}
+
+func (repo *commentRepository) Unlike(ctx context.Context, uid, cid int64) error {
+	return repo.dao.Unlike(ctx, uid, cid)
+}
+
+func (repo *commentRepository) Counts(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	cnts, err := repo.dao.FindCounts(ctx, biz, bizIds)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[int64]int64, len(cnts))
+	for _, c := range cnts {
+		res[c.BizId] = c.Cnt
+	}
+	return res, nil
+}
+
+func (repo *commentRepository) toEntity(c domain.Comment) dao.Comment {
+	return dao.Comment{
+		Id:       c.Id,
+		Uid:      c.Uid,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		RootId:   c.RootId,
+		ParentId: c.ParentId,
+		Content:  c.Content,
+		Status:   c.Status.ToUint8(),
+		LikeCnt:  c.LikeCnt,
+		ReplyCnt: c.ReplyCnt,
+	}
+}
+
+func (repo *commentRepository) toDomain(c dao.Comment) domain.Comment {
+	return domain.Comment{
+		Id:       c.Id,
+		Uid:      c.Uid,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		RootId:   c.RootId,
+		ParentId: c.ParentId,
+		Content:  c.Content,
+		Status:   domain.Status(c.Status),
+		LikeCnt:  c.LikeCnt,
+		ReplyCnt: c.ReplyCnt,
+		Ctime:    time.UnixMilli(c.Ctime),
+		Utime:    time.UnixMilli(c.Utime),
+	}
+}
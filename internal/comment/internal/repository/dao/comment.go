@@ -0,0 +1,299 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/comment/internal/domain"
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CommentDAO interface {
+	// Insert 同时维护根评论的回复数和内容的评论数
+	Insert(ctx context.Context, c Comment) (int64, error)
+	FindById(ctx context.Context, id int64) (Comment, error)
+	// Delete 删除根评论的时候，下面的回复一起删掉
+	Delete(ctx context.Context, c Comment) error
+	// UpdateStatus 只有当前状态是 from 的时候才会更新，返回是否更新了
+	UpdateStatus(ctx context.Context, c Comment, from, to uint8) (bool, error)
+	// ListRoots 只返回可见的根评论，cursor 为零值的时候从头开始
+	ListRoots(ctx context.Context, biz string, bizId int64, sortBy domain.SortBy, cursor domain.Cursor, limit int) ([]Comment, error)
+	// ListReplies 按照回复的先后顺序，返回 id 大于 afterId 的可见回复
+	ListReplies(ctx context.Context, rootId int64, afterId int64, limit int) ([]Comment, error)
+	// Like 重复点赞或者重复取消都不会改变点赞数
+	Like(ctx context.Context, uid, cid int64) error
+	Unlike(ctx context.Context, uid, cid int64) error
+	FindCounts(ctx context.Context, biz string, bizIds []int64) ([]CommentCount, error)
+}
+
+type CommentGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewCommentGORMDAO(db *egorm.Component) CommentDAO {
+	return &CommentGORMDAO{db: db}
+}
+
+func (dao *CommentGORMDAO) Insert(ctx context.Context, c Comment) (int64, error) {
+	now := time.Now().UnixMilli()
+	c.Ctime, c.Utime = now, now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Create(&c).Error
+		if err != nil {
+			return err
+		}
+		return dao.incrCounts(tx, c, 1, now)
+	})
+	return c.Id, err
+}
+
+func (dao *CommentGORMDAO) FindById(ctx context.Context, id int64) (Comment, error) {
+	var res Comment
+	err := dao.db.WithContext(ctx).Where("id = ?", id).First(&res).Error
+	return res, err
+}
+
+func (dao *CommentGORMDAO) Delete(ctx context.Context, c Comment) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cs []Comment
+		db := tx.Select("id", "status")
+		if c.RootId == 0 {
+			db = db.Where("id = ? OR root_id = ?", c.Id, c.Id)
+		} else {
+			db = db.Where("id = ?", c.Id)
+		}
+		err := db.Find(&cs).Error
+		if err != nil || len(cs) == 0 {
+			return err
+		}
+		ids := make([]int64, 0, len(cs))
+		var visible int64
+		var hidden bool
+		for _, src := range cs {
+			ids = append(ids, src.Id)
+			if src.Status == domain.StatusVisible.ToUint8() {
+				visible++
+			} else if src.Id == c.Id && c.RootId == 0 {
+				// 隐藏的根评论和它下面的回复都已经不在评论数里面了
+				hidden = true
+			}
+		}
+		err = tx.Where("id IN ?", ids).Delete(&Comment{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("cid IN ?", ids).Delete(&CommentLike{}).Error
+		if err != nil {
+			return err
+		}
+		// 回复数和评论数只统计可见的
+		if visible == 0 || hidden {
+			return nil
+		}
+		if c.RootId > 0 {
+			return dao.incrCounts(tx, c, -1, now)
+		}
+		return dao.incrCount(tx, c.Biz, c.BizId, -visible, now)
+	})
+}
+
+func (dao *CommentGORMDAO) UpdateStatus(ctx context.Context, c Comment, from, to uint8) (bool, error) {
+	now := time.Now().UnixMilli()
+	var updated bool
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Comment{}).
+			Where("id = ? AND status = ?", c.Id, from).
+			Updates(map[string]any{
+				"status": to,
+				"utime":  now,
+			})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		updated = true
+		var delta int64
+		switch {
+		case to == domain.StatusVisible.ToUint8():
+			delta = 1
+		case from == domain.StatusVisible.ToUint8():
+			delta = -1
+		default:
+			return nil
+		}
+		if c.RootId > 0 {
+			return dao.incrCounts(tx, c, delta, now)
+		}
+		// 根评论隐藏或者恢复的时候，下面可见的回复跟着一起从评论数里面扣掉或者加回来
+		var root Comment
+		err := tx.Select("reply_cnt").Where("id = ?", c.Id).First(&root).Error
+		if err != nil {
+			return err
+		}
+		return dao.incrCount(tx, c.Biz, c.BizId, delta*(1+root.ReplyCnt), now)
+	})
+	return updated, err
+}
+
+func (dao *CommentGORMDAO) ListRoots(ctx context.Context, biz string, bizId int64,
+	sortBy domain.SortBy, cursor domain.Cursor, limit int) ([]Comment, error) {
+	db := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ? AND root_id = 0 AND status = ?",
+			biz, bizId, domain.StatusVisible.ToUint8())
+	switch sortBy {
+	case domain.SortByLikes:
+		if !cursor.IsZero() {
+			db = db.Where("like_cnt < ? OR (like_cnt = ? AND id < ?)",
+				cursor.LikeCnt, cursor.LikeCnt, cursor.Id)
+		}
+		db = db.Order("like_cnt DESC, id DESC")
+	default:
+		if !cursor.IsZero() {
+			db = db.Where("id < ?", cursor.Id)
+		}
+		db = db.Order("id DESC")
+	}
+	var res []Comment
+	err := db.Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (dao *CommentGORMDAO) ListReplies(ctx context.Context, rootId int64, afterId int64, limit int) ([]Comment, error) {
+	var res []Comment
+	err := dao.db.WithContext(ctx).
+		Where("root_id = ? AND id > ? AND status = ?", rootId, afterId, domain.StatusVisible.ToUint8()).
+		Order("id ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *CommentGORMDAO) Like(ctx context.Context, uid, cid int64) error {
+	now := time.Now().UnixMilli()
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Clauses(clause.OnConflict{DoNothing: true}).
+			Create(&CommentLike{Uid: uid, Cid: cid, Ctime: now})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return dao.incrLikeCnt(tx, cid, 1)
+	})
+}
+
+func (dao *CommentGORMDAO) Unlike(ctx context.Context, uid, cid int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("uid = ? AND cid = ?", uid, cid).Delete(&CommentLike{})
+		if res.Error != nil || res.RowsAffected == 0 {
+			return res.Error
+		}
+		return dao.incrLikeCnt(tx, cid, -1)
+	})
+}
+
+func (dao *CommentGORMDAO) FindCounts(ctx context.Context, biz string, bizIds []int64) ([]CommentCount, error) {
+	var res []CommentCount
+	err := dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id IN ?", biz, bizIds).
+		Find(&res).Error
+	return res, err
+}
+
+// incrLikeCnt 点赞数不需要更新 utime，utime 只反映内容和状态的变化
+func (dao *CommentGORMDAO) incrLikeCnt(tx *gorm.DB, cid int64, delta int64) error {
+	return tx.Model(&Comment{}).Where("id = ?", cid).
+		Update("like_cnt", gorm.Expr("like_cnt + ?", delta)).Error
+}
+
+// incrCounts 更新 c 对应的回复数和评论数，
+// 回复只有在根评论可见的时候才会计入评论数
+func (dao *CommentGORMDAO) incrCounts(tx *gorm.DB, c Comment, delta int64, now int64) error {
+	if c.RootId == 0 {
+		return dao.incrCount(tx, c.Biz, c.BizId, delta, now)
+	}
+	// 先更新回复数，锁住根评论，避免根评论的状态在这个过程中发生变化
+	err := dao.incrReplyCnt(tx, c.RootId, delta, now)
+	if err != nil {
+		return err
+	}
+	var root Comment
+	err = tx.Select("status").Where("id = ?", c.RootId).First(&root).Error
+	if err != nil {
+		return err
+	}
+	if root.Status != domain.StatusVisible.ToUint8() {
+		return nil
+	}
+	return dao.incrCount(tx, c.Biz, c.BizId, delta, now)
+}
+
+func (dao *CommentGORMDAO) incrReplyCnt(tx *gorm.DB, rootId int64, delta int64, now int64) error {
+	return tx.Model(&Comment{}).Where("id = ?", rootId).
+		Updates(map[string]any{
+			"reply_cnt": gorm.Expr("reply_cnt + ?", delta),
+			"utime":     now,
+		}).Error
+}
+
+func (dao *CommentGORMDAO) incrCount(tx *gorm.DB, biz string, bizId int64, delta int64, now int64) error {
+	return tx.Clauses(clause.OnConflict{
+		DoUpdates: clause.Assignments(map[string]any{
+			"cnt":   gorm.Expr("cnt + ?", delta),
+			"utime": now,
+		}),
+	}).Create(&CommentCount{
+		Biz:   biz,
+		BizId: bizId,
+		Cnt:   delta,
+		Ctime: now,
+		Utime: now,
+	}).Error
+}
+
+type Comment struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64
+	// 按照 biz, biz_id 查询根评论
+	Biz      string `gorm:"type:varchar(64);index:biz_root"`
+	BizId    int64  `gorm:"index:biz_root"`
+	RootId   int64  `gorm:"index:biz_root;index:root_id"`
+	ParentId int64
+	Content  string `gorm:"type:text"`
+	Status   uint8
+	LikeCnt  int64
+	ReplyCnt int64
+	Ctime    int64
+	Utime    int64
+}
+
+// CommentCount 每个内容下面可见的评论数，包括回复
+type CommentCount struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Biz   string `gorm:"type:varchar(64);uniqueIndex:biz_biz_id"`
+	BizId int64  `gorm:"uniqueIndex:biz_biz_id"`
+	Cnt   int64
+	Ctime int64
+	Utime int64
+}
+
+type CommentLike struct {
+	Id    int64 `gorm:"primaryKey,autoIncrement"`
+	Uid   int64 `gorm:"uniqueIndex:uid_cid"`
+	Cid   int64 `gorm:"uniqueIndex:uid_cid;index:cid"`
+	Ctime int64
+}
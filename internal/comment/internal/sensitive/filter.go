@@ -0,0 +1,55 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensitive
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var ErrSensitiveContent = errors.New("包含敏感词")
+
+// Filter 评论落库之前的检查，返回的内容才会被保存下来，
+// 所以实现既可以直接拒绝，也可以把敏感词替换掉
+type Filter interface {
+	Filter(ctx context.Context, content string) (string, error)
+}
+
+// WordFilter 包含任意一个敏感词就拒绝，不区分大小写
+type WordFilter struct {
+	words []string
+}
+
+func NewWordFilter(words []string) *WordFilter {
+	res := make([]string, 0, len(words))
+	for _, w := range words {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w != "" {
+			res = append(res, w)
+		}
+	}
+	return &WordFilter{words: res}
+}
+
+func (f *WordFilter) Filter(ctx context.Context, content string) (string, error) {
+	lower := strings.ToLower(content)
+	for _, w := range f.words {
+		if strings.Contains(lower, w) {
+			return "", ErrSensitiveContent
+		}
+	}
+	return content, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sensitive
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWordFilter_Filter(t *testing.T) {
+	f := NewWordFilter([]string{"广告", " VPN ", ""})
+	testCases := []struct {
+		name    string
+		content string
+		wantErr error
+	}{
+		{
+			name:    "正常内容",
+			content: "这道题的基本回答不够全面",
+		},
+		{
+			name:    "包含敏感词",
+			content: "点我看广告",
+			wantErr: ErrSensitiveContent,
+		},
+		{
+			name:    "不区分大小写",
+			content: "买个 vpn 吧",
+			wantErr: ErrSensitiveContent,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := f.Filter(context.Background(), tc.content)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err == nil {
+				assert.Equal(t, tc.content, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/comment/internal/domain"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/skill"
+	"gorm.io/gorm"
+)
+
+// ContentService 查询被评论的内容是否存在，题目、案例和题集要求已经发布，技能要求不在回收站里面
+//
+//go:generate mockgen -source=./content.go -destination=../../mocks/content.mock.go -package=commentmocks -typed ContentService
+type ContentService interface {
+	Exists(ctx context.Context, biz string, bizId int64) (bool, error)
+}
+
+type contentService struct {
+	queSvc   baguwen.Service
+	qsSvc    baguwen.QuestionSetService
+	caseSvc  cases.Service
+	skillSvc skill.Service
+}
+
+func NewContentService(queSvc baguwen.Service, qsSvc baguwen.QuestionSetService,
+	caseSvc cases.Service, skillSvc skill.Service) ContentService {
+	return &contentService{
+		queSvc:   queSvc,
+		qsSvc:    qsSvc,
+		caseSvc:  caseSvc,
+		skillSvc: skillSvc,
+	}
+}
+
+func (s *contentService) Exists(ctx context.Context, biz string, bizId int64) (bool, error) {
+	var err error
+	switch biz {
+	case domain.BizQuestion:
+		var ques []baguwen.Question
+		ques, err = s.queSvc.GetPubByIDs(ctx, []int64{bizId})
+		return len(ques) > 0, err
+	case domain.BizCase:
+		var cs []cases.Case
+		cs, err = s.caseSvc.GetPubByIDs(ctx, []int64{bizId})
+		return len(cs) > 0, err
+	case domain.BizQuestionSet:
+		_, err = s.qsSvc.PubDetail(ctx, bizId)
+	case domain.BizSkill:
+		_, err = s.skillSvc.Info(ctx, bizId)
+	default:
+		return false, nil
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	return err == nil, err
+}
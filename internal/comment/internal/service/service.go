@@ -0,0 +1,183 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ecodeclub/webook/internal/comment/internal/domain"
+	"github.com/ecodeclub/webook/internal/comment/internal/repository"
+	"github.com/ecodeclub/webook/internal/comment/internal/sensitive"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidComment   = errors.New("评论内容非法")
+	ErrCommentNotFound  = errors.New("评论不存在")
+	ErrPermissionDenied = errors.New("只能删除自己的评论")
+)
+
+//go:generate mockgen -source=./service.go -destination=../../mocks/comment.mock.go -package=commentmocks -typed Service
+type Service interface {
+	// Create 被评论的内容必须存在并且已经发布，ParentId 不为 0 的时候是回复，
+	// 回复的 biz 和 bizId 必须和被回复的评论一致，并且根评论没有被隐藏
+	Create(ctx context.Context, c domain.Comment) (int64, error)
+	// Delete 用户删除自己的评论
+	Delete(ctx context.Context, uid, id int64) error
+	// Hide 创作者隐藏或者恢复评论
+	Hide(ctx context.Context, id int64, hidden bool) error
+	// Remove 创作者删除任意评论
+	Remove(ctx context.Context, id int64) error
+	List(ctx context.Context, biz string, bizId int64, sortBy domain.SortBy, cursor domain.Cursor, limit int) ([]domain.Comment, error)
+	// Replies 根评论被隐藏之后，下面的回复也一起看不到
+	Replies(ctx context.Context, rootId int64, cursor domain.Cursor, limit int) ([]domain.Comment, error)
+	Like(ctx context.Context, uid, id int64, like bool) error
+	// Counts 没有评论的内容不在返回值里面
+	Counts(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error)
+}
+
+type service struct {
+	repo       repository.CommentRepository
+	filter     sensitive.Filter
+	contentSvc ContentService
+}
+
+func NewService(repo repository.CommentRepository, filter sensitive.Filter, contentSvc ContentService) Service {
+	return &service{repo: repo, filter: filter, contentSvc: contentSvc}
+}
+
+func (s *service) Create(ctx context.Context, c domain.Comment) (int64, error) {
+	if !domain.ValidBiz(c.Biz) ||
+		strings.TrimSpace(c.Content) == "" ||
+		utf8.RuneCountInString(c.Content) > domain.MaxContentLen {
+		return 0, ErrInvalidComment
+	}
+	ok, err := s.contentSvc.Exists(ctx, c.Biz, c.BizId)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, ErrInvalidComment
+	}
+	content, err := s.filter.Filter(ctx, c.Content)
+	if err != nil {
+		return 0, err
+	}
+	c.Content = content
+	c.RootId = 0
+	if c.ParentId > 0 {
+		parent, err := s.visibleComment(ctx, c.ParentId)
+		if err != nil {
+			return 0, err
+		}
+		if parent.Biz != c.Biz || parent.BizId != c.BizId {
+			return 0, ErrInvalidComment
+		}
+		c.RootId = parent.RootId
+		if parent.IsRoot() {
+			c.RootId = parent.Id
+		} else if _, err = s.visibleComment(ctx, c.RootId); err != nil {
+			return 0, err
+		}
+	}
+	c.Status = domain.StatusVisible
+	return s.repo.Create(ctx, c)
+}
+
+func (s *service) Delete(ctx context.Context, uid, id int64) error {
+	c, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	if c.Uid != uid {
+		return ErrPermissionDenied
+	}
+	return s.repo.Delete(ctx, c)
+}
+
+func (s *service) Hide(ctx context.Context, id int64, hidden bool) error {
+	c, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	from, to := domain.StatusHidden, domain.StatusVisible
+	if hidden {
+		from, to = to, from
+	}
+	// 已经是目标状态了就什么也不做
+	_, err = s.repo.UpdateStatus(ctx, c, from, to)
+	return err
+}
+
+func (s *service) Remove(ctx context.Context, id int64) error {
+	c, err := s.find(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, c)
+}
+
+func (s *service) List(ctx context.Context, biz string, bizId int64,
+	sortBy domain.SortBy, cursor domain.Cursor, limit int) ([]domain.Comment, error) {
+	return s.repo.ListRoots(ctx, biz, bizId, sortBy, cursor, limit)
+}
+
+func (s *service) Replies(ctx context.Context, rootId int64, cursor domain.Cursor, limit int) ([]domain.Comment, error) {
+	_, err := s.visibleComment(ctx, rootId)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.ListReplies(ctx, rootId, cursor.Id, limit)
+}
+
+func (s *service) Like(ctx context.Context, uid, id int64, like bool) error {
+	if !like {
+		return s.repo.Unlike(ctx, uid, id)
+	}
+	_, err := s.visibleComment(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.repo.Like(ctx, uid, id)
+}
+
+func (s *service) Counts(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	if len(bizIds) == 0 {
+		return map[int64]int64{}, nil
+	}
+	return s.repo.Counts(ctx, biz, bizIds)
+}
+
+func (s *service) visibleComment(ctx context.Context, id int64) (domain.Comment, error) {
+	c, err := s.find(ctx, id)
+	if err != nil {
+		return domain.Comment{}, err
+	}
+	if c.Status != domain.StatusVisible {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	return c, nil
+}
+
+func (s *service) find(ctx context.Context, id int64) (domain.Comment, error) {
+	c, err := s.repo.FindById(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Comment{}, ErrCommentNotFound
+	}
+	return c, err
+}
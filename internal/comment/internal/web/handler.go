@@ -0,0 +1,199 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/comment/internal/domain"
+	"github.com/ecodeclub/webook/internal/comment/internal/sensitive"
+	"github.com/ecodeclub/webook/internal/comment/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLimit = 20
+	maxLimit     = 50
+	// 一次最多查询这么多个内容的评论数
+	maxCountSize = 100
+)
+
+type Handler struct {
+	svc service.Service
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) PublicRoutes(server *gin.Engine) {
+	g := server.Group("/comment")
+	g.POST("/list", ginx.B[ListReq](h.List))
+	g.POST("/replies", ginx.B[RepliesReq](h.Replies))
+	g.POST("/count", ginx.B[CountReq](h.Count))
+}
+
+func (h *Handler) PrivateRoutes(server *gin.Engine) {
+	g := server.Group("/comment")
+	g.POST("/create", ginx.BS[CreateReq](h.Create))
+	g.POST("/delete", ginx.BS[IdReq](h.Delete))
+	g.POST("/like", ginx.BS[LikeReq](h.Like))
+	// 创作者管理评论
+	g.POST("/moderate/hide", ginx.S(h.Permission), ginx.B[HideReq](h.Hide))
+	g.POST("/moderate/delete", ginx.S(h.Permission), ginx.B[IdReq](h.Remove))
+}
+
+func (h *Handler) List(ctx *ginx.Context, req ListReq) (ginx.Result, error) {
+	cursor, err := domain.ParseCursor(req.Cursor)
+	if err != nil {
+		return invalidCommentResult, nil
+	}
+	limit := h.limit(req.Limit)
+	cs, err := h.svc.List(ctx, req.Biz, req.BizId, req.sortBy(), cursor, limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toCommentList(cs, limit),
+	}, nil
+}
+
+func (h *Handler) Replies(ctx *ginx.Context, req RepliesReq) (ginx.Result, error) {
+	cursor, err := domain.ParseCursor(req.Cursor)
+	if err != nil {
+		return invalidCommentResult, nil
+	}
+	limit := h.limit(req.Limit)
+	cs, err := h.svc.Replies(ctx, req.RootId, cursor, limit)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return ginx.Result{
+		Data: h.toCommentList(cs, limit),
+	}, nil
+}
+
+func (h *Handler) Count(ctx *ginx.Context, req CountReq) (ginx.Result, error) {
+	if len(req.BizIds) > maxCountSize {
+		req.BizIds = req.BizIds[:maxCountSize]
+	}
+	cnts, err := h.svc.Counts(ctx, req.Biz, req.BizIds)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	// 按照请求的顺序返回，没有评论的就是 0
+	return ginx.Result{
+		Data: slice.Map(req.BizIds, func(idx int, src int64) Count {
+			return Count{BizId: src, Cnt: cnts[src]}
+		}),
+	}, nil
+}
+
+func (h *Handler) Create(ctx *ginx.Context, req CreateReq, sess session.Session) (ginx.Result, error) {
+	id, err := h.svc.Create(ctx, domain.Comment{
+		Uid:      sess.Claims().Uid,
+		Biz:      req.Biz,
+		BizId:    req.BizId,
+		ParentId: req.ParentId,
+		Content:  req.Content,
+	})
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return ginx.Result{
+		Data: id,
+	}, nil
+}
+
+func (h *Handler) Delete(ctx *ginx.Context, req IdReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Delete(ctx, sess.Claims().Uid, req.Id)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Like(ctx *ginx.Context, req LikeReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Like(ctx, sess.Claims().Uid, req.Id, req.Like)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Hide(ctx *ginx.Context, req HideReq) (ginx.Result, error) {
+	err := h.svc.Hide(ctx, req.Id, req.Hidden)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Remove(ctx *ginx.Context, req IdReq) (ginx.Result, error) {
+	err := h.svc.Remove(ctx, req.Id)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Permission(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	if sess.Claims().Get("creator").StringOrDefault("") != "true" {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return ginx.Result{}, fmt.Errorf("非法访问创作中心 uid: %d", sess.Claims().Uid)
+	}
+	return ginx.Result{}, ginx.ErrNoResponse
+}
+
+func (h *Handler) limit(limit int) int {
+	if limit <= 0 {
+		return defaultLimit
+	}
+	return min(limit, maxLimit)
+}
+
+// toCommentList 不足一页说明没有更多了
+func (h *Handler) toCommentList(cs []domain.Comment, limit int) CommentList {
+	res := CommentList{
+		Comments: slice.Map(cs, func(idx int, src domain.Comment) Comment {
+			return newComment(src)
+		}),
+	}
+	if len(cs) == limit {
+		res.Next = domain.NewCursor(cs[len(cs)-1]).String()
+	}
+	return res
+}
+
+// errorResult 业务上的错误直接告诉前端，不需要记录日志
+func (h *Handler) errorResult(err error) (ginx.Result, error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidComment):
+		return invalidCommentResult, nil
+	case errors.Is(err, sensitive.ErrSensitiveContent):
+		return sensitiveContentResult, nil
+	case errors.Is(err, service.ErrCommentNotFound):
+		return commentNotFoundResult, nil
+	case errors.Is(err, service.ErrPermissionDenied):
+		return permissionDeniedResult, nil
+	default:
+		return systemErrorResult, err
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import "github.com/ecodeclub/webook/internal/comment/internal/domain"
+
+const (
+	sortByTime  = "time"
+	sortByLikes = "likes"
+)
+
+type ListReq struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	// Sort 取值 time 或者 likes，默认按照时间倒序
+	Sort string `json:"sort,omitempty"`
+	// Cursor 上一页返回的 next，第一页不传
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+func (req ListReq) sortBy() domain.SortBy {
+	if req.Sort == sortByLikes {
+		return domain.SortByLikes
+	}
+	return domain.SortByTime
+}
+
+type RepliesReq struct {
+	RootId int64  `json:"rootId"`
+	Cursor string `json:"cursor,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+type CountReq struct {
+	Biz    string  `json:"biz"`
+	BizIds []int64 `json:"bizIds"`
+}
+
+type CreateReq struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	// ParentId 回复某条评论的时候才需要
+	ParentId int64  `json:"parentId,omitempty"`
+	Content  string `json:"content"`
+}
+
+type IdReq struct {
+	Id int64 `json:"id"`
+}
+
+type LikeReq struct {
+	Id int64 `json:"id"`
+	// Like 为 false 的时候取消点赞
+	Like bool `json:"like"`
+}
+
+type HideReq struct {
+	Id int64 `json:"id"`
+	// Hidden 为 false 的时候恢复展示
+	Hidden bool `json:"hidden"`
+}
+
+type Comment struct {
+	Id       int64  `json:"id"`
+	Uid      int64  `json:"uid"`
+	Biz      string `json:"biz"`
+	BizId    int64  `json:"bizId"`
+	RootId   int64  `json:"rootId,omitempty"`
+	ParentId int64  `json:"parentId,omitempty"`
+	Content  string `json:"content"`
+	LikeCnt  int64  `json:"likeCnt"`
+	ReplyCnt int64  `json:"replyCnt,omitempty"`
+	Ctime    int64  `json:"ctime"`
+}
+
+func newComment(c domain.Comment) Comment {
+	return Comment{
+		Id:       c.Id,
+		Uid:      c.Uid,
+		Biz:      c.Biz,
+		BizId:    c.BizId,
+		RootId:   c.RootId,
+		ParentId: c.ParentId,
+		Content:  c.Content,
+		LikeCnt:  c.LikeCnt,
+		ReplyCnt: c.ReplyCnt,
+		Ctime:    c.Ctime.UnixMilli(),
+	}
+}
+
+type CommentList struct {
+	Comments []Comment `json:"comments"`
+	// Next 下一页的游标，为空说明没有更多了
+	Next string `json:"next,omitempty"`
+}
+
+type Count struct {
+	BizId int64 `json:"bizId"`
+	Cnt   int64 `json:"cnt"`
+}
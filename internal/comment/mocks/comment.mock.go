@@ -0,0 +1,348 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./service.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./service.go -destination=../../mocks/comment.mock.go -package=commentmocks -typed Service
+//
+// Package commentmocks is a generated GoMock package.
+package commentmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	domain "github.com/ecodeclub/webook/internal/comment/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockService is a mock of Service interface.
+type MockService struct {
+	ctrl     *gomock.Controller
+	recorder *MockServiceMockRecorder
+}
+
+// MockServiceMockRecorder is the mock recorder for MockService.
+type MockServiceMockRecorder struct {
+	mock *MockService
+}
+
+// NewMockService creates a new mock instance.
+func NewMockService(ctrl *gomock.Controller) *MockService {
+	mock := &MockService{ctrl: ctrl}
+	mock.recorder = &MockServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockService) EXPECT() *MockServiceMockRecorder {
+	return m.recorder
+}
+
+// Counts mocks base method.
+func (m *MockService) Counts(ctx context.Context, biz string, bizIds []int64) (map[int64]int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Counts", ctx, biz, bizIds)
+	ret0, _ := ret[0].(map[int64]int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Counts indicates an expected call of Counts.
+func (mr *MockServiceMockRecorder) Counts(ctx, biz, bizIds any) *ServiceCountsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Counts", reflect.TypeOf((*MockService)(nil).Counts), ctx, biz, bizIds)
+	return &ServiceCountsCall{Call: call}
+}
+
+// ServiceCountsCall wrap *gomock.Call
+type ServiceCountsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceCountsCall) Return(arg0 map[int64]int64, arg1 error) *ServiceCountsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceCountsCall) Do(f func(context.Context, string, []int64) (map[int64]int64, error)) *ServiceCountsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceCountsCall) DoAndReturn(f func(context.Context, string, []int64) (map[int64]int64, error)) *ServiceCountsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Create mocks base method.
+func (m *MockService) Create(ctx context.Context, c domain.Comment) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", ctx, c)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockServiceMockRecorder) Create(ctx, c any) *ServiceCreateCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockService)(nil).Create), ctx, c)
+	return &ServiceCreateCall{Call: call}
+}
+
+// ServiceCreateCall wrap *gomock.Call
+type ServiceCreateCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c_2 *ServiceCreateCall) Return(arg0 int64, arg1 error) *ServiceCreateCall {
+	c_2.Call = c_2.Call.Return(arg0, arg1)
+	return c_2
+}
+
+// Do rewrite *gomock.Call.Do
+func (c_2 *ServiceCreateCall) Do(f func(context.Context, domain.Comment) (int64, error)) *ServiceCreateCall {
+	c_2.Call = c_2.Call.Do(f)
+	return c_2
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c_2 *ServiceCreateCall) DoAndReturn(f func(context.Context, domain.Comment) (int64, error)) *ServiceCreateCall {
+	c_2.Call = c_2.Call.DoAndReturn(f)
+	return c_2
+}
+
+// Delete mocks base method.
+func (m *MockService) Delete(ctx context.Context, uid, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, uid, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockServiceMockRecorder) Delete(ctx, uid, id any) *ServiceDeleteCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, uid, id)
+	return &ServiceDeleteCall{Call: call}
+}
+
+// ServiceDeleteCall wrap *gomock.Call
+type ServiceDeleteCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceDeleteCall) Return(arg0 error) *ServiceDeleteCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceDeleteCall) Do(f func(context.Context, int64, int64) error) *ServiceDeleteCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceDeleteCall) DoAndReturn(f func(context.Context, int64, int64) error) *ServiceDeleteCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Hide mocks base method.
+func (m *MockService) Hide(ctx context.Context, id int64, hidden bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Hide", ctx, id, hidden)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Hide indicates an expected call of Hide.
+func (mr *MockServiceMockRecorder) Hide(ctx, id, hidden any) *ServiceHideCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Hide", reflect.TypeOf((*MockService)(nil).Hide), ctx, id, hidden)
+	return &ServiceHideCall{Call: call}
+}
+
+// ServiceHideCall wrap *gomock.Call
+type ServiceHideCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceHideCall) Return(arg0 error) *ServiceHideCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceHideCall) Do(f func(context.Context, int64, bool) error) *ServiceHideCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceHideCall) DoAndReturn(f func(context.Context, int64, bool) error) *ServiceHideCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Like mocks base method.
+func (m *MockService) Like(ctx context.Context, uid, id int64, like bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Like", ctx, uid, id, like)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Like indicates an expected call of Like.
+func (mr *MockServiceMockRecorder) Like(ctx, uid, id, like any) *ServiceLikeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Like", reflect.TypeOf((*MockService)(nil).Like), ctx, uid, id, like)
+	return &ServiceLikeCall{Call: call}
+}
+
+// ServiceLikeCall wrap *gomock.Call
+type ServiceLikeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceLikeCall) Return(arg0 error) *ServiceLikeCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceLikeCall) Do(f func(context.Context, int64, int64, bool) error) *ServiceLikeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceLikeCall) DoAndReturn(f func(context.Context, int64, int64, bool) error) *ServiceLikeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// List mocks base method.
+func (m *MockService) List(ctx context.Context, biz string, bizId int64, sortBy domain.SortBy, cursor domain.Cursor, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, biz, bizId, sortBy, cursor, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// List indicates an expected call of List.
+func (mr *MockServiceMockRecorder) List(ctx, biz, bizId, sortBy, cursor, limit any) *ServiceListCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockService)(nil).List), ctx, biz, bizId, sortBy, cursor, limit)
+	return &ServiceListCall{Call: call}
+}
+
+// ServiceListCall wrap *gomock.Call
+type ServiceListCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceListCall) Return(arg0 []domain.Comment, arg1 error) *ServiceListCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceListCall) Do(f func(context.Context, string, int64, domain.SortBy, domain.Cursor, int) ([]domain.Comment, error)) *ServiceListCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceListCall) DoAndReturn(f func(context.Context, string, int64, domain.SortBy, domain.Cursor, int) ([]domain.Comment, error)) *ServiceListCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Remove mocks base method.
+func (m *MockService) Remove(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Remove", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Remove indicates an expected call of Remove.
+func (mr *MockServiceMockRecorder) Remove(ctx, id any) *ServiceRemoveCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Remove", reflect.TypeOf((*MockService)(nil).Remove), ctx, id)
+	return &ServiceRemoveCall{Call: call}
+}
+
+// ServiceRemoveCall wrap *gomock.Call
+type ServiceRemoveCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceRemoveCall) Return(arg0 error) *ServiceRemoveCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceRemoveCall) Do(f func(context.Context, int64) error) *ServiceRemoveCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceRemoveCall) DoAndReturn(f func(context.Context, int64) error) *ServiceRemoveCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Replies mocks base method.
+func (m *MockService) Replies(ctx context.Context, rootId int64, cursor domain.Cursor, limit int) ([]domain.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replies", ctx, rootId, cursor, limit)
+	ret0, _ := ret[0].([]domain.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replies indicates an expected call of Replies.
+func (mr *MockServiceMockRecorder) Replies(ctx, rootId, cursor, limit any) *ServiceRepliesCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replies", reflect.TypeOf((*MockService)(nil).Replies), ctx, rootId, cursor, limit)
+	return &ServiceRepliesCall{Call: call}
+}
+
+// ServiceRepliesCall wrap *gomock.Call
+type ServiceRepliesCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceRepliesCall) Return(arg0 []domain.Comment, arg1 error) *ServiceRepliesCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceRepliesCall) Do(f func(context.Context, int64, domain.Cursor, int) ([]domain.Comment, error)) *ServiceRepliesCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceRepliesCall) DoAndReturn(f func(context.Context, int64, domain.Cursor, int) ([]domain.Comment, error)) *ServiceRepliesCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./content.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./content.go -destination=../../mocks/content.mock.go -package=commentmocks -typed ContentService
+//
+// Package commentmocks is a generated GoMock package.
+package commentmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockContentService is a mock of ContentService interface.
+type MockContentService struct {
+	ctrl     *gomock.Controller
+	recorder *MockContentServiceMockRecorder
+}
+
+// MockContentServiceMockRecorder is the mock recorder for MockContentService.
+type MockContentServiceMockRecorder struct {
+	mock *MockContentService
+}
+
+// NewMockContentService creates a new mock instance.
+func NewMockContentService(ctrl *gomock.Controller) *MockContentService {
+	mock := &MockContentService{ctrl: ctrl}
+	mock.recorder = &MockContentServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContentService) EXPECT() *MockContentServiceMockRecorder {
+	return m.recorder
+}
+
+// Exists mocks base method.
+func (m *MockContentService) Exists(ctx context.Context, biz string, bizId int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Exists", ctx, biz, bizId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Exists indicates an expected call of Exists.
+func (mr *MockContentServiceMockRecorder) Exists(ctx, biz, bizId any) *ContentServiceExistsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Exists", reflect.TypeOf((*MockContentService)(nil).Exists), ctx, biz, bizId)
+	return &ContentServiceExistsCall{Call: call}
+}
+
+// ContentServiceExistsCall wrap *gomock.Call
+type ContentServiceExistsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ContentServiceExistsCall) Return(arg0 bool, arg1 error) *ContentServiceExistsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ContentServiceExistsCall) Do(f func(context.Context, string, int64) (bool, error)) *ContentServiceExistsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ContentServiceExistsCall) DoAndReturn(f func(context.Context, string, int64) (bool, error)) *ContentServiceExistsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
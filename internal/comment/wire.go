@@ -0,0 +1,101 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package comment
+
+import (
+	"sync"
+
+	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/comment/internal/domain"
+	"github.com/ecodeclub/webook/internal/comment/internal/repository"
+	"github.com/ecodeclub/webook/internal/comment/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/comment/internal/sensitive"
+	"github.com/ecodeclub/webook/internal/comment/internal/service"
+	"github.com/ecodeclub/webook/internal/comment/internal/web"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+	"github.com/gotomicro/ego/core/econf"
+)
+
+func InitModule(db *egorm.Component,
+	queModule *baguwen.Module,
+	caseModule *cases.Module,
+	skillModule *skill.Module) *Module {
+	wire.Build(
+		initFilter,
+		service.NewContentService,
+		wire.FieldsOf(new(*baguwen.Module), "Svc", "QsSvc"),
+		wire.FieldsOf(new(*cases.Module), "Svc"),
+		wire.FieldsOf(new(*skill.Module), "Svc"),
+		InitModuleWithFilter,
+	)
+	return new(Module)
+}
+
+// InitModuleWithFilter 可以替换成接入了第三方内容审核的实现
+func InitModuleWithFilter(db *egorm.Component, filter Filter, contentSvc ContentService) *Module {
+	wire.Build(
+		initDAO,
+		repository.NewCommentRepository,
+		service.NewService,
+		web.NewHandler,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module)
+}
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.CommentDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewCommentGORMDAO(db)
+}
+
+// initFilter 敏感词放在配置文件里面，没有配置就不过滤
+func initFilter() Filter {
+	type Config struct {
+		SensitiveWords []string `yaml:"sensitiveWords"`
+	}
+	var cfg Config
+	err := econf.UnmarshalKey("comment", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return sensitive.NewWordFilter(cfg.SensitiveWords)
+}
+
+const (
+	BizQuestion    = domain.BizQuestion
+	BizCase        = domain.BizCase
+	BizSkill       = domain.BizSkill
+	BizQuestionSet = domain.BizQuestionSet
+)
+
+var ErrSensitiveContent = sensitive.ErrSensitiveContent
+
+type Handler = web.Handler
+type Service = service.Service
+type Comment = domain.Comment
+type Filter = sensitive.Filter
+type ContentService = service.ContentService
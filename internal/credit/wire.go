@@ -32,8 +32,11 @@ import (
 )
 
 type Credit = domain.Credit
+type CreditLog = domain.CreditLog
 type Service = service.Service
 
+var ErrCreditNotEnough = service.ErrCreditNotEnough
+
 func InitModule(db *egorm.Component, q mq.MQ, e ecache.Cache) (*Module, error) {
 	wire.Build(wire.Struct(
 		new(Module), "*"),
@@ -37,8 +37,12 @@ func InitModule(db *gorm.DB, q mq.MQ, e ecache.Cache) (*Module, error) {
 
 type Credit = domain.Credit
 
+type CreditLog = domain.CreditLog
+
 type Service = service.Service
 
+var ErrCreditNotEnough = service.ErrCreditNotEnough
+
 var (
 	once = &sync.Once{}
 	svc  service.Service
@@ -0,0 +1,70 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// Level 用户回答对比的答案层级
+type Level uint8
+
+const (
+	LevelBasic Level = iota + 1
+	LevelIntermediate
+	LevelAdvanced
+)
+
+type Status uint8
+
+const (
+	StatusPending Status = iota + 1
+	StatusSucceeded
+	StatusFailed
+)
+
+// Evaluation 对用户自己写的答案的一次评估
+type Evaluation struct {
+	Id     int64
+	Uid    int64
+	Qid    int64
+	Answer string
+	// Provider 给出评估结果的大模型
+	Provider string
+	Status   Status
+	Feedback Feedback
+	// Cost 消耗的积分
+	Cost  uint64
+	Ctime time.Time
+	Utime time.Time
+}
+
+type Feedback struct {
+	// Score 0 - 100
+	Score    int
+	Summary  string
+	Elements []ElementFeedback
+}
+
+// ElementFeedback 和答案某个层级对比的结果
+type ElementFeedback struct {
+	Level Level
+	Score int
+	// Hits 答到了的关键字
+	Hits []string
+	// Missing 遗漏了的关键字
+	Missing []string
+	// Highlight 是否答到了亮点
+	Highlight bool
+	Comment   string
+}
@@ -0,0 +1,14 @@
+package errs
+
+var (
+	SystemError      = ErrorCode{Code: 516001, Msg: "系统错误"}
+	InvalidAnswer    = ErrorCode{Code: 516002, Msg: "回答内容非法"}
+	RateLimited      = ErrorCode{Code: 516003, Msg: "评估太频繁，请稍后再试"}
+	CreditNotEnough  = ErrorCode{Code: 516004, Msg: "积分不足"}
+	QuestionNotFound = ErrorCode{Code: 516005, Msg: "问题不存在"}
+)
+
+type ErrorCode struct {
+	Code int
+	Msg  string
+}
@@ -0,0 +1,323 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/credit"
+	creditmocks "github.com/ecodeclub/webook/internal/credit/mocks"
+	"github.com/ecodeclub/webook/internal/evaluation"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/llm"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/service"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/web"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	quemocks "github.com/ecodeclub/webook/internal/question/mocks"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const uid = 6001
+
+// failProvider 标题为 fail 的题目评估失败
+type failProvider struct {
+	llm.Provider
+}
+
+func (p failProvider) Evaluate(ctx context.Context, req llm.Request) (domain.Feedback, error) {
+	if req.Question.Title == "fail" {
+		return domain.Feedback{}, errors.New("模拟大模型调用失败")
+	}
+	return p.Provider.Evaluate(ctx, req)
+}
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server    *egin.Component
+	db        *egorm.Component
+	rdb       redis.Cmdable
+	queSvc    *quemocks.MockService
+	creditSvc *creditmocks.MockService
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	s.queSvc = quemocks.NewMockService(ctrl)
+	s.queSvc.EXPECT().GetPubByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, qids []int64) ([]baguwen.Question, error) {
+			switch qids[0] {
+			case 1:
+				return []baguwen.Question{{
+					Id:    1,
+					Title: "MySQL 事务",
+					Answer: baguwen.Answer{
+						Basic: baguwen.AnswerElement{
+							Keywords:  "事务,隔离级别",
+							Highlight: "MVCC",
+						},
+					},
+				}}, nil
+			case 2:
+				return []baguwen.Question{{Id: 2, Title: "fail"}}, nil
+			default:
+				// 不存在或者没有发布
+				return []baguwen.Question{}, nil
+			}
+		}).AnyTimes()
+	s.creditSvc = creditmocks.NewMockService(ctrl)
+	s.server = s.newServer(s.creditSvc, 10)
+	s.db = testioc.InitDB()
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+	s.rdb = testioc.InitRedis()
+}
+
+func (s *HandlerTestSuite) newServer(creditSvc credit.Service, cost uint64) *egin.Component {
+	module := startup.InitModule(&baguwen.Module{Svc: s.queSvc}, creditSvc,
+		failProvider{Provider: llm.NewFakeProvider()},
+		evaluation.Config{
+			Service: service.Config{Cost: cost},
+			Window:  time.Hour,
+			Rate:    3,
+		})
+
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid: uid,
+		}))
+	})
+	module.Hdl.MemberRoutes(server.Engine)
+	return server
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `answer_evaluations`").Error
+	require.NoError(s.T(), err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	keys, err := s.rdb.Keys(ctx, fmt.Sprintf("webook:evaluation:limit:%d:*", uid)).Result()
+	require.NoError(s.T(), err)
+	if len(keys) > 0 {
+		require.NoError(s.T(), s.rdb.Del(ctx, keys...).Err())
+	}
+}
+
+func (s *HandlerTestSuite) TestEvaluate() {
+	testCases := []struct {
+		name     string
+		before   func(t *testing.T)
+		req      web.EvaluateReq
+		wantCode int
+		wantResp test.Result[web.Evaluation]
+	}{
+		{
+			name: "评估成功",
+			before: func(t *testing.T) {
+				s.creditSvc.EXPECT().TryDeductCredits(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, c credit.Credit) (int64, error) {
+						assert.Equal(t, uint64(10), c.ChangeAmount)
+						assert.Equal(t, int64(uid), c.Uid)
+						return 11, nil
+					})
+				s.creditSvc.EXPECT().ConfirmDeductCredits(gomock.Any(), int64(uid), int64(11)).Return(nil)
+			},
+			req:      web.EvaluateReq{Qid: 1, Answer: "事务依靠 MVCC 实现"},
+			wantCode: 200,
+			wantResp: test.Result[web.Evaluation]{
+				Data: web.Evaluation{
+					Qid:     1,
+					Answer:  "事务依靠 MVCC 实现",
+					Status:  uint8(domain.StatusSucceeded),
+					Score:   50,
+					Summary: "综合得分 50",
+					Elements: []web.Element{
+						{
+							Level:     1,
+							Score:     50,
+							Hits:      []string{"事务"},
+							Missing:   []string{"隔离级别"},
+							Highlight: true,
+							Comment:   "答到了 1/2 个关键字，并且答到了亮点",
+						},
+					},
+					Cost: 10,
+				},
+			},
+		},
+		{
+			name:     "回答为空",
+			before:   func(t *testing.T) {},
+			req:      web.EvaluateReq{Qid: 1},
+			wantCode: 500,
+			wantResp: test.Result[web.Evaluation]{Code: 516002, Msg: "回答内容非法"},
+		},
+		{
+			name: "积分不足",
+			before: func(t *testing.T) {
+				s.creditSvc.EXPECT().TryDeductCredits(gomock.Any(), gomock.Any()).
+					Return(int64(0), fmt.Errorf("%w", credit.ErrCreditNotEnough))
+			},
+			req:      web.EvaluateReq{Qid: 1, Answer: "事务"},
+			wantCode: 500,
+			wantResp: test.Result[web.Evaluation]{Code: 516004, Msg: "积分不足"},
+		},
+		{
+			name: "评估失败退回积分",
+			before: func(t *testing.T) {
+				s.creditSvc.EXPECT().TryDeductCredits(gomock.Any(), gomock.Any()).Return(int64(12), nil)
+				s.creditSvc.EXPECT().CancelDeductCredits(gomock.Any(), int64(uid), int64(12)).Return(nil)
+			},
+			req:      web.EvaluateReq{Qid: 2, Answer: "随便写写"},
+			wantCode: 500,
+			wantResp: test.Result[web.Evaluation]{Code: 516001, Msg: "系统错误"},
+		},
+		{
+			name:     "问题不存在",
+			before:   func(t *testing.T) {},
+			req:      web.EvaluateReq{Qid: 100, Answer: "事务"},
+			wantCode: 500,
+			wantResp: test.Result[web.Evaluation]{Code: 516005, Msg: "问题不存在"},
+		},
+		{
+			name:     "评估太频繁",
+			before:   func(t *testing.T) {},
+			req:      web.EvaluateReq{Qid: 1, Answer: "事务"},
+			wantCode: 500,
+			wantResp: test.Result[web.Evaluation]{Code: 516003, Msg: "评估太频繁，请稍后再试"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/evaluation/evaluate", iox.NewJSONReader(tc.req))
+			require.NoError(t, err)
+			req.Header.Set("content-type", "application/json")
+			recorder := test.NewJSONResponseRecorder[web.Evaluation]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			resp := recorder.MustScan()
+			if resp.Code == 0 {
+				assert.True(t, resp.Data.Id > 0)
+				resp.Data.Id, resp.Data.Ctime = 0, 0
+			}
+			assert.Equal(t, tc.wantResp, resp)
+		})
+	}
+
+	// 失败的评估也会保留下来，最新的排在前面
+	req, err := http.NewRequest(http.MethodPost,
+		"/evaluation/list", iox.NewJSONReader(web.ListReq{Qid: 1}))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.EvaluationList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	list := recorder.MustScan().Data
+	assert.Equal(s.T(), int64(2), list.Total)
+	require.Len(s.T(), list.Evaluations, 2)
+	assert.Equal(s.T(), uint8(domain.StatusFailed), list.Evaluations[0].Status)
+	assert.Equal(s.T(), uint8(domain.StatusSucceeded), list.Evaluations[1].Status)
+	assert.Equal(s.T(), 50, list.Evaluations[1].Score)
+}
+
+func (s *HandlerTestSuite) TestEvaluate_Free() {
+	// 免费评估不会调用积分服务，mock 没有设置任何预期，调用了就会失败
+	creditSvc := creditmocks.NewMockService(gomock.NewController(s.T()))
+	server := s.newServer(creditSvc, 0)
+
+	testCases := []struct {
+		name     string
+		req      web.EvaluateReq
+		wantCode int
+		wantResp test.Result[web.Evaluation]
+	}{
+		{
+			// 评估成功不需要确认扣积分
+			name:     "评估成功",
+			req:      web.EvaluateReq{Qid: 1, Answer: "事务"},
+			wantCode: 200,
+			wantResp: test.Result[web.Evaluation]{
+				Data: web.Evaluation{
+					Qid:     1,
+					Answer:  "事务",
+					Status:  uint8(domain.StatusSucceeded),
+					Score:   50,
+					Summary: "综合得分 50",
+					Elements: []web.Element{
+						{
+							Level:   1,
+							Score:   50,
+							Hits:    []string{"事务"},
+							Missing: []string{"隔离级别"},
+							Comment: "答到了 1/2 个关键字",
+						},
+					},
+				},
+			},
+		},
+		{
+			// 评估失败也不需要退回积分
+			name:     "评估失败",
+			req:      web.EvaluateReq{Qid: 2, Answer: "随便写写"},
+			wantCode: 500,
+			wantResp: test.Result[web.Evaluation]{Code: 516001, Msg: "系统错误"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/evaluation/evaluate", iox.NewJSONReader(tc.req))
+			require.NoError(t, err)
+			req.Header.Set("content-type", "application/json")
+			recorder := test.NewJSONResponseRecorder[web.Evaluation]()
+			server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			resp := recorder.MustScan()
+			if resp.Code == 0 {
+				assert.True(t, resp.Data.Id > 0)
+				resp.Data.Id, resp.Data.Ctime = 0, 0
+			}
+			assert.Equal(t, tc.wantResp, resp)
+		})
+	}
+}
+
+func TestEvaluationHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,24 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package startup
+
+import (
+	"github.com/ecodeclub/webook/internal/credit"
+	"github.com/ecodeclub/webook/internal/evaluation"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/llm"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+)
+
+// Injectors from wire.go:
+
+func InitModule(qm *baguwen.Module, creditSvc credit.Service, provider llm.Provider, cfg evaluation.Config) *evaluation.Module {
+	db := testioc.InitDB()
+	cmdable := testioc.InitRedis()
+	module := evaluation.InitModuleWithProvider(db, cmdable, qm, creditSvc, provider, cfg)
+	return module
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limiter
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Limiter 按照用户限流
+type Limiter interface {
+	// Limit 返回 true 表示这一次请求要被限流
+	Limit(ctx context.Context, uid int64) (bool, error)
+}
+
+// FixedWindowLimiter 固定窗口限流，每个窗口一个 key，窗口结束之后自然过期
+type FixedWindowLimiter struct {
+	cmd    redis.Cmdable
+	prefix string
+	window time.Duration
+	rate   int64
+}
+
+func NewFixedWindowLimiter(cmd redis.Cmdable, prefix string, window time.Duration, rate int64) *FixedWindowLimiter {
+	return &FixedWindowLimiter{
+		cmd:    cmd,
+		prefix: prefix,
+		window: window,
+		rate:   rate,
+	}
+}
+
+func (l *FixedWindowLimiter) Limit(ctx context.Context, uid int64) (bool, error) {
+	start := time.Now().UnixMilli() / l.window.Milliseconds()
+	key := fmt.Sprintf("%s:%d:%d", l.prefix, uid, start)
+	pipe := l.cmd.TxPipeline()
+	cnt := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, l.window)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return false, err
+	}
+	return cnt.Val() > l.rate, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llm
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+)
+
+// FakeProvider 本地的实现，只按照关键字和亮点是否出现来打分，结果是确定的
+type FakeProvider struct{}
+
+func NewFakeProvider() *FakeProvider {
+	return &FakeProvider{}
+}
+
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+func (f *FakeProvider) Evaluate(ctx context.Context, req Request) (domain.Feedback, error) {
+	answer := strings.ToLower(req.Answer)
+	var (
+		res   domain.Feedback
+		total int
+	)
+	for _, e := range req.Elements() {
+		keywords := splitKeywords(e.Keywords)
+		if len(keywords) == 0 {
+			continue
+		}
+		fb := domain.ElementFeedback{
+			Level:   e.Level,
+			Hits:    []string{},
+			Missing: []string{},
+		}
+		for _, kw := range keywords {
+			if strings.Contains(answer, strings.ToLower(kw)) {
+				fb.Hits = append(fb.Hits, kw)
+			} else {
+				fb.Missing = append(fb.Missing, kw)
+			}
+		}
+		for _, hl := range splitKeywords(e.Highlight) {
+			if strings.Contains(answer, strings.ToLower(hl)) {
+				fb.Highlight = true
+				break
+			}
+		}
+		fb.Score = int(math.Round(float64(len(fb.Hits)) * 100 / float64(len(keywords))))
+		fb.Comment = fmt.Sprintf("答到了 %d/%d 个关键字", len(fb.Hits), len(keywords))
+		if fb.Highlight {
+			fb.Comment += "，并且答到了亮点"
+		}
+		total += fb.Score
+		res.Elements = append(res.Elements, fb)
+	}
+	if len(res.Elements) == 0 {
+		res.Summary = "答案里面没有可以用来对比的关键字"
+		return res, nil
+	}
+	res.Score = int(math.Round(float64(total) / float64(len(res.Elements))))
+	res.Summary = fmt.Sprintf("综合得分 %d", res.Score)
+	return res, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFakeProvider_Evaluate(t *testing.T) {
+	que := baguwen.Question{
+		Answer: baguwen.Answer{
+			Basic: baguwen.AnswerElement{
+				Keywords:  "索引，事务、Redo Log",
+				Highlight: "MVCC",
+			},
+			Intermediate: baguwen.AnswerElement{
+				Content: "只有内容没有关键字的层级不参与打分",
+			},
+			Advanced: baguwen.AnswerElement{
+				Keywords: "B+树;回表",
+			},
+		},
+	}
+	testCases := []struct {
+		name   string
+		answer string
+		want   domain.Feedback
+	}{
+		{
+			name:   "部分答对",
+			answer: "要用索引，并且开启事务，依靠 mvcc 来实现隔离，查询的时候尽量避免回表",
+			want: domain.Feedback{
+				Score:   59,
+				Summary: "综合得分 59",
+				Elements: []domain.ElementFeedback{
+					{
+						Level:     domain.LevelBasic,
+						Score:     67,
+						Hits:      []string{"索引", "事务"},
+						Missing:   []string{"Redo Log"},
+						Highlight: true,
+						Comment:   "答到了 2/3 个关键字，并且答到了亮点",
+					},
+					{
+						Level:   domain.LevelAdvanced,
+						Score:   50,
+						Hits:    []string{"回表"},
+						Missing: []string{"B+树"},
+						Comment: "答到了 1/2 个关键字",
+					},
+				},
+			},
+		},
+		{
+			name:   "全部答错",
+			answer: "不知道",
+			want: domain.Feedback{
+				Summary: "综合得分 0",
+				Elements: []domain.ElementFeedback{
+					{
+						Level:   domain.LevelBasic,
+						Hits:    []string{},
+						Missing: []string{"索引", "事务", "Redo Log"},
+						Comment: "答到了 0/3 个关键字",
+					},
+					{
+						Level:   domain.LevelAdvanced,
+						Hits:    []string{},
+						Missing: []string{"B+树", "回表"},
+						Comment: "答到了 0/2 个关键字",
+					},
+				},
+			},
+		},
+	}
+	p := NewFakeProvider()
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			fb, err := p.Evaluate(context.Background(), Request{Question: que, Answer: tc.answer})
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, fb)
+		})
+	}
+}
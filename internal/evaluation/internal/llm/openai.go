@@ -0,0 +1,162 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+)
+
+// OpenAIProvider 兼容 OpenAI chat completions 接口的实现，国内大部分厂商都提供了兼容的接口
+type OpenAIProvider struct {
+	client  *http.Client
+	baseURL string
+	apiKey  string
+	model   string
+}
+
+func NewOpenAIProvider(client *http.Client, baseURL, apiKey, model string) *OpenAIProvider {
+	return &OpenAIProvider{
+		client:  client,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		apiKey:  apiKey,
+		model:   model,
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return p.model
+}
+
+const systemPrompt = `你是一名资深的技术面试官，需要把候选人的回答和参考答案逐个层级地对比。
+只输出 JSON，格式为：
+{"score": 0-100 的整数, "summary": "总体评价", "elements": [{"level": 层级编号, "score": 0-100 的整数, "hits": ["答到了的关键字"], "missing": ["遗漏了的关键字"], "highlight": 是否答到了亮点, "comment": "该层级的评价"}]}`
+
+func (p *OpenAIProvider) Evaluate(ctx context.Context, req Request) (domain.Feedback, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: p.model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: p.prompt(req)},
+		},
+		ResponseFormat: responseFormat{Type: "json_object"},
+	})
+	if err != nil {
+		return domain.Feedback{}, err
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		p.baseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return domain.Feedback{}, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return domain.Feedback{}, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return domain.Feedback{}, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return domain.Feedback{}, fmt.Errorf("调用大模型失败 %d: %s", resp.StatusCode, data)
+	}
+	var chatResp chatResponse
+	if err = json.Unmarshal(data, &chatResp); err != nil {
+		return domain.Feedback{}, err
+	}
+	if len(chatResp.Choices) == 0 {
+		return domain.Feedback{}, errors.New("大模型没有返回结果")
+	}
+	var fb feedback
+	if err = json.Unmarshal([]byte(chatResp.Choices[0].Message.Content), &fb); err != nil {
+		return domain.Feedback{}, fmt.Errorf("解析大模型返回的结果失败: %w", err)
+	}
+	return fb.toDomain(), nil
+}
+
+func (p *OpenAIProvider) prompt(req Request) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "题目：%s\n%s\n\n", req.Question.Title, req.Question.Content)
+	for _, e := range req.Elements() {
+		fmt.Fprintf(&sb, "层级 %d 参考答案：\n%s\n关键字：%s\n亮点：%s\n\n",
+			e.Level, e.Content, e.Keywords, e.Highlight)
+	}
+	fmt.Fprintf(&sb, "候选人的回答：\n%s", req.Answer)
+	return sb.String()
+}
+
+type chatRequest struct {
+	Model          string         `json:"model"`
+	Messages       []chatMessage  `json:"messages"`
+	ResponseFormat responseFormat `json:"response_format"`
+}
+
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type responseFormat struct {
+	Type string `json:"type"`
+}
+
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+}
+
+type feedback struct {
+	Score    int    `json:"score"`
+	Summary  string `json:"summary"`
+	Elements []struct {
+		Level     uint8    `json:"level"`
+		Score     int      `json:"score"`
+		Hits      []string `json:"hits"`
+		Missing   []string `json:"missing"`
+		Highlight bool     `json:"highlight"`
+		Comment   string   `json:"comment"`
+	} `json:"elements"`
+}
+
+func (f feedback) toDomain() domain.Feedback {
+	res := domain.Feedback{
+		Score:    f.Score,
+		Summary:  f.Summary,
+		Elements: make([]domain.ElementFeedback, 0, len(f.Elements)),
+	}
+	for _, e := range f.Elements {
+		res.Elements = append(res.Elements, domain.ElementFeedback{
+			Level:     domain.Level(e.Level),
+			Score:     e.Score,
+			Hits:      e.Hits,
+			Missing:   e.Missing,
+			Highlight: e.Highlight,
+			Comment:   e.Comment,
+		})
+	}
+	return res
+}
@@ -0,0 +1,74 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package llm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+)
+
+// Provider 大模型的抽象，不同的厂商各自实现，测试的时候使用 FakeProvider
+type Provider interface {
+	// Name 记录评估结果是由哪个实现给出的
+	Name() string
+	Evaluate(ctx context.Context, req Request) (domain.Feedback, error)
+}
+
+type Request struct {
+	Question baguwen.Question
+	// Answer 用户自己写的答案
+	Answer string
+}
+
+// Element 用来对比的答案层级
+type Element struct {
+	Level domain.Level
+	baguwen.AnswerElement
+}
+
+// Elements 答案里面需要对比的层级，没有内容也没有关键字的层级会被跳过
+func (r Request) Elements() []Element {
+	ans := r.Question.Answer
+	all := []Element{
+		{Level: domain.LevelBasic, AnswerElement: ans.Basic},
+		{Level: domain.LevelIntermediate, AnswerElement: ans.Intermediate},
+		{Level: domain.LevelAdvanced, AnswerElement: ans.Advanced},
+	}
+	res := make([]Element, 0, len(all))
+	for _, e := range all {
+		if strings.TrimSpace(e.Content) != "" || strings.TrimSpace(e.Keywords) != "" {
+			res = append(res, e)
+		}
+	}
+	return res
+}
+
+// splitKeywords 关键字和亮点都是用户随手写的，各种分隔符都有可能
+func splitKeywords(s string) []string {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return strings.ContainsRune(",，、;；\n", r)
+	})
+	res := make([]string, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			res = append(res, f)
+		}
+	}
+	return res
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/ego-component/egorm"
+)
+
+type EvaluationDAO interface {
+	Create(ctx context.Context, e AnswerEvaluation) (int64, error)
+	// Update 更新评估的结果
+	Update(ctx context.Context, e AnswerEvaluation) error
+	// List 用户对某道题的评估，最新的排在前面
+	List(ctx context.Context, uid, qid int64, offset, limit int) ([]AnswerEvaluation, error)
+	Count(ctx context.Context, uid, qid int64) (int64, error)
+}
+
+type EvaluationGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewEvaluationGORMDAO(db *egorm.Component) EvaluationDAO {
+	return &EvaluationGORMDAO{db: db}
+}
+
+func (dao *EvaluationGORMDAO) Create(ctx context.Context, e AnswerEvaluation) (int64, error) {
+	now := time.Now().UnixMilli()
+	e.Ctime, e.Utime = now, now
+	err := dao.db.WithContext(ctx).Create(&e).Error
+	return e.Id, err
+}
+
+func (dao *EvaluationGORMDAO) Update(ctx context.Context, e AnswerEvaluation) error {
+	return dao.db.WithContext(ctx).Model(&AnswerEvaluation{}).
+		Where("id = ?", e.Id).
+		Updates(map[string]any{
+			"status":   e.Status,
+			"score":    e.Score,
+			"summary":  e.Summary,
+			"elements": e.Elements,
+			"utime":    time.Now().UnixMilli(),
+		}).Error
+}
+
+func (dao *EvaluationGORMDAO) List(ctx context.Context, uid, qid int64, offset, limit int) ([]AnswerEvaluation, error) {
+	var res []AnswerEvaluation
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND qid = ?", uid, qid).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *EvaluationGORMDAO) Count(ctx context.Context, uid, qid int64) (int64, error) {
+	var res int64
+	err := dao.db.WithContext(ctx).Model(&AnswerEvaluation{}).
+		Where("uid = ? AND qid = ?", uid, qid).
+		Count(&res).Error
+	return res, err
+}
+
+// AnswerEvaluation 对用户自己写的答案的一次评估
+type AnswerEvaluation struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Uid      int64  `gorm:"index:uid_qid"`
+	Qid      int64  `gorm:"index:uid_qid"`
+	Answer   string `gorm:"type:text"`
+	Provider string `gorm:"type:varchar(64)"`
+	Status   uint8
+	Score    int
+	Summary  string `gorm:"type:text"`
+	// 每个层级的对比结果
+	Elements sqlx.JsonColumn[[]Element]
+	Cost     uint64
+	Ctime    int64
+	Utime    int64
+}
+
+type Element struct {
+	Level     uint8    `json:"level"`
+	Score     int      `json:"score"`
+	Hits      []string `json:"hits"`
+	Missing   []string `json:"missing"`
+	Highlight bool     `json:"highlight"`
+	Comment   string   `json:"comment"`
+}
@@ -0,0 +1,127 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/repository/dao"
+	"golang.org/x/sync/errgroup"
+)
+
+type EvaluationRepository interface {
+	Create(ctx context.Context, e domain.Evaluation) (int64, error)
+	Update(ctx context.Context, e domain.Evaluation) error
+	List(ctx context.Context, uid, qid int64, offset, limit int) ([]domain.Evaluation, int64, error)
+}
+
+type evaluationRepository struct {
+	dao dao.EvaluationDAO
+}
+
+func NewEvaluationRepository(d dao.EvaluationDAO) EvaluationRepository {
+	return &evaluationRepository{dao: d}
+}
+
+func (repo *evaluationRepository) Create(ctx context.Context, e domain.Evaluation) (int64, error) {
+	return repo.dao.Create(ctx, repo.toEntity(e))
+}
+
+func (repo *evaluationRepository) Update(ctx context.Context, e domain.Evaluation) error {
+	return repo.dao.Update(ctx, repo.toEntity(e))
+}
+
+func (repo *evaluationRepository) List(ctx context.Context, uid, qid int64, offset, limit int) ([]domain.Evaluation, int64, error) {
+	var (
+		eg    errgroup.Group
+		es    []dao.AnswerEvaluation
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		es, err = repo.dao.List(ctx, uid, qid, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = repo.dao.Count(ctx, uid, qid)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, 0, err
+	}
+	return slice.Map(es, func(idx int, src dao.AnswerEvaluation) domain.Evaluation {
+		return repo.toDomain(src)
+	}), total, nil
+}
+
+func (repo *evaluationRepository) toEntity(e domain.Evaluation) dao.AnswerEvaluation {
+	return dao.AnswerEvaluation{
+		Id:       e.Id,
+		Uid:      e.Uid,
+		Qid:      e.Qid,
+		Answer:   e.Answer,
+		Provider: e.Provider,
+		Status:   uint8(e.Status),
+		Score:    e.Feedback.Score,
+		Summary:  e.Feedback.Summary,
+		Elements: sqlx.JsonColumn[[]dao.Element]{
+			Val: slice.Map(e.Feedback.Elements, func(idx int, src domain.ElementFeedback) dao.Element {
+				return dao.Element{
+					Level:     uint8(src.Level),
+					Score:     src.Score,
+					Hits:      src.Hits,
+					Missing:   src.Missing,
+					Highlight: src.Highlight,
+					Comment:   src.Comment,
+				}
+			}),
+			Valid: len(e.Feedback.Elements) > 0,
+		},
+		Cost: e.Cost,
+	}
+}
+
+func (repo *evaluationRepository) toDomain(e dao.AnswerEvaluation) domain.Evaluation {
+	return domain.Evaluation{
+		Id:       e.Id,
+		Uid:      e.Uid,
+		Qid:      e.Qid,
+		Answer:   e.Answer,
+		Provider: e.Provider,
+		Status:   domain.Status(e.Status),
+		Feedback: domain.Feedback{
+			Score:   e.Score,
+			Summary: e.Summary,
+			Elements: slice.Map(e.Elements.Val, func(idx int, src dao.Element) domain.ElementFeedback {
+				return domain.ElementFeedback{
+					Level:     domain.Level(src.Level),
+					Score:     src.Score,
+					Hits:      src.Hits,
+					Missing:   src.Missing,
+					Highlight: src.Highlight,
+					Comment:   src.Comment,
+				}
+			}),
+		},
+		Cost:  e.Cost,
+		Ctime: time.UnixMilli(e.Ctime),
+		Utime: time.UnixMilli(e.Utime),
+	}
+}
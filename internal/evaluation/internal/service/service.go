@@ -0,0 +1,189 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/ecodeclub/webook/internal/credit"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/limiter"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/llm"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/repository"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+var (
+	ErrInvalidAnswer    = errors.New("回答内容非法")
+	ErrRateLimited      = errors.New("评估太频繁")
+	ErrQuestionNotFound = errors.New("问题不存在")
+	ErrCreditNotEnough  = credit.ErrCreditNotEnough
+)
+
+const (
+	maxAnswerLen = 5000
+	// 积分流水的业务类型
+	creditBiz = 11
+)
+
+type Config struct {
+	// Cost 每次评估消耗的积分，为 0 的时候免费评估，不会调用积分服务
+	Cost uint64
+}
+
+type Service interface {
+	// Evaluate 评估用户自己写的答案，会先预扣积分，评估失败的时候退回，免费评估不会调用积分服务
+	Evaluate(ctx context.Context, uid, qid int64, answer string) (domain.Evaluation, error)
+	// List 用户对某道题的历史评估
+	List(ctx context.Context, uid, qid int64, offset, limit int) ([]domain.Evaluation, int64, error)
+}
+
+type service struct {
+	repo      repository.EvaluationRepository
+	provider  llm.Provider
+	limiter   limiter.Limiter
+	queSvc    baguwen.Service
+	creditSvc credit.Service
+	cfg       Config
+	logger    *elog.Component
+}
+
+func NewService(repo repository.EvaluationRepository,
+	provider llm.Provider,
+	l limiter.Limiter,
+	queSvc baguwen.Service,
+	creditSvc credit.Service,
+	cfg Config) Service {
+	return &service{
+		repo:      repo,
+		provider:  provider,
+		limiter:   l,
+		queSvc:    queSvc,
+		creditSvc: creditSvc,
+		cfg:       cfg,
+		logger:    elog.DefaultLogger,
+	}
+}
+
+func (s *service) Evaluate(ctx context.Context, uid, qid int64, answer string) (domain.Evaluation, error) {
+	if answer == "" || utf8.RuneCountInString(answer) > maxAnswerLen {
+		return domain.Evaluation{}, ErrInvalidAnswer
+	}
+	ques, err := s.queSvc.GetPubByIDs(ctx, []int64{qid})
+	if err != nil {
+		return domain.Evaluation{}, err
+	}
+	if len(ques) == 0 {
+		return domain.Evaluation{}, fmt.Errorf("%w: qid %d", ErrQuestionNotFound, qid)
+	}
+	que := ques[0]
+	limited, err := s.limiter.Limit(ctx, uid)
+	if err != nil {
+		return domain.Evaluation{}, err
+	}
+	if limited {
+		return domain.Evaluation{}, ErrRateLimited
+	}
+	e := domain.Evaluation{
+		Uid:      uid,
+		Qid:      qid,
+		Answer:   answer,
+		Provider: s.provider.Name(),
+		Status:   domain.StatusPending,
+		Cost:     s.cfg.Cost,
+	}
+	// 先落库，用评估的 id 作为积分流水的去重 key
+	e.Id, err = s.repo.Create(ctx, e)
+	if err != nil {
+		return domain.Evaluation{}, err
+	}
+	tid, err := s.tryDeduct(ctx, e)
+	if err != nil {
+		s.fail(ctx, e)
+		return domain.Evaluation{}, err
+	}
+	e.Feedback, err = s.provider.Evaluate(ctx, llm.Request{Question: que, Answer: answer})
+	if err != nil {
+		s.fail(ctx, e)
+		s.cancelDeduct(ctx, e, tid)
+		return domain.Evaluation{}, err
+	}
+	e.Status = domain.StatusSucceeded
+	if err = s.repo.Update(ctx, e); err != nil {
+		s.cancelDeduct(ctx, e, tid)
+		return domain.Evaluation{}, err
+	}
+	s.confirmDeduct(ctx, e, tid)
+	return e, nil
+}
+
+// tryDeduct 预扣评估消耗的积分，免费评估不需要经过积分服务
+func (s *service) tryDeduct(ctx context.Context, e domain.Evaluation) (int64, error) {
+	if e.Cost == 0 {
+		return 0, nil
+	}
+	return s.creditSvc.TryDeductCredits(ctx, credit.Credit{
+		Uid:          e.Uid,
+		ChangeAmount: e.Cost,
+		Logs: []credit.CreditLog{
+			{
+				Key:    fmt.Sprintf("evaluation:%d", e.Id),
+				Biz:    creditBiz,
+				BizId:  e.Id,
+				Action: "评估答案",
+			},
+		},
+	})
+}
+
+// confirmDeduct 评估结果已经给出去了，扣积分失败只记录日志，后续人工处理
+func (s *service) confirmDeduct(ctx context.Context, e domain.Evaluation, tid int64) {
+	if e.Cost == 0 {
+		return
+	}
+	if err := s.creditSvc.ConfirmDeductCredits(ctx, e.Uid, tid); err != nil {
+		s.logger.Error("确认扣除评估答案的积分失败",
+			elog.FieldErr(err),
+			elog.Int64("uid", e.Uid),
+			elog.Int64("tid", tid))
+	}
+}
+
+func (s *service) cancelDeduct(ctx context.Context, e domain.Evaluation, tid int64) {
+	if e.Cost == 0 {
+		return
+	}
+	if err := s.creditSvc.CancelDeductCredits(ctx, e.Uid, tid); err != nil {
+		s.logger.Error("退回评估答案预扣的积分失败",
+			elog.FieldErr(err),
+			elog.Int64("uid", e.Uid),
+			elog.Int64("tid", tid))
+	}
+}
+
+func (s *service) fail(ctx context.Context, e domain.Evaluation) {
+	e.Status = domain.StatusFailed
+	if err := s.repo.Update(ctx, e); err != nil {
+		s.logger.Error("更新评估状态失败", elog.FieldErr(err), elog.Int64("id", e.Id))
+	}
+}
+
+func (s *service) List(ctx context.Context, uid, qid int64, offset, limit int) ([]domain.Evaluation, int64, error) {
+	return s.repo.List(ctx, uid, qid, offset, limit)
+}
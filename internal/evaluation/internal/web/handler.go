@@ -0,0 +1,81 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const maxLimit = 100
+
+type Handler struct {
+	svc service.Service
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) MemberRoutes(server *gin.Engine) {
+	g := server.Group("/evaluation")
+	g.POST("/evaluate", ginx.BS[EvaluateReq](h.Evaluate))
+	g.POST("/list", ginx.BS[ListReq](h.List))
+}
+
+// Evaluate 把用户自己写的答案和参考答案对比
+func (h *Handler) Evaluate(ctx *ginx.Context, req EvaluateReq, sess session.Session) (ginx.Result, error) {
+	e, err := h.svc.Evaluate(ctx, sess.Claims().Uid, req.Qid, req.Answer)
+	switch {
+	case errors.Is(err, service.ErrInvalidAnswer):
+		return invalidAnswerResult, nil
+	case errors.Is(err, service.ErrRateLimited):
+		return rateLimitedResult, nil
+	case errors.Is(err, service.ErrCreditNotEnough):
+		return creditNotEnoughResult, nil
+	case errors.Is(err, service.ErrQuestionNotFound):
+		return questionNotFoundResult, nil
+	case err != nil:
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: newEvaluation(e),
+	}, nil
+}
+
+// List 某道题的历史评估，方便对比自己的进步
+func (h *Handler) List(ctx *ginx.Context, req ListReq, sess session.Session) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	es, total, err := h.svc.List(ctx, sess.Claims().Uid, req.Qid, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: EvaluationList{
+			Total: total,
+			Evaluations: slice.Map(es, func(idx int, src domain.Evaluation) Evaluation {
+				return newEvaluation(src)
+			}),
+		},
+	}, nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/domain"
+)
+
+type EvaluateReq struct {
+	Qid    int64  `json:"qid"`
+	Answer string `json:"answer"`
+}
+
+type ListReq struct {
+	Qid    int64 `json:"qid"`
+	Offset int   `json:"offset,omitempty"`
+	Limit  int   `json:"limit,omitempty"`
+}
+
+type Evaluation struct {
+	Id       int64     `json:"id"`
+	Qid      int64     `json:"qid"`
+	Answer   string    `json:"answer"`
+	Status   uint8     `json:"status"`
+	Score    int       `json:"score"`
+	Summary  string    `json:"summary"`
+	Elements []Element `json:"elements,omitempty"`
+	Cost     uint64    `json:"cost"`
+	Ctime    int64     `json:"ctime"`
+}
+
+type Element struct {
+	Level     uint8    `json:"level"`
+	Score     int      `json:"score"`
+	Hits      []string `json:"hits"`
+	Missing   []string `json:"missing"`
+	Highlight bool     `json:"highlight"`
+	Comment   string   `json:"comment"`
+}
+
+type EvaluationList struct {
+	Total       int64        `json:"total"`
+	Evaluations []Evaluation `json:"evaluations"`
+}
+
+func newEvaluation(e domain.Evaluation) Evaluation {
+	return Evaluation{
+		Id:      e.Id,
+		Qid:     e.Qid,
+		Answer:  e.Answer,
+		Status:  uint8(e.Status),
+		Score:   e.Feedback.Score,
+		Summary: e.Feedback.Summary,
+		Elements: slice.Map(e.Feedback.Elements, func(idx int, src domain.ElementFeedback) Element {
+			return Element{
+				Level:     uint8(src.Level),
+				Score:     src.Score,
+				Hits:      src.Hits,
+				Missing:   src.Missing,
+				Highlight: src.Highlight,
+				Comment:   src.Comment,
+			}
+		}),
+		Cost:  e.Cost,
+		Ctime: e.Ctime.UnixMilli(),
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package evaluation
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/credit"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/limiter"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/llm"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/repository"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/service"
+	"github.com/ecodeclub/webook/internal/evaluation/internal/web"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitModule(db *egorm.Component,
+	cmd redis.Cmdable,
+	queModule *baguwen.Module,
+	creditSvc credit.Service) *Module {
+	wire.Build(initConfig, initProvider, InitModuleWithProvider)
+	return new(Module)
+}
+
+// InitModuleWithProvider 测试的时候可以传入本地的实现
+func InitModuleWithProvider(db *egorm.Component,
+	cmd redis.Cmdable,
+	queModule *baguwen.Module,
+	creditSvc credit.Service,
+	provider llm.Provider,
+	cfg Config) *Module {
+	wire.Build(
+		initDAO,
+		repository.NewEvaluationRepository,
+		initLimiter,
+		wire.FieldsOf(new(*baguwen.Module), "Svc"),
+		wire.FieldsOf(new(Config), "Service"),
+		service.NewService,
+		web.NewHandler,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module)
+}
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.EvaluationDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewEvaluationGORMDAO(db)
+}
+
+func initLimiter(cmd redis.Cmdable, cfg Config) limiter.Limiter {
+	return limiter.NewFixedWindowLimiter(cmd, "webook:evaluation:limit", cfg.Window, cfg.Rate)
+}
+
+// initConfig 没有配置的时候，每次评估消耗 10 积分，每个用户每小时最多评估 10 次。
+// 本地的实现只按照关键字打分，不消耗积分
+func initConfig() Config {
+	type evaluationConfig struct {
+		Cost     uint64        `yaml:"cost"`
+		Window   time.Duration `yaml:"window"`
+		Rate     int64         `yaml:"rate"`
+		Provider string        `yaml:"provider"`
+	}
+	cfg := evaluationConfig{
+		Cost:     10,
+		Window:   time.Hour,
+		Rate:     10,
+		Provider: "fake",
+	}
+	err := econf.UnmarshalKey("evaluation", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Provider == "fake" {
+		cfg.Cost = 0
+	}
+	return Config{
+		Service: service.Config{Cost: cfg.Cost},
+		Window:  cfg.Window,
+		Rate:    cfg.Rate,
+	}
+}
+
+// initProvider 没有配置的时候使用本地的实现，只按照关键字打分
+func initProvider() llm.Provider {
+	type OpenAIConfig struct {
+		BaseURL string        `yaml:"baseURL"`
+		APIKey  string        `yaml:"apiKey"`
+		Model   string        `yaml:"model"`
+		Timeout time.Duration `yaml:"timeout"`
+	}
+	type providerConfig struct {
+		Provider string       `yaml:"provider"`
+		OpenAI   OpenAIConfig `yaml:"openai"`
+	}
+	cfg := providerConfig{
+		Provider: "fake",
+		OpenAI:   OpenAIConfig{Timeout: time.Minute},
+	}
+	err := econf.UnmarshalKey("evaluation", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	switch cfg.Provider {
+	case "fake":
+		return llm.NewFakeProvider()
+	case "openai":
+		return llm.NewOpenAIProvider(&http.Client{Timeout: cfg.OpenAI.Timeout},
+			cfg.OpenAI.BaseURL, cfg.OpenAI.APIKey, cfg.OpenAI.Model)
+	default:
+		panic(fmt.Sprintf("未知的大模型实现 %s", cfg.Provider))
+	}
+}
+
+// Config 评估的积分消耗和限流
+type Config struct {
+	Service service.Config
+	// 每个用户在 Window 时间内最多评估 Rate 次
+	Window time.Duration
+	Rate   int64
+}
+
+type Handler = web.Handler
+type Service = service.Service
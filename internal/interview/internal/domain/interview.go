@@ -0,0 +1,170 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	BizQuestion = "question"
+	BizCase     = "case"
+)
+
+// Level 题目在技能里面的等级，按照标签生成的时候没有等级
+type Level uint8
+
+const (
+	LevelNone Level = iota
+	LevelBasic
+	LevelIntermediate
+	LevelAdvanced
+)
+
+// Element 答案的组成部分，题目有分析、基本、中级、高级四个部分，案例只有一个部分
+type Element uint8
+
+const (
+	ElementAnalysis Element = iota + 1
+	ElementBasic
+	ElementIntermediate
+	ElementAdvanced
+)
+
+// Elements 某一类内容需要自评的部分
+func Elements(biz string) []Element {
+	if biz == BizCase {
+		return []Element{ElementAnalysis}
+	}
+	return []Element{ElementAnalysis, ElementBasic, ElementIntermediate, ElementAdvanced}
+}
+
+// Status 自评结果
+type Status uint8
+
+const (
+	StatusUnknown  Status = iota + 1 // 不会
+	StatusFuzzy                      // 模糊
+	StatusMastered                   // 掌握
+)
+
+func (s Status) Valid() bool {
+	return s >= StatusUnknown && s <= StatusMastered
+}
+
+// InterviewStatus 模拟面试的状态
+type InterviewStatus uint8
+
+const (
+	InterviewStatusInProgress InterviewStatus = iota + 1
+	InterviewStatusFinished
+)
+
+// Source 生成试卷的来源，技能或者标签二选一
+type Source struct {
+	Sid  int64
+	Lids []int64
+}
+
+func (s Source) Biz() string {
+	if s.Sid > 0 {
+		return "skill"
+	}
+	return "label"
+}
+
+// BizId 按照技能生成的时候是技能 ID，按照标签生成的时候是第一个标签的 ID，
+// 方便按照来源查看历史记录
+func (s Source) BizId() int64 {
+	if s.Sid > 0 || len(s.Lids) == 0 {
+		return s.Sid
+	}
+	return s.Lids[0]
+}
+
+// Item 试卷上的一道题目或者一个案例
+type Item struct {
+	Biz   string
+	BizId int64
+	Level Level
+}
+
+// Grade 用户对某个条目某一部分的自评
+type Grade struct {
+	Biz     string
+	BizId   int64
+	Element Element
+	Status  Status
+}
+
+// Interview 一次模拟面试，试卷在开始的时候就确定下来
+type Interview struct {
+	Id       int64
+	Uid      int64
+	Source   Source
+	Duration time.Duration
+	Items    []Item
+	Grades   []Grade
+	Status   InterviewStatus
+	// Score 结束的时候计算的得分，0 - 100
+	Score    int
+	Ctime    time.Time
+	Deadline time.Time
+	Utime    time.Time
+}
+
+// Expired 超过了限定时间就不能再自评了
+func (i Interview) Expired(now time.Time) bool {
+	return now.After(i.Deadline)
+}
+
+func (i Interview) Contains(biz string, bizId int64) bool {
+	for _, item := range i.Items {
+		if item.Biz == biz && item.BizId == bizId {
+			return true
+		}
+	}
+	return false
+}
+
+// CalcScore 掌握算 2 分，模糊算 1 分，不会以及没有自评的都不算分，最后折算成百分制
+func (i Interview) CalcScore() int {
+	type key struct {
+		biz     string
+		bizId   int64
+		element Element
+	}
+	grades := make(map[key]Status, len(i.Grades))
+	for _, g := range i.Grades {
+		grades[key{biz: g.Biz, bizId: g.BizId, element: g.Element}] = g.Status
+	}
+	total, got := 0, 0
+	for _, item := range i.Items {
+		for _, e := range Elements(item.Biz) {
+			total += 2
+			switch grades[key{biz: item.Biz, bizId: item.BizId, element: e}] {
+			case StatusMastered:
+				got += 2
+			case StatusFuzzy:
+				got++
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return int(math.Round(float64(got) * 100 / float64(total)))
+}
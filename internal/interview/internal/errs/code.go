@@ -0,0 +1,14 @@
+package errs
+
+var (
+	SystemError       = ErrorCode{Code: 515001, Msg: "系统错误"}
+	InterviewNotFound = ErrorCode{Code: 515002, Msg: "模拟面试不存在"}
+	InvalidInput      = ErrorCode{Code: 515003, Msg: "输入错误"}
+	EmptySheet        = ErrorCode{Code: 515004, Msg: "没有可以用来模拟面试的题目"}
+	InterviewEnded    = ErrorCode{Code: 515005, Msg: "模拟面试已经结束"}
+)
+
+type ErrorCode struct {
+	Code int
+	Msg  string
+}
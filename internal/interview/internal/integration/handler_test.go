@@ -0,0 +1,409 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/cases"
+	casemocks "github.com/ecodeclub/webook/internal/cases/mocks"
+	"github.com/ecodeclub/webook/internal/interview"
+	"github.com/ecodeclub/webook/internal/interview/internal/domain"
+	"github.com/ecodeclub/webook/internal/interview/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/interview/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/interview/internal/web"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	quemocks "github.com/ecodeclub/webook/internal/question/mocks"
+	"github.com/ecodeclub/webook/internal/skill"
+	skillmocks "github.com/ecodeclub/webook/internal/skill/mocks"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const uid = 5001
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server *egin.Component
+	db     *egorm.Component
+	svc    interview.Service
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	skillSvc := skillmocks.NewMockSkillService(ctrl)
+	skillSvc.EXPECT().Info(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, id int64) (skill.Skill, error) {
+			switch id {
+			case 1:
+				return skill.Skill{
+					ID:           1,
+					Basic:        skill.SkillLevel{Questions: []int64{1, 2}},
+					Intermediate: skill.SkillLevel{Questions: []int64{3}},
+					Advanced:     skill.SkillLevel{Cases: []int64{1}},
+				}, nil
+			case 2:
+				// 全部都没有发布
+				return skill.Skill{
+					ID:    2,
+					Basic: skill.SkillLevel{Questions: []int64{2}},
+				}, nil
+			default:
+				return skill.Skill{}, errors.New("技能不存在")
+			}
+		}).AnyTimes()
+
+	queSvc := quemocks.NewMockService(ctrl)
+	// 2 号题目没有发布
+	queSvc.EXPECT().GetPubByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, ids []int64) ([]baguwen.Question, error) {
+			res := make([]baguwen.Question, 0, len(ids))
+			for _, id := range ids {
+				if id != 2 {
+					res = append(res, baguwen.Question{Id: id, Title: fmt.Sprintf("题目%d", id)})
+				}
+			}
+			return res, nil
+		}).AnyTimes()
+	queSvc.EXPECT().PubListByLabels(gomock.Any(), gomock.Any(), false, 0, gomock.Any()).
+		Return([]baguwen.Question{{Id: 5}, {Id: 6}}, int64(2), nil).AnyTimes()
+
+	caseSvc := casemocks.NewMockService(ctrl)
+	caseSvc.EXPECT().GetPubByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, ids []int64) ([]cases.Case, error) {
+			res := make([]cases.Case, 0, len(ids))
+			for _, id := range ids {
+				res = append(res, cases.Case{Id: id, Title: fmt.Sprintf("案例%d", id)})
+			}
+			return res, nil
+		}).AnyTimes()
+	caseSvc.EXPECT().PubListByLabels(gomock.Any(), gomock.Any(), false, 0, gomock.Any()).
+		Return([]cases.Case{}, int64(0), nil).AnyTimes()
+
+	module := startup.InitModule(&skill.Module{Svc: skillSvc},
+		&baguwen.Module{Svc: queSvc},
+		&cases.Module{Svc: caseSvc})
+	s.svc = module.Svc
+
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid: uid,
+		}))
+	})
+	module.Hdl.MemberRoutes(server.Engine)
+	s.server = server
+	s.db = testioc.InitDB()
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `mock_interviews`").Error
+	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `mock_interview_grades`").Error
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TestStart() {
+	testCases := []struct {
+		name     string
+		req      web.StartReq
+		wantCode int
+		wantResp test.Result[web.Interview]
+	}{
+		{
+			name:     "按照技能生成",
+			req:      web.StartReq{Sid: 1, Lids: []int64{1}, Duration: 20},
+			wantCode: 200,
+			wantResp: test.Result[web.Interview]{
+				Data: web.Interview{
+					Biz:      "skill",
+					BizId:    1,
+					Duration: 20,
+					// 基础占一半的时间，用不完的时间留给后面的等级
+					Items: []web.Item{
+						{Biz: "question", BizId: 1, Level: 1, Title: "题目1"},
+						{Biz: "question", BizId: 3, Level: 2, Title: "题目3"},
+						{Biz: "case", BizId: 1, Level: 3, Title: "案例1"},
+					},
+					Status: 1,
+				},
+			},
+		},
+		{
+			name:     "按照标签生成",
+			req:      web.StartReq{Lids: []int64{1, 2}, Duration: 10},
+			wantCode: 200,
+			wantResp: test.Result[web.Interview]{
+				Data: web.Interview{
+					Biz:      "label",
+					BizId:    1,
+					Lids:     []int64{1, 2},
+					Duration: 10,
+					Items: []web.Item{
+						{Biz: "question", BizId: 5, Title: "题目5"},
+						{Biz: "question", BizId: 6, Title: "题目6"},
+					},
+					Status: 1,
+				},
+			},
+		},
+		{
+			name:     "没有来源",
+			req:      web.StartReq{Duration: 20},
+			wantCode: 500,
+			wantResp: test.Result[web.Interview]{Code: 515003, Msg: "输入错误"},
+		},
+		{
+			name:     "时间太短",
+			req:      web.StartReq{Sid: 1, Duration: 5},
+			wantCode: 500,
+			wantResp: test.Result[web.Interview]{Code: 515003, Msg: "输入错误"},
+		},
+		{
+			name:     "没有发布的题目",
+			req:      web.StartReq{Sid: 2, Duration: 20},
+			wantCode: 500,
+			wantResp: test.Result[web.Interview]{Code: 515004, Msg: "没有可以用来模拟面试的题目"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/interview/start", iox.NewJSONReader(tc.req))
+			require.NoError(t, err)
+			req.Header.Set("content-type", "application/json")
+			recorder := test.NewJSONResponseRecorder[web.Interview]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			resp := recorder.MustScan()
+			if resp.Code == 0 {
+				assert.True(t, resp.Data.Id > 0)
+				assert.Equal(t, int64(tc.req.Duration)*60000, resp.Data.Deadline-resp.Data.Ctime)
+				assert.ElementsMatch(t, tc.wantResp.Data.Items, resp.Data.Items)
+				resp.Data.Id, resp.Data.Ctime, resp.Data.Deadline, resp.Data.Utime = 0, 0, 0, 0
+				resp.Data.Items = tc.wantResp.Data.Items
+			}
+			assert.Equal(t, tc.wantResp, resp)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestGrade() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	i, err := s.svc.Start(ctx, uid, domain.Source{Sid: 1}, time.Minute*20)
+	require.NoError(s.T(), err)
+	other, err := s.svc.Start(ctx, uid+1, domain.Source{Sid: 1}, time.Minute*20)
+	require.NoError(s.T(), err)
+
+	testCases := []struct {
+		name     string
+		req      web.GradeReq
+		wantCode int
+		wantResp test.Result[any]
+	}{
+		{
+			name:     "题目掌握",
+			req:      web.GradeReq{Id: i.Id, Biz: "question", BizId: 1, Element: 1, Status: 3},
+			wantCode: 200,
+		},
+		{
+			name:     "题目不会",
+			req:      web.GradeReq{Id: i.Id, Biz: "question", BizId: 1, Element: 2, Status: 1},
+			wantCode: 200,
+		},
+		{
+			name:     "覆盖之前的自评",
+			req:      web.GradeReq{Id: i.Id, Biz: "question", BizId: 1, Element: 2, Status: 2},
+			wantCode: 200,
+		},
+		{
+			name:     "案例掌握",
+			req:      web.GradeReq{Id: i.Id, Biz: "case", BizId: 1, Element: 1, Status: 3},
+			wantCode: 200,
+		},
+		{
+			name:     "案例只有一个部分",
+			req:      web.GradeReq{Id: i.Id, Biz: "case", BizId: 1, Element: 2, Status: 3},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 515003, Msg: "输入错误"},
+		},
+		{
+			name:     "不在试卷上",
+			req:      web.GradeReq{Id: i.Id, Biz: "question", BizId: 2, Element: 1, Status: 3},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 515003, Msg: "输入错误"},
+		},
+		{
+			name:     "自评非法",
+			req:      web.GradeReq{Id: i.Id, Biz: "question", BizId: 3, Element: 1, Status: 4},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 515003, Msg: "输入错误"},
+		},
+		{
+			name:     "别人的模拟面试",
+			req:      web.GradeReq{Id: other.Id, Biz: "question", BizId: 1, Element: 1, Status: 3},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 515002, Msg: "模拟面试不存在"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			s.post(t, "/interview/grade", tc.req, tc.wantCode, tc.wantResp)
+		})
+	}
+
+	// 交卷，总共 9 个部分，满分 18 分，得了 5 分
+	detail := s.detail("/interview/finish", i.Id)
+	assert.Equal(s.T(), uint8(2), detail.Status)
+	assert.Equal(s.T(), 28, detail.Score)
+	assert.Equal(s.T(), []web.Item{
+		{Biz: "question", BizId: 1, Level: 1, Title: "题目1", Grades: []web.Grade{
+			{Element: 1, Status: 3},
+			{Element: 2, Status: 2},
+		}},
+		{Biz: "question", BizId: 3, Level: 2, Title: "题目3"},
+		{Biz: "case", BizId: 1, Level: 3, Title: "案例1", Grades: []web.Grade{
+			{Element: 1, Status: 3},
+		}},
+	}, detail.Items)
+
+	// 重复交卷不会重新计算
+	again := s.detail("/interview/finish", i.Id)
+	again.Utime = detail.Utime
+	assert.Equal(s.T(), detail, again)
+	s.post(s.T(), "/interview/grade",
+		web.GradeReq{Id: i.Id, Biz: "question", BizId: 3, Element: 1, Status: 3},
+		500, test.Result[any]{Code: 515005, Msg: "模拟面试已经结束"})
+}
+
+func (s *HandlerTestSuite) TestTimeout() {
+	now := time.Now()
+	i := dao.MockInterview{
+		Uid:      uid,
+		Biz:      "skill",
+		BizId:    1,
+		Duration: time.Minute.Milliseconds() * 10,
+		Items:    sqlxItems(dao.Item{Biz: "question", BizId: 1, Level: 1}),
+		Status:   dao.StatusInProgress,
+		Ctime:    now.Add(-time.Minute * 11).UnixMilli(),
+		Deadline: now.Add(-time.Minute).UnixMilli(),
+		Utime:    now.Add(-time.Minute * 11).UnixMilli(),
+	}
+	require.NoError(s.T(), s.db.Create(&i).Error)
+	require.NoError(s.T(), s.db.Create(&dao.MockInterviewGrade{
+		Iid: i.Id, Biz: "question", BizId: 1, Element: 1, Status: 3,
+	}).Error)
+
+	s.post(s.T(), "/interview/grade",
+		web.GradeReq{Id: i.Id, Biz: "question", BizId: 1, Element: 2, Status: 3},
+		500, test.Result[any]{Code: 515005, Msg: "模拟面试已经结束"})
+	// 超时之后自动交卷
+	detail := s.detail("/interview/detail", i.Id)
+	assert.Equal(s.T(), uint8(2), detail.Status)
+	assert.Equal(s.T(), 25, detail.Score)
+}
+
+func (s *HandlerTestSuite) TestList() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	for idx := 0; idx < 3; idx++ {
+		_, err := s.svc.Start(ctx, uid, domain.Source{Sid: 1}, time.Minute*20)
+		require.NoError(s.T(), err)
+	}
+	_, err := s.svc.Start(ctx, uid, domain.Source{Lids: []int64{1}}, time.Minute*10)
+	require.NoError(s.T(), err)
+	_, err = s.svc.Start(ctx, uid+1, domain.Source{Sid: 1}, time.Minute*20)
+	require.NoError(s.T(), err)
+
+	testCases := []struct {
+		name      string
+		req       web.ListReq
+		wantTotal int64
+		wantLen   int
+	}{
+		{name: "全部", req: web.ListReq{Limit: 10}, wantTotal: 4, wantLen: 4},
+		{name: "同一个技能", req: web.ListReq{Biz: "skill", BizId: 1, Limit: 2}, wantTotal: 3, wantLen: 2},
+		{name: "分页", req: web.ListReq{Biz: "skill", BizId: 1, Offset: 2, Limit: 2}, wantTotal: 3, wantLen: 1},
+		{name: "同一个标签", req: web.ListReq{Biz: "label", BizId: 1, Limit: 10}, wantTotal: 1, wantLen: 1},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/interview/list", iox.NewJSONReader(tc.req))
+			require.NoError(t, err)
+			req.Header.Set("content-type", "application/json")
+			recorder := test.NewJSONResponseRecorder[web.InterviewList]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, 200, recorder.Code)
+			data := recorder.MustScan().Data
+			assert.Equal(t, tc.wantTotal, data.Total)
+			assert.Equal(t, tc.wantLen, len(data.Interviews))
+			for _, i := range data.Interviews {
+				// 列表里面不需要试卷
+				assert.Empty(t, i.Items)
+			}
+		})
+	}
+}
+
+func (s *HandlerTestSuite) post(t *testing.T, path string, body any, wantCode int, wantResp test.Result[any]) {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, wantCode, recorder.Code)
+	assert.Equal(t, wantResp, recorder.MustScan())
+}
+
+func (s *HandlerTestSuite) detail(path string, id int64) web.Interview {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(web.IdReq{Id: id}))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.Interview]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func sqlxItems(items ...dao.Item) sqlx.JsonColumn[[]dao.Item] {
+	return sqlx.JsonColumn[[]dao.Item]{Val: items, Valid: true}
+}
+
+func TestInterviewHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,156 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type InterviewDAO interface {
+	Create(ctx context.Context, i MockInterview) (int64, error)
+	// Get 只能获取 uid 自己的模拟面试
+	Get(ctx context.Context, uid, id int64) (MockInterview, error)
+	// Grade 覆盖之前的自评，并且更新模拟面试的更新时间
+	Grade(ctx context.Context, g MockInterviewGrade) error
+	FindGrades(ctx context.Context, iid int64) ([]MockInterviewGrade, error)
+	// Finish 结束模拟面试，已经结束了的不会重复计算得分，这个时候返回 false
+	Finish(ctx context.Context, uid, id int64, score int) (bool, error)
+	// List 按照开始时间倒序排列，biz 为空的时候不过滤来源
+	List(ctx context.Context, uid int64, biz string, bizId int64, offset, limit int) ([]MockInterview, error)
+	Count(ctx context.Context, uid int64, biz string, bizId int64) (int64, error)
+}
+
+type InterviewGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewInterviewGORMDAO(db *egorm.Component) InterviewDAO {
+	return &InterviewGORMDAO{db: db}
+}
+
+func (dao *InterviewGORMDAO) Create(ctx context.Context, i MockInterview) (int64, error) {
+	now := time.Now().UnixMilli()
+	i.Ctime, i.Utime = now, now
+	err := dao.db.WithContext(ctx).Create(&i).Error
+	return i.Id, err
+}
+
+func (dao *InterviewGORMDAO) Get(ctx context.Context, uid, id int64) (MockInterview, error) {
+	var i MockInterview
+	err := dao.db.WithContext(ctx).Where("id = ? AND uid = ?", id, uid).First(&i).Error
+	return i, err
+}
+
+func (dao *InterviewGORMDAO) Grade(ctx context.Context, g MockInterviewGrade) error {
+	now := time.Now().UnixMilli()
+	g.Ctime, g.Utime = now, now
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"status", "utime"}),
+		}).Create(&g).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&MockInterview{}).Where("id = ?", g.Iid).
+			Update("utime", now).Error
+	})
+}
+
+func (dao *InterviewGORMDAO) FindGrades(ctx context.Context, iid int64) ([]MockInterviewGrade, error) {
+	var res []MockInterviewGrade
+	err := dao.db.WithContext(ctx).Where("iid = ?", iid).
+		Order("id ASC").Find(&res).Error
+	return res, err
+}
+
+func (dao *InterviewGORMDAO) Finish(ctx context.Context, uid, id int64, score int) (bool, error) {
+	res := dao.db.WithContext(ctx).Model(&MockInterview{}).
+		Where("id = ? AND uid = ? AND status = ?", id, uid, StatusInProgress).
+		Updates(map[string]any{
+			"status": StatusFinished,
+			"score":  score,
+			"utime":  time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (dao *InterviewGORMDAO) List(ctx context.Context, uid int64, biz string, bizId int64, offset, limit int) ([]MockInterview, error) {
+	var res []MockInterview
+	err := dao.where(ctx, uid, biz, bizId).
+		Order("ctime DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *InterviewGORMDAO) Count(ctx context.Context, uid int64, biz string, bizId int64) (int64, error) {
+	var res int64
+	err := dao.where(ctx, uid, biz, bizId).Model(&MockInterview{}).Count(&res).Error
+	return res, err
+}
+
+func (dao *InterviewGORMDAO) where(ctx context.Context, uid int64, biz string, bizId int64) *gorm.DB {
+	db := dao.db.WithContext(ctx).Where("uid = ?", uid)
+	if biz != "" {
+		db = db.Where("biz = ? AND biz_id = ?", biz, bizId)
+	}
+	return db
+}
+
+const (
+	StatusInProgress uint8 = 1
+	StatusFinished   uint8 = 2
+)
+
+type MockInterview struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"index:uid_ctime"`
+	// 试卷的来源，技能或者标签
+	Biz   string `gorm:"type:varchar(64)"`
+	BizId int64
+	Lids  sqlx.JsonColumn[[]int64]
+	// 限定时间，单位是毫秒
+	Duration int64
+	Items    sqlx.JsonColumn[[]Item]
+	Status   uint8
+	Score    int
+	Deadline int64
+	Ctime    int64 `gorm:"index:uid_ctime"`
+	Utime    int64
+}
+
+type Item struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	Level uint8  `json:"level"`
+}
+
+// MockInterviewGrade 用户对试卷上某个条目某一部分的自评
+type MockInterviewGrade struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Iid     int64  `gorm:"uniqueIndex:iid_biz_element"`
+	Biz     string `gorm:"type:varchar(64);uniqueIndex:iid_biz_element"`
+	BizId   int64  `gorm:"uniqueIndex:iid_biz_element"`
+	Element uint8  `gorm:"uniqueIndex:iid_biz_element"`
+	Status  uint8
+	Ctime   int64
+	Utime   int64
+}
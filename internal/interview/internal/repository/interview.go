@@ -0,0 +1,151 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/ecodeclub/webook/internal/interview/internal/domain"
+	"github.com/ecodeclub/webook/internal/interview/internal/repository/dao"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+var ErrInterviewNotFound = errors.New("模拟面试不存在")
+
+type InterviewRepository interface {
+	Create(ctx context.Context, i domain.Interview) (int64, error)
+	// Get 包括全部的自评
+	Get(ctx context.Context, uid, id int64) (domain.Interview, error)
+	Grade(ctx context.Context, iid int64, g domain.Grade) error
+	// Finish 返回 false 代表模拟面试已经结束了
+	Finish(ctx context.Context, uid, id int64, score int) (bool, error)
+	// List 不包括自评
+	List(ctx context.Context, uid int64, biz string, bizId int64, offset, limit int) ([]domain.Interview, int64, error)
+}
+
+type interviewRepository struct {
+	dao dao.InterviewDAO
+}
+
+func NewInterviewRepository(d dao.InterviewDAO) InterviewRepository {
+	return &interviewRepository{dao: d}
+}
+
+func (repo *interviewRepository) Create(ctx context.Context, i domain.Interview) (int64, error) {
+	return repo.dao.Create(ctx, dao.MockInterview{
+		Uid:      i.Uid,
+		Biz:      i.Source.Biz(),
+		BizId:    i.Source.BizId(),
+		Lids:     sqlx.JsonColumn[[]int64]{Val: i.Source.Lids, Valid: len(i.Source.Lids) > 0},
+		Duration: i.Duration.Milliseconds(),
+		Items: sqlx.JsonColumn[[]dao.Item]{
+			Val: slice.Map(i.Items, func(idx int, src domain.Item) dao.Item {
+				return dao.Item{Biz: src.Biz, BizId: src.BizId, Level: uint8(src.Level)}
+			}),
+			Valid: true,
+		},
+		Status:   uint8(domain.InterviewStatusInProgress),
+		Deadline: i.Deadline.UnixMilli(),
+	})
+}
+
+func (repo *interviewRepository) Get(ctx context.Context, uid, id int64) (domain.Interview, error) {
+	i, err := repo.dao.Get(ctx, uid, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Interview{}, ErrInterviewNotFound
+	}
+	if err != nil {
+		return domain.Interview{}, err
+	}
+	grades, err := repo.dao.FindGrades(ctx, id)
+	if err != nil {
+		return domain.Interview{}, err
+	}
+	res := repo.toDomain(i)
+	res.Grades = slice.Map(grades, func(idx int, src dao.MockInterviewGrade) domain.Grade {
+		return domain.Grade{
+			Biz:     src.Biz,
+			BizId:   src.BizId,
+			Element: domain.Element(src.Element),
+			Status:  domain.Status(src.Status),
+		}
+	})
+	return res, nil
+}
+
+func (repo *interviewRepository) Grade(ctx context.Context, iid int64, g domain.Grade) error {
+	return repo.dao.Grade(ctx, dao.MockInterviewGrade{
+		Iid:     iid,
+		Biz:     g.Biz,
+		BizId:   g.BizId,
+		Element: uint8(g.Element),
+		Status:  uint8(g.Status),
+	})
+}
+
+func (repo *interviewRepository) Finish(ctx context.Context, uid, id int64, score int) (bool, error) {
+	return repo.dao.Finish(ctx, uid, id, score)
+}
+
+func (repo *interviewRepository) List(ctx context.Context, uid int64, biz string, bizId int64, offset, limit int) ([]domain.Interview, int64, error) {
+	var (
+		eg    errgroup.Group
+		is    []dao.MockInterview
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		is, err = repo.dao.List(ctx, uid, biz, bizId, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = repo.dao.Count(ctx, uid, biz, bizId)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, 0, err
+	}
+	return slice.Map(is, func(idx int, src dao.MockInterview) domain.Interview {
+		return repo.toDomain(src)
+	}), total, nil
+}
+
+func (repo *interviewRepository) toDomain(i dao.MockInterview) domain.Interview {
+	src := domain.Source{Lids: i.Lids.Val}
+	// 按照标签生成的时候 biz_id 是第一个标签
+	if i.Biz == "skill" {
+		src.Sid = i.BizId
+	}
+	return domain.Interview{
+		Id:       i.Id,
+		Uid:      i.Uid,
+		Source:   src,
+		Duration: time.Duration(i.Duration) * time.Millisecond,
+		Items: slice.Map(i.Items.Val, func(idx int, src dao.Item) domain.Item {
+			return domain.Item{Biz: src.Biz, BizId: src.BizId, Level: domain.Level(src.Level)}
+		}),
+		Status:   domain.InterviewStatus(i.Status),
+		Score:    i.Score,
+		Ctime:    time.UnixMilli(i.Ctime),
+		Deadline: time.UnixMilli(i.Deadline),
+		Utime:    time.UnixMilli(i.Utime),
+	}
+}
@@ -0,0 +1,304 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/interview/internal/domain"
+	"github.com/ecodeclub/webook/internal/interview/internal/repository"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/skill"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrInterviewNotFound = repository.ErrInterviewNotFound
+	ErrInvalidSource     = errors.New("必须指定技能或者标签")
+	ErrInvalidDuration   = errors.New("模拟面试的时长非法")
+	ErrInvalidGrade      = errors.New("自评非法")
+	ErrEmptySheet        = errors.New("没有可以用来模拟面试的题目")
+	ErrInterviewEnded    = errors.New("模拟面试已经结束")
+)
+
+const (
+	MinDuration = 10 * time.Minute
+	MaxDuration = 3 * time.Hour
+
+	// 回答一道题目和一个案例预计花费的时间
+	questionCost = 5 * time.Minute
+	caseCost     = 10 * time.Minute
+	// 按照标签生成的时候，最多从这么多题目和案例中挑选
+	maxCandidates = 200
+)
+
+// 按照技能生成的时候，各个等级占用的时间比例，剩下没有用完的时间留给下一个等级
+var levelWeights = []struct {
+	level  domain.Level
+	weight int64
+}{
+	{level: domain.LevelBasic, weight: 5},
+	{level: domain.LevelIntermediate, weight: 3},
+	{level: domain.LevelAdvanced, weight: 2},
+}
+
+type Service interface {
+	// Start 生成一张随机的试卷并开始计时
+	Start(ctx context.Context, uid int64, src domain.Source, duration time.Duration) (domain.Interview, error)
+	// Grade 对试卷上某个条目的某一部分自评，超时之后就不能自评了
+	Grade(ctx context.Context, uid, id int64, g domain.Grade) error
+	// Finish 结束模拟面试并计算得分，重复调用返回第一次的结果
+	Finish(ctx context.Context, uid, id int64) (domain.Interview, error)
+	// Detail 超时没有结束的模拟面试会在这里自动结束
+	Detail(ctx context.Context, uid, id int64) (domain.Interview, error)
+	// List 历史记录，biz 不为空的时候只返回同一个来源的，方便比较
+	List(ctx context.Context, uid int64, biz string, bizId int64, offset, limit int) ([]domain.Interview, int64, error)
+}
+
+type service struct {
+	repo     repository.InterviewRepository
+	skillSvc skill.Service
+	queSvc   baguwen.Service
+	caseSvc  cases.Service
+}
+
+func NewService(repo repository.InterviewRepository,
+	skillSvc skill.Service,
+	queSvc baguwen.Service,
+	caseSvc cases.Service) Service {
+	return &service{
+		repo:     repo,
+		skillSvc: skillSvc,
+		queSvc:   queSvc,
+		caseSvc:  caseSvc,
+	}
+}
+
+func (s *service) Start(ctx context.Context, uid int64, src domain.Source, duration time.Duration) (domain.Interview, error) {
+	if src.Sid <= 0 && len(src.Lids) == 0 {
+		return domain.Interview{}, ErrInvalidSource
+	}
+	if duration < MinDuration || duration > MaxDuration {
+		return domain.Interview{}, ErrInvalidDuration
+	}
+	var (
+		items []domain.Item
+		err   error
+	)
+	if src.Sid > 0 {
+		// 按照技能生成的时候不需要标签
+		src.Lids = nil
+		items, err = s.skillItems(ctx, src.Sid, duration)
+	} else {
+		items, err = s.labelItems(ctx, src.Lids, duration)
+	}
+	if err != nil {
+		return domain.Interview{}, err
+	}
+	if len(items) == 0 {
+		return domain.Interview{}, ErrEmptySheet
+	}
+	now := time.Now()
+	i := domain.Interview{
+		Uid:      uid,
+		Source:   src,
+		Duration: duration,
+		Items:    items,
+		Status:   domain.InterviewStatusInProgress,
+		Ctime:    now,
+		Deadline: now.Add(duration),
+		Utime:    now,
+	}
+	i.Id, err = s.repo.Create(ctx, i)
+	return i, err
+}
+
+func (s *service) skillItems(ctx context.Context, sid int64, duration time.Duration) ([]domain.Item, error) {
+	sk, err := s.skillSvc.Info(ctx, sid)
+	if err != nil {
+		return nil, err
+	}
+	pubQids, pubCids, err := s.published(ctx, sk.Questions(), sk.Cases())
+	if err != nil {
+		return nil, err
+	}
+	levels := map[domain.Level]skill.SkillLevel{
+		domain.LevelBasic:        sk.Basic,
+		domain.LevelIntermediate: sk.Intermediate,
+		domain.LevelAdvanced:     sk.Advanced,
+	}
+	var (
+		res    []domain.Item
+		remain time.Duration
+	)
+	for _, lw := range levelWeights {
+		sl := levels[lw.level]
+		candidates := make([]domain.Item, 0, len(sl.Questions)+len(sl.Cases))
+		for _, qid := range sl.Questions {
+			if pubQids[qid] {
+				candidates = append(candidates, domain.Item{Biz: domain.BizQuestion, BizId: qid, Level: lw.level})
+			}
+		}
+		for _, cid := range sl.Cases {
+			if pubCids[cid] {
+				candidates = append(candidates, domain.Item{Biz: domain.BizCase, BizId: cid, Level: lw.level})
+			}
+		}
+		var picked []domain.Item
+		picked, remain = s.pick(candidates, remain+duration*time.Duration(lw.weight)/10)
+		res = append(res, picked...)
+	}
+	return res, nil
+}
+
+// published 过滤掉还没有发布或者已经下架的题目和案例
+func (s *service) published(ctx context.Context, qids, cids []int64) (map[int64]bool, map[int64]bool, error) {
+	var (
+		eg      errgroup.Group
+		pubQids = make(map[int64]bool, len(qids))
+		pubCids = make(map[int64]bool, len(cids))
+	)
+	eg.Go(func() error {
+		ques, err := s.queSvc.GetPubByIDs(ctx, qids)
+		for _, q := range ques {
+			pubQids[q.Id] = true
+		}
+		return err
+	})
+	eg.Go(func() error {
+		cs, err := s.caseSvc.GetPubByIDs(ctx, cids)
+		for _, c := range cs {
+			pubCids[c.Id] = true
+		}
+		return err
+	})
+	return pubQids, pubCids, eg.Wait()
+}
+
+func (s *service) labelItems(ctx context.Context, lids []int64, duration time.Duration) ([]domain.Item, error) {
+	var (
+		eg errgroup.Group
+		qs []baguwen.Question
+		cs []cases.Case
+	)
+	eg.Go(func() error {
+		var err error
+		qs, _, err = s.queSvc.PubListByLabels(ctx, lids, false, 0, maxCandidates)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		cs, _, err = s.caseSvc.PubListByLabels(ctx, lids, false, 0, maxCandidates)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	candidates := make([]domain.Item, 0, len(qs)+len(cs))
+	candidates = append(candidates, slice.Map(qs, func(idx int, src baguwen.Question) domain.Item {
+		return domain.Item{Biz: domain.BizQuestion, BizId: src.Id}
+	})...)
+	candidates = append(candidates, slice.Map(cs, func(idx int, src cases.Case) domain.Item {
+		return domain.Item{Biz: domain.BizCase, BizId: src.Id}
+	})...)
+	res, _ := s.pick(candidates, duration)
+	return res, nil
+}
+
+// pick 打乱顺序之后尽可能多地挑选，直到用完 budget，返回剩下的时间
+func (s *service) pick(candidates []domain.Item, budget time.Duration) ([]domain.Item, time.Duration) {
+	rand.Shuffle(len(candidates), func(i, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+	res := make([]domain.Item, 0, len(candidates))
+	for _, c := range candidates {
+		cost := questionCost
+		if c.Biz == domain.BizCase {
+			cost = caseCost
+		}
+		if cost <= budget {
+			res = append(res, c)
+			budget -= cost
+		}
+	}
+	return res, budget
+}
+
+func (s *service) Grade(ctx context.Context, uid, id int64, g domain.Grade) error {
+	i, err := s.repo.Get(ctx, uid, id)
+	if err != nil {
+		return err
+	}
+	if i.Status == domain.InterviewStatusFinished {
+		return ErrInterviewEnded
+	}
+	if i.Expired(time.Now()) {
+		_, err = s.finish(ctx, i)
+		if err != nil {
+			return err
+		}
+		return ErrInterviewEnded
+	}
+	if !g.Status.Valid() || !i.Contains(g.Biz, g.BizId) ||
+		!slice.Contains(domain.Elements(g.Biz), g.Element) {
+		return ErrInvalidGrade
+	}
+	return s.repo.Grade(ctx, id, g)
+}
+
+func (s *service) Finish(ctx context.Context, uid, id int64) (domain.Interview, error) {
+	i, err := s.repo.Get(ctx, uid, id)
+	if err != nil {
+		return domain.Interview{}, err
+	}
+	if i.Status == domain.InterviewStatusFinished {
+		return i, nil
+	}
+	return s.finish(ctx, i)
+}
+
+func (s *service) Detail(ctx context.Context, uid, id int64) (domain.Interview, error) {
+	i, err := s.repo.Get(ctx, uid, id)
+	if err != nil {
+		return domain.Interview{}, err
+	}
+	if i.Status == domain.InterviewStatusInProgress && i.Expired(time.Now()) {
+		return s.finish(ctx, i)
+	}
+	return i, nil
+}
+
+func (s *service) finish(ctx context.Context, i domain.Interview) (domain.Interview, error) {
+	i.Score = i.CalcScore()
+	ok, err := s.repo.Finish(ctx, i.Uid, i.Id, i.Score)
+	if err != nil {
+		return domain.Interview{}, err
+	}
+	if !ok {
+		// 并发结束的时候以先结束的为准，返回数据库里面的结果
+		return s.repo.Get(ctx, i.Uid, i.Id)
+	}
+	i.Status = domain.InterviewStatusFinished
+	return i, nil
+}
+
+func (s *service) List(ctx context.Context, uid int64, biz string, bizId int64, offset, limit int) ([]domain.Interview, int64, error) {
+	return s.repo.List(ctx, uid, biz, bizId, offset, limit)
+}
@@ -0,0 +1,174 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/interview/internal/domain"
+	"github.com/ecodeclub/webook/internal/interview/internal/service"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/sync/errgroup"
+)
+
+const maxLimit = 100
+
+type Handler struct {
+	svc     service.Service
+	queSvc  baguwen.Service
+	caseSvc cases.Service
+}
+
+func NewHandler(svc service.Service, queSvc baguwen.Service, caseSvc cases.Service) *Handler {
+	return &Handler{
+		svc:     svc,
+		queSvc:  queSvc,
+		caseSvc: caseSvc,
+	}
+}
+
+func (h *Handler) MemberRoutes(server *gin.Engine) {
+	g := server.Group("/interview")
+	g.POST("/start", ginx.BS[StartReq](h.Start))
+	g.POST("/grade", ginx.BS[GradeReq](h.Grade))
+	g.POST("/finish", ginx.BS[IdReq](h.Finish))
+	g.POST("/detail", ginx.BS[IdReq](h.Detail))
+	g.POST("/list", ginx.BS[ListReq](h.List))
+}
+
+// Start 生成试卷并开始计时
+func (h *Handler) Start(ctx *ginx.Context, req StartReq, sess session.Session) (ginx.Result, error) {
+	i, err := h.svc.Start(ctx, sess.Claims().Uid, domain.Source{
+		Sid:  req.Sid,
+		Lids: req.Lids,
+	}, time.Duration(req.Duration)*time.Minute)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return h.detail(ctx, i)
+}
+
+// Grade 自评某个条目的某一部分
+func (h *Handler) Grade(ctx *ginx.Context, req GradeReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Grade(ctx, sess.Claims().Uid, req.Id, domain.Grade{
+		Biz:     req.Biz,
+		BizId:   req.BizId,
+		Element: domain.Element(req.Element),
+		Status:  domain.Status(req.Status),
+	})
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return ginx.Result{}, nil
+}
+
+// Finish 交卷
+func (h *Handler) Finish(ctx *ginx.Context, req IdReq, sess session.Session) (ginx.Result, error) {
+	i, err := h.svc.Finish(ctx, sess.Claims().Uid, req.Id)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return h.detail(ctx, i)
+}
+
+func (h *Handler) Detail(ctx *ginx.Context, req IdReq, sess session.Session) (ginx.Result, error) {
+	i, err := h.svc.Detail(ctx, sess.Claims().Uid, req.Id)
+	if err != nil {
+		return h.errorResult(err)
+	}
+	return h.detail(ctx, i)
+}
+
+// List 历史记录
+func (h *Handler) List(ctx *ginx.Context, req ListReq, sess session.Session) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	is, total, err := h.svc.List(ctx, sess.Claims().Uid, req.Biz, req.BizId, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: InterviewList{
+			Total: total,
+			Interviews: slice.Map(is, func(idx int, src domain.Interview) Interview {
+				return newInterview(src)
+			}),
+		},
+	}, nil
+}
+
+// detail 补充题目和案例的标题
+func (h *Handler) detail(ctx *ginx.Context, i domain.Interview) (ginx.Result, error) {
+	var (
+		eg   errgroup.Group
+		qids []int64
+		cids []int64
+	)
+	for _, item := range i.Items {
+		if item.Biz == domain.BizCase {
+			cids = append(cids, item.BizId)
+		} else {
+			qids = append(qids, item.BizId)
+		}
+	}
+	titles := map[string]map[int64]string{
+		domain.BizQuestion: make(map[int64]string, len(qids)),
+		domain.BizCase:     make(map[int64]string, len(cids)),
+	}
+	eg.Go(func() error {
+		ques, err := h.queSvc.GetPubByIDs(ctx, qids)
+		for _, q := range ques {
+			titles[domain.BizQuestion][q.Id] = q.Title
+		}
+		return err
+	})
+	eg.Go(func() error {
+		cs, err := h.caseSvc.GetPubByIDs(ctx, cids)
+		for _, c := range cs {
+			titles[domain.BizCase][c.Id] = c.Title
+		}
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: newInterviewDetail(i, titles),
+	}, nil
+}
+
+func (h *Handler) errorResult(err error) (ginx.Result, error) {
+	switch {
+	case errors.Is(err, service.ErrInterviewNotFound):
+		return interviewNotFoundResult, nil
+	case errors.Is(err, service.ErrInvalidSource),
+		errors.Is(err, service.ErrInvalidDuration),
+		errors.Is(err, service.ErrInvalidGrade):
+		return invalidInputResult, nil
+	case errors.Is(err, service.ErrEmptySheet):
+		return emptySheetResult, nil
+	case errors.Is(err, service.ErrInterviewEnded):
+		return interviewEndedResult, nil
+	default:
+		return systemErrorResult, err
+	}
+}
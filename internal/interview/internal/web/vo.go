@@ -0,0 +1,114 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/interview/internal/domain"
+)
+
+type StartReq struct {
+	// 技能或者标签二选一，同时传的时候以技能为准
+	Sid  int64   `json:"sid,omitempty"`
+	Lids []int64 `json:"lids,omitempty"`
+	// Duration 限定时间，单位是分钟
+	Duration int `json:"duration"`
+}
+
+type GradeReq struct {
+	Id      int64  `json:"id"`
+	Biz     string `json:"biz"`
+	BizId   int64  `json:"bizId"`
+	Element uint8  `json:"element"`
+	Status  uint8  `json:"status"`
+}
+
+type IdReq struct {
+	Id int64 `json:"id"`
+}
+
+type ListReq struct {
+	// 只看同一个来源的历史记录，为空的时候返回全部
+	Biz    string `json:"biz,omitempty"`
+	BizId  int64  `json:"bizId,omitempty"`
+	Offset int    `json:"offset,omitempty"`
+	Limit  int    `json:"limit,omitempty"`
+}
+
+type Interview struct {
+	Id       int64   `json:"id"`
+	Biz      string  `json:"biz"`
+	BizId    int64   `json:"bizId,omitempty"`
+	Lids     []int64 `json:"lids,omitempty"`
+	Duration int     `json:"duration"`
+	Items    []Item  `json:"items,omitempty"`
+	Status   uint8   `json:"status"`
+	Score    int     `json:"score"`
+	Ctime    int64   `json:"ctime"`
+	Deadline int64   `json:"deadline"`
+	Utime    int64   `json:"utime"`
+}
+
+type Item struct {
+	Biz    string  `json:"biz"`
+	BizId  int64   `json:"bizId"`
+	Level  uint8   `json:"level"`
+	Title  string  `json:"title"`
+	Grades []Grade `json:"grades,omitempty"`
+}
+
+type Grade struct {
+	Element uint8 `json:"element"`
+	Status  uint8 `json:"status"`
+}
+
+type InterviewList struct {
+	Total      int64       `json:"total"`
+	Interviews []Interview `json:"interviews"`
+}
+
+func newInterview(i domain.Interview) Interview {
+	return Interview{
+		Id:       i.Id,
+		Biz:      i.Source.Biz(),
+		BizId:    i.Source.BizId(),
+		Lids:     i.Source.Lids,
+		Duration: int(i.Duration.Minutes()),
+		Status:   uint8(i.Status),
+		Score:    i.Score,
+		Ctime:    i.Ctime.UnixMilli(),
+		Deadline: i.Deadline.UnixMilli(),
+		Utime:    i.Utime.UnixMilli(),
+	}
+}
+
+// newInterviewDetail 包括试卷上的条目和自评，titles 先按照 biz 再按照 bizId 索引
+func newInterviewDetail(i domain.Interview, titles map[string]map[int64]string) Interview {
+	res := newInterview(i)
+	res.Items = slice.Map(i.Items, func(idx int, src domain.Item) Item {
+		grades := slice.FilterMap(i.Grades, func(idx int, g domain.Grade) (Grade, bool) {
+			return Grade{Element: uint8(g.Element), Status: uint8(g.Status)},
+				g.Biz == src.Biz && g.BizId == src.BizId
+		})
+		return Item{
+			Biz:    src.Biz,
+			BizId:  src.BizId,
+			Level:  uint8(src.Level),
+			Title:  titles[src.Biz][src.BizId],
+			Grades: grades,
+		}
+	})
+	return res
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package interview
+
+import (
+	"sync"
+
+	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/interview/internal/repository"
+	"github.com/ecodeclub/webook/internal/interview/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/interview/internal/service"
+	"github.com/ecodeclub/webook/internal/interview/internal/web"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+)
+
+func InitModule(db *egorm.Component,
+	skillModule *skill.Module,
+	queModule *baguwen.Module,
+	caseModule *cases.Module) *Module {
+	wire.Build(
+		initDAO,
+		repository.NewInterviewRepository,
+		wire.FieldsOf(new(*skill.Module), "Svc"),
+		wire.FieldsOf(new(*baguwen.Module), "Svc"),
+		wire.FieldsOf(new(*cases.Module), "Svc"),
+		service.NewService,
+		web.NewHandler,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module)
+}
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.InterviewDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewInterviewGORMDAO(db)
+}
+
+type Handler = web.Handler
+type Service = service.Service
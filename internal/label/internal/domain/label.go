@@ -20,3 +20,9 @@ type Label struct {
 	Uid  int64
 	Name string
 }
+
+// LabelUsage 标签被某一类业务对象使用的次数
+type LabelUsage struct {
+	Label Label
+	Count int64
+}
@@ -25,6 +25,7 @@ import (
 	"github.com/ecodeclub/ekit/iox"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/webook/internal/label"
 	"github.com/ecodeclub/webook/internal/label/internal/integration/startup"
 	"github.com/ecodeclub/webook/internal/label/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/label/internal/web"
@@ -44,19 +45,21 @@ type HandlerTestSuite struct {
 	db     *egorm.Component
 	rdb    ecache.Cache
 	dao    dao.LabelDAO
+	svc    label.Service
 }
 
 func (s *HandlerTestSuite) SetupSuite() {
-	handler, err := startup.InitHandler()
-	require.NoError(s.T(), err)
+	module := startup.InitModule()
+	s.svc = module.Svc
 
 	econf.Set("server", map[string]any{"contextTimeout": "1s"})
 	server := egin.Load("server").Build()
-	handler.PrivateRoutes(server.Engine)
+	module.Hdl.PublicRoutes(server.Engine)
+	module.Hdl.PrivateRoutes(server.Engine)
 
 	s.server = server
 	s.db = testioc.InitDB()
-	err = dao.InitTables(s.db)
+	err := dao.InitTables(s.db)
 	require.NoError(s.T(), err)
 	s.dao = dao.NewLabelGORMDAO(s.db)
 	s.rdb = testioc.InitCache()
@@ -65,6 +68,8 @@ func (s *HandlerTestSuite) SetupSuite() {
 func (s *HandlerTestSuite) TearDownTest() {
 	err := s.db.Exec("TRUNCATE TABLE `labels`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `label_refs`").Error
+	require.NoError(s.T(), err)
 }
 
 func (s *HandlerTestSuite) TestSystemLabels() {
@@ -157,6 +162,207 @@ func (s *HandlerTestSuite) TestCreate() {
 	}
 }
 
+func (s *HandlerTestSuite) TestBizLabels() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	_, err := s.svc.CreateSystemLabel(ctx, "MySQL")
+	require.NoError(t, err)
+
+	// 已有的标签会被复用，没有的会被创建，重复的标签只算一次
+	err = s.svc.SaveBizLabels(ctx, "question", 1, []string{"MySQL", "Redis", "MySQL"})
+	require.NoError(t, err)
+	err = s.svc.SaveBizLabels(ctx, "question", 2, []string{"MySQL"})
+	require.NoError(t, err)
+	err = s.svc.SaveBizLabels(ctx, "question", 3, []string{"Kafka"})
+	require.NoError(t, err)
+	err = s.svc.SaveBizLabels(ctx, "case", 1, []string{"Redis"})
+	require.NoError(t, err)
+
+	// 自由填写的标签不会变成系统标签
+	labels, err := s.svc.SystemLabels(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(labels))
+	assert.Equal(t, "MySQL", labels[0].Name)
+	var all []dao.Label
+	err = s.db.WithContext(ctx).Order("id").Find(&all).Error
+	require.NoError(t, err)
+	require.Equal(t, 3, len(all))
+	lids := make(map[string]int64, len(all))
+	for _, l := range all {
+		lids[l.Name] = l.Id
+	}
+	assert.Equal(t, int64(1), lids["MySQL"])
+
+	testCases := []struct {
+		name      string
+		biz       string
+		lids      []int64
+		matchAll  bool
+		offset    int
+		limit     int
+		wantIds   []int64
+		wantTotal int64
+	}{
+		{
+			name:      "任意匹配",
+			biz:       "question",
+			lids:      []int64{lids["MySQL"], lids["Redis"]},
+			limit:     10,
+			wantIds:   []int64{2, 1},
+			wantTotal: 2,
+		},
+		{
+			name:      "全部匹配",
+			biz:       "question",
+			lids:      []int64{lids["MySQL"], lids["Redis"]},
+			matchAll:  true,
+			limit:     10,
+			wantIds:   []int64{1},
+			wantTotal: 1,
+		},
+		{
+			name:      "全部匹配，重复的标签",
+			biz:       "question",
+			lids:      []int64{lids["MySQL"], lids["MySQL"]},
+			matchAll:  true,
+			limit:     10,
+			wantIds:   []int64{2, 1},
+			wantTotal: 2,
+		},
+		{
+			name:      "分页",
+			biz:       "question",
+			lids:      []int64{lids["MySQL"], lids["Redis"], lids["Kafka"]},
+			offset:    1,
+			limit:     1,
+			wantIds:   []int64{2},
+			wantTotal: 3,
+		},
+		{
+			name:      "区分业务",
+			biz:       "case",
+			lids:      []int64{lids["MySQL"], lids["Redis"]},
+			limit:     10,
+			wantIds:   []int64{1},
+			wantTotal: 1,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ids, total, err := s.svc.BizIDs(ctx, tc.biz, tc.lids, tc.matchAll, tc.offset, tc.limit)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantIds, ids)
+			assert.Equal(t, tc.wantTotal, total)
+		})
+	}
+
+	// 覆盖原本的标签
+	err = s.svc.SaveBizLabels(ctx, "question", 1, []string{"Redis"})
+	require.NoError(t, err)
+	ids, total, err := s.svc.BizIDs(ctx, "question", []int64{lids["MySQL"]}, false, 0, 10)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{2}, ids)
+	assert.Equal(t, int64(1), total)
+
+	err = s.svc.DeleteBizLabels(ctx, "question", 2)
+	require.NoError(t, err)
+	ids, total, err = s.svc.BizIDs(ctx, "question", []int64{lids["MySQL"]}, false, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	assert.Equal(t, int64(0), total)
+}
+
+func (s *HandlerTestSuite) TestFindOrCreateLabels() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	ls, err := s.dao.FindOrCreateLabels(ctx, 1, []string{"MySQL"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(ls))
+
+	// 已经存在的标签不会重复创建，返回的标签都有 ID
+	res, err := s.dao.FindOrCreateLabels(ctx, 1, []string{"MySQL", "Redis"})
+	require.NoError(t, err)
+	require.Equal(t, 2, len(res))
+	for _, l := range res {
+		assert.True(t, l.Id > 0)
+		if l.Name == "MySQL" {
+			assert.Equal(t, ls[0].Id, l.Id)
+		}
+	}
+	var cnt int64
+	err = s.db.WithContext(ctx).Model(&dao.Label{}).Where("uid = ?", 1).Count(&cnt).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cnt)
+
+	// 唯一索引保证同一个 uid 下面不会重名
+	_, err = s.dao.CreateLabel(ctx, dao.Label{Uid: 1, Name: "MySQL"})
+	assert.Error(t, err)
+	_, err = s.dao.CreateLabel(ctx, dao.Label{Uid: 2, Name: "MySQL"})
+	assert.NoError(t, err)
+}
+
+func (s *HandlerTestSuite) TestUsage() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	err := s.svc.SaveBizLabels(ctx, "question", 1, []string{"MySQL", "Redis"})
+	require.NoError(s.T(), err)
+	err = s.svc.SaveBizLabels(ctx, "question", 2, []string{"Redis"})
+	require.NoError(s.T(), err)
+	err = s.svc.SaveBizLabels(ctx, "case", 1, []string{"Kafka"})
+	require.NoError(s.T(), err)
+
+	testCases := []struct {
+		name     string
+		req      web.UsageReq
+		wantCode int
+		wantResp test.Result[[]web.LabelUsage]
+	}{
+		{
+			name:     "问题",
+			req:      web.UsageReq{Biz: "question"},
+			wantCode: 200,
+			wantResp: test.Result[[]web.LabelUsage]{
+				Data: []web.LabelUsage{
+					{Id: 2, Name: "Redis", Count: 2},
+					{Id: 1, Name: "MySQL", Count: 1},
+				},
+			},
+		},
+		{
+			name:     "案例",
+			req:      web.UsageReq{Biz: "case"},
+			wantCode: 200,
+			wantResp: test.Result[[]web.LabelUsage]{
+				Data: []web.LabelUsage{
+					{Id: 3, Name: "Kafka", Count: 1},
+				},
+			},
+		},
+		{
+			name:     "没有使用",
+			req:      web.UsageReq{Biz: "skill"},
+			wantCode: 200,
+			wantResp: test.Result[[]web.LabelUsage]{
+				Data: []web.LabelUsage{},
+			},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/label/usage", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[[]web.LabelUsage]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+		})
+	}
+}
+
 func TestHandler(t *testing.T) {
 	suite.Run(t, new(HandlerTestSuite))
 }
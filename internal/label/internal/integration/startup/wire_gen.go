@@ -8,14 +8,13 @@ package startup
 
 import (
 	"github.com/ecodeclub/webook/internal/label"
-	"github.com/ecodeclub/webook/internal/label/internal/web"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 )
 
 // Injectors from wire.go:
 
-func InitHandler() (*web.Handler, error) {
+func InitModule() *label.Module {
 	db := testioc.InitDB()
-	handler := label.InitHandler(db)
-	return handler, nil
+	module := label.InitModule(db)
+	return module
 }
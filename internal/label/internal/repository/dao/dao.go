@@ -18,13 +18,28 @@ import (
 	"context"
 	"time"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type LabelDAO interface {
 	UidLabels(ctx context.Context, uid int64) ([]Label, error)
 	CreateLabel(ctx context.Context, label Label) (int64, error)
 	GetByID(ctx context.Context, id int64) (Label, error)
+	// FindLabels 查找 uid 名下名字在 names 里面的标签
+	FindLabels(ctx context.Context, uid int64, names []string) ([]Label, error)
+	// FindOrCreateLabels 查找 uid 名下的标签，不存在的会被创建
+	FindOrCreateLabels(ctx context.Context, uid int64, names []string) ([]Label, error)
+
+	// SaveRefs 用 lids 覆盖业务对象原本关联的标签
+	SaveRefs(ctx context.Context, biz string, bizId int64, lids []int64) error
+	DeleteRefs(ctx context.Context, biz string, bizId int64) error
+	// BizIDs 按照 biz_id 倒序返回关联了标签的业务 ID
+	BizIDs(ctx context.Context, biz string, lids []int64, matchAll bool, offset int, limit int) ([]int64, error)
+	CountBizIDs(ctx context.Context, biz string, lids []int64, matchAll bool) (int64, error)
+	Usage(ctx context.Context, biz string) ([]LabelUsage, error)
 }
 
 type LabelGORMDAO struct {
@@ -45,6 +60,106 @@ func (dao *LabelGORMDAO) CreateLabel(ctx context.Context, label Label) (int64, e
 	return label.Id, err
 }
 
+func (dao *LabelGORMDAO) FindLabels(ctx context.Context, uid int64, names []string) ([]Label, error) {
+	var res []Label
+	err := dao.db.WithContext(ctx).Where("uid = ? AND name IN ?", uid, names).Find(&res).Error
+	return res, err
+}
+
+// FindOrCreateLabels 并发创建同名标签的时候依赖 (uid, name) 唯一索引忽略冲突，
+// 所以插入之后要重新查询一遍才能拿到别人创建的标签的 ID
+func (dao *LabelGORMDAO) FindOrCreateLabels(ctx context.Context, uid int64, names []string) ([]Label, error) {
+	res, err := dao.FindLabels(ctx, uid, names)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]struct{}, len(res))
+	for _, l := range res {
+		existing[l.Name] = struct{}{}
+	}
+	now := time.Now().UnixMilli()
+	var missing []Label
+	for _, name := range names {
+		if _, ok := existing[name]; ok {
+			continue
+		}
+		existing[name] = struct{}{}
+		missing = append(missing, Label{Name: name, Uid: uid, Ctime: now, Utime: now})
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+	err = dao.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&missing).Error
+	if err != nil {
+		return nil, err
+	}
+	return dao.FindLabels(ctx, uid, names)
+}
+
+func (dao *LabelGORMDAO) SaveRefs(ctx context.Context, biz string, bizId int64, lids []int64) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("biz = ? AND biz_id = ?", biz, bizId).Delete(&LabelRef{}).Error
+		if err != nil {
+			return err
+		}
+		if len(lids) == 0 {
+			return nil
+		}
+		now := time.Now().UnixMilli()
+		refs := slice.Map(lids, func(idx int, src int64) LabelRef {
+			return LabelRef{Lid: src, Biz: biz, BizId: bizId, Ctime: now}
+		})
+		return tx.Create(&refs).Error
+	})
+}
+
+func (dao *LabelGORMDAO) DeleteRefs(ctx context.Context, biz string, bizId int64) error {
+	return dao.db.WithContext(ctx).
+		Where("biz = ? AND biz_id = ?", biz, bizId).Delete(&LabelRef{}).Error
+}
+
+func (dao *LabelGORMDAO) BizIDs(ctx context.Context, biz string, lids []int64,
+	matchAll bool, offset int, limit int) ([]int64, error) {
+	var res []int64
+	err := dao.bizIDsQuery(ctx, biz, lids, matchAll).
+		Order("biz_id DESC").
+		Offset(offset).Limit(limit).
+		Pluck("biz_id", &res).Error
+	return res, err
+}
+
+func (dao *LabelGORMDAO) CountBizIDs(ctx context.Context, biz string, lids []int64, matchAll bool) (int64, error) {
+	var res int64
+	err := dao.db.WithContext(ctx).
+		Table("(?) AS t", dao.bizIDsQuery(ctx, biz, lids, matchAll).Select("biz_id")).
+		Count(&res).Error
+	return res, err
+}
+
+// bizIDsQuery 任意匹配的时候按照 biz_id 去重，全部匹配的时候要求命中的标签数量和 lids 一致，
+// 所以 lids 里面不能有重复的元素
+func (dao *LabelGORMDAO) bizIDsQuery(ctx context.Context, biz string, lids []int64, matchAll bool) *gorm.DB {
+	query := dao.db.WithContext(ctx).Model(&LabelRef{}).
+		Where("biz = ? AND lid IN ?", biz, lids).
+		Group("biz_id")
+	if matchAll {
+		query = query.Having("COUNT(*) = ?", len(lids))
+	}
+	return query
+}
+
+func (dao *LabelGORMDAO) Usage(ctx context.Context, biz string) ([]LabelUsage, error) {
+	var res []LabelUsage
+	err := dao.db.WithContext(ctx).Model(&LabelRef{}).
+		Select("labels.id AS id, labels.name AS name, COUNT(*) AS cnt").
+		Joins("JOIN labels ON labels.id = label_refs.lid").
+		Where("label_refs.biz = ?", biz).
+		Group("labels.id, labels.name").
+		Order("cnt DESC, labels.id ASC").
+		Scan(&res).Error
+	return res, err
+}
+
 func (dao *LabelGORMDAO) UidLabels(ctx context.Context, uid int64) ([]Label, error) {
 	var res []Label
 	err := dao.db.WithContext(ctx).
@@ -57,9 +172,25 @@ func NewLabelGORMDAO(db *egorm.Component) LabelDAO {
 }
 
 type Label struct {
-	Id    int64 `gorm:"primaryKey,autoIncrement"`
-	Name  string
-	Uid   int64 `gorm:"index"`
+	Id int64 `gorm:"primaryKey,autoIncrement"`
+	// 同一个 uid 名下的标签不能重名
+	Name  string `gorm:"type:varchar(256);uniqueIndex:uid_name,priority:2"`
+	Uid   int64  `gorm:"uniqueIndex:uid_name,priority:1"`
 	Ctime int64
 	Utime int64
 }
+
+// LabelRef 业务对象和标签的关联关系，例如线上库的问题和案例
+type LabelRef struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Lid   int64  `gorm:"uniqueIndex:biz_lid,priority:3;index:lid_biz,priority:1"`
+	Biz   string `gorm:"type:varchar(64);uniqueIndex:biz_lid,priority:1;index:lid_biz,priority:2"`
+	BizId int64  `gorm:"uniqueIndex:biz_lid,priority:2"`
+	Ctime int64
+}
+
+type LabelUsage struct {
+	Id   int64
+	Name string
+	Cnt  int64
+}
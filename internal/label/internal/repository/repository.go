@@ -25,6 +25,15 @@ import (
 type LabelRepository interface {
 	UidLabels(ctx context.Context, uid int64) ([]domain.Label, error)
 	CreateLabel(ctx context.Context, uid int64, name string) (int64, error)
+	FindLabels(ctx context.Context, uid int64, names []string) ([]domain.Label, error)
+	// FindOrCreateLabels 不存在的标签会被创建出来
+	FindOrCreateLabels(ctx context.Context, uid int64, names []string) ([]domain.Label, error)
+
+	SaveRefs(ctx context.Context, biz string, bizId int64, lids []int64) error
+	DeleteRefs(ctx context.Context, biz string, bizId int64) error
+	BizIDs(ctx context.Context, biz string, lids []int64, matchAll bool, offset int, limit int) ([]int64, error)
+	TotalBizIDs(ctx context.Context, biz string, lids []int64, matchAll bool) (int64, error)
+	Usage(ctx context.Context, biz string) ([]domain.LabelUsage, error)
 }
 
 type CachedLabelRepository struct {
@@ -40,15 +49,54 @@ func (repo *CachedLabelRepository) CreateLabel(ctx context.Context, uid int64, n
 
 func (repo *CachedLabelRepository) UidLabels(ctx context.Context, uid int64) ([]domain.Label, error) {
 	labels, err := repo.dao.UidLabels(ctx, uid)
-	return slice.Map(labels, func(idx int, src dao.Label) domain.Label {
-		return domain.Label{
-			Id:   src.Id,
-			Uid:  src.Uid,
-			Name: src.Name,
+	return slice.Map(labels, repo.toDomain), err
+}
+
+func (repo *CachedLabelRepository) FindLabels(ctx context.Context, uid int64, names []string) ([]domain.Label, error) {
+	labels, err := repo.dao.FindLabels(ctx, uid, names)
+	return slice.Map(labels, repo.toDomain), err
+}
+
+func (repo *CachedLabelRepository) FindOrCreateLabels(ctx context.Context, uid int64, names []string) ([]domain.Label, error) {
+	labels, err := repo.dao.FindOrCreateLabels(ctx, uid, names)
+	return slice.Map(labels, repo.toDomain), err
+}
+
+func (repo *CachedLabelRepository) SaveRefs(ctx context.Context, biz string, bizId int64, lids []int64) error {
+	return repo.dao.SaveRefs(ctx, biz, bizId, lids)
+}
+
+func (repo *CachedLabelRepository) DeleteRefs(ctx context.Context, biz string, bizId int64) error {
+	return repo.dao.DeleteRefs(ctx, biz, bizId)
+}
+
+func (repo *CachedLabelRepository) BizIDs(ctx context.Context, biz string, lids []int64,
+	matchAll bool, offset int, limit int) ([]int64, error) {
+	return repo.dao.BizIDs(ctx, biz, lids, matchAll, offset, limit)
+}
+
+func (repo *CachedLabelRepository) TotalBizIDs(ctx context.Context, biz string, lids []int64, matchAll bool) (int64, error) {
+	return repo.dao.CountBizIDs(ctx, biz, lids, matchAll)
+}
+
+func (repo *CachedLabelRepository) Usage(ctx context.Context, biz string) ([]domain.LabelUsage, error) {
+	usages, err := repo.dao.Usage(ctx, biz)
+	return slice.Map(usages, func(idx int, src dao.LabelUsage) domain.LabelUsage {
+		return domain.LabelUsage{
+			Label: domain.Label{Id: src.Id, Name: src.Name},
+			Count: src.Cnt,
 		}
 	}), err
 }
 
+func (repo *CachedLabelRepository) toDomain(idx int, src dao.Label) domain.Label {
+	return domain.Label{
+		Id:   src.Id,
+		Uid:  src.Uid,
+		Name: src.Name,
+	}
+}
+
 func NewCachedLabelRepository(dao dao.LabelDAO) LabelRepository {
 	return &CachedLabelRepository{dao: dao}
 }
@@ -17,15 +17,34 @@ package service
 import (
 	"context"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/label/internal/domain"
 	"github.com/ecodeclub/webook/internal/label/internal/repository"
+	"golang.org/x/sync/errgroup"
 )
 
-const systemUid int64 = -1
+const (
+	systemUid int64 = -1
+	// bizLabelUid 业务对象上自由填写的标签，和系统标签分开，
+	// 避免作者随手写的标签出现在系统标签里面
+	bizLabelUid int64 = -2
+)
 
+//go:generate mockgen -source=./service.go -destination=../../mocks/label.mock.go -package=labelmocks -typed Service
 type Service interface {
 	SystemLabels(ctx context.Context) ([]domain.Label, error)
 	CreateSystemLabel(ctx context.Context, name string) (int64, error)
+
+	// SaveBizLabels 用 labels 覆盖业务对象关联的标签，
+	// labels 是标签的名字，同名的系统标签会被复用，其余的会被创建为业务标签
+	SaveBizLabels(ctx context.Context, biz string, bizId int64, labels []string) error
+	// DeleteBizLabels 删除业务对象关联的全部标签
+	DeleteBizLabels(ctx context.Context, biz string, bizId int64) error
+	// BizIDs 按照 ID 倒序返回关联了标签的业务 ID 以及总数，
+	// matchAll 为 true 的时候要求关联了全部标签，否则关联了任意一个即可
+	BizIDs(ctx context.Context, biz string, lids []int64, matchAll bool, offset int, limit int) ([]int64, int64, error)
+	// Usage 标签在 biz 中被使用的次数，按照次数倒序排列
+	Usage(ctx context.Context, biz string) ([]domain.LabelUsage, error)
 }
 
 type service struct {
@@ -40,6 +59,80 @@ func (s *service) SystemLabels(ctx context.Context) ([]domain.Label, error) {
 	return s.repo.UidLabels(ctx, systemUid)
 }
 
+func (s *service) SaveBizLabels(ctx context.Context, biz string, bizId int64, labels []string) error {
+	labels = dedup(labels)
+	if len(labels) == 0 {
+		return s.repo.DeleteRefs(ctx, biz, bizId)
+	}
+	ls, err := s.repo.FindLabels(ctx, systemUid, labels)
+	if err != nil {
+		return err
+	}
+	existing := make(map[string]struct{}, len(ls))
+	for _, l := range ls {
+		existing[l.Name] = struct{}{}
+	}
+	missing := slice.FilterMap(labels, func(idx int, src string) (string, bool) {
+		_, ok := existing[src]
+		return src, !ok
+	})
+	if len(missing) > 0 {
+		bizLabels, err := s.repo.FindOrCreateLabels(ctx, bizLabelUid, missing)
+		if err != nil {
+			return err
+		}
+		ls = append(ls, bizLabels...)
+	}
+	return s.repo.SaveRefs(ctx, biz, bizId, slice.Map(ls, func(idx int, src domain.Label) int64 {
+		return src.Id
+	}))
+}
+
+func (s *service) DeleteBizLabels(ctx context.Context, biz string, bizId int64) error {
+	return s.repo.DeleteRefs(ctx, biz, bizId)
+}
+
+func (s *service) BizIDs(ctx context.Context, biz string, lids []int64,
+	matchAll bool, offset int, limit int) ([]int64, int64, error) {
+	lids = dedup(lids)
+	if len(lids) == 0 {
+		return nil, 0, nil
+	}
+	var (
+		eg    errgroup.Group
+		ids   []int64
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		ids, err = s.repo.BizIDs(ctx, biz, lids, matchAll, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.repo.TotalBizIDs(ctx, biz, lids, matchAll)
+		return err
+	})
+	return ids, total, eg.Wait()
+}
+
+func (s *service) Usage(ctx context.Context, biz string) ([]domain.LabelUsage, error) {
+	return s.repo.Usage(ctx, biz)
+}
+
+func dedup[T comparable](src []T) []T {
+	seen := make(map[T]struct{}, len(src))
+	res := make([]T, 0, len(src))
+	for _, e := range src {
+		if _, ok := seen[e]; ok {
+			continue
+		}
+		seen[e] = struct{}{}
+		res = append(res, e)
+	}
+	return res
+}
+
 func NewService(repo repository.LabelRepository) Service {
 	return &service{repo: repo}
 }
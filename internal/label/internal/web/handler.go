@@ -30,6 +30,10 @@ func NewHandler(svc service.Service) *Handler {
 	return &Handler{svc: svc}
 }
 
+func (h *Handler) PublicRoutes(server *gin.Engine) {
+	server.POST("/label/usage", ginx.B(h.Usage))
+}
+
 func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	g := server.Group("/label")
 	g.GET("/system", ginx.W(h.SystemLabels))
@@ -58,3 +62,20 @@ func (h *Handler) CreateSystemLabel(ctx *ginx.Context, req Label) (ginx.Result,
 	}
 	return ginx.Result{Data: id}, nil
 }
+
+// Usage 标签的使用次数，前端用来展示分面数量
+func (h *Handler) Usage(ctx *ginx.Context, req UsageReq) (ginx.Result, error) {
+	usages, err := h.svc.Usage(ctx, req.Biz)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: slice.Map(usages, func(idx int, src domain.LabelUsage) LabelUsage {
+			return LabelUsage{
+				Id:    src.Label.Id,
+				Name:  src.Label.Name,
+				Count: src.Count,
+			}
+		}),
+	}, nil
+}
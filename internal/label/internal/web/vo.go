@@ -18,3 +18,14 @@ type Label struct {
 	Id   int64  `json:"id,omitempty"`
 	Name string `json:"name,omitempty"`
 }
+
+type UsageReq struct {
+	// 业务类型，例如 question，case
+	Biz string `json:"biz"`
+}
+
+type LabelUsage struct {
+	Id    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
@@ -19,6 +19,7 @@ package label
 import (
 	"sync"
 
+	"github.com/ecodeclub/webook/internal/label/internal/domain"
 	"github.com/ecodeclub/webook/internal/label/internal/repository"
 	"github.com/ecodeclub/webook/internal/label/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/label/internal/service"
@@ -33,9 +34,9 @@ var HandlerSet = wire.NewSet(
 	service.NewService,
 	web.NewHandler)
 
-func InitHandler(db *egorm.Component) *Handler {
-	wire.Build(HandlerSet)
-	return new(Handler)
+func InitModule(db *egorm.Component) *Module {
+	wire.Build(HandlerSet, wire.Struct(new(Module), "*"))
+	return new(Module)
 }
 
 var once = &sync.Once{}
@@ -48,3 +49,5 @@ func InitTablesOnce(db *egorm.Component) dao.LabelDAO {
 }
 
 type Handler = web.Handler
+type Service = service.Service
+type Label = domain.Label
@@ -9,6 +9,7 @@ package label
 import (
 	"sync"
 
+	"github.com/ecodeclub/webook/internal/label/internal/domain"
 	"github.com/ecodeclub/webook/internal/label/internal/repository"
 	"github.com/ecodeclub/webook/internal/label/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/label/internal/service"
@@ -20,12 +21,16 @@ import (
 
 // Injectors from wire.go:
 
-func InitHandler(db *gorm.DB) *web.Handler {
+func InitModule(db *gorm.DB) *Module {
 	labelDAO := InitTablesOnce(db)
 	labelRepository := repository.NewCachedLabelRepository(labelDAO)
 	serviceService := service.NewService(labelRepository)
 	handler := web.NewHandler(serviceService)
-	return handler
+	module := &Module{
+		Svc: serviceService,
+		Hdl: handler,
+	}
+	return module
 }
 
 // wire.go:
@@ -42,3 +47,7 @@ func InitTablesOnce(db *egorm.Component) dao.LabelDAO {
 }
 
 type Handler = web.Handler
+
+type Service = service.Service
+
+type Label = domain.Label
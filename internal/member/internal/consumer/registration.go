@@ -12,7 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package event
+package consumer
 
 import (
 	"context"
@@ -26,15 +26,22 @@ import (
 	"github.com/gotomicro/ego/core/elog"
 )
 
+const userRegistrationEvents = "user_registration_events"
+
+type RegistrationEvent struct {
+	Uid int64 `json:"uid"`
+}
+
 type RegistrationEventConsumer struct {
 	svc      service.Service
 	consumer mq.Consumer
-	endAt    int64
-	logger   *elog.Component
+	// trial 新用户注册后赠送的会员时长，为 0 的时候不赠送
+	trial  time.Duration
+	logger *elog.Component
 }
 
 func NewRegistrationEventConsumer(svc service.Service,
-	q mq.MQ) (*RegistrationEventConsumer, error) {
+	q mq.MQ, trial time.Duration) (*RegistrationEventConsumer, error) {
 	const groupID = "member"
 	consumer, err := q.Consumer(userRegistrationEvents, groupID)
 	if err != nil {
@@ -43,7 +50,7 @@ func NewRegistrationEventConsumer(svc service.Service,
 	return &RegistrationEventConsumer{
 		svc:      svc,
 		consumer: consumer,
-		endAt:    time.Date(2024, 6, 30, 23, 59, 59, 0, time.UTC).UnixMilli(),
+		trial:    trial,
 		logger:   elog.DefaultLogger,
 	}, nil
 }
@@ -71,10 +78,16 @@ func (c *RegistrationEventConsumer) Consume(ctx context.Context) error {
 	if err != nil {
 		return fmt.Errorf("解析消息失败: %w", err)
 	}
-	_, err = c.svc.CreateNewMembership(ctx, domain.Member{
-		UID:     evt.Uid,
-		StartAt: time.Now().UnixMilli(),
-		EndAt:   c.endAt,
+	if c.trial <= 0 {
+		return nil
+	}
+	_, err = c.svc.ExtendMembership(ctx, evt.Uid, c.trial, domain.Source{
+		// 每个用户只能获得一次注册试用
+		Key:    fmt.Sprintf("registration:%d", evt.Uid),
+		Biz:    "user",
+		BizId:  evt.Uid,
+		Action: "注册试用",
+		Tier:   domain.TierNormal,
 	})
 
 	if err != nil {
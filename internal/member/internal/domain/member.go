@@ -14,9 +14,44 @@
 
 package domain
 
+// Tier 会员等级，数值越大等级越高
+type Tier uint8
+
+const (
+	TierNormal Tier = iota + 1 // 普通会员
+	TierSenior                 // 高级会员
+)
+
 type Member struct {
 	ID      int64
 	UID     int64
+	Tier    Tier
 	StartAt int64
 	EndAt   int64
 }
+
+// Source 会员变更的来源，同一个 Key 只会生效一次
+type Source struct {
+	Key    string
+	Biz    string
+	BizId  int64
+	Action string
+	// Tier 本次延长的时长对应的会员等级，高等级的时长优先使用
+	Tier Tier
+}
+
+// MemberRecord 会员变更记录
+type MemberRecord struct {
+	Key    string
+	Biz    string
+	BizId  int64
+	Action string
+	Tier   Tier
+	// Duration 本次延长的时长，毫秒数
+	Duration int64
+	// OldEndAt 变更前的会员结束日期，UTC Unix毫秒数
+	OldEndAt int64
+	// NewEndAt 变更后的会员结束日期，UTC Unix毫秒数
+	NewEndAt int64
+	Ctime    int64
+}
@@ -14,8 +14,29 @@
 
 package event
 
-const userRegistrationEvents = "user_registration_events"
+const (
+	memberEvents               = "member_events"
+	memberExpiryReminderEvents = "member_expiry_reminder_events"
+)
 
-type RegistrationEvent struct {
-	Uid int64 `json:"uid"`
+// MemberEvent 会员信息发生变更后发出的事件
+type MemberEvent struct {
+	Uid     int64  `json:"uid"`
+	Tier    uint8  `json:"tier"`
+	StartAt int64  `json:"startAt"`
+	EndAt   int64  `json:"endAt"`
+	Version int64  `json:"version"`
+	Key     string `json:"key"`
+	Biz     string `json:"biz"`
+	BizId   int64  `json:"bizId"`
+	Action  string `json:"action"`
+}
+
+// ExpiryReminderEvent 会员即将到期的提醒，由通知模块消费
+type ExpiryReminderEvent struct {
+	Uid   int64 `json:"uid"`
+	Tier  uint8 `json:"tier"`
+	EndAt int64 `json:"endAt"`
+	// Days 距离到期的天数
+	Days int `json:"days"`
 }
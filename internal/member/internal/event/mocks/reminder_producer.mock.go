@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./reminder_producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./reminder_producer.go -package=evtmocks -destination=./mocks/reminder_producer.mock.go -typed ExpiryReminderEventProducer
+//
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	event "github.com/ecodeclub/webook/internal/member/internal/event"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockExpiryReminderEventProducer is a mock of ExpiryReminderEventProducer interface.
+type MockExpiryReminderEventProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockExpiryReminderEventProducerMockRecorder
+}
+
+// MockExpiryReminderEventProducerMockRecorder is the mock recorder for MockExpiryReminderEventProducer.
+type MockExpiryReminderEventProducerMockRecorder struct {
+	mock *MockExpiryReminderEventProducer
+}
+
+// NewMockExpiryReminderEventProducer creates a new mock instance.
+func NewMockExpiryReminderEventProducer(ctrl *gomock.Controller) *MockExpiryReminderEventProducer {
+	mock := &MockExpiryReminderEventProducer{ctrl: ctrl}
+	mock.recorder = &MockExpiryReminderEventProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockExpiryReminderEventProducer) EXPECT() *MockExpiryReminderEventProducerMockRecorder {
+	return m.recorder
+}
+
+// Produce mocks base method.
+func (m *MockExpiryReminderEventProducer) Produce(ctx context.Context, evt event.ExpiryReminderEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Produce", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Produce indicates an expected call of Produce.
+func (mr *MockExpiryReminderEventProducerMockRecorder) Produce(ctx, evt any) *ExpiryReminderEventProducerProduceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockExpiryReminderEventProducer)(nil).Produce), ctx, evt)
+	return &ExpiryReminderEventProducerProduceCall{Call: call}
+}
+
+// ExpiryReminderEventProducerProduceCall wrap *gomock.Call
+type ExpiryReminderEventProducerProduceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ExpiryReminderEventProducerProduceCall) Return(arg0 error) *ExpiryReminderEventProducerProduceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ExpiryReminderEventProducerProduceCall) Do(f func(context.Context, event.ExpiryReminderEvent) error) *ExpiryReminderEventProducerProduceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ExpiryReminderEventProducerProduceCall) DoAndReturn(f func(context.Context, event.ExpiryReminderEvent) error) *ExpiryReminderEventProducerProduceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/ecodeclub/mq-api"
+)
+
+//go:generate mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go -typed MemberEventProducer
+type MemberEventProducer interface {
+	Produce(ctx context.Context, evt MemberEvent) error
+}
+
+type memberEventProducer struct {
+	producer mq.Producer
+}
+
+func NewMemberEventProducer(q mq.MQ) (MemberEventProducer, error) {
+	producer, err := q.Producer(memberEvents)
+	if err != nil {
+		return nil, err
+	}
+	return &memberEventProducer{producer: producer}, nil
+}
+
+func (p *memberEventProducer) Produce(ctx context.Context, evt MemberEvent) error {
+	data, err := json.Marshal(&evt)
+	if err != nil {
+		return fmt.Errorf("序列化失败: %w", err)
+	}
+	// 同一个用户的变更进入同一个分区，保证顺序
+	_, err = p.producer.Produce(ctx, &mq.Message{
+		Key:   []byte(strconv.FormatInt(evt.Uid, 10)),
+		Value: data,
+	})
+	if err != nil {
+		return fmt.Errorf("发送会员变更消息失败: %w", err)
+	}
+	return nil
+}
@@ -22,16 +22,22 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ecodeclub/ecache"
 	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/member/internal/consumer"
 	"github.com/ecodeclub/webook/internal/member/internal/domain"
 	"github.com/ecodeclub/webook/internal/member/internal/event"
+	evtmocks "github.com/ecodeclub/webook/internal/member/internal/event/mocks"
 	"github.com/ecodeclub/webook/internal/member/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/member/internal/job"
+	"github.com/ecodeclub/webook/internal/member/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/member/internal/service"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 	"github.com/ego-component/egorm"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
 )
 
 func TestMemberModule(t *testing.T) {
@@ -43,23 +49,39 @@ type ModuleTestSuite struct {
 	db  *egorm.Component
 	mq  mq.MQ
 	svc service.Service
+	// trial 注册赠送的会员时长
+	trial time.Duration
+	cache ecache.Cache
 }
 
 func (s *ModuleTestSuite) SetupSuite() {
 	s.svc = startup.InitService()
 	s.db = testioc.InitDB()
 	s.mq = testioc.InitMQ()
+	s.trial = time.Hour * 24 * 7
+	s.cache = testioc.InitCache()
 }
 
 func (s *ModuleTestSuite) TearDownSuite() {
 	err := s.db.Exec("DROP TABLE `members`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("DROP TABLE `member_records`").Error
+	require.NoError(s.T(), err)
+	err = s.db.Exec("DROP TABLE `member_periods`").Error
+	require.NoError(s.T(), err)
 }
 
 func (s *ModuleTestSuite) TearDownTest() {
 	err := s.db.Exec("TRUNCATE TABLE `members`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `member_records`").Error
+	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `member_periods`").Error
+	require.NoError(s.T(), err)
+	_, err = s.cache.Delete(context.Background(), "member:version:2001")
+	require.NoError(s.T(), err)
 }
+
 func (s *ModuleTestSuite) TestConsumer_ConsumeRegistrationEvent() {
 	t := s.T()
 	producer, err := s.mq.Producer("user_registration_events")
@@ -88,9 +110,8 @@ func (s *ModuleTestSuite) TestConsumer_ConsumeRegistrationEvent() {
 			errAssertFunc: assert.NoError,
 		},
 
-		// 开会员失败_新用户注册_优惠已到期, 无法测到,通过代码审查来补充
 		{
-			name: "开会员失败_用户已注册_会员生效中",
+			name: "开会员成功_用户已注册_会员生效中_叠加时长",
 			before: func(t *testing.T, producer mq.Producer, message *mq.Message) {
 				t.Helper()
 				_, err := producer.Produce(context.Background(), message)
@@ -102,12 +123,21 @@ func (s *ModuleTestSuite) TestConsumer_ConsumeRegistrationEvent() {
 				})
 				require.NoError(t, err)
 			},
-			after:         func(t *testing.T, uid int64) {},
+			after: func(t *testing.T, uid int64) {
+				info, err := s.svc.GetMembershipInfo(context.Background(), uid)
+				require.NoError(t, err)
+				assert.True(t, info.EndAt > time.Now().Add(s.trial).UnixMilli())
+				records, err := s.svc.GetMembershipRecords(context.Background(), uid)
+				require.NoError(t, err)
+				require.Len(t, records, 1)
+				assert.Equal(t, "registration:1993", records[0].Key)
+				assert.Equal(t, s.trial.Milliseconds(), records[0].NewEndAt-records[0].OldEndAt)
+			},
 			Uid:           1993,
-			errAssertFunc: assert.Error,
+			errAssertFunc: assert.NoError,
 		},
 		{
-			name: "开会员失败_用户已注册_会员已失效",
+			name: "开会员成功_用户已注册_会员已失效_从当前时间开始",
 			before: func(t *testing.T, producer mq.Producer, message *mq.Message) {
 				t.Helper()
 				_, err := producer.Produce(context.Background(), message)
@@ -119,13 +149,40 @@ func (s *ModuleTestSuite) TestConsumer_ConsumeRegistrationEvent() {
 				})
 				require.NoError(t, err)
 			},
-			after:         func(t *testing.T, uid int64) {},
+			after: func(t *testing.T, uid int64) {
+				info, err := s.svc.GetMembershipInfo(context.Background(), uid)
+				require.NoError(t, err)
+				assert.True(t, info.StartAt > time.Date(2023, 6, 30, 23, 59, 59, 0, time.UTC).UnixMilli())
+				assert.Equal(t, s.trial.Milliseconds(), info.EndAt-info.StartAt)
+			},
 			Uid:           1994,
-			errAssertFunc: assert.Error,
+			errAssertFunc: assert.NoError,
+		},
+		{
+			name: "重复消费_只赠送一次",
+			before: func(t *testing.T, producer mq.Producer, message *mq.Message) {
+				t.Helper()
+				_, err := s.svc.ExtendMembership(context.Background(), 1995, s.trial, domain.Source{
+					Key:    "registration:1995",
+					Biz:    "user",
+					BizId:  1995,
+					Action: "注册试用",
+				})
+				require.NoError(t, err)
+				_, err = producer.Produce(context.Background(), message)
+				require.NoError(t, err)
+			},
+			after: func(t *testing.T, uid int64) {
+				records, err := s.svc.GetMembershipRecords(context.Background(), uid)
+				require.NoError(t, err)
+				require.Len(t, records, 1)
+			},
+			Uid:           1995,
+			errAssertFunc: assert.NoError,
 		},
 	}
 
-	consumer, err := event.NewRegistrationEventConsumer(s.svc, s.mq)
+	c, err := consumer.NewRegistrationEventConsumer(s.svc, s.mq, s.trial)
 	require.NoError(t, err)
 
 	for i := range testCases {
@@ -134,7 +191,7 @@ func (s *ModuleTestSuite) TestConsumer_ConsumeRegistrationEvent() {
 			message := s.newRegistrationEventMessage(t, tc.Uid)
 			tc.before(t, producer, message)
 
-			err = consumer.Consume(context.Background())
+			err = c.Consume(context.Background())
 
 			tc.errAssertFunc(t, err)
 			tc.after(t, tc.Uid)
@@ -143,7 +200,201 @@ func (s *ModuleTestSuite) TestConsumer_ConsumeRegistrationEvent() {
 }
 
 func (s *ModuleTestSuite) newRegistrationEventMessage(t *testing.T, uid int64) *mq.Message {
-	marshal, err := json.Marshal(event.RegistrationEvent{Uid: uid})
+	marshal, err := json.Marshal(consumer.RegistrationEvent{Uid: uid})
 	require.NoError(t, err)
 	return &mq.Message{Value: marshal}
 }
+
+func (s *ModuleTestSuite) TestService_ExtendMembership() {
+	t := s.T()
+	ctx := context.Background()
+	const uid = int64(2001)
+
+	m, err := s.svc.ExtendMembership(ctx, uid, time.Hour*24, domain.Source{
+		Key:    "order:1",
+		Biz:    "order",
+		BizId:  1,
+		Action: "购买会员",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierNormal, m.Tier)
+	firstEndAt := m.EndAt
+
+	// 叠加时长，同时升级
+	m, err = s.svc.ExtendMembership(ctx, uid, time.Hour*24*30, domain.Source{
+		Key:    "order:2",
+		Biz:    "order",
+		BizId:  2,
+		Action: "购买会员",
+		Tier:   domain.TierSenior,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierSenior, m.Tier)
+	assert.Equal(t, firstEndAt+(time.Hour*24*30).Milliseconds(), m.EndAt)
+
+	// 幂等，并且等级不会下降
+	m, err = s.svc.ExtendMembership(ctx, uid, time.Hour*24, domain.Source{
+		Key:    "order:1",
+		Biz:    "order",
+		BizId:  1,
+		Action: "购买会员",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierSenior, m.Tier)
+	assert.Equal(t, firstEndAt+(time.Hour*24*30).Milliseconds(), m.EndAt)
+
+	// 重复的来源不会递增版本号
+	ver, err := s.svc.GetMembershipVersion(ctx, uid)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), ver)
+
+	records, err := s.svc.GetMembershipRecords(ctx, uid)
+	require.NoError(t, err)
+	require.Len(t, records, 2)
+	assert.Equal(t, "order:2", records[0].Key)
+	assert.Equal(t, "order:1", records[1].Key)
+
+	_, err = s.svc.ExtendMembership(ctx, uid, 0, domain.Source{Key: "order:3"})
+	assert.Error(t, err)
+}
+
+func (s *ModuleTestSuite) TestService_ExtendMembership_ExpiredTier() {
+	t := s.T()
+	ctx := context.Background()
+	const uid = int64(2002)
+	now := time.Now()
+
+	// 高级会员已经过期了
+	_, err := s.svc.CreateNewMembership(ctx, domain.Member{
+		UID:     uid,
+		Tier:    domain.TierSenior,
+		StartAt: now.AddDate(0, 0, -30).UnixMilli(),
+		EndAt:   now.AddDate(0, 0, -1).UnixMilli(),
+	})
+	require.NoError(t, err)
+
+	// 再购买普通会员，过期的高级会员不能复活
+	m, err := s.svc.ExtendMembership(ctx, uid, time.Hour*24, domain.Source{
+		Key:    "order:11",
+		Biz:    "order",
+		BizId:  11,
+		Action: "购买会员",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierNormal, m.Tier)
+	assert.True(t, m.EndAt > now.UnixMilli())
+}
+
+func (s *ModuleTestSuite) TestService_ExtendMembership_MixedTier() {
+	t := s.T()
+	ctx := context.Background()
+	day := time.Hour * 24
+
+	// 高级会员期间购买普通会员，普通会员顺延到高级会员之后
+	const seniorFirst = int64(2003)
+	m, err := s.svc.ExtendMembership(ctx, seniorFirst, day*10, domain.Source{
+		Key: "order:21", Biz: "order", BizId: 21, Action: "购买会员", Tier: domain.TierSenior,
+	})
+	require.NoError(t, err)
+	seniorEndAt := m.EndAt
+	m, err = s.svc.ExtendMembership(ctx, seniorFirst, day*5, domain.Source{
+		Key: "order:22", Biz: "order", BizId: 22, Action: "购买会员",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierSenior, m.Tier)
+	assert.Equal(t, seniorEndAt+(day*5).Milliseconds(), m.EndAt)
+	periods := s.periods(t, seniorFirst)
+	require.Len(t, periods, 2)
+	assert.Equal(t, uint8(domain.TierSenior), periods[0].Tier)
+	assert.Equal(t, seniorEndAt, periods[0].EndAt)
+	assert.Equal(t, uint8(domain.TierNormal), periods[1].Tier)
+	assert.Equal(t, seniorEndAt, periods[1].StartAt)
+	assert.Equal(t, m.EndAt, periods[1].EndAt)
+
+	// 高级会员的时段用完之后，变成普通会员
+	err = s.db.Model(&dao.MemberPeriod{}).Where("id = ?", periods[0].Id).
+		Update("end_at", time.Now().Add(-time.Minute).UnixMilli()).Error
+	require.NoError(t, err)
+	err = s.db.Model(&dao.MemberPeriod{}).Where("id = ?", periods[1].Id).
+		Update("start_at", time.Now().Add(-time.Minute).UnixMilli()).Error
+	require.NoError(t, err)
+	m, err = s.svc.GetMembershipInfo(ctx, seniorFirst)
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierNormal, m.Tier)
+
+	// 普通会员期间购买高级会员，高级会员立刻生效，剩下的普通会员顺延，总时长不变
+	const normalFirst = int64(2004)
+	startAt := time.Now().Add(-time.Hour)
+	normalEndAt := startAt.Add(day * 10).UnixMilli()
+	_, err = s.svc.CreateNewMembership(ctx, domain.Member{
+		UID:     normalFirst,
+		Tier:    domain.TierNormal,
+		StartAt: startAt.UnixMilli(),
+		EndAt:   normalEndAt,
+	})
+	require.NoError(t, err)
+	m, err = s.svc.ExtendMembership(ctx, normalFirst, day*5, domain.Source{
+		Key: "order:24", Biz: "order", BizId: 24, Action: "购买会员", Tier: domain.TierSenior,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, domain.TierSenior, m.Tier)
+	assert.Equal(t, normalEndAt+(day*5).Milliseconds(), m.EndAt)
+	periods = s.periods(t, normalFirst)
+	require.Len(t, periods, 3)
+	var normal, senior int64
+	for _, p := range periods {
+		if p.Tier == uint8(domain.TierSenior) {
+			senior += p.EndAt - p.StartAt
+		} else {
+			normal += p.EndAt - p.StartAt
+		}
+	}
+	assert.Equal(t, (day * 10).Milliseconds(), normal)
+	assert.Equal(t, (day * 5).Milliseconds(), senior)
+	assert.Equal(t, uint8(domain.TierSenior), periods[1].Tier)
+	assert.Equal(t, m.EndAt, periods[2].EndAt)
+}
+
+func (s *ModuleTestSuite) periods(t *testing.T, uid int64) []dao.MemberPeriod {
+	var res []dao.MemberPeriod
+	err := s.db.Where("uid = ?", uid).Order("start_at ASC").Find(&res).Error
+	require.NoError(t, err)
+	return res
+}
+
+func (s *ModuleTestSuite) TestExpiryReminderJob() {
+	t := s.T()
+	ctx := context.Background()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	members := []domain.Member{
+		// 7 天后到期
+		{UID: 3001, StartAt: now.UnixMilli(), EndAt: today.AddDate(0, 0, 7).Add(time.Hour).UnixMilli()},
+		// 1 天后到期
+		{UID: 3002, StartAt: now.UnixMilli(), EndAt: today.AddDate(0, 0, 1).Add(time.Hour).UnixMilli()},
+		// 3 天后到期，不需要提醒
+		{UID: 3003, StartAt: now.UnixMilli(), EndAt: today.AddDate(0, 0, 3).Add(time.Hour).UnixMilli()},
+	}
+	for _, m := range members {
+		_, err := s.svc.CreateNewMembership(ctx, m)
+		require.NoError(t, err)
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	producer := evtmocks.NewMockExpiryReminderEventProducer(ctrl)
+	var evts []event.ExpiryReminderEvent
+	producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, evt event.ExpiryReminderEvent) error {
+			evts = append(evts, evt)
+			return nil
+		}).Times(2)
+
+	// limit 设置为 1，验证分批查询
+	j := job.NewExpiryReminderJob(s.svc, producer, []int{7, 1}, 1, time.Minute)
+	require.NoError(t, j.Run())
+	assert.ElementsMatch(t, []event.ExpiryReminderEvent{
+		{Uid: 3001, Tier: uint8(domain.TierNormal), EndAt: members[0].EndAt, Days: 7},
+		{Uid: 3002, Tier: uint8(domain.TierNormal), EndAt: members[1].EndAt, Days: 1},
+	}, evts)
+}
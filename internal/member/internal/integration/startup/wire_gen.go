@@ -17,6 +17,7 @@ import (
 func InitService() service.Service {
 	db := testioc.InitDB()
 	mq := testioc.InitMQ()
-	serviceService := member.InitService(db, mq)
+	cache := testioc.InitCache()
+	serviceService := member.InitService(db, mq, cache)
 	return serviceService
 }
@@ -0,0 +1,93 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/member/internal/event"
+	"github.com/ecodeclub/webook/internal/member/internal/service"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// ExpiryReminderJob 扫描即将到期的会员，发送到期提醒事件
+// 每天执行一次，对于 days 中的每一个 N，提醒在 N 天后那一天到期的会员
+type ExpiryReminderJob struct {
+	svc      service.Service
+	producer event.ExpiryReminderEventProducer
+	days     []int
+	limit    int
+	timeout  time.Duration
+	logger   *elog.Component
+}
+
+func NewExpiryReminderJob(svc service.Service, producer event.ExpiryReminderEventProducer,
+	days []int, limit int, timeout time.Duration) *ExpiryReminderJob {
+	return &ExpiryReminderJob{
+		svc:      svc,
+		producer: producer,
+		days:     days,
+		limit:    limit,
+		timeout:  timeout,
+		logger:   elog.DefaultLogger,
+	}
+}
+
+func (e *ExpiryReminderJob) Name() string {
+	return "ExpiryReminderJob"
+}
+
+func (e *ExpiryReminderJob) Run() error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), e.timeout)
+	defer cancelFunc()
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	for _, d := range e.days {
+		start := today.AddDate(0, 0, d)
+		err := e.remind(ctx, d, start.UnixMilli(), start.AddDate(0, 0, 1).UnixMilli())
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *ExpiryReminderJob) remind(ctx context.Context, days int, start, end int64) error {
+	offset := 0
+	for {
+		members, err := e.svc.ListExpiringMembers(ctx, start, end, offset, e.limit)
+		if err != nil {
+			return fmt.Errorf("获取即将到期的会员失败: %w", err)
+		}
+		for _, m := range members {
+			err = e.producer.Produce(ctx, event.ExpiryReminderEvent{
+				Uid:   m.UID,
+				Tier:  uint8(m.Tier),
+				EndAt: m.EndAt,
+				Days:  days,
+			})
+			if err != nil {
+				// 单个用户发送失败不影响其他用户
+				e.logger.Error("发送会员到期提醒失败", elog.FieldErr(err), elog.Int64("uid", m.UID))
+			}
+		}
+		if len(members) < e.limit {
+			return nil
+		}
+		offset += len(members)
+	}
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecodeclub/ecache"
+)
+
+type MemberCache interface {
+	// IncrVersion 会员信息每变更一次，版本号加一
+	IncrVersion(ctx context.Context, uid int64) (int64, error)
+	// GetVersion 没有记录的时候返回 0
+	GetVersion(ctx context.Context, uid int64) (int64, error)
+}
+
+type MemberECache struct {
+	ec ecache.Cache
+}
+
+func NewMemberECache(ec ecache.Cache) MemberCache {
+	return &MemberECache{
+		ec: &ecache.NamespaceCache{
+			Namespace: "member:",
+			C:         ec,
+		},
+	}
+}
+
+func (m *MemberECache) IncrVersion(ctx context.Context, uid int64) (int64, error) {
+	return m.ec.IncrBy(ctx, m.versionKey(uid), 1)
+}
+
+func (m *MemberECache) GetVersion(ctx context.Context, uid int64) (int64, error) {
+	val := m.ec.Get(ctx, m.versionKey(uid))
+	if val.KeyNotFound() {
+		return 0, nil
+	}
+	return val.AsInt64()
+}
+
+// 注意 Namespace 设置
+func (m *MemberECache) versionKey(uid int64) string {
+	return fmt.Sprintf("version:%d", uid)
+}
@@ -16,14 +16,30 @@ package dao
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ego-component/egorm"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+var ErrDuplicateMemberRecord = errors.New("会员变更记录已存在")
+
 type MemberDAO interface {
+	// FindByUID 返回的 Tier 是当前时段的会员等级
 	FindByUID(ctx context.Context, uid int64) (Member, error)
 	Create(ctx context.Context, member Member) (int64, error)
+	// Upsert 延长会员时长，会员未过期的时候在原结束日期上叠加，已过期或者不存在的时候从现在开始计算。
+	// 每次延长都是一个单独的时段，高等级的时段优先使用，低等级的时段顺延到后面，
+	// 所以不同等级的时长叠加在一起的时候，各自的时长和等级都不会变化
+	// 同一个 Key 的变更记录只会生效一次，重复的时候返回 ErrDuplicateMemberRecord
+	Upsert(ctx context.Context, uid int64, tier uint8, duration int64, r MemberRecord) (Member, error)
+	FindRecordsByUID(ctx context.Context, uid int64) ([]MemberRecord, error)
+	// FindExpiring 查找结束日期在 [start, end) 之间的会员
+	FindExpiring(ctx context.Context, start, end int64, offset, limit int) ([]Member, error)
 }
 
 type memberGROMDAO struct {
@@ -37,24 +53,205 @@ func NewMemberGORMDAO(db *egorm.Component) MemberDAO {
 func (g *memberGROMDAO) FindByUID(ctx context.Context, uid int64) (Member, error) {
 	var m Member
 	err := g.db.WithContext(ctx).First(&m, "uid", uid).Error
+	if err != nil {
+		return m, err
+	}
+	// 会员表里面的等级是最后一次变更时的等级，时段切换之后以时段为准
+	now := time.Now().UnixMilli()
+	var periods []MemberPeriod
+	err = g.db.WithContext(ctx).
+		Where("uid = ? AND start_at <= ? AND end_at > ?", uid, now, now).
+		Limit(1).Find(&periods).Error
+	if err == nil && len(periods) > 0 {
+		m.Tier = periods[0].Tier
+	}
 	return m, err
 }
 
 func (g *memberGROMDAO) Create(ctx context.Context, member Member) (int64, error) {
 	now := time.Now().UnixMilli()
 	member.Ctime, member.Utime = now, now
-	if err := g.db.WithContext(ctx).Create(&member).Error; err != nil {
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&member).Error; err != nil {
+			return err
+		}
+		if member.EndAt <= member.StartAt {
+			return nil
+		}
+		return tx.Create(&MemberPeriod{
+			Uid:     member.Uid,
+			Tier:    member.Tier,
+			StartAt: member.StartAt,
+			EndAt:   member.EndAt,
+			Ctime:   now,
+			Utime:   now,
+		}).Error
+	})
+	if err != nil {
 		return 0, err
 	}
 	return member.Id, nil
 }
 
+func (g *memberGROMDAO) Upsert(ctx context.Context, uid int64, tier uint8, duration int64, r MemberRecord) (Member, error) {
+	var m Member
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uid = ?", uid).First(&m).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			m = Member{Uid: uid, Tier: tier, Ctime: now}
+		case err != nil:
+			return fmt.Errorf("查找会员记录失败: %w", err)
+		}
+		oldEndAt := m.EndAt
+		if m.EndAt < now {
+			// 已经过期的会员重新开始计算，过期的等级也不再算数
+			m.StartAt, m.EndAt = now, now
+			m.Tier = tier
+		}
+		m.EndAt += duration
+		m.Tier, err = g.addPeriod(tx, m, oldEndAt, tier, duration, now)
+		if err != nil {
+			return fmt.Errorf("更新会员时段失败: %w", err)
+		}
+		m.Utime = now
+		if err = tx.Save(&m).Error; err != nil {
+			return fmt.Errorf("更新会员记录失败: %w", err)
+		}
+
+		r.Uid = uid
+		r.Tier = tier
+		r.Duration = duration
+		r.OldEndAt = oldEndAt
+		r.NewEndAt = m.EndAt
+		r.Ctime, r.Utime = now, now
+		err = tx.Create(&r).Error
+		if me, ok := err.(*mysql.MySQLError); ok {
+			const uniqueIndexErrNo uint16 = 1062
+			if me.Number == uniqueIndexErrNo {
+				return ErrDuplicateMemberRecord
+			}
+		}
+		return err
+	})
+	return m, err
+}
+
+// addPeriod 插入一个新的时段，返回当前时段的等级。
+// 没有过期的时段按照等级从高到低排列，新的时段放在所有等级不低于它的时段后面，
+// 后面等级更低的时段整体往后顺延，正在进行中的低等级时段会被拆成两段
+func (g *memberGROMDAO) addPeriod(tx *gorm.DB, m Member, oldEndAt int64, tier uint8, duration int64, now int64) (uint8, error) {
+	var periods []MemberPeriod
+	err := tx.Where("uid = ? AND end_at > ?", m.Uid, now).
+		Order("start_at ASC").Find(&periods).Error
+	if err != nil {
+		return 0, err
+	}
+	if len(periods) == 0 && oldEndAt > now {
+		// 没有时段记录的老会员，剩下的时长当做一个时段
+		p := MemberPeriod{Uid: m.Uid, Tier: m.Tier, StartAt: now, EndAt: oldEndAt, Ctime: now, Utime: now}
+		if err = tx.Create(&p).Error; err != nil {
+			return 0, err
+		}
+		periods = append(periods, p)
+	}
+
+	startAt := now
+	for _, p := range periods {
+		if p.Tier < tier {
+			break
+		}
+		startAt = max(startAt, p.EndAt)
+	}
+	for _, p := range periods {
+		if p.EndAt <= startAt {
+			continue
+		}
+		if p.StartAt < startAt {
+			// 正在进行中的低等级时段，已经用掉的部分保留，剩下的部分顺延
+			err = tx.Model(&p).Updates(map[string]any{"end_at": startAt, "utime": now}).Error
+			if err != nil {
+				return 0, err
+			}
+			err = tx.Create(&MemberPeriod{Uid: m.Uid, Tier: p.Tier,
+				StartAt: startAt + duration, EndAt: p.EndAt + duration, Ctime: now, Utime: now}).Error
+		} else {
+			err = tx.Model(&p).Updates(map[string]any{
+				"start_at": p.StartAt + duration,
+				"end_at":   p.EndAt + duration,
+				"utime":    now,
+			}).Error
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	err = tx.Create(&MemberPeriod{Uid: m.Uid, Tier: tier,
+		StartAt: startAt, EndAt: startAt + duration, Ctime: now, Utime: now}).Error
+	if err != nil {
+		return 0, err
+	}
+	if startAt == now || len(periods) == 0 {
+		return tier, nil
+	}
+	return periods[0].Tier, nil
+}
+
+func (g *memberGROMDAO) FindRecordsByUID(ctx context.Context, uid int64) ([]MemberRecord, error) {
+	var res []MemberRecord
+	err := g.db.WithContext(ctx).
+		Where("uid = ?", uid).
+		Order("id DESC").
+		Find(&res).Error
+	return res, err
+}
+
 // Member 会员表,每个用户只有一条记录,后续只需要修改开始、结束日期及状态即可
 type Member struct {
 	Id      int64 `gorm:"primaryKey;autoIncrement;comment:会员表自增ID"`
 	Uid     int64 `gorm:"not null;uniqueIndex:unq_user_id;comment: 用户ID"`
+	Tier    uint8 `gorm:"not null;default:1;comment: 会员等级 1=普通会员 2=高级会员"`
 	StartAt int64 `gorm:"not null;comment: 会员开始日期,UTC Unix毫秒数"`
-	EndAt   int64 `gorm:"not null;comment: 会员结束日期,UTC Unix毫秒数"`
+	EndAt   int64 `gorm:"not null;index:idx_end_at;comment: 会员结束日期,UTC Unix毫秒数"`
 	Ctime   int64
 	Utime   int64
 }
+
+// MemberPeriod 会员时段，每个时段有自己的等级，同一个用户的时段之间不会重叠
+type MemberPeriod struct {
+	Id      int64 `gorm:"primaryKey;autoIncrement;comment:会员时段自增ID"`
+	Uid     int64 `gorm:"not null;index:idx_uid_end_at;comment: 用户ID"`
+	Tier    uint8 `gorm:"not null;comment: 这个时段的会员等级"`
+	StartAt int64 `gorm:"not null;comment: 时段开始日期,UTC Unix毫秒数"`
+	EndAt   int64 `gorm:"not null;index:idx_uid_end_at;comment: 时段结束日期,UTC Unix毫秒数"`
+	Ctime   int64
+	Utime   int64
+}
+
+func (g *memberGROMDAO) FindExpiring(ctx context.Context, start, end int64, offset, limit int) ([]Member, error) {
+	var res []Member
+	err := g.db.WithContext(ctx).
+		Where("end_at >= ? AND end_at < ?", start, end).
+		Order("id ASC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+// MemberRecord 会员变更记录,Key 用来保证同一个来源的变更只会生效一次
+type MemberRecord struct {
+	Id       int64  `gorm:"primaryKey;autoIncrement;comment:会员变更记录自增ID"`
+	Key      string `gorm:"type:varchar(256);not null;uniqueIndex:unq_key;comment: 变更来源的唯一标识"`
+	Uid      int64  `gorm:"not null;index:idx_uid;comment: 用户ID"`
+	Biz      string `gorm:"type:varchar(64);not null;default:'';comment: 变更来源的业务"`
+	BizId    int64  `gorm:"not null;default:0;comment: 变更来源的业务ID"`
+	Action   string `gorm:"type:varchar(256);not null;default:'';comment: 变更原因"`
+	Tier     uint8  `gorm:"not null;comment: 本次变更对应的会员等级"`
+	Duration int64  `gorm:"not null;comment: 延长的时长,毫秒数"`
+	OldEndAt int64  `gorm:"not null;comment: 变更前的会员结束日期,UTC Unix毫秒数"`
+	NewEndAt int64  `gorm:"not null;comment: 变更后的会员结束日期,UTC Unix毫秒数"`
+	Ctime    int64
+	Utime    int64
+}
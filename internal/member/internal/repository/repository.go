@@ -2,8 +2,11 @@ package repository
 
 import (
 	"context"
+	"time"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/member/internal/domain"
+	"github.com/ecodeclub/webook/internal/member/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/member/internal/repository/dao"
 )
 
@@ -11,16 +14,27 @@ type MemberRepository interface {
 	FindByUID(ctx context.Context, uid int64) (domain.Member, error)
 	Create(ctx context.Context, member domain.Member) (int64, error)
 	// Update(ctx context.Context, member domain.Member) error
+	// Upsert 延长会员时长，同一个来源重复变更的时候返回 ErrDuplicateMemberRecord
+	Upsert(ctx context.Context, uid int64, duration time.Duration, src domain.Source) (domain.Member, error)
+	FindRecordsByUID(ctx context.Context, uid int64) ([]domain.MemberRecord, error)
+	FindExpiring(ctx context.Context, start, end int64, offset, limit int) ([]domain.Member, error)
+	// IncrVersion 会员信息变更后递增版本号，返回新的版本号
+	IncrVersion(ctx context.Context, uid int64) (int64, error)
+	GetVersion(ctx context.Context, uid int64) (int64, error)
 }
 
-func NewMemberRepository(d dao.MemberDAO) MemberRepository {
+var ErrDuplicateMemberRecord = dao.ErrDuplicateMemberRecord
+
+func NewMemberRepository(d dao.MemberDAO, c cache.MemberCache) MemberRepository {
 	return &memberRepository{
-		dao: d,
+		dao:   d,
+		cache: c,
 	}
 }
 
 type memberRepository struct {
-	dao dao.MemberDAO
+	dao   dao.MemberDAO
+	cache cache.MemberCache
 }
 
 func (m *memberRepository) FindByUID(ctx context.Context, userID int64) (domain.Member, error) {
@@ -35,6 +49,7 @@ func (m *memberRepository) toDomain(d dao.Member) domain.Member {
 	return domain.Member{
 		ID:      d.Id,
 		UID:     d.Uid,
+		Tier:    domain.Tier(d.Tier),
 		StartAt: d.StartAt,
 		EndAt:   d.EndAt,
 	}
@@ -48,7 +63,53 @@ func (m *memberRepository) toEntity(d domain.Member) dao.Member {
 	return dao.Member{
 		Id:      d.ID,
 		Uid:     d.UID,
+		Tier:    uint8(d.Tier),
 		StartAt: d.StartAt,
 		EndAt:   d.EndAt,
 	}
 }
+
+func (m *memberRepository) Upsert(ctx context.Context, uid int64, duration time.Duration, src domain.Source) (domain.Member, error) {
+	d, err := m.dao.Upsert(ctx, uid, uint8(src.Tier), duration.Milliseconds(), dao.MemberRecord{
+		Key:    src.Key,
+		Biz:    src.Biz,
+		BizId:  src.BizId,
+		Action: src.Action,
+	})
+	if err != nil {
+		return domain.Member{}, err
+	}
+	return m.toDomain(d), nil
+}
+
+func (m *memberRepository) FindRecordsByUID(ctx context.Context, uid int64) ([]domain.MemberRecord, error) {
+	records, err := m.dao.FindRecordsByUID(ctx, uid)
+	return slice.Map(records, func(idx int, src dao.MemberRecord) domain.MemberRecord {
+		return domain.MemberRecord{
+			Key:      src.Key,
+			Biz:      src.Biz,
+			BizId:    src.BizId,
+			Action:   src.Action,
+			Tier:     domain.Tier(src.Tier),
+			Duration: src.Duration,
+			OldEndAt: src.OldEndAt,
+			NewEndAt: src.NewEndAt,
+			Ctime:    src.Ctime,
+		}
+	}), err
+}
+
+func (m *memberRepository) FindExpiring(ctx context.Context, start, end int64, offset, limit int) ([]domain.Member, error) {
+	members, err := m.dao.FindExpiring(ctx, start, end, offset, limit)
+	return slice.Map(members, func(idx int, src dao.Member) domain.Member {
+		return m.toDomain(src)
+	}), err
+}
+
+func (m *memberRepository) IncrVersion(ctx context.Context, uid int64) (int64, error) {
+	return m.cache.IncrVersion(ctx, uid)
+}
+
+func (m *memberRepository) GetVersion(ctx context.Context, uid int64) (int64, error) {
+	return m.cache.GetVersion(ctx, uid)
+}
@@ -16,23 +16,44 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/ecodeclub/webook/internal/member/internal/domain"
+	"github.com/ecodeclub/webook/internal/member/internal/event"
 	"github.com/ecodeclub/webook/internal/member/internal/repository"
+	"github.com/gotomicro/ego/core/elog"
 )
 
 //go:generate mockgen -source=./service.go -package=membermocks --destination=../../mocks/member.mock.go -typed Service
 type Service interface {
 	GetMembershipInfo(ctx context.Context, userID int64) (domain.Member, error)
 	CreateNewMembership(ctx context.Context, member domain.Member) (int64, error)
+	// ExtendMembership 延长会员时长，会员未过期的时候在原有结束日期上叠加
+	// 同一个 source.Key 只会生效一次，重复调用直接返回当前的会员信息
+	ExtendMembership(ctx context.Context, uid int64, duration time.Duration, source domain.Source) (domain.Member, error)
+	// GetMembershipRecords 会员变更记录，按照时间倒序排列
+	GetMembershipRecords(ctx context.Context, uid int64) ([]domain.MemberRecord, error)
+	// GetMembershipVersion 会员信息的版本号，每次变更都会递增
+	// 用于通知持有旧会员信息的会话刷新
+	GetMembershipVersion(ctx context.Context, uid int64) (int64, error)
+	// ListExpiringMembers 结束日期在 [start, end) 之间的会员，UTC Unix毫秒数
+	ListExpiringMembers(ctx context.Context, start, end int64, offset, limit int) ([]domain.Member, error)
 }
 
 type service struct {
-	repo repository.MemberRepository
+	repo     repository.MemberRepository
+	producer event.MemberEventProducer
+	logger   *elog.Component
 }
 
-func NewMemberService(repo repository.MemberRepository) Service {
-	return &service{repo: repo}
+func NewMemberService(repo repository.MemberRepository, producer event.MemberEventProducer) Service {
+	return &service{
+		repo:     repo,
+		producer: producer,
+		logger:   elog.DefaultLogger,
+	}
 }
 
 func (s *service) GetMembershipInfo(ctx context.Context, userID int64) (domain.Member, error) {
@@ -40,5 +61,71 @@ func (s *service) GetMembershipInfo(ctx context.Context, userID int64) (domain.M
 }
 
 func (s *service) CreateNewMembership(ctx context.Context, member domain.Member) (int64, error) {
-	return s.repo.Create(ctx, member)
+	if member.Tier == 0 {
+		member.Tier = domain.TierNormal
+	}
+	id, err := s.repo.Create(ctx, member)
+	if err != nil {
+		return 0, err
+	}
+	member.ID = id
+	s.notifyChanged(ctx, member, domain.Source{Action: "开通会员", Tier: member.Tier})
+	return id, nil
+}
+
+func (s *service) ExtendMembership(ctx context.Context, uid int64, duration time.Duration, source domain.Source) (domain.Member, error) {
+	if source.Key == "" {
+		return domain.Member{}, fmt.Errorf("会员变更来源的 Key 不能为空")
+	}
+	if duration <= 0 {
+		return domain.Member{}, fmt.Errorf("会员延长时长非法 %s", duration)
+	}
+	if source.Tier == 0 {
+		source.Tier = domain.TierNormal
+	}
+	m, err := s.repo.Upsert(ctx, uid, duration, source)
+	if errors.Is(err, repository.ErrDuplicateMemberRecord) {
+		// 已经处理过的来源，保持幂等
+		return s.repo.FindByUID(ctx, uid)
+	}
+	if err != nil {
+		return domain.Member{}, err
+	}
+	s.notifyChanged(ctx, m, source)
+	return m, nil
+}
+
+// notifyChanged 递增版本号并发送变更事件
+// 会员信息已经变更成功，所以这里出错只记录日志，最多导致会话中的会员信息延迟刷新
+func (s *service) notifyChanged(ctx context.Context, m domain.Member, source domain.Source) {
+	ver, err := s.repo.IncrVersion(ctx, m.UID)
+	if err != nil {
+		s.logger.Error("递增会员版本号失败", elog.FieldErr(err), elog.Int64("uid", m.UID))
+	}
+	err = s.producer.Produce(ctx, event.MemberEvent{
+		Uid:     m.UID,
+		Tier:    uint8(m.Tier),
+		StartAt: m.StartAt,
+		EndAt:   m.EndAt,
+		Version: ver,
+		Key:     source.Key,
+		Biz:     source.Biz,
+		BizId:   source.BizId,
+		Action:  source.Action,
+	})
+	if err != nil {
+		s.logger.Error("发送会员变更事件失败", elog.FieldErr(err), elog.Int64("uid", m.UID))
+	}
+}
+
+func (s *service) GetMembershipRecords(ctx context.Context, uid int64) ([]domain.MemberRecord, error) {
+	return s.repo.FindRecordsByUID(ctx, uid)
+}
+
+func (s *service) GetMembershipVersion(ctx context.Context, uid int64) (int64, error) {
+	return s.repo.GetVersion(ctx, uid)
+}
+
+func (s *service) ListExpiringMembers(ctx context.Context, start, end int64, offset, limit int) ([]domain.Member, error) {
+	return s.repo.FindExpiring(ctx, start, end, offset, limit)
 }
@@ -11,6 +11,7 @@ package membermocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/ecodeclub/webook/internal/member/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -78,6 +79,45 @@ func (c *ServiceCreateNewMembershipCall) DoAndReturn(f func(context.Context, dom
 	return c
 }
 
+// ExtendMembership mocks base method.
+func (m *MockService) ExtendMembership(ctx context.Context, uid int64, duration time.Duration, source domain.Source) (domain.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExtendMembership", ctx, uid, duration, source)
+	ret0, _ := ret[0].(domain.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExtendMembership indicates an expected call of ExtendMembership.
+func (mr *MockServiceMockRecorder) ExtendMembership(ctx, uid, duration, source any) *ServiceExtendMembershipCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExtendMembership", reflect.TypeOf((*MockService)(nil).ExtendMembership), ctx, uid, duration, source)
+	return &ServiceExtendMembershipCall{Call: call}
+}
+
+// ServiceExtendMembershipCall wrap *gomock.Call
+type ServiceExtendMembershipCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceExtendMembershipCall) Return(arg0 domain.Member, arg1 error) *ServiceExtendMembershipCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceExtendMembershipCall) Do(f func(context.Context, int64, time.Duration, domain.Source) (domain.Member, error)) *ServiceExtendMembershipCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceExtendMembershipCall) DoAndReturn(f func(context.Context, int64, time.Duration, domain.Source) (domain.Member, error)) *ServiceExtendMembershipCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetMembershipInfo mocks base method.
 func (m *MockService) GetMembershipInfo(ctx context.Context, userID int64) (domain.Member, error) {
 	m.ctrl.T.Helper()
@@ -116,3 +156,120 @@ func (c *ServiceGetMembershipInfoCall) DoAndReturn(f func(context.Context, int64
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// GetMembershipRecords mocks base method.
+func (m *MockService) GetMembershipRecords(ctx context.Context, uid int64) ([]domain.MemberRecord, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembershipRecords", ctx, uid)
+	ret0, _ := ret[0].([]domain.MemberRecord)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembershipRecords indicates an expected call of GetMembershipRecords.
+func (mr *MockServiceMockRecorder) GetMembershipRecords(ctx, uid any) *ServiceGetMembershipRecordsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembershipRecords", reflect.TypeOf((*MockService)(nil).GetMembershipRecords), ctx, uid)
+	return &ServiceGetMembershipRecordsCall{Call: call}
+}
+
+// ServiceGetMembershipRecordsCall wrap *gomock.Call
+type ServiceGetMembershipRecordsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceGetMembershipRecordsCall) Return(arg0 []domain.MemberRecord, arg1 error) *ServiceGetMembershipRecordsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceGetMembershipRecordsCall) Do(f func(context.Context, int64) ([]domain.MemberRecord, error)) *ServiceGetMembershipRecordsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceGetMembershipRecordsCall) DoAndReturn(f func(context.Context, int64) ([]domain.MemberRecord, error)) *ServiceGetMembershipRecordsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// GetMembershipVersion mocks base method.
+func (m *MockService) GetMembershipVersion(ctx context.Context, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMembershipVersion", ctx, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMembershipVersion indicates an expected call of GetMembershipVersion.
+func (mr *MockServiceMockRecorder) GetMembershipVersion(ctx, uid any) *ServiceGetMembershipVersionCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMembershipVersion", reflect.TypeOf((*MockService)(nil).GetMembershipVersion), ctx, uid)
+	return &ServiceGetMembershipVersionCall{Call: call}
+}
+
+// ServiceGetMembershipVersionCall wrap *gomock.Call
+type ServiceGetMembershipVersionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceGetMembershipVersionCall) Return(arg0 int64, arg1 error) *ServiceGetMembershipVersionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceGetMembershipVersionCall) Do(f func(context.Context, int64) (int64, error)) *ServiceGetMembershipVersionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceGetMembershipVersionCall) DoAndReturn(f func(context.Context, int64) (int64, error)) *ServiceGetMembershipVersionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListExpiringMembers mocks base method.
+func (m *MockService) ListExpiringMembers(ctx context.Context, start, end int64, offset, limit int) ([]domain.Member, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiringMembers", ctx, start, end, offset, limit)
+	ret0, _ := ret[0].([]domain.Member)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiringMembers indicates an expected call of ListExpiringMembers.
+func (mr *MockServiceMockRecorder) ListExpiringMembers(ctx, start, end, offset, limit any) *ServiceListExpiringMembersCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiringMembers", reflect.TypeOf((*MockService)(nil).ListExpiringMembers), ctx, start, end, offset, limit)
+	return &ServiceListExpiringMembersCall{Call: call}
+}
+
+// ServiceListExpiringMembersCall wrap *gomock.Call
+type ServiceListExpiringMembersCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceListExpiringMembersCall) Return(arg0 []domain.Member, arg1 error) *ServiceListExpiringMembersCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceListExpiringMembersCall) Do(f func(context.Context, int64, int64, int, int) ([]domain.Member, error)) *ServiceListExpiringMembersCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceListExpiringMembersCall) DoAndReturn(f func(context.Context, int64, int64, int, int) ([]domain.Member, error)) *ServiceListExpiringMembersCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
@@ -14,9 +14,9 @@
 
 package member
 
-import "github.com/ecodeclub/webook/internal/member/internal/event"
+import "github.com/ecodeclub/webook/internal/member/internal/consumer"
 
 type Module struct {
 	Svc Service
-	c   *event.RegistrationEventConsumer
+	c   *consumer.RegistrationEventConsumer
 }
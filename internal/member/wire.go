@@ -19,21 +19,36 @@ package member
 import (
 	"context"
 	"sync"
+	"time"
 
+	"github.com/ecodeclub/ecache"
 	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/member/internal/consumer"
 	"github.com/ecodeclub/webook/internal/member/internal/domain"
 	"github.com/ecodeclub/webook/internal/member/internal/event"
+	"github.com/ecodeclub/webook/internal/member/internal/job"
 	"github.com/ecodeclub/webook/internal/member/internal/repository"
+	"github.com/ecodeclub/webook/internal/member/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/member/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/member/internal/service"
 	"github.com/ego-component/egorm"
 	"github.com/google/wire"
+	"github.com/gotomicro/ego/core/econf"
 )
 
 type Member = domain.Member
+type MemberRecord = domain.MemberRecord
+type Source = domain.Source
+type Tier = domain.Tier
 type Service = service.Service
+type ExpiryReminderJob = job.ExpiryReminderJob
 
-func InitModule(db *egorm.Component, q mq.MQ) (*Module, error) {
+const (
+	TierNormal = domain.TierNormal
+	TierSenior = domain.TierSenior
+)
+
+func InitModule(db *egorm.Component, q mq.MQ, ec ecache.Cache) (*Module, error) {
 	wire.Build(wire.Struct(
 		new(Module), "*"),
 		InitService,
@@ -47,21 +62,52 @@ var (
 	svc  service.Service
 )
 
-func InitService(db *egorm.Component, q mq.MQ) Service {
+func InitService(db *egorm.Component, q mq.MQ, ec ecache.Cache) Service {
 	once.Do(func() {
 		_ = dao.InitTables(db)
 		d := dao.NewMemberGORMDAO(db)
-		r := repository.NewMemberRepository(d)
-		svc = service.NewMemberService(r)
+		c := cache.NewMemberECache(ec)
+		r := repository.NewMemberRepository(d, c)
+		p, err := event.NewMemberEventProducer(q)
+		if err != nil {
+			panic(err)
+		}
+		svc = service.NewMemberService(r, p)
 	})
 	return svc
 }
 
-func initRegistrationConsumer(svc service.Service, q mq.MQ) *event.RegistrationEventConsumer {
-	c, err := event.NewRegistrationEventConsumer(svc, q)
+func initRegistrationConsumer(svc service.Service, q mq.MQ) *consumer.RegistrationEventConsumer {
+	type Config struct {
+		// TrialDays 新用户注册赠送的会员天数
+		TrialDays int `yaml:"trialDays"`
+	}
+	cfg := Config{TrialDays: 7}
+	err := econf.UnmarshalKey("member", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	c, err := consumer.NewRegistrationEventConsumer(svc, q, time.Duration(cfg.TrialDays)*time.Hour*24)
 	if err != nil {
 		panic(err)
 	}
 	c.Start(context.Background())
 	return c
 }
+
+func InitExpiryReminderJob(db *egorm.Component, q mq.MQ, ec ecache.Cache) *ExpiryReminderJob {
+	type Config struct {
+		// ReminderDays 在到期前的第几天发送提醒
+		ReminderDays []int `yaml:"reminderDays"`
+	}
+	cfg := Config{ReminderDays: []int{7, 1}}
+	err := econf.UnmarshalKey("member", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	p, err := event.NewExpiryReminderEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return job.NewExpiryReminderJob(InitService(db, q, ec), p, cfg.ReminderDays, 100, time.Hour)
+}
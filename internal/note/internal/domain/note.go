@@ -0,0 +1,39 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+const (
+	BizQuestion = "question"
+	BizCase     = "case"
+
+	// MaxContentLen 单条笔记的字数上限
+	MaxContentLen = 10000
+	// MaxElement 笔记可以挂在题目答案的某个部分上，0 表示整道题，1 - 4 对应分析、基本、中级、高级
+	MaxElement = 4
+)
+
+// Note 用户自己的笔记，只有自己能看到，Markdown 格式
+type Note struct {
+	Id      int64
+	Uid     int64
+	Biz     string
+	BizId   int64
+	Element uint8
+	Content string
+	Ctime   time.Time
+	Utime   time.Time
+}
@@ -0,0 +1,11 @@
+package errs
+
+var (
+	SystemError = ErrorCode{Code: 517001, Msg: "系统错误"}
+	InvalidNote = ErrorCode{Code: 517002, Msg: "笔记内容非法"}
+)
+
+type ErrorCode struct {
+	Code int
+	Msg  string
+}
@@ -0,0 +1,241 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/note/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/note/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/note/internal/web"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const uid = 5001
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server *egin.Component
+	db     *egorm.Component
+	svc    note.Service
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	module := startup.InitModule()
+	s.svc = module.Svc
+
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid: uid,
+		}))
+	})
+	module.Hdl.MemberRoutes(server.Engine)
+	s.server = server
+	s.db = testioc.InitDB()
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `notes`").Error
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TearDownSuite() {
+	err := s.db.Exec("DROP TABLE `notes`").Error
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TestSave() {
+	testCases := []struct {
+		name    string
+		n       note.Note
+		wantErr error
+		after   func(t *testing.T)
+	}{
+		{
+			name: "题目答案的某个部分",
+			n:    note.Note{Uid: uid, Biz: note.BizQuestion, BizId: 1, Element: 2, Content: "基本回答要提到 MVCC"},
+			after: func(t *testing.T) {
+				n := s.findNote(t, note.BizQuestion, 1, 2)
+				assert.Equal(t, "基本回答要提到 MVCC", n.Content)
+			},
+		},
+		{
+			name: "覆盖原有的笔记",
+			n:    note.Note{Uid: uid, Biz: note.BizCase, BizId: 2, Content: "新的"},
+			after: func(t *testing.T) {
+				n := s.findNote(t, note.BizCase, 2, 0)
+				assert.Equal(t, "新的", n.Content)
+				var cnt int64
+				err := s.db.Model(&dao.Note{}).
+					Where("uid = ? AND biz = ? AND biz_id = ?", uid, note.BizCase, 2).
+					Count(&cnt).Error
+				require.NoError(t, err)
+				assert.Equal(t, int64(1), cnt)
+			},
+		},
+		{
+			name:    "内容为空",
+			n:       note.Note{Uid: uid, Biz: note.BizQuestion, BizId: 1},
+			wantErr: note.ErrInvalidNote,
+		},
+		{
+			name:    "内容太长",
+			n:       note.Note{Uid: uid, Biz: note.BizQuestion, BizId: 1, Content: strings.Repeat("长", 10001)},
+			wantErr: note.ErrInvalidNote,
+		},
+		{
+			name:    "案例没有答案的部分",
+			n:       note.Note{Uid: uid, Biz: note.BizCase, BizId: 1, Element: 1, Content: "亮点"},
+			wantErr: note.ErrInvalidNote,
+		},
+		{
+			name:    "未知的业务",
+			n:       note.Note{Uid: uid, Biz: "skill", BizId: 1, Content: "技能"},
+			wantErr: note.ErrInvalidNote,
+		},
+	}
+	err := s.db.Create(&dao.Note{Uid: uid, Biz: note.BizCase, BizId: 2, Content: "旧的"}).Error
+	require.NoError(s.T(), err)
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+			defer cancel()
+			err := s.svc.Save(ctx, tc.n)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if tc.after != nil {
+				tc.after(t)
+			}
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestList() {
+	s.createNotes()
+	res := s.post("/note/list", web.Page{Offset: 1, Limit: 2})
+	assert.Equal(s.T(), int64(3), res.Total)
+	assert.Equal(s.T(), []int64{2, 1}, s.ids(res.Notes))
+}
+
+func (s *HandlerTestSuite) TestSearch() {
+	s.createNotes()
+	testCases := []struct {
+		name      string
+		req       web.SearchReq
+		wantTotal int64
+		wantIds   []int64
+	}{
+		{
+			name:      "命中多条",
+			req:       web.SearchReq{Keyword: "Redis"},
+			wantTotal: 2,
+			wantIds:   []int64{3, 1},
+		},
+		{
+			name:      "通配符按照字面量处理",
+			req:       web.SearchReq{Keyword: "100%"},
+			wantTotal: 1,
+			wantIds:   []int64{2},
+		},
+		{
+			name:    "关键字为空",
+			req:     web.SearchReq{},
+			wantIds: []int64{},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			res := s.post("/note/search", tc.req)
+			assert.Equal(t, tc.wantTotal, res.Total)
+			assert.Equal(t, tc.wantIds, s.ids(res.Notes))
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestDelete() {
+	s.createNotes()
+	req, err := http.NewRequest(http.MethodPost, "/note/delete",
+		iox.NewJSONReader(web.DeleteReq{Biz: note.BizQuestion, BizId: 1, Element: 1}))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+
+	res := s.post("/note/list", web.Page{})
+	assert.Equal(s.T(), []int64{3, 2}, s.ids(res.Notes))
+}
+
+// createNotes 准备三条笔记，还有一条别人的
+func (s *HandlerTestSuite) createNotes() {
+	notes := []dao.Note{
+		{Id: 1, Uid: uid, Biz: note.BizQuestion, BizId: 1, Element: 1, Content: "Redis 单线程", Utime: 1},
+		{Id: 2, Uid: uid, Biz: note.BizQuestion, BizId: 1, Element: 2, Content: "命中率 100%", Utime: 2},
+		{Id: 3, Uid: uid, Biz: note.BizCase, BizId: 1, Content: "Redis 分布式锁", Utime: 3},
+		{Id: 4, Uid: uid + 1, Biz: note.BizCase, BizId: 1, Content: "Redis 别人的", Utime: 4},
+	}
+	err := s.db.Create(&notes).Error
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) findNote(t *testing.T, biz string, bizId int64, element uint8) dao.Note {
+	var n dao.Note
+	err := s.db.Where("uid = ? AND biz = ? AND biz_id = ? AND element = ?",
+		uid, biz, bizId, element).First(&n).Error
+	require.NoError(t, err)
+	return n
+}
+
+func (s *HandlerTestSuite) post(path string, body any) web.NoteList {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.NoteList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func (s *HandlerTestSuite) ids(ns []web.Note) []int64 {
+	res := make([]int64, 0, len(ns))
+	for _, n := range ns {
+		res = append(res, n.Id)
+	}
+	return res
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
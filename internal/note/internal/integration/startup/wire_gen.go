@@ -0,0 +1,20 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package startup
+
+import (
+	"github.com/ecodeclub/webook/internal/note"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+)
+
+// Injectors from wire.go:
+
+func InitModule() *note.Module {
+	db := testioc.InitDB()
+	module := note.InitModule(db)
+	return module
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NoteDAO interface {
+	// Upsert 同一个用户在同一个位置上只有一条笔记
+	Upsert(ctx context.Context, n Note) error
+	Delete(ctx context.Context, uid int64, biz string, bizId int64, element uint8) error
+	FindByBiz(ctx context.Context, uid int64, biz string, bizId int64) ([]Note, error)
+	// List 最近修改的排在前面，keyword 不为空的时候只返回内容包含 keyword 的
+	List(ctx context.Context, uid int64, keyword string, offset, limit int) ([]Note, error)
+	Count(ctx context.Context, uid int64, keyword string) (int64, error)
+}
+
+type NoteGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewNoteGORMDAO(db *egorm.Component) NoteDAO {
+	return &NoteGORMDAO{db: db}
+}
+
+func (dao *NoteGORMDAO) Upsert(ctx context.Context, n Note) error {
+	now := time.Now().UnixMilli()
+	n.Ctime, n.Utime = now, now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"content", "utime"}),
+	}).Create(&n).Error
+}
+
+func (dao *NoteGORMDAO) Delete(ctx context.Context, uid int64, biz string, bizId int64, element uint8) error {
+	return dao.db.WithContext(ctx).
+		Where("uid = ? AND biz = ? AND biz_id = ? AND element = ?", uid, biz, bizId, element).
+		Delete(&Note{}).Error
+}
+
+func (dao *NoteGORMDAO) FindByBiz(ctx context.Context, uid int64, biz string, bizId int64) ([]Note, error) {
+	var res []Note
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND biz = ? AND biz_id = ?", uid, biz, bizId).
+		Order("element ASC").
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *NoteGORMDAO) List(ctx context.Context, uid int64, keyword string, offset, limit int) ([]Note, error) {
+	var res []Note
+	err := dao.where(ctx, uid, keyword).
+		Order("utime DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *NoteGORMDAO) Count(ctx context.Context, uid int64, keyword string) (int64, error) {
+	var res int64
+	err := dao.where(ctx, uid, keyword).Model(&Note{}).Count(&res).Error
+	return res, err
+}
+
+func (dao *NoteGORMDAO) where(ctx context.Context, uid int64, keyword string) *gorm.DB {
+	db := dao.db.WithContext(ctx).Where("uid = ?", uid)
+	if keyword != "" {
+		// 单个用户的笔记不会太多，直接用 LIKE 就可以了
+		db = db.Where("content LIKE ?", "%"+likeEscaper.Replace(keyword)+"%")
+	}
+	return db
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type Note struct {
+	Id      int64  `gorm:"primaryKey,autoIncrement"`
+	Uid     int64  `gorm:"uniqueIndex:uid_biz_element;index:uid_utime"`
+	Biz     string `gorm:"type:varchar(64);uniqueIndex:uid_biz_element"`
+	BizId   int64  `gorm:"uniqueIndex:uid_biz_element"`
+	Element uint8  `gorm:"uniqueIndex:uid_biz_element"`
+	Content string `gorm:"type:text"`
+	Ctime   int64
+	Utime   int64 `gorm:"index:uid_utime"`
+}
@@ -0,0 +1,98 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/note/internal/domain"
+	"github.com/ecodeclub/webook/internal/note/internal/repository/dao"
+	"golang.org/x/sync/errgroup"
+)
+
+type NoteRepository interface {
+	Save(ctx context.Context, n domain.Note) error
+	Delete(ctx context.Context, uid int64, biz string, bizId int64, element uint8) error
+	FindByBiz(ctx context.Context, uid int64, biz string, bizId int64) ([]domain.Note, error)
+	List(ctx context.Context, uid int64, keyword string, offset, limit int) ([]domain.Note, int64, error)
+}
+
+type noteRepository struct {
+	dao dao.NoteDAO
+}
+
+func NewNoteRepository(d dao.NoteDAO) NoteRepository {
+	return &noteRepository{dao: d}
+}
+
+func (repo *noteRepository) Save(ctx context.Context, n domain.Note) error {
+	return repo.dao.Upsert(ctx, dao.Note{
+		Uid:     n.Uid,
+		Biz:     n.Biz,
+		BizId:   n.BizId,
+		Element: n.Element,
+		Content: n.Content,
+	})
+}
+
+func (repo *noteRepository) Delete(ctx context.Context, uid int64, biz string, bizId int64, element uint8) error {
+	return repo.dao.Delete(ctx, uid, biz, bizId, element)
+}
+
+func (repo *noteRepository) FindByBiz(ctx context.Context, uid int64, biz string, bizId int64) ([]domain.Note, error) {
+	ns, err := repo.dao.FindByBiz(ctx, uid, biz, bizId)
+	return slice.Map(ns, func(idx int, src dao.Note) domain.Note {
+		return repo.toDomain(src)
+	}), err
+}
+
+func (repo *noteRepository) List(ctx context.Context, uid int64, keyword string, offset, limit int) ([]domain.Note, int64, error) {
+	var (
+		eg    errgroup.Group
+		ns    []dao.Note
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		ns, err = repo.dao.List(ctx, uid, keyword, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = repo.dao.Count(ctx, uid, keyword)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, 0, err
+	}
+	return slice.Map(ns, func(idx int, src dao.Note) domain.Note {
+		return repo.toDomain(src)
+	}), total, nil
+}
+
+func (repo *noteRepository) toDomain(n dao.Note) domain.Note {
+	return domain.Note{
+		Id:      n.Id,
+		Uid:     n.Uid,
+		Biz:     n.Biz,
+		BizId:   n.BizId,
+		Element: n.Element,
+		Content: n.Content,
+		Ctime:   time.UnixMilli(n.Ctime),
+		Utime:   time.UnixMilli(n.Utime),
+	}
+}
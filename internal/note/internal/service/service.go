@@ -0,0 +1,81 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ecodeclub/webook/internal/note/internal/domain"
+	"github.com/ecodeclub/webook/internal/note/internal/repository"
+)
+
+var ErrInvalidNote = errors.New("笔记内容非法")
+
+//go:generate mockgen -source=./service.go -destination=../../mocks/note.mock.go -package=notemocks -typed Service
+type Service interface {
+	// Save 覆盖同一个位置上之前的笔记，调用方需要保证 biz 和 bizId 是已经发布了的
+	Save(ctx context.Context, n domain.Note) error
+	Delete(ctx context.Context, uid int64, biz string, bizId int64, element uint8) error
+	// FindByBiz 用户在某道题或者某个案例上的全部笔记，按照 element 排序
+	FindByBiz(ctx context.Context, uid int64, biz string, bizId int64) ([]domain.Note, error)
+	List(ctx context.Context, uid int64, offset, limit int) ([]domain.Note, int64, error)
+	// Search 在用户自己的笔记里面搜索
+	Search(ctx context.Context, uid int64, keyword string, offset, limit int) ([]domain.Note, int64, error)
+}
+
+type service struct {
+	repo repository.NoteRepository
+}
+
+func NewService(repo repository.NoteRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Save(ctx context.Context, n domain.Note) error {
+	if (n.Biz != domain.BizQuestion && n.Biz != domain.BizCase) ||
+		strings.TrimSpace(n.Content) == "" ||
+		utf8.RuneCountInString(n.Content) > domain.MaxContentLen ||
+		n.Element > domain.MaxElement {
+		return ErrInvalidNote
+	}
+	// 案例没有分成几个部分
+	if n.Biz == domain.BizCase && n.Element != 0 {
+		return ErrInvalidNote
+	}
+	return s.repo.Save(ctx, n)
+}
+
+func (s *service) Delete(ctx context.Context, uid int64, biz string, bizId int64, element uint8) error {
+	return s.repo.Delete(ctx, uid, biz, bizId, element)
+}
+
+func (s *service) FindByBiz(ctx context.Context, uid int64, biz string, bizId int64) ([]domain.Note, error) {
+	return s.repo.FindByBiz(ctx, uid, biz, bizId)
+}
+
+func (s *service) List(ctx context.Context, uid int64, offset, limit int) ([]domain.Note, int64, error) {
+	return s.repo.List(ctx, uid, "", offset, limit)
+}
+
+func (s *service) Search(ctx context.Context, uid int64, keyword string, offset, limit int) ([]domain.Note, int64, error) {
+	keyword = strings.TrimSpace(keyword)
+	if keyword == "" {
+		return []domain.Note{}, 0, nil
+	}
+	return s.repo.List(ctx, uid, keyword, offset, limit)
+}
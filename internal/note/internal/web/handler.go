@@ -0,0 +1,84 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/note/internal/domain"
+	"github.com/ecodeclub/webook/internal/note/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const maxLimit = 100
+
+// Handler 保存笔记需要确认题目或者案例已经发布，所以放在题目和案例的模块里面
+type Handler struct {
+	svc service.Service
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) MemberRoutes(server *gin.Engine) {
+	g := server.Group("/note")
+	g.POST("/list", ginx.BS[Page](h.List))
+	g.POST("/search", ginx.BS[SearchReq](h.Search))
+	g.POST("/delete", ginx.BS[DeleteReq](h.Delete))
+}
+
+// List 自己的全部笔记，最近修改的排在前面
+func (h *Handler) List(ctx *ginx.Context, req Page, sess session.Session) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	ns, total, err := h.svc.List(ctx, sess.Claims().Uid, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return h.noteList(ns, total), nil
+}
+
+func (h *Handler) Search(ctx *ginx.Context, req SearchReq, sess session.Session) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	ns, total, err := h.svc.Search(ctx, sess.Claims().Uid, req.Keyword, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return h.noteList(ns, total), nil
+}
+
+func (h *Handler) Delete(ctx *ginx.Context, req DeleteReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Delete(ctx, sess.Claims().Uid, req.Biz, req.BizId, req.Element)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) noteList(ns []domain.Note, total int64) ginx.Result {
+	return ginx.Result{
+		Data: NoteList{
+			Total: total,
+			Notes: slice.Map(ns, func(idx int, src domain.Note) Note {
+				return NewNote(src)
+			}),
+		},
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"time"
+
+	"github.com/ecodeclub/webook/internal/note/internal/domain"
+)
+
+type Page struct {
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+}
+
+type SearchReq struct {
+	Keyword string `json:"keyword"`
+	Offset  int    `json:"offset,omitempty"`
+	Limit   int    `json:"limit,omitempty"`
+}
+
+type DeleteReq struct {
+	Biz     string `json:"biz"`
+	BizId   int64  `json:"bizId"`
+	Element uint8  `json:"element"`
+}
+
+type Note struct {
+	Id    int64  `json:"id"`
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	// Element 0 表示整道题，1 - 4 对应答案的分析、基本、中级、高级
+	Element uint8  `json:"element"`
+	Content string `json:"content"`
+	Utime   string `json:"utime"`
+}
+
+func NewNote(n domain.Note) Note {
+	return Note{
+		Id:      n.Id,
+		Biz:     n.Biz,
+		BizId:   n.BizId,
+		Element: n.Element,
+		Content: n.Content,
+		Utime:   n.Utime.Format(time.DateTime),
+	}
+}
+
+type NoteList struct {
+	Total int64  `json:"total"`
+	Notes []Note `json:"notes"`
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package note
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/note/internal/domain"
+	"github.com/ecodeclub/webook/internal/note/internal/repository"
+	"github.com/ecodeclub/webook/internal/note/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/note/internal/service"
+	"github.com/ecodeclub/webook/internal/note/internal/web"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+func InitModule(db *egorm.Component) *Module {
+	wire.Build(
+		initDAO,
+		repository.NewNoteRepository,
+		service.NewService,
+		web.NewHandler,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module)
+}
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.NoteDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewNoteGORMDAO(db)
+}
+
+const (
+	BizQuestion = domain.BizQuestion
+	BizCase     = domain.BizCase
+)
+
+var (
+	ErrInvalidNote    = service.ErrInvalidNote
+	InvalidNoteResult = web.InvalidNoteResult
+)
+
+type Handler = web.Handler
+type Service = service.Service
+type Note = domain.Note
+type NoteVO = web.Note
+
+// NewNoteVO 在题目和案例的详情里面展示笔记
+func NewNoteVO(n Note) NoteVO {
+	return web.NewNote(n)
+}
+
+// BizNotes 在题目和案例的详情里面展示用户自己的笔记。
+// 笔记是附加信息，查询失败只记录日志，不影响业务对象本身的展示
+func BizNotes(ctx context.Context, svc Service, uid int64, biz string, bizId int64) []NoteVO {
+	ns, err := svc.FindByBiz(ctx, uid, biz, bizId)
+	if err != nil {
+		elog.DefaultLogger.Error("查询笔记失败", elog.FieldErr(err),
+			elog.String("biz", biz), elog.Int64("bizId", bizId))
+	}
+	return slice.Map(ns, func(idx int, src Note) NoteVO {
+		return NewNoteVO(src)
+	})
+}
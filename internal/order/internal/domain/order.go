@@ -37,6 +37,7 @@ type Order struct {
 }
 
 type OrderItem struct {
+	ID               int64
 	OrderID          int64
 	SPUID            int64
 	SKUID            int64
@@ -45,4 +46,8 @@ type OrderItem struct {
 	SKUOriginalPrice int64
 	SKURealPrice     int64
 	Quantity         int64
+	// ReleaseAt 预售商品的发售时间, 订单在发售之后才履约, 0 表示立即履约
+	ReleaseAt int64
+	// FulfilledAt 履约时间, 0 表示还没有履约
+	FulfilledAt int64
 }
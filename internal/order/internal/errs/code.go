@@ -15,7 +15,10 @@
 package errs
 
 var (
-	SystemError = ErrorCode{Code: 506001, Msg: "系统错误"}
+	SystemError       = ErrorCode{Code: 506001, Msg: "系统错误"}
+	InsufficientStock = ErrorCode{Code: 506002, Msg: "商品库存不足"}
+	ExceedStockLimit  = ErrorCode{Code: 506003, Msg: "超出商品限购数量"}
+	NotOnSale         = ErrorCode{Code: 506004, Msg: "商品不在销售时间内"}
 )
 
 type ErrorCode struct {
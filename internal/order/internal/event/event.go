@@ -0,0 +1,27 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+const orderFulfillmentEvents = "order_fulfillment_events"
+
+// FulfillmentEvent 订单项履约事件, 由发放权益的模块消费
+// 同一个订单项可能因为重试发送多次, 消费方需要按照 OrderSN + SKUID 去重
+type FulfillmentEvent struct {
+	OrderSN  string `json:"orderSN"`
+	BuyerID  int64  `json:"buyerId"`
+	SPUID    int64  `json:"spuId"`
+	SKUID    int64  `json:"skuId"`
+	Quantity int64  `json:"quantity"`
+}
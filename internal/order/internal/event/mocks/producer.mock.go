@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./producer.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./producer.go -package=evtmocks -destination=./mocks/producer.mock.go -typed FulfillmentEventProducer
+//
+// Package evtmocks is a generated GoMock package.
+package evtmocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	event "github.com/ecodeclub/webook/internal/order/internal/event"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFulfillmentEventProducer is a mock of FulfillmentEventProducer interface.
+type MockFulfillmentEventProducer struct {
+	ctrl     *gomock.Controller
+	recorder *MockFulfillmentEventProducerMockRecorder
+}
+
+// MockFulfillmentEventProducerMockRecorder is the mock recorder for MockFulfillmentEventProducer.
+type MockFulfillmentEventProducerMockRecorder struct {
+	mock *MockFulfillmentEventProducer
+}
+
+// NewMockFulfillmentEventProducer creates a new mock instance.
+func NewMockFulfillmentEventProducer(ctrl *gomock.Controller) *MockFulfillmentEventProducer {
+	mock := &MockFulfillmentEventProducer{ctrl: ctrl}
+	mock.recorder = &MockFulfillmentEventProducerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFulfillmentEventProducer) EXPECT() *MockFulfillmentEventProducerMockRecorder {
+	return m.recorder
+}
+
+// Produce mocks base method.
+func (m *MockFulfillmentEventProducer) Produce(ctx context.Context, evt event.FulfillmentEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Produce", ctx, evt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Produce indicates an expected call of Produce.
+func (mr *MockFulfillmentEventProducerMockRecorder) Produce(ctx, evt any) *FulfillmentEventProducerProduceCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Produce", reflect.TypeOf((*MockFulfillmentEventProducer)(nil).Produce), ctx, evt)
+	return &FulfillmentEventProducerProduceCall{Call: call}
+}
+
+// FulfillmentEventProducerProduceCall wrap *gomock.Call
+type FulfillmentEventProducerProduceCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *FulfillmentEventProducerProduceCall) Return(arg0 error) *FulfillmentEventProducerProduceCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *FulfillmentEventProducerProduceCall) Do(f func(context.Context, event.FulfillmentEvent) error) *FulfillmentEventProducerProduceCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *FulfillmentEventProducerProduceCall) DoAndReturn(f func(context.Context, event.FulfillmentEvent) error) *FulfillmentEventProducerProduceCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
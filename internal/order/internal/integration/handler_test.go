@@ -22,15 +22,23 @@ import (
 	"net/http"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ecodeclub/ekit/iox"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/cart"
+	cartmocks "github.com/ecodeclub/webook/internal/cart/mocks"
 	"github.com/ecodeclub/webook/internal/credit"
 	creditmocks "github.com/ecodeclub/webook/internal/credit/mocks"
 	"github.com/ecodeclub/webook/internal/order/internal/domain"
 	"github.com/ecodeclub/webook/internal/order/internal/errs"
+	"github.com/ecodeclub/webook/internal/order/internal/event"
+	evtmocks "github.com/ecodeclub/webook/internal/order/internal/event/mocks"
 	"github.com/ecodeclub/webook/internal/order/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/order/internal/job"
+	"github.com/ecodeclub/webook/internal/order/internal/repository"
 	"github.com/ecodeclub/webook/internal/order/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/order/internal/service"
 	"github.com/ecodeclub/webook/internal/order/internal/web"
 	"github.com/ecodeclub/webook/internal/payment"
 	"github.com/ecodeclub/webook/internal/product"
@@ -98,6 +106,38 @@ func (f *fakePaymentService) CreatePayment(ctx context.Context, p payment.Paymen
 				},
 			},
 		},
+		3: {
+			ID:          3,
+			SN:          "PaymentSN-3",
+			OrderID:     p.OrderID,
+			OrderSN:     p.OrderSN,
+			TotalAmount: p.TotalAmount,
+			PayDDL:      p.PayDDL,
+			Records: []payment.Record{
+				{
+					PaymentNO3rd: "credit-3",
+					Channel:      payment.ChannelTypeCredit,
+					Amount:       1180,
+					Status:       0,
+				},
+			},
+		},
+		4: {
+			ID:          4,
+			SN:          "PaymentSN-4",
+			OrderID:     p.OrderID,
+			OrderSN:     p.OrderSN,
+			TotalAmount: p.TotalAmount,
+			PayDDL:      p.PayDDL,
+			Records: []payment.Record{
+				{
+					PaymentNO3rd: "credit-4",
+					Channel:      payment.ChannelTypeCredit,
+					Amount:       2170,
+					Status:       0,
+				},
+			},
+		},
 	}
 	r, ok := columns[id]
 	if !ok {
@@ -135,10 +175,48 @@ func (f *fakePaymentService) FindPaymentByID(ctx context.Context, paymentID int6
 	return p, nil
 }
 
-type fakeProductService struct{}
+// fakeProductService 订单只依赖 FindBySN、FindBySNs 和库存相关的方法，其余方法不会被调用
+type fakeProductService struct {
+	product.Service
+}
+
+// ReserveStock 只模拟限购，SKU102 每个用户限购 1 件
+func (f *fakeProductService) ReserveStock(_ context.Context, _ string, _ int64, items []product.StockItem) error {
+	for _, item := range items {
+		if item.SKUID == 102 && item.Quantity > 1 {
+			return product.ErrExceedStockLimit
+		}
+	}
+	return nil
+}
+
+func (f *fakeProductService) CommitStock(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeProductService) ReleaseStock(_ context.Context, _ string) error {
+	return nil
+}
+
+func (f *fakeProductService) FindBySNs(ctx context.Context, sns []string) ([]product.Product, error) {
+	res := make([]product.Product, 0, len(sns))
+	for _, sn := range sns {
+		p, err := f.FindBySN(ctx, sn)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, p)
+	}
+	return res, nil
+}
 
 func (f *fakeProductService) FindBySN(_ context.Context, sn string) (product.Product, error) {
 	var StatusOnShelf int64 = 2
+	if sn == "SKU104" {
+		// 模拟促销已经结束的商品
+		return product.Product{}, fmt.Errorf("%w: SKU %s", product.ErrNotOnSale, sn)
+	}
+	now := time.Now()
 	products := map[string]product.Product{
 		"SKU100": {
 			SPU: product.SPU{
@@ -178,6 +256,49 @@ func (f *fakeProductService) FindBySN(_ context.Context, sn string) (product.Pro
 				Status:   StatusOnShelf,
 			},
 		},
+		"SKU102": {
+			SPU: product.SPU{
+				ID:     102,
+				SN:     "SPUSN102",
+				Name:   "商品SPU102",
+				Desc:   "商品SPU102描述",
+				Status: StatusOnShelf,
+			},
+			SKU: product.SKU{
+				ID:         102,
+				SN:         "SKU102",
+				Name:       "商品SKU102",
+				Desc:       "商品SKU102",
+				Price:      100,
+				Stock:      10,
+				StockLimit: 1,
+				SaleType:   1, // 无限制
+				Status:     StatusOnShelf,
+			},
+		},
+		"SKU103": {
+			SPU: product.SPU{
+				ID:     103,
+				SN:     "SPUSN103",
+				Name:   "商品SPU103",
+				Desc:   "商品SPU103描述",
+				Status: StatusOnShelf,
+			},
+			SKU: product.SKU{
+				ID:             103,
+				SN:             "SKU103",
+				Name:           "商品SKU103",
+				Desc:           "商品SKU103",
+				Price:          990,
+				Stock:          10,
+				StockLimit:     10,
+				SaleType:       2, // 限时促销
+				SaleStart:      now.Add(-time.Hour).UnixMilli(),
+				SaleEnd:        now.Add(time.Hour).UnixMilli(),
+				PromotionPrice: 590,
+				Status:         StatusOnShelf,
+			},
+		},
 	}
 
 	if _, ok := products[sn]; !ok {
@@ -204,7 +325,18 @@ func (s *HandlerTestSuite) SetupSuite() {
 		TotalAmount: 1000,
 	}, nil)
 
-	handler, err := startup.InitHandler(&fakePaymentService{}, &fakeProductService{}, mockedCreditSvc)
+	// 购物车中有 SKU100 和 SKU103
+	mockedCartSvc := cartmocks.NewMockService(s.ctrl)
+	mockedCartSvc.EXPECT().FindItems(gomock.Any(), testUID, []string{"SKU100", "SKU103"}).AnyTimes().Return([]cart.Item{
+		{SN: "SKU100", Quantity: 1},
+		{SN: "SKU103", Quantity: 2},
+	}, nil)
+	mockedCartSvc.EXPECT().FindItems(gomock.Any(), testUID, []string{"SKU101"}).AnyTimes().
+		Return(nil, fmt.Errorf("%w: SKU101", cart.ErrItemNotFound))
+	// 只有结算成功的时候才会清理购物车
+	mockedCartSvc.EXPECT().RemoveItems(gomock.Any(), testUID, []string{"SKU100", "SKU103"}).Times(1).Return(nil)
+
+	handler, err := startup.InitHandler(&fakePaymentService{}, &fakeProductService{}, mockedCreditSvc, mockedCartSvc)
 	require.NoError(s.T(), err)
 
 	econf.Set("server", map[string]any{"contextTimeout": "1s"})
@@ -277,6 +409,72 @@ func (s *HandlerTestSuite) TestPreviewOrder() {
 				},
 			},
 		},
+		{
+			name: "获取成功_限时促销",
+			req: web.PreviewOrderReq{
+				ProductSKUSN: "SKU103",
+				Quantity:     2,
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.PreviewOrderResp]{
+				Data: web.PreviewOrderResp{
+					Credits: 1000,
+					Payments: []web.Payment{
+						{Type: payment.ChannelTypeCredit},
+						{Type: payment.ChannelTypeWechat},
+					},
+					Products: []web.Product{
+						{
+							SPUSN:         "SPUSN103",
+							SKUSN:         "SKU103",
+							Name:          "商品SKU103",
+							Desc:          "商品SKU103",
+							OriginalPrice: 990,
+							RealPrice:     590,
+							Quantity:      2,
+						},
+					},
+					Policy: "请注意: 虚拟商品、一旦支持成功不退、不换,请谨慎操作",
+				},
+			},
+		},
+		{
+			name: "获取成功_购物车",
+			req: web.PreviewOrderReq{
+				CartItemSNs: []string{"SKU100", "SKU103"},
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.PreviewOrderResp]{
+				Data: web.PreviewOrderResp{
+					Credits: 1000,
+					Payments: []web.Payment{
+						{Type: payment.ChannelTypeCredit},
+						{Type: payment.ChannelTypeWechat},
+					},
+					Products: []web.Product{
+						{
+							SPUSN:         "SPUSN100",
+							SKUSN:         "SKU100",
+							Name:          "商品SKU100",
+							Desc:          "商品SKU100",
+							OriginalPrice: 990,
+							RealPrice:     990,
+							Quantity:      1,
+						},
+						{
+							SPUSN:         "SPUSN103",
+							SKUSN:         "SKU103",
+							Name:          "商品SKU103",
+							Desc:          "商品SKU103",
+							OriginalPrice: 990,
+							RealPrice:     590,
+							Quantity:      2,
+						},
+					},
+					Policy: "请注意: 虚拟商品、一旦支持成功不退、不换,请谨慎操作",
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
@@ -336,6 +534,29 @@ func (s *HandlerTestSuite) TestPreviewOrderFailed() {
 				Msg:  errs.SystemError.Msg,
 			},
 		},
+		{
+			name: "商品不在销售时间内",
+			req: web.PreviewOrderReq{
+				ProductSKUSN: "SKU104",
+				Quantity:     1,
+			},
+			wantCode: 500,
+			wantResp: test.Result[any]{
+				Code: errs.NotOnSale.Code,
+				Msg:  errs.NotOnSale.Msg,
+			},
+		},
+		{
+			name: "购物车中没有选中的商品",
+			req: web.PreviewOrderReq{
+				CartItemSNs: []string{"SKU101"},
+			},
+			wantCode: 500,
+			wantResp: test.Result[any]{
+				Code: errs.SystemError.Code,
+				Msg:  errs.SystemError.Msg,
+			},
+		},
 		// todo: 要购买商品超过库存限制(stockLimit)但是库存充足
 	}
 	for _, tc := range testCases {
@@ -408,6 +629,47 @@ func (s *HandlerTestSuite) TestCreateOrderAndPayment() {
 				assert.NotZero(t, result.Data.WechatCodeURL)
 			},
 		},
+		{
+			name: "创建成功_限时促销",
+			req: web.CreateOrderReq{
+				RequestID: "requestID11",
+				Products: []web.Product{
+					{
+						SKUSN:    "SKU103",
+						Quantity: 2,
+					},
+				},
+				Payments: []web.Payment{
+					{Type: payment.ChannelTypeCredit},
+					{Type: payment.ChannelTypeWechat},
+				},
+				OriginalTotalPrice: 2 * 990,
+				RealTotalPrice:     2 * 590,
+			},
+			wantCode: 200,
+			assertRespFunc: func(t *testing.T, result test.Result[web.CreateOrderResp]) {
+				t.Helper()
+				assert.NotZero(t, result.Data.OrderSN)
+			},
+		},
+		{
+			name: "创建成功_购物车结算",
+			req: web.CreateOrderReq{
+				RequestID:   "requestID13",
+				CartItemSNs: []string{"SKU100", "SKU103"},
+				Payments: []web.Payment{
+					{Type: payment.ChannelTypeCredit},
+					{Type: payment.ChannelTypeWechat},
+				},
+				OriginalTotalPrice: 990 + 2*990,
+				RealTotalPrice:     990 + 2*590,
+			},
+			wantCode: 200,
+			assertRespFunc: func(t *testing.T, result test.Result[web.CreateOrderResp]) {
+				t.Helper()
+				assert.NotZero(t, result.Data.OrderSN)
+			},
+		},
 	}
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
@@ -574,7 +836,56 @@ func (s *HandlerTestSuite) TestCreateOrderAndPaymentFailed() {
 				Msg:  errs.SystemError.Msg,
 			},
 		},
-		// todo: 要购买商品超过库存限制(stockLimit)但是库存充足
+		{
+			name: "要购买商品超过限购数量但是库存充足",
+			req: web.CreateOrderReq{
+				RequestID: "requestID10",
+				Products: []web.Product{
+					{
+						SKUSN:    "SKU102",
+						Quantity: 2,
+					},
+				},
+				OriginalTotalPrice: 2 * 100,
+				RealTotalPrice:     2 * 100,
+			},
+			wantCode: 500,
+			wantResp: test.Result[any]{
+				Code: errs.ExceedStockLimit.Code,
+				Msg:  errs.ExceedStockLimit.Msg,
+			},
+		},
+		{
+			name: "要购买商品不在销售时间内",
+			req: web.CreateOrderReq{
+				RequestID: "requestID12",
+				Products: []web.Product{
+					{
+						SKUSN:    "SKU104",
+						Quantity: 1,
+					},
+				},
+				OriginalTotalPrice: 100,
+				RealTotalPrice:     100,
+			},
+			wantCode: 500,
+			wantResp: test.Result[any]{
+				Code: errs.NotOnSale.Code,
+				Msg:  errs.NotOnSale.Msg,
+			},
+		},
+		{
+			name: "购物车中没有选中的商品",
+			req: web.CreateOrderReq{
+				RequestID:   "requestID14",
+				CartItemSNs: []string{"SKU101"},
+			},
+			wantCode: 500,
+			wantResp: test.Result[any]{
+				Code: errs.SystemError.Code,
+				Msg:  errs.SystemError.Msg,
+			},
+		},
 	}
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
@@ -722,6 +1033,16 @@ func (s *HandlerTestSuite) TestCompleteOrder() {
 						SKURealPrice:     9900,
 						Quantity:         1,
 					},
+					{
+						SPUId:            2,
+						SKUId:            2,
+						SKUName:          "预售商品SKU",
+						SKUDescription:   "预售商品SKU描述",
+						SKUOriginalPrice: 9900,
+						SKURealPrice:     9900,
+						Quantity:         1,
+						ReleaseAt:        time.Now().Add(time.Hour).UnixMilli(),
+					},
 				})
 				require.NoError(t, err)
 			},
@@ -730,6 +1051,12 @@ func (s *HandlerTestSuite) TestCompleteOrder() {
 				order, err := s.dao.FindOrderBySNAndBuyerID(context.Background(), "orderSN-22", testUID)
 				assert.NoError(t, err)
 				assert.Equal(t, int64(domain.OrderStatusCompleted), order.Status)
+				// 普通商品立刻履约, 预售商品等到发售之后再履约
+				items, err := s.dao.FindOrderItemsByOrderID(context.Background(), order.Id)
+				require.NoError(t, err)
+				require.Len(t, items, 2)
+				assert.NotZero(t, items[0].FulfilledAt)
+				assert.Zero(t, items[1].FulfilledAt)
 			},
 			req: web.CompleteOrderReq{
 				OrderSN: "orderSN-22",
@@ -814,6 +1141,91 @@ func (s *HandlerTestSuite) TestCompleteOrderFailed() {
 	}
 }
 
+func (s *HandlerTestSuite) TestFulfillPresaleOrdersJob() {
+	t := s.T()
+	ctx := context.Background()
+	now := time.Now()
+	newItem := func(skuID int64, releaseAt time.Time) dao.OrderItem {
+		return dao.OrderItem{
+			SPUId:            skuID,
+			SKUId:            skuID,
+			SKUName:          fmt.Sprintf("SKUName-%d", skuID),
+			SKUDescription:   fmt.Sprintf("SKUDescription-%d", skuID),
+			SKUOriginalPrice: 100,
+			SKURealPrice:     100,
+			Quantity:         1,
+			ReleaseAt:        releaseAt.UnixMilli(),
+		}
+	}
+	orders := []struct {
+		order dao.Order
+		items []dao.OrderItem
+	}{
+		// 已经发售, 需要履约
+		{
+			order: dao.Order{SN: "OrderSN-fulfill-1", BuyerId: 501, Status: domain.OrderStatusCompleted},
+			items: []dao.OrderItem{newItem(1, now.Add(-time.Minute)), newItem(2, now.Add(-time.Hour))},
+		},
+		{
+			order: dao.Order{SN: "OrderSN-fulfill-2", BuyerId: 502, Status: domain.OrderStatusCompleted},
+			items: []dao.OrderItem{newItem(3, now.Add(-time.Second))},
+		},
+		// 还没有发售
+		{
+			order: dao.Order{SN: "OrderSN-fulfill-3", BuyerId: 503, Status: domain.OrderStatusCompleted},
+			items: []dao.OrderItem{newItem(4, now.Add(time.Hour))},
+		},
+		// 还没有支付
+		{
+			order: dao.Order{SN: "OrderSN-fulfill-4", BuyerId: 504, Status: domain.OrderStatusUnpaid},
+			items: []dao.OrderItem{newItem(5, now.Add(-time.Minute))},
+		},
+	}
+	for _, o := range orders {
+		_, err := s.dao.CreateOrder(ctx, o.order, o.items)
+		require.NoError(t, err)
+	}
+	// 已经履约过了
+	_, err := s.dao.CreateOrder(ctx, dao.Order{SN: "OrderSN-fulfill-5", BuyerId: 505, Status: domain.OrderStatusCompleted},
+		[]dao.OrderItem{newItem(6, now.Add(-time.Minute))})
+	require.NoError(t, err)
+	err = s.db.WithContext(ctx).Model(&dao.OrderItem{}).Where("sku_id = ?", 6).Update("fulfilled_at", now.UnixMilli()).Error
+	require.NoError(t, err)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	producer := evtmocks.NewMockFulfillmentEventProducer(ctrl)
+	var evts []event.FulfillmentEvent
+	producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, evt event.FulfillmentEvent) error {
+			evts = append(evts, evt)
+			if evt.SKUID == 3 {
+				return fmt.Errorf("mock error")
+			}
+			return nil
+		}).Times(3)
+
+	svc := service.NewService(repository.NewRepository(s.dao), &fakeProductService{}, producer)
+	// limit 设置为 1，验证分批查询
+	j := job.NewFulfillPresaleOrdersJob(svc, 1, time.Minute)
+	require.NoError(t, j.Run())
+	assert.ElementsMatch(t, []event.FulfillmentEvent{
+		{OrderSN: "OrderSN-fulfill-1", BuyerID: 501, SPUID: 1, SKUID: 1, Quantity: 1},
+		{OrderSN: "OrderSN-fulfill-1", BuyerID: 501, SPUID: 2, SKUID: 2, Quantity: 1},
+		{OrderSN: "OrderSN-fulfill-2", BuyerID: 502, SPUID: 3, SKUID: 3, Quantity: 1},
+	}, evts)
+
+	var items []dao.OrderItem
+	require.NoError(t, s.db.WithContext(ctx).Order("sku_id ASC").Find(&items).Error)
+	require.Len(t, items, 6)
+	fulfilled := make(map[int64]bool, len(items))
+	for _, item := range items {
+		fulfilled[item.SKUId] = item.FulfilledAt > 0
+	}
+	// 发送失败的订单项撤销了履约标记, 下一次还会重试
+	assert.Equal(t, map[int64]bool{1: true, 2: true, 3: false, 4: false, 5: false, 6: true}, fulfilled)
+}
+
 func (s *HandlerTestSuite) TestCloseTimeoutOrders() {
 
 	total := 15
@@ -17,6 +17,7 @@
 package startup
 
 import (
+	"github.com/ecodeclub/webook/internal/cart"
 	"github.com/ecodeclub/webook/internal/credit"
 	"github.com/ecodeclub/webook/internal/order"
 	"github.com/ecodeclub/webook/internal/order/internal/web"
@@ -26,7 +27,7 @@ import (
 	"github.com/google/wire"
 )
 
-func InitHandler(paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service) (*web.Handler, error) {
+func InitHandler(paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, cartSvc cart.Service) (*web.Handler, error) {
 	wire.Build(testioc.BaseSet, order.InitHandler)
 	return new(web.Handler), nil
 }
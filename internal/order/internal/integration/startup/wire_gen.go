@@ -7,6 +7,7 @@
 package startup
 
 import (
+	"github.com/ecodeclub/webook/internal/cart"
 	"github.com/ecodeclub/webook/internal/credit"
 	"github.com/ecodeclub/webook/internal/order"
 	"github.com/ecodeclub/webook/internal/order/internal/web"
@@ -17,9 +18,10 @@ import (
 
 // Injectors from wire.go:
 
-func InitHandler(paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service) (*web.Handler, error) {
+func InitHandler(paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, cartSvc cart.Service) (*web.Handler, error) {
 	db := testioc.InitDB()
+	mq := testioc.InitMQ()
 	cache := testioc.InitCache()
-	handler := order.InitHandler(db, paymentSvc, productSvc, creditSvc, cache)
+	handler := order.InitHandler(db, mq, paymentSvc, productSvc, creditSvc, cartSvc, cache)
 	return handler, nil
 }
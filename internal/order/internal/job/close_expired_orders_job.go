@@ -19,8 +19,6 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/ecodeclub/ekit/slice"
-	"github.com/ecodeclub/webook/internal/order/internal/domain"
 	"github.com/ecodeclub/webook/internal/order/internal/service"
 )
 
@@ -49,11 +47,7 @@ func (c *CloseExpiredOrdersJob) Run() error {
 			return fmt.Errorf("获取过期订单失败: %w", err)
 		}
 
-		ids := slice.Map(orders, func(idx int, src domain.Order) int64 {
-			return src.ID
-		})
-
-		err = c.svc.CloseExpiredOrders(ctx, ids)
+		err = c.svc.CloseExpiredOrders(ctx, orders)
 		if err != nil {
 			return fmt.Errorf("关闭过期订单失败: %w", err)
 		}
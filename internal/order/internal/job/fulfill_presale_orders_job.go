@@ -0,0 +1,62 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/order/internal/service"
+)
+
+// FulfillPresaleOrdersJob 履约已经到了发售时间的预售订单项
+// 同时也会重试立即履约时失败的订单项
+type FulfillPresaleOrdersJob struct {
+	svc     service.Service
+	limit   int
+	timeout time.Duration
+}
+
+func NewFulfillPresaleOrdersJob(svc service.Service, limit int, timeout time.Duration) *FulfillPresaleOrdersJob {
+	return &FulfillPresaleOrdersJob{svc: svc, limit: limit, timeout: timeout}
+}
+
+func (f *FulfillPresaleOrdersJob) Name() string {
+	return "FulfillPresaleOrdersJob"
+}
+
+func (f *FulfillPresaleOrdersJob) Run() error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), f.timeout)
+	defer cancelFunc()
+
+	now := time.Now().UnixMilli()
+	// 按照 ID 往后翻页, 履约失败的订单项不会被反复查询出来
+	var minID int64
+	for {
+		items, err := f.svc.ListUnfulfilledOrderItems(ctx, now, minID, f.limit)
+		if err != nil {
+			return fmt.Errorf("获取待履约的订单项失败: %w", err)
+		}
+		err = f.svc.FulfillOrderItems(ctx, items)
+		if err != nil {
+			return fmt.Errorf("履约订单项失败: %w", err)
+		}
+		if len(items) < f.limit {
+			return nil
+		}
+		minID = items[len(items)-1].ID
+	}
+}
@@ -36,6 +36,14 @@ type OrderDAO interface {
 	CountExpiredOrders(ctx context.Context, ctime int64) (int64, error)
 	ListExpiredOrders(ctx context.Context, offset, limit int, ctime int64) ([]Order, error)
 	UpdateExpiredOrders(ctx context.Context, orderIDs []int64) error
+
+	FindOrdersByIDs(ctx context.Context, ids []int64) ([]Order, error)
+	// ListUnfulfilledOrderItems 已完成订单中到了发售时间还没有履约的订单项, 按照 id 升序, 只返回 id 大于 minID 的
+	ListUnfulfilledOrderItems(ctx context.Context, releaseAt int64, minID int64, limit int) ([]OrderItem, error)
+	// SetOrderItemFulfilled 标记订单项已经履约, 已经被标记过的时候返回 false
+	SetOrderItemFulfilled(ctx context.Context, id int64, fulfilledAt int64) (bool, error)
+	// ResetOrderItemFulfilled 取消履约标记, 以便之后重新履约
+	ResetOrderItemFulfilled(ctx context.Context, id int64) error
 }
 
 func NewOrderGORMDAO(db *egorm.Component) OrderDAO {
@@ -121,6 +129,41 @@ func (g *gormOrderDAO) UpdateExpiredOrders(ctx context.Context, orderIDs []int64
 	}).Error
 }
 
+func (g *gormOrderDAO) FindOrdersByIDs(ctx context.Context, ids []int64) ([]Order, error) {
+	var res []Order
+	err := g.db.WithContext(ctx).Where("id IN ?", ids).Find(&res).Error
+	return res, err
+}
+
+func (g *gormOrderDAO) ListUnfulfilledOrderItems(ctx context.Context, releaseAt int64, minID int64, limit int) ([]OrderItem, error) {
+	var res []OrderItem
+	err := g.db.WithContext(ctx).Model(&OrderItem{}).
+		Select("order_items.*").
+		Joins("JOIN orders ON orders.id = order_items.order_id").
+		Where("order_items.fulfilled_at = 0 AND order_items.release_at <= ? AND order_items.id > ? AND orders.status = ?",
+			releaseAt, minID, OrderStatusCompleted).
+		Order("order_items.id ASC").Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (g *gormOrderDAO) SetOrderItemFulfilled(ctx context.Context, id int64, fulfilledAt int64) (bool, error) {
+	// 条件更新, 多个实例同时履约的时候只有一个能够成功
+	res := g.db.WithContext(ctx).Model(&OrderItem{}).
+		Where("id = ? AND fulfilled_at = 0", id).
+		Updates(map[string]any{
+			"fulfilled_at": fulfilledAt,
+			"utime":        time.Now().UnixMilli(),
+		})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (g *gormOrderDAO) ResetOrderItemFulfilled(ctx context.Context, id int64) error {
+	return g.db.WithContext(ctx).Model(&OrderItem{}).Where("id = ?", id).Updates(map[string]any{
+		"fulfilled_at": 0,
+		"utime":        time.Now().UnixMilli(),
+	}).Error
+}
+
 const (
 	OrderStatusUnpaid    = iota + 1 // 未支付
 	OrderStatusCompleted            // 已完成(已支付)
@@ -152,6 +195,8 @@ type OrderItem struct {
 	SKUOriginalPrice int64  `gorm:"column:sku_original_price;not null;comment:商品原始单价;单位为分, 999表示9.99元"`
 	SKURealPrice     int64  `gorm:"column:sku_real_price;not null;comment:商品实付单价;单位为分, 999表示9.99元"`
 	Quantity         int64  `gorm:"not null;comment:购买数量"`
+	ReleaseAt        int64  `gorm:"not null;default:0;index:idx_fulfilled_at_release_at,priority:2;comment:预售商品的发售时间,UTC Unix毫秒数,0表示立即履约"`
+	FulfilledAt      int64  `gorm:"not null;default:0;index:idx_fulfilled_at_release_at,priority:1;comment:履约时间,UTC Unix毫秒数,0表示还没有履约"`
 	Ctime            int64
 	Utime            int64
 }
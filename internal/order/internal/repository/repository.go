@@ -35,6 +35,11 @@ type OrderRepository interface {
 	TotalExpiredOrders(ctx context.Context, ctime int64) (int64, error)
 	ListExpiredOrders(ctx context.Context, offset, limit int, ctime int64) ([]domain.Order, error)
 	CloseExpiredOrders(ctx context.Context, orderIDs []int64) error
+
+	FindOrdersByIDs(ctx context.Context, ids []int64) ([]domain.Order, error)
+	ListUnfulfilledOrderItems(ctx context.Context, releaseAt int64, minID int64, limit int) ([]domain.OrderItem, error)
+	SetOrderItemFulfilled(ctx context.Context, id int64, fulfilledAt int64) (bool, error)
+	ResetOrderItemFulfilled(ctx context.Context, id int64) error
 }
 
 func NewRepository(d dao.OrderDAO) OrderRepository {
@@ -80,6 +85,7 @@ func (o *orderRepository) toOrderItemEntities(orderItems []domain.OrderItem) []d
 			SKUOriginalPrice: src.SKUOriginalPrice,
 			SKURealPrice:     src.SKURealPrice,
 			Quantity:         src.Quantity,
+			ReleaseAt:        src.ReleaseAt,
 		}
 	})
 }
@@ -112,22 +118,29 @@ func (o *orderRepository) toOrderDomain(order dao.Order, orderItems []dao.OrderI
 		ClosedAt:           order.ClosedAt,
 		Status:             order.Status,
 		Items: slice.Map(orderItems, func(idx int, src dao.OrderItem) domain.OrderItem {
-			return domain.OrderItem{
-				OrderID:          src.OrderId,
-				SPUID:            src.SPUId,
-				SKUID:            src.SKUId,
-				SKUName:          src.SKUName,
-				SKUDescription:   src.SKUDescription,
-				SKUOriginalPrice: src.SKUOriginalPrice,
-				SKURealPrice:     src.SKURealPrice,
-				Quantity:         src.Quantity,
-			}
+			return o.toOrderItemDomain(src)
 		}),
 		Ctime: order.Ctime,
 		Utime: order.Utime,
 	}
 }
 
+func (o *orderRepository) toOrderItemDomain(item dao.OrderItem) domain.OrderItem {
+	return domain.OrderItem{
+		ID:               item.Id,
+		OrderID:          item.OrderId,
+		SPUID:            item.SPUId,
+		SKUID:            item.SKUId,
+		SKUName:          item.SKUName,
+		SKUDescription:   item.SKUDescription,
+		SKUOriginalPrice: item.SKUOriginalPrice,
+		SKURealPrice:     item.SKURealPrice,
+		Quantity:         item.Quantity,
+		ReleaseAt:        item.ReleaseAt,
+		FulfilledAt:      item.FulfilledAt,
+	}
+}
+
 func (o *orderRepository) FindOrderBySNAndBuyerID(ctx context.Context, sn string, buyerID int64) (domain.Order, error) {
 	order, err := o.dao.FindOrderBySNAndBuyerID(ctx, sn, buyerID)
 	if err != nil {
@@ -176,3 +189,31 @@ func (o *orderRepository) ListExpiredOrders(ctx context.Context, offset, limit i
 func (o *orderRepository) CloseExpiredOrders(ctx context.Context, orderIDs []int64) error {
 	return o.dao.UpdateExpiredOrders(ctx, orderIDs)
 }
+
+func (o *orderRepository) FindOrdersByIDs(ctx context.Context, ids []int64) ([]domain.Order, error) {
+	os, err := o.dao.FindOrdersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(os, func(idx int, src dao.Order) domain.Order {
+		return o.toOrderDomain(src, nil)
+	}), nil
+}
+
+func (o *orderRepository) ListUnfulfilledOrderItems(ctx context.Context, releaseAt int64, minID int64, limit int) ([]domain.OrderItem, error) {
+	items, err := o.dao.ListUnfulfilledOrderItems(ctx, releaseAt, minID, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src dao.OrderItem) domain.OrderItem {
+		return o.toOrderItemDomain(src)
+	}), nil
+}
+
+func (o *orderRepository) SetOrderItemFulfilled(ctx context.Context, id int64, fulfilledAt int64) (bool, error) {
+	return o.dao.SetOrderItemFulfilled(ctx, id, fulfilledAt)
+}
+
+func (o *orderRepository) ResetOrderItemFulfilled(ctx context.Context, id int64) error {
+	return o.dao.ResetOrderItemFulfilled(ctx, id)
+}
@@ -19,8 +19,12 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/order/internal/domain"
+	"github.com/ecodeclub/webook/internal/order/internal/event"
 	"github.com/ecodeclub/webook/internal/order/internal/repository"
+	"github.com/ecodeclub/webook/internal/product"
+	"github.com/gotomicro/ego/core/elog"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -31,20 +35,48 @@ type Service interface {
 	CompleteOrder(ctx context.Context, order domain.Order) error
 	ListOrders(ctx context.Context, offset, limit int, uid int64) ([]domain.Order, int64, error)
 	ListExpiredOrders(ctx context.Context, offset, limit int, ctime int64) ([]domain.Order, int64, error)
-	CloseExpiredOrders(ctx context.Context, orderIDs []int64) error
+	// CloseExpiredOrders 关闭超时订单并释放预留的库存
+	CloseExpiredOrders(ctx context.Context, orders []domain.Order) error
 	CancelOrder(ctx context.Context, order domain.Order) error
+	// ListUnfulfilledOrderItems 已完成订单中到了发售时间还没有履约的订单项, 按照 ID 升序
+	ListUnfulfilledOrderItems(ctx context.Context, releaseAt int64, minID int64, limit int) ([]domain.OrderItem, error)
+	// FulfillOrderItems 履约订单项, 单个订单项履约失败只记录日志, 等待下一次重试
+	FulfillOrderItems(ctx context.Context, items []domain.OrderItem) error
 }
 
-func NewService(repo repository.OrderRepository) Service {
-	return &service{repo: repo}
+func NewService(repo repository.OrderRepository, productSvc product.Service, producer event.FulfillmentEventProducer) Service {
+	return &service{repo: repo, productSvc: productSvc, producer: producer, logger: elog.DefaultLogger}
 }
 
 type service struct {
-	repo repository.OrderRepository
+	repo       repository.OrderRepository
+	productSvc product.Service
+	producer   event.FulfillmentEventProducer
+	logger     *elog.Component
 }
 
 func (s *service) CreateOrder(ctx context.Context, order domain.Order) (domain.Order, error) {
-	return s.repo.CreateOrder(ctx, order)
+	// 先预留库存，库存不足或者超出限购数量的时候不会创建订单
+	err := s.productSvc.ReserveStock(ctx, order.SN, order.BuyerID,
+		slice.Map(order.Items, func(idx int, src domain.OrderItem) product.StockItem {
+			return product.StockItem{SKUID: src.SKUID, Quantity: src.Quantity}
+		}))
+	if err != nil {
+		return domain.Order{}, err
+	}
+	o, err := s.repo.CreateOrder(ctx, order)
+	if err != nil {
+		s.releaseStock(ctx, order.SN)
+		return domain.Order{}, err
+	}
+	return o, nil
+}
+
+// releaseStock 释放失败只记录日志，预留记录还在，可以人工或者重试释放
+func (s *service) releaseStock(ctx context.Context, orderSN string) {
+	if err := s.productSvc.ReleaseStock(ctx, orderSN); err != nil {
+		s.logger.Error("释放库存失败", elog.FieldErr(err), elog.String("orderSN", orderSN))
+	}
 }
 
 func (s *service) FindOrder(ctx context.Context, orderSN string, buyerID int64) (domain.Order, error) {
@@ -58,7 +90,22 @@ func (s *service) UpdateOrder(ctx context.Context, order domain.Order) error {
 func (s *service) CompleteOrder(ctx context.Context, order domain.Order) error {
 	// 已收到用户付款,不管订单状态为什么一律标记为“已完成”
 	order.Status = domain.OrderStatusCompleted
-	return s.repo.UpdateOrder(ctx, order)
+	err := s.repo.UpdateOrder(ctx, order)
+	if err != nil {
+		return err
+	}
+	err = s.productSvc.CommitStock(ctx, order.SN)
+	if err != nil {
+		return err
+	}
+	// 非预售的订单项立刻履约, 预售的订单项等到发售之后由定时任务履约
+	now := time.Now().UnixMilli()
+	for _, item := range order.Items {
+		if item.ReleaseAt <= now {
+			s.fulfill(ctx, order, item)
+		}
+	}
+	return nil
 }
 
 func (s *service) ListOrders(ctx context.Context, offset, limit int, uid int64) ([]domain.Order, int64, error) {
@@ -101,8 +148,20 @@ func (s *service) ListExpiredOrders(ctx context.Context, offset, limit int, ctim
 	return os, total, eg.Wait()
 }
 
-func (s *service) CloseExpiredOrders(ctx context.Context, orderIDs []int64) error {
-	return s.repo.CloseExpiredOrders(ctx, orderIDs)
+func (s *service) CloseExpiredOrders(ctx context.Context, orders []domain.Order) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	err := s.repo.CloseExpiredOrders(ctx, slice.Map(orders, func(idx int, src domain.Order) int64 {
+		return src.ID
+	}))
+	if err != nil {
+		return err
+	}
+	for _, o := range orders {
+		s.releaseStock(ctx, o.SN)
+	}
+	return nil
 }
 
 func (s *service) CancelOrder(ctx context.Context, order domain.Order) error {
@@ -111,5 +170,64 @@ func (s *service) CancelOrder(ctx context.Context, order domain.Order) error {
 	}
 	order.Status = domain.OrderStatusCanceled
 	order.ClosedAt = time.Now().UnixMilli()
-	return s.repo.UpdateOrder(ctx, order)
+	err := s.repo.UpdateOrder(ctx, order)
+	if err != nil {
+		return err
+	}
+	return s.productSvc.ReleaseStock(ctx, order.SN)
+}
+
+func (s *service) ListUnfulfilledOrderItems(ctx context.Context, releaseAt int64, minID int64, limit int) ([]domain.OrderItem, error) {
+	return s.repo.ListUnfulfilledOrderItems(ctx, releaseAt, minID, limit)
+}
+
+func (s *service) FulfillOrderItems(ctx context.Context, items []domain.OrderItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	orders, err := s.repo.FindOrdersByIDs(ctx, slice.Map(items, func(idx int, src domain.OrderItem) int64 {
+		return src.OrderID
+	}))
+	if err != nil {
+		return err
+	}
+	orderMap := slice.ToMap(orders, func(element domain.Order) int64 {
+		return element.ID
+	})
+	for _, item := range items {
+		order, ok := orderMap[item.OrderID]
+		if !ok {
+			s.logger.Error("订单项对应的订单不存在", elog.Int64("orderItemID", item.ID), elog.Int64("orderID", item.OrderID))
+			continue
+		}
+		s.fulfill(ctx, order, item)
+	}
+	return nil
+}
+
+// fulfill 先标记再发送消息, 保证多个实例不会重复履约；发送失败的时候撤销标记, 等待定时任务重试
+func (s *service) fulfill(ctx context.Context, order domain.Order, item domain.OrderItem) {
+	ok, err := s.repo.SetOrderItemFulfilled(ctx, item.ID, time.Now().UnixMilli())
+	if err != nil {
+		s.logger.Error("标记订单项履约失败", elog.FieldErr(err), elog.Int64("orderItemID", item.ID))
+		return
+	}
+	if !ok {
+		// 已经被其他实例履约了
+		return
+	}
+	err = s.producer.Produce(ctx, event.FulfillmentEvent{
+		OrderSN:  order.SN,
+		BuyerID:  order.BuyerID,
+		SPUID:    item.SPUID,
+		SKUID:    item.SKUID,
+		Quantity: item.Quantity,
+	})
+	if err == nil {
+		return
+	}
+	s.logger.Error("订单项履约失败", elog.FieldErr(err), elog.String("orderSN", order.SN), elog.Int64("orderItemID", item.ID))
+	if err = s.repo.ResetOrderItemFulfilled(ctx, item.ID); err != nil {
+		s.logger.Error("撤销订单项履约标记失败", elog.FieldErr(err), elog.Int64("orderItemID", item.ID))
+	}
 }
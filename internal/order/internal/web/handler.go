@@ -16,6 +16,7 @@ package web
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -23,6 +24,7 @@ import (
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/ginx"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/cart"
 	"github.com/ecodeclub/webook/internal/credit"
 	"github.com/ecodeclub/webook/internal/order/internal/domain"
 	"github.com/ecodeclub/webook/internal/order/internal/service"
@@ -30,6 +32,7 @@ import (
 	"github.com/ecodeclub/webook/internal/pkg/sequencenumber"
 	"github.com/ecodeclub/webook/internal/product"
 	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/elog"
 )
 
 var _ ginx.Handler = &Handler{}
@@ -39,12 +42,14 @@ type Handler struct {
 	paymentSvc  payment.Service
 	productSvc  product.Service
 	creditSvc   credit.Service
+	cartSvc     cart.Service
 	snGenerator *sequencenumber.Generator
 	cache       ecache.Cache
+	logger      *elog.Component
 }
 
-func NewHandler(svc service.Service, paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, snGenerator *sequencenumber.Generator, cache ecache.Cache) *Handler {
-	return &Handler{svc: svc, paymentSvc: paymentSvc, productSvc: productSvc, creditSvc: creditSvc, snGenerator: snGenerator, cache: cache}
+func NewHandler(svc service.Service, paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, cartSvc cart.Service, snGenerator *sequencenumber.Generator, cache ecache.Cache) *Handler {
+	return &Handler{svc: svc, paymentSvc: paymentSvc, productSvc: productSvc, creditSvc: creditSvc, cartSvc: cartSvc, snGenerator: snGenerator, cache: cache, logger: elog.DefaultLogger}
 }
 
 func (h *Handler) PrivateRoutes(server *gin.Engine) {
@@ -64,14 +69,30 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 func (h *Handler) PublicRoutes(_ *gin.Engine) {}
 
 // RetrievePreviewOrder 获取订单预览信息, 此时订单尚未创建
+// 传入 CartItemSNs 的时候预览购物车中选中的商品, 否则预览单个商品
 func (h *Handler) RetrievePreviewOrder(ctx *ginx.Context, req PreviewOrderReq, sess session.Session) (ginx.Result, error) {
-	p, err := h.productSvc.FindBySN(ctx.Request.Context(), req.ProductSKUSN)
+	lines := []Product{{SKUSN: req.ProductSKUSN, Quantity: req.Quantity}}
+	if len(req.CartItemSNs) > 0 {
+		var err error
+		lines, err = h.getCartLines(ctx.Request.Context(), sess.Claims().Uid, req.CartItemSNs)
+		if err != nil {
+			return systemErrorResult, err
+		}
+	}
+	products, err := h.productSvc.FindBySNs(ctx.Request.Context(), slice.Map(lines, func(idx int, src Product) string {
+		return src.SKUSN
+	}))
+	if errors.Is(err, product.ErrNotOnSale) {
+		return notOnSaleResult, err
+	}
 	if err != nil {
 		return systemErrorResult, fmt.Errorf("商品SKU序列号非法: %w", err)
 	}
-	if req.Quantity < 1 || req.Quantity > p.SKU.Stock {
-		// todo: 重新审视stockLimit的意义及用法
-		return systemErrorResult, fmt.Errorf("要购买的商品数量非法")
+	for i, p := range products {
+		if lines[i].Quantity < 1 || lines[i].Quantity > p.SKU.Stock {
+			// 这里只是预检，单个用户的限购数量在创建订单预留库存的时候校验
+			return systemErrorResult, fmt.Errorf("要购买的商品数量非法")
+		}
 	}
 	c, err := h.creditSvc.GetCreditsByUID(ctx.Request.Context(), sess.Claims().Uid)
 	if err != nil {
@@ -81,12 +102,23 @@ func (h *Handler) RetrievePreviewOrder(ctx *ginx.Context, req PreviewOrderReq, s
 		Data: PreviewOrderResp{
 			Credits:  c.TotalAmount,
 			Payments: h.toPaymentChannelVO(ctx),
-			Products: h.toProductVO(p, req.Quantity),
+			Products: h.toProductVO(products, lines),
 			Policy:   "请注意: 虚拟商品、一旦支持成功不退、不换,请谨慎操作",
 		},
 	}, nil
 }
 
+// getCartLines 按照 sns 的顺序获取购物车中选中的商品及其数量
+func (h *Handler) getCartLines(ctx context.Context, uid int64, sns []string) ([]Product, error) {
+	items, err := h.cartSvc.FindItems(ctx, uid, sns)
+	if err != nil {
+		return nil, fmt.Errorf("获取购物车中的商品失败: %w", err)
+	}
+	return slice.Map(items, func(idx int, src cart.Item) Product {
+		return Product{SKUSN: src.SN, Quantity: src.Quantity}
+	}), nil
+}
+
 func (h *Handler) toPaymentChannelVO(ctx *ginx.Context) []Payment {
 	pcs := h.paymentSvc.GetPaymentChannels(ctx.Request.Context())
 	channels := make([]Payment, 0, len(pcs))
@@ -96,18 +128,19 @@ func (h *Handler) toPaymentChannelVO(ctx *ginx.Context) []Payment {
 	return channels
 }
 
-func (h *Handler) toProductVO(p product.Product, quantity int64) []Product {
-	return []Product{
-		{
+func (h *Handler) toProductVO(products []product.Product, lines []Product) []Product {
+	now := time.Now().UnixMilli()
+	return slice.Map(products, func(idx int, p product.Product) Product {
+		return Product{
 			SPUSN:         p.SPU.SN,
 			SKUSN:         p.SKU.SN,
 			Name:          p.SKU.Name,
 			Desc:          p.SKU.Desc,
 			OriginalPrice: p.SKU.Price,
-			RealPrice:     p.SKU.Price, // 引入优惠券时, 需要获取用户的优惠信息,动态计算
-			Quantity:      quantity,
-		},
-	}
+			RealPrice:     p.SKU.RealPrice(now), // 引入优惠券时, 需要获取用户的优惠信息,动态计算
+			Quantity:      lines[idx].Quantity,
+		}
+	})
 }
 
 // CreateOrderAndPayment 创建订单和支付
@@ -117,8 +150,25 @@ func (h *Handler) CreateOrderAndPayment(ctx *ginx.Context, req CreateOrderReq, s
 		return systemErrorResult, fmt.Errorf("请求ID错误: %w", err)
 	}
 
-	order, err := h.createOrder(ctx, req, sess.Claims().Uid)
-	if err != nil {
+	uid := sess.Claims().Uid
+	if len(req.CartItemSNs) > 0 {
+		// 结算购物车中选中的商品, 商品数量以购物车为准
+		lines, err := h.getCartLines(ctx.Request.Context(), uid, req.CartItemSNs)
+		if err != nil {
+			return systemErrorResult, err
+		}
+		req.Products = lines
+	}
+
+	order, err := h.createOrder(ctx, req, uid)
+	switch {
+	case errors.Is(err, product.ErrInsufficientStock):
+		return insufficientStockResult, fmt.Errorf("创建订单失败: %w", err)
+	case errors.Is(err, product.ErrExceedStockLimit):
+		return exceedStockLimitResult, fmt.Errorf("创建订单失败: %w", err)
+	case errors.Is(err, product.ErrNotOnSale):
+		return notOnSaleResult, fmt.Errorf("创建订单失败: %w", err)
+	case err != nil:
 		// 创建订单失败
 		return systemErrorResult, fmt.Errorf("创建订单失败: %w", err)
 	}
@@ -136,6 +186,13 @@ func (h *Handler) CreateOrderAndPayment(ctx *ginx.Context, req CreateOrderReq, s
 		return systemErrorResult, fmt.Errorf("订单冗余支付ID及SN失败: %w", err)
 	}
 
+	if len(req.CartItemSNs) > 0 {
+		// 订单已经创建成功, 清理购物车失败不影响下单
+		if er := h.cartSvc.RemoveItems(ctx.Request.Context(), uid, req.CartItemSNs); er != nil {
+			h.logger.Error("清理购物车失败", elog.FieldErr(er), elog.Int64("uid", uid), elog.String("orderSN", order.SN))
+		}
+	}
+
 	// 微信支付需要返回二维码URL
 	var wechatCodeURL string
 	for _, r := range p.Records {
@@ -205,16 +262,21 @@ func (h *Handler) getOrderItems(ctx context.Context, req CreateOrderReq) ([]doma
 	if len(req.Products) == 0 {
 		return nil, 0, 0, fmt.Errorf("商品信息非法")
 	}
+	// 一次查询所有的商品, 返回的顺序和请求的顺序一致
+	products, err := h.productSvc.FindBySNs(ctx, slice.Map(req.Products, func(idx int, src Product) string {
+		return src.SKUSN
+	}))
+	if err != nil {
+		// SN非法
+		return nil, 0, 0, fmt.Errorf("商品SKUSN非法: %w", err)
+	}
 	orderItems := make([]domain.OrderItem, 0, len(req.Products))
 	originalTotalPrice, realTotalPrice := int64(0), int64(0)
-	for _, p := range req.Products {
-		pp, err := h.productSvc.FindBySN(ctx, p.SKUSN)
-		if err != nil {
-			// SN非法
-			return nil, 0, 0, fmt.Errorf("商品SKUSN非法: %w", err)
-		}
+	now := time.Now().UnixMilli()
+	for i, p := range req.Products {
+		pp := products[i]
 		if p.Quantity < 1 || p.Quantity > pp.SKU.Stock {
-			// todo: 重新审视stockLimit的意义及用法
+			// 这里只是预检，库存和限购数量在预留库存的时候原子地校验
 			return nil, 0, 0, fmt.Errorf("商品数量非法")
 		}
 
@@ -224,8 +286,9 @@ func (h *Handler) getOrderItems(ctx context.Context, req CreateOrderReq) ([]doma
 			SKUName:          pp.SKU.Name,
 			SKUDescription:   pp.SKU.Desc,
 			SKUOriginalPrice: pp.SKU.Price,
-			SKURealPrice:     pp.SKU.Price, // 引入优惠券时,需要重新计算
+			SKURealPrice:     pp.SKU.RealPrice(now), // 引入优惠券时,需要重新计算
 			Quantity:         p.Quantity,
+			ReleaseAt:        pp.SKU.ReleaseAt,
 		}
 		originalTotalPrice += item.SKUOriginalPrice * p.Quantity
 		realTotalPrice += item.SKURealPrice * p.Quantity
@@ -293,11 +356,7 @@ func (h *Handler) CloseTimeoutOrders(ctx *ginx.Context, req CloseTimeoutOrdersRe
 			return systemErrorResult, fmt.Errorf("获取过期订单失败: %w", err)
 		}
 
-		ids := slice.Map(orders, func(idx int, src domain.Order) int64 {
-			return src.ID
-		})
-
-		err = h.svc.CloseExpiredOrders(ctx.Request.Context(), ids)
+		err = h.svc.CloseExpiredOrders(ctx.Request.Context(), orders)
 		if err != nil {
 			return systemErrorResult, fmt.Errorf("关闭过期订单失败: %w", err)
 		}
@@ -345,6 +404,7 @@ func (h *Handler) toOrderVO(order domain.Order) Order {
 				SKUOriginalPrice: src.SKUOriginalPrice,
 				SKURealPrice:     src.SKURealPrice,
 				Quantity:         src.Quantity,
+				ReleaseAt:        src.ReleaseAt,
 			}
 		}),
 		Ctime: order.Ctime,
@@ -24,4 +24,16 @@ var (
 		Code: errs.SystemError.Code,
 		Msg:  errs.SystemError.Msg,
 	}
+	insufficientStockResult = ginx.Result{
+		Code: errs.InsufficientStock.Code,
+		Msg:  errs.InsufficientStock.Msg,
+	}
+	exceedStockLimitResult = ginx.Result{
+		Code: errs.ExceedStockLimit.Code,
+		Msg:  errs.ExceedStockLimit.Msg,
+	}
+	notOnSaleResult = ginx.Result{
+		Code: errs.NotOnSale.Code,
+		Msg:  errs.NotOnSale.Msg,
+	}
 )
@@ -18,6 +18,8 @@ package web
 type PreviewOrderReq struct {
 	ProductSKUSN string `json:"sn"`
 	Quantity     int64  `json:"quantity"`
+	// CartItemSNs 购物车中选中的商品, 不为空的时候忽略 ProductSKUSN 和 Quantity
+	CartItemSNs []string `json:"cartItemSNs,omitempty"`
 }
 
 type PreviewOrderResp struct {
@@ -49,6 +51,8 @@ type CreateOrderReq struct {
 	Payments           []Payment `json:"paymentChannels"` // 支付通道
 	OriginalTotalPrice int64     `json:"originalTotalPrice"`
 	RealTotalPrice     int64     `json:"realTotalPrice"`
+	// CartItemSNs 结算购物车中选中的商品, 不为空的时候忽略 Products, 下单成功后从购物车中移除
+	CartItemSNs []string `json:"cartItemSNs,omitempty"`
 }
 
 type CreateOrderResp struct {
@@ -117,6 +121,7 @@ type OrderItem struct {
 	SKUOriginalPrice int64  `json:"skuOriginalPrice"`
 	SKURealPrice     int64  `json:"skuRealPrice"`
 	Quantity         int64  `json:"quantity"`
+	ReleaseAt        int64  `json:"releaseAt,omitempty"`
 }
 
 // CancelOrderReq 取消订单
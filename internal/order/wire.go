@@ -22,8 +22,10 @@ import (
 
 	"github.com/ecodeclub/ecache"
 	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/cart"
 	"github.com/ecodeclub/webook/internal/credit"
 	"github.com/ecodeclub/webook/internal/order/internal/consumer"
+	"github.com/ecodeclub/webook/internal/order/internal/event"
 	"github.com/ecodeclub/webook/internal/order/internal/job"
 	"github.com/ecodeclub/webook/internal/order/internal/repository"
 	"github.com/ecodeclub/webook/internal/order/internal/repository/dao"
@@ -40,13 +42,14 @@ import (
 type Handler = web.Handler
 type CompleteOrderConsumer = consumer.CompleteOrderConsumer
 type CloseExpiredOrdersJob = job.CloseExpiredOrdersJob
+type FulfillPresaleOrdersJob = job.FulfillPresaleOrdersJob
 
 var HandlerSet = wire.NewSet(
 	initService,
 	sequencenumber.NewGenerator,
 	web.NewHandler)
 
-func InitHandler(db *egorm.Component, paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, cache ecache.Cache) *Handler {
+func InitHandler(db *egorm.Component, q mq.MQ, paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, cartSvc cart.Service, cache ecache.Cache) *Handler {
 	wire.Build(HandlerSet)
 	return new(Handler)
 }
@@ -56,17 +59,21 @@ var (
 	svc  service.Service
 )
 
-func initService(db *gorm.DB) service.Service {
+func initService(db *gorm.DB, q mq.MQ, productSvc product.Service) service.Service {
 	once.Do(func() {
 		_ = dao.InitTables(db)
 		orderDAO := dao.NewOrderGORMDAO(db)
 		orderRepository := repository.NewRepository(orderDAO)
-		svc = service.NewService(orderRepository)
+		producer, err := event.NewFulfillmentEventProducer(q)
+		if err != nil {
+			panic(err)
+		}
+		svc = service.NewService(orderRepository, productSvc, producer)
 	})
 	return svc
 }
 
-func InitCompleteOrderConsumer(db *egorm.Component, q mq.MQ) *CompleteOrderConsumer {
+func InitCompleteOrderConsumer(db *egorm.Component, q mq.MQ, productSvc product.Service) *CompleteOrderConsumer {
 	wire.Build(initService, InitMQConsumer, consumer.NewCompleteOrderConsumer)
 	return new(CompleteOrderConsumer)
 }
@@ -81,6 +88,10 @@ func InitMQConsumer(q mq.MQ) []mq.Consumer {
 	return []mq.Consumer{c}
 }
 
-func InitCloseExpiredOrdersJob(db *egorm.Component) *CloseExpiredOrdersJob {
-	return job.NewCloseExpiredOrdersJob(initService(db), 10, 31, time.Hour)
+func InitCloseExpiredOrdersJob(db *egorm.Component, q mq.MQ, productSvc product.Service) *CloseExpiredOrdersJob {
+	return job.NewCloseExpiredOrdersJob(initService(db, q, productSvc), 10, 31, time.Hour)
+}
+
+func InitFulfillPresaleOrdersJob(db *egorm.Component, q mq.MQ, productSvc product.Service) *FulfillPresaleOrdersJob {
+	return job.NewFulfillPresaleOrdersJob(initService(db, q, productSvc), 100, time.Minute)
 }
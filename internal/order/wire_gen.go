@@ -12,12 +12,14 @@ import (
 
 	"github.com/ecodeclub/ecache"
 	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/cart"
 	"github.com/ecodeclub/webook/internal/credit"
 	"github.com/ecodeclub/webook/internal/order/internal/consumer"
+	"github.com/ecodeclub/webook/internal/order/internal/event"
 	"github.com/ecodeclub/webook/internal/order/internal/job"
 	"github.com/ecodeclub/webook/internal/order/internal/repository"
 	"github.com/ecodeclub/webook/internal/order/internal/repository/dao"
-	service4 "github.com/ecodeclub/webook/internal/order/internal/service"
+	"github.com/ecodeclub/webook/internal/order/internal/service"
 	"github.com/ecodeclub/webook/internal/order/internal/web"
 	"github.com/ecodeclub/webook/internal/payment"
 	"github.com/ecodeclub/webook/internal/pkg/sequencenumber"
@@ -29,17 +31,17 @@ import (
 
 // Injectors from wire.go:
 
-func InitHandler(db *gorm.DB, paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, cache ecache.Cache) *web.Handler {
-	serviceService := initService(db)
+func InitHandler(db *gorm.DB, q mq.MQ, paymentSvc payment.Service, productSvc product.Service, creditSvc credit.Service, cartSvc cart.Service, cache ecache.Cache) *web.Handler {
+	service := initService(db, q, productSvc)
 	generator := sequencenumber.NewGenerator()
-	handler := web.NewHandler(serviceService, paymentSvc, productSvc, creditSvc, generator, cache)
+	handler := web.NewHandler(service, paymentSvc, productSvc, creditSvc, cartSvc, generator, cache)
 	return handler
 }
 
-func InitCompleteOrderConsumer(db *gorm.DB, q mq.MQ) *consumer.CompleteOrderConsumer {
-	serviceService := initService(db)
+func InitCompleteOrderConsumer(db *gorm.DB, q mq.MQ, productSvc product.Service) *consumer.CompleteOrderConsumer {
+	service := initService(db, q, productSvc)
 	v := InitMQConsumer(q)
-	completeOrderConsumer := consumer.NewCompleteOrderConsumer(serviceService, v)
+	completeOrderConsumer := consumer.NewCompleteOrderConsumer(service, v)
 	return completeOrderConsumer
 }
 
@@ -51,21 +53,27 @@ type CompleteOrderConsumer = consumer.CompleteOrderConsumer
 
 type CloseExpiredOrdersJob = job.CloseExpiredOrdersJob
 
+type FulfillPresaleOrdersJob = job.FulfillPresaleOrdersJob
+
 var HandlerSet = wire.NewSet(
 	initService, sequencenumber.NewGenerator, web.NewHandler,
 )
 
 var (
 	once = &sync.Once{}
-	svc  service4.Service
+	svc  service.Service
 )
 
-func initService(db *gorm.DB) service4.Service {
+func initService(db *gorm.DB, q mq.MQ, productSvc product.Service) service.Service {
 	once.Do(func() {
 		_ = dao.InitTables(db)
 		orderDAO := dao.NewOrderGORMDAO(db)
 		orderRepository := repository.NewRepository(orderDAO)
-		svc = service4.NewService(orderRepository)
+		producer, err := event.NewFulfillmentEventProducer(q)
+		if err != nil {
+			panic(err)
+		}
+		svc = service.NewService(orderRepository, productSvc, producer)
 	})
 	return svc
 }
@@ -80,6 +88,10 @@ func InitMQConsumer(q mq.MQ) []mq.Consumer {
 	return []mq.Consumer{c}
 }
 
-func InitCloseExpiredOrdersJob(db *egorm.Component) *CloseExpiredOrdersJob {
-	return job.NewCloseExpiredOrdersJob(initService(db), 10, 31, time.Hour)
+func InitCloseExpiredOrdersJob(db *egorm.Component, q mq.MQ, productSvc product.Service) *CloseExpiredOrdersJob {
+	return job.NewCloseExpiredOrdersJob(initService(db, q, productSvc), 10, 31, time.Hour)
+}
+
+func InitFulfillPresaleOrdersJob(db *egorm.Component, q mq.MQ, productSvc product.Service) *FulfillPresaleOrdersJob {
+	return job.NewFulfillPresaleOrdersJob(initService(db, q, productSvc), 100, time.Minute)
 }
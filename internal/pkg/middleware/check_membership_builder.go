@@ -27,10 +27,15 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// GraceHeader 会员处于宽限期时返回的响应头，值为宽限期结束时间，UTC Unix毫秒数
+const GraceHeader = "X-Member-Grace-Until"
+
 type CheckMembershipMiddlewareBuilder struct {
 	svc    member.Service
 	logger *elog.Component
 	sp     session.Provider
+	// gracePeriod 会员到期之后的宽限期，毫秒数
+	gracePeriod int64
 }
 
 func NewCheckMembershipMiddlewareBuilder(svc member.Service) *CheckMembershipMiddlewareBuilder {
@@ -40,6 +45,13 @@ func NewCheckMembershipMiddlewareBuilder(svc member.Service) *CheckMembershipMid
 	}
 }
 
+// GracePeriod 会员到期之后在宽限期内仍然可以访问会员资源，
+// 但是响应中会带上 GraceHeader 提醒前端会员已经到期
+func (c *CheckMembershipMiddlewareBuilder) GracePeriod(d time.Duration) *CheckMembershipMiddlewareBuilder {
+	c.gracePeriod = d.Milliseconds()
+	return c
+}
+
 func (c *CheckMembershipMiddlewareBuilder) Build() gin.HandlerFunc {
 	if c.sp == nil {
 		c.sp = session.DefaultProvider()
@@ -55,17 +67,29 @@ func (c *CheckMembershipMiddlewareBuilder) Build() gin.HandlerFunc {
 
 		claims := sess.Claims()
 		memberDDL, _ := claims.Get("memberDDL").AsInt64()
+		memberVer, _ := claims.Get("memberVer").AsInt64()
+		// 会员信息变更（续费、升级、撤销）之后版本号会递增，
+		// 版本号和 jwt 中的不一致说明 jwt 中的会员信息已经过时
+		ver, err := c.svc.GetMembershipVersion(ctx, claims.Uid)
+		if err != nil {
+			// 查询版本号失败的时候退化为只检查会员截止日期
+			c.logger.Warn("查询会员版本号失败", elog.Int64("uid", claims.Uid), elog.FieldErr(err))
+			ver = memberVer
+		}
 		// 如果 jwt 中的数据格式不对，那么这里就会返回 0
-		// jwt中找到会员截止日期，没有过期
-		if memberDDL > time.Now().UnixMilli() {
+		// jwt中找到会员截止日期，没有过期（或者还在宽限期内），并且会员信息没有变更过
+		if memberDDL+c.gracePeriod > time.Now().UnixMilli() && ver == memberVer {
+			c.setGraceHeader(ctx, memberDDL)
 			return
 		}
 
-		elog.Debug("会员已过期", elog.Int64("uid", claims.Uid),
-			elog.String("ddl", time.UnixMilli(memberDDL).Format(time.DateTime)))
+		elog.Debug("会员已过期或者会员信息已变更", elog.Int64("uid", claims.Uid),
+			elog.String("ddl", time.UnixMilli(memberDDL).Format(time.DateTime)),
+			elog.Int64("ver", memberVer), elog.Int64("newVer", ver))
 
 		// 1. jwt中未找到会员截止日期
 		// 2. jwt中会员已经过期，有可能在这个期间，用户续费了会员，所以要再去实时查询一下
+		// 3. 会员信息已经变更，需要刷新 jwt 中的会员信息
 		// 查询svc
 		info, err := c.svc.GetMembershipInfo(ctx, claims.Uid)
 		if err != nil {
@@ -74,7 +98,7 @@ func (c *CheckMembershipMiddlewareBuilder) Build() gin.HandlerFunc {
 			return
 		}
 
-		if info.EndAt < time.Now().UnixMilli() {
+		if info.EndAt+c.gracePeriod < time.Now().UnixMilli() {
 			elog.Debug("会员已过期", elog.Int64("uid", claims.Uid),
 				elog.String("ddl", time.UnixMilli(info.EndAt).Format(time.DateTime)))
 			gctx.AbortWithStatus(http.StatusForbidden)
@@ -84,6 +108,7 @@ func (c *CheckMembershipMiddlewareBuilder) Build() gin.HandlerFunc {
 		// 在原有jwt数据中添加会员截止日期
 		jwtData := claims.Data
 		jwtData["memberDDL"] = strconv.FormatInt(info.EndAt, 10)
+		jwtData["memberVer"] = strconv.FormatInt(ver, 10)
 		claims.Data = jwtData
 		err = c.sp.UpdateClaims(gctx, claims)
 		if err != nil {
@@ -91,5 +116,13 @@ func (c *CheckMembershipMiddlewareBuilder) Build() gin.HandlerFunc {
 			gctx.AbortWithStatus(http.StatusForbidden)
 			return
 		}
+		c.setGraceHeader(ctx, info.EndAt)
+	}
+}
+
+// setGraceHeader 会员已经到期但是还在宽限期内
+func (c *CheckMembershipMiddlewareBuilder) setGraceHeader(ctx *gin.Context, ddl int64) {
+	if ddl <= time.Now().UnixMilli() {
+		ctx.Header(GraceHeader, strconv.FormatInt(ddl+c.gracePeriod, 10))
 	}
 }
@@ -38,6 +38,9 @@ func TestCheck(t *testing.T) {
 		mock      func(ctrl *gomock.Controller) (member.Service, session.Provider)
 		wantCode  int
 		afterFunc func(t *testing.T, ctx *ginx.Context)
+		// 宽限期
+		gracePeriod time.Duration
+		wantGrace   bool
 	}{
 		{
 			name: "无JWT",
@@ -64,8 +67,10 @@ func TestCheck(t *testing.T) {
 				mockSession := sessmocks.NewMockSession(ctrl)
 				mockSession.EXPECT().Claims().Return(claims)
 				mockP.EXPECT().Get(gomock.Any()).Return(mockSession, nil)
+				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2793)).Return(int64(0), nil)
 
-				return nil, mockP
+				return service, mockP
 			},
 			afterFunc: func(t *testing.T, ctx *ginx.Context) {},
 			wantCode:  200,
@@ -74,6 +79,7 @@ func TestCheck(t *testing.T) {
 			name: "JWT会员过期-续费",
 			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
 				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2795)).Return(int64(0), nil)
 				newExpired := time.Now().Add(time.Hour)
 				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2795)).
 					Return(member.Member{
@@ -99,6 +105,7 @@ func TestCheck(t *testing.T) {
 					SSID: "ssid-2795",
 					Data: map[string]string{
 						"memberDDL": strconv.FormatInt(newExpired.UnixMilli(), 10),
+						"memberVer": "0",
 					},
 				}).Return(nil)
 				return service, provider
@@ -110,6 +117,7 @@ func TestCheck(t *testing.T) {
 			name: "JWT会员过期-会员查找失败",
 			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
 				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2795)).Return(int64(0), nil)
 				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2795)).
 					Return(member.Member{}, errors.New("mock error"))
 
@@ -136,6 +144,7 @@ func TestCheck(t *testing.T) {
 			name: "JWT会员过期-全过期",
 			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
 				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2795)).Return(int64(0), nil)
 				newExpired := time.Now().Add(-time.Hour)
 				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2795)).
 					Return(member.Member{
@@ -166,6 +175,7 @@ func TestCheck(t *testing.T) {
 			name: "JWT会员过期-刷新token失败",
 			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
 				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2795)).Return(int64(0), nil)
 				newExpired := time.Now().Add(time.Hour)
 				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2795)).
 					Return(member.Member{
@@ -191,6 +201,7 @@ func TestCheck(t *testing.T) {
 					SSID: "ssid-2795",
 					Data: map[string]string{
 						"memberDDL": strconv.FormatInt(newExpired.UnixMilli(), 10),
+						"memberVer": "0",
 					},
 				}).Return(errors.New("mock error"))
 				return service, provider
@@ -198,6 +209,187 @@ func TestCheck(t *testing.T) {
 			afterFunc: func(t *testing.T, ctx *ginx.Context) {},
 			wantCode:  403,
 		},
+		{
+			name: "JWT有效会员-会员信息已变更",
+			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
+				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2796)).Return(int64(3), nil)
+				newExpired := time.Now().Add(30 * 24 * time.Hour)
+				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2796)).
+					Return(member.Member{
+						UID:   2796,
+						EndAt: newExpired.UnixMilli(),
+					}, nil)
+
+				mockSession := sessmocks.NewMockSession(ctrl)
+				claims := session.Claims{
+					Uid:  2796,
+					SSID: "ssid-2796",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10),
+						"memberVer": "2",
+					},
+				}
+				mockSession.EXPECT().Claims().Return(claims)
+				provider := sessmocks.NewMockProvider(ctrl)
+				provider.EXPECT().Get(gomock.Any()).Return(mockSession, nil)
+				provider.EXPECT().UpdateClaims(gomock.Any(), session.Claims{
+					Uid:  2796,
+					SSID: "ssid-2796",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(newExpired.UnixMilli(), 10),
+						"memberVer": "3",
+					},
+				}).Return(nil)
+				return service, provider
+			},
+			afterFunc: func(t *testing.T, ctx *ginx.Context) {},
+			wantCode:  200,
+		},
+		{
+			name: "JWT有效会员-会员已被撤销",
+			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
+				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2797)).Return(int64(1), nil)
+				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2797)).
+					Return(member.Member{
+						UID:   2797,
+						EndAt: time.Now().Add(-time.Minute).UnixMilli(),
+					}, nil)
+
+				mockSession := sessmocks.NewMockSession(ctrl)
+				claims := session.Claims{
+					Uid:  2797,
+					SSID: "ssid-2797",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10),
+					},
+				}
+				mockSession.EXPECT().Claims().Return(claims)
+				provider := sessmocks.NewMockProvider(ctrl)
+				provider.EXPECT().Get(gomock.Any()).Return(mockSession, nil)
+				return service, provider
+			},
+			afterFunc: func(t *testing.T, ctx *ginx.Context) {},
+			wantCode:  403,
+		},
+		{
+			name: "JWT有效会员-查询版本号失败",
+			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
+				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2798)).
+					Return(int64(0), errors.New("mock error"))
+
+				mockSession := sessmocks.NewMockSession(ctrl)
+				claims := session.Claims{
+					Uid:  2798,
+					SSID: "ssid-2798",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10),
+						"memberVer": "5",
+					},
+				}
+				mockSession.EXPECT().Claims().Return(claims)
+				provider := sessmocks.NewMockProvider(ctrl)
+				provider.EXPECT().Get(gomock.Any()).Return(mockSession, nil)
+				return service, provider
+			},
+			afterFunc: func(t *testing.T, ctx *ginx.Context) {},
+			wantCode:  200,
+		},
+		{
+			name: "JWT会员过期-宽限期内",
+			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
+				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2799)).Return(int64(1), nil)
+
+				mockSession := sessmocks.NewMockSession(ctrl)
+				claims := session.Claims{
+					Uid:  2799,
+					SSID: "ssid-2799",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(time.Now().Add(-time.Hour).UnixMilli(), 10),
+						"memberVer": "1",
+					},
+				}
+				mockSession.EXPECT().Claims().Return(claims)
+				provider := sessmocks.NewMockProvider(ctrl)
+				provider.EXPECT().Get(gomock.Any()).Return(mockSession, nil)
+				return service, provider
+			},
+			afterFunc:   func(t *testing.T, ctx *ginx.Context) {},
+			gracePeriod: 24 * time.Hour,
+			wantCode:    200,
+			wantGrace:   true,
+		},
+		{
+			name: "JWT会员过期-超出宽限期",
+			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
+				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2800)).Return(int64(1), nil)
+				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2800)).
+					Return(member.Member{
+						UID:   2800,
+						EndAt: time.Now().Add(-48 * time.Hour).UnixMilli(),
+					}, nil)
+
+				mockSession := sessmocks.NewMockSession(ctrl)
+				claims := session.Claims{
+					Uid:  2800,
+					SSID: "ssid-2800",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(time.Now().Add(-48*time.Hour).UnixMilli(), 10),
+						"memberVer": "1",
+					},
+				}
+				mockSession.EXPECT().Claims().Return(claims)
+				provider := sessmocks.NewMockProvider(ctrl)
+				provider.EXPECT().Get(gomock.Any()).Return(mockSession, nil)
+				return service, provider
+			},
+			afterFunc:   func(t *testing.T, ctx *ginx.Context) {},
+			gracePeriod: 24 * time.Hour,
+			wantCode:    403,
+		},
+		{
+			name: "会员信息已变更-刷新后处于宽限期",
+			mock: func(ctrl *gomock.Controller) (member.Service, session.Provider) {
+				service := membermocks.NewMockService(ctrl)
+				service.EXPECT().GetMembershipVersion(gomock.Any(), int64(2801)).Return(int64(2), nil)
+				endAt := time.Now().Add(-time.Hour)
+				service.EXPECT().GetMembershipInfo(gomock.Any(), int64(2801)).
+					Return(member.Member{
+						UID:   2801,
+						EndAt: endAt.UnixMilli(),
+					}, nil)
+
+				mockSession := sessmocks.NewMockSession(ctrl)
+				claims := session.Claims{
+					Uid:  2801,
+					SSID: "ssid-2801",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10),
+						"memberVer": "1",
+					},
+				}
+				mockSession.EXPECT().Claims().Return(claims)
+				provider := sessmocks.NewMockProvider(ctrl)
+				provider.EXPECT().Get(gomock.Any()).Return(mockSession, nil)
+				provider.EXPECT().UpdateClaims(gomock.Any(), session.Claims{
+					Uid:  2801,
+					SSID: "ssid-2801",
+					Data: map[string]string{
+						"memberDDL": strconv.FormatInt(endAt.UnixMilli(), 10),
+						"memberVer": "2",
+					},
+				}).Return(nil)
+				return service, provider
+			},
+			afterFunc:   func(t *testing.T, ctx *ginx.Context) {},
+			gracePeriod: 24 * time.Hour,
+			wantCode:    200,
+			wantGrace:   true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -207,11 +399,12 @@ func TestCheck(t *testing.T) {
 			svc, p := tc.mock(ctrl)
 			w := httptest.NewRecorder()
 			c, _ := gin.CreateTestContext(w)
-			builder := NewCheckMembershipMiddlewareBuilder(svc)
+			builder := NewCheckMembershipMiddlewareBuilder(svc).GracePeriod(tc.gracePeriod)
 			builder.sp = p
 			hdl := builder.Build()
 			hdl(c)
 			assert.Equal(t, tc.wantCode, c.Writer.Status())
+			assert.Equal(t, tc.wantGrace, w.Header().Get(GraceHeader) != "")
 		})
 	}
 }
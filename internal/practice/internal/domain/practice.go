@@ -0,0 +1,110 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import "time"
+
+// Level 回答的层级，和问题答案的四个部分一一对应
+type Level uint8
+
+const (
+	LevelAnalysis Level = iota + 1
+	LevelBasic
+	LevelIntermediate
+	LevelAdvanced
+)
+
+// Levels 全部层级，从浅到深
+var Levels = []Level{LevelAnalysis, LevelBasic, LevelIntermediate, LevelAdvanced}
+
+func (l Level) Valid() bool {
+	return l >= LevelAnalysis && l <= LevelAdvanced
+}
+
+// Status 掌握程度
+type Status uint8
+
+const (
+	StatusUnknown  Status = iota + 1 // 不会
+	StatusFuzzy                      // 模糊
+	StatusMastered                   // 掌握
+)
+
+func (s Status) Valid() bool {
+	return s >= StatusUnknown && s <= StatusMastered
+}
+
+// Session 一次练习。题目在开始的时候就确定下来，
+// 之后题集或者标签发生变化都不会影响已经开始的练习
+type Session struct {
+	Id  int64
+	Uid int64
+	// 练习的来源，例如题集、标签，由调用方决定
+	Biz   string
+	BizId int64
+	Qids  []int64
+	Ctime time.Time
+	Utime time.Time
+}
+
+// Mastery 用户在某道题某个层级上的掌握程度，以最近一次标记为准
+type Mastery struct {
+	Qid    int64
+	Level  Level
+	Status Status
+	Utime  time.Time
+}
+
+// Progress 一组题目的掌握情况
+type Progress struct {
+	// 题目总数
+	Total int
+	// 按照 Levels 的顺序排列，没有标记过的题目不计入任何一种状态
+	Levels []LevelProgress
+}
+
+type LevelProgress struct {
+	Level    Level
+	Mastered int
+	Fuzzy    int
+	Unknown  int
+}
+
+// NewProgress 统计 qids 的掌握情况，不属于 qids 的记录会被忽略
+func NewProgress(qids []int64, masteries []Mastery) Progress {
+	set := make(map[int64]struct{}, len(qids))
+	for _, qid := range qids {
+		set[qid] = struct{}{}
+	}
+	levels := make([]LevelProgress, len(Levels))
+	for i, l := range Levels {
+		levels[i].Level = l
+	}
+	for _, m := range masteries {
+		if _, ok := set[m.Qid]; !ok || !m.Level.Valid() {
+			continue
+		}
+		lp := &levels[m.Level-LevelAnalysis]
+		switch m.Status {
+		case StatusMastered:
+			lp.Mastered++
+		case StatusFuzzy:
+			lp.Fuzzy++
+		case StatusUnknown:
+			lp.Unknown++
+		}
+	}
+	return Progress{Total: len(set), Levels: levels}
+}
@@ -0,0 +1,14 @@
+package errs
+
+var (
+	SystemError        = ErrorCode{Code: 513001, Msg: "系统错误"}
+	SessionNotFound    = ErrorCode{Code: 513002, Msg: "练习不存在"}
+	InvalidMastery     = ErrorCode{Code: 513003, Msg: "掌握程度非法"}
+	QuestionNotInScope = ErrorCode{Code: 513004, Msg: "题目不属于这次练习"}
+	EmptySession       = ErrorCode{Code: 513005, Msg: "练习中没有题目"}
+)
+
+type ErrorCode struct {
+	Code int
+	Msg  string
+}
@@ -0,0 +1,213 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/practice"
+	"github.com/ecodeclub/webook/internal/practice/internal/domain"
+	"github.com/ecodeclub/webook/internal/practice/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/practice/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/practice/internal/service"
+	"github.com/ecodeclub/webook/internal/practice/internal/web"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+const uid = 3001
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server *egin.Component
+	db     *egorm.Component
+	svc    practice.Service
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	module := startup.InitModule()
+	s.svc = module.Svc
+
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid: uid,
+		}))
+	})
+	module.Hdl.MemberRoutes(server.Engine)
+	s.server = server
+	s.db = testioc.InitDB()
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `practice_sessions`").Error
+	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `question_masteries`").Error
+	require.NoError(s.T(), err)
+}
+
+func (s *HandlerTestSuite) TestRecord() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	sid, err := s.svc.StartSession(ctx, domain.Session{
+		Uid:   uid,
+		Biz:   "questionSet",
+		BizId: 1,
+		Qids:  []int64{3, 1, 2},
+	})
+	require.NoError(s.T(), err)
+	otherSid, err := s.svc.StartSession(ctx, domain.Session{
+		Uid:   uid + 1,
+		Biz:   "label",
+		BizId: 1,
+		Qids:  []int64{1},
+	})
+	require.NoError(s.T(), err)
+
+	testCases := []struct {
+		name     string
+		req      web.RecordReq
+		wantCode int
+		wantResp test.Result[any]
+	}{
+		{
+			name:     "标记成功",
+			req:      web.RecordReq{Sid: sid, Qid: 1, Level: 2, Status: 3},
+			wantCode: 200,
+		},
+		{
+			name:     "标记不会",
+			req:      web.RecordReq{Sid: sid, Qid: 3, Level: 2, Status: 1},
+			wantCode: 200,
+		},
+		{
+			name:     "覆盖之前的标记",
+			req:      web.RecordReq{Sid: sid, Qid: 3, Level: 2, Status: 2},
+			wantCode: 200,
+		},
+		{
+			name:     "题目不属于这次练习",
+			req:      web.RecordReq{Sid: sid, Qid: 4, Level: 2, Status: 3},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 513004, Msg: "题目不属于这次练习"},
+		},
+		{
+			name:     "层级非法",
+			req:      web.RecordReq{Sid: sid, Qid: 1, Level: 5, Status: 3},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 513003, Msg: "掌握程度非法"},
+		},
+		{
+			name:     "别人的练习",
+			req:      web.RecordReq{Sid: otherSid, Qid: 1, Level: 1, Status: 3},
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 513002, Msg: "练习不存在"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/practice/record", iox.NewJSONReader(tc.req))
+			require.NoError(t, err)
+			req.Header.Set("content-type", "application/json")
+			recorder := test.NewJSONResponseRecorder[any]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+		})
+	}
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/practice/detail", iox.NewJSONReader(web.Sid{Sid: sid}))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.Session]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	detail := recorder.MustScan().Data
+	assert.NotEmpty(s.T(), detail.Utime)
+	detail.Utime = ""
+	assert.Equal(s.T(), web.Session{
+		Id:    sid,
+		Biz:   "questionSet",
+		BizId: 1,
+		Qids:  []int64{3, 1, 2},
+		Masteries: []web.Mastery{
+			{Qid: 1, Level: 2, Status: 3},
+			{Qid: 3, Level: 2, Status: 2},
+		},
+		Progress: web.Progress{
+			Total: 3,
+			Levels: []web.LevelProgress{
+				{Level: 1},
+				{Level: 2, Mastered: 1, Fuzzy: 1},
+				{Level: 3},
+				{Level: 4},
+			},
+		},
+	}, detail)
+}
+
+func (s *HandlerTestSuite) TestProgress() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	_, err := s.svc.StartSession(ctx, domain.Session{Uid: uid})
+	assert.ErrorIs(s.T(), err, service.ErrEmptySession)
+
+	sid, err := s.svc.StartSession(ctx, domain.Session{Uid: uid, Biz: "label", BizId: 2, Qids: []int64{1, 2}})
+	require.NoError(s.T(), err)
+	require.NoError(s.T(), s.svc.Record(ctx, uid, sid, domain.Mastery{
+		Qid: 1, Level: domain.LevelBasic, Status: domain.StatusMastered}))
+	require.NoError(s.T(), s.svc.Record(ctx, uid, sid, domain.Mastery{
+		Qid: 2, Level: domain.LevelAdvanced, Status: domain.StatusUnknown}))
+
+	// 掌握情况跨练习共享，只统计传入的题目
+	p, err := s.svc.Progress(ctx, uid, []int64{1, 3})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), domain.Progress{
+		Total: 2,
+		Levels: []domain.LevelProgress{
+			{Level: domain.LevelAnalysis},
+			{Level: domain.LevelBasic, Mastered: 1},
+			{Level: domain.LevelIntermediate},
+			{Level: domain.LevelAdvanced},
+		},
+	}, p)
+
+	p, err = s.svc.Progress(ctx, uid+1, []int64{1, 2})
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), 0, p.Levels[1].Mastered)
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PracticeDAO interface {
+	CreateSession(ctx context.Context, s PracticeSession) (int64, error)
+	// GetSession 只能获取 uid 自己的练习
+	GetSession(ctx context.Context, uid int64, id int64) (PracticeSession, error)
+	// Record 覆盖之前的掌握程度，并且更新练习的更新时间
+	Record(ctx context.Context, m QuestionMastery) error
+	FindMasteries(ctx context.Context, uid int64, qids []int64) ([]QuestionMastery, error)
+}
+
+type PracticeGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewPracticeGORMDAO(db *egorm.Component) PracticeDAO {
+	return &PracticeGORMDAO{db: db}
+}
+
+func (dao *PracticeGORMDAO) CreateSession(ctx context.Context, s PracticeSession) (int64, error) {
+	now := time.Now().UnixMilli()
+	s.Ctime, s.Utime = now, now
+	err := dao.db.WithContext(ctx).Create(&s).Error
+	return s.Id, err
+}
+
+func (dao *PracticeGORMDAO) GetSession(ctx context.Context, uid int64, id int64) (PracticeSession, error) {
+	var s PracticeSession
+	err := dao.db.WithContext(ctx).Where("id = ? AND uid = ?", id, uid).First(&s).Error
+	return s, err
+}
+
+func (dao *PracticeGORMDAO) Record(ctx context.Context, m QuestionMastery) error {
+	now := time.Now().UnixMilli()
+	m.Ctime, m.Utime = now, now
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			DoUpdates: clause.AssignmentColumns([]string{"status", "sid", "utime"}),
+		}).Create(&m).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&PracticeSession{}).Where("id = ?", m.Sid).
+			Update("utime", now).Error
+	})
+}
+
+func (dao *PracticeGORMDAO) FindMasteries(ctx context.Context, uid int64, qids []int64) ([]QuestionMastery, error) {
+	var res []QuestionMastery
+	if len(qids) == 0 {
+		return res, nil
+	}
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND qid IN ?", uid, qids).
+		Order("qid ASC, level ASC").
+		Find(&res).Error
+	return res, err
+}
+
+type PracticeSession struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"index:uid_utime"`
+	// 练习的来源，例如题集、标签
+	Biz   string `gorm:"type:varchar(64)"`
+	BizId int64
+	// 开始练习时候的题目
+	Qids  sqlx.JsonColumn[[]int64]
+	Ctime int64
+	Utime int64 `gorm:"index:uid_utime"`
+}
+
+// QuestionMastery 用户在一道题的一个层级上的掌握程度，跨练习共享
+type QuestionMastery struct {
+	Id    int64 `gorm:"primaryKey,autoIncrement"`
+	Uid   int64 `gorm:"uniqueIndex:uid_qid_level"`
+	Qid   int64 `gorm:"uniqueIndex:uid_qid_level"`
+	Level uint8 `gorm:"uniqueIndex:uid_qid_level"`
+	// 最近一次标记所在的练习
+	Sid    int64
+	Status uint8
+	Ctime  int64
+	Utime  int64
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ekit/sqlx"
+	"github.com/ecodeclub/webook/internal/practice/internal/domain"
+	"github.com/ecodeclub/webook/internal/practice/internal/repository/dao"
+	"gorm.io/gorm"
+)
+
+var ErrSessionNotFound = errors.New("练习不存在")
+
+type PracticeRepository interface {
+	CreateSession(ctx context.Context, s domain.Session) (int64, error)
+	GetSession(ctx context.Context, uid int64, id int64) (domain.Session, error)
+	Record(ctx context.Context, uid int64, sid int64, m domain.Mastery) error
+	FindMasteries(ctx context.Context, uid int64, qids []int64) ([]domain.Mastery, error)
+}
+
+type practiceRepository struct {
+	dao dao.PracticeDAO
+}
+
+func NewPracticeRepository(d dao.PracticeDAO) PracticeRepository {
+	return &practiceRepository{dao: d}
+}
+
+func (repo *practiceRepository) CreateSession(ctx context.Context, s domain.Session) (int64, error) {
+	return repo.dao.CreateSession(ctx, dao.PracticeSession{
+		Uid:   s.Uid,
+		Biz:   s.Biz,
+		BizId: s.BizId,
+		Qids:  sqlx.JsonColumn[[]int64]{Val: s.Qids, Valid: true},
+	})
+}
+
+func (repo *practiceRepository) GetSession(ctx context.Context, uid int64, id int64) (domain.Session, error) {
+	s, err := repo.dao.GetSession(ctx, uid, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.Session{}, ErrSessionNotFound
+	}
+	if err != nil {
+		return domain.Session{}, err
+	}
+	return domain.Session{
+		Id:    s.Id,
+		Uid:   s.Uid,
+		Biz:   s.Biz,
+		BizId: s.BizId,
+		Qids:  s.Qids.Val,
+		Ctime: time.UnixMilli(s.Ctime),
+		Utime: time.UnixMilli(s.Utime),
+	}, nil
+}
+
+func (repo *practiceRepository) Record(ctx context.Context, uid int64, sid int64, m domain.Mastery) error {
+	return repo.dao.Record(ctx, dao.QuestionMastery{
+		Uid:    uid,
+		Qid:    m.Qid,
+		Level:  uint8(m.Level),
+		Sid:    sid,
+		Status: uint8(m.Status),
+	})
+}
+
+func (repo *practiceRepository) FindMasteries(ctx context.Context, uid int64, qids []int64) ([]domain.Mastery, error) {
+	res, err := repo.dao.FindMasteries(ctx, uid, qids)
+	return slice.Map(res, func(idx int, src dao.QuestionMastery) domain.Mastery {
+		return domain.Mastery{
+			Qid:    src.Qid,
+			Level:  domain.Level(src.Level),
+			Status: domain.Status(src.Status),
+			Utime:  time.UnixMilli(src.Utime),
+		}
+	}), err
+}
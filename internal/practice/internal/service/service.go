@@ -0,0 +1,96 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/ecodeclub/webook/internal/practice/internal/domain"
+	"github.com/ecodeclub/webook/internal/practice/internal/repository"
+)
+
+var (
+	ErrSessionNotFound    = repository.ErrSessionNotFound
+	ErrInvalidMastery     = errors.New("掌握程度非法")
+	ErrQuestionNotInScope = errors.New("题目不属于这次练习")
+	ErrEmptySession       = errors.New("练习中没有题目")
+)
+
+//go:generate mockgen -source=./service.go -destination=../../mocks/practice.mock.go -package=practicemocks -typed Service
+type Service interface {
+	// StartSession 开始一次练习，返回练习 ID
+	StartSession(ctx context.Context, s domain.Session) (int64, error)
+	// Session 用户只能看到自己的练习
+	Session(ctx context.Context, uid int64, sid int64) (domain.Session, error)
+	// Record 标记练习中一道题在某个层级上的掌握程度，题目必须属于这次练习
+	Record(ctx context.Context, uid int64, sid int64, m domain.Mastery) error
+	// Masteries 用户在 qids 上的全部标记，不区分练习
+	Masteries(ctx context.Context, uid int64, qids []int64) ([]domain.Mastery, error)
+	// Progress 统计用户在 qids 上的掌握情况
+	Progress(ctx context.Context, uid int64, qids []int64) (domain.Progress, error)
+}
+
+type service struct {
+	repo repository.PracticeRepository
+}
+
+func NewService(repo repository.PracticeRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) StartSession(ctx context.Context, sess domain.Session) (int64, error) {
+	if len(sess.Qids) == 0 {
+		return 0, ErrEmptySession
+	}
+	return s.repo.CreateSession(ctx, sess)
+}
+
+func (s *service) Session(ctx context.Context, uid int64, sid int64) (domain.Session, error) {
+	return s.repo.GetSession(ctx, uid, sid)
+}
+
+func (s *service) Record(ctx context.Context, uid int64, sid int64, m domain.Mastery) error {
+	if !m.Level.Valid() || !m.Status.Valid() {
+		return ErrInvalidMastery
+	}
+	sess, err := s.repo.GetSession(ctx, uid, sid)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, qid := range sess.Qids {
+		if qid == m.Qid {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrQuestionNotInScope
+	}
+	return s.repo.Record(ctx, uid, sid, m)
+}
+
+func (s *service) Masteries(ctx context.Context, uid int64, qids []int64) ([]domain.Mastery, error) {
+	return s.repo.FindMasteries(ctx, uid, qids)
+}
+
+func (s *service) Progress(ctx context.Context, uid int64, qids []int64) (domain.Progress, error) {
+	masteries, err := s.repo.FindMasteries(ctx, uid, qids)
+	if err != nil {
+		return domain.Progress{}, err
+	}
+	return domain.NewProgress(qids, masteries), nil
+}
@@ -0,0 +1,82 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/practice/internal/domain"
+	"github.com/ecodeclub/webook/internal/practice/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// Handler 练习的开始由题目、技能等模块负责，因为只有它们知道练习包含哪些题目
+type Handler struct {
+	svc service.Service
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) MemberRoutes(server *gin.Engine) {
+	g := server.Group("/practice")
+	g.POST("/record", ginx.BS[RecordReq](h.Record))
+	g.POST("/detail", ginx.BS[Sid](h.Detail))
+}
+
+// Record 标记掌握程度
+func (h *Handler) Record(ctx *ginx.Context, req RecordReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Record(ctx.Request.Context(), sess.Claims().Uid, req.Sid, domain.Mastery{
+		Qid:    req.Qid,
+		Level:  domain.Level(req.Level),
+		Status: domain.Status(req.Status),
+	})
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+// Detail 练习详情，包括练习中每道题的掌握程度
+func (h *Handler) Detail(ctx *ginx.Context, req Sid, sess session.Session) (ginx.Result, error) {
+	uid := sess.Claims().Uid
+	s, err := h.svc.Session(ctx.Request.Context(), uid, req.Sid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	masteries, err := h.svc.Masteries(ctx.Request.Context(), uid, s.Qids)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: newSession(s, masteries),
+	}, nil
+}
+
+func (h *Handler) errorResult(err error) ginx.Result {
+	switch {
+	case errors.Is(err, service.ErrSessionNotFound):
+		return sessionNotFoundResult
+	case errors.Is(err, service.ErrInvalidMastery):
+		return invalidMasteryResult
+	case errors.Is(err, service.ErrQuestionNotInScope):
+		return questionNotInScopeResult
+	default:
+		return systemErrorResult
+	}
+}
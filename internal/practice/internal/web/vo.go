@@ -0,0 +1,92 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/practice/internal/domain"
+)
+
+type Sid struct {
+	Sid int64 `json:"sid"`
+}
+
+type RecordReq struct {
+	Sid int64 `json:"sid"`
+	Qid int64 `json:"qid"`
+	// 1 分析，2 基本回答，3 中级回答，4 高级回答
+	Level uint8 `json:"level"`
+	// 1 不会，2 模糊，3 掌握
+	Status uint8 `json:"status"`
+}
+
+type Session struct {
+	Id        int64     `json:"id"`
+	Biz       string    `json:"biz"`
+	BizId     int64     `json:"bizId"`
+	Qids      []int64   `json:"qids"`
+	Masteries []Mastery `json:"masteries"`
+	Progress  Progress  `json:"progress"`
+	Utime     string    `json:"utime"`
+}
+
+type Mastery struct {
+	Qid    int64 `json:"qid"`
+	Level  uint8 `json:"level"`
+	Status uint8 `json:"status"`
+}
+
+type Progress struct {
+	Total  int             `json:"total"`
+	Levels []LevelProgress `json:"levels"`
+}
+
+type LevelProgress struct {
+	Level    uint8 `json:"level"`
+	Mastered int   `json:"mastered"`
+	Fuzzy    int   `json:"fuzzy"`
+	Unknown  int   `json:"unknown"`
+}
+
+func newSession(s domain.Session, masteries []domain.Mastery) Session {
+	return Session{
+		Id:    s.Id,
+		Biz:   s.Biz,
+		BizId: s.BizId,
+		Qids:  s.Qids,
+		Masteries: slice.Map(masteries, func(idx int, src domain.Mastery) Mastery {
+			return Mastery{Qid: src.Qid, Level: uint8(src.Level), Status: uint8(src.Status)}
+		}),
+		Progress: NewProgress(domain.NewProgress(s.Qids, masteries)),
+		Utime:    s.Utime.Format(time.DateTime),
+	}
+}
+
+// NewProgress 其它模块展示掌握情况的时候也使用这个结构体
+func NewProgress(p domain.Progress) Progress {
+	return Progress{
+		Total: p.Total,
+		Levels: slice.Map(p.Levels, func(idx int, src domain.LevelProgress) LevelProgress {
+			return LevelProgress{
+				Level:    uint8(src.Level),
+				Mastered: src.Mastered,
+				Fuzzy:    src.Fuzzy,
+				Unknown:  src.Unknown,
+			}
+		}),
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package practice
+
+import (
+	"sync"
+
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/webook/internal/practice/internal/domain"
+	"github.com/ecodeclub/webook/internal/practice/internal/errs"
+	"github.com/ecodeclub/webook/internal/practice/internal/repository"
+	"github.com/ecodeclub/webook/internal/practice/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/practice/internal/service"
+	"github.com/ecodeclub/webook/internal/practice/internal/web"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+)
+
+func InitModule(db *egorm.Component) *Module {
+	wire.Build(
+		initDAO,
+		repository.NewPracticeRepository,
+		service.NewService,
+		web.NewHandler,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module)
+}
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.PracticeDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewPracticeGORMDAO(db)
+}
+
+var ErrEmptySession = service.ErrEmptySession
+
+// EmptySessionResult 其它模块开始练习的时候，练习中没有题目返回这个结果
+var EmptySessionResult = ginx.Result{
+	Code: errs.EmptySession.Code,
+	Msg:  errs.EmptySession.Msg,
+}
+
+type Handler = web.Handler
+type Service = service.Service
+type Session = domain.Session
+type Progress = domain.Progress
+type ProgressVO = web.Progress
+
+// NewProgressVO 在其它模块里面展示掌握情况
+func NewProgressVO(p Progress) ProgressVO {
+	return web.NewProgress(p)
+}
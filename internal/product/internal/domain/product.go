@@ -14,9 +14,32 @@
 
 package domain
 
+import (
+	"errors"
+	"fmt"
+)
+
+const (
+	StatusOffShelf = iota + 1 // 下架
+	StatusOnShelf             // 上架
+)
+
 const (
-	StatusOffShelf = iota // 下架
-	StatusOnShelf         // 上架
+	SaleTypeUnlimited = iota + 1 // 无限期
+	SaleTypePromotion            // 限时促销
+	SaleTypePresale              // 预售
+)
+
+var (
+	ErrInvalidProduct = errors.New("商品信息非法")
+	// ErrInsufficientStock 库存不足
+	ErrInsufficientStock = errors.New("商品库存不足")
+	// ErrExceedStockLimit 超出单个用户的限购数量
+	ErrExceedStockLimit = errors.New("超出商品限购数量")
+	// ErrNotOnSale 不在销售时间窗口内
+	ErrNotOnSale = errors.New("商品不在销售时间内")
+	// ErrNotFound 商品不存在或者已经下架
+	ErrNotFound = errors.New("商品不存在")
 )
 
 type Product struct {
@@ -30,6 +53,19 @@ type SPU struct {
 	Name   string
 	Desc   string
 	Status int64
+	SKUs   []SKU
+}
+
+func (s SPU) Validate() error {
+	if s.SN == "" || s.Name == "" {
+		return fmt.Errorf("%w: SPU 的 SN 和名称不能为空", ErrInvalidProduct)
+	}
+	for _, sku := range s.SKUs {
+		if err := sku.Validate(); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type SKU struct {
@@ -43,7 +79,83 @@ type SKU struct {
 	StockLimit int64
 
 	SaleType int64
-	// SaleStart int64
-	// SaleEnd   int64
-	Status int64
+	// SaleStart 销售开始时间，UTC Unix毫秒数，0 表示立刻开始
+	SaleStart int64
+	// SaleEnd 销售结束时间，UTC Unix毫秒数，0 表示不会结束
+	SaleEnd int64
+	// PromotionPrice 限时促销的价格，只在销售时间窗口内生效
+	PromotionPrice int64
+	// ReleaseAt 预售商品的发售时间，UTC Unix毫秒数，订单在发售之后才履约
+	ReleaseAt int64
+	Status    int64
+}
+
+// OnSale 是否在销售时间窗口内，无限期销售的商品总是在销售中
+func (s SKU) OnSale(now int64) bool {
+	if s.SaleType == SaleTypeUnlimited {
+		return true
+	}
+	return (s.SaleStart == 0 || s.SaleStart <= now) && (s.SaleEnd == 0 || now < s.SaleEnd)
+}
+
+// RealPrice 实际售价，限时促销的商品在销售时间窗口内使用促销价格
+func (s SKU) RealPrice(now int64) int64 {
+	if s.SaleType == SaleTypePromotion && s.OnSale(now) {
+		return s.PromotionPrice
+	}
+	return s.Price
+}
+
+func (s SKU) Validate() error {
+	if s.SN == "" || s.Name == "" {
+		return fmt.Errorf("%w: SKU 的 SN 和名称不能为空", ErrInvalidProduct)
+	}
+	if s.Price <= 0 {
+		return fmt.Errorf("%w: SKU %s 价格必须大于 0", ErrInvalidProduct, s.SN)
+	}
+	if s.Stock < 0 {
+		return fmt.Errorf("%w: SKU %s 库存不能为负数", ErrInvalidProduct, s.SN)
+	}
+	if s.StockLimit < 1 {
+		return fmt.Errorf("%w: SKU %s 库存限制必须大于 0", ErrInvalidProduct, s.SN)
+	}
+	switch s.SaleType {
+	case SaleTypeUnlimited:
+		if s.SaleStart != 0 || s.SaleEnd != 0 || s.PromotionPrice != 0 || s.ReleaseAt != 0 {
+			return fmt.Errorf("%w: SKU %s 无限期销售不能设置销售时间和促销价格", ErrInvalidProduct, s.SN)
+		}
+	case SaleTypePromotion:
+		if s.SaleStart <= 0 || s.SaleEnd <= s.SaleStart {
+			return fmt.Errorf("%w: SKU %s 限时促销的销售时间非法", ErrInvalidProduct, s.SN)
+		}
+		if s.PromotionPrice <= 0 || s.PromotionPrice >= s.Price {
+			return fmt.Errorf("%w: SKU %s 促销价格必须大于 0 并且小于原价", ErrInvalidProduct, s.SN)
+		}
+		if s.ReleaseAt != 0 {
+			return fmt.Errorf("%w: SKU %s 限时促销不能设置发售时间", ErrInvalidProduct, s.SN)
+		}
+	case SaleTypePresale:
+		if s.ReleaseAt <= 0 {
+			return fmt.Errorf("%w: SKU %s 预售必须设置发售时间", ErrInvalidProduct, s.SN)
+		}
+		if s.SaleEnd != 0 && (s.SaleEnd <= s.SaleStart || s.SaleEnd > s.ReleaseAt) {
+			return fmt.Errorf("%w: SKU %s 预售的销售时间非法", ErrInvalidProduct, s.SN)
+		}
+		if s.PromotionPrice != 0 {
+			return fmt.Errorf("%w: SKU %s 预售不能设置促销价格", ErrInvalidProduct, s.SN)
+		}
+	default:
+		return fmt.Errorf("%w: SKU %s 销售类型 %d 非法", ErrInvalidProduct, s.SN, s.SaleType)
+	}
+	return nil
+}
+
+func IsValidStatus(status int64) bool {
+	return status == StatusOffShelf || status == StatusOnShelf
+}
+
+// StockItem 预留库存的时候使用
+type StockItem struct {
+	SKUID    int64
+	Quantity int64
 }
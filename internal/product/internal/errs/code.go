@@ -15,7 +15,10 @@
 package errs
 
 var (
-	SystemError = ErrorCode{Code: 504001, Msg: "系统错误"}
+	SystemError    = ErrorCode{Code: 504001, Msg: "系统错误"}
+	InvalidProduct = ErrorCode{Code: 504002, Msg: "商品信息非法"}
+	DuplicateSN    = ErrorCode{Code: 504003, Msg: "商品 SN 已存在"}
+	NotOnSale      = ErrorCode{Code: 504004, Msg: "商品不在销售时间内"}
 )
 
 type ErrorCode struct {
@@ -18,11 +18,17 @@ package integration
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/ecodeclub/ekit/iox"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/product"
+	"github.com/ecodeclub/webook/internal/product/internal/domain"
 	"github.com/ecodeclub/webook/internal/product/internal/errs"
 	"github.com/ecodeclub/webook/internal/product/internal/integration/startup"
 	"github.com/ecodeclub/webook/internal/product/internal/repository/dao"
@@ -33,6 +39,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gotomicro/ego/core/econf"
 	"github.com/gotomicro/ego/server/egin"
+	"github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -44,7 +51,9 @@ type HandlerTestSuite struct {
 	suite.Suite
 	server *egin.Component
 	db     *egorm.Component
+	rdb    redis.Cmdable
 	dao    dao.ProductDAO
+	svc    product.Service
 }
 
 func (s *HandlerTestSuite) SetupSuite() {
@@ -55,9 +64,11 @@ func (s *HandlerTestSuite) SetupSuite() {
 	server := egin.Load("server").Build()
 	server.Use(func(ctx *gin.Context) {
 		ctx.Set("_session", session.NewMemorySession(session.Claims{
-			Uid: uid,
+			Uid:  uid,
+			Data: map[string]string{"creator": "true"},
 		}))
 	})
+	handler.PublicRoutes(server.Engine)
 	handler.PrivateRoutes(server.Engine)
 
 	s.server = server
@@ -65,6 +76,17 @@ func (s *HandlerTestSuite) SetupSuite() {
 	err = dao.InitTables(s.db)
 	require.NoError(s.T(), err)
 	s.dao = dao.NewProductGORMDAO(s.db)
+	s.svc = startup.InitService()
+	s.rdb = testioc.InitRedis()
+	s.clearCache()
+}
+
+func (s *HandlerTestSuite) clearCache() {
+	keys, err := s.rdb.Keys(context.Background(), "webook:product:*").Result()
+	require.NoError(s.T(), err)
+	if len(keys) > 0 {
+		require.NoError(s.T(), s.rdb.Del(context.Background(), keys...).Err())
+	}
 }
 
 func (s *HandlerTestSuite) TearDownSuite() {
@@ -72,6 +94,9 @@ func (s *HandlerTestSuite) TearDownSuite() {
 	require.NoError(s.T(), err)
 	err = s.db.Exec("DROP TABLE `product_skus`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("DROP TABLE `stock_reservations`").Error
+	require.NoError(s.T(), err)
+	s.clearCache()
 }
 
 func (s *HandlerTestSuite) TearDownTest() {
@@ -79,6 +104,8 @@ func (s *HandlerTestSuite) TearDownTest() {
 	require.NoError(s.T(), err)
 	err = s.db.Exec("TRUNCATE TABLE `product_skus`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `stock_reservations`").Error
+	require.NoError(s.T(), err)
 }
 
 func (s *HandlerTestSuite) TestProductDetail() {
@@ -141,6 +168,7 @@ func (s *HandlerTestSuite) TestProductDetail() {
 						Stock:      1000,
 						StockLimit: 100000000,
 						SaleType:   1,
+						RealPrice:  799,
 					},
 				},
 			},
@@ -316,6 +344,594 @@ func (s *HandlerTestSuite) TestProductDetailFailed() {
 	}
 }
 
+func (s *HandlerTestSuite) TestSave() {
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		after  func(t *testing.T)
+
+		req      web.SaveSPUReq
+		wantCode int
+		wantResp test.Result[int64]
+	}{
+		{
+			name:   "新建成功",
+			before: func(t *testing.T) {},
+			after: func(t *testing.T) {
+				spu, err := s.dao.GetSPUBySN(context.Background(), "SPU101")
+				require.NoError(t, err)
+				assert.Equal(t, "会员服务", spu.Name)
+				assert.Equal(t, int64(dao.StatusOffShelf), spu.Status)
+				skus, err := s.dao.FindSKUsBySPUIDs(context.Background(), []int64{spu.Id})
+				require.NoError(t, err)
+				require.Len(t, skus, 1)
+				assert.Equal(t, int64(799), skus[0].Price)
+				assert.Equal(t, int64(dao.StatusOffShelf), skus[0].Status)
+			},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU101",
+					Name: "会员服务",
+					Desc: "提供不同期限的会员服务",
+					SKUs: []web.SKU{
+						{
+							SN:         "SKU101",
+							Name:       "星期会员",
+							Desc:       "提供一周的会员服务",
+							Price:      799,
+							Stock:      1000,
+							StockLimit: 1,
+							SaleType:   domain.SaleTypeUnlimited,
+						},
+					},
+				},
+			},
+			wantCode: 200,
+			wantResp: test.Result[int64]{Data: 1},
+		},
+		{
+			name: "更新成功",
+			before: func(t *testing.T) {
+				_, err := s.dao.SaveSPU(context.Background(), dao.ProductSPU{
+					SN:          "SPU102",
+					Name:        "面试项目",
+					Description: "旧的描述",
+				}, []dao.ProductSKU{
+					{
+						SN:         "SKU102",
+						Name:       "用户项目",
+						Price:      9999,
+						Stock:      10,
+						StockLimit: 1,
+						SaleType:   domain.SaleTypeUnlimited,
+					},
+				})
+				require.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				spu, err := s.dao.GetSPUBySN(context.Background(), "SPU102")
+				require.NoError(t, err)
+				assert.Equal(t, "新的描述", spu.Description)
+				skus, err := s.dao.FindSKUsBySPUIDs(context.Background(), []int64{spu.Id})
+				require.NoError(t, err)
+				require.Len(t, skus, 2)
+				assert.Equal(t, int64(8888), skus[0].Price)
+				assert.Equal(t, "SKU103", skus[1].SN)
+			},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					ID:   2,
+					SN:   "SPU102",
+					Name: "面试项目",
+					Desc: "新的描述",
+					SKUs: []web.SKU{
+						{
+							ID:         2,
+							SN:         "SKU102",
+							Name:       "用户项目",
+							Price:      8888,
+							Stock:      10,
+							StockLimit: 1,
+							SaleType:   domain.SaleTypeUnlimited,
+						},
+						{
+							SN:         "SKU103",
+							Name:       "权限项目",
+							Price:      19999,
+							Stock:      10,
+							StockLimit: 1,
+							SaleType:   domain.SaleTypePresale,
+						},
+					},
+				},
+			},
+			wantCode: 200,
+			wantResp: test.Result[int64]{Data: 2},
+		},
+		{
+			name:   "价格非法",
+			before: func(t *testing.T) {},
+			after:  func(t *testing.T) {},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU104",
+					Name: "会员服务",
+					SKUs: []web.SKU{
+						{SN: "SKU104", Name: "月会员", Price: 0, Stock: 1, StockLimit: 1, SaleType: domain.SaleTypeUnlimited},
+					},
+				},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: errs.InvalidProduct.Code, Msg: errs.InvalidProduct.Msg},
+		},
+		{
+			name:   "库存非法",
+			before: func(t *testing.T) {},
+			after:  func(t *testing.T) {},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU105",
+					Name: "会员服务",
+					SKUs: []web.SKU{
+						{SN: "SKU105", Name: "月会员", Price: 990, Stock: -1, StockLimit: 1, SaleType: domain.SaleTypeUnlimited},
+					},
+				},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: errs.InvalidProduct.Code, Msg: errs.InvalidProduct.Msg},
+		},
+		{
+			name:   "销售类型非法",
+			before: func(t *testing.T) {},
+			after:  func(t *testing.T) {},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU106",
+					Name: "会员服务",
+					SKUs: []web.SKU{
+						{SN: "SKU106", Name: "月会员", Price: 990, Stock: 1, StockLimit: 1, SaleType: 4},
+					},
+				},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: errs.InvalidProduct.Code, Msg: errs.InvalidProduct.Msg},
+		},
+		{
+			name:   "限时促销时间非法",
+			before: func(t *testing.T) {},
+			after:  func(t *testing.T) {},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU108",
+					Name: "会员服务",
+					SKUs: []web.SKU{
+						{SN: "SKU108", Name: "月会员", Price: 990, Stock: 1, StockLimit: 1, SaleType: domain.SaleTypePromotion,
+							SaleStart: 2000, SaleEnd: 1000, PromotionPrice: 590},
+					},
+				},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: errs.InvalidProduct.Code, Msg: errs.InvalidProduct.Msg},
+		},
+		{
+			name:   "促销价格非法",
+			before: func(t *testing.T) {},
+			after:  func(t *testing.T) {},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU109",
+					Name: "会员服务",
+					SKUs: []web.SKU{
+						{SN: "SKU109", Name: "月会员", Price: 990, Stock: 1, StockLimit: 1, SaleType: domain.SaleTypePromotion,
+							SaleStart: 1000, SaleEnd: 2000, PromotionPrice: 990},
+					},
+				},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: errs.InvalidProduct.Code, Msg: errs.InvalidProduct.Msg},
+		},
+		{
+			name:   "预售没有发售时间",
+			before: func(t *testing.T) {},
+			after:  func(t *testing.T) {},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU110",
+					Name: "会员服务",
+					SKUs: []web.SKU{
+						{SN: "SKU110", Name: "月会员", Price: 990, Stock: 1, StockLimit: 1, SaleType: domain.SaleTypePresale,
+							SaleStart: 1000, SaleEnd: 2000},
+					},
+				},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: errs.InvalidProduct.Code, Msg: errs.InvalidProduct.Msg},
+		},
+		{
+			name: "SN重复",
+			before: func(t *testing.T) {
+				_, err := s.dao.SaveSPU(context.Background(), dao.ProductSPU{
+					SN:   "SPU107",
+					Name: "会员服务",
+				}, nil)
+				require.NoError(t, err)
+			},
+			after: func(t *testing.T) {},
+			req: web.SaveSPUReq{
+				SPU: web.SPU{
+					SN:   "SPU107",
+					Name: "会员服务",
+				},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: errs.DuplicateSN.Code, Msg: errs.DuplicateSN.Msg},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/product/save", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[int64]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+			tc.after(t)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestShelfAndCatalog() {
+	t := s.T()
+	ctx := context.Background()
+	_, err := s.dao.SaveSPU(ctx, dao.ProductSPU{
+		SN:   "SPU201",
+		Name: "会员服务",
+	}, []dao.ProductSKU{
+		{SN: "SKU201", Name: "月会员", Price: 990, Stock: 10, StockLimit: 1, SaleType: domain.SaleTypeUnlimited},
+		{SN: "SKU202", Name: "年会员", Price: 11880, Stock: 10, StockLimit: 1, SaleType: domain.SaleTypeUnlimited},
+	})
+	require.NoError(t, err)
+	_, err = s.dao.SaveSPU(ctx, dao.ProductSPU{
+		SN:   "SPU202",
+		Name: "面试项目",
+	}, []dao.ProductSKU{
+		{SN: "SKU203", Name: "用户项目", Price: 9999, Stock: 10, StockLimit: 1, SaleType: domain.SaleTypeUnlimited},
+	})
+	require.NoError(t, err)
+
+	// SPU201 和 SKU201 上架，其余保持下架
+	for _, r := range []struct {
+		path string
+		req  web.StatusReq
+	}{
+		{path: "/product/spu/status", req: web.StatusReq{SN: "SPU201", Status: domain.StatusOnShelf}},
+		{path: "/product/sku/status", req: web.StatusReq{SN: "SKU201", Status: domain.StatusOnShelf}},
+		{path: "/product/sku/status", req: web.StatusReq{SN: "SKU203", Status: domain.StatusOnShelf}},
+	} {
+		req, err := http.NewRequest(http.MethodPost, r.path, iox.NewJSONReader(r.req))
+		req.Header.Set("content-type", "application/json")
+		require.NoError(t, err)
+		recorder := test.NewJSONResponseRecorder[any]()
+		s.server.ServeHTTP(recorder, req)
+		require.Equal(t, 200, recorder.Code)
+	}
+
+	// 非法状态
+	req, err := http.NewRequest(http.MethodPost, "/product/spu/status",
+		iox.NewJSONReader(web.StatusReq{SN: "SPU201", Status: 3}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 500, recorder.Code)
+	assert.Equal(t, errs.InvalidProduct.Code, recorder.MustScan().Code)
+
+	// 公开目录只有上架的 SPU 和 SKU
+	req, err = http.NewRequest(http.MethodPost, "/product/catalog",
+		iox.NewJSONReader(web.Page{Offset: 0, Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	catalogRecorder := test.NewJSONResponseRecorder[web.SPUList]()
+	s.server.ServeHTTP(catalogRecorder, req)
+	require.Equal(t, 200, catalogRecorder.Code)
+	catalog := catalogRecorder.MustScan().Data
+	assert.Equal(t, int64(1), catalog.Total)
+	require.Len(t, catalog.SPUs, 1)
+	assert.Equal(t, "SPU201", catalog.SPUs[0].SN)
+	require.Len(t, catalog.SPUs[0].SKUs, 1)
+	assert.Equal(t, "SKU201", catalog.SPUs[0].SKUs[0].SN)
+
+	// 非法的分页参数按照默认值处理
+	req, err = http.NewRequest(http.MethodPost, "/product/catalog",
+		iox.NewJSONReader(web.Page{Offset: -1, Limit: -1}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	catalogRecorder = test.NewJSONResponseRecorder[web.SPUList]()
+	s.server.ServeHTTP(catalogRecorder, req)
+	require.Equal(t, 200, catalogRecorder.Code)
+	assert.Len(t, catalogRecorder.MustScan().Data.SPUs, 1)
+
+	// 创作者可以看到所有的 SPU 和 SKU
+	req, err = http.NewRequest(http.MethodPost, "/product/list",
+		iox.NewJSONReader(web.Page{Offset: 0, Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	listRecorder := test.NewJSONResponseRecorder[web.SPUList]()
+	s.server.ServeHTTP(listRecorder, req)
+	require.Equal(t, 200, listRecorder.Code)
+	list := listRecorder.MustScan().Data
+	assert.Equal(t, int64(2), list.Total)
+	require.Len(t, list.SPUs, 2)
+	assert.Equal(t, "SPU202", list.SPUs[0].SN)
+	assert.Len(t, list.SPUs[0].SKUs, 1)
+	assert.Equal(t, "SPU201", list.SPUs[1].SN)
+	assert.Len(t, list.SPUs[1].SKUs, 2)
+
+	// 创作者查看详情
+	req, err = http.NewRequest(http.MethodPost, "/product/spu/detail",
+		iox.NewJSONReader(web.ProductSNReq{SN: "SPU202"}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	detailRecorder := test.NewJSONResponseRecorder[web.SPU]()
+	s.server.ServeHTTP(detailRecorder, req)
+	require.Equal(t, 200, detailRecorder.Code)
+	detail := detailRecorder.MustScan().Data
+	assert.Equal(t, int64(domain.StatusOffShelf), detail.Status)
+	require.Len(t, detail.SKUs, 1)
+	assert.Equal(t, int64(domain.StatusOnShelf), detail.SKUs[0].Status)
+}
+
+func (s *HandlerTestSuite) TestStock() {
+	t := s.T()
+	ctx := context.Background()
+	_, err := s.dao.SaveSPU(ctx, dao.ProductSPU{
+		SN:   "SPU301",
+		Name: "会员服务",
+	}, []dao.ProductSKU{
+		{SN: "SKU301", Name: "月会员", Price: 990, Stock: 10, StockLimit: 3, SaleType: domain.SaleTypeUnlimited},
+		{SN: "SKU302", Name: "年会员", Price: 11880, Stock: 1, StockLimit: 3, SaleType: domain.SaleTypeUnlimited},
+	})
+	require.NoError(t, err)
+	stockOf := func(t *testing.T, id int64) int64 {
+		var sku dao.ProductSKU
+		require.NoError(t, s.db.Where("id = ?", id).First(&sku).Error)
+		return sku.Stock
+	}
+
+	// 预留成功
+	err = s.svc.ReserveStock(ctx, "order-1", uid, []domain.StockItem{{SKUID: 1, Quantity: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), stockOf(t, 1))
+
+	// 重复预留不会重复扣减
+	err = s.svc.ReserveStock(ctx, "order-1", uid, []domain.StockItem{{SKUID: 1, Quantity: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), stockOf(t, 1))
+
+	// 其中一个 SKU 库存不足，整体回滚
+	err = s.svc.ReserveStock(ctx, "order-2", uid, []domain.StockItem{{SKUID: 1, Quantity: 1}, {SKUID: 2, Quantity: 2}})
+	assert.ErrorIs(t, err, domain.ErrInsufficientStock)
+	assert.Equal(t, int64(8), stockOf(t, 1))
+	assert.Equal(t, int64(1), stockOf(t, 2))
+
+	// 已经预留了 2 件，再买 2 件就超出限购数量
+	err = s.svc.ReserveStock(ctx, "order-3", uid, []domain.StockItem{{SKUID: 1, Quantity: 2}})
+	assert.ErrorIs(t, err, domain.ErrExceedStockLimit)
+	assert.Equal(t, int64(8), stockOf(t, 1))
+	// 其他用户不受影响
+	err = s.svc.ReserveStock(ctx, "order-4", uid+1, []domain.StockItem{{SKUID: 1, Quantity: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), stockOf(t, 1))
+
+	// 释放之后库存归还，也不再计入限购数量
+	require.NoError(t, s.svc.ReleaseStock(ctx, "order-1"))
+	require.NoError(t, s.svc.ReleaseStock(ctx, "order-1"))
+	assert.Equal(t, int64(8), stockOf(t, 1))
+	err = s.svc.ReserveStock(ctx, "order-5", uid, []domain.StockItem{{SKUID: 1, Quantity: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stockOf(t, 1))
+
+	// 确认之后不能再释放
+	require.NoError(t, s.svc.CommitStock(ctx, "order-5"))
+	require.NoError(t, s.svc.ReleaseStock(ctx, "order-5"))
+	assert.Equal(t, int64(5), stockOf(t, 1))
+
+	// 同一个 SKU 出现多次的时候合并预留
+	err = s.svc.ReserveStock(ctx, "order-6", uid+2, []domain.StockItem{{SKUID: 1, Quantity: 1}, {SKUID: 1, Quantity: 2}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stockOf(t, 1))
+	var reservations []dao.StockReservation
+	require.NoError(t, s.db.Where("order_sn = ?", "order-6").Find(&reservations).Error)
+	require.Len(t, reservations, 1)
+	assert.Equal(t, int64(3), reservations[0].Quantity)
+}
+
+func (s *HandlerTestSuite) TestStock_Concurrent() {
+	t := s.T()
+	ctx := context.Background()
+	_, err := s.dao.SaveSPU(ctx, dao.ProductSPU{
+		SN:   "SPU401",
+		Name: "会员服务",
+	}, []dao.ProductSKU{
+		{SN: "SKU401", Name: "月会员", Price: 990, Stock: 5, StockLimit: 2, SaleType: domain.SaleTypeUnlimited},
+	})
+	require.NoError(t, err)
+
+	// 20 个请求：10 个用户，每个用户下 2 单，每单 1 件
+	var (
+		wg      sync.WaitGroup
+		success atomic.Int64
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			er := s.svc.ReserveStock(ctx, fmt.Sprintf("order-%d", i), int64(1000+i%10),
+				[]domain.StockItem{{SKUID: 1, Quantity: 1}})
+			if er == nil {
+				success.Add(1)
+			}
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, int64(5), success.Load())
+	var sku dao.ProductSKU
+	require.NoError(t, s.db.Where("id = ?", 1).First(&sku).Error)
+	assert.Equal(t, int64(0), sku.Stock)
+}
+
+func (s *HandlerTestSuite) TestSaleWindow() {
+	t := s.T()
+	ctx := context.Background()
+	now := time.Now()
+	_, err := s.dao.SaveSPU(ctx, dao.ProductSPU{
+		SN:   "SPU501",
+		Name: "会员服务",
+	}, []dao.ProductSKU{
+		// 正在促销
+		{SN: "SKU501", Name: "月会员", Price: 990, Stock: 10, StockLimit: 10, SaleType: domain.SaleTypePromotion,
+			SaleStart: now.Add(-time.Hour).UnixMilli(), SaleEnd: now.Add(time.Hour).UnixMilli(), PromotionPrice: 590},
+		// 促销还没开始
+		{SN: "SKU502", Name: "季度会员", Price: 2970, Stock: 10, StockLimit: 10, SaleType: domain.SaleTypePromotion,
+			SaleStart: now.Add(time.Hour).UnixMilli(), SaleEnd: now.Add(2 * time.Hour).UnixMilli(), PromotionPrice: 1990},
+		// 预售已经结束
+		{SN: "SKU503", Name: "年会员", Price: 11880, Stock: 10, StockLimit: 10, SaleType: domain.SaleTypePresale,
+			SaleStart: now.Add(-2 * time.Hour).UnixMilli(), SaleEnd: now.Add(-time.Hour).UnixMilli(), ReleaseAt: now.Add(time.Hour).UnixMilli()},
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.svc.UpdateSPUStatus(ctx, "SPU501", domain.StatusOnShelf))
+	for _, sn := range []string{"SKU501", "SKU502", "SKU503"} {
+		require.NoError(t, s.svc.UpdateSKUStatus(ctx, sn, domain.StatusOnShelf))
+	}
+
+	// 在销售时间内使用促销价格
+	p, err := s.svc.FindBySN(ctx, "SKU501")
+	require.NoError(t, err)
+	assert.Equal(t, int64(590), p.SKU.RealPrice(now.UnixMilli()))
+	assert.Equal(t, int64(990), p.SKU.RealPrice(now.Add(time.Hour).UnixMilli()))
+
+	// 不在销售时间内
+	_, err = s.svc.FindBySN(ctx, "SKU502")
+	assert.ErrorIs(t, err, domain.ErrNotOnSale)
+	_, err = s.svc.FindBySN(ctx, "SKU503")
+	assert.ErrorIs(t, err, domain.ErrNotOnSale)
+	err = s.svc.ReserveStock(ctx, "order-1", uid, []domain.StockItem{{SKUID: 1, Quantity: 1}, {SKUID: 2, Quantity: 1}})
+	assert.ErrorIs(t, err, domain.ErrNotOnSale)
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/product/detail", iox.NewJSONReader(web.ProductSNReq{SN: "SKU502"}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[web.Product]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 500, recorder.Code)
+	assert.Equal(t, errs.NotOnSale.Code, recorder.MustScan().Code)
+
+	// 跨过销售时间窗口的边界后自动上下架
+	require.NoError(t, s.svc.UpdateSKUStatus(ctx, "SKU502", domain.StatusOffShelf))
+	statusOf := func(sn string) int64 {
+		var sku dao.ProductSKU
+		require.NoError(t, s.db.Where("sn = ?", sn).First(&sku).Error)
+		return sku.Status
+	}
+	err = s.svc.SyncSaleWindow(ctx, now.Add(-3*time.Hour).UnixMilli(), now.UnixMilli())
+	require.NoError(t, err)
+	assert.Equal(t, int64(domain.StatusOnShelf), statusOf("SKU501"))
+	assert.Equal(t, int64(domain.StatusOffShelf), statusOf("SKU502"))
+	assert.Equal(t, int64(domain.StatusOffShelf), statusOf("SKU503"))
+
+	err = s.svc.SyncSaleWindow(ctx, now.UnixMilli(), now.Add(90*time.Minute).UnixMilli())
+	require.NoError(t, err)
+	assert.Equal(t, int64(domain.StatusOffShelf), statusOf("SKU501"))
+	assert.Equal(t, int64(domain.StatusOnShelf), statusOf("SKU502"))
+}
+
+func (s *HandlerTestSuite) TestFindBySNs() {
+	t := s.T()
+	ctx := context.Background()
+	_, err := s.dao.SaveSPU(ctx, dao.ProductSPU{
+		SN:   "SPU601",
+		Name: "会员服务",
+	}, []dao.ProductSKU{
+		{SN: "SKU601", Name: "月会员", Price: 990, Stock: 10, StockLimit: 10, SaleType: domain.SaleTypeUnlimited},
+		{SN: "SKU602", Name: "年会员", Price: 11880, Stock: 10, StockLimit: 10, SaleType: domain.SaleTypeUnlimited},
+		{SN: "SKU603", Name: "季度会员", Price: 2970, Stock: 10, StockLimit: 10, SaleType: domain.SaleTypeUnlimited},
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.svc.UpdateSPUStatus(ctx, "SPU601", domain.StatusOnShelf))
+	for _, sn := range []string{"SKU601", "SKU602"} {
+		require.NoError(t, s.svc.UpdateSKUStatus(ctx, sn, domain.StatusOnShelf))
+	}
+
+	// 按照请求的顺序返回
+	products, err := s.svc.FindBySNs(ctx, []string{"SKU602", "SKU601"})
+	require.NoError(t, err)
+	require.Len(t, products, 2)
+	assert.Equal(t, "SKU602", products[0].SKU.SN)
+	assert.Equal(t, "SPU601", products[0].SPU.SN)
+	assert.Equal(t, "SKU601", products[1].SKU.SN)
+
+	// 下架的 SKU 找不到
+	_, err = s.svc.FindBySNs(ctx, []string{"SKU601", "SKU603"})
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	// 已经写入 Redis
+	cnt, err := s.rdb.Exists(ctx, "webook:product:sku:SKU601", "webook:product:sku:SKU602").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cnt)
+
+	// 直接修改数据库不会影响缓存
+	require.NoError(t, s.db.Model(&dao.ProductSKU{}).Where("sn = ?", "SKU601").Update("name", "新的月会员").Error)
+	p, err := s.svc.FindBySN(ctx, "SKU601")
+	require.NoError(t, err)
+	assert.Equal(t, "月会员", p.SKU.Name)
+
+	// 修改状态之后缓存失效
+	require.NoError(t, s.svc.UpdateSPUStatus(ctx, "SPU601", domain.StatusOffShelf))
+	cnt, err = s.rdb.Exists(ctx, "webook:product:sku:SKU601", "webook:product:sku:SKU602").Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), cnt)
+	_, err = s.svc.FindBySN(ctx, "SKU601")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	require.NoError(t, s.svc.UpdateSPUStatus(ctx, "SPU601", domain.StatusOnShelf))
+	p, err = s.svc.FindBySN(ctx, "SKU601")
+	require.NoError(t, err)
+	assert.Equal(t, "新的月会员", p.SKU.Name)
+}
+
+func (s *HandlerTestSuite) TestStockCache() {
+	t := s.T()
+	ctx := context.Background()
+	_, err := s.dao.SaveSPU(ctx, dao.ProductSPU{
+		SN:   "SPU701",
+		Name: "会员服务",
+	}, []dao.ProductSKU{
+		{SN: "SKU701", Name: "月会员", Price: 990, Stock: 10, StockLimit: 10, SaleType: domain.SaleTypeUnlimited},
+	})
+	require.NoError(t, err)
+	require.NoError(t, s.svc.UpdateSPUStatus(ctx, "SPU701", domain.StatusOnShelf))
+	require.NoError(t, s.svc.UpdateSKUStatus(ctx, "SKU701", domain.StatusOnShelf))
+	p, err := s.svc.FindBySN(ctx, "SKU701")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), p.SKU.Stock)
+
+	// 预留库存之后缓存失效，看到的是最新的库存
+	err = s.svc.ReserveStock(ctx, "order-701", uid, []domain.StockItem{{SKUID: p.SKU.ID, Quantity: 2}})
+	require.NoError(t, err)
+	p, err = s.svc.FindBySN(ctx, "SKU701")
+	require.NoError(t, err)
+	assert.Equal(t, int64(8), p.SKU.Stock)
+
+	// 归还库存同理
+	require.NoError(t, s.svc.ReleaseStock(ctx, "order-701"))
+	p, err = s.svc.FindBySN(ctx, "SKU701")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), p.SKU.Stock)
+}
+
 func TestHandler(t *testing.T) {
 	suite.Run(t, new(HandlerTestSuite))
 }
@@ -24,6 +24,11 @@ import (
 )
 
 func InitHandler() (*web.Handler, error) {
-	wire.Build(testioc.BaseSet, product.InitHandler)
+	wire.Build(testioc.BaseSet, testioc.InitRedis, product.InitHandler)
 	return new(web.Handler), nil
 }
+
+func InitService() product.Service {
+	wire.Build(testioc.BaseSet, testioc.InitRedis, product.InitService)
+	return nil
+}
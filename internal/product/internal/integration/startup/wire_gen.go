@@ -8,6 +8,7 @@ package startup
 
 import (
 	"github.com/ecodeclub/webook/internal/product"
+	"github.com/ecodeclub/webook/internal/product/internal/service"
 	"github.com/ecodeclub/webook/internal/product/internal/web"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 )
@@ -16,6 +17,14 @@ import (
 
 func InitHandler() (*web.Handler, error) {
 	db := testioc.InitDB()
-	handler := product.InitHandler(db)
+	cmdable := testioc.InitRedis()
+	handler := product.InitHandler(db, cmdable)
 	return handler, nil
 }
+
+func InitService() service.Service {
+	db := testioc.InitDB()
+	cmdable := testioc.InitRedis()
+	serviceService := product.InitService(db, cmdable)
+	return serviceService
+}
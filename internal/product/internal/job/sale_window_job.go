@@ -0,0 +1,57 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/product/internal/service"
+)
+
+// SaleWindowJob 在限时促销、预售商品的销售时间窗口边界上自动上架、下架 SKU
+// 每次运行处理 (上次运行时间, 当前时间] 之间跨越的边界
+type SaleWindowJob struct {
+	svc     service.Service
+	lastRun int64
+	timeout time.Duration
+}
+
+func NewSaleWindowJob(svc service.Service, interval time.Duration, timeout time.Duration) *SaleWindowJob {
+	return &SaleWindowJob{
+		svc:     svc,
+		lastRun: time.Now().Add(-interval).UnixMilli(),
+		timeout: timeout,
+	}
+}
+
+func (s *SaleWindowJob) Name() string {
+	return "SaleWindowJob"
+}
+
+func (s *SaleWindowJob) Run() error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), s.timeout)
+	defer cancelFunc()
+
+	now := time.Now().UnixMilli()
+	// 窗口为左闭右开，所以这里 +1 保证边界正好等于 now 的 SKU 本次就会被处理
+	err := s.svc.SyncSaleWindow(ctx, s.lastRun+1, now+1)
+	if err != nil {
+		return fmt.Errorf("同步商品销售时间窗口失败: %w", err)
+	}
+	s.lastRun = now
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/product/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+// ProductCache 以 SKU SN 为键缓存上架的商品，分为本地缓存和 Redis 两级
+// 本地缓存无法在多个实例之间失效，所以过期时间很短
+// 库存只用于预检，真正的扣减在预留库存的时候原子地完成。预留和归还库存之后都会失效缓存，
+// 只有其他实例的本地缓存会在短时间内不一致
+type ProductCache interface {
+	// GetProducts 只返回命中缓存的商品
+	GetProducts(ctx context.Context, sns []string) (map[string]domain.Product, error)
+	SetProducts(ctx context.Context, products []domain.Product) error
+	DelProducts(ctx context.Context, sns []string) error
+}
+
+type productCache struct {
+	local           ecache.Cache
+	cmd             redis.Cmdable
+	localExpiration time.Duration
+	expiration      time.Duration
+}
+
+func NewProductCache(local ecache.Cache, cmd redis.Cmdable) ProductCache {
+	return &productCache{
+		local:           local,
+		cmd:             cmd,
+		localExpiration: time.Second * 10,
+		expiration:      time.Minute * 10,
+	}
+}
+
+func (p *productCache) GetProducts(ctx context.Context, sns []string) (map[string]domain.Product, error) {
+	res := make(map[string]domain.Product, len(sns))
+	missing := make([]string, 0, len(sns))
+	for _, sn := range sns {
+		val := p.local.Get(ctx, p.key(sn))
+		if prod, ok := val.Val.(domain.Product); ok && val.Err == nil {
+			res[sn] = prod
+			continue
+		}
+		missing = append(missing, sn)
+	}
+	if len(missing) == 0 {
+		return res, nil
+	}
+
+	vals, err := p.cmd.MGet(ctx, slice.Map(missing, func(idx int, src string) string {
+		return p.key(src)
+	})...).Result()
+	if err != nil {
+		return res, err
+	}
+	for i, val := range vals {
+		str, ok := val.(string)
+		if !ok {
+			continue
+		}
+		var prod domain.Product
+		if err = json.Unmarshal([]byte(str), &prod); err != nil {
+			return res, err
+		}
+		res[missing[i]] = prod
+		_ = p.local.Set(ctx, p.key(missing[i]), prod, p.localExpiration)
+	}
+	return res, nil
+}
+
+func (p *productCache) SetProducts(ctx context.Context, products []domain.Product) error {
+	if len(products) == 0 {
+		return nil
+	}
+	pipe := p.cmd.Pipeline()
+	for _, prod := range products {
+		val, err := json.Marshal(prod)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, p.key(prod.SKU.SN), val, p.expiration)
+		_ = p.local.Set(ctx, p.key(prod.SKU.SN), prod, p.localExpiration)
+	}
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (p *productCache) DelProducts(ctx context.Context, sns []string) error {
+	if len(sns) == 0 {
+		return nil
+	}
+	keys := slice.Map(sns, func(idx int, src string) string {
+		return p.key(src)
+	})
+	_, _ = p.local.Delete(ctx, keys...)
+	return p.cmd.Del(ctx, keys...).Err()
+}
+
+func (p *productCache) key(sn string) string {
+	return fmt.Sprintf("webook:product:sku:%s", sn)
+}
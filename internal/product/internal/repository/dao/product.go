@@ -16,9 +16,17 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/ego-component/egorm"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrDuplicateSN    = errors.New("商品 SN 已存在")
+	ErrRecordNotFound = gorm.ErrRecordNotFound
 )
 
 type ProductDAO interface {
@@ -26,6 +34,31 @@ type ProductDAO interface {
 	FindSKUBySN(ctx context.Context, sn string) (ProductSKU, error)
 	CreateSPU(ctx context.Context, spu ProductSPU) (int64, error)
 	CreateSKU(ctx context.Context, sku ProductSKU) (int64, error)
+
+	// SaveSPU 保存 SPU 以及它的 SKU，Id 为 0 的时候新建，否则更新
+	// 新建的 SPU 和 SKU 都处于下架状态，状态只能通过 UpdateSPUStatus 和 UpdateSKUStatus 修改
+	SaveSPU(ctx context.Context, spu ProductSPU, skus []ProductSKU) (int64, error)
+	UpdateSPUStatus(ctx context.Context, sn string, status int64) error
+	UpdateSKUStatus(ctx context.Context, sn string, status int64) error
+	// GetSPUBySN 不区分上下架状态
+	GetSPUBySN(ctx context.Context, sn string) (ProductSPU, error)
+	// ListSPUs 不区分上下架状态
+	ListSPUs(ctx context.Context, offset, limit int) ([]ProductSPU, error)
+	CountSPUs(ctx context.Context) (int64, error)
+	ListOnShelfSPUs(ctx context.Context, offset, limit int) ([]ProductSPU, error)
+	CountOnShelfSPUs(ctx context.Context) (int64, error)
+	// FindSKUsBySPUIDs 不区分上下架状态
+	FindSKUsBySPUIDs(ctx context.Context, spuIDs []int64) ([]ProductSKU, error)
+	FindOnShelfSKUsBySPUIDs(ctx context.Context, spuIDs []int64) ([]ProductSKU, error)
+	FindSKUsByIDs(ctx context.Context, ids []int64) ([]ProductSKU, error)
+	FindOnShelfSKUsBySNs(ctx context.Context, sns []string) ([]ProductSKU, error)
+	FindOnShelfSPUsByIDs(ctx context.Context, ids []int64) ([]ProductSPU, error)
+	// FindSKUsBySaleBoundary 查找销售开始时间或者结束时间在 [start, end) 之间的 SKU
+	FindSKUsBySaleBoundary(ctx context.Context, start, end int64) ([]ProductSKU, error)
+	// OnShelfBySaleStart 上架销售开始时间在 [start, end) 之间的 SKU
+	OnShelfBySaleStart(ctx context.Context, start, end int64) (int64, error)
+	// OffShelfBySaleEnd 下架销售结束时间在 [start, end) 之间的 SKU
+	OffShelfBySaleEnd(ctx context.Context, start, end int64) (int64, error)
 }
 
 type ProductGORMDAO struct {
@@ -57,13 +90,208 @@ func (d *ProductGORMDAO) FindSPUByID(ctx context.Context, id int64) (ProductSPU,
 func (d *ProductGORMDAO) CreateSPU(ctx context.Context, spu ProductSPU) (int64, error) {
 	now := time.Now()
 	spu.Utime, spu.Ctime = now.UnixMilli(), now.UnixMilli()
-	return spu.Id, d.db.WithContext(ctx).Create(&spu).Error
+	err := d.db.WithContext(ctx).Create(&spu).Error
+	return spu.Id, err
 }
 
 func (d *ProductGORMDAO) CreateSKU(ctx context.Context, sku ProductSKU) (int64, error) {
 	now := time.Now()
 	sku.Utime, sku.Ctime = now.UnixMilli(), now.UnixMilli()
-	return sku.Id, d.db.WithContext(ctx).Create(&sku).Error
+	err := d.db.WithContext(ctx).Create(&sku).Error
+	return sku.Id, err
+}
+
+func (d *ProductGORMDAO) SaveSPU(ctx context.Context, spu ProductSPU, skus []ProductSKU) (int64, error) {
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		spu.Utime = now
+		if spu.Id == 0 {
+			spu.Ctime = now
+			spu.Status = StatusOffShelf
+			if err := tx.Create(&spu).Error; err != nil {
+				return err
+			}
+		} else {
+			res := tx.Model(&ProductSPU{}).Where("id = ?", spu.Id).Updates(map[string]any{
+				"sn":          spu.SN,
+				"name":        spu.Name,
+				"description": spu.Description,
+				"utime":       now,
+			})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		for _, sku := range skus {
+			sku.ProductSPUID = spu.Id
+			sku.Utime = now
+			if sku.Id == 0 {
+				sku.Ctime = now
+				sku.Status = StatusOffShelf
+				if err := tx.Create(&sku).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			res := tx.Model(&ProductSKU{}).
+				Where("id = ? AND product_spu_id = ?", sku.Id, spu.Id).
+				Updates(map[string]any{
+					"sn":              sku.SN,
+					"name":            sku.Name,
+					"description":     sku.Description,
+					"price":           sku.Price,
+					"stock":           sku.Stock,
+					"stock_limit":     sku.StockLimit,
+					"sale_type":       sku.SaleType,
+					"sale_start":      sku.SaleStart,
+					"sale_end":        sku.SaleEnd,
+					"promotion_price": sku.PromotionPrice,
+					"release_at":      sku.ReleaseAt,
+					"utime":           now,
+				})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+		return nil
+	})
+	if d.isDuplicateErr(err) {
+		return 0, ErrDuplicateSN
+	}
+	return spu.Id, err
+}
+
+func (d *ProductGORMDAO) isDuplicateErr(err error) bool {
+	var me *mysql.MySQLError
+	if errors.As(err, &me) {
+		const uniqueIndexErrNo uint16 = 1062
+		return me.Number == uniqueIndexErrNo
+	}
+	return false
+}
+
+func (d *ProductGORMDAO) UpdateSPUStatus(ctx context.Context, sn string, status int64) error {
+	return d.updateStatus(ctx, &ProductSPU{}, sn, status)
+}
+
+func (d *ProductGORMDAO) UpdateSKUStatus(ctx context.Context, sn string, status int64) error {
+	return d.updateStatus(ctx, &ProductSKU{}, sn, status)
+}
+
+func (d *ProductGORMDAO) updateStatus(ctx context.Context, model any, sn string, status int64) error {
+	res := d.db.WithContext(ctx).Model(model).Where("sn = ?", sn).Updates(map[string]any{
+		"status": status,
+		"utime":  time.Now().UnixMilli(),
+	})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (d *ProductGORMDAO) GetSPUBySN(ctx context.Context, sn string) (ProductSPU, error) {
+	var res ProductSPU
+	err := d.db.WithContext(ctx).Where("sn = ?", sn).First(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) ListSPUs(ctx context.Context, offset, limit int) ([]ProductSPU, error) {
+	var res []ProductSPU
+	err := d.db.WithContext(ctx).Order("id DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) CountSPUs(ctx context.Context) (int64, error) {
+	var res int64
+	err := d.db.WithContext(ctx).Model(&ProductSPU{}).Count(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) ListOnShelfSPUs(ctx context.Context, offset, limit int) ([]ProductSPU, error) {
+	var res []ProductSPU
+	err := d.db.WithContext(ctx).Where("status = ?", StatusOnShelf).Order("id DESC").
+		Offset(offset).Limit(limit).Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) CountOnShelfSPUs(ctx context.Context) (int64, error) {
+	var res int64
+	err := d.db.WithContext(ctx).Model(&ProductSPU{}).
+		Where("status = ?", StatusOnShelf).Count(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) FindSKUsBySPUIDs(ctx context.Context, spuIDs []int64) ([]ProductSKU, error) {
+	var res []ProductSKU
+	err := d.db.WithContext(ctx).Where("product_spu_id IN ?", spuIDs).
+		Order("id ASC").Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) FindOnShelfSKUsBySPUIDs(ctx context.Context, spuIDs []int64) ([]ProductSKU, error) {
+	var res []ProductSKU
+	err := d.db.WithContext(ctx).Where("product_spu_id IN ? AND status = ?", spuIDs, StatusOnShelf).
+		Order("id ASC").Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) FindSKUsByIDs(ctx context.Context, ids []int64) ([]ProductSKU, error) {
+	var res []ProductSKU
+	err := d.db.WithContext(ctx).Where("id IN ?", ids).Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) FindOnShelfSKUsBySNs(ctx context.Context, sns []string) ([]ProductSKU, error) {
+	var res []ProductSKU
+	err := d.db.WithContext(ctx).Where("sn IN ? AND status = ?", sns, StatusOnShelf).Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) FindOnShelfSPUsByIDs(ctx context.Context, ids []int64) ([]ProductSPU, error) {
+	var res []ProductSPU
+	err := d.db.WithContext(ctx).Where("id IN ? AND status = ?", ids, StatusOnShelf).Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) FindSKUsBySaleBoundary(ctx context.Context, start, end int64) ([]ProductSKU, error) {
+	var res []ProductSKU
+	err := d.db.WithContext(ctx).
+		Where("sale_type <> ?", SaleTypeUnlimited).
+		Where("(sale_start >= ? AND sale_start < ?) OR (sale_end >= ? AND sale_end < ?)", start, end, start, end).
+		Find(&res).Error
+	return res, err
+}
+
+func (d *ProductGORMDAO) OnShelfBySaleStart(ctx context.Context, start, end int64) (int64, error) {
+	res := d.db.WithContext(ctx).Model(&ProductSKU{}).
+		Where("sale_type <> ? AND sale_start >= ? AND sale_start < ?", SaleTypeUnlimited, start, end).
+		// 同时开始和结束的窗口不需要上架
+		Where("sale_end = 0 OR sale_end >= ?", end).
+		Updates(map[string]any{
+			"status": StatusOnShelf,
+			"utime":  time.Now().UnixMilli(),
+		})
+	return res.RowsAffected, res.Error
+}
+
+func (d *ProductGORMDAO) OffShelfBySaleEnd(ctx context.Context, start, end int64) (int64, error) {
+	res := d.db.WithContext(ctx).Model(&ProductSKU{}).
+		Where("sale_type <> ? AND sale_end >= ? AND sale_end < ?", SaleTypeUnlimited, start, end).
+		Updates(map[string]any{
+			"status": StatusOffShelf,
+			"utime":  time.Now().UnixMilli(),
+		})
+	return res.RowsAffected, res.Error
 }
 
 type ProductSPU struct {
@@ -77,23 +305,31 @@ type ProductSPU struct {
 }
 
 type ProductSKU struct {
-	Id           int64  `gorm:"primaryKey;autoIncrement;comment:商品SKU自增ID"`
-	SN           string `gorm:"type:varchar(255);not null;uniqueIndex:uniq_product_sku_sn;comment:商品SKU序列号"`
-	ProductSPUID int64  `gorm:"column:product_spu_id;not null;index:idx_product_spu_id;comment:商品SPU自增ID"`
-	Name         string `gorm:"type:varchar(255);not null;comment:SKU名称"`
-	Description  string `gorm:"not null;comment:商品描述"`
-	Price        int64  `gorm:"not null;comment:商品单价;单位为分, 999表示9.99元"`
-	Stock        int64  `gorm:"not null;comment:库存数量"`
-	StockLimit   int64  `gorm:"not null;comment:库存限制"`
-	SaleType     int64  `gorm:"type:tinyint unsigned;not null;default:1;comment:销售类型: 1=无限期 2=限时促销 3=预售"`
-	// SaleStart    sql.NullInt64   `gorm:"comment:销售开始时间,无限期销售为NULL"`
-	// SaleEnd      sql.NullInt64   `gorm:"comment:销售结束时间,无限期和预售为NULL"`
-	Status int64 `gorm:"type:tinyint unsigned;not null;default:1;comment:状态 1=下架 2=上架"`
-	Ctime  int64
-	Utime  int64
+	Id             int64  `gorm:"primaryKey;autoIncrement;comment:商品SKU自增ID"`
+	SN             string `gorm:"type:varchar(255);not null;uniqueIndex:uniq_product_sku_sn;comment:商品SKU序列号"`
+	ProductSPUID   int64  `gorm:"column:product_spu_id;not null;index:idx_product_spu_id;comment:商品SPU自增ID"`
+	Name           string `gorm:"type:varchar(255);not null;comment:SKU名称"`
+	Description    string `gorm:"not null;comment:商品描述"`
+	Price          int64  `gorm:"not null;comment:商品单价;单位为分, 999表示9.99元"`
+	Stock          int64  `gorm:"not null;comment:库存数量"`
+	StockLimit     int64  `gorm:"not null;comment:库存限制"`
+	SaleType       int64  `gorm:"type:tinyint unsigned;not null;default:1;comment:销售类型: 1=无限期 2=限时促销 3=预售"`
+	SaleStart      int64  `gorm:"not null;default:0;index:idx_sale_start;comment:销售开始时间,UTC Unix毫秒数,0表示立刻开始"`
+	SaleEnd        int64  `gorm:"not null;default:0;index:idx_sale_end;comment:销售结束时间,UTC Unix毫秒数,0表示不会结束"`
+	PromotionPrice int64  `gorm:"not null;default:0;comment:限时促销价格,单位为分"`
+	ReleaseAt      int64  `gorm:"not null;default:0;comment:预售商品的发售时间,UTC Unix毫秒数"`
+	Status         int64  `gorm:"type:tinyint unsigned;not null;default:1;comment:状态 1=下架 2=上架"`
+	Ctime          int64
+	Utime          int64
 }
 
 const (
 	StatusOffShelf = iota + 1 // 下架
 	StatusOnShelf             // 上架
 )
+
+const (
+	SaleTypeUnlimited = iota + 1 // 无限期
+	SaleTypePromotion            // 限时促销
+	SaleTypePresale              // 预售
+)
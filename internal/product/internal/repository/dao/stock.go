@@ -0,0 +1,170 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock = errors.New("商品库存不足")
+	ErrExceedStockLimit  = errors.New("超出商品限购数量")
+)
+
+const (
+	StockReservationStatusReserved  = iota + 1 // 已预留
+	StockReservationStatusCommitted            // 已确认
+	StockReservationStatusReleased             // 已释放
+)
+
+// StockDAO 库存预留
+// 下单时预留（扣减）库存，订单完成时确认，订单取消或者超时时释放（归还）库存
+type StockDAO interface {
+	// Reserve 在同一个事务中扣减所有 SKU 的库存，任何一个 SKU 库存不足或者超出限购数量都会整体回滚
+	// 同一个订单重复预留的时候直接返回
+	Reserve(ctx context.Context, orderSN string, uid int64, items []StockReservation) error
+	Commit(ctx context.Context, orderSN string) error
+	// Release 归还订单还处于预留状态的库存，返回库存发生了变化的 SKU ID
+	Release(ctx context.Context, orderSN string) ([]int64, error)
+}
+
+type StockGORMDAO struct {
+	db *gorm.DB
+}
+
+func NewStockGORMDAO(db *gorm.DB) StockDAO {
+	return &StockGORMDAO{db: db}
+}
+
+func (d *StockGORMDAO) Reserve(ctx context.Context, orderSN string, uid int64, items []StockReservation) error {
+	return d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var cnt int64
+		err := tx.Model(&StockReservation{}).Where("order_sn = ?", orderSN).Count(&cnt).Error
+		if err != nil {
+			return err
+		}
+		if cnt > 0 {
+			return nil
+		}
+		now := time.Now().UnixMilli()
+		for _, item := range items {
+			// 乐观扣减库存，同时持有 SKU 的行锁，
+			// 同一个 SKU 的并发下单在这里串行，所以后面统计用户已购数量是安全的
+			res := tx.Model(&ProductSKU{}).
+				Where("id = ? AND stock >= ?", item.SKUID, item.Quantity).
+				Updates(map[string]any{
+					"stock": gorm.Expr("stock - ?", item.Quantity),
+					"utime": now,
+				})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return ErrInsufficientStock
+			}
+			var sku ProductSKU
+			err = tx.Select("stock_limit").Where("id = ?", item.SKUID).First(&sku).Error
+			if err != nil {
+				return err
+			}
+			var bought int64
+			err = tx.Model(&StockReservation{}).
+				Clauses(clause.Locking{Strength: "SHARE"}).
+				Select("COALESCE(SUM(quantity), 0)").
+				Where("uid = ? AND sku_id = ? AND status IN ?", uid, item.SKUID,
+					[]int64{StockReservationStatusReserved, StockReservationStatusCommitted}).
+				Scan(&bought).Error
+			if err != nil {
+				return err
+			}
+			if bought+item.Quantity > sku.StockLimit {
+				return ErrExceedStockLimit
+			}
+			item.OrderSN, item.Uid = orderSN, uid
+			item.Status = StockReservationStatusReserved
+			item.Ctime, item.Utime = now, now
+			err = tx.Create(&item).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (d *StockGORMDAO) Commit(ctx context.Context, orderSN string) error {
+	return d.db.WithContext(ctx).Model(&StockReservation{}).
+		Where("order_sn = ? AND status = ?", orderSN, StockReservationStatusReserved).
+		Updates(map[string]any{
+			"status": StockReservationStatusCommitted,
+			"utime":  time.Now().UnixMilli(),
+		}).Error
+}
+
+func (d *StockGORMDAO) Release(ctx context.Context, orderSN string) ([]int64, error) {
+	var items []StockReservation
+	err := d.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("order_sn = ? AND status = ?", orderSN, StockReservationStatusReserved).
+			Find(&items).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		for _, item := range items {
+			err = tx.Model(&ProductSKU{}).Where("id = ?", item.SKUID).
+				Updates(map[string]any{
+					"stock": gorm.Expr("stock + ?", item.Quantity),
+					"utime": now,
+				}).Error
+			if err != nil {
+				return err
+			}
+			err = tx.Model(&StockReservation{}).Where("id = ?", item.Id).
+				Updates(map[string]any{
+					"status": StockReservationStatusReleased,
+					"utime":  now,
+				}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(items, func(idx int, src StockReservation) int64 {
+		return src.SKUID
+	}), nil
+}
+
+// StockReservation 库存预留记录，同时用来统计用户的已购数量
+type StockReservation struct {
+	Id       int64  `gorm:"primaryKey;autoIncrement;comment:库存预留记录自增ID"`
+	OrderSN  string `gorm:"type:varchar(255);not null;uniqueIndex:uniq_order_sn_sku_id;comment:订单序列号"`
+	SKUID    int64  `gorm:"column:sku_id;not null;uniqueIndex:uniq_order_sn_sku_id;index:idx_uid_sku_id,priority:2;comment:商品SKU自增ID"`
+	Uid      int64  `gorm:"not null;index:idx_uid_sku_id,priority:1;comment:购买者ID"`
+	Quantity int64  `gorm:"not null;comment:预留数量"`
+	Status   int64  `gorm:"type:tinyint unsigned;not null;default:1;comment:状态 1=已预留 2=已确认 3=已释放"`
+	Ctime    int64
+	Utime    int64
+}
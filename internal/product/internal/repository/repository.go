@@ -16,58 +16,397 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/product/internal/domain"
+	"github.com/ecodeclub/webook/internal/product/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/product/internal/repository/dao"
 	"github.com/gotomicro/ego/core/elog"
+	"golang.org/x/sync/errgroup"
 )
 
 type ProductRepository interface {
 	FindBySN(ctx context.Context, sn string) (domain.Product, error)
+	// FindBySNs 按照 sns 的顺序返回上架的商品，任何一个 SN 找不到都会返回错误
+	FindBySNs(ctx context.Context, sns []string) ([]domain.Product, error)
+	SaveSPU(ctx context.Context, spu domain.SPU) (int64, error)
+	UpdateSPUStatus(ctx context.Context, sn string, status int64) error
+	UpdateSKUStatus(ctx context.Context, sn string, status int64) error
+	// FindSPUBySN 包含所有的 SKU，不区分上下架状态
+	FindSPUBySN(ctx context.Context, sn string) (domain.SPU, error)
+	// ListSPUs 包含所有的 SKU，不区分上下架状态
+	ListSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error)
+	// ListOnShelfSPUs 只包含上架的 SPU 和 SKU
+	ListOnShelfSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error)
+
+	ReserveStock(ctx context.Context, orderSN string, uid int64, items []domain.StockItem) error
+	CommitStock(ctx context.Context, orderSN string) error
+	ReleaseStock(ctx context.Context, orderSN string) error
+
+	// FindSKUsByIDs 不区分上下架状态
+	FindSKUsByIDs(ctx context.Context, ids []int64) ([]domain.SKU, error)
+	// SyncSaleWindow 上架销售开始时间在 [start, end) 之间的 SKU，下架销售结束时间在 [start, end) 之间的 SKU
+	SyncSaleWindow(ctx context.Context, start, end int64) error
 }
 
-func NewProductRepository(d dao.ProductDAO) ProductRepository {
+var ErrDuplicateSN = dao.ErrDuplicateSN
+
+func NewProductRepository(d dao.ProductDAO, sd dao.StockDAO, c cache.ProductCache) ProductRepository {
 	return &productRepository{
-		dao:    d,
-		logger: elog.DefaultLogger}
+		dao:      d,
+		stockDAO: sd,
+		cache:    c,
+		logger:   elog.DefaultLogger}
 }
 
 type productRepository struct {
-	dao    dao.ProductDAO
-	logger *elog.Component
+	dao      dao.ProductDAO
+	stockDAO dao.StockDAO
+	cache    cache.ProductCache
+	logger   *elog.Component
 }
 
 func (p *productRepository) FindBySN(ctx context.Context, sn string) (domain.Product, error) {
-	sku, err := p.dao.FindSKUBySN(ctx, sn)
+	res, err := p.FindBySNs(ctx, []string{sn})
 	if err != nil {
 		return domain.Product{}, err
 	}
-	spu, err := p.dao.FindSPUByID(ctx, sku.ProductSPUID)
+	return res[0], nil
+}
+
+func (p *productRepository) FindBySNs(ctx context.Context, sns []string) ([]domain.Product, error) {
+	cached, err := p.cache.GetProducts(ctx, sns)
 	if err != nil {
-		return domain.Product{}, err
+		// 缓存出错的时候退化为查询数据库
+		p.logger.Error("从缓存中获取商品失败", elog.FieldErr(err), elog.Any("sns", sns))
+	}
+	missing := slice.FilterMap(sns, func(idx int, src string) (string, bool) {
+		_, ok := cached[src]
+		return src, !ok
+	})
+	if len(missing) > 0 {
+		products, err := p.findBySNs(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		if err = p.cache.SetProducts(ctx, products); err != nil {
+			p.logger.Error("缓存商品失败", elog.FieldErr(err), elog.Any("sns", missing))
+		}
+		if cached == nil {
+			cached = make(map[string]domain.Product, len(products))
+		}
+		for _, prod := range products {
+			cached[prod.SKU.SN] = prod
+		}
+	}
+	res := make([]domain.Product, 0, len(sns))
+	for _, sn := range sns {
+		prod, ok := cached[sn]
+		if !ok {
+			return nil, fmt.Errorf("%w: SKU %s", domain.ErrNotFound, sn)
+		}
+		res = append(res, prod)
+	}
+	return res, nil
+}
+
+// findBySNs 一次查询所有的 SKU，再一次查询它们的 SPU，SKU 或者 SPU 下架的商品会被忽略
+func (p *productRepository) findBySNs(ctx context.Context, sns []string) ([]domain.Product, error) {
+	skus, err := p.dao.FindOnShelfSKUsBySNs(ctx, sns)
+	if err != nil || len(skus) == 0 {
+		return nil, err
+	}
+	spus, err := p.dao.FindOnShelfSPUsByIDs(ctx, slice.Map(skus, func(idx int, src dao.ProductSKU) int64 {
+		return src.ProductSPUID
+	}))
+	if err != nil {
+		return nil, err
+	}
+	spuMap := make(map[int64]dao.ProductSPU, len(spus))
+	for _, spu := range spus {
+		spuMap[spu.Id] = spu
+	}
+	return slice.FilterMap(skus, func(idx int, src dao.ProductSKU) (domain.Product, bool) {
+		spu, ok := spuMap[src.ProductSPUID]
+		return p.toDomainProduct(spu, src), ok
+	}), nil
+}
+
+func (p *productRepository) SaveSPU(ctx context.Context, spu domain.SPU) (int64, error) {
+	sns := slice.Map(spu.SKUs, func(idx int, src domain.SKU) string {
+		return src.SN
+	})
+	if spu.ID != 0 {
+		// SKU 的 SN 可能被修改，所以旧的 SN 也需要失效
+		skus, err := p.dao.FindSKUsBySPUIDs(ctx, []int64{spu.ID})
+		if err != nil {
+			return 0, err
+		}
+		sns = append(sns, slice.Map(skus, func(idx int, src dao.ProductSKU) string {
+			return src.SN
+		})...)
+	}
+	id, err := p.dao.SaveSPU(ctx, dao.ProductSPU{
+		Id:          spu.ID,
+		SN:          spu.SN,
+		Name:        spu.Name,
+		Description: spu.Desc,
+	}, slice.Map(spu.SKUs, func(idx int, src domain.SKU) dao.ProductSKU {
+		return p.toEntitySKU(src)
+	}))
+	if err != nil {
+		return 0, err
+	}
+	p.invalidate(ctx, sns)
+	return id, nil
+}
+
+func (p *productRepository) UpdateSPUStatus(ctx context.Context, sn string, status int64) error {
+	err := p.dao.UpdateSPUStatus(ctx, sn, status)
+	if err != nil {
+		return err
+	}
+	spu, err := p.dao.GetSPUBySN(ctx, sn)
+	if err != nil {
+		return err
+	}
+	skus, err := p.dao.FindSKUsBySPUIDs(ctx, []int64{spu.Id})
+	if err != nil {
+		return err
+	}
+	p.invalidate(ctx, slice.Map(skus, func(idx int, src dao.ProductSKU) string {
+		return src.SN
+	}))
+	return nil
+}
+
+func (p *productRepository) UpdateSKUStatus(ctx context.Context, sn string, status int64) error {
+	err := p.dao.UpdateSKUStatus(ctx, sn, status)
+	if err != nil {
+		return err
+	}
+	p.invalidate(ctx, []string{sn})
+	return nil
+}
+
+// invalidate 数据库已经修改成功，缓存失效失败只记录日志，依赖过期时间兜底
+func (p *productRepository) invalidate(ctx context.Context, sns []string) {
+	if err := p.cache.DelProducts(ctx, sns); err != nil {
+		p.logger.Error("删除商品缓存失败", elog.FieldErr(err), elog.Any("sns", sns))
+	}
+}
+
+// invalidateSKUs 库存变化之后按照 SKU ID 失效缓存
+func (p *productRepository) invalidateSKUs(ctx context.Context, ids []int64) {
+	if len(ids) == 0 {
+		return
+	}
+	skus, err := p.dao.FindSKUsByIDs(ctx, ids)
+	if err != nil {
+		p.logger.Error("查找需要失效缓存的商品失败", elog.FieldErr(err), elog.Any("ids", ids))
+		return
+	}
+	p.invalidate(ctx, slice.Map(skus, func(idx int, src dao.ProductSKU) string {
+		return src.SN
+	}))
+}
+
+func (p *productRepository) FindSPUBySN(ctx context.Context, sn string) (domain.SPU, error) {
+	spu, err := p.dao.GetSPUBySN(ctx, sn)
+	if err != nil {
+		return domain.SPU{}, err
+	}
+	skus, err := p.dao.FindSKUsBySPUIDs(ctx, []int64{spu.Id})
+	if err != nil {
+		return domain.SPU{}, err
+	}
+	res := p.toDomainSPU(spu)
+	res.SKUs = slice.Map(skus, func(idx int, src dao.ProductSKU) domain.SKU {
+		return p.toDomainSKU(src)
+	})
+	return res, nil
+}
+
+func (p *productRepository) ListSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error) {
+	var (
+		eg    errgroup.Group
+		spus  []dao.ProductSPU
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		spus, err = p.dao.ListSPUs(ctx, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = p.dao.CountSPUs(ctx)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, 0, err
+	}
+	res, err := p.withSKUs(ctx, spus, p.dao.FindSKUsBySPUIDs)
+	return res, total, err
+}
+
+func (p *productRepository) ListOnShelfSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error) {
+	var (
+		eg    errgroup.Group
+		spus  []dao.ProductSPU
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		spus, err = p.dao.ListOnShelfSPUs(ctx, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = p.dao.CountOnShelfSPUs(ctx)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, 0, err
+	}
+	res, err := p.withSKUs(ctx, spus, p.dao.FindOnShelfSKUsBySPUIDs)
+	return res, total, err
+}
+
+// withSKUs 批量查询 SKU 并且组装到对应的 SPU 上
+func (p *productRepository) withSKUs(ctx context.Context, spus []dao.ProductSPU,
+	findSKUs func(ctx context.Context, spuIDs []int64) ([]dao.ProductSKU, error)) ([]domain.SPU, error) {
+	if len(spus) == 0 {
+		return []domain.SPU{}, nil
+	}
+	skus, err := findSKUs(ctx, slice.Map(spus, func(idx int, src dao.ProductSPU) int64 {
+		return src.Id
+	}))
+	if err != nil {
+		return nil, err
+	}
+	skuMap := make(map[int64][]domain.SKU, len(spus))
+	for _, sku := range skus {
+		skuMap[sku.ProductSPUID] = append(skuMap[sku.ProductSPUID], p.toDomainSKU(sku))
+	}
+	return slice.Map(spus, func(idx int, src dao.ProductSPU) domain.SPU {
+		res := p.toDomainSPU(src)
+		res.SKUs = skuMap[src.Id]
+		return res
+	}), nil
+}
+
+func (p *productRepository) ReserveStock(ctx context.Context, orderSN string, uid int64, items []domain.StockItem) error {
+	err := p.stockDAO.Reserve(ctx, orderSN, uid, slice.Map(items, func(idx int, src domain.StockItem) dao.StockReservation {
+		return dao.StockReservation{
+			SKUID:    src.SKUID,
+			Quantity: src.Quantity,
+		}
+	}))
+	switch {
+	case errors.Is(err, dao.ErrInsufficientStock):
+		return domain.ErrInsufficientStock
+	case errors.Is(err, dao.ErrExceedStockLimit):
+		return domain.ErrExceedStockLimit
+	case err != nil:
+		return err
+	}
+	p.invalidateSKUs(ctx, slice.Map(items, func(idx int, src domain.StockItem) int64 {
+		return src.SKUID
+	}))
+	return nil
+}
+
+func (p *productRepository) CommitStock(ctx context.Context, orderSN string) error {
+	return p.stockDAO.Commit(ctx, orderSN)
+}
+
+func (p *productRepository) ReleaseStock(ctx context.Context, orderSN string) error {
+	ids, err := p.stockDAO.Release(ctx, orderSN)
+	if err != nil {
+		return err
+	}
+	p.invalidateSKUs(ctx, ids)
+	return nil
+}
+
+func (p *productRepository) FindSKUsByIDs(ctx context.Context, ids []int64) ([]domain.SKU, error) {
+	skus, err := p.dao.FindSKUsByIDs(ctx, ids)
+	return slice.Map(skus, func(idx int, src dao.ProductSKU) domain.SKU {
+		return p.toDomainSKU(src)
+	}), err
+}
+
+func (p *productRepository) SyncSaleWindow(ctx context.Context, start, end int64) error {
+	skus, err := p.dao.FindSKUsBySaleBoundary(ctx, start, end)
+	if err != nil || len(skus) == 0 {
+		return err
+	}
+	// 先上架再下架，窗口很短的 SKU 最终会处于下架状态
+	_, err = p.dao.OnShelfBySaleStart(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	_, err = p.dao.OffShelfBySaleEnd(ctx, start, end)
+	if err != nil {
+		return err
+	}
+	p.invalidate(ctx, slice.Map(skus, func(idx int, src dao.ProductSKU) string {
+		return src.SN
+	}))
+	return nil
+}
+
+func (p *productRepository) toDomainSPU(spu dao.ProductSPU) domain.SPU {
+	return domain.SPU{
+		ID:     spu.Id,
+		SN:     spu.SN,
+		Name:   spu.Name,
+		Desc:   spu.Description,
+		Status: spu.Status,
+	}
+}
+
+func (p *productRepository) toDomainSKU(sku dao.ProductSKU) domain.SKU {
+	return domain.SKU{
+		ID:             sku.Id,
+		SN:             sku.SN,
+		Name:           sku.Name,
+		Desc:           sku.Description,
+		Price:          sku.Price,
+		Stock:          sku.Stock,
+		StockLimit:     sku.StockLimit,
+		SaleType:       sku.SaleType,
+		SaleStart:      sku.SaleStart,
+		SaleEnd:        sku.SaleEnd,
+		PromotionPrice: sku.PromotionPrice,
+		ReleaseAt:      sku.ReleaseAt,
+		Status:         sku.Status,
+	}
+}
+
+func (p *productRepository) toEntitySKU(sku domain.SKU) dao.ProductSKU {
+	return dao.ProductSKU{
+		Id:             sku.ID,
+		SN:             sku.SN,
+		Name:           sku.Name,
+		Description:    sku.Desc,
+		Price:          sku.Price,
+		Stock:          sku.Stock,
+		StockLimit:     sku.StockLimit,
+		SaleType:       sku.SaleType,
+		SaleStart:      sku.SaleStart,
+		SaleEnd:        sku.SaleEnd,
+		PromotionPrice: sku.PromotionPrice,
+		ReleaseAt:      sku.ReleaseAt,
 	}
-	return p.toDomainProduct(spu, sku), err
 }
 
 func (p *productRepository) toDomainProduct(spu dao.ProductSPU, sku dao.ProductSKU) domain.Product {
 	return domain.Product{
-		SPU: domain.SPU{
-			ID:     spu.Id,
-			SN:     spu.SN,
-			Name:   spu.Name,
-			Desc:   spu.Description,
-			Status: spu.Status,
-		},
-		SKU: domain.SKU{
-			ID:         sku.Id,
-			SN:         sku.SN,
-			Name:       sku.Name,
-			Desc:       sku.Description,
-			Price:      sku.Price,
-			Stock:      sku.Stock,
-			StockLimit: sku.StockLimit,
-			SaleType:   sku.SaleType,
-			Status:     sku.Status,
-		},
+		SPU: p.toDomainSPU(spu),
+		SKU: p.toDomainSKU(sku),
 	}
 }
@@ -16,15 +16,48 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
 
 	"github.com/ecodeclub/webook/internal/product/internal/domain"
 	"github.com/ecodeclub/webook/internal/product/internal/repository"
 )
 
 type Service interface {
+	// FindBySN 查找上架并且在销售时间窗口内的商品
+	// 商品不存在或者已经下架返回 domain.ErrNotFound，不在销售时间内返回 domain.ErrNotOnSale
 	FindBySN(ctx context.Context, sn string) (domain.Product, error)
+	// FindBySNs 批量查找商品，按照 sns 的顺序返回，要求同 FindBySN
+	FindBySNs(ctx context.Context, sns []string) ([]domain.Product, error)
+	// SaveSPU 保存 SPU 以及它的 SKU，返回 SPU 的 ID
+	// 商品信息非法的时候返回 domain.ErrInvalidProduct，SN 重复的时候返回 ErrDuplicateSN
+	SaveSPU(ctx context.Context, spu domain.SPU) (int64, error)
+	UpdateSPUStatus(ctx context.Context, sn string, status int64) error
+	UpdateSKUStatus(ctx context.Context, sn string, status int64) error
+	// FindSPUBySN 给创作者使用，不区分上下架状态
+	FindSPUBySN(ctx context.Context, sn string) (domain.SPU, error)
+	// ListSPUs 给创作者使用，不区分上下架状态
+	ListSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error)
+	// ListOnShelfSPUs 公开的商品目录，只包含上架的 SPU 和 SKU
+	ListOnShelfSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error)
+
+	// ReserveStock 下单的时候预留库存，所有 SKU 要么全部预留成功，要么全部失败
+	// 同一个 SKU 出现多次的时候按照总数量预留
+	// 库存不足返回 domain.ErrInsufficientStock，超出用户限购数量返回 domain.ErrExceedStockLimit
+	// 同一个订单重复预留不会重复扣减
+	ReserveStock(ctx context.Context, orderSN string, uid int64, items []domain.StockItem) error
+	// CommitStock 订单完成之后确认预留的库存
+	CommitStock(ctx context.Context, orderSN string) error
+	// ReleaseStock 订单取消或者超时之后释放预留的库存，重复释放不会重复归还
+	ReleaseStock(ctx context.Context, orderSN string) error
+	// SyncSaleWindow 在销售时间窗口的边界上架或者下架 SKU，[start, end) 为 UTC Unix毫秒数
+	SyncSaleWindow(ctx context.Context, start, end int64) error
 }
 
+var ErrDuplicateSN = repository.ErrDuplicateSN
+
 func NewService(repo repository.ProductRepository) Service {
 	return &service{repo: repo}
 }
@@ -34,5 +67,111 @@ type service struct {
 }
 
 func (s *service) FindBySN(ctx context.Context, sn string) (domain.Product, error) {
-	return s.repo.FindBySN(ctx, sn)
+	p, err := s.repo.FindBySN(ctx, sn)
+	if err != nil {
+		return domain.Product{}, err
+	}
+	if !p.SKU.OnSale(time.Now().UnixMilli()) {
+		return domain.Product{}, fmt.Errorf("%w: SKU %s", domain.ErrNotOnSale, sn)
+	}
+	return p, nil
+}
+
+func (s *service) FindBySNs(ctx context.Context, sns []string) ([]domain.Product, error) {
+	products, err := s.repo.FindBySNs(ctx, sns)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UnixMilli()
+	for _, p := range products {
+		if !p.SKU.OnSale(now) {
+			return nil, fmt.Errorf("%w: SKU %s", domain.ErrNotOnSale, p.SKU.SN)
+		}
+	}
+	return products, nil
+}
+
+func (s *service) SaveSPU(ctx context.Context, spu domain.SPU) (int64, error) {
+	if err := spu.Validate(); err != nil {
+		return 0, err
+	}
+	return s.repo.SaveSPU(ctx, spu)
+}
+
+func (s *service) UpdateSPUStatus(ctx context.Context, sn string, status int64) error {
+	if !domain.IsValidStatus(status) {
+		return fmt.Errorf("%w: 状态 %d 非法", domain.ErrInvalidProduct, status)
+	}
+	return s.repo.UpdateSPUStatus(ctx, sn, status)
+}
+
+func (s *service) UpdateSKUStatus(ctx context.Context, sn string, status int64) error {
+	if !domain.IsValidStatus(status) {
+		return fmt.Errorf("%w: 状态 %d 非法", domain.ErrInvalidProduct, status)
+	}
+	return s.repo.UpdateSKUStatus(ctx, sn, status)
+}
+
+func (s *service) FindSPUBySN(ctx context.Context, sn string) (domain.SPU, error) {
+	return s.repo.FindSPUBySN(ctx, sn)
+}
+
+func (s *service) ListSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error) {
+	return s.repo.ListSPUs(ctx, offset, limit)
+}
+
+func (s *service) ListOnShelfSPUs(ctx context.Context, offset, limit int) ([]domain.SPU, int64, error) {
+	return s.repo.ListOnShelfSPUs(ctx, offset, limit)
+}
+
+func (s *service) ReserveStock(ctx context.Context, orderSN string, uid int64, items []domain.StockItem) error {
+	if len(items) == 0 {
+		return fmt.Errorf("%w: 预留库存的商品为空", domain.ErrInvalidProduct)
+	}
+	for _, item := range items {
+		if item.Quantity < 1 {
+			return fmt.Errorf("%w: 商品数量 %d 非法", domain.ErrInvalidProduct, item.Quantity)
+		}
+	}
+	items = mergeStockItems(items)
+	skus, err := s.repo.FindSKUsByIDs(ctx, slice.Map(items, func(idx int, src domain.StockItem) int64 {
+		return src.SKUID
+	}))
+	if err != nil {
+		return err
+	}
+	now := time.Now().UnixMilli()
+	for _, sku := range skus {
+		if !sku.OnSale(now) {
+			return fmt.Errorf("%w: SKU %s", domain.ErrNotOnSale, sku.SN)
+		}
+	}
+	return s.repo.ReserveStock(ctx, orderSN, uid, items)
+}
+
+// mergeStockItems 同一个 SKU 出现多次的时候合并成一个，数量相加，保持第一次出现的顺序
+func mergeStockItems(items []domain.StockItem) []domain.StockItem {
+	res := make([]domain.StockItem, 0, len(items))
+	idx := make(map[int64]int, len(items))
+	for _, item := range items {
+		if i, ok := idx[item.SKUID]; ok {
+			res[i].Quantity += item.Quantity
+			continue
+		}
+		idx[item.SKUID] = len(res)
+		res = append(res, item)
+	}
+	return res
+}
+
+func (s *service) CommitStock(ctx context.Context, orderSN string) error {
+	return s.repo.CommitStock(ctx, orderSN)
+}
+
+func (s *service) ReleaseStock(ctx context.Context, orderSN string) error {
+	return s.repo.ReleaseStock(ctx, orderSN)
+}
+
+func (s *service) SyncSaleWindow(ctx context.Context, start, end int64) error {
+	return s.repo.SyncSaleWindow(ctx, start, end)
 }
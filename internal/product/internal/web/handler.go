@@ -15,12 +15,22 @@
 package web
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/ginx"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/product/internal/domain"
 	"github.com/ecodeclub/webook/internal/product/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
+// 分页查询一次最多返回的数量
+const maxLimit = 100
+
 type Handler struct {
 	svc service.Service
 }
@@ -29,15 +39,119 @@ func NewHandler(svc service.Service) *Handler {
 	return &Handler{svc: svc}
 }
 
+func (h *Handler) PublicRoutes(server *gin.Engine) {
+	server.POST("/product/catalog", ginx.B[Page](h.Catalog))
+}
+
 func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	g := server.Group("/product")
 	g.POST("/detail", ginx.BS[ProductSNReq](h.RetrieveProductDetail))
+	// 下面是创作者才能使用的商品管理接口
+	g.POST("/save", ginx.S(h.Permission), ginx.B[SaveSPUReq](h.Save))
+	g.POST("/list", ginx.S(h.Permission), ginx.B[Page](h.List))
+	g.POST("/spu/detail", ginx.S(h.Permission), ginx.B[ProductSNReq](h.SPUDetail))
+	g.POST("/spu/status", ginx.S(h.Permission), ginx.B[StatusReq](h.UpdateSPUStatus))
+	g.POST("/sku/status", ginx.S(h.Permission), ginx.B[StatusReq](h.UpdateSKUStatus))
+}
+
+func (h *Handler) Permission(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	if sess.Claims().Get("creator").StringOrDefault("") != "true" {
+		ctx.AbortWithStatus(http.StatusInternalServerError)
+		return ginx.Result{}, fmt.Errorf("非法访问创作中心 uid: %d", sess.Claims().Uid)
+	}
+	return ginx.Result{}, ginx.ErrNoResponse
+}
+
+func (h *Handler) Save(ctx *ginx.Context, req SaveSPUReq) (ginx.Result, error) {
+	id, err := h.svc.SaveSPU(ctx.Request.Context(), req.SPU.toDomain())
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{
+		Data: id,
+	}, nil
+}
+
+func (h *Handler) List(ctx *ginx.Context, req Page) (ginx.Result, error) {
+	req.Offset = max(req.Offset, 0)
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	spus, total, err := h.svc.ListSPUs(ctx.Request.Context(), req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toSPUList(spus, total),
+	}, nil
+}
+
+func (h *Handler) Catalog(ctx *ginx.Context, req Page) (ginx.Result, error) {
+	req.Offset = max(req.Offset, 0)
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	spus, total, err := h.svc.ListOnShelfSPUs(ctx.Request.Context(), req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toSPUList(spus, total),
+	}, nil
+}
+
+func (h *Handler) SPUDetail(ctx *ginx.Context, req ProductSNReq) (ginx.Result, error) {
+	spu, err := h.svc.FindSPUBySN(ctx.Request.Context(), req.SN)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: newSPU(spu),
+	}, nil
+}
+
+func (h *Handler) UpdateSPUStatus(ctx *ginx.Context, req StatusReq) (ginx.Result, error) {
+	err := h.svc.UpdateSPUStatus(ctx.Request.Context(), req.SN, req.Status)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *Handler) UpdateSKUStatus(ctx *ginx.Context, req StatusReq) (ginx.Result, error) {
+	err := h.svc.UpdateSKUStatus(ctx.Request.Context(), req.SN, req.Status)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{Msg: "OK"}, nil
+}
+
+func (h *Handler) errorResult(err error) ginx.Result {
+	switch {
+	case errors.Is(err, domain.ErrInvalidProduct):
+		return invalidProductResult
+	case errors.Is(err, service.ErrDuplicateSN):
+		return duplicateSNResult
+	case errors.Is(err, domain.ErrNotOnSale):
+		return notOnSaleResult
+	default:
+		return systemErrorResult
+	}
+}
+
+func (h *Handler) toSPUList(spus []domain.SPU, total int64) SPUList {
+	return SPUList{
+		Total: total,
+		SPUs: slice.Map(spus, func(idx int, src domain.SPU) SPU {
+			return newSPU(src)
+		}),
+	}
 }
 
 func (h *Handler) RetrieveProductDetail(ctx *ginx.Context, req ProductSNReq, _ session.Session) (ginx.Result, error) {
 	p, err := h.svc.FindBySN(ctx.Request.Context(), req.SN)
 	if err != nil {
-		return systemErrorResult, err
+		return h.errorResult(err), err
 	}
 	return ginx.Result{
 		Data: Product{
@@ -47,13 +161,18 @@ func (h *Handler) RetrieveProductDetail(ctx *ginx.Context, req ProductSNReq, _ s
 				Desc: p.SPU.Desc,
 			},
 			SKU: ProductSKU{
-				SN:         p.SKU.SN,
-				Name:       p.SKU.Name,
-				Desc:       p.SKU.Desc,
-				Price:      p.SKU.Price,
-				Stock:      p.SKU.Stock,
-				StockLimit: p.SKU.StockLimit,
-				SaleType:   p.SKU.SaleType,
+				SN:             p.SKU.SN,
+				Name:           p.SKU.Name,
+				Desc:           p.SKU.Desc,
+				Price:          p.SKU.Price,
+				Stock:          p.SKU.Stock,
+				StockLimit:     p.SKU.StockLimit,
+				SaleType:       p.SKU.SaleType,
+				SaleStart:      p.SKU.SaleStart,
+				SaleEnd:        p.SKU.SaleEnd,
+				PromotionPrice: p.SKU.PromotionPrice,
+				ReleaseAt:      p.SKU.ReleaseAt,
+				RealPrice:      p.SKU.RealPrice(time.Now().UnixMilli()),
 			},
 		},
 	}, nil
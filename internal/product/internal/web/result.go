@@ -24,4 +24,16 @@ var (
 		Code: errs.SystemError.Code,
 		Msg:  errs.SystemError.Msg,
 	}
+	invalidProductResult = ginx.Result{
+		Code: errs.InvalidProduct.Code,
+		Msg:  errs.InvalidProduct.Msg,
+	}
+	duplicateSNResult = ginx.Result{
+		Code: errs.DuplicateSN.Code,
+		Msg:  errs.DuplicateSN.Msg,
+	}
+	notOnSaleResult = ginx.Result{
+		Code: errs.NotOnSale.Code,
+		Msg:  errs.NotOnSale.Msg,
+	}
 )
@@ -14,6 +14,11 @@
 
 package web
 
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/product/internal/domain"
+)
+
 type ProductSNReq struct {
 	SN string `json:"sn"`
 }
@@ -30,13 +35,122 @@ type ProductSPU struct {
 }
 
 type ProductSKU struct {
-	SN         string `json:"sn"`
-	Name       string `json:"name"`
-	Desc       string `json:"desc"`
-	Price      int64  `json:"price"`
-	Stock      int64  `json:"stock"`
-	StockLimit int64  `json:"stockLimit"`
-	SaleType   int64  `json:"saleType"`
-	// SaleStart  int64  `json:"saleStart"`
-	// SaleEnd    int64  `json:"saleEnd"`
+	SN             string `json:"sn"`
+	Name           string `json:"name"`
+	Desc           string `json:"desc"`
+	Price          int64  `json:"price"`
+	Stock          int64  `json:"stock"`
+	StockLimit     int64  `json:"stockLimit"`
+	SaleType       int64  `json:"saleType"`
+	SaleStart      int64  `json:"saleStart,omitempty"`
+	SaleEnd        int64  `json:"saleEnd,omitempty"`
+	PromotionPrice int64  `json:"promotionPrice,omitempty"`
+	ReleaseAt      int64  `json:"releaseAt,omitempty"`
+	// RealPrice 当前的实际售价
+	RealPrice int64 `json:"realPrice"`
+}
+
+type Page struct {
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+}
+
+type SaveSPUReq struct {
+	SPU SPU `json:"spu"`
+}
+
+type StatusReq struct {
+	SN     string `json:"sn"`
+	Status int64  `json:"status"`
+}
+
+// SPU 带有 SKU 的完整商品信息
+type SPU struct {
+	ID     int64  `json:"id,omitempty"`
+	SN     string `json:"sn"`
+	Name   string `json:"name"`
+	Desc   string `json:"desc"`
+	Status int64  `json:"status,omitempty"`
+	SKUs   []SKU  `json:"skus,omitempty"`
+}
+
+func newSPU(spu domain.SPU) SPU {
+	return SPU{
+		ID:     spu.ID,
+		SN:     spu.SN,
+		Name:   spu.Name,
+		Desc:   spu.Desc,
+		Status: spu.Status,
+		SKUs: slice.Map(spu.SKUs, func(idx int, src domain.SKU) SKU {
+			return newSKU(src)
+		}),
+	}
+}
+
+func (s SPU) toDomain() domain.SPU {
+	return domain.SPU{
+		ID:   s.ID,
+		SN:   s.SN,
+		Name: s.Name,
+		Desc: s.Desc,
+		SKUs: slice.Map(s.SKUs, func(idx int, src SKU) domain.SKU {
+			return src.toDomain()
+		}),
+	}
+}
+
+type SKU struct {
+	ID             int64  `json:"id,omitempty"`
+	SN             string `json:"sn"`
+	Name           string `json:"name"`
+	Desc           string `json:"desc"`
+	Price          int64  `json:"price"`
+	Stock          int64  `json:"stock"`
+	StockLimit     int64  `json:"stockLimit"`
+	SaleType       int64  `json:"saleType"`
+	SaleStart      int64  `json:"saleStart,omitempty"`
+	SaleEnd        int64  `json:"saleEnd,omitempty"`
+	PromotionPrice int64  `json:"promotionPrice,omitempty"`
+	ReleaseAt      int64  `json:"releaseAt,omitempty"`
+	Status         int64  `json:"status,omitempty"`
+}
+
+func newSKU(sku domain.SKU) SKU {
+	return SKU{
+		ID:             sku.ID,
+		SN:             sku.SN,
+		Name:           sku.Name,
+		Desc:           sku.Desc,
+		Price:          sku.Price,
+		Stock:          sku.Stock,
+		StockLimit:     sku.StockLimit,
+		SaleType:       sku.SaleType,
+		SaleStart:      sku.SaleStart,
+		SaleEnd:        sku.SaleEnd,
+		PromotionPrice: sku.PromotionPrice,
+		ReleaseAt:      sku.ReleaseAt,
+		Status:         sku.Status,
+	}
+}
+
+func (s SKU) toDomain() domain.SKU {
+	return domain.SKU{
+		ID:             s.ID,
+		SN:             s.SN,
+		Name:           s.Name,
+		Desc:           s.Desc,
+		Price:          s.Price,
+		Stock:          s.Stock,
+		StockLimit:     s.StockLimit,
+		SaleType:       s.SaleType,
+		SaleStart:      s.SaleStart,
+		SaleEnd:        s.SaleEnd,
+		PromotionPrice: s.PromotionPrice,
+		ReleaseAt:      s.ReleaseAt,
+	}
+}
+
+type SPUList struct {
+	Total int64 `json:"total,omitempty"`
+	SPUs  []SPU `json:"spus,omitempty"`
 }
@@ -18,9 +18,16 @@ package product
 
 import (
 	"sync"
+	"time"
+
+	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/ecache/memory/lru"
+	"github.com/redis/go-redis/v9"
 
 	"github.com/ecodeclub/webook/internal/product/internal/domain"
+	"github.com/ecodeclub/webook/internal/product/internal/job"
 	"github.com/ecodeclub/webook/internal/product/internal/repository"
+	"github.com/ecodeclub/webook/internal/product/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/product/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/product/internal/service"
 	"github.com/ecodeclub/webook/internal/product/internal/web"
@@ -30,6 +37,9 @@ import (
 
 var ServiceSet = wire.NewSet(
 	InitTablesOnce,
+	dao.NewStockGORMDAO,
+	initLocalCache,
+	cache.NewProductCache,
 	repository.NewProductRepository,
 	service.NewService)
 
@@ -37,17 +47,33 @@ var HandlerSet = wire.NewSet(
 	InitService,
 	web.NewHandler)
 
-func InitHandler(db *egorm.Component) *Handler {
+func InitHandler(db *egorm.Component, cmd redis.Cmdable) *Handler {
 	wire.Build(HandlerSet)
 	return new(Handler)
 }
 
-func InitService(db *egorm.Component) Service {
+func InitService(db *egorm.Component, cmd redis.Cmdable) Service {
 	wire.Build(ServiceSet)
 	return nil
 }
 
-var once = &sync.Once{}
+func InitSaleWindowJob(db *egorm.Component, cmd redis.Cmdable) *SaleWindowJob {
+	return job.NewSaleWindowJob(InitService(db, cmd), time.Minute, time.Minute)
+}
+
+var (
+	once       = &sync.Once{}
+	localOnce  = &sync.Once{}
+	localCache ecache.Cache
+)
+
+// initLocalCache 同一个进程内共享本地缓存，保证失效的时候所有的 Service 都能看到
+func initLocalCache() ecache.Cache {
+	localOnce.Do(func() {
+		localCache = lru.NewCache(10000)
+	})
+	return localCache
+}
 
 func InitTablesOnce(db *egorm.Component) dao.ProductDAO {
 	once.Do(func() {
@@ -59,7 +85,16 @@ func InitTablesOnce(db *egorm.Component) dao.ProductDAO {
 type Handler = web.Handler
 
 type Service = service.Service
+type SaleWindowJob = job.SaleWindowJob
 
 type Product = domain.Product
 type SKU = domain.SKU
 type SPU = domain.SPU
+type StockItem = domain.StockItem
+
+var (
+	ErrInsufficientStock = domain.ErrInsufficientStock
+	ErrExceedStockLimit  = domain.ErrExceedStockLimit
+	ErrNotOnSale         = domain.ErrNotOnSale
+	ErrNotFound          = domain.ErrNotFound
+)
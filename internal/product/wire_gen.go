@@ -8,28 +8,37 @@ package product
 
 import (
 	"sync"
+	"time"
 
+	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/ecache/memory/lru"
 	"github.com/ecodeclub/webook/internal/product/internal/domain"
+	"github.com/ecodeclub/webook/internal/product/internal/job"
 	"github.com/ecodeclub/webook/internal/product/internal/repository"
+	"github.com/ecodeclub/webook/internal/product/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/product/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/product/internal/service"
 	"github.com/ecodeclub/webook/internal/product/internal/web"
 	"github.com/ego-component/egorm"
 	"github.com/google/wire"
+	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
 )
 
 // Injectors from wire.go:
 
-func InitHandler(db *gorm.DB) *web.Handler {
-	service := InitService(db)
+func InitHandler(db *gorm.DB, cmd redis.Cmdable) *web.Handler {
+	service := InitService(db, cmd)
 	handler := web.NewHandler(service)
 	return handler
 }
 
-func InitService(db *gorm.DB) service.Service {
+func InitService(db *gorm.DB, cmd redis.Cmdable) service.Service {
 	productDAO := InitTablesOnce(db)
-	productRepository := repository.NewProductRepository(productDAO)
+	stockDAO := dao.NewStockGORMDAO(db)
+	ecacheCache := initLocalCache()
+	productCache := cache.NewProductCache(ecacheCache, cmd)
+	productRepository := repository.NewProductRepository(productDAO, stockDAO, productCache)
 	serviceService := service.NewService(productRepository)
 	return serviceService
 }
@@ -37,14 +46,30 @@ func InitService(db *gorm.DB) service.Service {
 // wire.go:
 
 var ServiceSet = wire.NewSet(
-	InitTablesOnce, repository.NewProductRepository, service.NewService,
+	InitTablesOnce, dao.NewStockGORMDAO, initLocalCache, cache.NewProductCache, repository.NewProductRepository, service.NewService,
 )
 
 var HandlerSet = wire.NewSet(
 	InitService, web.NewHandler,
 )
 
-var once = &sync.Once{}
+func InitSaleWindowJob(db *egorm.Component, cmd redis.Cmdable) *SaleWindowJob {
+	return job.NewSaleWindowJob(InitService(db, cmd), time.Minute, time.Minute)
+}
+
+var (
+	once       = &sync.Once{}
+	localOnce  = &sync.Once{}
+	localCache ecache.Cache
+)
+
+// initLocalCache 同一个进程内共享本地缓存，保证失效的时候所有的 Service 都能看到
+func initLocalCache() ecache.Cache {
+	localOnce.Do(func() {
+		localCache = lru.NewCache(10000)
+	})
+	return localCache
+}
 
 func InitTablesOnce(db *egorm.Component) dao.ProductDAO {
 	once.Do(func() {
@@ -57,8 +82,19 @@ type Handler = web.Handler
 
 type Service = service.Service
 
+type SaleWindowJob = job.SaleWindowJob
+
 type Product = domain.Product
 
 type SKU = domain.SKU
 
 type SPU = domain.SPU
+
+type StockItem = domain.StockItem
+
+var (
+	ErrInsufficientStock = domain.ErrInsufficientStock
+	ErrExceedStockLimit  = domain.ErrExceedStockLimit
+	ErrNotOnSale         = domain.ErrNotOnSale
+	ErrNotFound          = domain.ErrNotFound
+)
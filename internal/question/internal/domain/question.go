@@ -26,9 +26,25 @@ type Question struct {
 	Content string
 
 	Answer Answer
-	Utime  time.Time
+
+	Status uint8
+	// 审核人
+	Reviewer int64
+	// 最近一次的审核意见
+	ReviewComment string
+
+	Utime time.Time
 }
 
+const (
+	QuestionStatusDraft         = iota + 1 // 草稿
+	QuestionStatusPendingReview            // 待审核
+	QuestionStatusApproved                 // 审核通过
+	QuestionStatusRejected                 // 审核拒绝
+	QuestionStatusPublished                // 已发布
+	QuestionStatusUnpublished              // 已下架
+)
+
 type Answer struct {
 	Analysis AnswerElement
 	// 基本回答
@@ -30,3 +30,12 @@ type QuestionSet struct {
 
 	Utime time.Time
 }
+
+// Qids 按照题集中的顺序返回题目 ID
+func (set QuestionSet) Qids() []int64 {
+	ids := make([]int64, len(set.Questions))
+	for i, q := range set.Questions {
+		ids[i] = q.Id
+	}
+	return ids
+}
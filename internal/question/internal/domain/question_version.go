@@ -0,0 +1,69 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// QuestionVersion 线上库的一个快照，不可修改
+type QuestionVersion struct {
+	Version int64
+	// 回滚产生的版本会记录回滚到的版本，正常发布为 0
+	BaseVersion int64
+	// 发布人
+	Uid      int64
+	Question Question
+	Ctime    time.Time
+}
+
+// FieldDiff 两个版本之间某个字段的差异
+// Field 形如 title、content、labels、basic.keywords
+type FieldDiff struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// Diff 逐个字段比较 q 和 other，只返回有差异的字段
+// q 被认为是旧的版本，other 是新的版本
+func (q Question) Diff(other Question) []FieldDiff {
+	res := make([]FieldDiff, 0, 8)
+	res = appendDiff(res, "title", q.Title, other.Title)
+	res = appendDiff(res, "content", q.Content, other.Content)
+	res = appendDiff(res, "labels", strings.Join(q.Labels, ","), strings.Join(other.Labels, ","))
+	res = q.Answer.Analysis.diff(res, "analysis", other.Answer.Analysis)
+	res = q.Answer.Basic.diff(res, "basic", other.Answer.Basic)
+	res = q.Answer.Intermediate.diff(res, "intermediate", other.Answer.Intermediate)
+	res = q.Answer.Advanced.diff(res, "advanced", other.Answer.Advanced)
+	return res
+}
+
+func (ele AnswerElement) diff(res []FieldDiff, prefix string, other AnswerElement) []FieldDiff {
+	res = appendDiff(res, prefix+".content", ele.Content, other.Content)
+	res = appendDiff(res, prefix+".keywords", ele.Keywords, other.Keywords)
+	res = appendDiff(res, prefix+".shorthand", ele.Shorthand, other.Shorthand)
+	res = appendDiff(res, prefix+".highlight", ele.Highlight, other.Highlight)
+	res = appendDiff(res, prefix+".guidance", ele.Guidance, other.Guidance)
+	return res
+}
+
+func appendDiff(res []FieldDiff, field, old, new string) []FieldDiff {
+	if old == new {
+		return res
+	}
+	return append(res, FieldDiff{Field: field, Old: old, New: new})
+}
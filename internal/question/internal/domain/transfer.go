@@ -0,0 +1,24 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// ImportResult 批量导入时单道题目的结果
+type ImportResult struct {
+	// 题目的来源，文件名或者 文件名[下标]
+	Name string
+	// 创建或者更新之后的问题 ID，失败的时候为 0
+	Id  int64
+	Err error
+}
@@ -1,7 +1,16 @@
 package errs
 
 var (
-	SystemError = ErrorCode{Code: 502001, Msg: "系统错误"}
+	SystemError         = ErrorCode{Code: 502001, Msg: "系统错误"}
+	VersionNotFound     = ErrorCode{Code: 502002, Msg: "问题版本不存在"}
+	InvalidStatus       = ErrorCode{Code: 502003, Msg: "问题当前状态不允许该操作"}
+	InvalidFile         = ErrorCode{Code: 502004, Msg: "导入导出的文件格式非法"}
+	QuestionNotFound    = ErrorCode{Code: 502005, Msg: "题目不存在或者未发布"}
+	QuestionInSet       = ErrorCode{Code: 502006, Msg: "题目还在题集中，请先从题集中移除"}
+	QuestionInSkill     = ErrorCode{Code: 502007, Msg: "题目还被技能引用，请先解除引用"}
+	InvalidReviewer     = ErrorCode{Code: 502008, Msg: "审核人非法，不能指定自己审核"}
+	QuestionSetNotFound = ErrorCode{Code: 502009, Msg: "题集不存在或者当前状态不允许该操作"}
+	InvalidSetQuestion  = ErrorCode{Code: 502010, Msg: "题目不存在或者已经删除，不能加入题集"}
 )
 
 type ErrorCode struct {
@@ -0,0 +1,266 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/question/internal/domain"
+)
+
+const (
+	Markdown = "md"
+	JSON     = "json"
+
+	// 单个文件的上限，避免 zip 炸弹
+	maxFileSize = 1 << 20
+	// zip 里面最多的文件数
+	maxZipFiles = 1000
+	// zip 解压之后全部文件加起来的上限
+	maxZipSize = 16 << 20
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("不支持的文件格式")
+	ErrInvalidFile       = errors.New("文件内容非法")
+)
+
+// Entry 导入文件中的一道题。解析失败的题目 Err 不为 nil，不影响其它题目
+type Entry struct {
+	// 题目的来源，文件名或者 文件名[下标]
+	Name     string
+	Question domain.Question
+	Err      error
+}
+
+// Question 导入导出的格式。Markdown 里面题目内容是正文，其余部分在 front-matter 里面
+type Question struct {
+	// 有 id 的时候更新，否则创建
+	Id     int64    `yaml:"id,omitempty" json:"id,omitempty"`
+	Title  string   `yaml:"title" json:"title"`
+	Labels []string `yaml:"labels,omitempty" json:"labels,omitempty"`
+
+	Content string `yaml:"-" json:"content"`
+
+	Analysis     AnswerElement `yaml:"analysis" json:"analysis"`
+	Basic        AnswerElement `yaml:"basic" json:"basic"`
+	Intermediate AnswerElement `yaml:"intermediate" json:"intermediate"`
+	Advanced     AnswerElement `yaml:"advanced" json:"advanced"`
+}
+
+type AnswerElement struct {
+	Content   string `yaml:"content,omitempty" json:"content,omitempty"`
+	Keywords  string `yaml:"keywords,omitempty" json:"keywords,omitempty"`
+	Shorthand string `yaml:"shorthand,omitempty" json:"shorthand,omitempty"`
+	Highlight string `yaml:"highlight,omitempty" json:"highlight,omitempty"`
+	Guidance  string `yaml:"guidance,omitempty" json:"guidance,omitempty"`
+}
+
+// Decode 按照扩展名解析 .md、.json 或者 .zip 文件
+func Decode(name string, data []byte) ([]Entry, error) {
+	if strings.ToLower(path.Ext(name)) == ".zip" {
+		return decodeZip(data)
+	}
+	return decodeFile(name, data)
+}
+
+func decodeFile(name string, data []byte) ([]Entry, error) {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".md", ".markdown":
+		q, err := decodeMarkdown(data)
+		if err == nil {
+			err = q.validate()
+		}
+		return []Entry{{Name: name, Question: q.toDomain(), Err: err}}, nil
+	case ".json":
+		var qs []Question
+		if err := json.Unmarshal(data, &qs); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+		}
+		return slice.Map(qs, func(idx int, src Question) Entry {
+			return Entry{
+				Name:     fmt.Sprintf("%s[%d]", name, idx),
+				Question: src.toDomain(),
+				Err:      src.validate(),
+			}
+		}), nil
+	default:
+		return nil, fmt.Errorf("%w %s", ErrUnsupportedFormat, name)
+	}
+}
+
+func decodeZip(data []byte) ([]Entry, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	if len(r.File) > maxZipFiles {
+		return nil, fmt.Errorf("%w: 文件数量超过 %d", ErrInvalidFile, maxZipFiles)
+	}
+	var declared uint64
+	for _, f := range r.File {
+		declared += f.UncompressedSize64
+	}
+	if declared > maxZipSize {
+		return nil, fmt.Errorf("%w: 解压之后超过 %d 字节", ErrInvalidFile, maxZipSize)
+	}
+	res := make([]Entry, 0, len(r.File))
+	// 头部记录的大小可以伪造，所以还要按照实际读出来的字节数计算
+	var total int
+	for _, f := range r.File {
+		// 跳过目录和 macOS 打包时附带的元数据
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") {
+			continue
+		}
+		content, err := readZipFile(f)
+		total += len(content)
+		if total > maxZipSize {
+			return nil, fmt.Errorf("%w: 解压之后超过 %d 字节", ErrInvalidFile, maxZipSize)
+		}
+		if err != nil {
+			res = append(res, Entry{Name: f.Name, Err: err})
+			continue
+		}
+		entries, err := decodeFile(f.Name, content)
+		if err != nil {
+			res = append(res, Entry{Name: f.Name, Err: err})
+			continue
+		}
+		res = append(res, entries...)
+	}
+	return res, nil
+}
+
+// readZipFile 最多读取 maxFileSize+1 个字节，超过 maxFileSize 的文件返回错误
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > maxFileSize {
+		return nil, fmt.Errorf("%w: 文件超过 %d 字节", ErrInvalidFile, maxFileSize)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(io.LimitReader(rc, maxFileSize+1))
+	if err != nil {
+		return data, err
+	}
+	if len(data) > maxFileSize {
+		return data, fmt.Errorf("%w: 文件超过 %d 字节", ErrInvalidFile, maxFileSize)
+	}
+	return data, nil
+}
+
+// Encode 导出题目，Markdown 格式下每道题一个文件，打包成 zip
+func Encode(format string, ques []domain.Question) (string, []byte, error) {
+	qs := slice.Map(ques, func(idx int, src domain.Question) Question {
+		return newQuestion(src)
+	})
+	switch format {
+	case JSON:
+		data, err := json.MarshalIndent(qs, "", "  ")
+		return "questions.json", data, err
+	case Markdown:
+		data, err := encodeZip(qs)
+		return "questions.zip", data, err
+	default:
+		return "", nil, fmt.Errorf("%w %s", ErrUnsupportedFormat, format)
+	}
+}
+
+func encodeZip(qs []Question) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, q := range qs {
+		data, err := encodeMarkdown(q)
+		if err != nil {
+			return nil, err
+		}
+		f, err := w.Create(fmt.Sprintf("%d.md", q.Id))
+		if err != nil {
+			return nil, err
+		}
+		if _, err = f.Write(data); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (q Question) validate() error {
+	if strings.TrimSpace(q.Title) == "" {
+		return fmt.Errorf("%w: 标题不能为空", ErrInvalidFile)
+	}
+	return nil
+}
+
+func (q Question) toDomain() domain.Question {
+	return domain.Question{
+		Id:      q.Id,
+		Title:   q.Title,
+		Labels:  q.Labels,
+		Content: q.Content,
+		Answer: domain.Answer{
+			Analysis:     q.Analysis.toDomain(),
+			Basic:        q.Basic.toDomain(),
+			Intermediate: q.Intermediate.toDomain(),
+			Advanced:     q.Advanced.toDomain(),
+		},
+	}
+}
+
+func newQuestion(q domain.Question) Question {
+	return Question{
+		Id:           q.Id,
+		Title:        q.Title,
+		Labels:       q.Labels,
+		Content:      q.Content,
+		Analysis:     newAnswerElement(q.Answer.Analysis),
+		Basic:        newAnswerElement(q.Answer.Basic),
+		Intermediate: newAnswerElement(q.Answer.Intermediate),
+		Advanced:     newAnswerElement(q.Answer.Advanced),
+	}
+}
+
+func (ele AnswerElement) toDomain() domain.AnswerElement {
+	return domain.AnswerElement{
+		Content:   ele.Content,
+		Keywords:  ele.Keywords,
+		Shorthand: ele.Shorthand,
+		Highlight: ele.Highlight,
+		Guidance:  ele.Guidance,
+	}
+}
+
+func newAnswerElement(ele domain.AnswerElement) AnswerElement {
+	return AnswerElement{
+		Content:   ele.Content,
+		Keywords:  ele.Keywords,
+		Shorthand: ele.Shorthand,
+		Highlight: ele.Highlight,
+		Guidance:  ele.Guidance,
+	}
+}
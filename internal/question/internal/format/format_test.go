@@ -0,0 +1,221 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ecodeclub/webook/internal/question/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	testCases := []struct {
+		name     string
+		filename string
+		data     []byte
+		want     []Entry
+		wantErr  error
+	}{
+		{
+			name:     "Markdown",
+			filename: "redis.md",
+			data: []byte("---\r\ntitle: Redis 为什么快\r\nlabels: [Redis]\r\nbasic:\r\n  content: 基本回答\r\n" +
+				"  keywords: 内存\r\n---\r\n\r\n题目内容\r\n"),
+			want: []Entry{
+				{
+					Name: "redis.md",
+					Question: domain.Question{
+						Title:   "Redis 为什么快",
+						Labels:  []string{"Redis"},
+						Content: "题目内容",
+						Answer: domain.Answer{
+							Basic: domain.AnswerElement{Content: "基本回答", Keywords: "内存"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "Markdown没有正文",
+			filename: "redis.md",
+			data:     []byte("---\nid: 12\ntitle: Redis\n---"),
+			want: []Entry{
+				{Name: "redis.md", Question: domain.Question{Id: 12, Title: "Redis"}},
+			},
+		},
+		{
+			name:     "Markdown缺少标题",
+			filename: "redis.md",
+			data:     []byte("---\nlabels: [Redis]\n---\n内容"),
+			want: []Entry{
+				{
+					Name:     "redis.md",
+					Question: domain.Question{Labels: []string{"Redis"}, Content: "内容"},
+					Err:      ErrInvalidFile,
+				},
+			},
+		},
+		{
+			name:     "Markdown缺少front-matter",
+			filename: "redis.md",
+			data:     []byte("# Redis"),
+			want:     []Entry{{Name: "redis.md", Err: ErrInvalidFile}},
+		},
+		{
+			name:     "JSON",
+			filename: "questions.json",
+			data:     []byte(`[{"id":3,"title":"MySQL","content":"内容","advanced":{"guidance":"引导"}},{"title":""}]`),
+			want: []Entry{
+				{
+					Name: "questions.json[0]",
+					Question: domain.Question{
+						Id:      3,
+						Title:   "MySQL",
+						Content: "内容",
+						Answer: domain.Answer{
+							Advanced: domain.AnswerElement{Guidance: "引导"},
+						},
+					},
+				},
+				{Name: "questions.json[1]", Err: ErrInvalidFile},
+			},
+		},
+		{
+			name:     "JSON格式错误",
+			filename: "questions.json",
+			data:     []byte(`{`),
+			wantErr:  ErrInvalidFile,
+		},
+		{
+			name:     "不支持的格式",
+			filename: "questions.txt",
+			wantErr:  ErrUnsupportedFormat,
+		},
+		{
+			name:     "zip",
+			filename: "questions.zip",
+			data: newZip(t,
+				[2]string{"a/", ""},
+				[2]string{"a/redis.md", "---\ntitle: Redis\n---\n内容"},
+				[2]string{"a/readme.txt", "说明"},
+			),
+			want: []Entry{
+				{Name: "a/redis.md", Question: domain.Question{Title: "Redis", Content: "内容"}},
+				{Name: "a/readme.txt", Err: ErrUnsupportedFormat},
+			},
+		},
+		{
+			name:     "zip单个文件过大",
+			filename: "questions.zip",
+			data: newZip(t,
+				[2]string{"big.md", strings.Repeat("a", maxFileSize+1)},
+				[2]string{"redis.md", "---\ntitle: Redis\n---\n内容"},
+			),
+			want: []Entry{
+				{Name: "big.md", Err: ErrInvalidFile},
+				{Name: "redis.md", Question: domain.Question{Title: "Redis", Content: "内容"}},
+			},
+		},
+		{
+			name:     "zip解压之后过大",
+			filename: "questions.zip",
+			data: func() []byte {
+				files := make([][2]string, 0, maxZipSize/maxFileSize+1)
+				for i := 0; i <= maxZipSize/maxFileSize; i++ {
+					files = append(files, [2]string{fmt.Sprintf("%d.md", i), strings.Repeat("a", maxFileSize)})
+				}
+				return newZip(t, files...)
+			}(),
+			wantErr: ErrInvalidFile,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			entries, err := Decode(tc.filename, tc.data)
+			assert.ErrorIs(t, err, tc.wantErr)
+			if err != nil {
+				return
+			}
+			require.Equal(t, len(tc.want), len(entries))
+			for i, want := range tc.want {
+				assert.Equal(t, want.Name, entries[i].Name)
+				assert.ErrorIs(t, entries[i].Err, want.Err)
+				if want.Err == nil {
+					assert.Equal(t, want.Question, entries[i].Question)
+				}
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	ques := []domain.Question{
+		{
+			Id:      1,
+			Uid:     123,
+			Title:   "Redis 为什么快",
+			Labels:  []string{"Redis"},
+			Content: "题目内容",
+			Answer: domain.Answer{
+				Analysis:     domain.AnswerElement{Id: 10, Content: "分析"},
+				Basic:        domain.AnswerElement{Content: "基本回答", Keywords: "内存"},
+				Intermediate: domain.AnswerElement{Shorthand: "速记"},
+				Advanced:     domain.AnswerElement{Highlight: "亮点", Guidance: "引导"},
+			},
+		},
+		{Id: 2, Title: "MySQL"},
+	}
+	// 导出只保留导入需要的字段
+	want := []domain.Question{ques[0], ques[1]}
+	want[0].Uid = 0
+	want[0].Answer.Analysis.Id = 0
+
+	for _, format := range []string{Markdown, JSON} {
+		t.Run(format, func(t *testing.T) {
+			name, data, err := Encode(format, ques)
+			require.NoError(t, err)
+			entries, err := Decode(name, data)
+			require.NoError(t, err)
+			require.Equal(t, len(want), len(entries))
+			for i := range want {
+				assert.NoError(t, entries[i].Err)
+				assert.Equal(t, want[i], entries[i].Question)
+			}
+		})
+	}
+
+	_, _, err := Encode("txt", ques)
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+// newZip 每个文件是 [文件名, 内容]，以 / 结尾的是目录
+func newZip(t *testing.T, files ...[2]string) []byte {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, file := range files {
+		f, err := w.Create(file[0])
+		require.NoError(t, err)
+		_, err = f.Write([]byte(file[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
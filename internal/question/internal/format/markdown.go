@@ -0,0 +1,73 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package format
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const delimiter = "---"
+
+// decodeMarkdown 解析带 front-matter 的 Markdown，形如：
+//
+//	---
+//	title: 标题
+//	labels: [MySQL]
+//	basic:
+//	  content: 基本回答
+//	---
+//	题目内容
+func decodeMarkdown(data []byte) (Question, error) {
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimPrefix(text, "\ufeff")
+	if !strings.HasPrefix(text, delimiter+"\n") {
+		return Question{}, fmt.Errorf("%w: 缺少 front-matter", ErrInvalidFile)
+	}
+	text = text[len(delimiter)+1:]
+	var front, body string
+	if idx := strings.Index(text, "\n"+delimiter+"\n"); idx >= 0 {
+		front, body = text[:idx], text[idx+len(delimiter)+2:]
+	} else if strings.HasSuffix(text, "\n"+delimiter) {
+		front = strings.TrimSuffix(text, "\n"+delimiter)
+	} else {
+		return Question{}, fmt.Errorf("%w: front-matter 没有结束", ErrInvalidFile)
+	}
+	var q Question
+	if err := yaml.Unmarshal([]byte(front), &q); err != nil {
+		return Question{}, fmt.Errorf("%w: %w", ErrInvalidFile, err)
+	}
+	q.Content = strings.TrimSpace(body)
+	return q, nil
+}
+
+func encodeMarkdown(q Question) ([]byte, error) {
+	front, err := yaml.Marshal(q)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	buf.WriteString(delimiter + "\n")
+	buf.Write(front)
+	buf.WriteString(delimiter + "\n")
+	if q.Content != "" {
+		buf.WriteString(q.Content)
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
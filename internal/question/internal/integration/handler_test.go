@@ -17,9 +17,12 @@
 package integration
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"mime/multipart"
 	"net/http"
+	"net/http/httptest"
 	"strconv"
 	"testing"
 	"time"
@@ -29,19 +32,33 @@ import (
 
 	"github.com/ecodeclub/ecache"
 	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/label"
+	labelmocks "github.com/ecodeclub/webook/internal/label/mocks"
+	"github.com/ecodeclub/webook/internal/note"
+	notemocks "github.com/ecodeclub/webook/internal/note/mocks"
+	"github.com/ecodeclub/webook/internal/practice"
+	practicemocks "github.com/ecodeclub/webook/internal/practice/mocks"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/question/internal/event"
+	evtmocks "github.com/ecodeclub/webook/internal/question/internal/event/mocks"
 	"github.com/ecodeclub/webook/internal/question/internal/integration/startup"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/question/internal/web"
+	quemocks "github.com/ecodeclub/webook/internal/question/mocks"
 	"github.com/ecodeclub/webook/internal/test"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 	"github.com/ego-component/egorm"
 	"github.com/gin-gonic/gin"
 	"github.com/gotomicro/ego/core/econf"
 	"github.com/gotomicro/ego/server/egin"
+	"github.com/gotomicro/ego/task/ejob"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+	"gorm.io/gorm"
 )
 
 const uid = 123
@@ -53,9 +70,18 @@ type HandlerTestSuite struct {
 	rdb            ecache.Cache
 	dao            dao.QuestionDAO
 	questionSetDAO dao.QuestionSetDAO
+	ctrl           *gomock.Controller
+	producer       *evtmocks.MockSyncEventProducer
+	labelSvc       *labelmocks.MockService
+	practiceSvc    *practicemocks.MockService
+	noteSvc        *notemocks.MockService
+	purgeJob       *baguwen.PurgeJob
+	reindexJob     *baguwen.ReindexJob
+	qsHdl          *baguwen.QuestionSetHandler
 }
 
 func (s *HandlerTestSuite) TearDownSuite() {
+	s.ctrl.Finish()
 	err := s.db.Exec("DROP TABLE `answer_elements`").Error
 	require.NoError(s.T(), err)
 	err = s.db.Exec("DROP TABLE `questions`").Error
@@ -65,12 +91,18 @@ func (s *HandlerTestSuite) TearDownSuite() {
 	require.NoError(s.T(), err)
 	err = s.db.Exec("DROP TABLE `publish_questions`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("DROP TABLE `question_versions`").Error
+	require.NoError(s.T(), err)
 
 	err = s.db.Exec("DROP TABLE `question_sets`").Error
 	require.NoError(s.T(), err)
 
 	err = s.db.Exec("DROP TABLE `question_set_questions`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("DROP TABLE `publish_question_sets`").Error
+	require.NoError(s.T(), err)
+	err = s.db.Exec("DROP TABLE `publish_question_set_questions`").Error
+	require.NoError(s.T(), err)
 }
 
 func (s *HandlerTestSuite) TearDownTest() {
@@ -83,18 +115,85 @@ func (s *HandlerTestSuite) TearDownTest() {
 	require.NoError(s.T(), err)
 	err = s.db.Exec("TRUNCATE TABLE `publish_questions`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `question_versions`").Error
+	require.NoError(s.T(), err)
 
 	err = s.db.Exec("TRUNCATE TABLE `question_sets`").Error
 	require.NoError(s.T(), err)
 
 	err = s.db.Exec("TRUNCATE TABLE `question_set_questions`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `publish_question_sets`").Error
+	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE TABLE `publish_question_set_questions`").Error
+	require.NoError(s.T(), err)
+
+	// 线上库的缓存
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	rdb := testioc.InitRedis()
+	keys, err := rdb.Keys(ctx, "webook:question:*").Result()
+	require.NoError(s.T(), err)
+	if len(keys) > 0 {
+		err = rdb.Del(ctx, keys...).Err()
+		require.NoError(s.T(), err)
+	}
 }
 
 func (s *HandlerTestSuite) SetupSuite() {
-	handler, err := startup.InitHandler()
+	s.ctrl = gomock.NewController(s.T())
+	s.producer = evtmocks.NewMockSyncEventProducer(s.ctrl)
+	s.labelSvc = labelmocks.NewMockService(s.ctrl)
+	s.labelSvc.EXPECT().SaveBizLabels(gomock.Any(), "question", gomock.Any(), gomock.Any()).
+		Return(nil).AnyTimes()
+	s.labelSvc.EXPECT().DeleteBizLabels(gomock.Any(), "question", gomock.Any()).
+		Return(nil).AnyTimes()
+	// 掌握情况只统计题目数量，方便断言
+	s.practiceSvc = practicemocks.NewMockService(s.ctrl)
+	s.practiceSvc.EXPECT().Progress(gomock.Any(), int64(uid), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uid int64, qids []int64) (practice.Progress, error) {
+			return practice.Progress{Total: len(qids)}, nil
+		}).AnyTimes()
+	// 只有 1 号题目有笔记
+	s.noteSvc = notemocks.NewMockService(s.ctrl)
+	s.noteSvc.EXPECT().FindByBiz(gomock.Any(), int64(uid), note.BizQuestion, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uid int64, biz string, bizId int64) ([]note.Note, error) {
+			if bizId != 1 {
+				return nil, nil
+			}
+			return []note.Note{
+				{Id: 1, Uid: uid, Biz: biz, BizId: bizId, Element: 2, Content: "我的笔记", Utime: time.UnixMilli(123)},
+			}, nil
+		}).AnyTimes()
+	// 同样只有 1 号题目有相关推荐
+	relatedSvc := quemocks.NewMockRelatedService(s.ctrl)
+	relatedSvc.EXPECT().Related(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, qid int64) ([]baguwen.Related, error) {
+			if qid != 1 {
+				return nil, nil
+			}
+			return []baguwen.Related{
+				{Biz: "question", BizId: 2, Title: "这是标题 1"},
+				{Biz: "case", BizId: 3, Title: "案例"},
+			}, nil
+		}).AnyTimes()
+	relatedSvc.EXPECT().RemoveQuestion(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	// 只有 10 号题目被技能引用了
+	refSvc := quemocks.NewMockReferenceService(s.ctrl)
+	refSvc.EXPECT().Referenced(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, qid int64) (bool, error) {
+			return qid == 10, nil
+		}).AnyTimes()
+	labelModule := &label.Module{Svc: s.labelSvc}
+	practiceModule := &practice.Module{Svc: s.practiceSvc}
+	noteModule := &note.Module{Svc: s.noteSvc}
+	handler, err := startup.InitHandler(s.producer, labelModule, practiceModule, noteModule, relatedSvc, refSvc)
+	require.NoError(s.T(), err)
+	questionSetHandler, err := startup.InitQuestionSetHandler(s.producer, labelModule, practiceModule, noteModule, relatedSvc, refSvc)
+	require.NoError(s.T(), err)
+	s.purgeJob, err = startup.InitPurgeJob(s.producer, labelModule, practiceModule, noteModule, relatedSvc, refSvc)
 	require.NoError(s.T(), err)
-	questionSetHandler, err := startup.InitQuestionSetHandler()
+	s.reindexJob, err = startup.InitReindexJob(s.producer, labelModule, practiceModule, noteModule, relatedSvc, refSvc)
 	require.NoError(s.T(), err)
 
 	econf.Set("server", map[string]any{"contextTimeout": "1s"})
@@ -102,6 +201,7 @@ func (s *HandlerTestSuite) SetupSuite() {
 
 	handler.PublicRoutes(server.Engine)
 	questionSetHandler.PublicRoutes(server.Engine)
+	s.qsHdl = questionSetHandler
 	server.Use(func(ctx *gin.Context) {
 		ctx.Set("_session", session.NewMemorySession(session.Claims{
 			Uid: uid,
@@ -155,6 +255,7 @@ func (s *HandlerTestSuite) TestSave() {
 						Valid: true,
 						Val:   []string{"MySQL"},
 					},
+					Status: dao.QuestionStatusDraft,
 				}, q)
 				assert.Equal(t, 4, len(eles))
 				wantEles := []dao.AnswerElement{
@@ -228,6 +329,7 @@ func (s *HandlerTestSuite) TestSave() {
 					Uid:     uid,
 					Title:   "面试题1",
 					Content: "新的内容",
+					Status:  dao.QuestionStatusDraft,
 				}, q)
 				assert.Equal(t, 4, len(eles))
 				analysis := eles[0]
@@ -377,21 +479,162 @@ func (s *HandlerTestSuite) TestList() {
 	require.NoError(s.T(), err)
 }
 
+func (s *HandlerTestSuite) TestPubListByLabels() {
+	data := make([]dao.PublishQuestion, 0, 4)
+	for idx := 0; idx < 4; idx++ {
+		data = append(data, dao.PublishQuestion{
+			Uid:     uid,
+			Title:   fmt.Sprintf("这是标题 %d", idx),
+			Content: fmt.Sprintf("这是解析 %d", idx),
+			Status:  dao.QuestionStatusPublished,
+		})
+	}
+	// 已经下架的不会返回
+	data[1].Status = dao.QuestionStatusUnpublished
+	err := s.db.Create(&data).Error
+	require.NoError(s.T(), err)
+
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		req    web.PubListReq
+
+		wantCode int
+		wantResp test.Result[web.QuestionList]
+	}{
+		{
+			name: "任意匹配",
+			before: func(t *testing.T) {
+				s.labelSvc.EXPECT().BizIDs(gomock.Any(), "question", []int64{1, 2}, false, 0, 10).
+					Return([]int64{4, 2, 1}, int64(3), nil)
+			},
+			req: web.PubListReq{
+				Limit:    10,
+				LabelIds: []int64{1, 2},
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.QuestionList]{
+				Data: web.QuestionList{
+					Total: 3,
+					Questions: []web.Question{
+						{
+							Id:      4,
+							Title:   "这是标题 3",
+							Content: "这是解析 3",
+							Status:  dao.QuestionStatusPublished,
+							Utime:   time.UnixMilli(0).Format(time.DateTime),
+						},
+						{
+							Id:      1,
+							Title:   "这是标题 0",
+							Content: "这是解析 0",
+							Status:  dao.QuestionStatusPublished,
+							Utime:   time.UnixMilli(0).Format(time.DateTime),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "全部匹配",
+			before: func(t *testing.T) {
+				s.labelSvc.EXPECT().BizIDs(gomock.Any(), "question", []int64{1, 2}, true, 0, 10).
+					Return([]int64{3}, int64(1), nil)
+			},
+			req: web.PubListReq{
+				Limit:    10,
+				LabelIds: []int64{1, 2},
+				MatchAll: true,
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.QuestionList]{
+				Data: web.QuestionList{
+					Total: 1,
+					Questions: []web.Question{
+						{
+							Id:      3,
+							Title:   "这是标题 2",
+							Content: "这是解析 2",
+							Status:  dao.QuestionStatusPublished,
+							Utime:   time.UnixMilli(0).Format(time.DateTime),
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "没有匹配",
+			before: func(t *testing.T) {
+				s.labelSvc.EXPECT().BizIDs(gomock.Any(), "question", []int64{3}, false, 0, 10).
+					Return(nil, int64(0), nil)
+			},
+			req: web.PubListReq{
+				Limit:    10,
+				LabelIds: []int64{3},
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.QuestionList]{
+				Data: web.QuestionList{},
+			},
+		},
+		{
+			name: "非法分页参数",
+			before: func(t *testing.T) {
+				// 负数的 offset 会被修正为 0，limit 不能超过上限
+				s.labelSvc.EXPECT().BizIDs(gomock.Any(), "question", []int64{3}, false, 0, 100).
+					Return(nil, int64(0), nil)
+			},
+			req: web.PubListReq{
+				Offset:   -10,
+				Limit:    10000,
+				LabelIds: []int64{3},
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.QuestionList]{
+				Data: web.QuestionList{},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/question/pub/list", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[web.QuestionList]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+		})
+	}
+}
+
 func (s *HandlerTestSuite) TestSync() {
 	testCases := []struct {
 		name   string
 		before func(t *testing.T)
 		after  func(t *testing.T)
-		req    web.SaveReq
+		req    web.Qid
 
 		wantCode int
 		wantResp test.Result[int64]
 	}{
 		{
-			//
-			name: "全部新建",
+			name: "首次发布",
 			before: func(t *testing.T) {
-
+				s.createQuestion(t, 1, dao.QuestionStatusApproved, "面试题1")
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, evt event.SyncSearchEvent) error {
+						assert.Equal(t, "question", evt.Biz)
+						assert.Equal(t, int64(1), evt.BizId)
+						assert.False(t, evt.Deleted)
+						assert.Equal(t, "面试题1", evt.Title)
+						assert.Equal(t, "面试题内容", evt.Content)
+						assert.Equal(t, "关键字 0 关键字 1 关键字 2 关键字 3", evt.Keywords)
+						return nil
+					})
 			},
 			after: func(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -402,141 +645,744 @@ func (s *HandlerTestSuite) TestSync() {
 					Uid:     uid,
 					Title:   "面试题1",
 					Content: "面试题内容",
+					Status:  dao.QuestionStatusPublished,
 				}, dao.Question(q))
-				assert.Equal(t, 4, len(eles))
-			},
-			req: web.SaveReq{
-				Question: web.Question{
-					Title:        "面试题1",
-					Content:      "面试题内容",
-					Analysis:     s.buildAnswerEle(0),
-					Basic:        s.buildAnswerEle(1),
-					Intermediate: s.buildAnswerEle(2),
-					Advanced:     s.buildAnswerEle(3),
-				},
+				require.Equal(t, 4, len(eles))
+				s.assertAnswerElement(t, s.buildDAOAnswerEle(1, 0, dao.AnswerElementTypeAnalysis),
+					dao.AnswerElement(eles[0]))
+
+				q2, _, err := s.dao.GetByID(ctx, 1)
+				require.NoError(t, err)
+				assert.Equal(t, uint8(dao.QuestionStatusPublished), q2.Status)
+
+				v, err := s.dao.GetVersion(ctx, 1, 1)
+				require.NoError(t, err)
+				assert.Equal(t, "面试题1", v.Title)
+				assert.Equal(t, int64(uid), v.Uid)
+				assert.Equal(t, 4, len(v.Elements.Val))
 			},
+			req:      web.Qid{Qid: 1},
 			wantCode: 200,
 			wantResp: test.Result[int64]{
 				Data: 1,
 			},
 		},
 		{
-			//
-			name: "部分更新",
+			name: "更新线上库",
 			before: func(t *testing.T) {
+				s.createQuestion(t, 2, dao.QuestionStatusApproved, "新的标题")
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
 				defer cancel()
-				err := s.db.WithContext(ctx).Create(&dao.Question{
+				err := s.db.WithContext(ctx).Create(&dao.PublishQuestion{
 					Id:      2,
 					Uid:     uid,
 					Title:   "老的标题",
 					Content: "老的内容",
+					Status:  dao.QuestionStatusPublished,
 					Ctime:   123,
 					Utime:   234,
 				}).Error
 				require.NoError(t, err)
-				err = s.db.Create(&dao.AnswerElement{
-					Id:        1,
-					Qid:       2,
-					Type:      dao.AnswerElementTypeAnalysis,
-					Content:   "老的分析",
-					Keywords:  "老的 keyword",
-					Shorthand: "老的速记",
-					Highlight: "老的亮点",
-					Guidance:  "老的引导点",
-					Ctime:     123,
-					Utime:     123,
+				err = s.db.WithContext(ctx).Create(&dao.PublishAnswerElement{
+					Qid:     2,
+					Type:    dao.AnswerElementTypeAnalysis,
+					Content: "老的分析",
+					Ctime:   123,
+					Utime:   123,
 				}).Error
 				require.NoError(t, err)
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				q, eles, err := s.dao.GetPubByID(ctx, 2)
+				require.NoError(t, err)
+				s.assertQuestion(t, dao.Question{
+					Uid:     uid,
+					Title:   "新的标题",
+					Content: "面试题内容",
+					Status:  dao.QuestionStatusPublished,
+				}, dao.Question(q))
+				require.Equal(t, 4, len(eles))
+				s.assertAnswerElement(t, s.buildDAOAnswerEle(2, 0, dao.AnswerElementTypeAnalysis),
+					dao.AnswerElement(eles[0]))
+			},
+			req:      web.Qid{Qid: 2},
+			wantCode: 200,
+			wantResp: test.Result[int64]{
+				Data: 2,
+			},
+		},
+		{
+			name: "没有审核通过",
+			before: func(t *testing.T) {
+				s.createQuestion(t, 3, dao.QuestionStatusPendingReview, "面试题3")
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, _, err := s.dao.GetPubByID(ctx, 3)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+			},
+			req:      web.Qid{Qid: 3},
+			wantCode: 500,
+			wantResp: test.Result[int64]{
+				Code: 502003,
+				Msg:  "问题当前状态不允许该操作",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/question/publish", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[int64]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+			tc.after(t)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestReviewWorkflow() {
+	// 审核人不能是提交人自己
+	const reviewer = uid + 1
+	s.createQuestion(s.T(), 1, dao.QuestionStatusDraft, "面试题1")
+
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		path   string
+		req    any
+		// 0 表示成功
+		wantErrCode int
+		// 制作库的状态
+		wantStatus uint8
+	}{
+		{
+			name:        "草稿不能发布",
+			path:        "/question/publish",
+			req:         web.Qid{Qid: 1},
+			wantErrCode: 502003,
+			wantStatus:  dao.QuestionStatusDraft,
+		},
+		{
+			name:        "审核人不能是自己",
+			path:        "/question/submit",
+			req:         web.SubmitReq{Qid: 1, Reviewer: uid},
+			wantErrCode: 502008,
+			wantStatus:  dao.QuestionStatusDraft,
+		},
+		{
+			name:        "没有指定审核人",
+			path:        "/question/submit",
+			req:         web.SubmitReq{Qid: 1, Reviewer: 0},
+			wantErrCode: 502008,
+			wantStatus:  dao.QuestionStatusDraft,
+		},
+		{
+			name:       "提交审核",
+			path:       "/question/submit",
+			req:        web.SubmitReq{Qid: 1, Reviewer: reviewer},
+			wantStatus: dao.QuestionStatusPendingReview,
+		},
+		{
+			name:        "重复提交审核",
+			path:        "/question/submit",
+			req:         web.SubmitReq{Qid: 1, Reviewer: reviewer},
+			wantErrCode: 502003,
+			wantStatus:  dao.QuestionStatusPendingReview,
+		},
+		{
+			name: "审核拒绝",
+			before: func(t *testing.T) {
+				s.assignReviewer(t, 1, uid)
+			},
+			path:       "/question/review",
+			req:        web.ReviewReq{Qid: 1, Comment: "答案不够详细"},
+			wantStatus: dao.QuestionStatusRejected,
+		},
+		{
+			name:       "再次提交审核，但是指定了别的审核人",
+			path:       "/question/submit",
+			req:        web.SubmitReq{Qid: 1, Reviewer: reviewer + 1},
+			wantStatus: dao.QuestionStatusPendingReview,
+		},
+		{
+			name:        "不是指定的审核人",
+			path:        "/question/review",
+			req:         web.ReviewReq{Qid: 1, Approved: true},
+			wantErrCode: 502003,
+			wantStatus:  dao.QuestionStatusPendingReview,
+		},
+		{
+			name: "审核通过",
+			before: func(t *testing.T) {
+				s.assignReviewer(t, 1, uid)
+			},
+			path:       "/question/review",
+			req:        web.ReviewReq{Qid: 1, Approved: true, Comment: "可以了"},
+			wantStatus: dao.QuestionStatusApproved,
+		},
+		{
+			name: "发布",
+			before: func(t *testing.T) {
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			path:       "/question/publish",
+			req:        web.Qid{Qid: 1},
+			wantStatus: dao.QuestionStatusPublished,
+		},
+		{
+			name: "下架",
+			before: func(t *testing.T) {
+				s.producer.EXPECT().Produce(gomock.Any(), event.SyncSearchEvent{
+					Biz:     "question",
+					BizId:   1,
+					Deleted: true,
+				}).Return(nil)
+			},
+			path:       "/question/unpublish",
+			req:        web.Qid{Qid: 1},
+			wantStatus: dao.QuestionStatusUnpublished,
+		},
+		{
+			name:        "重复下架",
+			path:        "/question/unpublish",
+			req:         web.Qid{Qid: 1},
+			wantErrCode: 502003,
+			wantStatus:  dao.QuestionStatusUnpublished,
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			if tc.before != nil {
+				tc.before(t)
+			}
+			req, err := http.NewRequest(http.MethodPost, tc.path, iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[any]()
+			s.server.ServeHTTP(recorder, req)
+			if tc.wantErrCode != 0 {
+				require.Equal(t, 500, recorder.Code)
+				assert.Equal(t, tc.wantErrCode, recorder.MustScan().Code)
+			} else {
+				require.Equal(t, 200, recorder.Code)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+			defer cancel()
+			q, _, err := s.dao.GetByID(ctx, 1)
+			require.NoError(t, err)
+			assert.Equal(t, tc.wantStatus, q.Status)
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	q, _, err := s.dao.GetByID(ctx, 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "可以了", q.ReviewComment)
+
+	// 下架之后线上库的数据还在，但是查询不到了
+	var pub dao.PublishQuestion
+	err = s.db.WithContext(ctx).Where("id = ?", 1).First(&pub).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint8(dao.QuestionStatusUnpublished), pub.Status)
+	_, _, err = s.dao.GetPubByID(ctx, 1)
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	cnt, err := s.dao.PubCount(ctx)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+}
+
+// assignReviewer 模拟把审核任务重新分配给当前登录的用户
+func (s *HandlerTestSuite) assignReviewer(t *testing.T, id int64, reviewer int64) {
+	err := s.db.Model(&dao.Question{}).Where("id = ?", id).
+		Update("reviewer", reviewer).Error
+	require.NoError(t, err)
+}
+
+func (s *HandlerTestSuite) TestDelete() {
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		after  func(t *testing.T)
+		qid    int64
+
+		wantCode int
+		wantResp test.Result[any]
+	}{
+		{
+			name: "删除已发布的问题",
+			before: func(t *testing.T) {
+				s.createQuestion(t, 1, dao.QuestionStatusPublished, "已发布")
+				err := s.db.Create(&dao.PublishQuestion{Id: 1, Uid: uid, Title: "已发布",
+					Status: dao.QuestionStatusPublished}).Error
+				require.NoError(t, err)
+				s.producer.EXPECT().Produce(gomock.Any(), event.SyncSearchEvent{
+					Biz:     "question",
+					BizId:   1,
+					Deleted: true,
+				}).Return(nil)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, _, err := s.dao.GetByID(ctx, 1)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+				var q dao.Question
+				err = s.db.WithContext(ctx).Where("id = ?", 1).First(&q).Error
+				require.NoError(t, err)
+				assert.True(t, q.Dtime > 0)
+				// 线上库直接删掉
+				_, _, err = s.dao.GetPubByID(ctx, 1)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+			},
+			qid:      1,
+			wantCode: 200,
+		},
+		{
+			name: "题集在回收站里面",
+			before: func(t *testing.T) {
+				s.createQuestion(t, 2, dao.QuestionStatusDraft, "草稿")
+				err := s.db.Create(&dao.QuestionSet{Id: 1, Uid: uid, Title: "已删除的题集", Dtime: 123}).Error
+				require.NoError(t, err)
+				err = s.db.Create(&dao.QuestionSetQuestion{QSID: 1, QID: 2}).Error
+				require.NoError(t, err)
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, _, err := s.dao.GetByID(ctx, 2)
+				assert.Equal(t, gorm.ErrRecordNotFound, err)
+			},
+			qid:      2,
+			wantCode: 200,
+		},
+		{
+			name: "还在题集中",
+			before: func(t *testing.T) {
+				s.createQuestion(t, 3, dao.QuestionStatusDraft, "草稿")
+				err := s.db.Create(&dao.QuestionSet{Id: 2, Uid: uid, Title: "题集"}).Error
+				require.NoError(t, err)
+				err = s.db.Create(&dao.QuestionSetQuestion{QSID: 2, QID: 3}).Error
+				require.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, _, err := s.dao.GetByID(ctx, 3)
+				require.NoError(t, err)
+			},
+			qid:      3,
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 502006, Msg: "题目还在题集中，请先从题集中移除"},
+		},
+		{
+			name: "还被技能引用",
+			before: func(t *testing.T) {
+				s.createQuestion(t, 10, dao.QuestionStatusDraft, "草稿")
+			},
+			after: func(t *testing.T) {
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, _, err := s.dao.GetByID(ctx, 10)
+				require.NoError(t, err)
+			},
+			qid:      10,
+			wantCode: 500,
+			wantResp: test.Result[any]{Code: 502007, Msg: "题目还被技能引用，请先解除引用"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/question/delete", iox.NewJSONReader(web.Qid{Qid: tc.qid}))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[any]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+			tc.after(t)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestRecycle() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	s.createQuestion(s.T(), 1, dao.QuestionStatusPublished, "刚删除的")
+	s.createQuestion(s.T(), 2, dao.QuestionStatusDraft, "很久之前删除的")
+	s.createQuestion(s.T(), 3, dao.QuestionStatusDraft, "没有删除的")
+	err := s.db.WithContext(ctx).Model(&dao.Question{}).Where("id = ?", 1).
+		Update("dtime", time.Now().UnixMilli()).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Model(&dao.Question{}).Where("id = ?", 2).
+		Update("dtime", time.Now().AddDate(0, 0, -31).UnixMilli()).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Create(&dao.QuestionSetQuestion{QSID: 1, QID: 2}).Error
+	require.NoError(s.T(), err)
+
+	// 回收站按照删除时间倒序
+	req, err := http.NewRequest(http.MethodPost,
+		"/question/recycle/list", iox.NewJSONReader(web.Page{Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[web.QuestionList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	list := recorder.MustScan().Data
+	assert.Equal(s.T(), int64(2), list.Total)
+	assert.Equal(s.T(), []int64{1, 2}, slice.Map(list.Questions, func(idx int, src web.Question) int64 {
+		return src.Id
+	}))
+
+	// 恢复之后回到草稿状态，需要重新发布
+	s.doPost(s.T(), "/question/recycle/restore", web.Qid{Qid: 1}, 200)
+	q, _, err := s.dao.GetByID(ctx, 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), uint8(dao.QuestionStatusDraft), q.Status)
+	assert.Equal(s.T(), int64(0), q.Dtime)
+	// 不在回收站里面的不能恢复
+	s.doPost(s.T(), "/question/recycle/restore", web.Qid{Qid: 3}, 500)
+
+	// 超过三十天的彻底删除
+	err = s.purgeJob.Run()
+	require.NoError(s.T(), err)
+	var cnt int64
+	err = s.db.WithContext(ctx).Model(&dao.Question{}).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(2), cnt)
+	err = s.db.WithContext(ctx).Model(&dao.AnswerElement{}).Where("qid = ?", 2).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+	err = s.db.WithContext(ctx).Model(&dao.QuestionSetQuestion{}).Where("q_id = ?", 2).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+}
+
+func (s *HandlerTestSuite) TestReindex() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := s.db.WithContext(ctx).Create(&[]dao.PublishQuestion{
+		{Id: 1, Uid: uid, Title: "已发布1", Content: "内容1", Status: dao.QuestionStatusPublished},
+		{Id: 2, Uid: uid, Title: "已发布2", Content: "内容2", Status: dao.QuestionStatusPublished},
+		{Id: 3, Uid: uid, Title: "已下架", Content: "内容3", Status: dao.QuestionStatusUnpublished},
+	}).Error
+	require.NoError(s.T(), err)
+
+	var evts []event.SyncSearchEvent
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, evt event.SyncSearchEvent) error {
+			evts = append(evts, evt)
+			return nil
+		}).Times(2)
+	err = s.reindexJob.Run(ejob.Context{Ctx: ctx})
+	require.NoError(s.T(), err)
+	// 下架的问题不会同步到搜索
+	assert.Equal(s.T(), []int64{1, 2}, slice.Map(evts, func(idx int, src event.SyncSearchEvent) int64 {
+		return src.BizId
+	}))
+	assert.Equal(s.T(), "question", evts[0].Biz)
+	assert.Equal(s.T(), "已发布1", evts[0].Title)
+	assert.Equal(s.T(), "内容1", evts[0].Content)
+}
+
+func (s *HandlerTestSuite) TestListReview() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	data := []dao.Question{
+		{Id: 1, Uid: uid, Title: "分配给我的", Status: dao.QuestionStatusPendingReview, Reviewer: uid, Utime: 123},
+		{Id: 2, Uid: uid, Title: "分配给别人的", Status: dao.QuestionStatusPendingReview, Reviewer: uid + 1, Utime: 123},
+		{Id: 3, Uid: uid, Title: "已经审核过的", Status: dao.QuestionStatusApproved, Reviewer: uid, Utime: 123},
+	}
+	err := s.db.WithContext(ctx).Create(&data).Error
+	require.NoError(s.T(), err)
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/question/review/list", iox.NewJSONReader(web.Page{Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[web.QuestionList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	assert.Equal(s.T(), web.QuestionList{
+		Total: 1,
+		Questions: []web.Question{
+			{
+				Id:     1,
+				Title:  "分配给我的",
+				Status: dao.QuestionStatusPendingReview,
+				Utime:  time.UnixMilli(123).Format(time.DateTime),
 			},
-			after: func(t *testing.T) {
-				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
-				defer cancel()
-				q, eles, err := s.dao.GetByID(ctx, 2)
-				require.NoError(t, err)
-				s.assertQuestion(t, dao.Question{
-					Uid:     uid,
-					Title:   "面试题1",
-					Content: "新的内容",
-				}, q)
-				assert.Equal(t, 4, len(eles))
-				analysis := eles[0]
-				s.assertAnswerElement(t, dao.AnswerElement{
-					Content:   "新的分析",
-					Type:      dao.AnswerElementTypeAnalysis,
-					Qid:       2,
-					Keywords:  "新的 keyword",
-					Shorthand: "新的速记",
-					Highlight: "新的亮点",
-					Guidance:  "新的引导点",
-				}, analysis)
+		},
+	}, recorder.MustScan().Data)
+}
+
+// createQuestion 在制作库中创建一个指定状态的问题，答案和 buildDAOAnswerEle 一致
+func (s *HandlerTestSuite) createQuestion(t *testing.T, qid int64, status uint8, title string) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := s.db.WithContext(ctx).Create(&dao.Question{
+		Id:      qid,
+		Uid:     uid,
+		Title:   title,
+		Content: "面试题内容",
+		Status:  status,
+		Ctime:   123,
+		Utime:   234,
+	}).Error
+	require.NoError(t, err)
+	eles := []dao.AnswerElement{
+		s.buildDAOAnswerEle(qid, 0, dao.AnswerElementTypeAnalysis),
+		s.buildDAOAnswerEle(qid, 1, dao.AnswerElementTypeBasic),
+		s.buildDAOAnswerEle(qid, 2, dao.AnswerElementTypeIntermedia),
+		s.buildDAOAnswerEle(qid, 3, dao.AnswerElementTypeAdvanced),
+	}
+	for i := range eles {
+		eles[i].Ctime = 123
+		eles[i].Utime = 234
+	}
+	err = s.db.WithContext(ctx).Create(&eles).Error
+	require.NoError(t, err)
+}
+
+func (s *HandlerTestSuite) TestVersion() {
+	// 发布两次，得到版本 1 和版本 2
+	s.publish(web.Question{
+		Title:        "版本一",
+		Content:      "面试题内容",
+		Analysis:     s.buildAnswerEle(0),
+		Basic:        s.buildAnswerEle(1),
+		Intermediate: s.buildAnswerEle(2),
+		Advanced:     s.buildAnswerEle(3),
+	})
+	basic := s.buildAnswerEle(1)
+	basic.Content = "新的基本回答"
+	s.publish(web.Question{
+		Id:           1,
+		Title:        "版本二",
+		Content:      "面试题内容",
+		Analysis:     s.buildAnswerEle(0),
+		Basic:        basic,
+		Intermediate: s.buildAnswerEle(2),
+		Advanced:     s.buildAnswerEle(3),
+	})
 
-				pq, pEles, err := s.dao.GetPubByID(ctx, 2)
+	s.T().Run("版本列表", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost,
+			"/question/version/list", iox.NewJSONReader(web.VersionPage{Qid: 1, Limit: 10}))
+		req.Header.Set("content-type", "application/json")
+		require.NoError(t, err)
+		recorder := test.NewJSONResponseRecorder[web.QuestionVersionList]()
+		s.server.ServeHTTP(recorder, req)
+		require.Equal(t, 200, recorder.Code)
+		data := recorder.MustScan().Data
+		assert.Equal(t, int64(2), data.Total)
+		require.Equal(t, 2, len(data.Versions))
+		assert.Equal(t, int64(2), data.Versions[0].Version)
+		assert.Equal(t, "版本二", data.Versions[0].Question.Title)
+		assert.Equal(t, int64(1), data.Versions[1].Version)
+		assert.Equal(t, "版本一", data.Versions[1].Question.Title)
+	})
 
-				s.assertQuestion(t, dao.Question{
+	s.T().Run("版本列表_非法分页参数", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost,
+			"/question/version/list", iox.NewJSONReader(web.VersionPage{Qid: 1, Offset: -1, Limit: -1}))
+		req.Header.Set("content-type", "application/json")
+		require.NoError(t, err)
+		recorder := test.NewJSONResponseRecorder[web.QuestionVersionList]()
+		s.server.ServeHTTP(recorder, req)
+		require.Equal(t, 200, recorder.Code)
+		data := recorder.MustScan().Data
+		assert.Equal(t, int64(2), data.Total)
+		assert.Equal(t, 2, len(data.Versions))
+	})
+
+	detailCases := []struct {
+		name     string
+		req      web.VersionReq
+		wantCode int
+		wantResp test.Result[web.QuestionVersion]
+	}{
+		{
+			name:     "查看版本",
+			req:      web.VersionReq{Qid: 1, Version: 1},
+			wantCode: 200,
+			wantResp: test.Result[web.QuestionVersion]{
+				Data: web.QuestionVersion{
+					Version: 1,
 					Uid:     uid,
-					Title:   "面试题1",
-					Content: "新的内容",
-				}, dao.Question(pq))
-				assert.Equal(t, 4, len(pEles))
-				pAnalysis := pEles[0]
-				s.assertAnswerElement(t, dao.AnswerElement{
-					Content:   "新的分析",
-					Type:      dao.AnswerElementTypeAnalysis,
-					Qid:       2,
-					Keywords:  "新的 keyword",
-					Shorthand: "新的速记",
-					Highlight: "新的亮点",
-					Guidance:  "新的引导点",
-				}, dao.AnswerElement(pAnalysis))
-			},
-			req: func() web.SaveReq {
-				analysis := web.AnswerElement{
-					Id:        1,
-					Content:   "新的分析",
-					Keywords:  "新的 keyword",
-					Shorthand: "新的速记",
-					Highlight: "新的亮点",
-					Guidance:  "新的引导点",
-				}
-				return web.SaveReq{
 					Question: web.Question{
-						Id:           2,
-						Title:        "面试题1",
-						Content:      "新的内容",
-						Analysis:     analysis,
+						Id:           1,
+						Title:        "版本一",
+						Content:      "面试题内容",
+						Analysis:     s.buildAnswerEle(0),
 						Basic:        s.buildAnswerEle(1),
 						Intermediate: s.buildAnswerEle(2),
 						Advanced:     s.buildAnswerEle(3),
 					},
-				}
-			}(),
-			wantCode: 200,
-			wantResp: test.Result[int64]{
-				Data: 2,
+				},
+			},
+		},
+		{
+			name:     "版本不存在",
+			req:      web.VersionReq{Qid: 1, Version: 9},
+			wantCode: 500,
+			wantResp: test.Result[web.QuestionVersion]{
+				Code: 502002,
+				Msg:  "问题版本不存在",
 			},
 		},
 	}
+	for _, tc := range detailCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/question/version/detail", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[web.QuestionVersion]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			resp := recorder.MustScan()
+			resp.Data.Ctime = ""
+			resp.Data.Question.Utime = ""
+			assert.Equal(t, tc.wantResp, resp)
+		})
+	}
 
-	for _, tc := range testCases {
+	// 修改制作库，但是不发布
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := s.db.WithContext(ctx).Model(&dao.Question{}).
+		Where("id = ?", 1).Update("content", "制作库的内容").Error
+	require.NoError(s.T(), err)
+
+	diffCases := []struct {
+		name     string
+		req      web.DiffReq
+		wantCode int
+		wantResp test.Result[[]web.FieldDiff]
+	}{
+		{
+			name:     "两个版本",
+			req:      web.DiffReq{Qid: 1, OldVersion: 1, NewVersion: 2},
+			wantCode: 200,
+			wantResp: test.Result[[]web.FieldDiff]{
+				Data: []web.FieldDiff{
+					{Field: "title", Old: "版本一", New: "版本二"},
+					{Field: "basic.content", Old: "这是解析 1", New: "新的基本回答"},
+				},
+			},
+		},
+		{
+			name:     "线上版本和制作库",
+			req:      web.DiffReq{Qid: 1, OldVersion: 2, NewVersion: 0},
+			wantCode: 200,
+			wantResp: test.Result[[]web.FieldDiff]{
+				Data: []web.FieldDiff{
+					{Field: "content", Old: "面试题内容", New: "制作库的内容"},
+				},
+			},
+		},
+		{
+			name:     "版本不存在",
+			req:      web.DiffReq{Qid: 1, OldVersion: 1, NewVersion: 9},
+			wantCode: 500,
+			wantResp: test.Result[[]web.FieldDiff]{
+				Code: 502002,
+				Msg:  "问题版本不存在",
+			},
+		},
+	}
+	for _, tc := range diffCases {
 		s.T().Run(tc.name, func(t *testing.T) {
-			tc.before(t)
 			req, err := http.NewRequest(http.MethodPost,
-				"/question/publish", iox.NewJSONReader(tc.req))
+				"/question/version/diff", iox.NewJSONReader(tc.req))
 			req.Header.Set("content-type", "application/json")
 			require.NoError(t, err)
-			recorder := test.NewJSONResponseRecorder[int64]()
+			recorder := test.NewJSONResponseRecorder[[]web.FieldDiff]()
 			s.server.ServeHTTP(recorder, req)
 			require.Equal(t, tc.wantCode, recorder.Code)
 			assert.Equal(t, tc.wantResp, recorder.MustScan())
-			tc.after(t)
-			// 清理掉 123 的数据
-			err = s.db.Exec("TRUNCATE table `questions`").Error
-			require.NoError(t, err)
-			err = s.db.Exec("TRUNCATE table `answer_elements`").Error
-			require.NoError(t, err)
 		})
 	}
+
+	s.T().Run("回滚", func(t *testing.T) {
+		s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+		req, err := http.NewRequest(http.MethodPost,
+			"/question/version/rollback", iox.NewJSONReader(web.VersionReq{Qid: 1, Version: 1}))
+		req.Header.Set("content-type", "application/json")
+		require.NoError(t, err)
+		recorder := test.NewJSONResponseRecorder[int64]()
+		s.server.ServeHTTP(recorder, req)
+		require.Equal(t, 200, recorder.Code)
+		assert.Equal(t, int64(3), recorder.MustScan().Data)
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		q, eles, err := s.dao.GetPubByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "版本一", q.Title)
+		require.Equal(t, 4, len(eles))
+		assert.Equal(t, "这是解析 1", eles[1].Content)
+
+		v, err := s.dao.GetVersion(ctx, 1, 3)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), v.BaseVersion)
+		assert.Equal(t, "版本一", v.Title)
+
+		// 制作库不受影响
+		pq, _, err := s.dao.GetByID(ctx, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "版本二", pq.Title)
+	})
+
+	s.T().Run("回滚到不存在的版本", func(t *testing.T) {
+		req, err := http.NewRequest(http.MethodPost,
+			"/question/version/rollback", iox.NewJSONReader(web.VersionReq{Qid: 1, Version: 9}))
+		req.Header.Set("content-type", "application/json")
+		require.NoError(t, err)
+		recorder := test.NewJSONResponseRecorder[int64]()
+		s.server.ServeHTTP(recorder, req)
+		require.Equal(t, 500, recorder.Code)
+		assert.Equal(t, test.Result[int64]{Code: 502002, Msg: "问题版本不存在"}, recorder.MustScan())
+	})
+}
+
+// publish 保存到制作库，审核通过之后发布
+func (s *HandlerTestSuite) publish(que web.Question) {
+	req, err := http.NewRequest(http.MethodPost,
+		"/question/save", iox.NewJSONReader(web.SaveReq{Question: que}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[int64]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	qid := recorder.MustScan().Data
+
+	err = s.db.Model(&dao.Question{}).Where("id = ?", qid).
+		Update("status", dao.QuestionStatusApproved).Error
+	require.NoError(s.T(), err)
+
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+	req, err = http.NewRequest(http.MethodPost,
+		"/question/publish", iox.NewJSONReader(web.Qid{Qid: qid}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder = test.NewJSONResponseRecorder[int64]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
 }
 
 func (s *HandlerTestSuite) TestPubDetail() {
@@ -574,6 +1420,28 @@ func (s *HandlerTestSuite) TestPubDetail() {
 				},
 			},
 		},
+		{
+			name: "带上自己的笔记和相关推荐",
+			req: web.Qid{
+				Qid: 1,
+			},
+			wantCode: 200,
+			wantResp: test.Result[web.Question]{
+				Data: web.Question{
+					Id:      1,
+					Title:   "这是标题 0",
+					Content: "这是解析 0",
+					Utime:   time.UnixMilli(0).Format(time.DateTime),
+					Notes: []note.NoteVO{
+						{Id: 1, Biz: note.BizQuestion, BizId: 1, Element: 2, Content: "我的笔记", Utime: time.UnixMilli(123).Format(time.DateTime)},
+					},
+					Related: []web.Related{
+						{Biz: "question", BizId: 2, Title: "这是标题 1"},
+						{Biz: "case", BizId: 3, Title: "案例"},
+					},
+				},
+			},
+		},
 	}
 	for _, tc := range testCases {
 		s.T().Run(tc.name, func(t *testing.T) {
@@ -589,6 +1457,215 @@ func (s *HandlerTestSuite) TestPubDetail() {
 	}
 }
 
+func (s *HandlerTestSuite) TestSaveNote() {
+	err := s.db.Create(&dao.PublishQuestion{Id: 1, Uid: uid, Title: "已发布"}).Error
+	require.NoError(s.T(), err)
+	testCases := []struct {
+		name   string
+		before func(t *testing.T)
+		req    web.NoteReq
+
+		wantCode int
+		wantResp test.Result[any]
+	}{
+		{
+			name: "保存成功",
+			before: func(t *testing.T) {
+				s.noteSvc.EXPECT().Save(gomock.Any(), note.Note{
+					Uid: uid, Biz: note.BizQuestion, BizId: 1, Element: 1, Content: "分析",
+				}).Return(nil)
+			},
+			req:      web.NoteReq{Qid: 1, Element: 1, Content: "分析"},
+			wantCode: 200,
+		},
+		{
+			name:     "题目没有发布",
+			before:   func(t *testing.T) {},
+			req:      web.NoteReq{Qid: 2, Content: "分析"},
+			wantCode: 200,
+			wantResp: test.Result[any]{Code: 502005, Msg: "题目不存在或者未发布"},
+		},
+		{
+			name: "笔记非法",
+			before: func(t *testing.T) {
+				s.noteSvc.EXPECT().Save(gomock.Any(), gomock.Any()).Return(note.ErrInvalidNote)
+			},
+			req:      web.NoteReq{Qid: 1, Element: 5, Content: "分析"},
+			wantCode: 200,
+			wantResp: test.Result[any]{Code: 517002, Msg: "笔记内容非法"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/question/note/save", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[any]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestPubCache() {
+	t := s.T()
+	s.createQuestion(t, 1, dao.QuestionStatusApproved, "面试题1")
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+	// 发布的时候预热
+	s.doPost(t, "/question/publish", web.Qid{Qid: 1}, 200)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := s.rdb.Get(ctx, "question:pub:1").String()
+	require.NoError(t, err)
+
+	list := s.pubList(t, web.PubListReq{Limit: 10})
+	assert.Equal(t, int64(1), list.Total)
+	require.Equal(t, 1, len(list.Questions))
+	_, err = s.rdb.Get(ctx, "question:pub:list").String()
+	require.NoError(t, err)
+
+	// 绕过缓存直接修改线上库，读到的依旧是缓存中的数据
+	err = s.db.Model(&dao.PublishQuestion{}).Where("id = ?", 1).
+		Update("title", "修改后的标题").Error
+	require.NoError(t, err)
+	assert.Equal(t, "面试题1", s.pubDetail(t, 1).Title)
+	assert.Equal(t, "面试题1", s.pubList(t, web.PubListReq{Limit: 10}).Questions[0].Title)
+
+	// 下架之后缓存失效
+	s.doPost(t, "/question/unpublish", web.Qid{Qid: 1}, 200)
+	assert.True(t, s.rdb.Get(ctx, "question:pub:1").KeyNotFound())
+	assert.True(t, s.rdb.Get(ctx, "question:pub:list").KeyNotFound())
+	list = s.pubList(t, web.PubListReq{Limit: 10})
+	assert.Equal(t, int64(0), list.Total)
+	assert.Equal(t, 0, len(list.Questions))
+}
+
+func (s *HandlerTestSuite) TestImportExport() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	err := s.db.Create(&dao.Question{
+		Id:      5,
+		Uid:     uid + 1,
+		Title:   "老的标题",
+		Content: "老的内容",
+		Status:  dao.QuestionStatusPublished,
+		Ctime:   123,
+		Utime:   123,
+	}).Error
+	require.NoError(t, err)
+
+	// 一个新建，一个更新，一个缺少标题，一个问题不存在
+	data := []byte(`[
+{"title":"Redis 为什么快","labels":["Redis"],"content":"题目内容","basic":{"content":"基本回答","keywords":"内存"}},
+{"id":5,"title":"新的标题","content":"新的内容"},
+{"content":"没有标题"},
+{"id":10000,"title":"不存在"}
+]`)
+	res := s.importFile(t, "questions.json", data, 200)
+	require.Equal(t, 4, len(res.Data))
+	assert.Equal(t, web.ImportResult{Name: "questions.json[0]", Id: 6}, res.Data[0])
+	assert.Equal(t, web.ImportResult{Name: "questions.json[1]", Id: 5}, res.Data[1])
+	assert.NotEmpty(t, res.Data[2].Error)
+	assert.NotEmpty(t, res.Data[3].Error)
+
+	q, eles, err := s.dao.GetByID(ctx, 6)
+	require.NoError(t, err)
+	s.assertQuestion(t, dao.Question{
+		Uid:     uid,
+		Title:   "Redis 为什么快",
+		Labels:  sqlx.JsonColumn[[]string]{Val: []string{"Redis"}, Valid: true},
+		Content: "题目内容",
+		Status:  dao.QuestionStatusDraft,
+	}, q)
+	assert.Equal(t, 4, len(eles))
+	assert.Equal(t, "基本回答", eles[1].Content)
+	assert.Equal(t, "内存", eles[1].Keywords)
+	// 更新之后需要重新审核
+	q, _, err = s.dao.GetByID(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "新的标题", q.Title)
+	assert.Equal(t, uint8(dao.QuestionStatusDraft), q.Status)
+
+	// 文件格式错误
+	res = s.importFile(t, "questions.txt", data, 500)
+	assert.Equal(t, 502004, res.Code)
+
+	// 导出之后可以原样导入
+	recorder := httptest.NewRecorder()
+	req, err := http.NewRequest(http.MethodPost, "/question/export",
+		iox.NewJSONReader(web.ExportReq{Qids: []int64{6, 5}, Format: "md"}))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	assert.Equal(t, `attachment; filename="questions.zip"`, recorder.Header().Get("Content-Disposition"))
+	res = s.importFile(t, "questions.zip", recorder.Body.Bytes(), 200)
+	assert.Equal(t, []web.ImportResult{
+		{Name: "6.md", Id: 6},
+		{Name: "5.md", Id: 5},
+	}, res.Data)
+	q, _, err = s.dao.GetByID(ctx, 6)
+	require.NoError(t, err)
+	assert.Equal(t, "Redis 为什么快", q.Title)
+	assert.Equal(t, []string{"Redis"}, q.Labels.Val)
+
+	// 问题不存在
+	s.doPost(t, "/question/export", web.ExportReq{Qids: []int64{10000}, Format: "json"}, 500)
+}
+
+func (s *HandlerTestSuite) importFile(t *testing.T, name string, data []byte, wantCode int) test.Result[[]web.ImportResult] {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	f, err := w.CreateFormFile("file", name)
+	require.NoError(t, err)
+	_, err = f.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	req, err := http.NewRequest(http.MethodPost, "/question/import", &body)
+	require.NoError(t, err)
+	req.Header.Set("content-type", w.FormDataContentType())
+	recorder := test.NewJSONResponseRecorder[[]web.ImportResult]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, wantCode, recorder.Code)
+	return recorder.MustScan()
+}
+
+func (s *HandlerTestSuite) doPost(t *testing.T, path string, body any, wantCode int) {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, wantCode, recorder.Code)
+}
+
+func (s *HandlerTestSuite) pubDetail(t *testing.T, qid int64) web.Question {
+	req, err := http.NewRequest(http.MethodPost,
+		"/question/pub/detail", iox.NewJSONReader(web.Qid{Qid: qid}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[web.Question]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func (s *HandlerTestSuite) pubList(t *testing.T, page web.PubListReq) web.QuestionList {
+	req, err := http.NewRequest(http.MethodPost,
+		"/question/pub/list", iox.NewJSONReader(page))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[web.QuestionList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
 func (s *HandlerTestSuite) buildDAOAnswerEle(
 	qid int64,
 	idx int,
@@ -1195,6 +2272,34 @@ func (s *HandlerTestSuite) TestQuestionSet_UpdateQuestions() {
 			wantCode: 500,
 			wantResp: test.Result[int64]{Code: 502001, Msg: "系统错误"},
 		},
+		{
+			name: "问题不存在或者已经删除",
+			before: func(t *testing.T) {
+				t.Helper()
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				_, err := s.questionSetDAO.Create(ctx, dao.QuestionSet{Id: 221, Uid: uid, Title: "题集"})
+				require.NoError(t, err)
+				err = s.db.WithContext(ctx).Create(&dao.Question{Id: 514, Uid: uid, Title: "删除的问题", Dtime: 123}).Error
+				require.NoError(t, err)
+				err = s.db.WithContext(ctx).Create(&dao.Question{Id: 515, Uid: uid, Title: "问题"}).Error
+				require.NoError(t, err)
+			},
+			after: func(t *testing.T) {
+				t.Helper()
+				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+				defer cancel()
+				qs, err := s.questionSetDAO.GetQuestionsByID(ctx, 221)
+				require.NoError(t, err)
+				assert.Empty(t, qs)
+			},
+			req: web.UpdateQuestionsOfQuestionSetReq{
+				QSID: 221,
+				QIDs: []int64{515, 514, 516},
+			},
+			wantCode: 500,
+			wantResp: test.Result[int64]{Code: 502010, Msg: "题目不存在或者已经删除，不能加入题集"},
+		},
 		// {
 		//	name: "当前用户并非题集的创建者",
 		//	before: func(t *testing.T) {
@@ -1560,11 +2665,11 @@ func (s *HandlerTestSuite) TestQuestionSet_ListPrivateQuestionSets() {
 func (s *HandlerTestSuite) TestQuestionSet_ListAllQuestionSets() {
 	// 插入一百条
 	total := 100
-	data := make([]dao.QuestionSet, 0, total)
+	data := make([]dao.PublishQuestionSet, 0, total)
 
 	for idx := 0; idx < total; idx++ {
 		// 空题集
-		data = append(data, dao.QuestionSet{
+		data = append(data, dao.PublishQuestionSet{
 			Uid:         int64(uid + idx),
 			Title:       fmt.Sprintf("题集标题 %d", idx),
 			Description: fmt.Sprintf("题集简介 %d", idx),
@@ -1572,6 +2677,13 @@ func (s *HandlerTestSuite) TestQuestionSet_ListAllQuestionSets() {
 	}
 	err := s.db.Create(&data).Error
 	require.NoError(s.T(), err)
+	// 制作库的题集没有发布，不会被展示
+	err = s.db.Create(&dao.QuestionSet{
+		Id:    int64(total + 1),
+		Uid:   uid,
+		Title: "未发布的题集",
+	}).Error
+	require.NoError(s.T(), err)
 
 	testCases := []struct {
 		name string
@@ -1644,6 +2756,276 @@ func (s *HandlerTestSuite) TestQuestionSet_ListAllQuestionSets() {
 	}
 }
 
+func (s *HandlerTestSuite) TestQuestionSet_Recycle() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := s.db.WithContext(ctx).Create(&dao.QuestionSet{Id: 1, Uid: uid, Title: "题集"}).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Create(&dao.QuestionSetQuestion{QSID: 1, QID: 1}).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Create(&dao.PublishQuestionSet{Id: 1, Uid: uid, Title: "题集"}).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Create(&dao.PublishQuestionSetQuestion{QSID: 1, QID: 1}).Error
+	require.NoError(s.T(), err)
+
+	// 删除之后线上库同步删除
+	s.doPost(s.T(), "/question-sets/delete", web.QuestionSetID{QSID: 1}, 200)
+	_, err = s.questionSetDAO.GetByID(ctx, 1)
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	var cnt int64
+	err = s.db.WithContext(ctx).Model(&dao.PublishQuestionSet{}).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+	err = s.db.WithContext(ctx).Model(&dao.PublishQuestionSetQuestion{}).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+	// 重复删除
+	req, err := http.NewRequest(http.MethodPost,
+		"/question-sets/delete", iox.NewJSONReader(web.QuestionSetID{QSID: 1}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	deleteRecorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(deleteRecorder, req)
+	require.Equal(s.T(), 500, deleteRecorder.Code)
+	assert.Equal(s.T(), 502009, deleteRecorder.MustScan().Code)
+
+	req, err = http.NewRequest(http.MethodPost,
+		"/question-sets/recycle/list", iox.NewJSONReader(web.Page{Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[web.QuestionSetList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	list := recorder.MustScan().Data
+	assert.Equal(s.T(), int64(1), list.Total)
+	assert.Equal(s.T(), "题集", list.QuestionSets[0].Title)
+
+	// 恢复之后制作库的题目关系还在
+	s.doPost(s.T(), "/question-sets/recycle/restore", web.QuestionSetID{QSID: 1}, 200)
+	qs, err := s.questionSetDAO.GetByID(ctx, 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), "题集", qs.Title)
+	err = s.db.WithContext(ctx).Model(&dao.QuestionSetQuestion{}).Where("qs_id = ?", 1).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), cnt)
+}
+
+func (s *HandlerTestSuite) TestQuestionSet_Publish() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	now := time.Now().UnixMilli()
+	formattedUtime := time.UnixMilli(now).Format(time.DateTime)
+
+	id, err := s.questionSetDAO.Create(ctx, dao.QuestionSet{
+		Id:          400,
+		Uid:         uid,
+		Title:       "Redis",
+		Description: "Redis题集",
+		Utime:       now,
+	})
+	require.NoError(t, err)
+	for _, qid := range []int64{701, 702, 703} {
+		que := dao.Question{
+			Id:      qid,
+			Uid:     uid,
+			Title:   fmt.Sprintf("Redis问题%d", qid),
+			Content: fmt.Sprintf("Redis内容%d", qid),
+			Status:  dao.QuestionStatusPublished,
+			Ctime:   now,
+			Utime:   now,
+		}
+		require.NoError(t, s.db.Create(&que).Error)
+		// 703 没有发布
+		if qid != 703 {
+			pub := dao.PublishQuestion(que)
+			require.NoError(t, s.db.Create(&pub).Error)
+		}
+	}
+	// 题目的顺序由前端决定，而不是按照 id 排序
+	qids := []int64{702, 703, 701}
+	s.doPost(t, "/question-sets/questions/save", web.UpdateQuestionsOfQuestionSetReq{
+		QSID: id,
+		QIDs: qids,
+	}, 200)
+	qs, err := s.questionSetDAO.GetQuestionsByID(ctx, id)
+	require.NoError(t, err)
+	assert.Equal(t, qids, slice.Map(qs, func(idx int, src dao.Question) int64 {
+		return src.Id
+	}))
+
+	// 未发布之前，会员看不到
+	s.doPost(t, "/question-sets/pub/detail", web.QuestionSetID{QSID: id}, 500)
+	list := s.pubQuestionSetList(t)
+	assert.Equal(t, web.QuestionSetList{}, list)
+
+	s.doPost(t, "/question-sets/publish", web.QuestionSetID{QSID: id}, 200)
+	expected := web.QuestionSet{
+		Id:          id,
+		Title:       "Redis",
+		Description: "Redis题集",
+		Questions: []web.Question{
+			{
+				Id:      702,
+				Title:   "Redis问题702",
+				Content: "Redis内容702",
+				Utime:   formattedUtime,
+			},
+			{
+				Id:      701,
+				Title:   "Redis问题701",
+				Content: "Redis内容701",
+				Utime:   formattedUtime,
+			},
+		},
+		Utime:    formattedUtime,
+		Progress: &practice.ProgressVO{Total: 2},
+	}
+	assert.Equal(t, expected, s.pubQuestionSetDetail(t, id))
+	list = s.pubQuestionSetList(t)
+	assert.Equal(t, int64(1), list.Total)
+
+	// 修改制作库之后，没有重新发布，线上库不变
+	s.doPost(t, "/question-sets/questions/save", web.UpdateQuestionsOfQuestionSetReq{
+		QSID: id,
+		QIDs: []int64{701, 702},
+	}, 200)
+	assert.Equal(t, expected, s.pubQuestionSetDetail(t, id))
+
+	// 重新发布之后，线上库的顺序跟着变化
+	s.doPost(t, "/question-sets/publish", web.QuestionSetID{QSID: id}, 200)
+	expected.Questions[0], expected.Questions[1] = expected.Questions[1], expected.Questions[0]
+	assert.Equal(t, expected, s.pubQuestionSetDetail(t, id))
+
+	// 题集不存在
+	s.doPost(t, "/question-sets/publish", web.QuestionSetID{QSID: 10000}, 500)
+}
+
+func (s *HandlerTestSuite) TestQuestionSetPermission() {
+	t := s.T()
+	// 不是创作者的时候不能发布、删除和恢复题集，也不能查看回收站
+	server := gin.New()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{Uid: uid}))
+	})
+	s.qsHdl.PrivateRoutes(server)
+	err := s.db.Create(&dao.QuestionSet{Id: 1, Uid: uid, Title: "题集"}).Error
+	require.NoError(t, err)
+	paths := []string{
+		"/question-sets/publish",
+		"/question-sets/delete",
+		"/question-sets/recycle/list",
+		"/question-sets/recycle/restore",
+	}
+	for _, path := range paths {
+		req, err := http.NewRequest(http.MethodPost,
+			path, iox.NewJSONReader(web.QuestionSetID{QSID: 1}))
+		require.NoError(t, err)
+		req.Header.Set("content-type", "application/json")
+		recorder := test.NewJSONResponseRecorder[any]()
+		server.ServeHTTP(recorder, req)
+		assert.Equal(t, http.StatusInternalServerError, recorder.Code, path)
+	}
+	_, err = s.questionSetDAO.GetByID(context.Background(), 1)
+	require.NoError(t, err)
+}
+
+func (s *HandlerTestSuite) TestPractice() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	for _, qid := range []int64{801, 802} {
+		que := dao.PublishQuestion{
+			Id:     qid,
+			Uid:    uid,
+			Title:  fmt.Sprintf("练习问题%d", qid),
+			Status: dao.QuestionStatusPublished,
+			Ctime:  123,
+			Utime:  123,
+		}
+		require.NoError(t, s.db.Create(&que).Error)
+	}
+	qsid, err := s.questionSetDAO.Create(ctx, dao.QuestionSet{Uid: uid, Title: "练习题集"})
+	require.NoError(t, err)
+	require.NoError(t, s.questionSetDAO.UpdateQuestionsByID(ctx, qsid, []int64{802, 801}))
+	require.NoError(t, s.questionSetDAO.Sync(ctx, qsid))
+
+	// 按照题集练习，题目顺序和题集一致
+	s.practiceSvc.EXPECT().StartSession(gomock.Any(), practice.Session{
+		Uid:   uid,
+		Biz:   "questionSet",
+		BizId: qsid,
+		Qids:  []int64{802, 801},
+	}).Return(int64(11), nil)
+	assert.Equal(t, int64(11), s.postForID(t, "/question-sets/practice/start", web.QuestionSetID{QSID: qsid}))
+
+	// 按照标签练习
+	s.labelSvc.EXPECT().BizIDs(gomock.Any(), "question", []int64{3}, false, 0, 100).
+		Return([]int64{802, 801}, int64(2), nil).Times(2)
+	s.practiceSvc.EXPECT().StartSession(gomock.Any(), practice.Session{
+		Uid:   uid,
+		Biz:   "label",
+		BizId: 3,
+		Qids:  []int64{802, 801},
+	}).Return(int64(12), nil)
+	assert.Equal(t, int64(12), s.postForID(t, "/question/practice/start", web.LabelReq{LabelId: 3}))
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/question/practice/progress", iox.NewJSONReader(web.LabelReq{LabelId: 3}))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[practice.ProgressVO]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	assert.Equal(t, practice.ProgressVO{Total: 2}, recorder.MustScan().Data)
+
+	// 标签下面没有题目
+	s.labelSvc.EXPECT().BizIDs(gomock.Any(), "question", []int64{4}, false, 0, 100).
+		Return(nil, int64(0), nil)
+	s.practiceSvc.EXPECT().StartSession(gomock.Any(), gomock.Any()).
+		Return(int64(0), practice.ErrEmptySession)
+	req, err = http.NewRequest(http.MethodPost,
+		"/question/practice/start", iox.NewJSONReader(web.LabelReq{LabelId: 4}))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	idRecorder := test.NewJSONResponseRecorder[int64]()
+	s.server.ServeHTTP(idRecorder, req)
+	require.Equal(t, 500, idRecorder.Code)
+	assert.Equal(t, practice.EmptySessionResult.Code, idRecorder.MustScan().Code)
+}
+
+func (s *HandlerTestSuite) postForID(t *testing.T, path string, body any) int64 {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[int64]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func (s *HandlerTestSuite) pubQuestionSetDetail(t *testing.T, id int64) web.QuestionSet {
+	req, err := http.NewRequest(http.MethodPost,
+		"/question-sets/pub/detail", iox.NewJSONReader(web.QuestionSetID{QSID: id}))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.QuestionSet]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func (s *HandlerTestSuite) pubQuestionSetList(t *testing.T) web.QuestionSetList {
+	req, err := http.NewRequest(http.MethodPost,
+		"/question-sets/pub/list", iox.NewJSONReader(web.Page{Limit: 10}))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.QuestionSetList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
 func TestHandler(t *testing.T) {
 	suite.Run(t, new(HandlerTestSuite))
 }
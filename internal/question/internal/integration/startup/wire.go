@@ -17,22 +17,46 @@
 package startup
 
 import (
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/practice"
 	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/question/internal/event"
 	"github.com/ecodeclub/webook/internal/question/internal/web"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 	"github.com/google/wire"
 )
 
-func InitHandler() (*web.Handler, error) {
+func InitHandler(p event.SyncEventProducer, lm *label.Module,
+	pm *practice.Module, nm *note.Module, rs baguwen.RelatedService,
+	rf baguwen.ReferenceService) (*web.Handler, error) {
 	wire.Build(testioc.BaseSet,
-		baguwen.InitModule,
+		baguwen.InitModuleWithProducer,
 		wire.FieldsOf(new(*baguwen.Module), "Hdl"),
 	)
 	return new(web.Handler), nil
 }
 
-func InitQuestionSetHandler() (*web.QuestionSetHandler, error) {
-	wire.Build(testioc.BaseSet, baguwen.InitModule,
+func InitQuestionSetHandler(p event.SyncEventProducer, lm *label.Module,
+	pm *practice.Module, nm *note.Module, rs baguwen.RelatedService,
+	rf baguwen.ReferenceService) (*web.QuestionSetHandler, error) {
+	wire.Build(testioc.BaseSet, baguwen.InitModuleWithProducer,
 		wire.FieldsOf(new(*baguwen.Module), "QsHdl"))
 	return new(web.QuestionSetHandler), nil
 }
+
+func InitPurgeJob(p event.SyncEventProducer, lm *label.Module,
+	pm *practice.Module, nm *note.Module, rs baguwen.RelatedService,
+	rf baguwen.ReferenceService) (*baguwen.PurgeJob, error) {
+	wire.Build(testioc.BaseSet, baguwen.InitModuleWithProducer,
+		wire.FieldsOf(new(*baguwen.Module), "PurgeJob"))
+	return new(baguwen.PurgeJob), nil
+}
+
+func InitReindexJob(p event.SyncEventProducer, lm *label.Module,
+	pm *practice.Module, nm *note.Module, rs baguwen.RelatedService,
+	rf baguwen.ReferenceService) (*baguwen.ReindexJob, error) {
+	wire.Build(testioc.BaseSet, baguwen.InitModuleWithProducer,
+		wire.FieldsOf(new(*baguwen.Module), "ReindexJob"))
+	return new(baguwen.ReindexJob), nil
+}
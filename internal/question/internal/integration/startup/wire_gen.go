@@ -7,17 +7,23 @@
 package startup
 
 import (
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/practice"
 	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/question/internal/event"
+	"github.com/ecodeclub/webook/internal/question/internal/job"
+	"github.com/ecodeclub/webook/internal/question/internal/service"
 	"github.com/ecodeclub/webook/internal/question/internal/web"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 )
 
 // Injectors from wire.go:
 
-func InitHandler() (*web.Handler, error) {
+func InitHandler(p event.SyncEventProducer, lm *label.Module, pm *practice.Module, nm *note.Module, rs service.RelatedService, rf service.ReferenceService) (*web.Handler, error) {
 	db := testioc.InitDB()
 	cache := testioc.InitCache()
-	module, err := baguwen.InitModule(db, cache)
+	module, err := baguwen.InitModuleWithProducer(db, cache, p, lm, pm, nm, rs, rf)
 	if err != nil {
 		return nil, err
 	}
@@ -25,13 +31,35 @@ func InitHandler() (*web.Handler, error) {
 	return handler, nil
 }
 
-func InitQuestionSetHandler() (*web.QuestionSetHandler, error) {
+func InitQuestionSetHandler(p event.SyncEventProducer, lm *label.Module, pm *practice.Module, nm *note.Module, rs service.RelatedService, rf service.ReferenceService) (*web.QuestionSetHandler, error) {
 	db := testioc.InitDB()
 	cache := testioc.InitCache()
-	module, err := baguwen.InitModule(db, cache)
+	module, err := baguwen.InitModuleWithProducer(db, cache, p, lm, pm, nm, rs, rf)
 	if err != nil {
 		return nil, err
 	}
 	questionSetHandler := module.QsHdl
 	return questionSetHandler, nil
 }
+
+func InitPurgeJob(p event.SyncEventProducer, lm *label.Module, pm *practice.Module, nm *note.Module, rs service.RelatedService, rf service.ReferenceService) (*job.PurgeJob, error) {
+	db := testioc.InitDB()
+	cache := testioc.InitCache()
+	module, err := baguwen.InitModuleWithProducer(db, cache, p, lm, pm, nm, rs, rf)
+	if err != nil {
+		return nil, err
+	}
+	purgeJob := module.PurgeJob
+	return purgeJob, nil
+}
+
+func InitReindexJob(p event.SyncEventProducer, lm *label.Module, pm *practice.Module, nm *note.Module, rs service.RelatedService, rf service.ReferenceService) (*job.ReindexJob, error) {
+	db := testioc.InitDB()
+	cache := testioc.InitCache()
+	module, err := baguwen.InitModuleWithProducer(db, cache, p, lm, pm, nm, rs, rf)
+	if err != nil {
+		return nil, err
+	}
+	reindexJob := module.ReindexJob
+	return reindexJob, nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/question/internal/service"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// PurgeJob 彻底删除在回收站里面放了超过 days 天的问题和题集
+type PurgeJob struct {
+	svc     service.Service
+	qsSvc   service.QuestionSetService
+	days    int
+	limit   int
+	timeout time.Duration
+	logger  *elog.Component
+}
+
+func NewPurgeJob(svc service.Service, qsSvc service.QuestionSetService,
+	days int, limit int, timeout time.Duration) *PurgeJob {
+	return &PurgeJob{
+		svc:     svc,
+		qsSvc:   qsSvc,
+		days:    days,
+		limit:   limit,
+		timeout: timeout,
+		logger:  elog.DefaultLogger,
+	}
+}
+
+func (j *PurgeJob) Name() string {
+	return "QuestionPurgeJob"
+}
+
+func (j *PurgeJob) Run() error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), j.timeout)
+	defer cancelFunc()
+	before := time.Now().AddDate(0, 0, -j.days)
+	cnt, err := j.purge(ctx, before, j.svc.Purge)
+	if err != nil {
+		return fmt.Errorf("清理回收站中的问题失败: %w", err)
+	}
+	setCnt, err := j.purge(ctx, before, j.qsSvc.Purge)
+	if err != nil {
+		return fmt.Errorf("清理回收站中的题集失败: %w", err)
+	}
+	j.logger.Info("清理回收站成功", elog.Int64("questions", cnt), elog.Int64("questionSets", setCnt))
+	return nil
+}
+
+func (j *PurgeJob) purge(ctx context.Context, before time.Time,
+	fn func(ctx context.Context, before time.Time, limit int) (int64, error)) (int64, error) {
+	var total int64
+	for {
+		cnt, err := fn(ctx, before, j.limit)
+		if err != nil {
+			return total, err
+		}
+		total += cnt
+		if cnt < int64(j.limit) {
+			return total, nil
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"github.com/ecodeclub/webook/internal/question/internal/service"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/gotomicro/ego/task/ejob"
+)
+
+// ReindexJob 命令行将线上库的问题全部重新同步到标签和搜索，
+// 例如第一次接入 Elasticsearch 或者补齐历史数据的标签关联的时候：
+// webook --config=config/config.yaml --job=question-reindex
+type ReindexJob struct {
+	svc    service.Service
+	limit  int
+	logger *elog.Component
+}
+
+func NewReindexJob(svc service.Service, limit int) *ReindexJob {
+	return &ReindexJob{
+		svc:    svc,
+		limit:  limit,
+		logger: elog.DefaultLogger,
+	}
+}
+
+func (j *ReindexJob) Name() string {
+	return "question-reindex"
+}
+
+func (j *ReindexJob) Run(ctx ejob.Context) error {
+	var minID int64
+	for {
+		lastID, err := j.svc.Reindex(ctx.Ctx, minID, j.limit)
+		if err != nil {
+			return err
+		}
+		if lastID == 0 {
+			break
+		}
+		minID = lastID
+	}
+	j.logger.Info("问题重新同步到标签和搜索成功", elog.Int64("lastId", minID))
+	return nil
+}
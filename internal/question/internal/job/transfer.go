@@ -0,0 +1,123 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ecodeclub/webook/internal/question/internal/service"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/gotomicro/ego/task/ejob"
+)
+
+// ImportJob 命令行导入问题，通过 --job-data 传入参数：
+// webook --config=config/config.yaml --job=question-import --job-data='{"uid":1,"path":"questions.zip"}'
+type ImportJob struct {
+	svc    service.TransferService
+	logger *elog.Component
+}
+
+type importArgs struct {
+	// 作者
+	Uid  int64  `json:"uid"`
+	Path string `json:"path"`
+}
+
+func NewImportJob(svc service.TransferService) *ImportJob {
+	return &ImportJob{
+		svc:    svc,
+		logger: elog.DefaultLogger,
+	}
+}
+
+func (j *ImportJob) Name() string {
+	return "question-import"
+}
+
+func (j *ImportJob) Run(ctx ejob.Context) error {
+	var args importArgs
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&args); err != nil {
+		return fmt.Errorf("解析 job-data 失败: %w", err)
+	}
+	data, err := os.ReadFile(args.Path)
+	if err != nil {
+		return err
+	}
+	res, err := j.svc.Import(ctx.Ctx, args.Uid, filepath.Base(args.Path), data)
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, r := range res {
+		if r.Err != nil {
+			failed++
+			j.logger.Error("导入问题失败", elog.String("name", r.Name), elog.FieldErr(r.Err))
+			continue
+		}
+		j.logger.Info("导入问题成功", elog.String("name", r.Name), elog.Int64("qid", r.Id))
+	}
+	if failed > 0 {
+		return fmt.Errorf("共 %d 道题，%d 道导入失败", len(res), failed)
+	}
+	return nil
+}
+
+// ExportJob 命令行导出问题：
+// webook --config=config/config.yaml --job=question-export --job-data='{"qids":[1,2],"format":"md","path":"out.zip"}'
+type ExportJob struct {
+	svc    service.TransferService
+	logger *elog.Component
+}
+
+type exportArgs struct {
+	Qids   []int64 `json:"qids"`
+	Format string  `json:"format"`
+	// 为空的时候写到当前目录下，文件名由导出格式决定
+	Path string `json:"path"`
+}
+
+func NewExportJob(svc service.TransferService) *ExportJob {
+	return &ExportJob{
+		svc:    svc,
+		logger: elog.DefaultLogger,
+	}
+}
+
+func (j *ExportJob) Name() string {
+	return "question-export"
+}
+
+func (j *ExportJob) Run(ctx ejob.Context) error {
+	var args exportArgs
+	if err := json.NewDecoder(ctx.Request.Body).Decode(&args); err != nil {
+		return fmt.Errorf("解析 job-data 失败: %w", err)
+	}
+	name, data, err := j.svc.Export(ctx.Ctx, args.Qids, args.Format)
+	if err != nil {
+		return err
+	}
+	path := args.Path
+	if path == "" {
+		path = name
+	}
+	err = os.WriteFile(path, data, 0o644)
+	if err == nil {
+		j.logger.Info("导出问题成功", elog.String("path", path), elog.Int("count", len(args.Qids)))
+	}
+	return err
+}
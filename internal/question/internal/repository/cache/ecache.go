@@ -16,11 +16,17 @@ package cache
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/webook/internal/question/internal/domain"
 )
 
+// 设置更久的过期时间都可以，毕竟很少更新题库，而且发布和下架的时候会主动更新缓存
+const expiration = time.Minute * 30
+
 type QuestionECache struct {
 	ec ecache.Cache
 }
@@ -39,8 +45,66 @@ func (q *QuestionECache) GetTotal(ctx context.Context) (int64, error) {
 }
 
 func (q *QuestionECache) SetTotal(ctx context.Context, total int64) error {
-	// 设置更久的过期时间都可以，毕竟很少更新题库
-	return q.ec.Set(ctx, q.totalKey(), total, time.Minute*30)
+	return q.ec.Set(ctx, q.totalKey(), total, expiration)
+}
+
+func (q *QuestionECache) DelTotal(ctx context.Context) error {
+	_, err := q.ec.Delete(ctx, q.totalKey())
+	return err
+}
+
+func (q *QuestionECache) GetPubQuestion(ctx context.Context, qid int64) (domain.Question, error) {
+	var res domain.Question
+	err := q.get(ctx, q.pubKey(qid), &res)
+	return res, err
+}
+
+func (q *QuestionECache) SetPubQuestion(ctx context.Context, que domain.Question) error {
+	return q.set(ctx, q.pubKey(que.Id), que)
+}
+
+func (q *QuestionECache) DelPubQuestion(ctx context.Context, qid int64) error {
+	_, err := q.ec.Delete(ctx, q.pubKey(qid))
+	return err
+}
+
+func (q *QuestionECache) GetPubList(ctx context.Context) ([]domain.Question, error) {
+	var res []domain.Question
+	err := q.get(ctx, q.pubListKey(), &res)
+	return res, err
+}
+
+func (q *QuestionECache) SetPubList(ctx context.Context, qs []domain.Question) error {
+	return q.set(ctx, q.pubListKey(), qs)
+}
+
+func (q *QuestionECache) DelPubList(ctx context.Context) error {
+	_, err := q.ec.Delete(ctx, q.pubListKey())
+	return err
+}
+
+func (q *QuestionECache) get(ctx context.Context, key string, val any) error {
+	data, err := q.ec.Get(ctx, key).String()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), val)
+}
+
+func (q *QuestionECache) set(ctx context.Context, key string, val any) error {
+	data, err := json.Marshal(val)
+	if err != nil {
+		return err
+	}
+	return q.ec.Set(ctx, key, string(data), expiration)
+}
+
+func (q *QuestionECache) pubKey(qid int64) string {
+	return fmt.Sprintf("pub:%d", qid)
+}
+
+func (q *QuestionECache) pubListKey() string {
+	return "pub:list"
 }
 
 // 注意 Namespace 设置
@@ -14,9 +14,24 @@
 
 package cache
 
-import "context"
+import (
+	"context"
+
+	"github.com/ecodeclub/webook/internal/question/internal/domain"
+)
 
 type QuestionCache interface {
 	GetTotal(ctx context.Context) (int64, error)
 	SetTotal(ctx context.Context, total int64) error
+	DelTotal(ctx context.Context) error
+
+	// GetPubQuestion 线上库的问题，包含答案
+	GetPubQuestion(ctx context.Context, qid int64) (domain.Question, error)
+	SetPubQuestion(ctx context.Context, que domain.Question) error
+	DelPubQuestion(ctx context.Context, qid int64) error
+
+	// GetPubList 线上库按照 ID 倒序排列的最前面的一批问题，不包含答案
+	GetPubList(ctx context.Context) ([]domain.Question, error)
+	SetPubList(ctx context.Context, qs []domain.Question) error
+	DelPubList(ctx context.Context) error
 }
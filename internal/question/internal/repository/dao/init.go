@@ -22,7 +22,10 @@ func InitTables(db *egorm.Component) error {
 		&PublishQuestion{},
 		&AnswerElement{},
 		&PublishAnswerElement{},
+		&QuestionVersion{},
 		&QuestionSet{},
 		&QuestionSetQuestion{},
+		&PublishQuestionSet{},
+		&PublishQuestionSetQuestion{},
 	)
 }
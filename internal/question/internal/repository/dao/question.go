@@ -19,6 +19,7 @@ import (
 	"time"
 
 	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ekit/sqlx"
 	"github.com/ego-component/egorm"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
@@ -31,13 +32,44 @@ type QuestionDAO interface {
 	List(ctx context.Context, offset int, limit int) ([]Question, error)
 	Count(ctx context.Context) (int64, error)
 
-	Sync(ctx context.Context, que Question, eles []AnswerElement) (int64, error)
+	// Sync 将审核通过的制作库内容同步到线上库，并且记录一个版本，uid 是发布人
+	// 如果问题不存在或者不是审核通过的状态，返回 gorm.ErrRecordNotFound
+	Sync(ctx context.Context, qid int64, uid int64) error
+
+	// 审核流程，状态不对的时候都返回 gorm.ErrRecordNotFound
+	Submit(ctx context.Context, qid int64, reviewer int64) error
+	Review(ctx context.Context, qid int64, reviewer int64, status uint8, comment string) error
+	// Unpublish 下架，线上库的数据会保留，但是不会再出现在线上库的查询结果中
+	Unpublish(ctx context.Context, qid int64) error
+	ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]Question, error)
+	CountReview(ctx context.Context, reviewer int64) (int64, error)
 
 	// 线上库 API
 	PubList(ctx context.Context, offset int, limit int) ([]PublishQuestion, error)
 	PubCount(ctx context.Context) (int64, error)
 	GetPubByID(ctx context.Context, qid int64) (PublishQuestion, []PublishAnswerElement, error)
 	GetPubByIDs(ctx context.Context, qids []int64) ([]PublishQuestion, error)
+	// PubIDs 按照 id 升序返回线上库中 id 大于 minID 的问题 id，用于批量同步
+	PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error)
+
+	// 回收站 API，删除的时候问题会先放到回收站里面，过一段时间之后才会彻底删除
+	// Delete 删除问题，线上库的数据会被直接删掉。还在题集中的问题不能删除，返回 ErrQuestionInSet
+	// 问题不存在或者已经被删除的时候返回 gorm.ErrRecordNotFound
+	Delete(ctx context.Context, qid int64) error
+	ListDeleted(ctx context.Context, offset int, limit int) ([]Question, error)
+	CountDeleted(ctx context.Context) (int64, error)
+	// Restore 从回收站恢复，恢复之后是草稿状态，需要重新审核发布
+	// 问题不在回收站里面的时候返回 gorm.ErrRecordNotFound
+	Restore(ctx context.Context, qid int64) error
+	// Purge 彻底删除在 before 之前放进回收站的问题，一次最多删除 limit 个，返回删除的个数
+	Purge(ctx context.Context, before int64, limit int) (int64, error)
+
+	// 版本 API
+	ListVersions(ctx context.Context, qid int64, offset int, limit int) ([]QuestionVersion, error)
+	CountVersions(ctx context.Context, qid int64) (int64, error)
+	GetVersion(ctx context.Context, qid int64, version int64) (QuestionVersion, error)
+	// Rollback 将线上库回滚到 version 对应的快照，并且生成一个新的版本，返回新的版本号
+	Rollback(ctx context.Context, qid int64, version int64, uid int64) (int64, error)
 }
 
 type GORMQuestionDAO struct {
@@ -47,14 +79,14 @@ type GORMQuestionDAO struct {
 func (g *GORMQuestionDAO) GetPubByIDs(ctx context.Context, qids []int64) ([]PublishQuestion, error) {
 	var qs []PublishQuestion
 	db := g.db.WithContext(ctx)
-	err := db.Where("id IN ?", qids).Find(&qs).Error
+	err := db.Where("id IN ? AND status <> ?", qids, QuestionStatusUnpublished).Find(&qs).Error
 	return qs, err
 }
 
 func (g *GORMQuestionDAO) GetPubByID(ctx context.Context, qid int64) (PublishQuestion, []PublishAnswerElement, error) {
 	var q PublishQuestion
 	db := g.db.WithContext(ctx)
-	err := db.Where("id = ?", qid).First(&q).Error
+	err := db.Where("id = ? AND status <> ?", qid, QuestionStatusUnpublished).First(&q).Error
 	if err != nil {
 		return PublishQuestion{}, nil, err
 	}
@@ -67,9 +99,12 @@ func (g *GORMQuestionDAO) GetPubByID(ctx context.Context, qid int64) (PublishQue
 func (g *GORMQuestionDAO) Update(ctx context.Context, q Question, eles []AnswerElement) error {
 	now := time.Now().UnixMilli()
 	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		res := tx.Model(&Question{}).WithContext(ctx).Where("id = ?", q.Id).Updates(map[string]any{
+		// 修改之后需要重新审核
+		res := tx.Model(&Question{}).WithContext(ctx).Where("id = ? AND dtime = 0", q.Id).Updates(map[string]any{
 			"title":   q.Title,
+			"labels":  q.Labels,
 			"content": q.Content,
+			"status":  QuestionStatusDraft,
 			"utime":   now,
 		})
 		if res.Error != nil {
@@ -80,19 +115,6 @@ func (g *GORMQuestionDAO) Update(ctx context.Context, q Question, eles []AnswerE
 	})
 }
 
-func (g *GORMQuestionDAO) update(tx *gorm.DB, q Question, eles []AnswerElement) error {
-	now := time.Now().UnixMilli()
-	res := tx.Model(&q).Where("id = ?", q.Id).Updates(map[string]any{
-		"title":   q.Title,
-		"content": q.Content,
-		"utime":   now,
-	})
-	if res.Error != nil {
-		return res.Error
-	}
-	return g.saveEles(tx, eles)
-}
-
 func (g *GORMQuestionDAO) Create(ctx context.Context, q Question, eles []AnswerElement) (int64, error) {
 	var qid int64
 	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
@@ -104,6 +126,7 @@ func (g *GORMQuestionDAO) Create(ctx context.Context, q Question, eles []AnswerE
 }
 
 func (g *GORMQuestionDAO) create(tx *gorm.DB, q Question, eles []AnswerElement) (int64, error) {
+	q.Status = QuestionStatusDraft
 	err := tx.Create(&q).Error
 	if err != nil {
 		return 0, err
@@ -118,6 +141,7 @@ func (g *GORMQuestionDAO) create(tx *gorm.DB, q Question, eles []AnswerElement)
 func (g *GORMQuestionDAO) List(ctx context.Context, offset int, limit int) ([]Question, error) {
 	var res []Question
 	err := g.db.WithContext(ctx).
+		Where("dtime = 0").
 		Offset(offset).Limit(limit).
 		Order("id DESC").
 		Find(&res).Error
@@ -126,28 +150,43 @@ func (g *GORMQuestionDAO) List(ctx context.Context, offset int, limit int) ([]Qu
 
 func (g *GORMQuestionDAO) Count(ctx context.Context) (int64, error) {
 	var res int64
-	err := g.db.WithContext(ctx).Model(&Question{}).Select("COUNT(id)").Count(&res).Error
+	err := g.db.WithContext(ctx).Model(&Question{}).
+		Where("dtime = 0").
+		Select("COUNT(id)").Count(&res).Error
 	return res, err
 }
 
 func (g *GORMQuestionDAO) PubList(ctx context.Context, offset int, limit int) ([]PublishQuestion, error) {
 	var res []PublishQuestion
-	err := g.db.WithContext(ctx).Offset(offset).
-		Limit(limit).Order("id DESC").
+	err := g.db.WithContext(ctx).
+		Where("status <> ?", QuestionStatusUnpublished).
+		Offset(offset).Limit(limit).
+		Order("id DESC").
 		Find(&res).Error
 	return res, err
 }
 
+func (g *GORMQuestionDAO) PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error) {
+	var res []int64
+	err := g.db.WithContext(ctx).Model(&PublishQuestion{}).
+		Where("id > ? AND status <> ?", minID, QuestionStatusUnpublished).
+		Order("id ASC").Limit(limit).
+		Pluck("id", &res).Error
+	return res, err
+}
+
 func (g *GORMQuestionDAO) PubCount(ctx context.Context) (int64, error) {
 	var res int64
-	err := g.db.WithContext(ctx).Model(&PublishQuestion{}).Select("COUNT(id)").Count(&res).Error
+	err := g.db.WithContext(ctx).Model(&PublishQuestion{}).
+		Where("status <> ?", QuestionStatusUnpublished).
+		Select("COUNT(id)").Count(&res).Error
 	return res, err
 }
 
 func (g *GORMQuestionDAO) GetByID(ctx context.Context, id int64) (Question, []AnswerElement, error) {
 	var q Question
 	db := g.db.WithContext(ctx)
-	err := db.Where("id = ?", id).First(&q).Error
+	err := db.Where("id = ? AND dtime = 0", id).First(&q).Error
 	if err != nil {
 		return Question{}, nil, err
 	}
@@ -178,29 +217,323 @@ func (g *GORMQuestionDAO) saveLive(tx *gorm.DB, que PublishQuestion, eles []Publ
 	return err
 }
 
-func (g *GORMQuestionDAO) Sync(ctx context.Context, que Question, eles []AnswerElement) (int64, error) {
-	qid := que.Id
-	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		var err error
-		if que.Id > 0 {
-			err = g.update(tx, que, eles)
-		} else {
-			qid, err = g.create(tx, que, eles)
+func (g *GORMQuestionDAO) Sync(ctx context.Context, qid int64, uid int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Question{}).
+			Where("id = ? AND status = ? AND dtime = 0", qid, QuestionStatusApproved).
+			Updates(map[string]any{
+				"status": QuestionStatusPublished,
+				"utime":  time.Now().UnixMilli(),
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		var que Question
+		err := tx.Where("id = ?", qid).First(&que).Error
+		if err != nil {
+			return err
 		}
+		var eles []AnswerElement
+		err = tx.Where("qid = ?", qid).Order("type ASC").Find(&eles).Error
 		if err != nil {
 			return err
 		}
 		pubEles := slice.Map(eles, func(idx int, src AnswerElement) PublishAnswerElement {
-			// 强制将 id 设置为 0。因为前面的 update 或者 upsert 触发了 update 的时候，
-			// 即便是执行了更新，GIN 也会赋予一个 id，但是这个 id 是错误的 id。
-			// 我们依赖于唯一索引来更新
+			// 线上库依赖于唯一索引来更新，所以不能带上制作库的 id
+			src.Id = 0
+			return PublishAnswerElement(src)
+		})
+		err = g.saveLive(tx, PublishQuestion(que), pubEles)
+		if err != nil {
+			return err
+		}
+		_, err = g.saveVersion(tx, que, eles, uid, 0)
+		return err
+	})
+}
+
+func (g *GORMQuestionDAO) Submit(ctx context.Context, qid int64, reviewer int64) error {
+	res := g.db.WithContext(ctx).Model(&Question{}).
+		// 0 是引入审核流程之前的老数据，当成草稿处理
+		Where("id = ? AND dtime = 0 AND status IN ?", qid, []uint8{0, QuestionStatusDraft,
+			QuestionStatusRejected, QuestionStatusUnpublished}).
+		Updates(map[string]any{
+			"status":         QuestionStatusPendingReview,
+			"reviewer":       reviewer,
+			"review_comment": "",
+			"utime":          time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (g *GORMQuestionDAO) Review(ctx context.Context, qid int64, reviewer int64, status uint8, comment string) error {
+	res := g.db.WithContext(ctx).Model(&Question{}).
+		Where("id = ? AND reviewer = ? AND status = ? AND dtime = 0", qid, reviewer, QuestionStatusPendingReview).
+		Updates(map[string]any{
+			"status":         status,
+			"review_comment": comment,
+			"utime":          time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (g *GORMQuestionDAO) Unpublish(ctx context.Context, qid int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		res := tx.Model(&PublishQuestion{}).
+			Where("id = ? AND status <> ?", qid, QuestionStatusUnpublished).
+			Updates(map[string]any{
+				"status": QuestionStatusUnpublished,
+				"utime":  now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		// 制作库可能已经被修改过了，这种时候保留制作库的状态
+		return tx.Model(&Question{}).
+			Where("id = ? AND status = ?", qid, QuestionStatusPublished).
+			Updates(map[string]any{
+				"status": QuestionStatusUnpublished,
+				"utime":  now,
+			}).Error
+	})
+}
+
+func (g *GORMQuestionDAO) Delete(ctx context.Context, qid int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 先更新 dtime 锁住问题，再检查题集，
+		// UpdateQuestionsByID 也会锁住问题，所以两者不会同时成功
+		now := time.Now().UnixMilli()
+		res := tx.Model(&Question{}).
+			Where("id = ? AND dtime = 0", qid).
+			Updates(map[string]any{
+				"dtime": now,
+				"utime": now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		// 回收站里面的题集不算，题集彻底删除的时候会一并删除关联关系
+		var cnt int64
+		err := tx.Model(&QuestionSetQuestion{}).
+			Joins("JOIN question_sets ON question_sets.id = question_set_questions.qs_id").
+			Where("question_set_questions.q_id = ? AND question_sets.dtime = 0", qid).
+			Count(&cnt).Error
+		if err != nil {
+			return err
+		}
+		if cnt > 0 {
+			return ErrQuestionInSet
+		}
+		// 线上库直接删除，恢复之后重新发布的时候会再同步过去
+		err = tx.Where("id = ?", qid).Delete(&PublishQuestion{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("qid = ?", qid).Delete(&PublishAnswerElement{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("q_id = ?", qid).Delete(&PublishQuestionSetQuestion{}).Error
+	})
+}
+
+func (g *GORMQuestionDAO) ListDeleted(ctx context.Context, offset int, limit int) ([]Question, error) {
+	var res []Question
+	err := g.db.WithContext(ctx).
+		Where("dtime > 0").
+		Offset(offset).Limit(limit).
+		Order("dtime DESC, id DESC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionDAO) CountDeleted(ctx context.Context) (int64, error) {
+	var res int64
+	err := g.db.WithContext(ctx).Model(&Question{}).
+		Where("dtime > 0").
+		Select("COUNT(id)").Count(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionDAO) Restore(ctx context.Context, qid int64) error {
+	res := g.db.WithContext(ctx).Model(&Question{}).
+		Where("id = ? AND dtime > 0", qid).
+		Updates(map[string]any{
+			"status": QuestionStatusDraft,
+			"dtime":  0,
+			"utime":  time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (g *GORMQuestionDAO) Purge(ctx context.Context, before int64, limit int) (int64, error) {
+	var qids []int64
+	err := g.db.WithContext(ctx).Model(&Question{}).
+		Where("dtime > 0 AND dtime < ?", before).
+		Order("id ASC").Limit(limit).
+		Pluck("id", &qids).Error
+	if err != nil || len(qids) == 0 {
+		return 0, err
+	}
+	// 线上库的数据在删除的时候就已经删掉了
+	err = g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 回收站里面的题集不会阻止删除问题，所以这里可能还有关联关系
+		err := tx.Where("q_id IN ?", qids).Delete(&QuestionSetQuestion{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("qid IN ?", qids).Delete(&AnswerElement{}).Error
+		if err != nil {
+			return err
+		}
+		err = tx.Where("qid IN ?", qids).Delete(&QuestionVersion{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", qids).Delete(&Question{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(qids)), nil
+}
+
+func (g *GORMQuestionDAO) ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]Question, error) {
+	var res []Question
+	err := g.db.WithContext(ctx).
+		Where("reviewer = ? AND status = ? AND dtime = 0", reviewer, QuestionStatusPendingReview).
+		Offset(offset).Limit(limit).
+		Order("id DESC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionDAO) CountReview(ctx context.Context, reviewer int64) (int64, error) {
+	var res int64
+	err := g.db.WithContext(ctx).Model(&Question{}).
+		Where("reviewer = ? AND status = ? AND dtime = 0", reviewer, QuestionStatusPendingReview).
+		Count(&res).Error
+	return res, err
+}
+
+// saveVersion 记录一个快照，版本号是当前最大的版本号 + 1
+// 并发发布同一个问题的时候，会因为唯一索引冲突而失败
+func (g *GORMQuestionDAO) saveVersion(tx *gorm.DB, que Question, eles []AnswerElement, uid int64, base int64) (int64, error) {
+	var maxVersion int64
+	err := tx.Model(&QuestionVersion{}).
+		Select("COALESCE(MAX(version), 0)").
+		Where("qid = ?", que.Id).Scan(&maxVersion).Error
+	if err != nil {
+		return 0, err
+	}
+	snapshot := slice.Map(eles, func(idx int, src AnswerElement) AnswerElement {
+		src.Id = 0
+		src.Qid = que.Id
+		return src
+	})
+	v := QuestionVersion{
+		Qid:         que.Id,
+		Version:     maxVersion + 1,
+		BaseVersion: base,
+		Uid:         uid,
+		Labels:      que.Labels,
+		Title:       que.Title,
+		Content:     que.Content,
+		Elements:    sqlx.JsonColumn[[]AnswerElement]{Val: snapshot, Valid: true},
+		Ctime:       time.Now().UnixMilli(),
+	}
+	return v.Version, tx.Create(&v).Error
+}
+
+func (g *GORMQuestionDAO) ListVersions(ctx context.Context, qid int64, offset int, limit int) ([]QuestionVersion, error) {
+	var res []QuestionVersion
+	err := g.db.WithContext(ctx).Where("qid = ?", qid).
+		Offset(offset).Limit(limit).
+		Order("version DESC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionDAO) CountVersions(ctx context.Context, qid int64) (int64, error) {
+	var res int64
+	err := g.db.WithContext(ctx).Model(&QuestionVersion{}).
+		Where("qid = ?", qid).Count(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionDAO) GetVersion(ctx context.Context, qid int64, version int64) (QuestionVersion, error) {
+	var res QuestionVersion
+	err := g.db.WithContext(ctx).
+		Where("qid = ? AND version = ?", qid, version).
+		First(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionDAO) Rollback(ctx context.Context, qid int64, version int64, uid int64) (int64, error) {
+	var newVersion int64
+	err := g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var v QuestionVersion
+		err := tx.Where("qid = ? AND version = ?", qid, version).First(&v).Error
+		if err != nil {
+			return err
+		}
+		// 只回滚内容，线上库的状态保持不变
+		var pub PublishQuestion
+		err = tx.Where("id = ?", qid).First(&pub).Error
+		if err != nil {
+			return err
+		}
+		now := time.Now().UnixMilli()
+		pub.Labels = v.Labels
+		pub.Title = v.Title
+		pub.Content = v.Content
+		pub.Utime = now
+		eles := slice.Map(v.Elements.Val, func(idx int, src AnswerElement) AnswerElement {
 			src.Id = 0
 			src.Qid = qid
+			src.Ctime = now
+			src.Utime = now
+			return src
+		})
+		pubEles := slice.Map(eles, func(idx int, src AnswerElement) PublishAnswerElement {
 			return PublishAnswerElement(src)
 		})
-		return g.saveLive(tx, PublishQuestion(que), pubEles)
+		err = g.saveLive(tx, pub, pubEles)
+		if err != nil {
+			return err
+		}
+		newVersion, err = g.saveVersion(tx, Question(pub), eles, uid, version)
+		return err
 	})
-	return qid, err
+	return newVersion, err
 }
 
 func NewGORMQuestionDAO(db *egorm.Component) QuestionDAO {
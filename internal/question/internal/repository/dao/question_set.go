@@ -19,12 +19,15 @@ import (
 	"errors"
 	"time"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ego-component/egorm"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	ErrInvalidQuestionID = errors.New("问题ID非法")
+	ErrQuestionInSet     = errors.New("问题还在题集中")
 )
 
 type QuestionSetDAO interface {
@@ -32,11 +35,29 @@ type QuestionSetDAO interface {
 	GetByID(ctx context.Context, id int64) (QuestionSet, error)
 
 	GetQuestionsByID(ctx context.Context, id int64) ([]Question, error)
+	// UpdateQuestionsByID 覆盖题集中的问题，问题不存在或者已经被删除的时候返回 ErrInvalidQuestionID
 	UpdateQuestionsByID(ctx context.Context, id int64, qids []int64) error
 
 	Count(ctx context.Context) (int64, error)
 	List(ctx context.Context, offset, limit int) ([]QuestionSet, error)
 	UpdateNonZero(ctx context.Context, set QuestionSet) error
+
+	// Sync 将题集和题集中题目的顺序同步到线上库
+	Sync(ctx context.Context, id int64) error
+	GetPubByID(ctx context.Context, id int64) (PublishQuestionSet, error)
+	// GetPubQuestionsByID 按照题集中的顺序返回已经发布的题目
+	GetPubQuestionsByID(ctx context.Context, id int64) ([]PublishQuestion, error)
+	PubCount(ctx context.Context) (int64, error)
+	PubList(ctx context.Context, offset, limit int) ([]PublishQuestionSet, error)
+
+	// Delete 删除题集，线上库的题集会被直接删掉，题集不存在或者已经被删除的时候返回 gorm.ErrRecordNotFound
+	Delete(ctx context.Context, id int64) error
+	ListDeleted(ctx context.Context, offset, limit int) ([]QuestionSet, error)
+	CountDeleted(ctx context.Context) (int64, error)
+	// Restore 从回收站恢复，需要重新发布，题集不在回收站里面的时候返回 gorm.ErrRecordNotFound
+	Restore(ctx context.Context, id int64) error
+	// Purge 彻底删除在 before 之前放进回收站的题集，一次最多删除 limit 个，返回删除的个数
+	Purge(ctx context.Context, before int64, limit int) (int64, error)
 }
 
 type GORMQuestionSetDAO struct {
@@ -45,7 +66,7 @@ type GORMQuestionSetDAO struct {
 
 func (g *GORMQuestionSetDAO) UpdateNonZero(ctx context.Context, set QuestionSet) error {
 	set.Utime = time.Now().UnixMilli()
-	return g.db.WithContext(ctx).Where("id = ?", set.Id).Updates(set).Error
+	return g.db.WithContext(ctx).Where("id = ? AND dtime = 0", set.Id).Updates(set).Error
 }
 
 func (g *GORMQuestionSetDAO) Create(ctx context.Context, qs QuestionSet) (int64, error) {
@@ -59,7 +80,7 @@ func (g *GORMQuestionSetDAO) Create(ctx context.Context, qs QuestionSet) (int64,
 
 func (g *GORMQuestionSetDAO) GetByID(ctx context.Context, id int64) (QuestionSet, error) {
 	var qs QuestionSet
-	if err := g.db.WithContext(ctx).First(&qs, "id = ?", id).Error; err != nil {
+	if err := g.db.WithContext(ctx).First(&qs, "id = ? AND dtime = 0", id).Error; err != nil {
 		return QuestionSet{}, err
 	}
 	return qs, nil
@@ -68,22 +89,24 @@ func (g *GORMQuestionSetDAO) GetByID(ctx context.Context, id int64) (QuestionSet
 func (g *GORMQuestionSetDAO) GetQuestionsByID(ctx context.Context, id int64) ([]Question, error) {
 	var qsq []QuestionSetQuestion
 	tx := g.db.WithContext(ctx)
-	if err := tx.Find(&qsq, "qs_id = ?", id).Error; err != nil {
+	if err := tx.Where("qs_id = ?", id).Order("sort ASC, id ASC").Find(&qsq).Error; err != nil {
 		return nil, err
 	}
-	questionIDs := make([]int64, len(qsq))
-	for i := range qsq {
-		questionIDs[i] = qsq[i].QID
-	}
+	questionIDs := slice.Map(qsq, func(idx int, src QuestionSetQuestion) int64 {
+		return src.QID
+	})
+	// 回收站里面的题目不展示
 	var q []Question
-	err := tx.WithContext(ctx).Where("id IN ?", questionIDs).Order("id ASC").Find(&q).Error
-	return q, err
+	err := tx.WithContext(ctx).Where("id IN ? AND dtime = 0", questionIDs).Find(&q).Error
+	return sortByIDs(q, questionIDs, func(src Question) int64 {
+		return src.Id
+	}), err
 }
 
 func (g *GORMQuestionSetDAO) UpdateQuestionsByID(ctx context.Context, id int64, qids []int64) error {
 	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		var qs QuestionSet
-		if err := tx.WithContext(ctx).First(&qs, "id = ? ", id).Error; err != nil {
+		if err := tx.WithContext(ctx).First(&qs, "id = ? AND dtime = 0", id).Error; err != nil {
 			return err
 		}
 		// 全部删除
@@ -95,6 +118,20 @@ func (g *GORMQuestionSetDAO) UpdateQuestionsByID(ctx context.Context, id int64,
 			return nil
 		}
 
+		// 锁住问题，避免问题在加入题集的同时被删除
+		var validIDs []int64
+		err := tx.Model(&Question{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ? AND dtime = 0", qids).
+			Pluck("id", &validIDs).Error
+		if err != nil {
+			return err
+		}
+		if len(validIDs) != len(slice.ToMap(qids, func(element int64) int64 {
+			return element
+		})) {
+			return ErrInvalidQuestionID
+		}
+
 		// 重新创建
 		now := time.Now().UnixMilli()
 		var newQuestions []QuestionSetQuestion
@@ -102,6 +139,7 @@ func (g *GORMQuestionSetDAO) UpdateQuestionsByID(ctx context.Context, id int64,
 			newQuestions = append(newQuestions, QuestionSetQuestion{
 				QSID:  id,
 				QID:   qids[i],
+				Sort:  i,
 				Ctime: now,
 				Utime: now,
 			})
@@ -116,17 +154,170 @@ func (g *GORMQuestionSetDAO) UpdateQuestionsByID(ctx context.Context, id int64,
 func (g *GORMQuestionSetDAO) Count(ctx context.Context) (int64, error) {
 	var res int64
 	db := g.db.WithContext(ctx).Model(&QuestionSet{})
-	err := db.Select("COUNT(id)").Count(&res).Error
+	err := db.Where("dtime = 0").Select("COUNT(id)").Count(&res).Error
 	return res, err
 }
 
 func (g *GORMQuestionSetDAO) List(ctx context.Context, offset, limit int) ([]QuestionSet, error) {
 	var res []QuestionSet
 	db := g.db.WithContext(ctx)
-	err := db.Offset(offset).Limit(limit).Order("id DESC").Find(&res).Error
+	err := db.Where("dtime = 0").Offset(offset).Limit(limit).Order("id DESC").Find(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionSetDAO) Sync(ctx context.Context, id int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var qs QuestionSet
+		if err := tx.First(&qs, "id = ? AND dtime = 0", id).Error; err != nil {
+			return err
+		}
+		var qsq []QuestionSetQuestion
+		if err := tx.Where("qs_id = ?", id).Order("sort ASC, id ASC").Find(&qsq).Error; err != nil {
+			return err
+		}
+		pubQs := PublishQuestionSet(qs)
+		if err := tx.Save(&pubQs).Error; err != nil {
+			return err
+		}
+		// 线上库的题目关系整体覆盖
+		if err := tx.Where("qs_id = ?", id).Delete(&PublishQuestionSetQuestion{}).Error; err != nil {
+			return err
+		}
+		if len(qsq) == 0 {
+			return nil
+		}
+		pubQsq := slice.Map(qsq, func(idx int, src QuestionSetQuestion) PublishQuestionSetQuestion {
+			src.Id = 0
+			return PublishQuestionSetQuestion(src)
+		})
+		return tx.Create(&pubQsq).Error
+	})
+}
+
+func (g *GORMQuestionSetDAO) GetPubByID(ctx context.Context, id int64) (PublishQuestionSet, error) {
+	var qs PublishQuestionSet
+	err := g.db.WithContext(ctx).First(&qs, "id = ?", id).Error
+	return qs, err
+}
+
+func (g *GORMQuestionSetDAO) GetPubQuestionsByID(ctx context.Context, id int64) ([]PublishQuestion, error) {
+	var qsq []PublishQuestionSetQuestion
+	db := g.db.WithContext(ctx)
+	if err := db.Where("qs_id = ?", id).Order("sort ASC, id ASC").Find(&qsq).Error; err != nil {
+		return nil, err
+	}
+	questionIDs := slice.Map(qsq, func(idx int, src PublishQuestionSetQuestion) int64 {
+		return src.QID
+	})
+	// 下架或者还没发布的题目不会出现在线上题集里面
+	var q []PublishQuestion
+	err := db.Where("id IN ? AND status <> ?", questionIDs, QuestionStatusUnpublished).
+		Find(&q).Error
+	return sortByIDs(q, questionIDs, func(src PublishQuestion) int64 {
+		return src.Id
+	}), err
+}
+
+func (g *GORMQuestionSetDAO) PubCount(ctx context.Context) (int64, error) {
+	var res int64
+	err := g.db.WithContext(ctx).Model(&PublishQuestionSet{}).Select("COUNT(id)").Count(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionSetDAO) PubList(ctx context.Context, offset, limit int) ([]PublishQuestionSet, error) {
+	var res []PublishQuestionSet
+	err := g.db.WithContext(ctx).Offset(offset).Limit(limit).Order("id DESC").Find(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionSetDAO) Delete(ctx context.Context, id int64) error {
+	return g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UnixMilli()
+		res := tx.Model(&QuestionSet{}).
+			Where("id = ? AND dtime = 0", id).
+			Updates(map[string]any{
+				"dtime": now,
+				"utime": now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		// 制作库的题目关系保留下来，恢复的时候还能用
+		if err := tx.Where("id = ?", id).Delete(&PublishQuestionSet{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("qs_id = ?", id).Delete(&PublishQuestionSetQuestion{}).Error
+	})
+}
+
+func (g *GORMQuestionSetDAO) ListDeleted(ctx context.Context, offset, limit int) ([]QuestionSet, error) {
+	var res []QuestionSet
+	err := g.db.WithContext(ctx).Where("dtime > 0").
+		Offset(offset).Limit(limit).
+		Order("dtime DESC, id DESC").
+		Find(&res).Error
+	return res, err
+}
+
+func (g *GORMQuestionSetDAO) CountDeleted(ctx context.Context) (int64, error) {
+	var res int64
+	err := g.db.WithContext(ctx).Model(&QuestionSet{}).
+		Where("dtime > 0").Select("COUNT(id)").Count(&res).Error
 	return res, err
 }
 
+func (g *GORMQuestionSetDAO) Restore(ctx context.Context, id int64) error {
+	res := g.db.WithContext(ctx).Model(&QuestionSet{}).
+		Where("id = ? AND dtime > 0", id).
+		Updates(map[string]any{
+			"dtime": 0,
+			"utime": time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (g *GORMQuestionSetDAO) Purge(ctx context.Context, before int64, limit int) (int64, error) {
+	var ids []int64
+	err := g.db.WithContext(ctx).Model(&QuestionSet{}).
+		Where("dtime > 0 AND dtime < ?", before).
+		Order("id ASC").Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	err = g.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("qs_id IN ?", ids).Delete(&QuestionSetQuestion{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&QuestionSet{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
+// sortByIDs 按照 ids 的顺序重排 src，不在 ids 里面的会被丢弃
+func sortByIDs[T any](src []T, ids []int64, idOf func(src T) int64) []T {
+	m := make(map[int64]T, len(src))
+	for _, s := range src {
+		m[idOf(s)] = s
+	}
+	return slice.FilterMap(ids, func(idx int, id int64) (T, bool) {
+		t, ok := m[id]
+		return t, ok
+	})
+}
+
 func NewGORMQuestionSetDAO(db *egorm.Component) QuestionSetDAO {
 	return &GORMQuestionSetDAO{db: db}
 }
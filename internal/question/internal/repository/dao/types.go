@@ -27,12 +27,43 @@ type Question struct {
 	// 面试题目内容
 	Content string
 
+	// 状态，制作库和线上库共用这个字段
+	Status uint8 `gorm:"index"`
+	// 审核人
+	Reviewer int64 `gorm:"index"`
+	// 最近一次的审核意见
+	ReviewComment string
+
 	Ctime int64
 	Utime int64 `gorm:"index"`
+	// 删除时间，0 代表没有删除，删除之后会先放在回收站里面
+	// 线上库不使用这个字段，删除的时候线上库的数据会被直接删掉
+	Dtime int64 `gorm:"index"`
 }
 
 type PublishQuestion Question
 
+// QuestionVersion 线上库的快照。每发布一次（包括回滚）就生成一个新版本，
+// 生成之后不会再被修改
+type QuestionVersion struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Qid int64 `gorm:"uniqueIndex:qid_version"`
+	// 版本号，同一个问题从 1 开始递增
+	Version int64 `gorm:"uniqueIndex:qid_version"`
+	// 如果是回滚产生的版本，那么记录的是回滚到的版本，正常发布为 0
+	BaseVersion int64
+	// 发布人
+	Uid int64
+
+	Labels  sqlx.JsonColumn[[]string] `gorm:"type:varchar(512)"`
+	Title   string                    `gorm:"type:varchar(512)"`
+	Content string
+	// 发布时的回答，固定是四个部分
+	Elements sqlx.JsonColumn[[]AnswerElement]
+
+	Ctime int64
+}
+
 type PublishAnswerElement AnswerElement
 
 // AnswerElement 回答，对于一个问题来说，回答分成好几个部分
@@ -76,17 +107,34 @@ type QuestionSet struct {
 
 	Ctime int64
 	Utime int64 `gorm:"index"`
+	// 删除时间，0 代表没有删除，和 Question 一样线上库不使用这个字段
+	Dtime int64 `gorm:"index"`
 }
 
 // QuestionSetQuestion 题集问题 —— 题集与题目的关联关系
 type QuestionSetQuestion struct {
-	Id    int64 `gorm:"primaryKey,autoIncrement"`
-	QSID  int64 `gorm:"uniqueIndex:qsid_qid"`
-	QID   int64 `gorm:"uniqueIndex:qsid_qid"`
+	Id   int64 `gorm:"primaryKey,autoIncrement"`
+	QSID int64 `gorm:"uniqueIndex:qsid_qid"`
+	QID  int64 `gorm:"uniqueIndex:qsid_qid"`
+	// 题目在题集中的顺序，越小越靠前
+	Sort  int
 	Ctime int64
 	Utime int64 `gorm:"index"`
 }
 
+type PublishQuestionSet QuestionSet
+
+type PublishQuestionSetQuestion QuestionSetQuestion
+
+const (
+	QuestionStatusDraft         = iota + 1 // 草稿
+	QuestionStatusPendingReview            // 待审核
+	QuestionStatusApproved                 // 审核通过
+	QuestionStatusRejected                 // 审核拒绝
+	QuestionStatusPublished                // 已发布
+	QuestionStatusUnpublished              // 已下架
+)
+
 const (
 	AnswerElementTypeUnknown = iota
 	AnswerElementTypeAnalysis
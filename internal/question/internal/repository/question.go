@@ -16,6 +16,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ecodeclub/ekit/sqlx"
@@ -23,6 +25,8 @@ import (
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/cache"
 	"github.com/gotomicro/ego/core/elog"
+	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
 
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/dao"
@@ -31,8 +35,13 @@ import (
 type Repository interface {
 	PubList(ctx context.Context, offset int, limit int) ([]domain.Question, error)
 	PubTotal(ctx context.Context) (int64, error)
-	// Sync 保存到制作库，而后同步到线上库
-	Sync(ctx context.Context, que *domain.Question) (int64, error)
+	// Sync 将审核通过的制作库内容同步到线上库，uid 是发布人
+	Sync(ctx context.Context, qid int64, uid int64) error
+	Submit(ctx context.Context, qid int64, reviewer int64) error
+	Review(ctx context.Context, qid int64, reviewer int64, status uint8, comment string) error
+	Unpublish(ctx context.Context, qid int64) error
+	ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Question, error)
+	TotalReview(ctx context.Context, reviewer int64) (int64, error)
 	List(ctx context.Context, offset int, limit int) ([]domain.Question, error)
 	Total(ctx context.Context) (int64, error)
 	Update(ctx context.Context, question *domain.Question) error
@@ -40,14 +49,40 @@ type Repository interface {
 	GetById(ctx context.Context, qid int64) (domain.Question, error)
 	GetPubByID(ctx context.Context, qid int64) (domain.Question, error)
 	GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Question, error)
+	PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error)
+
+	// Delete 放进回收站，同时删除线上库
+	Delete(ctx context.Context, qid int64) error
+	ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Question, error)
+	TotalDeleted(ctx context.Context) (int64, error)
+	Restore(ctx context.Context, qid int64) error
+	// Purge 彻底删除在 before 之前放进回收站的问题，返回删除的个数
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
+
+	ListVersions(ctx context.Context, qid int64, offset int, limit int) ([]domain.QuestionVersion, error)
+	TotalVersions(ctx context.Context, qid int64) (int64, error)
+	GetVersion(ctx context.Context, qid int64, version int64) (domain.QuestionVersion, error)
+	// Rollback 将线上库回滚到指定版本，返回新生成的版本号
+	Rollback(ctx context.Context, qid int64, version int64, uid int64) (int64, error)
 }
 
+var (
+	ErrVersionNotFound = errors.New("问题版本不存在")
+	ErrInvalidStatus   = errors.New("问题不存在或者当前状态不允许该操作")
+	ErrQuestionInSet   = dao.ErrQuestionInSet
+)
+
+// pubListCacheSize 缓存线上库最前面的这么多条问题，落在这个范围内的分页都直接从缓存中截取
+const pubListCacheSize = 100
+
 // CachedRepository 支持缓存的 repository 实现
-// 虽然在 Save 的时候，理论上也需要更新缓存，但是没有必要
+// 缓存的只有线上库，所以 Save 的时候不需要更新缓存，而在发布、回滚、下架的时候更新
 type CachedRepository struct {
 	dao    dao.QuestionDAO
 	cache  cache.QuestionCache
 	logger *elog.Component
+	// 缓存未命中的时候只允许一个请求回源，避免缓存击穿
+	sf singleflight.Group
 }
 
 func (c *CachedRepository) GetPubByIDs(ctx context.Context, qids []int64) ([]domain.Question, error) {
@@ -58,7 +93,30 @@ func (c *CachedRepository) GetPubByIDs(ctx context.Context, qids []int64) ([]dom
 }
 
 func (c *CachedRepository) GetPubByID(ctx context.Context, qid int64) (domain.Question, error) {
-	// 可以缓存
+	que, err := c.cache.GetPubQuestion(ctx, qid)
+	if err == nil {
+		return que, nil
+	}
+	// 结果是所有等待的请求共享的，不能因为第一个请求被取消就让大家一起失败
+	sfCtx := context.WithoutCancel(ctx)
+	val, err, _ := c.sf.Do(fmt.Sprintf("pub:%d", qid), func() (any, error) {
+		que, err := c.pubQuestion(sfCtx, qid)
+		if err != nil {
+			return domain.Question{}, err
+		}
+		err = c.cache.SetPubQuestion(sfCtx, que)
+		if err != nil {
+			c.logger.Error("更新缓存中的问题失败", elog.FieldErr(err), elog.Int64("qid", qid))
+		}
+		return que, nil
+	})
+	if err != nil {
+		return domain.Question{}, err
+	}
+	return val.(domain.Question), nil
+}
+
+func (c *CachedRepository) pubQuestion(ctx context.Context, qid int64) (domain.Question, error) {
 	data, pubEles, err := c.dao.GetPubByID(ctx, qid)
 	if err != nil {
 		return domain.Question{}, err
@@ -87,10 +145,104 @@ func (c *CachedRepository) Create(ctx context.Context, question *domain.Question
 	return c.dao.Create(ctx, q, eles)
 }
 
-func (c *CachedRepository) Sync(ctx context.Context, que *domain.Question) (int64, error) {
-	// 理论上来说要更新缓存，但是我懒得写了
-	q, eles := c.toEntity(que)
-	return c.dao.Sync(ctx, q, eles)
+func (c *CachedRepository) Sync(ctx context.Context, qid int64, uid int64) error {
+	err := c.statusErr(c.dao.Sync(ctx, qid, uid), qid)
+	if err == nil {
+		c.refreshPubCache(ctx, qid)
+	}
+	return err
+}
+
+// refreshPubCache 线上库发生变化之后预热问题详情，列表和总数等下一次查询的时候再加载
+// 缓存出错只记录日志，等过期之后自然就一致了
+func (c *CachedRepository) refreshPubCache(ctx context.Context, qid int64) {
+	que, err := c.pubQuestion(ctx, qid)
+	if err == nil {
+		err = c.cache.SetPubQuestion(ctx, que)
+	}
+	if err != nil {
+		c.logger.Error("预热缓存中的问题失败", elog.FieldErr(err), elog.Int64("qid", qid))
+	}
+	c.evictPubList(ctx)
+}
+
+func (c *CachedRepository) evictPubList(ctx context.Context) {
+	if err := c.cache.DelPubList(ctx); err != nil {
+		c.logger.Error("删除缓存中的问题列表失败", elog.FieldErr(err))
+	}
+	if err := c.cache.DelTotal(ctx); err != nil {
+		c.logger.Error("删除缓存中的总数失败", elog.FieldErr(err))
+	}
+}
+
+func (c *CachedRepository) Submit(ctx context.Context, qid int64, reviewer int64) error {
+	return c.statusErr(c.dao.Submit(ctx, qid, reviewer), qid)
+}
+
+func (c *CachedRepository) Review(ctx context.Context, qid int64, reviewer int64, status uint8, comment string) error {
+	return c.statusErr(c.dao.Review(ctx, qid, reviewer, status, comment), qid)
+}
+
+func (c *CachedRepository) Unpublish(ctx context.Context, qid int64) error {
+	err := c.statusErr(c.dao.Unpublish(ctx, qid), qid)
+	if err != nil {
+		return err
+	}
+	if er := c.cache.DelPubQuestion(ctx, qid); er != nil {
+		c.logger.Error("删除缓存中的问题失败", elog.FieldErr(er), elog.Int64("qid", qid))
+	}
+	c.evictPubList(ctx)
+	return nil
+}
+
+func (c *CachedRepository) Delete(ctx context.Context, qid int64) error {
+	err := c.statusErr(c.dao.Delete(ctx, qid), qid)
+	if err != nil {
+		return err
+	}
+	if er := c.cache.DelPubQuestion(ctx, qid); er != nil {
+		c.logger.Error("删除缓存中的问题失败", elog.FieldErr(er), elog.Int64("qid", qid))
+	}
+	c.evictPubList(ctx)
+	return nil
+}
+
+func (c *CachedRepository) ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Question, error) {
+	qs, err := c.dao.ListDeleted(ctx, offset, limit)
+	return slice.Map(qs, func(idx int, src dao.Question) domain.Question {
+		return c.toDomain(src)
+	}), err
+}
+
+func (c *CachedRepository) TotalDeleted(ctx context.Context) (int64, error) {
+	return c.dao.CountDeleted(ctx)
+}
+
+func (c *CachedRepository) Restore(ctx context.Context, qid int64) error {
+	return c.statusErr(c.dao.Restore(ctx, qid), qid)
+}
+
+func (c *CachedRepository) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return c.dao.Purge(ctx, before.UnixMilli(), limit)
+}
+
+// statusErr 审核流程中 DAO 用 gorm.ErrRecordNotFound 表示状态不对
+func (c *CachedRepository) statusErr(err error, qid int64) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: qid %d", ErrInvalidStatus, qid)
+	}
+	return err
+}
+
+func (c *CachedRepository) ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Question, error) {
+	qs, err := c.dao.ListReview(ctx, reviewer, offset, limit)
+	return slice.Map(qs, func(idx int, src dao.Question) domain.Question {
+		return c.toDomain(src)
+	}), err
+}
+
+func (c *CachedRepository) TotalReview(ctx context.Context, reviewer int64) (int64, error) {
+	return c.dao.CountReview(ctx, reviewer)
 }
 
 func (c *CachedRepository) List(ctx context.Context, offset int, limit int) ([]domain.Question, error) {
@@ -104,8 +256,50 @@ func (c *CachedRepository) Total(ctx context.Context) (int64, error) {
 	return c.dao.Count(ctx)
 }
 
+func (c *CachedRepository) PubIDs(ctx context.Context, minID int64, limit int) ([]int64, error) {
+	return c.dao.PubIDs(ctx, minID, limit)
+}
+
 func (c *CachedRepository) PubList(ctx context.Context, offset int, limit int) ([]domain.Question, error) {
-	// TODO 缓存第一页
+	offset = max(offset, 0)
+	if limit <= 0 {
+		return []domain.Question{}, nil
+	}
+	if offset+limit > pubListCacheSize {
+		return c.pubList(ctx, offset, limit)
+	}
+	qs, err := c.cachedPubList(ctx)
+	if err != nil {
+		return nil, err
+	}
+	end := min(offset+limit, len(qs))
+	return qs[min(offset, end):end], nil
+}
+
+func (c *CachedRepository) cachedPubList(ctx context.Context) ([]domain.Question, error) {
+	qs, err := c.cache.GetPubList(ctx)
+	if err == nil {
+		return qs, nil
+	}
+	sfCtx := context.WithoutCancel(ctx)
+	val, err, _ := c.sf.Do("pub:list", func() (any, error) {
+		qs, err := c.pubList(sfCtx, 0, pubListCacheSize)
+		if err != nil {
+			return nil, err
+		}
+		err = c.cache.SetPubList(sfCtx, qs)
+		if err != nil {
+			c.logger.Error("更新缓存中的问题列表失败", elog.FieldErr(err))
+		}
+		return qs, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return val.([]domain.Question), nil
+}
+
+func (c *CachedRepository) pubList(ctx context.Context, offset int, limit int) ([]domain.Question, error) {
 	qs, err := c.dao.PubList(ctx, offset, limit)
 	return slice.Map(qs, func(idx int, src dao.PublishQuestion) domain.Question {
 		return c.toDomain(dao.Question(src))
@@ -128,6 +322,57 @@ func (c *CachedRepository) PubTotal(ctx context.Context) (int64, error) {
 	return res, nil
 }
 
+func (c *CachedRepository) ListVersions(ctx context.Context, qid int64, offset int, limit int) ([]domain.QuestionVersion, error) {
+	vs, err := c.dao.ListVersions(ctx, qid, offset, limit)
+	return slice.Map(vs, func(idx int, src dao.QuestionVersion) domain.QuestionVersion {
+		return c.versionToDomain(src)
+	}), err
+}
+
+func (c *CachedRepository) TotalVersions(ctx context.Context, qid int64) (int64, error) {
+	return c.dao.CountVersions(ctx, qid)
+}
+
+func (c *CachedRepository) GetVersion(ctx context.Context, qid int64, version int64) (domain.QuestionVersion, error) {
+	v, err := c.dao.GetVersion(ctx, qid, version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.QuestionVersion{}, fmt.Errorf("%w: qid %d, version %d", ErrVersionNotFound, qid, version)
+	}
+	if err != nil {
+		return domain.QuestionVersion{}, err
+	}
+	return c.versionToDomain(v), nil
+}
+
+func (c *CachedRepository) Rollback(ctx context.Context, qid int64, version int64, uid int64) (int64, error) {
+	res, err := c.dao.Rollback(ctx, qid, version, uid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, fmt.Errorf("%w: qid %d, version %d", ErrVersionNotFound, qid, version)
+	}
+	if err == nil {
+		c.refreshPubCache(ctx, qid)
+	}
+	return res, err
+}
+
+func (c *CachedRepository) versionToDomain(v dao.QuestionVersion) domain.QuestionVersion {
+	que := c.toDomainWithAnswer(dao.Question{
+		Id:      v.Qid,
+		Uid:     v.Uid,
+		Labels:  v.Labels,
+		Title:   v.Title,
+		Content: v.Content,
+		Utime:   v.Ctime,
+	}, v.Elements.Val)
+	return domain.QuestionVersion{
+		Version:     v.Version,
+		BaseVersion: v.BaseVersion,
+		Uid:         v.Uid,
+		Question:    que,
+		Ctime:       time.UnixMilli(v.Ctime),
+	}
+}
+
 func (c *CachedRepository) toDomainWithAnswer(que dao.Question, eles []dao.AnswerElement) domain.Question {
 	res := c.toDomain(que)
 	for _, ele := range eles {
@@ -152,7 +397,11 @@ func (c *CachedRepository) toDomain(que dao.Question) domain.Question {
 		Title:   que.Title,
 		Content: que.Content,
 		Labels:  que.Labels.Val,
-		Utime:   time.UnixMilli(que.Utime),
+
+		Status:        que.Status,
+		Reviewer:      que.Reviewer,
+		ReviewComment: que.ReviewComment,
+		Utime:         time.UnixMilli(que.Utime),
 	}
 }
 
@@ -16,12 +16,21 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/dao"
 	"github.com/gotomicro/ego/core/elog"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrQuestionSetNotFound = errors.New("题集不存在或者当前状态不允许该操作")
+	ErrInvalidQuestionID   = dao.ErrInvalidQuestionID
 )
 
 type QuestionSetRepository interface {
@@ -31,6 +40,17 @@ type QuestionSetRepository interface {
 	Total(ctx context.Context) (int64, error)
 	List(ctx context.Context, offset int, limit int) ([]domain.QuestionSet, error)
 	UpdateNonZero(ctx context.Context, set domain.QuestionSet) error
+
+	Sync(ctx context.Context, id int64) error
+	GetPubByID(ctx context.Context, id int64) (domain.QuestionSet, error)
+	PubTotal(ctx context.Context) (int64, error)
+	PubList(ctx context.Context, offset int, limit int) ([]domain.QuestionSet, error)
+
+	Delete(ctx context.Context, id int64) error
+	ListDeleted(ctx context.Context, offset int, limit int) ([]domain.QuestionSet, error)
+	TotalDeleted(ctx context.Context) (int64, error)
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 type questionSetRepository struct {
@@ -130,6 +150,81 @@ func (q *questionSetRepository) toDomainQuestionSet(qs dao.QuestionSet) domain.Q
 	}
 }
 
+func (q *questionSetRepository) Sync(ctx context.Context, id int64) error {
+	return q.dao.Sync(ctx, id)
+}
+
+func (q *questionSetRepository) GetPubByID(ctx context.Context, id int64) (domain.QuestionSet, error) {
+	var (
+		eg        errgroup.Group
+		set       dao.PublishQuestionSet
+		questions []dao.PublishQuestion
+	)
+	eg.Go(func() error {
+		var err error
+		set, err = q.dao.GetPubByID(ctx, id)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		questions, err = q.dao.GetPubQuestionsByID(ctx, id)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return domain.QuestionSet{}, err
+	}
+	res := q.toDomainQuestionSet(dao.QuestionSet(set))
+	res.Questions = slice.Map(questions, func(idx int, src dao.PublishQuestion) domain.Question {
+		return q.toDomainQuestion(dao.Question(src))
+	})
+	return res, nil
+}
+
+func (q *questionSetRepository) PubTotal(ctx context.Context) (int64, error) {
+	return q.dao.PubCount(ctx)
+}
+
+func (q *questionSetRepository) PubList(ctx context.Context, offset int, limit int) ([]domain.QuestionSet, error) {
+	qs, err := q.dao.PubList(ctx, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(qs, func(idx int, src dao.PublishQuestionSet) domain.QuestionSet {
+		return q.toDomainQuestionSet(dao.QuestionSet(src))
+	}), nil
+}
+
+func (q *questionSetRepository) Delete(ctx context.Context, id int64) error {
+	return q.notFoundErr(q.dao.Delete(ctx, id), id)
+}
+
+func (q *questionSetRepository) ListDeleted(ctx context.Context, offset int, limit int) ([]domain.QuestionSet, error) {
+	qs, err := q.dao.ListDeleted(ctx, offset, limit)
+	return slice.Map(qs, func(idx int, src dao.QuestionSet) domain.QuestionSet {
+		return q.toDomainQuestionSet(src)
+	}), err
+}
+
+func (q *questionSetRepository) TotalDeleted(ctx context.Context) (int64, error) {
+	return q.dao.CountDeleted(ctx)
+}
+
+func (q *questionSetRepository) Restore(ctx context.Context, id int64) error {
+	return q.notFoundErr(q.dao.Restore(ctx, id), id)
+}
+
+// notFoundErr 回收站相关的操作中 DAO 用 gorm.ErrRecordNotFound 表示题集不存在或者状态不对
+func (q *questionSetRepository) notFoundErr(err error, id int64) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("%w: id %d", ErrQuestionSetNotFound, id)
+	}
+	return err
+}
+
+func (q *questionSetRepository) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return q.dao.Purge(ctx, before.UnixMilli(), limit)
+}
+
 func NewQuestionSetRepository(d dao.QuestionSetDAO) QuestionSetRepository {
 	return &questionSetRepository{
 		dao:    d,
@@ -16,10 +16,18 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
 
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/gotomicro/ego/core/elog"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
+	"github.com/ecodeclub/webook/internal/question/internal/event"
 	"github.com/ecodeclub/webook/internal/question/internal/repository"
 )
 
@@ -27,18 +35,66 @@ import (
 type Service interface {
 	// Save 保存数据，question 绝对不会为 nil
 	Save(ctx context.Context, question *domain.Question) (int64, error)
-	Publish(ctx context.Context, que *domain.Question) (int64, error)
+	// Publish 发布，只有审核通过的问题才能发布，uid 是发布人
+	Publish(ctx context.Context, qid int64, uid int64) error
+	// Submit uid 提交审核，并且指定审核人，审核人不能是 uid 自己
+	Submit(ctx context.Context, qid int64, uid int64, reviewer int64) error
+	// Review 审核，只有被指定的审核人才能审核
+	Review(ctx context.Context, qid int64, reviewer int64, approved bool, comment string) error
+	// Unpublish 下架，问题不会被删除，但是不会再出现在线上库中
+	Unpublish(ctx context.Context, qid int64) error
+	// ListReview 分配给 reviewer 的待审核问题
+	ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Question, int64, error)
 	List(ctx context.Context, offset int, limit int) ([]domain.Question, int64, error)
 
 	PubList(ctx context.Context, offset int, limit int) ([]domain.Question, int64, error)
+	// PubListByLabels 按照标签过滤线上库，matchAll 为 true 的时候要求具备全部标签，否则具备任意一个即可
+	PubListByLabels(ctx context.Context, lids []int64, matchAll bool, offset int, limit int) ([]domain.Question, int64, error)
 	// GetPubByIDs 目前只会获取基础信息，也就是不包括答案在内的信息
 	GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Question, error)
 	Detail(ctx context.Context, qid int64) (domain.Question, error)
 	PubDetail(ctx context.Context, qid int64) (domain.Question, error)
+
+	// Delete 删除问题，问题会先放进回收站，线上库的内容会被一并删除
+	// 还在题集中或者被技能引用的问题不能删除
+	Delete(ctx context.Context, qid int64) error
+	// ListDeleted 回收站里面的问题，按照删除时间倒序
+	ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Question, int64, error)
+	// Restore 从回收站恢复，恢复之后是草稿，需要重新审核发布
+	Restore(ctx context.Context, qid int64) error
+	// Purge 彻底删除在 before 之前放进回收站的问题，一次最多 limit 个，返回删除的个数
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
+	// Reindex 将线上库中 id 大于 minID 的问题重新同步到标签和搜索，一次最多 limit 个
+	// 返回最后一个问题的 id，没有更多问题的时候返回 0
+	Reindex(ctx context.Context, minID int64, limit int) (int64, error)
+
+	// ListVersions 按照版本号倒序返回线上库的历史版本
+	ListVersions(ctx context.Context, qid int64, offset int, limit int) ([]domain.QuestionVersion, int64, error)
+	GetVersion(ctx context.Context, qid int64, version int64) (domain.QuestionVersion, error)
+	// Diff 逐字段比较两个版本，version 为 0 代表制作库中当前的内容
+	Diff(ctx context.Context, qid int64, oldVersion int64, newVersion int64) ([]domain.FieldDiff, error)
+	// Rollback 将线上库回滚到 version，回滚本身也会生成一个新版本
+	Rollback(ctx context.Context, qid int64, version int64, uid int64) (int64, error)
 }
 
+var (
+	ErrVersionNotFound    = repository.ErrVersionNotFound
+	ErrInvalidStatus      = repository.ErrInvalidStatus
+	ErrQuestionInSet      = repository.ErrQuestionInSet
+	ErrQuestionReferenced = errors.New("问题还被技能引用")
+	ErrInvalidReviewer    = errors.New("审核人非法")
+)
+
+// 在标签、搜索等模块里面代表问题
+const biz = "question"
+
 type service struct {
-	repo repository.Repository
+	repo       repository.Repository
+	labelSvc   label.Service
+	producer   event.SyncEventProducer
+	refSvc     ReferenceService
+	relatedSvc RelatedService
+	logger     *elog.Component
 }
 
 func (s *service) GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Question, error) {
@@ -49,6 +105,66 @@ func (s *service) PubDetail(ctx context.Context, qid int64) (domain.Question, er
 	return s.repo.GetPubByID(ctx, qid)
 }
 
+func (s *service) ListVersions(ctx context.Context, qid int64, offset int, limit int) ([]domain.QuestionVersion, int64, error) {
+	var (
+		eg    errgroup.Group
+		vs    []domain.QuestionVersion
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		vs, err = s.repo.ListVersions(ctx, qid, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.repo.TotalVersions(ctx, qid)
+		return err
+	})
+	return vs, total, eg.Wait()
+}
+
+func (s *service) GetVersion(ctx context.Context, qid int64, version int64) (domain.QuestionVersion, error) {
+	return s.repo.GetVersion(ctx, qid, version)
+}
+
+func (s *service) Diff(ctx context.Context, qid int64, oldVersion int64, newVersion int64) ([]domain.FieldDiff, error) {
+	var (
+		eg       errgroup.Group
+		old, new domain.Question
+	)
+	eg.Go(func() error {
+		var err error
+		old, err = s.questionOfVersion(ctx, qid, oldVersion)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		new, err = s.questionOfVersion(ctx, qid, newVersion)
+		return err
+	})
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return old.Diff(new), nil
+}
+
+func (s *service) questionOfVersion(ctx context.Context, qid int64, version int64) (domain.Question, error) {
+	if version == 0 {
+		return s.repo.GetById(ctx, qid)
+	}
+	v, err := s.repo.GetVersion(ctx, qid, version)
+	return v.Question, err
+}
+
+func (s *service) Rollback(ctx context.Context, qid int64, version int64, uid int64) (int64, error) {
+	v, err := s.repo.Rollback(ctx, qid, version, uid)
+	if err == nil {
+		s.syncPub(ctx, qid)
+	}
+	return v, err
+}
+
 func (s *service) Detail(ctx context.Context, qid int64) (domain.Question, error) {
 	return s.repo.GetById(ctx, qid)
 }
@@ -93,6 +209,26 @@ func (s *service) PubList(ctx context.Context, offset int, limit int) ([]domain.
 	return qs, total, eg.Wait()
 }
 
+func (s *service) PubListByLabels(ctx context.Context, lids []int64, matchAll bool,
+	offset int, limit int) ([]domain.Question, int64, error) {
+	ids, total, err := s.labelSvc.BizIDs(ctx, biz, lids, matchAll, offset, limit)
+	if err != nil || len(ids) == 0 {
+		return nil, total, err
+	}
+	qs, err := s.repo.GetPubByIDs(ctx, ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	// 保持标签模块给出的顺序
+	qm := slice.ToMap(qs, func(element domain.Question) int64 {
+		return element.Id
+	})
+	return slice.FilterMap(ids, func(idx int, src int64) (domain.Question, bool) {
+		q, ok := qm[src]
+		return q, ok
+	}), total, nil
+}
+
 func (s *service) Save(ctx context.Context, question *domain.Question) (int64, error) {
 	if question.Id > 0 {
 		return question.Id, s.repo.Update(ctx, question)
@@ -100,10 +236,180 @@ func (s *service) Save(ctx context.Context, question *domain.Question) (int64, e
 	return s.repo.Create(ctx, question)
 }
 
-func (s *service) Publish(ctx context.Context, question *domain.Question) (int64, error) {
-	return s.repo.Sync(ctx, question)
+func (s *service) Publish(ctx context.Context, qid int64, uid int64) error {
+	err := s.repo.Sync(ctx, qid, uid)
+	if err == nil {
+		s.syncPub(ctx, qid)
+	}
+	return err
+}
+
+func (s *service) Submit(ctx context.Context, qid int64, uid int64, reviewer int64) error {
+	if reviewer <= 0 || reviewer == uid {
+		return fmt.Errorf("%w: uid %d, reviewer %d", ErrInvalidReviewer, uid, reviewer)
+	}
+	return s.repo.Submit(ctx, qid, reviewer)
+}
+
+func (s *service) Review(ctx context.Context, qid int64, reviewer int64, approved bool, comment string) error {
+	status := uint8(domain.QuestionStatusRejected)
+	if approved {
+		status = domain.QuestionStatusApproved
+	}
+	return s.repo.Review(ctx, qid, reviewer, status, comment)
+}
+
+func (s *service) Unpublish(ctx context.Context, qid int64) error {
+	err := s.repo.Unpublish(ctx, qid)
+	if err != nil {
+		return err
+	}
+	s.removePub(ctx, qid)
+	return nil
+}
+
+func (s *service) Delete(ctx context.Context, qid int64) error {
+	referenced, err := s.refSvc.Referenced(ctx, qid)
+	if err != nil {
+		return err
+	}
+	if referenced {
+		return fmt.Errorf("%w: qid %d", ErrQuestionReferenced, qid)
+	}
+	err = s.repo.Delete(ctx, qid)
+	if err != nil {
+		return err
+	}
+	s.removePub(ctx, qid)
+	return nil
+}
+
+func (s *service) ListDeleted(ctx context.Context, offset int, limit int) ([]domain.Question, int64, error) {
+	var (
+		eg    errgroup.Group
+		qs    []domain.Question
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		qs, err = s.repo.ListDeleted(ctx, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.repo.TotalDeleted(ctx)
+		return err
+	})
+	return qs, total, eg.Wait()
+}
+
+func (s *service) Restore(ctx context.Context, qid int64) error {
+	return s.repo.Restore(ctx, qid)
 }
 
-func NewService(repo repository.Repository) Service {
-	return &service{repo: repo}
+func (s *service) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return s.repo.Purge(ctx, before, limit)
+}
+
+// removePub 问题从线上库消失之后，同步删除标签、相关推荐和搜索索引，失败了也不影响下架或者删除本身
+func (s *service) removePub(ctx context.Context, qid int64) {
+	err := s.labelSvc.DeleteBizLabels(ctx, biz, qid)
+	if err != nil {
+		s.logger.Error("删除问题的标签失败", elog.FieldErr(err), elog.Int64("qid", qid))
+	}
+	err = s.relatedSvc.RemoveQuestion(ctx, qid)
+	if err != nil {
+		s.logger.Error("删除问题的相关推荐失败", elog.FieldErr(err), elog.Int64("qid", qid))
+	}
+	s.produceSyncEvent(ctx, event.SyncSearchEvent{Biz: biz, BizId: qid, Deleted: true})
+}
+
+// syncPub 将线上库的标签同步到标签模块，内容同步到搜索，失败了也不影响发布本身
+func (s *service) syncPub(ctx context.Context, qid int64) {
+	que, err := s.repo.GetPubByID(ctx, qid)
+	if err != nil {
+		s.logger.Error("同步时查询线上库失败", elog.FieldErr(err), elog.Int64("qid", qid))
+		return
+	}
+	err = s.labelSvc.SaveBizLabels(ctx, biz, qid, que.Labels)
+	if err != nil {
+		s.logger.Error("保存问题的标签失败", elog.FieldErr(err), elog.Int64("qid", qid))
+	}
+	s.produceSyncEvent(ctx, s.toSyncEvent(que))
+}
+
+func (s *service) Reindex(ctx context.Context, minID int64, limit int) (int64, error) {
+	qids, err := s.repo.PubIDs(ctx, minID, limit)
+	if err != nil || len(qids) == 0 {
+		return 0, err
+	}
+	for _, qid := range qids {
+		que, err := s.repo.GetPubByID(ctx, qid)
+		if err != nil {
+			return 0, fmt.Errorf("查询线上库失败 qid %d: %w", qid, err)
+		}
+		err = s.labelSvc.SaveBizLabels(ctx, biz, qid, que.Labels)
+		if err != nil {
+			return 0, fmt.Errorf("保存问题的标签失败 qid %d: %w", qid, err)
+		}
+		err = s.producer.Produce(ctx, s.toSyncEvent(que))
+		if err != nil {
+			return 0, fmt.Errorf("发送搜索同步消息失败 qid %d: %w", qid, err)
+		}
+	}
+	return qids[len(qids)-1], nil
+}
+
+func (s *service) toSyncEvent(que domain.Question) event.SyncSearchEvent {
+	eles := []domain.AnswerElement{que.Answer.Analysis, que.Answer.Basic,
+		que.Answer.Intermediate, que.Answer.Advanced}
+	return event.SyncSearchEvent{
+		Biz:     biz,
+		BizId:   que.Id,
+		Title:   que.Title,
+		Content: que.Content,
+		Keywords: strings.Join(slice.Map(eles, func(idx int, src domain.AnswerElement) string {
+			return src.Keywords
+		}), " "),
+		Labels: que.Labels,
+	}
+}
+
+func (s *service) produceSyncEvent(ctx context.Context, evt event.SyncSearchEvent) {
+	err := s.producer.Produce(ctx, evt)
+	if err != nil {
+		s.logger.Error("发送搜索同步消息失败", elog.FieldErr(err),
+			elog.String("biz", evt.Biz), elog.Int64("bizId", evt.BizId))
+	}
+}
+
+func (s *service) ListReview(ctx context.Context, reviewer int64, offset int, limit int) ([]domain.Question, int64, error) {
+	var (
+		eg    errgroup.Group
+		qs    []domain.Question
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		qs, err = s.repo.ListReview(ctx, reviewer, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.repo.TotalReview(ctx, reviewer)
+		return err
+	})
+	return qs, total, eg.Wait()
+}
+
+func NewService(repo repository.Repository, labelSvc label.Service,
+	producer event.SyncEventProducer, refSvc ReferenceService, relatedSvc RelatedService) Service {
+	return &service{
+		repo:       repo,
+		labelSvc:   labelSvc,
+		producer:   producer,
+		refSvc:     refSvc,
+		relatedSvc: relatedSvc,
+		logger:     elog.DefaultLogger,
+	}
 }
@@ -16,19 +16,41 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
 	"github.com/ecodeclub/webook/internal/question/internal/repository"
 	"golang.org/x/sync/errgroup"
 )
 
+//go:generate mockgen -source=./question_set.go -destination=../../mocks/question_set.mock.go -package=quemocks -typed QuestionSetService
 type QuestionSetService interface {
 	Save(ctx context.Context, set domain.QuestionSet) (int64, error)
 	UpdateQuestions(ctx context.Context, set domain.QuestionSet) error
 	List(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error)
 	Detail(ctx context.Context, id int64) (domain.QuestionSet, error)
+
+	// Publish 发布题集，题集中的题目顺序会一并同步到线上库
+	Publish(ctx context.Context, id int64) error
+	PubList(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error)
+	// PubDetail 线上题集详情，只包含已经发布的题目
+	PubDetail(ctx context.Context, id int64) (domain.QuestionSet, error)
+
+	// Delete 删除题集，题集会先放进回收站，线上库的题集会被一并删除
+	Delete(ctx context.Context, id int64) error
+	// ListDeleted 回收站里面的题集，按照删除时间倒序
+	ListDeleted(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error)
+	// Restore 从回收站恢复，需要重新发布
+	Restore(ctx context.Context, id int64) error
+	// Purge 彻底删除在 before 之前放进回收站的题集，一次最多 limit 个，返回删除的个数
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
+var (
+	ErrQuestionSetNotFound = repository.ErrQuestionSetNotFound
+	ErrInvalidQuestionID   = repository.ErrInvalidQuestionID
+)
+
 type questionSetService struct {
 	repo repository.QuestionSetRepository
 }
@@ -71,3 +93,62 @@ func (q *questionSetService) List(ctx context.Context, offset, limit int) ([]dom
 	})
 	return qs, total, eg.Wait()
 }
+
+func (q *questionSetService) Publish(ctx context.Context, id int64) error {
+	return q.repo.Sync(ctx, id)
+}
+
+func (q *questionSetService) PubDetail(ctx context.Context, id int64) (domain.QuestionSet, error) {
+	return q.repo.GetPubByID(ctx, id)
+}
+
+func (q *questionSetService) PubList(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error) {
+	var (
+		eg    errgroup.Group
+		qs    []domain.QuestionSet
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		qs, err = q.repo.PubList(ctx, offset, limit)
+		return err
+	})
+
+	eg.Go(func() error {
+		var err error
+		total, err = q.repo.PubTotal(ctx)
+		return err
+	})
+	return qs, total, eg.Wait()
+}
+
+func (q *questionSetService) Delete(ctx context.Context, id int64) error {
+	return q.repo.Delete(ctx, id)
+}
+
+func (q *questionSetService) ListDeleted(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error) {
+	var (
+		eg    errgroup.Group
+		qs    []domain.QuestionSet
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		qs, err = q.repo.ListDeleted(ctx, offset, limit)
+		return err
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = q.repo.TotalDeleted(ctx)
+		return err
+	})
+	return qs, total, eg.Wait()
+}
+
+func (q *questionSetService) Restore(ctx context.Context, id int64) error {
+	return q.repo.Restore(ctx, id)
+}
+
+func (q *questionSetService) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return q.repo.Purge(ctx, before, limit)
+}
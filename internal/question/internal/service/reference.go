@@ -0,0 +1,26 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import "context"
+
+// ReferenceService 查询技能等模块对题目的引用，被引用的题目不能删除。
+// 技能模块依赖题目模块，所以由技能模块实现
+//
+//go:generate mockgen -source=./reference.go -destination=../../mocks/reference.mock.go -package=quemocks -typed ReferenceService
+type ReferenceService interface {
+	// Referenced 题目是否还被引用
+	Referenced(ctx context.Context, qid int64) (bool, error)
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/ecodeclub/webook/internal/question/internal/domain"
+)
+
+// RelatedService 相关推荐依赖技能、案例等模块，所以由推荐模块实现，这里只负责展示
+//
+//go:generate mockgen -source=./related.go -destination=../../mocks/related.mock.go -package=quemocks -typed RelatedService
+type RelatedService interface {
+	// Related 按照相关程度排序，没有计算过的题目返回空
+	Related(ctx context.Context, qid int64) ([]domain.Related, error)
+	// RemoveQuestion 题目下架或者删除之后，从别的题目的相关推荐里面去掉
+	RemoveQuestion(ctx context.Context, qid int64) error
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ecodeclub/webook/internal/question/internal/domain"
+	"github.com/ecodeclub/webook/internal/question/internal/format"
+)
+
+var (
+	ErrUnsupportedFormat = format.ErrUnsupportedFormat
+	ErrInvalidFile       = format.ErrInvalidFile
+)
+
+// TransferService 制作库问题的批量导入导出
+type TransferService interface {
+	// Import 解析文件并逐条创建或者更新问题，单道题目失败不影响其它题目，
+	// 只有整个文件无法解析的时候才会返回 error
+	Import(ctx context.Context, uid int64, filename string, data []byte) ([]domain.ImportResult, error)
+	// Export 按照 qids 的顺序导出，fileFormat 是 md 或者 json
+	Export(ctx context.Context, qids []int64, fileFormat string) (string, []byte, error)
+}
+
+type transferService struct {
+	svc Service
+}
+
+func NewTransferService(svc Service) TransferService {
+	return &transferService{svc: svc}
+}
+
+func (t *transferService) Import(ctx context.Context, uid int64, filename string, data []byte) ([]domain.ImportResult, error) {
+	entries, err := format.Decode(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]domain.ImportResult, 0, len(entries))
+	for _, entry := range entries {
+		r := domain.ImportResult{Name: entry.Name, Err: entry.Err}
+		if r.Err == nil {
+			r.Id, r.Err = t.save(ctx, uid, entry.Question)
+		}
+		res = append(res, r)
+	}
+	return res, nil
+}
+
+func (t *transferService) save(ctx context.Context, uid int64, que domain.Question) (int64, error) {
+	if que.Id > 0 {
+		// 更新的时候问题必须存在，否则 Save 不会报错
+		if _, err := t.svc.Detail(ctx, que.Id); err != nil {
+			return 0, fmt.Errorf("问题 %d 不存在: %w", que.Id, err)
+		}
+	}
+	que.Uid = uid
+	return t.svc.Save(ctx, &que)
+}
+
+func (t *transferService) Export(ctx context.Context, qids []int64, fileFormat string) (string, []byte, error) {
+	ques := make([]domain.Question, 0, len(qids))
+	for _, qid := range qids {
+		que, err := t.svc.Detail(ctx, qid)
+		if err != nil {
+			return "", nil, err
+		}
+		ques = append(ques, que)
+	}
+	return format.Encode(fileFormat, ques)
+}
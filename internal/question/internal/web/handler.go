@@ -15,44 +15,88 @@
 package web
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"time"
 
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/ginx"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/practice"
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
 	"github.com/ecodeclub/webook/internal/question/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/gotomicro/ego/core/elog"
 )
 
+const (
+	// 导入文件的大小上限
+	maxImportSize = 10 << 20
+	// 标签下的题目很多的时候，只练习最新的这么多道题
+	maxPracticeSize = 100
+	// 在练习模块里面代表标签
+	practiceBizLabel = "label"
+	// 分页查询一次最多返回的数量
+	maxLimit = 100
+)
+
 type Handler struct {
-	svc    service.Service
-	logger *elog.Component
+	svc         service.Service
+	transferSvc service.TransferService
+	practiceSvc practice.Service
+	noteSvc     note.Service
+	relatedSvc  service.RelatedService
+	logger      *elog.Component
 }
 
-func NewHandler(svc service.Service) *Handler {
+func NewHandler(svc service.Service, transferSvc service.TransferService,
+	practiceSvc practice.Service, noteSvc note.Service, relatedSvc service.RelatedService) *Handler {
 	return &Handler{
-		svc:    svc,
-		logger: elog.DefaultLogger,
+		svc:         svc,
+		transferSvc: transferSvc,
+		practiceSvc: practiceSvc,
+		noteSvc:     noteSvc,
+		relatedSvc:  relatedSvc,
+		logger:      elog.DefaultLogger,
 	}
 }
 
 func (h *Handler) PublicRoutes(server *gin.Engine) {
-	server.POST("/question/pub/list", ginx.B[Page](h.PubList))
+	server.POST("/question/pub/list", ginx.B[PubListReq](h.PubList))
 }
 
 func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	server.POST("/question/save", ginx.S(h.Permission), ginx.BS[SaveReq](h.Save))
 	server.POST("/question/list", ginx.S(h.Permission), ginx.B[Page](h.List))
 	server.POST("/question/detail", ginx.S(h.Permission), ginx.B[Qid](h.Detail))
-	server.POST("/question/publish", ginx.S(h.Permission), ginx.BS[SaveReq](h.Publish))
+	server.POST("/question/publish", ginx.S(h.Permission), ginx.BS[Qid](h.Publish))
+	server.POST("/question/unpublish", ginx.S(h.Permission), ginx.B[Qid](h.Unpublish))
+
+	server.POST("/question/delete", ginx.S(h.Permission), ginx.B[Qid](h.Delete))
+	server.POST("/question/recycle/list", ginx.S(h.Permission), ginx.B[Page](h.ListDeleted))
+	server.POST("/question/recycle/restore", ginx.S(h.Permission), ginx.B[Qid](h.Restore))
+
+	server.POST("/question/submit", ginx.S(h.Permission), ginx.BS[SubmitReq](h.Submit))
+	server.POST("/question/review", ginx.S(h.Permission), ginx.BS[ReviewReq](h.Review))
+	server.POST("/question/review/list", ginx.S(h.Permission), ginx.BS[Page](h.ListReview))
+
+	server.POST("/question/version/list", ginx.S(h.Permission), ginx.B[VersionPage](h.ListVersions))
+	server.POST("/question/version/detail", ginx.S(h.Permission), ginx.B[VersionReq](h.VersionDetail))
+	server.POST("/question/version/diff", ginx.S(h.Permission), ginx.B[DiffReq](h.Diff))
+	server.POST("/question/version/rollback", ginx.S(h.Permission), ginx.BS[VersionReq](h.Rollback))
+
+	server.POST("/question/import", ginx.S(h.Permission), ginx.S(h.Import))
+	server.POST("/question/export", ginx.S(h.Permission), ginx.B[ExportReq](h.Export))
 }
 
 func (h *Handler) MemberRoutes(server *gin.Engine) {
-	server.POST("/question/pub/detail", ginx.B[Qid](h.PubDetail))
+	server.POST("/question/pub/detail", ginx.BS[Qid](h.PubDetail))
+	server.POST("/question/note/save", ginx.BS[NoteReq](h.SaveNote))
+	server.POST("/question/practice/start", ginx.BS[LabelReq](h.StartPractice))
+	server.POST("/question/practice/progress", ginx.BS[LabelReq](h.PracticeProgress))
 }
 
 func (h *Handler) Save(ctx *ginx.Context,
@@ -69,15 +113,73 @@ func (h *Handler) Save(ctx *ginx.Context,
 	}, nil
 }
 
-func (h *Handler) Publish(ctx *ginx.Context, req SaveReq, sess session.Session) (ginx.Result, error) {
-	que := req.Question.toDomain()
-	que.Uid = sess.Claims().Uid
-	id, err := h.svc.Publish(ctx, &que)
+func (h *Handler) Publish(ctx *ginx.Context, req Qid, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Publish(ctx, req.Qid, sess.Claims().Uid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{
+		Data: req.Qid,
+	}, nil
+}
+
+func (h *Handler) Unpublish(ctx *ginx.Context, req Qid) (ginx.Result, error) {
+	err := h.svc.Unpublish(ctx, req.Qid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Delete(ctx *ginx.Context, req Qid) (ginx.Result, error) {
+	err := h.svc.Delete(ctx, req.Qid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) ListDeleted(ctx *ginx.Context, req Page) (ginx.Result, error) {
+	data, cnt, err := h.svc.ListDeleted(ctx, req.Offset, req.Limit)
 	if err != nil {
 		return systemErrorResult, err
 	}
 	return ginx.Result{
-		Data: id,
+		Data: h.toQuestionList(data, cnt),
+	}, nil
+}
+
+func (h *Handler) Restore(ctx *ginx.Context, req Qid) (ginx.Result, error) {
+	err := h.svc.Restore(ctx, req.Qid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Submit(ctx *ginx.Context, req SubmitReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Submit(ctx, req.Qid, sess.Claims().Uid, req.Reviewer)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) Review(ctx *ginx.Context, req ReviewReq, sess session.Session) (ginx.Result, error) {
+	err := h.svc.Review(ctx, req.Qid, sess.Claims().Uid, req.Approved, req.Comment)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) ListReview(ctx *ginx.Context, req Page, sess session.Session) (ginx.Result, error) {
+	data, cnt, err := h.svc.ListReview(ctx, sess.Claims().Uid, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toQuestionList(data, cnt),
 	}, nil
 }
 
@@ -92,8 +194,21 @@ func (h *Handler) List(ctx *ginx.Context, req Page) (ginx.Result, error) {
 	}, nil
 }
 
-func (h *Handler) PubList(ctx *ginx.Context, req Page) (ginx.Result, error) {
-	data, cnt, err := h.svc.PubList(ctx, req.Offset, req.Limit)
+func (h *Handler) PubList(ctx *ginx.Context, req PubListReq) (ginx.Result, error) {
+	req.Offset = max(req.Offset, 0)
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	var (
+		data []domain.Question
+		cnt  int64
+		err  error
+	)
+	if len(req.LabelIds) > 0 {
+		data, cnt, err = h.svc.PubListByLabels(ctx, req.LabelIds, req.MatchAll, req.Offset, req.Limit)
+	} else {
+		data, cnt, err = h.svc.PubList(ctx, req.Offset, req.Limit)
+	}
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -111,6 +226,7 @@ func (h *Handler) toQuestionList(data []domain.Question, cnt int64) QuestionList
 				Title:   src.Title,
 				Content: src.Content,
 				Labels:  src.Labels,
+				Status:  src.Status,
 				Utime:   src.Utime.Format(time.DateTime),
 			}
 		}),
@@ -122,22 +238,225 @@ func (h *Handler) Detail(ctx *ginx.Context, req Qid) (ginx.Result, error) {
 	if err != nil {
 		return systemErrorResult, err
 	}
+	que := newQuestion(detail)
+	que.Status = detail.Status
+	que.Reviewer = detail.Reviewer
+	que.ReviewComment = detail.ReviewComment
 	return ginx.Result{
-		Data: newQuestion(detail),
+		Data: que,
 	}, err
 }
 
-func (h *Handler) PubDetail(ctx *ginx.Context, req Qid) (ginx.Result, error) {
+func (h *Handler) PubDetail(ctx *ginx.Context, req Qid, sess session.Session) (ginx.Result, error) {
 	detail, err := h.svc.PubDetail(ctx, req.Qid)
 	if err != nil {
 		return systemErrorResult, err
 	}
+	que := newQuestion(detail)
+	que.Notes = note.BizNotes(ctx, h.noteSvc, sess.Claims().Uid, note.BizQuestion, req.Qid)
+	// 相关推荐只是锦上添花，查询失败也不影响题目本身的展示
+	rs, err := h.relatedSvc.Related(ctx, req.Qid)
+	if err != nil {
+		h.logger.Error("查询相关推荐失败", elog.FieldErr(err), elog.Int64("qid", req.Qid))
+	}
+	que.Related = slice.Map(rs, func(idx int, src domain.Related) Related {
+		return newRelated(src)
+	})
 	return ginx.Result{
-		Data: newQuestion(detail),
-	}, err
+		Data: que,
+	}, nil
+}
+
+// SaveNote 给已经发布的题目，或者题目答案的某个部分写笔记
+func (h *Handler) SaveNote(ctx *ginx.Context, req NoteReq, sess session.Session) (ginx.Result, error) {
+	ques, err := h.svc.GetPubByIDs(ctx, []int64{req.Qid})
+	if err != nil {
+		return systemErrorResult, err
+	}
+	if len(ques) == 0 {
+		return questionNotFoundResult, nil
+	}
+	err = h.noteSvc.Save(ctx, note.Note{
+		Uid:     sess.Claims().Uid,
+		Biz:     note.BizQuestion,
+		BizId:   req.Qid,
+		Element: req.Element,
+		Content: req.Content,
+	})
+	switch {
+	case errors.Is(err, note.ErrInvalidNote):
+		return note.InvalidNoteResult, nil
+	case err != nil:
+		return systemErrorResult, err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) ListVersions(ctx *ginx.Context, req VersionPage) (ginx.Result, error) {
+	req.Offset = max(req.Offset, 0)
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	vs, cnt, err := h.svc.ListVersions(ctx, req.Qid, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: QuestionVersionList{
+			Total: cnt,
+			Versions: slice.Map(vs, func(idx int, src domain.QuestionVersion) QuestionVersion {
+				return newQuestionVersion(src)
+			}),
+		},
+	}, nil
+}
+
+func (h *Handler) VersionDetail(ctx *ginx.Context, req VersionReq) (ginx.Result, error) {
+	v, err := h.svc.GetVersion(ctx, req.Qid, req.Version)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{
+		Data: newQuestionVersion(v),
+	}, nil
+}
+
+func (h *Handler) Diff(ctx *ginx.Context, req DiffReq) (ginx.Result, error) {
+	diffs, err := h.svc.Diff(ctx, req.Qid, req.OldVersion, req.NewVersion)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{
+		Data: slice.Map(diffs, func(idx int, src domain.FieldDiff) FieldDiff {
+			return FieldDiff{Field: src.Field, Old: src.Old, New: src.New}
+		}),
+	}, nil
+}
+
+func (h *Handler) Rollback(ctx *ginx.Context, req VersionReq, sess session.Session) (ginx.Result, error) {
+	version, err := h.svc.Rollback(ctx, req.Qid, req.Version, sess.Claims().Uid)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{
+		Data: version,
+	}, nil
+}
+
+// Import 上传 .md、.json 或者 .zip 文件，表单字段为 file
+func (h *Handler) Import(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return invalidFileResult, err
+	}
+	if header.Size > maxImportSize {
+		return invalidFileResult, fmt.Errorf("导入文件过大 %d", header.Size)
+	}
+	file, err := header.Open()
+	if err != nil {
+		return systemErrorResult, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	res, err := h.transferSvc.Import(ctx.Request.Context(), sess.Claims().Uid, header.Filename, data)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{
+		Data: slice.Map(res, func(idx int, src domain.ImportResult) ImportResult {
+			return newImportResult(src)
+		}),
+	}, nil
+}
+
+// Export 成功的时候直接返回文件内容
+func (h *Handler) Export(ctx *ginx.Context, req ExportReq) (ginx.Result, error) {
+	if len(req.Qids) == 0 {
+		return invalidFileResult, errors.New("没有需要导出的问题")
+	}
+	name, data, err := h.transferSvc.Export(ctx.Request.Context(), req.Qids, req.Format)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	ctx.Data(http.StatusOK, "application/octet-stream", data)
+	return ginx.Result{}, ginx.ErrNoResponse
+}
+
+// StartPractice 按照标签开始一次练习，返回练习 ID
+func (h *Handler) StartPractice(ctx *ginx.Context, req LabelReq, sess session.Session) (ginx.Result, error) {
+	qids, err := h.labelQids(ctx, req.LabelId)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	sid, err := h.practiceSvc.StartSession(ctx.Request.Context(), practice.Session{
+		Uid:   sess.Claims().Uid,
+		Biz:   practiceBizLabel,
+		BizId: req.LabelId,
+		Qids:  qids,
+	})
+	if errors.Is(err, practice.ErrEmptySession) {
+		return practice.EmptySessionResult, err
+	}
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: sid,
+	}, nil
+}
+
+// PracticeProgress 当前用户在标签下的掌握情况
+func (h *Handler) PracticeProgress(ctx *ginx.Context, req LabelReq, sess session.Session) (ginx.Result, error) {
+	qids, err := h.labelQids(ctx, req.LabelId)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	progress, err := h.practiceSvc.Progress(ctx.Request.Context(), sess.Claims().Uid, qids)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: practice.NewProgressVO(progress),
+	}, nil
+}
+
+func (h *Handler) labelQids(ctx *ginx.Context, lid int64) ([]int64, error) {
+	ques, _, err := h.svc.PubListByLabels(ctx.Request.Context(), []int64{lid}, false, 0, maxPracticeSize)
+	return slice.Map(ques, func(idx int, src domain.Question) int64 {
+		return src.Id
+	}), err
+}
+
+func (h *Handler) errorResult(err error) ginx.Result {
+	switch {
+	case errors.Is(err, service.ErrVersionNotFound):
+		return versionNotFoundResult
+	case errors.Is(err, service.ErrInvalidStatus):
+		return invalidStatusResult
+	case errors.Is(err, service.ErrQuestionInSet):
+		return questionInSetResult
+	case errors.Is(err, service.ErrQuestionReferenced):
+		return questionInSkillResult
+	case errors.Is(err, service.ErrInvalidReviewer):
+		return invalidReviewerResult
+	case errors.Is(err, service.ErrUnsupportedFormat),
+		errors.Is(err, service.ErrInvalidFile):
+		return invalidFileResult
+	default:
+		return systemErrorResult
+	}
 }
 
 func (h *Handler) Permission(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	return creatorPermission(ctx, sess)
+}
+
+// creatorPermission 只有创作者才能访问创作中心，问题和题集共用
+func creatorPermission(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
 	if sess.Claims().Get("creator").StringOrDefault("") != "true" {
 		ctx.AbortWithStatus(http.StatusInternalServerError)
 		return ginx.Result{}, fmt.Errorf("非法访问创作中心 uid: %d", sess.Claims().Uid)
@@ -15,6 +15,7 @@
 package web
 
 import (
+	"errors"
 	"time"
 
 	"github.com/ecodeclub/ekit/bean/copier"
@@ -22,6 +23,7 @@ import (
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/ginx"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/practice"
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
 	"github.com/ecodeclub/webook/internal/question/internal/service"
 	"github.com/gin-gonic/gin"
@@ -30,13 +32,17 @@ import (
 
 var _ ginx.Handler = (*QuestionSetHandler)(nil)
 
+// 在练习模块里面代表题集
+const practiceBizQuestionSet = "questionSet"
+
 type QuestionSetHandler struct {
-	dm2vo  copier.Copier[domain.QuestionSet, QuestionSet]
-	svc    service.QuestionSetService
-	logger *elog.Component
+	dm2vo       copier.Copier[domain.QuestionSet, QuestionSet]
+	svc         service.QuestionSetService
+	practiceSvc practice.Service
+	logger      *elog.Component
 }
 
-func NewQuestionSetHandler(svc service.QuestionSetService) (*QuestionSetHandler, error) {
+func NewQuestionSetHandler(svc service.QuestionSetService, practiceSvc practice.Service) (*QuestionSetHandler, error) {
 	dm2vo, err := copier.NewReflectCopier[domain.QuestionSet, QuestionSet](
 		copier.ConvertField[time.Time, string]("Utime", converter.ConverterFunc[time.Time, string](func(src time.Time) (string, error) {
 			return src.Format(time.DateTime), nil
@@ -46,9 +52,10 @@ func NewQuestionSetHandler(svc service.QuestionSetService) (*QuestionSetHandler,
 		return nil, err
 	}
 	return &QuestionSetHandler{
-		dm2vo:  dm2vo,
-		svc:    svc,
-		logger: elog.DefaultLogger,
+		dm2vo:       dm2vo,
+		svc:         svc,
+		practiceSvc: practiceSvc,
+		logger:      elog.DefaultLogger,
 	}, nil
 }
 
@@ -60,11 +67,31 @@ func (h *QuestionSetHandler) PrivateRoutes(server *gin.Engine) {
 	g.POST("/questions/save", ginx.BS[UpdateQuestionsOfQuestionSetReq](h.UpdateQuestionsOfQuestionSet))
 	g.POST("/list", ginx.B[Page](h.ListPrivateQuestionSets))
 	g.POST("/detail", ginx.B[QuestionSetID](h.RetrieveQuestionSetDetail))
+	g.POST("/publish", ginx.S(h.Permission), ginx.B[QuestionSetID](h.Publish))
+	g.POST("/delete", ginx.S(h.Permission), ginx.B[QuestionSetID](h.Delete))
+	g.POST("/recycle/list", ginx.S(h.Permission), ginx.B[Page](h.ListDeleted))
+	g.POST("/recycle/restore", ginx.S(h.Permission), ginx.B[QuestionSetID](h.Restore))
+}
 
+func (h *QuestionSetHandler) Permission(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	return creatorPermission(ctx, sess)
+}
+
+func (h *QuestionSetHandler) errorResult(err error) ginx.Result {
+	switch {
+	case errors.Is(err, service.ErrQuestionSetNotFound):
+		return questionSetNotFoundResult
+	case errors.Is(err, service.ErrInvalidQuestionID):
+		return invalidSetQuestionResult
+	default:
+		return systemErrorResult
+	}
 }
 
 func (h *QuestionSetHandler) MemberRoutes(server *gin.Engine) {
 	server.POST("/question-sets/pub/list", ginx.B[Page](h.ListAllQuestionSets))
+	server.POST("/question-sets/pub/detail", ginx.BS[QuestionSetID](h.PubDetail))
+	server.POST("/question-sets/practice/start", ginx.BS[QuestionSetID](h.StartPractice))
 }
 
 // SaveQuestionSet 保存
@@ -96,7 +123,7 @@ func (h *QuestionSetHandler) UpdateQuestionsOfQuestionSet(ctx *ginx.Context, req
 		Questions: questions,
 	})
 	if err != nil {
-		return systemErrorResult, err
+		return h.errorResult(err), err
 	}
 	return ginx.Result{}, nil
 }
@@ -133,9 +160,47 @@ func (h *QuestionSetHandler) toQuestionSetList(data []domain.QuestionSet, total
 	}
 }
 
-// ListAllQuestionSets 展示所有题集
+// Publish 发布题集
+func (h *QuestionSetHandler) Publish(ctx *ginx.Context, req QuestionSetID) (ginx.Result, error) {
+	err := h.svc.Publish(ctx.Request.Context(), req.QSID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{}, nil
+}
+
+// Delete 删除题集，放进回收站
+func (h *QuestionSetHandler) Delete(ctx *ginx.Context, req QuestionSetID) (ginx.Result, error) {
+	err := h.svc.Delete(ctx.Request.Context(), req.QSID)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+// ListDeleted 回收站里面的题集
+func (h *QuestionSetHandler) ListDeleted(ctx *ginx.Context, req Page) (ginx.Result, error) {
+	data, total, err := h.svc.ListDeleted(ctx, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toQuestionSetList(data, total),
+	}, nil
+}
+
+// Restore 从回收站恢复题集
+func (h *QuestionSetHandler) Restore(ctx *ginx.Context, req QuestionSetID) (ginx.Result, error) {
+	err := h.svc.Restore(ctx.Request.Context(), req.QSID)
+	if err != nil {
+		return h.errorResult(err), err
+	}
+	return ginx.Result{}, nil
+}
+
+// ListAllQuestionSets 展示所有已经发布的题集
 func (h *QuestionSetHandler) ListAllQuestionSets(ctx *ginx.Context, req Page) (ginx.Result, error) {
-	data, total, err := h.svc.List(ctx, req.Offset, req.Limit)
+	data, total, err := h.svc.PubList(ctx, req.Offset, req.Limit)
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -155,6 +220,47 @@ func (h *QuestionSetHandler) RetrieveQuestionSetDetail(ctx *ginx.Context, req Qu
 	}, nil
 }
 
+// PubDetail 线上题集详情，附带当前用户的掌握情况
+func (h *QuestionSetHandler) PubDetail(ctx *ginx.Context, req QuestionSetID, sess session.Session) (ginx.Result, error) {
+	data, err := h.svc.PubDetail(ctx.Request.Context(), req.QSID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	progress, err := h.practiceSvc.Progress(ctx.Request.Context(), sess.Claims().Uid, data.Qids())
+	if err != nil {
+		return systemErrorResult, err
+	}
+	vo := h.toQuestionSetVO(data)
+	pvo := practice.NewProgressVO(progress)
+	vo.Progress = &pvo
+	return ginx.Result{
+		Data: vo,
+	}, nil
+}
+
+// StartPractice 按照线上题集中的题目开始一次练习，返回练习 ID
+func (h *QuestionSetHandler) StartPractice(ctx *ginx.Context, req QuestionSetID, sess session.Session) (ginx.Result, error) {
+	data, err := h.svc.PubDetail(ctx.Request.Context(), req.QSID)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	sid, err := h.practiceSvc.StartSession(ctx.Request.Context(), practice.Session{
+		Uid:   sess.Claims().Uid,
+		Biz:   practiceBizQuestionSet,
+		BizId: req.QSID,
+		Qids:  data.Qids(),
+	})
+	if errors.Is(err, practice.ErrEmptySession) {
+		return practice.EmptySessionResult, err
+	}
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: sid,
+	}, nil
+}
+
 func (h *QuestionSetHandler) toQuestionSetVO(set domain.QuestionSet) QuestionSet {
 	return QuestionSet{
 		Id:          set.Id,
@@ -24,4 +24,40 @@ var (
 		Code: errs.SystemError.Code,
 		Msg:  errs.SystemError.Msg,
 	}
+	versionNotFoundResult = ginx.Result{
+		Code: errs.VersionNotFound.Code,
+		Msg:  errs.VersionNotFound.Msg,
+	}
+	invalidStatusResult = ginx.Result{
+		Code: errs.InvalidStatus.Code,
+		Msg:  errs.InvalidStatus.Msg,
+	}
+	invalidFileResult = ginx.Result{
+		Code: errs.InvalidFile.Code,
+		Msg:  errs.InvalidFile.Msg,
+	}
+	questionNotFoundResult = ginx.Result{
+		Code: errs.QuestionNotFound.Code,
+		Msg:  errs.QuestionNotFound.Msg,
+	}
+	questionInSetResult = ginx.Result{
+		Code: errs.QuestionInSet.Code,
+		Msg:  errs.QuestionInSet.Msg,
+	}
+	questionInSkillResult = ginx.Result{
+		Code: errs.QuestionInSkill.Code,
+		Msg:  errs.QuestionInSkill.Msg,
+	}
+	invalidReviewerResult = ginx.Result{
+		Code: errs.InvalidReviewer.Code,
+		Msg:  errs.InvalidReviewer.Msg,
+	}
+	questionSetNotFoundResult = ginx.Result{
+		Code: errs.QuestionSetNotFound.Code,
+		Msg:  errs.QuestionSetNotFound.Msg,
+	}
+	invalidSetQuestionResult = ginx.Result{
+		Code: errs.InvalidSetQuestion.Code,
+		Msg:  errs.InvalidSetQuestion.Msg,
+	}
 )
@@ -17,6 +17,9 @@ package web
 import (
 	"time"
 
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/practice"
+
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
 )
 
@@ -34,6 +37,11 @@ type Question struct {
 	Content string `json:"content,omitempty"`
 	Utime   string `json:"utime,omitempty"`
 
+	// 以下三个字段只在制作库中有意义
+	Status        uint8  `json:"status,omitempty"`
+	Reviewer      int64  `json:"reviewer,omitempty"`
+	ReviewComment string `json:"reviewComment,omitempty"`
+
 	// 题集 ID
 	Sets []QuestionSet `json:"sets"`
 
@@ -44,6 +52,25 @@ type Question struct {
 	Intermediate AnswerElement `json:"intermediate,omitempty"`
 	// 高阶回答
 	Advanced AnswerElement `json:"advanced,omitempty"`
+
+	// 用户自己的笔记，只在线上库详情里面有
+	Notes []note.NoteVO `json:"notes,omitempty"`
+	// 相关的题目和案例，只在线上库详情里面有
+	Related []Related `json:"related,omitempty"`
+}
+
+type Related struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"bizId"`
+	Title string `json:"title"`
+}
+
+func newRelated(r domain.Related) Related {
+	return Related{
+		Biz:   r.Biz,
+		BizId: r.BizId,
+		Title: r.Title,
+	}
 }
 
 func (que Question) toDomain() domain.Question {
@@ -118,10 +145,89 @@ type Page struct {
 	Limit  int `json:"limit,omitempty"`
 }
 
+type LabelReq struct {
+	LabelId int64 `json:"labelId"`
+}
+
+type PubListReq struct {
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+	// 按照标签过滤，为空的时候不过滤
+	LabelIds []int64 `json:"labelIds,omitempty"`
+	// 为 true 的时候要求具备全部标签，否则具备任意一个即可
+	MatchAll bool `json:"matchAll,omitempty"`
+}
+
 type Qid struct {
 	Qid int64 `json:"qid"`
 }
 
+type NoteReq struct {
+	Qid int64 `json:"qid"`
+	// Element 0 表示整道题，1 - 4 依次是分析、基本、进阶、高阶回答
+	Element uint8  `json:"element"`
+	Content string `json:"content"`
+}
+
+type SubmitReq struct {
+	Qid int64 `json:"qid"`
+	// 审核人
+	Reviewer int64 `json:"reviewer"`
+}
+
+type ReviewReq struct {
+	Qid      int64  `json:"qid"`
+	Approved bool   `json:"approved"`
+	Comment  string `json:"comment,omitempty"`
+}
+
+type VersionPage struct {
+	Qid    int64 `json:"qid"`
+	Offset int   `json:"offset,omitempty"`
+	Limit  int   `json:"limit,omitempty"`
+}
+
+type VersionReq struct {
+	Qid     int64 `json:"qid"`
+	Version int64 `json:"version"`
+}
+
+// DiffReq 版本号为 0 代表制作库中当前的内容
+type DiffReq struct {
+	Qid        int64 `json:"qid"`
+	OldVersion int64 `json:"oldVersion"`
+	NewVersion int64 `json:"newVersion"`
+}
+
+type QuestionVersion struct {
+	Version     int64    `json:"version"`
+	BaseVersion int64    `json:"baseVersion,omitempty"`
+	Uid         int64    `json:"uid,omitempty"`
+	Question    Question `json:"question"`
+	Ctime       string   `json:"ctime,omitempty"`
+}
+
+func newQuestionVersion(v domain.QuestionVersion) QuestionVersion {
+	return QuestionVersion{
+		Version:     v.Version,
+		BaseVersion: v.BaseVersion,
+		Uid:         v.Uid,
+		Question:    newQuestion(v.Question),
+		Ctime:       v.Ctime.Format(time.DateTime),
+	}
+}
+
+type QuestionVersionList struct {
+	Versions []QuestionVersion `json:"versions,omitempty"`
+	Total    int64             `json:"total,omitempty"`
+}
+
+type FieldDiff struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
 type QuestionList struct {
 	Questions []Question `json:"questions,omitempty"`
 	Total     int64      `json:"total,omitempty"`
@@ -148,9 +254,33 @@ type QuestionSet struct {
 	Description string     `json:"description,omitempty"`
 	Questions   []Question `json:"questions,omitempty"`
 	Utime       string     `json:"utime,omitempty"`
+	// 当前用户的掌握情况，只有线上题集详情才有
+	Progress *practice.ProgressVO `json:"progress,omitempty"`
 }
 
 type QuestionSetList struct {
 	Total        int64         `json:"total,omitempty"`
 	QuestionSets []QuestionSet `json:"questionSets,omitempty"`
 }
+
+type ExportReq struct {
+	Qids []int64 `json:"qids"`
+	// md 或者 json，md 会把每道题导出成一个文件，打包成 zip
+	Format string `json:"format"`
+}
+
+type ImportResult struct {
+	// 题目的来源，文件名或者 文件名[下标]
+	Name string `json:"name"`
+	Id   int64  `json:"id,omitempty"`
+	// 失败原因，成功的时候为空
+	Error string `json:"error,omitempty"`
+}
+
+func newImportResult(r domain.ImportResult) ImportResult {
+	res := ImportResult{Name: r.Name, Id: r.Id}
+	if r.Err != nil {
+		res.Error = r.Err.Error()
+	}
+	return res
+}
@@ -11,6 +11,7 @@ package quemocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	domain "github.com/ecodeclub/webook/internal/question/internal/domain"
 	gomock "go.uber.org/mock/gomock"
@@ -39,6 +40,44 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// Delete mocks base method.
+func (m *MockService) Delete(ctx context.Context, qid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, qid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockServiceMockRecorder) Delete(ctx, qid any) *ServiceDeleteCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockService)(nil).Delete), ctx, qid)
+	return &ServiceDeleteCall{Call: call}
+}
+
+// ServiceDeleteCall wrap *gomock.Call
+type ServiceDeleteCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceDeleteCall) Return(arg0 error) *ServiceDeleteCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceDeleteCall) Do(f func(context.Context, int64) error) *ServiceDeleteCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceDeleteCall) DoAndReturn(f func(context.Context, int64) error) *ServiceDeleteCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Detail mocks base method.
 func (m *MockService) Detail(ctx context.Context, qid int64) (domain.Question, error) {
 	m.ctrl.T.Helper()
@@ -78,6 +117,45 @@ func (c *ServiceDetailCall) DoAndReturn(f func(context.Context, int64) (domain.Q
 	return c
 }
 
+// Diff mocks base method.
+func (m *MockService) Diff(ctx context.Context, qid, oldVersion, newVersion int64) ([]domain.FieldDiff, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Diff", ctx, qid, oldVersion, newVersion)
+	ret0, _ := ret[0].([]domain.FieldDiff)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Diff indicates an expected call of Diff.
+func (mr *MockServiceMockRecorder) Diff(ctx, qid, oldVersion, newVersion any) *ServiceDiffCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Diff", reflect.TypeOf((*MockService)(nil).Diff), ctx, qid, oldVersion, newVersion)
+	return &ServiceDiffCall{Call: call}
+}
+
+// ServiceDiffCall wrap *gomock.Call
+type ServiceDiffCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceDiffCall) Return(arg0 []domain.FieldDiff, arg1 error) *ServiceDiffCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceDiffCall) Do(f func(context.Context, int64, int64, int64) ([]domain.FieldDiff, error)) *ServiceDiffCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceDiffCall) DoAndReturn(f func(context.Context, int64, int64, int64) ([]domain.FieldDiff, error)) *ServiceDiffCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // GetPubByIDs mocks base method.
 func (m *MockService) GetPubByIDs(ctx context.Context, ids []int64) ([]domain.Question, error) {
 	m.ctrl.T.Helper()
@@ -117,6 +195,45 @@ func (c *ServiceGetPubByIDsCall) DoAndReturn(f func(context.Context, []int64) ([
 	return c
 }
 
+// GetVersion mocks base method.
+func (m *MockService) GetVersion(ctx context.Context, qid, version int64) (domain.QuestionVersion, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVersion", ctx, qid, version)
+	ret0, _ := ret[0].(domain.QuestionVersion)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVersion indicates an expected call of GetVersion.
+func (mr *MockServiceMockRecorder) GetVersion(ctx, qid, version any) *ServiceGetVersionCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVersion", reflect.TypeOf((*MockService)(nil).GetVersion), ctx, qid, version)
+	return &ServiceGetVersionCall{Call: call}
+}
+
+// ServiceGetVersionCall wrap *gomock.Call
+type ServiceGetVersionCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceGetVersionCall) Return(arg0 domain.QuestionVersion, arg1 error) *ServiceGetVersionCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceGetVersionCall) Do(f func(context.Context, int64, int64) (domain.QuestionVersion, error)) *ServiceGetVersionCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceGetVersionCall) DoAndReturn(f func(context.Context, int64, int64) (domain.QuestionVersion, error)) *ServiceGetVersionCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // List mocks base method.
 func (m *MockService) List(ctx context.Context, offset, limit int) ([]domain.Question, int64, error) {
 	m.ctrl.T.Helper()
@@ -157,6 +274,126 @@ func (c *ServiceListCall) DoAndReturn(f func(context.Context, int, int) ([]domai
 	return c
 }
 
+// ListDeleted mocks base method.
+func (m *MockService) ListDeleted(ctx context.Context, offset, limit int) ([]domain.Question, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Question)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockServiceMockRecorder) ListDeleted(ctx, offset, limit any) *ServiceListDeletedCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockService)(nil).ListDeleted), ctx, offset, limit)
+	return &ServiceListDeletedCall{Call: call}
+}
+
+// ServiceListDeletedCall wrap *gomock.Call
+type ServiceListDeletedCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceListDeletedCall) Return(arg0 []domain.Question, arg1 int64, arg2 error) *ServiceListDeletedCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceListDeletedCall) Do(f func(context.Context, int, int) ([]domain.Question, int64, error)) *ServiceListDeletedCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceListDeletedCall) DoAndReturn(f func(context.Context, int, int) ([]domain.Question, int64, error)) *ServiceListDeletedCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListReview mocks base method.
+func (m *MockService) ListReview(ctx context.Context, reviewer int64, offset, limit int) ([]domain.Question, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListReview", ctx, reviewer, offset, limit)
+	ret0, _ := ret[0].([]domain.Question)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListReview indicates an expected call of ListReview.
+func (mr *MockServiceMockRecorder) ListReview(ctx, reviewer, offset, limit any) *ServiceListReviewCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListReview", reflect.TypeOf((*MockService)(nil).ListReview), ctx, reviewer, offset, limit)
+	return &ServiceListReviewCall{Call: call}
+}
+
+// ServiceListReviewCall wrap *gomock.Call
+type ServiceListReviewCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceListReviewCall) Return(arg0 []domain.Question, arg1 int64, arg2 error) *ServiceListReviewCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceListReviewCall) Do(f func(context.Context, int64, int, int) ([]domain.Question, int64, error)) *ServiceListReviewCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceListReviewCall) DoAndReturn(f func(context.Context, int64, int, int) ([]domain.Question, int64, error)) *ServiceListReviewCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListVersions mocks base method.
+func (m *MockService) ListVersions(ctx context.Context, qid int64, offset, limit int) ([]domain.QuestionVersion, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVersions", ctx, qid, offset, limit)
+	ret0, _ := ret[0].([]domain.QuestionVersion)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListVersions indicates an expected call of ListVersions.
+func (mr *MockServiceMockRecorder) ListVersions(ctx, qid, offset, limit any) *ServiceListVersionsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVersions", reflect.TypeOf((*MockService)(nil).ListVersions), ctx, qid, offset, limit)
+	return &ServiceListVersionsCall{Call: call}
+}
+
+// ServiceListVersionsCall wrap *gomock.Call
+type ServiceListVersionsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceListVersionsCall) Return(arg0 []domain.QuestionVersion, arg1 int64, arg2 error) *ServiceListVersionsCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceListVersionsCall) Do(f func(context.Context, int64, int, int) ([]domain.QuestionVersion, int64, error)) *ServiceListVersionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceListVersionsCall) DoAndReturn(f func(context.Context, int64, int, int) ([]domain.QuestionVersion, int64, error)) *ServiceListVersionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // PubDetail mocks base method.
 func (m *MockService) PubDetail(ctx context.Context, qid int64) (domain.Question, error) {
 	m.ctrl.T.Helper()
@@ -236,19 +473,58 @@ func (c *ServicePubListCall) DoAndReturn(f func(context.Context, int, int) ([]do
 	return c
 }
 
+// PubListByLabels mocks base method.
+func (m *MockService) PubListByLabels(ctx context.Context, lids []int64, matchAll bool, offset, limit int) ([]domain.Question, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PubListByLabels", ctx, lids, matchAll, offset, limit)
+	ret0, _ := ret[0].([]domain.Question)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PubListByLabels indicates an expected call of PubListByLabels.
+func (mr *MockServiceMockRecorder) PubListByLabels(ctx, lids, matchAll, offset, limit any) *ServicePubListByLabelsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PubListByLabels", reflect.TypeOf((*MockService)(nil).PubListByLabels), ctx, lids, matchAll, offset, limit)
+	return &ServicePubListByLabelsCall{Call: call}
+}
+
+// ServicePubListByLabelsCall wrap *gomock.Call
+type ServicePubListByLabelsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServicePubListByLabelsCall) Return(arg0 []domain.Question, arg1 int64, arg2 error) *ServicePubListByLabelsCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServicePubListByLabelsCall) Do(f func(context.Context, []int64, bool, int, int) ([]domain.Question, int64, error)) *ServicePubListByLabelsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServicePubListByLabelsCall) DoAndReturn(f func(context.Context, []int64, bool, int, int) ([]domain.Question, int64, error)) *ServicePubListByLabelsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
 // Publish mocks base method.
-func (m *MockService) Publish(ctx context.Context, que *domain.Question) (int64, error) {
+func (m *MockService) Publish(ctx context.Context, qid, uid int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Publish", ctx, que)
-	ret0, _ := ret[0].(int64)
-	ret1, _ := ret[1].(error)
-	return ret0, ret1
+	ret := m.ctrl.Call(m, "Publish", ctx, qid, uid)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // Publish indicates an expected call of Publish.
-func (mr *MockServiceMockRecorder) Publish(ctx, que any) *ServicePublishCall {
+func (mr *MockServiceMockRecorder) Publish(ctx, qid, uid any) *ServicePublishCall {
 	mr.mock.ctrl.T.Helper()
-	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockService)(nil).Publish), ctx, que)
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockService)(nil).Publish), ctx, qid, uid)
 	return &ServicePublishCall{Call: call}
 }
 
@@ -258,19 +534,212 @@ type ServicePublishCall struct {
 }
 
 // Return rewrite *gomock.Call.Return
-func (c *ServicePublishCall) Return(arg0 int64, arg1 error) *ServicePublishCall {
+func (c *ServicePublishCall) Return(arg0 error) *ServicePublishCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServicePublishCall) Do(f func(context.Context, int64, int64) error) *ServicePublishCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServicePublishCall) DoAndReturn(f func(context.Context, int64, int64) error) *ServicePublishCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Purge mocks base method.
+func (m *MockService) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockServiceMockRecorder) Purge(ctx, before, limit any) *ServicePurgeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockService)(nil).Purge), ctx, before, limit)
+	return &ServicePurgeCall{Call: call}
+}
+
+// ServicePurgeCall wrap *gomock.Call
+type ServicePurgeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServicePurgeCall) Return(arg0 int64, arg1 error) *ServicePurgeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServicePurgeCall) Do(f func(context.Context, time.Time, int) (int64, error)) *ServicePurgeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServicePurgeCall) DoAndReturn(f func(context.Context, time.Time, int) (int64, error)) *ServicePurgeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Reindex mocks base method.
+func (m *MockService) Reindex(ctx context.Context, minID int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reindex", ctx, minID, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reindex indicates an expected call of Reindex.
+func (mr *MockServiceMockRecorder) Reindex(ctx, minID, limit any) *ServiceReindexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reindex", reflect.TypeOf((*MockService)(nil).Reindex), ctx, minID, limit)
+	return &ServiceReindexCall{Call: call}
+}
+
+// ServiceReindexCall wrap *gomock.Call
+type ServiceReindexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceReindexCall) Return(arg0 int64, arg1 error) *ServiceReindexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceReindexCall) Do(f func(context.Context, int64, int) (int64, error)) *ServiceReindexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceReindexCall) DoAndReturn(f func(context.Context, int64, int) (int64, error)) *ServiceReindexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Restore mocks base method.
+func (m *MockService) Restore(ctx context.Context, qid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, qid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockServiceMockRecorder) Restore(ctx, qid any) *ServiceRestoreCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockService)(nil).Restore), ctx, qid)
+	return &ServiceRestoreCall{Call: call}
+}
+
+// ServiceRestoreCall wrap *gomock.Call
+type ServiceRestoreCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceRestoreCall) Return(arg0 error) *ServiceRestoreCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceRestoreCall) Do(f func(context.Context, int64) error) *ServiceRestoreCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceRestoreCall) DoAndReturn(f func(context.Context, int64) error) *ServiceRestoreCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Review mocks base method.
+func (m *MockService) Review(ctx context.Context, qid, reviewer int64, approved bool, comment string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Review", ctx, qid, reviewer, approved, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Review indicates an expected call of Review.
+func (mr *MockServiceMockRecorder) Review(ctx, qid, reviewer, approved, comment any) *ServiceReviewCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Review", reflect.TypeOf((*MockService)(nil).Review), ctx, qid, reviewer, approved, comment)
+	return &ServiceReviewCall{Call: call}
+}
+
+// ServiceReviewCall wrap *gomock.Call
+type ServiceReviewCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceReviewCall) Return(arg0 error) *ServiceReviewCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceReviewCall) Do(f func(context.Context, int64, int64, bool, string) error) *ServiceReviewCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceReviewCall) DoAndReturn(f func(context.Context, int64, int64, bool, string) error) *ServiceReviewCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Rollback mocks base method.
+func (m *MockService) Rollback(ctx context.Context, qid, version, uid int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Rollback", ctx, qid, version, uid)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Rollback indicates an expected call of Rollback.
+func (mr *MockServiceMockRecorder) Rollback(ctx, qid, version, uid any) *ServiceRollbackCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockService)(nil).Rollback), ctx, qid, version, uid)
+	return &ServiceRollbackCall{Call: call}
+}
+
+// ServiceRollbackCall wrap *gomock.Call
+type ServiceRollbackCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceRollbackCall) Return(arg0 int64, arg1 error) *ServiceRollbackCall {
 	c.Call = c.Call.Return(arg0, arg1)
 	return c
 }
 
 // Do rewrite *gomock.Call.Do
-func (c *ServicePublishCall) Do(f func(context.Context, *domain.Question) (int64, error)) *ServicePublishCall {
+func (c *ServiceRollbackCall) Do(f func(context.Context, int64, int64, int64) (int64, error)) *ServiceRollbackCall {
 	c.Call = c.Call.Do(f)
 	return c
 }
 
 // DoAndReturn rewrite *gomock.Call.DoAndReturn
-func (c *ServicePublishCall) DoAndReturn(f func(context.Context, *domain.Question) (int64, error)) *ServicePublishCall {
+func (c *ServiceRollbackCall) DoAndReturn(f func(context.Context, int64, int64, int64) (int64, error)) *ServiceRollbackCall {
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
@@ -313,3 +782,79 @@ func (c *ServiceSaveCall) DoAndReturn(f func(context.Context, *domain.Question)
 	c.Call = c.Call.DoAndReturn(f)
 	return c
 }
+
+// Submit mocks base method.
+func (m *MockService) Submit(ctx context.Context, qid, uid, reviewer int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Submit", ctx, qid, uid, reviewer)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Submit indicates an expected call of Submit.
+func (mr *MockServiceMockRecorder) Submit(ctx, qid, uid, reviewer any) *ServiceSubmitCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Submit", reflect.TypeOf((*MockService)(nil).Submit), ctx, qid, uid, reviewer)
+	return &ServiceSubmitCall{Call: call}
+}
+
+// ServiceSubmitCall wrap *gomock.Call
+type ServiceSubmitCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceSubmitCall) Return(arg0 error) *ServiceSubmitCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceSubmitCall) Do(f func(context.Context, int64, int64, int64) error) *ServiceSubmitCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceSubmitCall) DoAndReturn(f func(context.Context, int64, int64, int64) error) *ServiceSubmitCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Unpublish mocks base method.
+func (m *MockService) Unpublish(ctx context.Context, qid int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unpublish", ctx, qid)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unpublish indicates an expected call of Unpublish.
+func (mr *MockServiceMockRecorder) Unpublish(ctx, qid any) *ServiceUnpublishCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unpublish", reflect.TypeOf((*MockService)(nil).Unpublish), ctx, qid)
+	return &ServiceUnpublishCall{Call: call}
+}
+
+// ServiceUnpublishCall wrap *gomock.Call
+type ServiceUnpublishCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ServiceUnpublishCall) Return(arg0 error) *ServiceUnpublishCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ServiceUnpublishCall) Do(f func(context.Context, int64) error) *ServiceUnpublishCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ServiceUnpublishCall) DoAndReturn(f func(context.Context, int64) error) *ServiceUnpublishCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
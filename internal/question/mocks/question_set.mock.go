@@ -0,0 +1,469 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./question_set.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./question_set.go -destination=../../mocks/question_set.mock.go -package=quemocks -typed QuestionSetService
+//
+// Package quemocks is a generated GoMock package.
+package quemocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/ecodeclub/webook/internal/question/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockQuestionSetService is a mock of QuestionSetService interface.
+type MockQuestionSetService struct {
+	ctrl     *gomock.Controller
+	recorder *MockQuestionSetServiceMockRecorder
+}
+
+// MockQuestionSetServiceMockRecorder is the mock recorder for MockQuestionSetService.
+type MockQuestionSetServiceMockRecorder struct {
+	mock *MockQuestionSetService
+}
+
+// NewMockQuestionSetService creates a new mock instance.
+func NewMockQuestionSetService(ctrl *gomock.Controller) *MockQuestionSetService {
+	mock := &MockQuestionSetService{ctrl: ctrl}
+	mock.recorder = &MockQuestionSetServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockQuestionSetService) EXPECT() *MockQuestionSetServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockQuestionSetService) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockQuestionSetServiceMockRecorder) Delete(ctx, id any) *QuestionSetServiceDeleteCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockQuestionSetService)(nil).Delete), ctx, id)
+	return &QuestionSetServiceDeleteCall{Call: call}
+}
+
+// QuestionSetServiceDeleteCall wrap *gomock.Call
+type QuestionSetServiceDeleteCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServiceDeleteCall) Return(arg0 error) *QuestionSetServiceDeleteCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServiceDeleteCall) Do(f func(context.Context, int64) error) *QuestionSetServiceDeleteCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServiceDeleteCall) DoAndReturn(f func(context.Context, int64) error) *QuestionSetServiceDeleteCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Detail mocks base method.
+func (m *MockQuestionSetService) Detail(ctx context.Context, id int64) (domain.QuestionSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Detail", ctx, id)
+	ret0, _ := ret[0].(domain.QuestionSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Detail indicates an expected call of Detail.
+func (mr *MockQuestionSetServiceMockRecorder) Detail(ctx, id any) *QuestionSetServiceDetailCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Detail", reflect.TypeOf((*MockQuestionSetService)(nil).Detail), ctx, id)
+	return &QuestionSetServiceDetailCall{Call: call}
+}
+
+// QuestionSetServiceDetailCall wrap *gomock.Call
+type QuestionSetServiceDetailCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServiceDetailCall) Return(arg0 domain.QuestionSet, arg1 error) *QuestionSetServiceDetailCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServiceDetailCall) Do(f func(context.Context, int64) (domain.QuestionSet, error)) *QuestionSetServiceDetailCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServiceDetailCall) DoAndReturn(f func(context.Context, int64) (domain.QuestionSet, error)) *QuestionSetServiceDetailCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// List mocks base method.
+func (m *MockQuestionSetService) List(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.QuestionSet)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockQuestionSetServiceMockRecorder) List(ctx, offset, limit any) *QuestionSetServiceListCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockQuestionSetService)(nil).List), ctx, offset, limit)
+	return &QuestionSetServiceListCall{Call: call}
+}
+
+// QuestionSetServiceListCall wrap *gomock.Call
+type QuestionSetServiceListCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServiceListCall) Return(arg0 []domain.QuestionSet, arg1 int64, arg2 error) *QuestionSetServiceListCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServiceListCall) Do(f func(context.Context, int, int) ([]domain.QuestionSet, int64, error)) *QuestionSetServiceListCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServiceListCall) DoAndReturn(f func(context.Context, int, int) ([]domain.QuestionSet, int64, error)) *QuestionSetServiceListCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListDeleted mocks base method.
+func (m *MockQuestionSetService) ListDeleted(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.QuestionSet)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockQuestionSetServiceMockRecorder) ListDeleted(ctx, offset, limit any) *QuestionSetServiceListDeletedCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockQuestionSetService)(nil).ListDeleted), ctx, offset, limit)
+	return &QuestionSetServiceListDeletedCall{Call: call}
+}
+
+// QuestionSetServiceListDeletedCall wrap *gomock.Call
+type QuestionSetServiceListDeletedCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServiceListDeletedCall) Return(arg0 []domain.QuestionSet, arg1 int64, arg2 error) *QuestionSetServiceListDeletedCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServiceListDeletedCall) Do(f func(context.Context, int, int) ([]domain.QuestionSet, int64, error)) *QuestionSetServiceListDeletedCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServiceListDeletedCall) DoAndReturn(f func(context.Context, int, int) ([]domain.QuestionSet, int64, error)) *QuestionSetServiceListDeletedCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// PubDetail mocks base method.
+func (m *MockQuestionSetService) PubDetail(ctx context.Context, id int64) (domain.QuestionSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PubDetail", ctx, id)
+	ret0, _ := ret[0].(domain.QuestionSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PubDetail indicates an expected call of PubDetail.
+func (mr *MockQuestionSetServiceMockRecorder) PubDetail(ctx, id any) *QuestionSetServicePubDetailCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PubDetail", reflect.TypeOf((*MockQuestionSetService)(nil).PubDetail), ctx, id)
+	return &QuestionSetServicePubDetailCall{Call: call}
+}
+
+// QuestionSetServicePubDetailCall wrap *gomock.Call
+type QuestionSetServicePubDetailCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServicePubDetailCall) Return(arg0 domain.QuestionSet, arg1 error) *QuestionSetServicePubDetailCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServicePubDetailCall) Do(f func(context.Context, int64) (domain.QuestionSet, error)) *QuestionSetServicePubDetailCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServicePubDetailCall) DoAndReturn(f func(context.Context, int64) (domain.QuestionSet, error)) *QuestionSetServicePubDetailCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// PubList mocks base method.
+func (m *MockQuestionSetService) PubList(ctx context.Context, offset, limit int) ([]domain.QuestionSet, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PubList", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.QuestionSet)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// PubList indicates an expected call of PubList.
+func (mr *MockQuestionSetServiceMockRecorder) PubList(ctx, offset, limit any) *QuestionSetServicePubListCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PubList", reflect.TypeOf((*MockQuestionSetService)(nil).PubList), ctx, offset, limit)
+	return &QuestionSetServicePubListCall{Call: call}
+}
+
+// QuestionSetServicePubListCall wrap *gomock.Call
+type QuestionSetServicePubListCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServicePubListCall) Return(arg0 []domain.QuestionSet, arg1 int64, arg2 error) *QuestionSetServicePubListCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServicePubListCall) Do(f func(context.Context, int, int) ([]domain.QuestionSet, int64, error)) *QuestionSetServicePubListCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServicePubListCall) DoAndReturn(f func(context.Context, int, int) ([]domain.QuestionSet, int64, error)) *QuestionSetServicePubListCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Publish mocks base method.
+func (m *MockQuestionSetService) Publish(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Publish", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Publish indicates an expected call of Publish.
+func (mr *MockQuestionSetServiceMockRecorder) Publish(ctx, id any) *QuestionSetServicePublishCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Publish", reflect.TypeOf((*MockQuestionSetService)(nil).Publish), ctx, id)
+	return &QuestionSetServicePublishCall{Call: call}
+}
+
+// QuestionSetServicePublishCall wrap *gomock.Call
+type QuestionSetServicePublishCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServicePublishCall) Return(arg0 error) *QuestionSetServicePublishCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServicePublishCall) Do(f func(context.Context, int64) error) *QuestionSetServicePublishCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServicePublishCall) DoAndReturn(f func(context.Context, int64) error) *QuestionSetServicePublishCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Purge mocks base method.
+func (m *MockQuestionSetService) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockQuestionSetServiceMockRecorder) Purge(ctx, before, limit any) *QuestionSetServicePurgeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockQuestionSetService)(nil).Purge), ctx, before, limit)
+	return &QuestionSetServicePurgeCall{Call: call}
+}
+
+// QuestionSetServicePurgeCall wrap *gomock.Call
+type QuestionSetServicePurgeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServicePurgeCall) Return(arg0 int64, arg1 error) *QuestionSetServicePurgeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServicePurgeCall) Do(f func(context.Context, time.Time, int) (int64, error)) *QuestionSetServicePurgeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServicePurgeCall) DoAndReturn(f func(context.Context, time.Time, int) (int64, error)) *QuestionSetServicePurgeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Restore mocks base method.
+func (m *MockQuestionSetService) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockQuestionSetServiceMockRecorder) Restore(ctx, id any) *QuestionSetServiceRestoreCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockQuestionSetService)(nil).Restore), ctx, id)
+	return &QuestionSetServiceRestoreCall{Call: call}
+}
+
+// QuestionSetServiceRestoreCall wrap *gomock.Call
+type QuestionSetServiceRestoreCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServiceRestoreCall) Return(arg0 error) *QuestionSetServiceRestoreCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServiceRestoreCall) Do(f func(context.Context, int64) error) *QuestionSetServiceRestoreCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServiceRestoreCall) DoAndReturn(f func(context.Context, int64) error) *QuestionSetServiceRestoreCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Save mocks base method.
+func (m *MockQuestionSetService) Save(ctx context.Context, set domain.QuestionSet) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, set)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockQuestionSetServiceMockRecorder) Save(ctx, set any) *QuestionSetServiceSaveCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockQuestionSetService)(nil).Save), ctx, set)
+	return &QuestionSetServiceSaveCall{Call: call}
+}
+
+// QuestionSetServiceSaveCall wrap *gomock.Call
+type QuestionSetServiceSaveCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServiceSaveCall) Return(arg0 int64, arg1 error) *QuestionSetServiceSaveCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServiceSaveCall) Do(f func(context.Context, domain.QuestionSet) (int64, error)) *QuestionSetServiceSaveCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServiceSaveCall) DoAndReturn(f func(context.Context, domain.QuestionSet) (int64, error)) *QuestionSetServiceSaveCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// UpdateQuestions mocks base method.
+func (m *MockQuestionSetService) UpdateQuestions(ctx context.Context, set domain.QuestionSet) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateQuestions", ctx, set)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateQuestions indicates an expected call of UpdateQuestions.
+func (mr *MockQuestionSetServiceMockRecorder) UpdateQuestions(ctx, set any) *QuestionSetServiceUpdateQuestionsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateQuestions", reflect.TypeOf((*MockQuestionSetService)(nil).UpdateQuestions), ctx, set)
+	return &QuestionSetServiceUpdateQuestionsCall{Call: call}
+}
+
+// QuestionSetServiceUpdateQuestionsCall wrap *gomock.Call
+type QuestionSetServiceUpdateQuestionsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *QuestionSetServiceUpdateQuestionsCall) Return(arg0 error) *QuestionSetServiceUpdateQuestionsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *QuestionSetServiceUpdateQuestionsCall) Do(f func(context.Context, domain.QuestionSet) error) *QuestionSetServiceUpdateQuestionsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *QuestionSetServiceUpdateQuestionsCall) DoAndReturn(f func(context.Context, domain.QuestionSet) error) *QuestionSetServiceUpdateQuestionsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
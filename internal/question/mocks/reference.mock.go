@@ -0,0 +1,78 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./reference.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./reference.go -destination=../../mocks/reference.mock.go -package=quemocks -typed ReferenceService
+//
+// Package quemocks is a generated GoMock package.
+package quemocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockReferenceService is a mock of ReferenceService interface.
+type MockReferenceService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReferenceServiceMockRecorder
+}
+
+// MockReferenceServiceMockRecorder is the mock recorder for MockReferenceService.
+type MockReferenceServiceMockRecorder struct {
+	mock *MockReferenceService
+}
+
+// NewMockReferenceService creates a new mock instance.
+func NewMockReferenceService(ctrl *gomock.Controller) *MockReferenceService {
+	mock := &MockReferenceService{ctrl: ctrl}
+	mock.recorder = &MockReferenceServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReferenceService) EXPECT() *MockReferenceServiceMockRecorder {
+	return m.recorder
+}
+
+// Referenced mocks base method.
+func (m *MockReferenceService) Referenced(ctx context.Context, qid int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Referenced", ctx, qid)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Referenced indicates an expected call of Referenced.
+func (mr *MockReferenceServiceMockRecorder) Referenced(ctx, qid any) *ReferenceServiceReferencedCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Referenced", reflect.TypeOf((*MockReferenceService)(nil).Referenced), ctx, qid)
+	return &ReferenceServiceReferencedCall{Call: call}
+}
+
+// ReferenceServiceReferencedCall wrap *gomock.Call
+type ReferenceServiceReferencedCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *ReferenceServiceReferencedCall) Return(arg0 bool, arg1 error) *ReferenceServiceReferencedCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *ReferenceServiceReferencedCall) Do(f func(context.Context, int64) (bool, error)) *ReferenceServiceReferencedCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *ReferenceServiceReferencedCall) DoAndReturn(f func(context.Context, int64) (bool, error)) *ReferenceServiceReferencedCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
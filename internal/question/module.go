@@ -18,4 +18,12 @@ type Module struct {
 	Svc   Service
 	Hdl   *Handler
 	QsHdl *QuestionSetHandler
+	QsSvc QuestionSetService
+	// 命令行批量导入导出
+	ImportJob *ImportJob
+	ExportJob *ExportJob
+	// 定时清理回收站
+	PurgeJob *PurgeJob
+	// 命令行重新同步到标签和搜索
+	ReindexJob *ReindexJob
 }
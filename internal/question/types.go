@@ -16,6 +16,7 @@ package baguwen
 
 import (
 	"github.com/ecodeclub/webook/internal/question/internal/domain"
+	"github.com/ecodeclub/webook/internal/question/internal/job"
 	"github.com/ecodeclub/webook/internal/question/internal/service"
 	"github.com/ecodeclub/webook/internal/question/internal/web"
 )
@@ -24,4 +25,16 @@ type Handler = web.Handler
 type QuestionSetHandler = web.QuestionSetHandler
 
 type Service = service.Service
+type QuestionSetService = service.QuestionSetService
+type RelatedService = service.RelatedService
+type ReferenceService = service.ReferenceService
 type Question = domain.Question
+type Answer = domain.Answer
+type AnswerElement = domain.AnswerElement
+type QuestionSet = domain.QuestionSet
+type Related = domain.Related
+
+type ImportJob = job.ImportJob
+type ExportJob = job.ExportJob
+type PurgeJob = job.PurgeJob
+type ReindexJob = job.ReindexJob
@@ -18,9 +18,16 @@ package baguwen
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/practice"
 
+	"github.com/ecodeclub/webook/internal/question/internal/event"
+	"github.com/ecodeclub/webook/internal/question/internal/job"
 	"github.com/ecodeclub/webook/internal/question/internal/repository"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/dao"
@@ -31,12 +38,30 @@ import (
 	"gorm.io/gorm"
 )
 
-func InitModule(db *egorm.Component, ec ecache.Cache) (*Module, error) {
+func InitModule(db *egorm.Component, ec ecache.Cache, q mq.MQ,
+	labelModule *label.Module, practiceModule *practice.Module,
+	noteModule *note.Module, relatedSvc RelatedService, refSvc ReferenceService) (*Module, error) {
+	wire.Build(initSyncEventProducer, InitModuleWithProducer)
+	return new(Module), nil
+}
+
+// InitModuleWithProducer 测试的时候可以传入 mock 的 producer
+func InitModuleWithProducer(db *egorm.Component, ec ecache.Cache,
+	p event.SyncEventProducer, labelModule *label.Module, practiceModule *practice.Module,
+	noteModule *note.Module, relatedSvc RelatedService, refSvc ReferenceService) (*Module, error) {
 	wire.Build(InitQuestionDAO,
+		wire.FieldsOf(new(*label.Module), "Svc"),
+		wire.FieldsOf(new(*practice.Module), "Svc"),
+		wire.FieldsOf(new(*note.Module), "Svc"),
 		cache.NewQuestionECache,
 		repository.NewCacheRepository,
 		service.NewService,
+		service.NewTransferService,
 		web.NewHandler,
+		job.NewImportJob,
+		job.NewExportJob,
+		initPurgeJob,
+		initReindexJob,
 
 		InitQuestionSetDAO,
 		repository.NewQuestionSetRepository,
@@ -68,3 +93,20 @@ func InitQuestionSetDAO(db *egorm.Component) dao.QuestionSetDAO {
 	InitTableOnce(db)
 	return dao.NewGORMQuestionSetDAO(db)
 }
+
+// initPurgeJob 回收站里面的问题和题集保留 30 天
+func initPurgeJob(svc service.Service, qsSvc service.QuestionSetService) *PurgeJob {
+	return job.NewPurgeJob(svc, qsSvc, 30, 100, time.Hour)
+}
+
+func initReindexJob(svc service.Service) *ReindexJob {
+	return job.NewReindexJob(svc, 100)
+}
+
+func initSyncEventProducer(q mq.MQ) event.SyncEventProducer {
+	producer, err := event.NewSyncEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
+}
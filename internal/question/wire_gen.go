@@ -8,8 +8,15 @@ package baguwen
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/label"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/practice"
+	"github.com/ecodeclub/webook/internal/question/internal/event"
+	"github.com/ecodeclub/webook/internal/question/internal/job"
 	"github.com/ecodeclub/webook/internal/question/internal/repository"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/question/internal/repository/dao"
@@ -21,23 +28,46 @@ import (
 
 // Injectors from wire.go:
 
-func InitModule(db *gorm.DB, ec ecache.Cache) (*Module, error) {
+func InitModule(db *gorm.DB, ec ecache.Cache, q mq.MQ, labelModule *label.Module, practiceModule *practice.Module, noteModule *note.Module, relatedSvc service.RelatedService, refSvc service.ReferenceService) (*Module, error) {
+	syncEventProducer := initSyncEventProducer(q)
+	module, err := InitModuleWithProducer(db, ec, syncEventProducer, labelModule, practiceModule, noteModule, relatedSvc, refSvc)
+	if err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// InitModuleWithProducer 测试的时候可以传入 mock 的 producer
+func InitModuleWithProducer(db *gorm.DB, ec ecache.Cache, p event.SyncEventProducer, labelModule *label.Module, practiceModule *practice.Module, noteModule *note.Module, relatedSvc service.RelatedService, refSvc service.ReferenceService) (*Module, error) {
 	questionDAO := InitQuestionDAO(db)
 	questionCache := cache.NewQuestionECache(ec)
 	repositoryRepository := repository.NewCacheRepository(questionDAO, questionCache)
-	serviceService := service.NewService(repositoryRepository)
-	handler := web.NewHandler(serviceService)
+	serviceService := labelModule.Svc
+	service2 := service.NewService(repositoryRepository, serviceService, p, refSvc, relatedSvc)
+	transferService := service.NewTransferService(service2)
+	service3 := practiceModule.Svc
+	service4 := noteModule.Svc
+	handler := web.NewHandler(service2, transferService, service3, service4, relatedSvc)
 	questionSetDAO := InitQuestionSetDAO(db)
 	questionSetRepository := repository.NewQuestionSetRepository(questionSetDAO)
 	questionSetService := service.NewQuestionSetService(questionSetRepository)
-	questionSetHandler, err := web.NewQuestionSetHandler(questionSetService)
+	questionSetHandler, err := web.NewQuestionSetHandler(questionSetService, service3)
 	if err != nil {
 		return nil, err
 	}
+	importJob := job.NewImportJob(transferService)
+	exportJob := job.NewExportJob(transferService)
+	purgeJob := initPurgeJob(service2, questionSetService)
+	reindexJob := initReindexJob(service2)
 	module := &Module{
-		Svc:   serviceService,
-		Hdl:   handler,
-		QsHdl: questionSetHandler,
+		Svc:        service2,
+		Hdl:        handler,
+		QsHdl:      questionSetHandler,
+		QsSvc:      questionSetService,
+		ImportJob:  importJob,
+		ExportJob:  exportJob,
+		PurgeJob:   purgeJob,
+		ReindexJob: reindexJob,
 	}
 	return module, nil
 }
@@ -64,3 +94,20 @@ func InitQuestionSetDAO(db *egorm.Component) dao.QuestionSetDAO {
 	InitTableOnce(db)
 	return dao.NewGORMQuestionSetDAO(db)
 }
+
+// initPurgeJob 回收站里面的问题和题集保留 30 天
+func initPurgeJob(svc service.Service, qsSvc service.QuestionSetService) *PurgeJob {
+	return job.NewPurgeJob(svc, qsSvc, 30, 100, time.Hour)
+}
+
+func initReindexJob(svc service.Service) *ReindexJob {
+	return job.NewReindexJob(svc, 100)
+}
+
+func initSyncEventProducer(q mq.MQ) event.SyncEventProducer {
+	producer, err := event.NewSyncEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
+}
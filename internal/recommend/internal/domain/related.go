@@ -0,0 +1,161 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	BizQuestion = "question"
+	BizCase     = "case"
+
+	// 共同的标签、技能和题集对相关程度的贡献，题集是人工编排的，所以权重最高
+	LabelWeight = 1
+	SkillWeight = 2
+	SetWeight   = 3
+)
+
+// Item 已经发布了的题目或者案例
+type Item struct {
+	Biz    string
+	BizId  int64
+	Title  string
+	Labels []string
+}
+
+// Group 同一个技能或者同一个题集里面的内容
+type Group struct {
+	Questions []int64
+	Cases     []int64
+}
+
+// Related 预先计算好的相关内容
+type Related struct {
+	Qid   int64
+	Biz   string
+	BizId int64
+	Title string
+	Score int
+	Ctime time.Time
+}
+
+// Corpus 计算相关推荐需要的全部数据
+type Corpus struct {
+	Questions []Item
+	Cases     []Item
+	// Skills 每个技能引用的题目和案例，不区分级别
+	Skills []Group
+	// Sets 每个题集里面的题目
+	Sets []Group
+}
+
+type key struct {
+	biz   string
+	bizId int64
+}
+
+// TopK 计算每道题最相关的 k 个题目或者案例，没有任何关联的题目不在返回值里面。
+// 技能和题集里面引用了但是没有发布的内容会被忽略
+func (c Corpus) TopK(k int) map[int64][]Related {
+	items := make(map[key]Item, len(c.Questions)+len(c.Cases))
+	byLabel := make(map[string][]key)
+	for _, list := range [][]Item{c.Questions, c.Cases} {
+		for _, it := range list {
+			ik := key{biz: it.Biz, bizId: it.BizId}
+			items[ik] = it
+			for _, l := range it.Labels {
+				byLabel[l] = append(byLabel[l], ik)
+			}
+		}
+	}
+	// 每道题出现在哪些技能和题集里面
+	skillsOf, skillMembers := c.index(c.Skills, items)
+	setsOf, setMembers := c.index(c.Sets, items)
+
+	res := make(map[int64][]Related, len(c.Questions))
+	for _, q := range c.Questions {
+		self := key{biz: q.Biz, bizId: q.BizId}
+		scores := make(map[key]int)
+		for _, l := range q.Labels {
+			for _, other := range byLabel[l] {
+				scores[other] += LabelWeight
+			}
+		}
+		for _, g := range skillsOf[self] {
+			for _, other := range skillMembers[g] {
+				scores[other] += SkillWeight
+			}
+		}
+		for _, g := range setsOf[self] {
+			for _, other := range setMembers[g] {
+				scores[other] += SetWeight
+			}
+		}
+		delete(scores, self)
+		if len(scores) == 0 {
+			continue
+		}
+		rs := make([]Related, 0, len(scores))
+		for ik, score := range scores {
+			rs = append(rs, Related{
+				Qid:   q.BizId,
+				Biz:   ik.biz,
+				BizId: ik.bizId,
+				Title: items[ik].Title,
+				Score: score,
+			})
+		}
+		// 分数相同的时候题目排在案例前面，再按照 id 排序，保证结果稳定
+		sort.Slice(rs, func(i, j int) bool {
+			if rs[i].Score != rs[j].Score {
+				return rs[i].Score > rs[j].Score
+			}
+			if rs[i].Biz != rs[j].Biz {
+				return rs[i].Biz == BizQuestion
+			}
+			return rs[i].BizId < rs[j].BizId
+		})
+		res[q.BizId] = rs[:min(k, len(rs))]
+	}
+	return res
+}
+
+// index 返回每个内容所在的分组下标，以及每个分组里面已经发布的内容，重复引用只算一次
+func (c Corpus) index(groups []Group, items map[key]Item) (map[key][]int, [][]key) {
+	groupsOf := make(map[key][]int)
+	members := make([][]key, len(groups))
+	for idx, g := range groups {
+		seen := make(map[key]struct{}, len(g.Questions)+len(g.Cases))
+		add := func(biz string, ids []int64) {
+			for _, id := range ids {
+				ik := key{biz: biz, bizId: id}
+				if _, ok := items[ik]; !ok {
+					continue
+				}
+				if _, ok := seen[ik]; ok {
+					continue
+				}
+				seen[ik] = struct{}{}
+				members[idx] = append(members[idx], ik)
+				groupsOf[ik] = append(groupsOf[ik], idx)
+			}
+		}
+		add(BizQuestion, g.Questions)
+		add(BizCase, g.Cases)
+	}
+	return groupsOf, members
+}
@@ -0,0 +1,65 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCorpus_TopK(t *testing.T) {
+	corpus := Corpus{
+		Questions: []Item{
+			{Biz: BizQuestion, BizId: 1, Title: "Redis 单线程", Labels: []string{"Redis", "性能"}},
+			{Biz: BizQuestion, BizId: 2, Title: "Redis 持久化", Labels: []string{"Redis"}},
+			{Biz: BizQuestion, BizId: 3, Title: "MySQL 索引", Labels: []string{"MySQL", "性能"}},
+			{Biz: BizQuestion, BizId: 4, Title: "Kafka 消息丢失", Labels: []string{"Kafka"}},
+			{Biz: BizQuestion, BizId: 5, Title: "没有任何关联"},
+		},
+		Cases: []Item{
+			{Biz: BizCase, BizId: 1, Title: "Redis 分布式锁", Labels: []string{"Redis"}},
+		},
+		Skills: []Group{
+			// 100 没有发布，重复引用只算一次
+			{Questions: []int64{1, 3, 3, 100}, Cases: []int64{1}},
+		},
+		Sets: []Group{
+			{Questions: []int64{1, 4}},
+		},
+	}
+	res := corpus.TopK(3)
+	assert.Equal(t, map[int64][]Related{
+		1: {
+			// 技能 2 分 + 标签 性能 1 分
+			{Qid: 1, Biz: BizQuestion, BizId: 3, Title: "MySQL 索引", Score: 3},
+			// 题集 3 分
+			{Qid: 1, Biz: BizQuestion, BizId: 4, Title: "Kafka 消息丢失", Score: 3},
+			// 技能 2 分 + 标签 Redis 1 分，分数相同的时候题目在前
+			{Qid: 1, Biz: BizCase, BizId: 1, Title: "Redis 分布式锁", Score: 3},
+		},
+		2: {
+			{Qid: 2, Biz: BizQuestion, BizId: 1, Title: "Redis 单线程", Score: 1},
+			{Qid: 2, Biz: BizCase, BizId: 1, Title: "Redis 分布式锁", Score: 1},
+		},
+		3: {
+			{Qid: 3, Biz: BizQuestion, BizId: 1, Title: "Redis 单线程", Score: 3},
+			{Qid: 3, Biz: BizCase, BizId: 1, Title: "Redis 分布式锁", Score: 2},
+		},
+		4: {
+			{Qid: 4, Biz: BizQuestion, BizId: 1, Title: "Redis 单线程", Score: 3},
+		},
+	}, res)
+}
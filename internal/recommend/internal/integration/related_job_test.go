@@ -0,0 +1,179 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/cases"
+	casemocks "github.com/ecodeclub/webook/internal/cases/mocks"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	quemocks "github.com/ecodeclub/webook/internal/question/mocks"
+	"github.com/ecodeclub/webook/internal/recommend"
+	"github.com/ecodeclub/webook/internal/recommend/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/skill"
+	skillmocks "github.com/ecodeclub/webook/internal/skill/mocks"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+type RelatedJobTestSuite struct {
+	suite.Suite
+	db     *egorm.Component
+	module *recommend.Module
+}
+
+func (s *RelatedJobTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	queSvc := quemocks.NewMockService(ctrl)
+	queSvc.EXPECT().PubList(gomock.Any(), 0, gomock.Any()).Return([]baguwen.Question{
+		{Id: 1, Title: "Redis 单线程", Labels: []string{"Redis"}},
+		{Id: 2, Title: "Redis 持久化", Labels: []string{"Redis"}},
+		{Id: 3, Title: "MySQL 索引", Labels: []string{"MySQL"}},
+		{Id: 4, Title: "孤立的题目"},
+	}, int64(4), nil).AnyTimes()
+	qsSvc := quemocks.NewMockQuestionSetService(ctrl)
+	qsSvc.EXPECT().PubList(gomock.Any(), 0, gomock.Any()).
+		Return([]baguwen.QuestionSet{{Id: 1}}, int64(1), nil).AnyTimes()
+	qsSvc.EXPECT().PubDetail(gomock.Any(), int64(1)).Return(baguwen.QuestionSet{
+		Id:        1,
+		Questions: []baguwen.Question{{Id: 1}, {Id: 3}},
+	}, nil).AnyTimes()
+	caseSvc := casemocks.NewMockService(ctrl)
+	caseSvc.EXPECT().PubList(gomock.Any(), 0, gomock.Any()).Return([]cases.Case{
+		{Id: 1, Title: "Redis 分布式锁", Labels: []string{"Redis"}},
+	}, int64(1), nil).AnyTimes()
+	skillSvc := skillmocks.NewMockSkillService(ctrl)
+	skillSvc.EXPECT().List(gomock.Any(), 0, gomock.Any()).Return([]skill.Skill{
+		{ID: 1, Basic: skill.SkillLevel{Id: 11}, Advanced: skill.SkillLevel{Id: 12}},
+	}, int64(1), nil).AnyTimes()
+	skillSvc.EXPECT().RefsByLevelIDs(gomock.Any(), gomock.Any()).Return([]skill.SkillLevel{
+		{Id: 11, Questions: []int64{2}},
+		{Id: 12, Cases: []int64{1}},
+	}, nil).AnyTimes()
+
+	s.module = startup.InitModule(&baguwen.Module{Svc: queSvc, QsSvc: qsSvc},
+		&cases.Module{Svc: caseSvc}, &skill.Module{Svc: skillSvc})
+	s.db = testioc.InitDB()
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+}
+
+func (s *RelatedJobTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `related_items`").Error
+	require.NoError(s.T(), err)
+}
+
+func (s *RelatedJobTestSuite) TearDownSuite() {
+	err := s.db.Exec("DROP TABLE `related_items`").Error
+	require.NoError(s.T(), err)
+}
+
+func (s *RelatedJobTestSuite) TestRun() {
+	t := s.T()
+	// 上一轮计算的结果，题目已经下架了
+	err := s.db.Create(&dao.RelatedItem{Qid: 5, Biz: "question", BizId: 1, Title: "旧的", Ctime: 1}).Error
+	require.NoError(t, err)
+
+	err = s.module.RelatedJob.Run()
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name string
+		qid  int64
+		want []baguwen.Related
+	}{
+		{
+			name: "标签和题集",
+			qid:  1,
+			want: []baguwen.Related{
+				// 题集 3 分
+				{Biz: "question", BizId: 3, Title: "MySQL 索引"},
+				// 标签 1 分
+				{Biz: "question", BizId: 2, Title: "Redis 持久化"},
+				{Biz: "case", BizId: 1, Title: "Redis 分布式锁"},
+			},
+		},
+		{
+			name: "标签和技能",
+			qid:  2,
+			want: []baguwen.Related{
+				// 技能 2 分 + 标签 1 分
+				{Biz: "case", BizId: 1, Title: "Redis 分布式锁"},
+				{Biz: "question", BizId: 1, Title: "Redis 单线程"},
+			},
+		},
+		{
+			name: "没有关联",
+			qid:  4,
+			want: []baguwen.Related{},
+		},
+		{
+			name: "上一轮的结果被清理掉了",
+			qid:  5,
+			want: []baguwen.Related{},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+			defer cancel()
+			rs, err := s.module.Svc.Related(ctx, tc.qid)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, rs)
+		})
+	}
+}
+
+func (s *RelatedJobTestSuite) TestRemove() {
+	t := s.T()
+	items := []dao.RelatedItem{
+		{Qid: 1, Rank: 0, Biz: "question", BizId: 2, Title: "题目 2", Ctime: 1},
+		{Qid: 1, Rank: 1, Biz: "case", BizId: 1, Title: "案例 1", Ctime: 1},
+		{Qid: 1, Rank: 2, Biz: "question", BizId: 3, Title: "题目 3", Ctime: 1},
+		{Qid: 3, Rank: 0, Biz: "question", BizId: 2, Title: "题目 2", Ctime: 1},
+		// 同样 id 的案例不受影响
+		{Qid: 3, Rank: 1, Biz: "case", BizId: 2, Title: "案例 2", Ctime: 1},
+	}
+	err := s.db.Create(&items).Error
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	err = s.module.Svc.RemoveQuestion(ctx, 2)
+	require.NoError(t, err)
+	err = s.module.Svc.RemoveCase(ctx, 1)
+	require.NoError(t, err)
+
+	rs, err := s.module.Svc.Related(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []baguwen.Related{{Biz: "question", BizId: 3, Title: "题目 3"}}, rs)
+	rs, err = s.module.Svc.Related(ctx, 3)
+	require.NoError(t, err)
+	assert.Equal(t, []baguwen.Related{{Biz: "case", BizId: 2, Title: "案例 2"}}, rs)
+}
+
+func TestRelatedJob(t *testing.T) {
+	suite.Run(t, new(RelatedJobTestSuite))
+}
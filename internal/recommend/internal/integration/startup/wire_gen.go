@@ -0,0 +1,23 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package startup
+
+import (
+	"github.com/ecodeclub/webook/internal/cases"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend"
+	"github.com/ecodeclub/webook/internal/skill"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+)
+
+// Injectors from wire.go:
+
+func InitModule(queModule *baguwen.Module, caseModule *cases.Module, skillModule *skill.Module) *recommend.Module {
+	db := testioc.InitDB()
+	module := recommend.InitModule(db, queModule, caseModule, skillModule)
+	return module
+}
@@ -0,0 +1,215 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/cases"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend/internal/domain"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// RelatedJob 定时重新计算每道已发布题目的相关内容。
+// 数据量不大，每次都全量加载到内存里面计算
+type RelatedJob struct {
+	queSvc   baguwen.Service
+	qsSvc    baguwen.QuestionSetService
+	caseSvc  cases.Service
+	skillSvc skill.Service
+	repo     repository.RelatedRepository
+	// 每道题保留多少个相关内容
+	k int
+	// 分批加载数据的时候每批的大小
+	batchSize int
+	timeout   time.Duration
+	logger    *elog.Component
+}
+
+func NewRelatedJob(queSvc baguwen.Service,
+	qsSvc baguwen.QuestionSetService,
+	caseSvc cases.Service,
+	skillSvc skill.Service,
+	repo repository.RelatedRepository,
+	k int, batchSize int, timeout time.Duration) *RelatedJob {
+	return &RelatedJob{
+		queSvc:    queSvc,
+		qsSvc:     qsSvc,
+		caseSvc:   caseSvc,
+		skillSvc:  skillSvc,
+		repo:      repo,
+		k:         k,
+		batchSize: batchSize,
+		timeout:   timeout,
+		logger:    elog.DefaultLogger,
+	}
+}
+
+func (j *RelatedJob) Name() string {
+	return "RecommendRelatedJob"
+}
+
+func (j *RelatedJob) Run() error {
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+	start := time.Now()
+	corpus, err := j.load(ctx)
+	if err != nil {
+		return err
+	}
+	var failed int
+	for qid, rs := range corpus.TopK(j.k) {
+		err = j.repo.Save(ctx, qid, rs)
+		if err != nil {
+			failed++
+			j.logger.Error("保存相关推荐失败", elog.FieldErr(err), elog.Int64("qid", qid))
+		}
+	}
+	if failed > 0 {
+		// 保留上一轮的结果，下一轮再清理
+		return fmt.Errorf("%d 道题目的相关推荐保存失败", failed)
+	}
+	// 这一轮没有算出结果的，例如已经下架了的题目，清理掉上一轮的结果
+	return j.repo.DeleteBefore(ctx, start)
+}
+
+func (j *RelatedJob) load(ctx context.Context) (domain.Corpus, error) {
+	var (
+		res domain.Corpus
+		err error
+	)
+	res.Questions, err = j.loadQuestions(ctx)
+	if err != nil {
+		return domain.Corpus{}, fmt.Errorf("加载题目失败: %w", err)
+	}
+	res.Cases, err = j.loadCases(ctx)
+	if err != nil {
+		return domain.Corpus{}, fmt.Errorf("加载案例失败: %w", err)
+	}
+	res.Skills, err = j.loadSkills(ctx)
+	if err != nil {
+		return domain.Corpus{}, fmt.Errorf("加载技能失败: %w", err)
+	}
+	res.Sets, err = j.loadSets(ctx)
+	if err != nil {
+		return domain.Corpus{}, fmt.Errorf("加载题集失败: %w", err)
+	}
+	return res, nil
+}
+
+func (j *RelatedJob) loadQuestions(ctx context.Context) ([]domain.Item, error) {
+	var res []domain.Item
+	for offset := 0; ; offset += j.batchSize {
+		qs, _, err := j.queSvc.PubList(ctx, offset, j.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, slice.Map(qs, func(idx int, src baguwen.Question) domain.Item {
+			return domain.Item{Biz: domain.BizQuestion, BizId: src.Id, Title: src.Title, Labels: src.Labels}
+		})...)
+		if len(qs) < j.batchSize {
+			return res, nil
+		}
+	}
+}
+
+func (j *RelatedJob) loadCases(ctx context.Context) ([]domain.Item, error) {
+	var res []domain.Item
+	for offset := 0; ; offset += j.batchSize {
+		cs, _, err := j.caseSvc.PubList(ctx, offset, j.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, slice.Map(cs, func(idx int, src cases.Case) domain.Item {
+			return domain.Item{Biz: domain.BizCase, BizId: src.Id, Title: src.Title, Labels: src.Labels}
+		})...)
+		if len(cs) < j.batchSize {
+			return res, nil
+		}
+	}
+}
+
+// loadSkills 技能不区分级别，同一个技能下面的题目和案例都算作相关
+func (j *RelatedJob) loadSkills(ctx context.Context) ([]domain.Group, error) {
+	var res []domain.Group
+	for offset := 0; ; offset += j.batchSize {
+		skills, _, err := j.skillSvc.List(ctx, offset, j.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		// 级别 id 到技能在 res 里面的下标
+		levels := make(map[int64]int, len(skills)*3)
+		for _, sk := range skills {
+			idx := len(res)
+			res = append(res, domain.Group{})
+			for _, l := range []skill.SkillLevel{sk.Basic, sk.Intermediate, sk.Advanced} {
+				if l.Id > 0 {
+					levels[l.Id] = idx
+				}
+			}
+		}
+		if len(levels) > 0 {
+			lids := make([]int64, 0, len(levels))
+			for lid := range levels {
+				lids = append(lids, lid)
+			}
+			refs, err := j.skillSvc.RefsByLevelIDs(ctx, lids)
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range refs {
+				idx, ok := levels[ref.Id]
+				if !ok {
+					continue
+				}
+				res[idx].Questions = append(res[idx].Questions, ref.Questions...)
+				res[idx].Cases = append(res[idx].Cases, ref.Cases...)
+			}
+		}
+		if len(skills) < j.batchSize {
+			return res, nil
+		}
+	}
+}
+
+func (j *RelatedJob) loadSets(ctx context.Context) ([]domain.Group, error) {
+	var res []domain.Group
+	for offset := 0; ; offset += j.batchSize {
+		sets, _, err := j.qsSvc.PubList(ctx, offset, j.batchSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, set := range sets {
+			detail, err := j.qsSvc.PubDetail(ctx, set.Id)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, domain.Group{
+				Questions: slice.Map(detail.Questions, func(idx int, src baguwen.Question) int64 {
+					return src.Id
+				}),
+			})
+		}
+		if len(sets) < j.batchSize {
+			return res, nil
+		}
+	}
+}
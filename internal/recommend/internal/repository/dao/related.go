@@ -0,0 +1,78 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+)
+
+type RelatedDAO interface {
+	// Replace 覆盖某道题之前计算的结果
+	Replace(ctx context.Context, qid int64, items []RelatedItem) error
+	FindByQid(ctx context.Context, qid int64) ([]RelatedItem, error)
+	// DeleteBefore 删除 ctime 早于 ctime 的结果，也就是这一轮没有重新计算的题目
+	DeleteBefore(ctx context.Context, ctime int64) error
+	// DeleteByBiz 内容下架或者删除之后，把它从所有题目的结果里面去掉
+	DeleteByBiz(ctx context.Context, biz string, bizId int64) error
+}
+
+type RelatedGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewRelatedGORMDAO(db *egorm.Component) RelatedDAO {
+	return &RelatedGORMDAO{db: db}
+}
+
+func (dao *RelatedGORMDAO) Replace(ctx context.Context, qid int64, items []RelatedItem) error {
+	return dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("qid = ?", qid).Delete(&RelatedItem{}).Error
+		if err != nil || len(items) == 0 {
+			return err
+		}
+		return tx.Create(&items).Error
+	})
+}
+
+func (dao *RelatedGORMDAO) FindByQid(ctx context.Context, qid int64) ([]RelatedItem, error) {
+	var res []RelatedItem
+	err := dao.db.WithContext(ctx).Where("qid = ?", qid).
+		Order("`rank` ASC").Find(&res).Error
+	return res, err
+}
+
+func (dao *RelatedGORMDAO) DeleteBefore(ctx context.Context, ctime int64) error {
+	return dao.db.WithContext(ctx).Where("ctime < ?", ctime).Delete(&RelatedItem{}).Error
+}
+
+func (dao *RelatedGORMDAO) DeleteByBiz(ctx context.Context, biz string, bizId int64) error {
+	return dao.db.WithContext(ctx).Where("biz = ? AND biz_id = ?", biz, bizId).
+		Delete(&RelatedItem{}).Error
+}
+
+// RelatedItem 和题目相关的内容，标题冗余存储，下一轮计算的时候更新
+type RelatedItem struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Qid   int64  `gorm:"index:qid_rank"`
+	Rank  int    `gorm:"index:qid_rank"`
+	Biz   string `gorm:"type:varchar(64);index:biz_biz_id"`
+	BizId int64  `gorm:"index:biz_biz_id"`
+	Title string `gorm:"type:varchar(512)"`
+	Score int
+	Ctime int64 `gorm:"index"`
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/recommend/internal/domain"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository/dao"
+)
+
+type RelatedRepository interface {
+	// Save rs 已经按照相关程度排好序了
+	Save(ctx context.Context, qid int64, rs []domain.Related) error
+	FindByQid(ctx context.Context, qid int64) ([]domain.Related, error)
+	DeleteBefore(ctx context.Context, t time.Time) error
+	DeleteByBiz(ctx context.Context, biz string, bizId int64) error
+}
+
+type relatedRepository struct {
+	dao dao.RelatedDAO
+}
+
+func NewRelatedRepository(d dao.RelatedDAO) RelatedRepository {
+	return &relatedRepository{dao: d}
+}
+
+func (repo *relatedRepository) Save(ctx context.Context, qid int64, rs []domain.Related) error {
+	now := time.Now().UnixMilli()
+	return repo.dao.Replace(ctx, qid, slice.Map(rs, func(idx int, src domain.Related) dao.RelatedItem {
+		return dao.RelatedItem{
+			Qid:   qid,
+			Rank:  idx,
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Title: src.Title,
+			Score: src.Score,
+			Ctime: now,
+		}
+	}))
+}
+
+func (repo *relatedRepository) FindByQid(ctx context.Context, qid int64) ([]domain.Related, error) {
+	items, err := repo.dao.FindByQid(ctx, qid)
+	return slice.Map(items, func(idx int, src dao.RelatedItem) domain.Related {
+		return domain.Related{
+			Qid:   src.Qid,
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Title: src.Title,
+			Score: src.Score,
+			Ctime: time.UnixMilli(src.Ctime),
+		}
+	}), err
+}
+
+func (repo *relatedRepository) DeleteBefore(ctx context.Context, t time.Time) error {
+	return repo.dao.DeleteBefore(ctx, t.UnixMilli())
+}
+
+func (repo *relatedRepository) DeleteByBiz(ctx context.Context, biz string, bizId int64) error {
+	return repo.dao.DeleteByBiz(ctx, biz, bizId)
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/ecodeclub/ekit/slice"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend/internal/domain"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository"
+)
+
+// Service 实现了 baguwen.RelatedService 和 cases.RelatedService，题目详情里面的相关推荐来自这里
+type Service interface {
+	Related(ctx context.Context, qid int64) ([]baguwen.Related, error)
+	RemoveQuestion(ctx context.Context, qid int64) error
+	RemoveCase(ctx context.Context, cid int64) error
+}
+
+type service struct {
+	repo repository.RelatedRepository
+}
+
+func NewService(repo repository.RelatedRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Related(ctx context.Context, qid int64) ([]baguwen.Related, error) {
+	rs, err := s.repo.FindByQid(ctx, qid)
+	return slice.Map(rs, func(idx int, src domain.Related) baguwen.Related {
+		return baguwen.Related{
+			Biz:   src.Biz,
+			BizId: src.BizId,
+			Title: src.Title,
+		}
+	}), err
+}
+
+func (s *service) RemoveQuestion(ctx context.Context, qid int64) error {
+	return s.repo.DeleteByBiz(ctx, domain.BizQuestion, qid)
+}
+
+func (s *service) RemoveCase(ctx context.Context, cid int64) error {
+	return s.repo.DeleteByBiz(ctx, domain.BizCase, cid)
+}
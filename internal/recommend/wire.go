@@ -0,0 +1,95 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package recommend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/cases"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend/internal/job"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/recommend/internal/service"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+)
+
+func InitModule(db *egorm.Component,
+	queModule *baguwen.Module,
+	caseModule *cases.Module,
+	skillModule *skill.Module) *Module {
+	wire.Build(
+		initDAO,
+		repository.NewRelatedRepository,
+		service.NewService,
+		wire.FieldsOf(new(*baguwen.Module), "Svc", "QsSvc"),
+		wire.FieldsOf(new(*cases.Module), "Svc"),
+		wire.FieldsOf(new(*skill.Module), "Svc"),
+		initRelatedJob,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module)
+}
+
+// InitRelatedService 题目模块构造的时候需要，这个时候还没有题目模块，所以不能用 InitModule
+func InitRelatedService(db *egorm.Component) baguwen.RelatedService {
+	wire.Build(
+		initDAO,
+		repository.NewRelatedRepository,
+		service.NewService,
+		wire.Bind(new(baguwen.RelatedService), new(service.Service)),
+	)
+	return nil
+}
+
+// InitCaseRelatedService 和 InitRelatedService 一样，案例模块构造的时候还没有案例模块
+func InitCaseRelatedService(db *egorm.Component) cases.RelatedService {
+	wire.Build(
+		initDAO,
+		repository.NewRelatedRepository,
+		service.NewService,
+		wire.Bind(new(cases.RelatedService), new(service.Service)),
+	)
+	return nil
+}
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.RelatedDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewRelatedGORMDAO(db)
+}
+
+// initRelatedJob 每道题保留 10 个相关内容
+func initRelatedJob(queSvc baguwen.Service,
+	qsSvc baguwen.QuestionSetService,
+	caseSvc cases.Service,
+	skillSvc skill.Service,
+	repo repository.RelatedRepository) *RelatedJob {
+	return job.NewRelatedJob(queSvc, qsSvc, caseSvc, skillSvc, repo, 10, 100, time.Hour)
+}
+
+type Service = service.Service
+type RelatedJob = job.RelatedJob
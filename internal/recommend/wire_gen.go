@@ -0,0 +1,83 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package recommend
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/cases"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend/internal/job"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository"
+	"github.com/ecodeclub/webook/internal/recommend/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/recommend/internal/service"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm"
+)
+
+// Injectors from wire.go:
+
+func InitModule(db *gorm.DB, queModule *baguwen.Module, caseModule *cases.Module, skillModule *skill.Module) *Module {
+	relatedDAO := initDAO(db)
+	relatedRepository := repository.NewRelatedRepository(relatedDAO)
+	serviceService := service.NewService(relatedRepository)
+	service2 := queModule.Svc
+	questionSetService := queModule.QsSvc
+	service3 := caseModule.Svc
+	skillService := skillModule.Svc
+	relatedJob := initRelatedJob(service2, questionSetService, service3, skillService, relatedRepository)
+	module := &Module{
+		Svc:        serviceService,
+		RelatedJob: relatedJob,
+	}
+	return module
+}
+
+// InitRelatedService 题目模块构造的时候需要，这个时候还没有题目模块，所以不能用 InitModule
+func InitRelatedService(db *gorm.DB) baguwen.RelatedService {
+	relatedDAO := initDAO(db)
+	relatedRepository := repository.NewRelatedRepository(relatedDAO)
+	serviceService := service.NewService(relatedRepository)
+	return serviceService
+}
+
+// InitCaseRelatedService 和 InitRelatedService 一样，案例模块构造的时候还没有案例模块
+func InitCaseRelatedService(db *gorm.DB) cases.RelatedService {
+	relatedDAO := initDAO(db)
+	relatedRepository := repository.NewRelatedRepository(relatedDAO)
+	serviceService := service.NewService(relatedRepository)
+	return serviceService
+}
+
+// wire.go:
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.RelatedDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewRelatedGORMDAO(db)
+}
+
+// initRelatedJob 每道题保留 10 个相关内容
+func initRelatedJob(queSvc baguwen.Service,
+	qsSvc baguwen.QuestionSetService,
+	caseSvc cases.Service,
+	skillSvc skill.Service,
+	repo repository.RelatedRepository) *RelatedJob {
+	return job.NewRelatedJob(queSvc, qsSvc, caseSvc, skillSvc, repo, 10, 100, time.Hour)
+}
+
+type Service = service.Service
+
+type RelatedJob = job.RelatedJob
@@ -0,0 +1,95 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// MinGrade 完全想不起来
+	MinGrade = 0
+	// MaxGrade 毫不费力地答出来
+	MaxGrade = 5
+	// PassGrade 低于这个分数认为没有记住，需要从头开始复习
+	PassGrade = 3
+
+	DefaultEF = 2.5
+	MinEF     = 1.3
+)
+
+// Card 用户对某道题的复习进度，按照 SM-2 算法计算下一次复习的日期
+type Card struct {
+	Uid int64
+	Qid int64
+	// EF 难度系数，越小说明越难，复习间隔增长得越慢
+	EF float64
+	// Interval 距离下一次复习的天数
+	Interval int
+	// Repetitions 连续答对的次数
+	Repetitions int
+	// Due 下一次复习的日期，精确到天，也就是当天的零点
+	Due time.Time
+	// PrevEF、PrevInterval 和 PrevRepetitions 是上一次自评之前的复习进度
+	// 还没有到期就再次自评的时候，从这里重新计算，覆盖上一次的自评结果
+	PrevEF          float64
+	PrevInterval    int
+	PrevRepetitions int
+	Utime           time.Time
+}
+
+func NewCard(uid, qid int64) Card {
+	return Card{Uid: uid, Qid: qid, EF: DefaultEF}
+}
+
+func ValidGrade(grade int) bool {
+	return grade >= MinGrade && grade <= MaxGrade
+}
+
+// Review 根据本次自评的分数计算新的复习进度，now 是自评的时间
+// 只有到期的卡片才会推进复习进度，还没有到期的时候（比如同一天重复自评）只是替换上一次的分数
+func (c Card) Review(grade int, now time.Time) Card {
+	today := StartOfDay(now)
+	if c.Due.After(today) {
+		c.EF, c.Interval, c.Repetitions = c.PrevEF, c.PrevInterval, c.PrevRepetitions
+	} else {
+		c.PrevEF, c.PrevInterval, c.PrevRepetitions = c.EF, c.Interval, c.Repetitions
+	}
+	if grade < PassGrade {
+		c.Repetitions = 0
+		c.Interval = 1
+	} else {
+		switch c.Repetitions {
+		case 0:
+			c.Interval = 1
+		case 1:
+			c.Interval = 6
+		default:
+			c.Interval = int(math.Round(float64(c.Interval) * c.EF))
+		}
+		c.Repetitions++
+	}
+	// 不管是否答对，难度系数都要根据分数调整
+	q := float64(MaxGrade - grade)
+	c.EF = math.Max(MinEF, c.EF+0.1-q*(0.08+q*0.02))
+	c.Due = today.AddDate(0, 0, c.Interval)
+	c.Utime = now
+	return c
+}
+
+func StartOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCard_Review(t *testing.T) {
+	now := time.Date(2024, 5, 1, 20, 30, 0, 0, time.Local)
+	testCases := []struct {
+		name   string
+		card   Card
+		grades []int
+		want   Card
+	}{
+		{
+			name:   "连续答对",
+			card:   NewCard(1, 2),
+			grades: []int{4, 4, 4},
+			want:   Card{Uid: 1, Qid: 2, EF: 2.5, Interval: 15, Repetitions: 3},
+		},
+		{
+			name:   "答得很轻松",
+			card:   NewCard(1, 2),
+			grades: []int{5, 5},
+			want:   Card{Uid: 1, Qid: 2, EF: 2.7, Interval: 6, Repetitions: 2},
+		},
+		{
+			name:   "答错之后从头开始",
+			card:   NewCard(1, 2),
+			grades: []int{4, 4, 2},
+			want:   Card{Uid: 1, Qid: 2, EF: 2.18, Interval: 1, Repetitions: 0},
+		},
+		{
+			name:   "难度系数有下限",
+			card:   NewCard(1, 2),
+			grades: []int{0, 0, 0, 0},
+			want:   Card{Uid: 1, Qid: 2, EF: MinEF, Interval: 1, Repetitions: 0},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := tc.card
+			// 每一次都在到期的那一天复习
+			at := now
+			for _, g := range tc.grades {
+				if !c.Due.IsZero() {
+					at = c.Due.Add(now.Sub(StartOfDay(now)))
+				}
+				c = c.Review(g, at)
+			}
+			assert.InDelta(t, tc.want.EF, c.EF, 1e-9)
+			assert.Equal(t, tc.want.Interval, c.Interval)
+			assert.Equal(t, tc.want.Repetitions, c.Repetitions)
+			assert.Equal(t, StartOfDay(at).AddDate(0, 0, tc.want.Interval), c.Due)
+			assert.Equal(t, at, c.Utime)
+		})
+	}
+}
+
+func TestCard_Review_SameDay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 20, 30, 0, 0, time.Local)
+	c := NewCard(1, 2).Review(4, now).Review(4, now.AddDate(0, 0, 1))
+	assert.Equal(t, 6, c.Interval)
+	assert.Equal(t, 2, c.Repetitions)
+	due := c.Due
+
+	// 同一天再次自评，复习间隔不会继续增长
+	again := c.Review(5, now.AddDate(0, 0, 1).Add(time.Hour))
+	assert.Equal(t, 6, again.Interval)
+	assert.Equal(t, 2, again.Repetitions)
+	assert.Equal(t, due, again.Due)
+	// 分数以最后一次为准
+	assert.InDelta(t, 2.6, again.EF, 1e-9)
+
+	// 同一天改成答错，按照答错重新计算
+	wrong := again.Review(1, now.AddDate(0, 0, 1).Add(2*time.Hour))
+	assert.Equal(t, 1, wrong.Interval)
+	assert.Equal(t, 0, wrong.Repetitions)
+	assert.Equal(t, StartOfDay(now).AddDate(0, 0, 2), wrong.Due)
+
+	// 到期之后才会继续推进
+	next := again.Review(4, due)
+	assert.Equal(t, 16, next.Interval)
+	assert.Equal(t, 3, next.Repetitions)
+}
@@ -0,0 +1,12 @@
+package errs
+
+var (
+	SystemError      = ErrorCode{Code: 514001, Msg: "系统错误"}
+	InvalidGrade     = ErrorCode{Code: 514002, Msg: "自评分数非法"}
+	QuestionNotFound = ErrorCode{Code: 514003, Msg: "题目不存在"}
+)
+
+type ErrorCode struct {
+	Code int
+	Msg  string
+}
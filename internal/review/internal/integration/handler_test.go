@@ -0,0 +1,272 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ginx/session"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	quemocks "github.com/ecodeclub/webook/internal/question/mocks"
+	"github.com/ecodeclub/webook/internal/review"
+	"github.com/ecodeclub/webook/internal/review/internal/domain"
+	"github.com/ecodeclub/webook/internal/review/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/review/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/review/internal/web"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ego-component/egorm"
+	"github.com/gin-gonic/gin"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
+)
+
+const uid = 4001
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server *egin.Component
+	db     *egorm.Component
+	rdb    redis.Cmdable
+	job    *review.DueQueueJob
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	ctrl := gomock.NewController(s.T())
+	queSvc := quemocks.NewMockService(ctrl)
+	// 只有 id 小于 100 的题目是已经发布了的
+	queSvc.EXPECT().GetPubByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, ids []int64) ([]baguwen.Question, error) {
+			res := make([]baguwen.Question, 0, len(ids))
+			for _, id := range ids {
+				if id < 100 {
+					res = append(res, baguwen.Question{Id: id, Title: fmt.Sprintf("题目%d", id)})
+				}
+			}
+			return res, nil
+		}).AnyTimes()
+	module := startup.InitModule(&baguwen.Module{Svc: queSvc})
+	s.job = module.DueQueueJob
+
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	server.Use(func(ctx *gin.Context) {
+		ctx.Set("_session", session.NewMemorySession(session.Claims{
+			Uid: uid,
+		}))
+	})
+	module.Hdl.MemberRoutes(server.Engine)
+	s.server = server
+	s.db = testioc.InitDB()
+	err := dao.InitTables(s.db)
+	require.NoError(s.T(), err)
+	s.rdb = testioc.InitRedis()
+}
+
+func (s *HandlerTestSuite) TearDownTest() {
+	err := s.db.Exec("TRUNCATE TABLE `review_cards`").Error
+	require.NoError(s.T(), err)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	keys, err := s.rdb.Keys(ctx, "webook:review:due:*").Result()
+	require.NoError(s.T(), err)
+	if len(keys) > 0 {
+		require.NoError(s.T(), s.rdb.Del(ctx, keys...).Err())
+	}
+}
+
+func (s *HandlerTestSuite) TestGrade() {
+	tomorrow := domain.StartOfDay(time.Now()).AddDate(0, 0, 1)
+	testCases := []struct {
+		name     string
+		before   func(t *testing.T)
+		req      web.GradeReq
+		wantCode int
+		wantResp test.Result[web.Card]
+	}{
+		{
+			name:     "第一次复习",
+			before:   func(t *testing.T) {},
+			req:      web.GradeReq{Qid: 1, Grade: 4},
+			wantCode: 200,
+			wantResp: test.Result[web.Card]{
+				Data: web.Card{Qid: 1, EF: 2.5, Interval: 1, Repetitions: 1, Due: tomorrow.UnixMilli()},
+			},
+		},
+		{
+			name: "第三次复习",
+			before: func(t *testing.T) {
+				err := s.db.Create(&dao.ReviewCard{
+					Uid: uid, Qid: 2, EF: 2.5, Interval: 6, Repetitions: 2,
+				}).Error
+				require.NoError(t, err)
+			},
+			req:      web.GradeReq{Qid: 2, Grade: 5},
+			wantCode: 200,
+			wantResp: test.Result[web.Card]{
+				Data: web.Card{Qid: 2, EF: 2.6, Interval: 15, Repetitions: 3,
+					Due: tomorrow.AddDate(0, 0, 14).UnixMilli()},
+			},
+		},
+		{
+			name: "忘记了从头开始",
+			before: func(t *testing.T) {
+				err := s.db.Create(&dao.ReviewCard{
+					Uid: uid, Qid: 3, EF: 1.4, Interval: 20, Repetitions: 4,
+				}).Error
+				require.NoError(t, err)
+			},
+			req:      web.GradeReq{Qid: 3, Grade: 1},
+			wantCode: 200,
+			wantResp: test.Result[web.Card]{
+				Data: web.Card{Qid: 3, EF: 1.3, Interval: 1, Repetitions: 0, Due: tomorrow.UnixMilli()},
+			},
+		},
+		{
+			name: "同一天重复自评",
+			before: func(t *testing.T) {
+				// 今天已经按照 4 分从第二次复习推进到了第三次
+				err := s.db.Create(&dao.ReviewCard{
+					Uid: uid, Qid: 4, EF: 2.5, Interval: 15, Repetitions: 3,
+					Due:    tomorrow.AddDate(0, 0, 14).UnixMilli(),
+					PrevEF: 2.5, PrevInterval: 6, PrevRepetitions: 2,
+				}).Error
+				require.NoError(t, err)
+			},
+			req:      web.GradeReq{Qid: 4, Grade: 5},
+			wantCode: 200,
+			wantResp: test.Result[web.Card]{
+				Data: web.Card{Qid: 4, EF: 2.6, Interval: 15, Repetitions: 3,
+					Due: tomorrow.AddDate(0, 0, 14).UnixMilli()},
+			},
+		},
+		{
+			name:     "分数非法",
+			before:   func(t *testing.T) {},
+			req:      web.GradeReq{Qid: 1, Grade: 6},
+			wantCode: 500,
+			wantResp: test.Result[web.Card]{Code: 514002, Msg: "自评分数非法"},
+		},
+		{
+			name:     "题目不存在",
+			before:   func(t *testing.T) {},
+			req:      web.GradeReq{Qid: 100, Grade: 3},
+			wantCode: 500,
+			wantResp: test.Result[web.Card]{Code: 514003, Msg: "题目不存在"},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			tc.before(t)
+			req, err := http.NewRequest(http.MethodPost,
+				"/review/grade", iox.NewJSONReader(tc.req))
+			require.NoError(t, err)
+			req.Header.Set("content-type", "application/json")
+			recorder := test.NewJSONResponseRecorder[web.Card]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			resp := recorder.MustScan()
+			resp.Data.EF = float64(int(resp.Data.EF*100+0.5)) / 100
+			assert.Equal(t, tc.wantResp, resp)
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestToday() {
+	today := domain.StartOfDay(time.Now())
+	cards := []dao.ReviewCard{
+		{Uid: uid, Qid: 1, EF: 2.5, Interval: 1, Repetitions: 1, Due: today.UnixMilli()},
+		// 之前过期没有复习的排在前面
+		{Uid: uid, Qid: 2, EF: 2.5, Interval: 6, Repetitions: 2, Due: today.AddDate(0, 0, -3).UnixMilli()},
+		// 已经下架的题目
+		{Uid: uid, Qid: 100, EF: 2.5, Interval: 1, Repetitions: 1, Due: today.AddDate(0, 0, -1).UnixMilli()},
+		// 还没到期
+		{Uid: uid, Qid: 3, EF: 2.5, Interval: 1, Repetitions: 1, Due: today.AddDate(0, 0, 1).UnixMilli()},
+		// 别人的
+		{Uid: uid + 1, Qid: 4, EF: 2.5, Interval: 1, Repetitions: 1, Due: today.UnixMilli()},
+	}
+	err := s.db.Create(&cards).Error
+	require.NoError(s.T(), err)
+
+	// 第一次从数据库重建复习队列
+	resp := s.today(web.Page{Limit: 10})
+	assert.Equal(s.T(), int64(3), resp.Total)
+	assert.Equal(s.T(), []int64{2, 1}, s.qids(resp))
+	assert.Equal(s.T(), "题目2", resp.Questions[0].Title)
+
+	// 分页读取缓存
+	resp = s.today(web.Page{Offset: 1, Limit: 1})
+	assert.Equal(s.T(), int64(3), resp.Total)
+	assert.Equal(s.T(), []int64{}, s.qids(resp))
+	// 负数的 offset 按照 0 处理，不会把占位成员当成题目返回
+	resp = s.today(web.Page{Offset: -1, Limit: 10})
+	assert.Equal(s.T(), int64(3), resp.Total)
+	assert.Equal(s.T(), []int64{2, 1}, s.qids(resp))
+
+	// 复习过之后从今天的队列里面移除
+	req, err := http.NewRequest(http.MethodPost,
+		"/review/grade", iox.NewJSONReader(web.GradeReq{Qid: 2, Grade: 5}))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.Card]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	resp = s.today(web.Page{Limit: 10})
+	assert.Equal(s.T(), int64(2), resp.Total)
+	assert.Equal(s.T(), []int64{1}, s.qids(resp))
+
+	// 定时任务会预先计算所有用户的队列
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+	require.NoError(s.T(), s.job.Run())
+	key := fmt.Sprintf("webook:review:due:%d:%s", uid+1, today.Format("20060102"))
+	members, err := s.rdb.ZRange(ctx, key, 0, -1).Result()
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), []string{"0", "4"}, members)
+}
+
+func (s *HandlerTestSuite) today(page web.Page) web.TodayResp {
+	req, err := http.NewRequest(http.MethodPost,
+		"/review/today", iox.NewJSONReader(page))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.TodayResp]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func (s *HandlerTestSuite) qids(resp web.TodayResp) []int64 {
+	return slice.Map(resp.Questions, func(idx int, src web.Question) int64 {
+		return src.Id
+	})
+}
+
+func TestReviewHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
@@ -0,0 +1,22 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package startup
+
+import (
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/review"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+)
+
+// Injectors from wire.go:
+
+func InitModule(queModule *baguwen.Module) *review.Module {
+	db := testioc.InitDB()
+	cmdable := testioc.InitRedis()
+	module := review.InitModule(db, cmdable, queModule)
+	return module
+}
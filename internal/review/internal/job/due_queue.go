@@ -0,0 +1,69 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/review/internal/service"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// DueQueueJob 每天凌晨预先计算用户当天的复习队列，
+// 避免复习题目很多的用户第一次打开今日复习的时候才去查数据库
+type DueQueueJob struct {
+	svc     service.Service
+	limit   int
+	timeout time.Duration
+	logger  *elog.Component
+}
+
+func NewDueQueueJob(svc service.Service, limit int, timeout time.Duration) *DueQueueJob {
+	return &DueQueueJob{
+		svc:     svc,
+		limit:   limit,
+		timeout: timeout,
+		logger:  elog.DefaultLogger,
+	}
+}
+
+func (j *DueQueueJob) Name() string {
+	return "ReviewDueQueueJob"
+}
+
+func (j *DueQueueJob) Run() error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), j.timeout)
+	defer cancelFunc()
+	var afterUid int64
+	for {
+		uids, err := j.svc.DueUids(ctx, afterUid, j.limit)
+		if err != nil {
+			return fmt.Errorf("获取需要复习的用户失败: %w", err)
+		}
+		for _, uid := range uids {
+			err = j.svc.RefreshToday(ctx, uid)
+			if err != nil {
+				// 没算出来也没关系，用户打开今日复习的时候还会再算一次
+				j.logger.Error("计算复习队列失败", elog.FieldErr(err), elog.Int64("uid", uid))
+			}
+		}
+		if len(uids) < j.limit {
+			return nil
+		}
+		afterUid = uids[len(uids)-1]
+	}
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/review/internal/domain"
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrKeyNotExist = errors.New("复习队列不存在")
+
+// 队列里面的占位成员，分数为负无穷，一定排在最前面，用于区分"队列不存在"和"今天没有要复习的题目"
+const placeholder = "0"
+
+// ReviewCache 使用有序集合保存用户某一天要复习的题目，分数是卡片到期的时间
+type ReviewCache interface {
+	// DueQids 获取某一天的复习队列，队列不存在时返回 ErrKeyNotExist
+	DueQids(ctx context.Context, uid int64, day time.Time, offset, limit int) ([]int64, int64, error)
+	// SetDueCards 重建某一天的复习队列
+	SetDueCards(ctx context.Context, uid int64, day time.Time, cards []domain.Card) error
+	// Remove 从某一天的复习队列中移除已经复习过的题目
+	Remove(ctx context.Context, uid int64, day time.Time, qid int64) error
+}
+
+type reviewRedisCache struct {
+	cmd        redis.Cmdable
+	expiration time.Duration
+}
+
+func NewReviewRedisCache(cmd redis.Cmdable) ReviewCache {
+	return &reviewRedisCache{
+		cmd: cmd,
+		// 只有当天的队列有用，多留一点时间避免跨天的时候刚好过期
+		expiration: time.Hour * 36,
+	}
+}
+
+func (c *reviewRedisCache) DueQids(ctx context.Context, uid int64, day time.Time, offset, limit int) ([]int64, int64, error) {
+	key := c.key(uid, day)
+	pipe := c.cmd.Pipeline()
+	cnt := pipe.ZCard(ctx, key)
+	// 跳过占位成员
+	members := pipe.ZRange(ctx, key, int64(offset+1), int64(offset+limit))
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	total := cnt.Val()
+	if total == 0 {
+		return nil, 0, ErrKeyNotExist
+	}
+	vals := members.Val()
+	qids := make([]int64, 0, len(vals))
+	for _, v := range vals {
+		if v == placeholder {
+			continue
+		}
+		qid, er := strconv.ParseInt(v, 10, 64)
+		if er != nil {
+			return nil, 0, er
+		}
+		qids = append(qids, qid)
+	}
+	return qids, total - 1, nil
+}
+
+func (c *reviewRedisCache) SetDueCards(ctx context.Context, uid int64, day time.Time, cards []domain.Card) error {
+	key := c.key(uid, day)
+	members := make([]redis.Z, 0, len(cards)+1)
+	members = append(members, redis.Z{Score: math.Inf(-1), Member: placeholder})
+	for _, card := range cards {
+		members = append(members, redis.Z{
+			Score:  float64(card.Due.UnixMilli()),
+			Member: card.Qid,
+		})
+	}
+	pipe := c.cmd.TxPipeline()
+	pipe.Del(ctx, key)
+	pipe.ZAdd(ctx, key, members...)
+	pipe.Expire(ctx, key, c.expiration)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *reviewRedisCache) Remove(ctx context.Context, uid int64, day time.Time, qid int64) error {
+	// 队列不存在的时候 ZREM 什么也不会做，不会误建一个只有部分题目的队列
+	return c.cmd.ZRem(ctx, c.key(uid, day), qid).Err()
+}
+
+func (c *reviewRedisCache) key(uid int64, day time.Time) string {
+	return fmt.Sprintf("webook:review:due:%d:%s", uid, day.Format("20060102"))
+}
@@ -0,0 +1,97 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"time"
+
+	"github.com/ego-component/egorm"
+	"gorm.io/gorm/clause"
+)
+
+type ReviewDAO interface {
+	// GetCard 没有复习过的时候返回 gorm.ErrRecordNotFound
+	GetCard(ctx context.Context, uid, qid int64) (ReviewCard, error)
+	// Upsert 覆盖之前的复习进度
+	Upsert(ctx context.Context, c ReviewCard) error
+	// DueCards 在 before 之前到期的卡片，最早到期的排在前面
+	DueCards(ctx context.Context, uid int64, before int64, limit int) ([]ReviewCard, error)
+	// DueUids 有卡片在 before 之前到期的用户，按照 uid 升序排列，从 afterUid 之后开始
+	DueUids(ctx context.Context, before int64, afterUid int64, limit int) ([]int64, error)
+}
+
+type ReviewGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewReviewGORMDAO(db *egorm.Component) ReviewDAO {
+	return &ReviewGORMDAO{db: db}
+}
+
+func (dao *ReviewGORMDAO) GetCard(ctx context.Context, uid, qid int64) (ReviewCard, error) {
+	var c ReviewCard
+	err := dao.db.WithContext(ctx).Where("uid = ? AND qid = ?", uid, qid).First(&c).Error
+	return c, err
+}
+
+func (dao *ReviewGORMDAO) Upsert(ctx context.Context, c ReviewCard) error {
+	now := time.Now().UnixMilli()
+	c.Ctime, c.Utime = now, now
+	return dao.db.WithContext(ctx).Clauses(clause.OnConflict{
+		DoUpdates: clause.AssignmentColumns([]string{"ef", "interval", "repetitions", "due",
+			"prev_ef", "prev_interval", "prev_repetitions", "utime"}),
+	}).Create(&c).Error
+}
+
+func (dao *ReviewGORMDAO) DueCards(ctx context.Context, uid int64, before int64, limit int) ([]ReviewCard, error) {
+	var res []ReviewCard
+	err := dao.db.WithContext(ctx).
+		Where("uid = ? AND due < ?", uid, before).
+		Order("due ASC, id ASC").
+		Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *ReviewGORMDAO) DueUids(ctx context.Context, before int64, afterUid int64, limit int) ([]int64, error) {
+	var res []int64
+	err := dao.db.WithContext(ctx).Model(&ReviewCard{}).
+		Distinct("uid").
+		Where("uid > ? AND due < ?", afterUid, before).
+		Order("uid ASC").
+		Limit(limit).
+		Pluck("uid", &res).Error
+	return res, err
+}
+
+// ReviewCard 用户对一道题的复习进度
+type ReviewCard struct {
+	Id  int64 `gorm:"primaryKey,autoIncrement"`
+	Uid int64 `gorm:"uniqueIndex:uid_qid;index:uid_due"`
+	Qid int64 `gorm:"uniqueIndex:uid_qid"`
+	EF  float64
+	// 单位是天
+	Interval    int
+	Repetitions int
+	// 下一次复习那一天零点的毫秒数
+	Due int64 `gorm:"index:uid_due"`
+	// 上一次自评之前的复习进度，同一天重复自评的时候用来覆盖上一次的结果
+	PrevEF          float64
+	PrevInterval    int
+	PrevRepetitions int
+	Ctime           int64
+	Utime           int64
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/review/internal/domain"
+	"github.com/ecodeclub/webook/internal/review/internal/repository/cache"
+	"github.com/ecodeclub/webook/internal/review/internal/repository/dao"
+	"github.com/gotomicro/ego/core/elog"
+	"gorm.io/gorm"
+)
+
+// 复习队列最多缓存这么多道题目，超出的部分等到下一次重建队列的时候再复习
+const maxQueueSize = 1000
+
+type ReviewRepository interface {
+	// GetCard 没有复习过的题目返回一张新卡片
+	GetCard(ctx context.Context, uid, qid int64) (domain.Card, error)
+	Save(ctx context.Context, c domain.Card) error
+	// DueQids 某一天要复习的题目，包括之前过期没有复习的
+	DueQids(ctx context.Context, uid int64, day time.Time, offset, limit int) ([]int64, int64, error)
+	// RefreshDueQueue 从数据库重建某一天的复习队列
+	RefreshDueQueue(ctx context.Context, uid int64, day time.Time) error
+	// DueUids 某一天有题目要复习的用户
+	DueUids(ctx context.Context, day time.Time, afterUid int64, limit int) ([]int64, error)
+}
+
+type cachedReviewRepository struct {
+	dao    dao.ReviewDAO
+	cache  cache.ReviewCache
+	logger *elog.Component
+}
+
+func NewCachedReviewRepository(d dao.ReviewDAO, c cache.ReviewCache) ReviewRepository {
+	return &cachedReviewRepository{
+		dao:    d,
+		cache:  c,
+		logger: elog.DefaultLogger,
+	}
+}
+
+func (repo *cachedReviewRepository) GetCard(ctx context.Context, uid, qid int64) (domain.Card, error) {
+	c, err := repo.dao.GetCard(ctx, uid, qid)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.NewCard(uid, qid), nil
+	}
+	if err != nil {
+		return domain.Card{}, err
+	}
+	return repo.toDomain(c), nil
+}
+
+func (repo *cachedReviewRepository) Save(ctx context.Context, c domain.Card) error {
+	err := repo.dao.Upsert(ctx, repo.toEntity(c))
+	if err != nil {
+		return err
+	}
+	// 复习过之后，最早也要到明天才会再次到期，所以直接从今天的队列里面移除
+	today := domain.StartOfDay(c.Utime)
+	if er := repo.cache.Remove(ctx, c.Uid, today, c.Qid); er != nil {
+		repo.logger.Error("从复习队列中移除题目失败",
+			elog.FieldErr(er),
+			elog.Int64("uid", c.Uid),
+			elog.Int64("qid", c.Qid))
+	}
+	return nil
+}
+
+func (repo *cachedReviewRepository) DueQids(ctx context.Context, uid int64, day time.Time, offset, limit int) ([]int64, int64, error) {
+	if offset < 0 || limit < 0 {
+		return nil, 0, fmt.Errorf("非法的分页参数 offset %d, limit %d", offset, limit)
+	}
+	qids, total, err := repo.cache.DueQids(ctx, uid, day, offset, limit)
+	if err == nil {
+		return qids, total, nil
+	}
+	cards, err := repo.refresh(ctx, uid, day)
+	if err != nil {
+		return nil, 0, err
+	}
+	total = int64(len(cards))
+	if offset >= len(cards) {
+		return []int64{}, total, nil
+	}
+	cards = cards[offset:min(offset+limit, len(cards))]
+	return slice.Map(cards, func(idx int, src domain.Card) int64 {
+		return src.Qid
+	}), total, nil
+}
+
+func (repo *cachedReviewRepository) RefreshDueQueue(ctx context.Context, uid int64, day time.Time) error {
+	_, err := repo.refresh(ctx, uid, day)
+	return err
+}
+
+func (repo *cachedReviewRepository) refresh(ctx context.Context, uid int64, day time.Time) ([]domain.Card, error) {
+	cs, err := repo.dao.DueCards(ctx, uid, day.AddDate(0, 0, 1).UnixMilli(), maxQueueSize)
+	if err != nil {
+		return nil, err
+	}
+	cards := slice.Map(cs, func(idx int, src dao.ReviewCard) domain.Card {
+		return repo.toDomain(src)
+	})
+	if er := repo.cache.SetDueCards(ctx, uid, day, cards); er != nil {
+		repo.logger.Error("重建复习队列失败",
+			elog.FieldErr(er),
+			elog.Int64("uid", uid),
+			elog.String("day", day.Format(time.DateOnly)))
+	}
+	return cards, nil
+}
+
+func (repo *cachedReviewRepository) DueUids(ctx context.Context, day time.Time, afterUid int64, limit int) ([]int64, error) {
+	return repo.dao.DueUids(ctx, day.AddDate(0, 0, 1).UnixMilli(), afterUid, limit)
+}
+
+func (repo *cachedReviewRepository) toDomain(c dao.ReviewCard) domain.Card {
+	return domain.Card{
+		Uid:             c.Uid,
+		Qid:             c.Qid,
+		EF:              c.EF,
+		Interval:        c.Interval,
+		Repetitions:     c.Repetitions,
+		Due:             time.UnixMilli(c.Due),
+		PrevEF:          c.PrevEF,
+		PrevInterval:    c.PrevInterval,
+		PrevRepetitions: c.PrevRepetitions,
+		Utime:           time.UnixMilli(c.Utime),
+	}
+}
+
+func (repo *cachedReviewRepository) toEntity(c domain.Card) dao.ReviewCard {
+	return dao.ReviewCard{
+		Uid:             c.Uid,
+		Qid:             c.Qid,
+		EF:              c.EF,
+		Interval:        c.Interval,
+		Repetitions:     c.Repetitions,
+		Due:             c.Due.UnixMilli(),
+		PrevEF:          c.PrevEF,
+		PrevInterval:    c.PrevInterval,
+		PrevRepetitions: c.PrevRepetitions,
+	}
+}
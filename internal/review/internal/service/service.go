@@ -0,0 +1,70 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/review/internal/domain"
+	"github.com/ecodeclub/webook/internal/review/internal/repository"
+)
+
+var ErrInvalidGrade = errors.New("自评分数非法")
+
+type Service interface {
+	// Grade 用户对题目自评，返回更新之后的复习进度
+	// 题目还没有到期的时候，本次自评覆盖上一次的自评，不会再推进复习进度
+	Grade(ctx context.Context, uid, qid int64, grade int) (domain.Card, error)
+	// Today 今天要复习的题目，最早到期的排在前面
+	Today(ctx context.Context, uid int64, offset, limit int) ([]int64, int64, error)
+	// DueUids 今天有题目要复习的用户，用于预先计算复习队列
+	DueUids(ctx context.Context, afterUid int64, limit int) ([]int64, error)
+	// RefreshToday 重新计算用户今天的复习队列
+	RefreshToday(ctx context.Context, uid int64) error
+}
+
+type service struct {
+	repo repository.ReviewRepository
+}
+
+func NewService(repo repository.ReviewRepository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) Grade(ctx context.Context, uid, qid int64, grade int) (domain.Card, error) {
+	if !domain.ValidGrade(grade) {
+		return domain.Card{}, ErrInvalidGrade
+	}
+	c, err := s.repo.GetCard(ctx, uid, qid)
+	if err != nil {
+		return domain.Card{}, err
+	}
+	c = c.Review(grade, time.Now())
+	return c, s.repo.Save(ctx, c)
+}
+
+func (s *service) Today(ctx context.Context, uid int64, offset, limit int) ([]int64, int64, error) {
+	return s.repo.DueQids(ctx, uid, domain.StartOfDay(time.Now()), offset, limit)
+}
+
+func (s *service) DueUids(ctx context.Context, afterUid int64, limit int) ([]int64, error) {
+	return s.repo.DueUids(ctx, domain.StartOfDay(time.Now()), afterUid, limit)
+}
+
+func (s *service) RefreshToday(ctx context.Context, uid int64) error {
+	return s.repo.RefreshDueQueue(ctx, uid, domain.StartOfDay(time.Now()))
+}
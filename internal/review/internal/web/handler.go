@@ -0,0 +1,97 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"errors"
+
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/ginx/session"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/review/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const maxLimit = 100
+
+type Handler struct {
+	svc    service.Service
+	queSvc baguwen.Service
+}
+
+func NewHandler(svc service.Service, queSvc baguwen.Service) *Handler {
+	return &Handler{svc: svc, queSvc: queSvc}
+}
+
+func (h *Handler) MemberRoutes(server *gin.Engine) {
+	g := server.Group("/review")
+	g.POST("/grade", ginx.BS[GradeReq](h.Grade))
+	g.POST("/today", ginx.BS[Page](h.Today))
+}
+
+// Grade 看完题目之后自评，计算下一次复习的日期
+func (h *Handler) Grade(ctx *ginx.Context, req GradeReq, sess session.Session) (ginx.Result, error) {
+	ques, err := h.queSvc.GetPubByIDs(ctx, []int64{req.Qid})
+	if err != nil {
+		return systemErrorResult, err
+	}
+	if len(ques) == 0 {
+		return questionNotFoundResult, nil
+	}
+	c, err := h.svc.Grade(ctx, sess.Claims().Uid, req.Qid, req.Grade)
+	switch {
+	case errors.Is(err, service.ErrInvalidGrade):
+		return invalidGradeResult, nil
+	case err != nil:
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: newCard(c),
+	}, nil
+}
+
+// Today 今天要复习的题目
+func (h *Handler) Today(ctx *ginx.Context, req Page, sess session.Session) (ginx.Result, error) {
+	req.Offset = max(req.Offset, 0)
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	qids, total, err := h.svc.Today(ctx, sess.Claims().Uid, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	ques, err := h.queSvc.GetPubByIDs(ctx, qids)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	qm := make(map[int64]baguwen.Question, len(ques))
+	for _, q := range ques {
+		qm[q.Id] = q
+	}
+	// 按照到期的先后顺序返回，已经下架的题目直接跳过
+	res := make([]Question, 0, len(qids))
+	for _, qid := range qids {
+		q, ok := qm[qid]
+		if ok {
+			res = append(res, newQuestion(q))
+		}
+	}
+	return ginx.Result{
+		Data: TodayResp{
+			Total:     total,
+			Questions: res,
+		},
+	}, nil
+}
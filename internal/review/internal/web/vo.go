@@ -0,0 +1,71 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/review/internal/domain"
+)
+
+type GradeReq struct {
+	Qid int64 `json:"qid"`
+	// Grade 0 - 5，0 表示完全想不起来，5 表示毫不费力地答出来
+	Grade int `json:"grade"`
+}
+
+type Page struct {
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+}
+
+type Card struct {
+	Qid         int64   `json:"qid"`
+	EF          float64 `json:"ef"`
+	Interval    int     `json:"interval"`
+	Repetitions int     `json:"repetitions"`
+	// Due 下一次复习的日期
+	Due int64 `json:"due"`
+}
+
+func newCard(c domain.Card) Card {
+	return Card{
+		Qid:         c.Qid,
+		EF:          c.EF,
+		Interval:    c.Interval,
+		Repetitions: c.Repetitions,
+		Due:         c.Due.UnixMilli(),
+	}
+}
+
+type TodayResp struct {
+	Total     int64      `json:"total"`
+	Questions []Question `json:"questions"`
+}
+
+type Question struct {
+	Id     int64    `json:"id"`
+	Title  string   `json:"title"`
+	Labels []string `json:"labels"`
+	Utime  int64    `json:"utime"`
+}
+
+func newQuestion(q baguwen.Question) Question {
+	return Question{
+		Id:     q.Id,
+		Title:  q.Title,
+		Labels: q.Labels,
+		Utime:  q.Utime.UnixMilli(),
+	}
+}
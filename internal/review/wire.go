@@ -0,0 +1,66 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package review
+
+import (
+	"sync"
+	"time"
+
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/review/internal/job"
+	"github.com/ecodeclub/webook/internal/review/internal/repository"
+	"github.com/ecodeclub/webook/internal/review/internal/repository/cache"
+	"github.com/ecodeclub/webook/internal/review/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/review/internal/service"
+	"github.com/ecodeclub/webook/internal/review/internal/web"
+	"github.com/ego-component/egorm"
+	"github.com/google/wire"
+	"github.com/redis/go-redis/v9"
+)
+
+func InitModule(db *egorm.Component, cmd redis.Cmdable, queModule *baguwen.Module) *Module {
+	wire.Build(
+		initDAO,
+		cache.NewReviewRedisCache,
+		repository.NewCachedReviewRepository,
+		service.NewService,
+		wire.FieldsOf(new(*baguwen.Module), "Svc"),
+		web.NewHandler,
+		initDueQueueJob,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module)
+}
+
+var once = &sync.Once{}
+
+func initDAO(db *egorm.Component) dao.ReviewDAO {
+	once.Do(func() {
+		err := dao.InitTables(db)
+		if err != nil {
+			panic(err)
+		}
+	})
+	return dao.NewReviewGORMDAO(db)
+}
+
+func initDueQueueJob(svc service.Service) *DueQueueJob {
+	return job.NewDueQueueJob(svc, 100, time.Hour)
+}
+
+type Handler = web.Handler
+type DueQueueJob = job.DueQueueJob
@@ -0,0 +1,67 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// 可以被搜索的业务
+const (
+	BizQuestion = "question"
+	BizCase     = "case"
+	BizSkill    = "skill"
+)
+
+// Document 被索引的文档，Biz + BizID 唯一确定一个文档
+type Document struct {
+	Biz   string
+	BizID int64
+
+	Title   string
+	Content string
+	// 关键字，例如问题答案中的关键字
+	Keywords string
+	Labels   []string
+}
+
+type Query struct {
+	// 用户输入的关键字
+	Keywords string
+	// 为空表示搜索全部业务
+	Biz string
+	// 文档必须包含全部的标签
+	Labels []string
+	Offset int
+	Limit  int
+}
+
+type Hit struct {
+	Biz    string
+	BizID  int64
+	Title  string
+	Labels []string
+	Score  float64
+	// 命中的字段，命中的部分用 <em></em> 包起来
+	Highlight Highlight
+}
+
+// Highlight 没有命中的字段为空
+type Highlight struct {
+	Title    string
+	Content  string
+	Keywords string
+}
+
+type Result struct {
+	Total int64
+	Hits  []Hit
+}
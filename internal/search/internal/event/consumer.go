@@ -0,0 +1,89 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+	"github.com/ecodeclub/webook/internal/search/internal/service"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+type SyncConsumer struct {
+	svc      service.Service
+	consumer mq.Consumer
+	logger   *elog.Component
+}
+
+func NewSyncConsumer(svc service.Service, q mq.MQ) (*SyncConsumer, error) {
+	groupID := "search"
+	consumer, err := q.Consumer(syncSearchEvents, groupID)
+	if err != nil {
+		return nil, err
+	}
+	return &SyncConsumer{
+		svc:      svc,
+		consumer: consumer,
+		logger:   elog.DefaultLogger,
+	}, nil
+}
+
+// Start 后面要考虑借助 ctx 来优雅退出
+func (c *SyncConsumer) Start(ctx context.Context) {
+	go func() {
+		for {
+			err := c.Consume(ctx)
+			if err != nil {
+				c.logger.Error("同步搜索数据失败", elog.FieldErr(err))
+			}
+		}
+	}()
+}
+
+func (c *SyncConsumer) Consume(ctx context.Context) error {
+	msg, err := c.consumer.Consume(ctx)
+	if err != nil {
+		return fmt.Errorf("获取消息失败: %w", err)
+	}
+	var evt SyncSearchEvent
+	err = json.Unmarshal(msg.Value, &evt)
+	if err != nil {
+		return fmt.Errorf("解析消息失败: %w", err)
+	}
+	if evt.Deleted {
+		err = c.svc.Delete(ctx, evt.Biz, evt.BizId)
+	} else {
+		err = c.svc.Index(ctx, domain.Document{
+			Biz:      evt.Biz,
+			BizID:    evt.BizId,
+			Title:    evt.Title,
+			Content:  evt.Content,
+			Keywords: evt.Keywords,
+			Labels:   evt.Labels,
+		})
+	}
+	if err != nil {
+		return fmt.Errorf("更新索引失败 biz %s, bizId %d: %w", evt.Biz, evt.BizId, err)
+	}
+	return nil
+}
+
+func (c *SyncConsumer) Stop(_ context.Context) error {
+	return c.consumer.Close()
+}
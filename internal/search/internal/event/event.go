@@ -0,0 +1,29 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package event
+
+const syncSearchEvents = "sync_search_events"
+
+// SyncSearchEvent 各个业务在数据发生变化的时候发送的事件
+type SyncSearchEvent struct {
+	Biz   string `json:"biz"`
+	BizId int64  `json:"biz_id"`
+	// 为 true 的时候从索引中删除，例如下架
+	Deleted  bool     `json:"deleted"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Keywords string   `json:"keywords"`
+	Labels   []string `json:"labels"`
+}
@@ -0,0 +1,230 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+)
+
+// ESIndex 通过 REST API 访问 Elasticsearch（或者兼容的 OpenSearch）
+// 只用到了最基础的 API，所以没有引入官方客户端
+type ESIndex struct {
+	client *http.Client
+	// 例如 http://localhost:9200
+	addr  string
+	index string
+}
+
+func NewESIndex(client *http.Client, addr string, index string) *ESIndex {
+	return &ESIndex{
+		client: client,
+		addr:   strings.TrimSuffix(addr, "/"),
+		index:  index,
+	}
+}
+
+// esDocument 存储在 ES 中的文档
+type esDocument struct {
+	Biz      string   `json:"biz"`
+	BizID    int64    `json:"biz_id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Keywords string   `json:"keywords"`
+	Labels   []string `json:"labels"`
+}
+
+// InitIndex 创建索引，索引已经存在的时候什么也不做
+// biz 和 labels 用于过滤，所以是 keyword 类型
+func (e *ESIndex) InitIndex(ctx context.Context) error {
+	mapping := map[string]any{
+		"mappings": map[string]any{
+			"properties": map[string]any{
+				"biz":      map[string]any{"type": "keyword"},
+				"biz_id":   map[string]any{"type": "long"},
+				"title":    map[string]any{"type": "text"},
+				"content":  map[string]any{"type": "text"},
+				"keywords": map[string]any{"type": "text"},
+				"labels":   map[string]any{"type": "keyword"},
+			},
+		},
+	}
+	status, body, err := e.do(ctx, http.MethodHead, "/"+e.index, nil)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusOK {
+		return nil
+	}
+	status, body, err = e.do(ctx, http.MethodPut, "/"+e.index, mapping)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("创建索引失败 %d: %s", status, body)
+	}
+	return nil
+}
+
+func (e *ESIndex) Put(ctx context.Context, doc domain.Document) error {
+	status, body, err := e.do(ctx, http.MethodPut, e.docPath(doc.Biz, doc.BizID), esDocument{
+		Biz:      doc.Biz,
+		BizID:    doc.BizID,
+		Title:    doc.Title,
+		Content:  doc.Content,
+		Keywords: doc.Keywords,
+		Labels:   doc.Labels,
+	})
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusCreated {
+		return fmt.Errorf("索引文档失败 %d: %s", status, body)
+	}
+	return nil
+}
+
+func (e *ESIndex) Delete(ctx context.Context, biz string, bizID int64) error {
+	status, body, err := e.do(ctx, http.MethodDelete, e.docPath(biz, bizID), nil)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK && status != http.StatusNotFound {
+		return fmt.Errorf("删除文档失败 %d: %s", status, body)
+	}
+	return nil
+}
+
+func (e *ESIndex) Search(ctx context.Context, q domain.Query) (domain.Result, error) {
+	filters := make([]any, 0, len(q.Labels)+1)
+	if q.Biz != "" {
+		filters = append(filters, map[string]any{"term": map[string]any{"biz": q.Biz}})
+	}
+	for _, label := range q.Labels {
+		filters = append(filters, map[string]any{"term": map[string]any{"labels": label}})
+	}
+	req := map[string]any{
+		"from": q.Offset,
+		"size": q.Limit,
+		"query": map[string]any{
+			"bool": map[string]any{
+				"must": map[string]any{
+					"multi_match": map[string]any{
+						"query":    q.Keywords,
+						"fields":   []string{"title^3", "keywords^2", "content"},
+						"operator": "and",
+					},
+				},
+				"filter": filters,
+			},
+		},
+		"highlight": map[string]any{
+			"pre_tags":  []string{highlightPreTag},
+			"post_tags": []string{highlightPostTag},
+			"fields": map[string]any{
+				"title":    map[string]any{"number_of_fragments": 0},
+				"keywords": map[string]any{"number_of_fragments": 0},
+				"content": map[string]any{
+					"fragment_size":       fragmentSize,
+					"number_of_fragments": 1,
+				},
+			},
+		},
+	}
+	status, body, err := e.do(ctx, http.MethodPost, "/"+e.index+"/_search", req)
+	if err != nil {
+		return domain.Result{}, err
+	}
+	if status != http.StatusOK {
+		return domain.Result{}, fmt.Errorf("搜索失败 %d: %s", status, body)
+	}
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Score     float64             `json:"_score"`
+				Source    esDocument          `json:"_source"`
+				Highlight map[string][]string `json:"highlight"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return domain.Result{}, fmt.Errorf("解析搜索结果失败: %w", err)
+	}
+	res := domain.Result{
+		Total: resp.Hits.Total.Value,
+		Hits:  make([]domain.Hit, 0, len(resp.Hits.Hits)),
+	}
+	for _, h := range resp.Hits.Hits {
+		res.Hits = append(res.Hits, domain.Hit{
+			Biz:    h.Source.Biz,
+			BizID:  h.Source.BizID,
+			Title:  h.Source.Title,
+			Labels: h.Source.Labels,
+			Score:  h.Score,
+			Highlight: domain.Highlight{
+				Title:    e.first(h.Highlight["title"]),
+				Content:  e.first(h.Highlight["content"]),
+				Keywords: e.first(h.Highlight["keywords"]),
+			},
+		})
+	}
+	return res, nil
+}
+
+func (e *ESIndex) first(fragments []string) string {
+	if len(fragments) == 0 {
+		return ""
+	}
+	return fragments[0]
+}
+
+func (e *ESIndex) docPath(biz string, bizID int64) string {
+	return fmt.Sprintf("/%s/_doc/%s_%d", e.index, biz, bizID)
+}
+
+// do 发送请求，返回状态码和响应体
+func (e *ESIndex) do(ctx context.Context, method string, path string, body any) (int, []byte, error) {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return 0, nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, e.addr+path, reader)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, data, err
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+)
+
+// Index 索引的抽象
+// 测试和单机部署使用 MemoryIndex，生产环境使用 ESIndex
+type Index interface {
+	// Put 新增或者覆盖一个文档
+	Put(ctx context.Context, doc domain.Document) error
+	// Delete 删除文档，文档不存在的时候不会返回 error
+	Delete(ctx context.Context, biz string, bizID int64) error
+	Search(ctx context.Context, q domain.Query) (domain.Result, error)
+}
+
+const (
+	highlightPreTag  = "<em>"
+	highlightPostTag = "</em>"
+	// content 的高亮只返回命中位置附近的一个片段
+	fragmentSize = 100
+)
@@ -0,0 +1,189 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+)
+
+// 不同字段命中的权重
+const (
+	titleWeight    = 3
+	keywordsWeight = 2
+	contentWeight  = 1
+)
+
+// MemoryIndex 进程内的倒排索引
+// 数据不会持久化，也不会在多个实例之间共享，所以只在测试里面使用
+type MemoryIndex struct {
+	mu   sync.RWMutex
+	docs map[string]domain.Document
+	// term => 文档 key => 权重
+	postings map[string]map[string]float64
+}
+
+func NewMemoryIndex() *MemoryIndex {
+	return &MemoryIndex{
+		docs:     make(map[string]domain.Document, 64),
+		postings: make(map[string]map[string]float64, 256),
+	}
+}
+
+func (m *MemoryIndex) Put(_ context.Context, doc domain.Document) error {
+	key := m.key(doc.Biz, doc.BizID)
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(key)
+	m.docs[key] = doc
+	m.add(key, doc.Title, titleWeight)
+	m.add(key, doc.Keywords, keywordsWeight)
+	m.add(key, doc.Content, contentWeight)
+	return nil
+}
+
+func (m *MemoryIndex) add(key string, text string, weight float64) {
+	for _, term := range tokenize(text, true) {
+		p, ok := m.postings[term]
+		if !ok {
+			p = make(map[string]float64, 8)
+			m.postings[term] = p
+		}
+		p[key] += weight
+	}
+}
+
+func (m *MemoryIndex) Delete(_ context.Context, biz string, bizID int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.remove(m.key(biz, bizID))
+	return nil
+}
+
+func (m *MemoryIndex) remove(key string) {
+	doc, ok := m.docs[key]
+	if !ok {
+		return
+	}
+	delete(m.docs, key)
+	for _, text := range []string{doc.Title, doc.Keywords, doc.Content} {
+		for _, term := range tokenize(text, true) {
+			p := m.postings[term]
+			delete(p, key)
+			if len(p) == 0 {
+				delete(m.postings, term)
+			}
+		}
+	}
+}
+
+func (m *MemoryIndex) Search(_ context.Context, q domain.Query) (domain.Result, error) {
+	terms := m.dedup(tokenize(q.Keywords, false))
+	if len(terms) == 0 {
+		return domain.Result{}, nil
+	}
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	// 必须命中全部的 term
+	scores := make(map[string]float64, len(m.postings[terms[0]]))
+	for key, w := range m.postings[terms[0]] {
+		scores[key] = w
+	}
+	for _, term := range terms[1:] {
+		p := m.postings[term]
+		for key := range scores {
+			w, ok := p[key]
+			if !ok {
+				delete(scores, key)
+				continue
+			}
+			scores[key] += w
+		}
+	}
+
+	hits := make([]domain.Hit, 0, len(scores))
+	for key, score := range scores {
+		doc := m.docs[key]
+		if !m.match(doc, q) {
+			continue
+		}
+		hits = append(hits, domain.Hit{
+			Biz:    doc.Biz,
+			BizID:  doc.BizID,
+			Title:  doc.Title,
+			Labels: doc.Labels,
+			Score:  score,
+			Highlight: domain.Highlight{
+				Title:    highlight(doc.Title, terms, 0),
+				Content:  highlight(doc.Content, terms, fragmentSize),
+				Keywords: highlight(doc.Keywords, terms, 0),
+			},
+		})
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Biz != hits[j].Biz {
+			return hits[i].Biz < hits[j].Biz
+		}
+		return hits[i].BizID > hits[j].BizID
+	})
+
+	res := domain.Result{Total: int64(len(hits))}
+	if q.Offset >= len(hits) {
+		return res, nil
+	}
+	end := len(hits)
+	if q.Limit > 0 {
+		end = min(end, q.Offset+q.Limit)
+	}
+	res.Hits = hits[q.Offset:end]
+	return res, nil
+}
+
+func (m *MemoryIndex) match(doc domain.Document, q domain.Query) bool {
+	if q.Biz != "" && q.Biz != doc.Biz {
+		return false
+	}
+	for _, label := range q.Labels {
+		if !slice.Contains(doc.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *MemoryIndex) dedup(terms []string) []string {
+	seen := make(map[string]struct{}, len(terms))
+	res := make([]string, 0, len(terms))
+	for _, t := range terms {
+		if _, ok := seen[t]; ok {
+			continue
+		}
+		seen[t] = struct{}{}
+		res = append(res, t)
+	}
+	return res
+}
+
+func (m *MemoryIndex) key(biz string, bizID int64) string {
+	return fmt.Sprintf("%s:%d", biz, bizID)
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenize(t *testing.T) {
+	testCases := []struct {
+		name        string
+		text        string
+		withUnigram bool
+		want        []string
+	}{
+		{
+			name: "英文",
+			text: "Redis Cluster, MySQL-8",
+			want: []string{"redis", "cluster", "mysql", "8"},
+		},
+		{
+			name: "中文二元组",
+			text: "数据库索引",
+			want: []string{"数据", "据库", "库索", "索引"},
+		},
+		{
+			name:        "中文建索引",
+			text:        "分布式",
+			withUnigram: true,
+			want:        []string{"分布", "布式", "分", "布", "式"},
+		},
+		{
+			name: "中英文混合",
+			text: "Go的GC",
+			want: []string{"go", "的", "gc"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, tokenize(tc.text, tc.withUnigram))
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		terms    []string
+		fragment int
+		want     string
+	}{
+		{
+			name:  "相邻的命中合并",
+			text:  "MySQL 数据库索引",
+			terms: []string{"数据", "据库"},
+			want:  "MySQL <em>数据库</em>索引",
+		},
+		{
+			name:  "忽略大小写",
+			text:  "Redis 和 redis",
+			terms: []string{"redis"},
+			want:  "<em>Redis</em> 和 <em>redis</em>",
+		},
+		{
+			name:  "没有命中",
+			text:  "Kafka",
+			terms: []string{"redis"},
+			want:  "",
+		},
+		{
+			name:     "片段",
+			text:     "0123456789abcdefghij",
+			terms:    []string{"abc"},
+			fragment: 5,
+			want:     "9<em>abc</em>d",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.want, highlight(tc.text, tc.terms, tc.fragment))
+		})
+	}
+}
+
+func TestMemoryIndex(t *testing.T) {
+	ctx := context.Background()
+	idx := NewMemoryIndex()
+	docs := []domain.Document{
+		{Biz: domain.BizQuestion, BizID: 1, Title: "Redis 的数据结构", Content: "String、List、Hash",
+			Labels: []string{"Redis"}},
+		{Biz: domain.BizQuestion, BizID: 2, Title: "MySQL 索引", Content: "B+ 树，和 Redis 无关",
+			Keywords: "最左前缀", Labels: []string{"MySQL"}},
+		{Biz: domain.BizCase, BizID: 1, Title: "缓存击穿", Content: "使用 Redis 分布式锁",
+			Labels: []string{"Redis", "分布式"}},
+	}
+	for _, doc := range docs {
+		require.NoError(t, idx.Put(ctx, doc))
+	}
+
+	res, err := idx.Search(ctx, domain.Query{Keywords: "redis"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), res.Total)
+	// 标题命中的权重更高
+	assert.Equal(t, int64(1), res.Hits[0].BizID)
+	assert.Equal(t, domain.BizQuestion, res.Hits[0].Biz)
+	assert.Equal(t, "<em>Redis</em> 的数据结构", res.Hits[0].Highlight.Title)
+
+	res, err = idx.Search(ctx, domain.Query{Keywords: "redis", Biz: domain.BizCase})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Hits))
+	assert.Equal(t, "使用 <em>Redis</em> 分布式锁", res.Hits[0].Highlight.Content)
+	assert.Equal(t, "", res.Hits[0].Highlight.Title)
+
+	res, err = idx.Search(ctx, domain.Query{Keywords: "redis", Labels: []string{"Redis", "分布式"}})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Hits))
+	assert.Equal(t, domain.BizCase, res.Hits[0].Biz)
+
+	// 必须命中全部的词
+	res, err = idx.Search(ctx, domain.Query{Keywords: "最左 索引"})
+	require.NoError(t, err)
+	require.Equal(t, 1, len(res.Hits))
+	assert.Equal(t, int64(2), res.Hits[0].BizID)
+	assert.Equal(t, "<em>最左</em>前缀", res.Hits[0].Highlight.Keywords)
+
+	// 分页
+	res, err = idx.Search(ctx, domain.Query{Keywords: "redis", Offset: 1, Limit: 1})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), res.Total)
+	assert.Equal(t, 1, len(res.Hits))
+
+	// 更新之后旧的内容搜索不到
+	require.NoError(t, idx.Put(ctx, domain.Document{Biz: domain.BizQuestion, BizID: 1, Title: "Kafka 的分区"}))
+	res, err = idx.Search(ctx, domain.Query{Keywords: "redis"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), res.Total)
+	res, err = idx.Search(ctx, domain.Query{Keywords: "分区"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), res.Total)
+
+	require.NoError(t, idx.Delete(ctx, domain.BizQuestion, 1))
+	require.NoError(t, idx.Delete(ctx, domain.BizQuestion, 100))
+	res, err = idx.Search(ctx, domain.Query{Keywords: "分区"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.Total)
+	assert.Equal(t, 0, len(idx.postings["kafka"]))
+}
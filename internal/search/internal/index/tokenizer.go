@@ -0,0 +1,118 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package index
+
+import (
+	"strings"
+	"unicode"
+)
+
+// tokenize 简单的分词：
+// 连续的字母数字作为一个词，统一转小写；
+// 连续的汉字切成二元组，单个汉字自成一个词。
+// 如果 withUnigram 为 true，那么连续的汉字还会额外输出每一个字，
+// 建索引的时候使用，这样单个汉字的查询也能命中
+func tokenize(text string, withUnigram bool) []string {
+	var (
+		res  []string
+		word strings.Builder
+		han  []rune
+	)
+	flushWord := func() {
+		if word.Len() > 0 {
+			res = append(res, word.String())
+			word.Reset()
+		}
+	}
+	flushHan := func() {
+		switch {
+		case len(han) == 1:
+			res = append(res, string(han))
+		case len(han) > 1:
+			for i := 0; i+1 < len(han); i++ {
+				res = append(res, string(han[i:i+2]))
+			}
+			if withUnigram {
+				for _, r := range han {
+					res = append(res, string(r))
+				}
+			}
+		}
+		han = han[:0]
+	}
+	for _, r := range text {
+		switch {
+		case unicode.Is(unicode.Han, r):
+			flushWord()
+			han = append(han, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			flushHan()
+			word.WriteRune(unicode.ToLower(r))
+		default:
+			flushWord()
+			flushHan()
+		}
+	}
+	flushWord()
+	flushHan()
+	return res
+}
+
+// highlight 把 text 中命中 terms 的部分用高亮标签包起来
+// 如果没有命中任何 term，返回空字符串
+// fragment 大于 0 的时候，只返回第一个命中位置附近长度为 fragment 的片段
+func highlight(text string, terms []string, fragment int) string {
+	runes := []rune(text)
+	lower := make([]rune, len(runes))
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+	marked := make([]bool, len(runes))
+	first := -1
+	for _, term := range terms {
+		t := []rune(term)
+		for i := 0; i+len(t) <= len(lower); i++ {
+			if string(lower[i:i+len(t)]) != term {
+				continue
+			}
+			for j := i; j < i+len(t); j++ {
+				marked[j] = true
+			}
+			if first == -1 || i < first {
+				first = i
+			}
+		}
+	}
+	if first == -1 {
+		return ""
+	}
+	start, end := 0, len(runes)
+	if fragment > 0 && len(runes) > fragment {
+		// 命中的位置前面保留一点上下文
+		start = max(0, first-fragment/5)
+		end = min(len(runes), start+fragment)
+	}
+	var sb strings.Builder
+	for i := start; i < end; i++ {
+		if marked[i] && (i == start || !marked[i-1]) {
+			sb.WriteString(highlightPreTag)
+		}
+		sb.WriteRune(runes[i])
+		if marked[i] && (i == end-1 || !marked[i+1]) {
+			sb.WriteString(highlightPostTag)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,256 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build e2e
+
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ecodeclub/ekit/iox"
+	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+	"github.com/ecodeclub/webook/internal/search/internal/event"
+	"github.com/ecodeclub/webook/internal/search/internal/index"
+	"github.com/ecodeclub/webook/internal/search/internal/integration/startup"
+	"github.com/ecodeclub/webook/internal/search/internal/service"
+	"github.com/ecodeclub/webook/internal/search/internal/web"
+	"github.com/ecodeclub/webook/internal/test"
+	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/gotomicro/ego/core/econf"
+	"github.com/gotomicro/ego/server/egin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type HandlerTestSuite struct {
+	suite.Suite
+	server *egin.Component
+	idx    *index.MemoryIndex
+}
+
+func (s *HandlerTestSuite) SetupSuite() {
+	s.idx = index.NewMemoryIndex()
+	handler := startup.InitHandler(s.idx)
+	econf.Set("server", map[string]any{"contextTimeout": "1s"})
+	server := egin.Load("server").Build()
+	handler.PublicRoutes(server.Engine)
+	s.server = server
+
+	ctx := context.Background()
+	docs := []domain.Document{
+		{
+			Biz: domain.BizQuestion, BizID: 1,
+			Title:    "Redis 的数据结构有哪些",
+			Content:  "请说一说 Redis 支持的数据结构",
+			Keywords: "跳表 压缩列表",
+			Labels:   []string{"Redis"},
+		},
+		{
+			Biz: domain.BizQuestion, BizID: 2,
+			Title:   "MySQL 的索引",
+			Content: "B+ 树索引",
+			Labels:  []string{"MySQL"},
+		},
+		{
+			Biz: domain.BizCase, BizID: 1,
+			Title:   "用 Redis 实现分布式锁",
+			Content: "SETNX 加过期时间",
+			Labels:  []string{"Redis", "分布式锁"},
+		},
+		{
+			Biz: domain.BizSkill, BizID: 1,
+			Title:   "Redis",
+			Content: "Redis 相关的技能",
+			Labels:  []string{"Redis"},
+		},
+	}
+	for _, doc := range docs {
+		require.NoError(s.T(), s.idx.Put(ctx, doc))
+	}
+}
+
+func (s *HandlerTestSuite) TestSearch() {
+	testCases := []struct {
+		name     string
+		req      web.SearchReq
+		wantCode int
+		wantResp test.Result[web.SearchResult]
+	}{
+		{
+			name:     "按照业务过滤",
+			req:      web.SearchReq{Keywords: "跳表", Biz: domain.BizQuestion},
+			wantCode: 200,
+			wantResp: test.Result[web.SearchResult]{
+				Data: web.SearchResult{
+					Total: 1,
+					Hits: []web.Hit{
+						{
+							Biz:    domain.BizQuestion,
+							BizId:  1,
+							Title:  "Redis 的数据结构有哪些",
+							Labels: []string{"Redis"},
+							Highlight: web.Highlight{
+								Keywords: "<em>跳表</em> 压缩列表",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "按照标签过滤",
+			req:      web.SearchReq{Keywords: "redis", Labels: []string{"分布式锁"}},
+			wantCode: 200,
+			wantResp: test.Result[web.SearchResult]{
+				Data: web.SearchResult{
+					Total: 1,
+					Hits: []web.Hit{
+						{
+							Biz:    domain.BizCase,
+							BizId:  1,
+							Title:  "用 Redis 实现分布式锁",
+							Labels: []string{"Redis", "分布式锁"},
+							Highlight: web.Highlight{
+								Title: "用 <em>Redis</em> 实现分布式锁",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "高亮中文",
+			req:      web.SearchReq{Keywords: "数据结构", Limit: 10},
+			wantCode: 200,
+			wantResp: test.Result[web.SearchResult]{
+				Data: web.SearchResult{
+					Total: 1,
+					Hits: []web.Hit{
+						{
+							Biz:    domain.BizQuestion,
+							BizId:  1,
+							Title:  "Redis 的数据结构有哪些",
+							Labels: []string{"Redis"},
+							Highlight: web.Highlight{
+								Title:   "Redis 的<em>数据结构</em>有哪些",
+								Content: "请说一说 Redis 支持的<em>数据结构</em>",
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name:     "没有结果",
+			req:      web.SearchReq{Keywords: "Kafka"},
+			wantCode: 200,
+			wantResp: test.Result[web.SearchResult]{},
+		},
+	}
+	for _, tc := range testCases {
+		s.T().Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost,
+				"/search", iox.NewJSONReader(tc.req))
+			req.Header.Set("content-type", "application/json")
+			require.NoError(t, err)
+			recorder := test.NewJSONResponseRecorder[web.SearchResult]()
+			s.server.ServeHTTP(recorder, req)
+			require.Equal(t, tc.wantCode, recorder.Code)
+			assert.Equal(t, tc.wantResp, recorder.MustScan())
+		})
+	}
+}
+
+func (s *HandlerTestSuite) TestSearch_Page() {
+	req, err := http.NewRequest(http.MethodPost,
+		"/search", iox.NewJSONReader(web.SearchReq{Keywords: "redis", Offset: 1, Limit: 2}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(s.T(), err)
+	recorder := test.NewJSONResponseRecorder[web.SearchResult]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	data := recorder.MustScan().Data
+	assert.Equal(s.T(), int64(3), data.Total)
+	assert.Equal(s.T(), 2, len(data.Hits))
+}
+
+func TestHandler(t *testing.T) {
+	suite.Run(t, new(HandlerTestSuite))
+}
+
+type SyncConsumerTestSuite struct {
+	suite.Suite
+	idx      *index.MemoryIndex
+	consumer *event.SyncConsumer
+	producer mq.Producer
+}
+
+func (s *SyncConsumerTestSuite) SetupSuite() {
+	q := testioc.InitMQ()
+	s.idx = index.NewMemoryIndex()
+	consumer, err := event.NewSyncConsumer(service.NewService(s.idx), q)
+	require.NoError(s.T(), err)
+	s.consumer = consumer
+	producer, err := q.Producer("sync_search_events")
+	require.NoError(s.T(), err)
+	s.producer = producer
+}
+
+func (s *SyncConsumerTestSuite) TestConsume() {
+	t := s.T()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	s.produce(ctx, event.SyncSearchEvent{
+		Biz:      domain.BizQuestion,
+		BizId:    1,
+		Title:    "Redis 的数据结构",
+		Keywords: "跳表",
+		Labels:   []string{"Redis"},
+	})
+	require.NoError(t, s.consumer.Consume(ctx))
+	res, err := s.idx.Search(ctx, domain.Query{Keywords: "跳表"})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), res.Total)
+	assert.Equal(t, "Redis 的数据结构", res.Hits[0].Title)
+
+	// 下架之后从索引中删除
+	s.produce(ctx, event.SyncSearchEvent{
+		Biz:     domain.BizQuestion,
+		BizId:   1,
+		Deleted: true,
+	})
+	require.NoError(t, s.consumer.Consume(ctx))
+	res, err = s.idx.Search(ctx, domain.Query{Keywords: "跳表"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), res.Total)
+}
+
+func (s *SyncConsumerTestSuite) produce(ctx context.Context, evt event.SyncSearchEvent) {
+	data, err := json.Marshal(evt)
+	require.NoError(s.T(), err)
+	_, err = s.producer.Produce(ctx, &mq.Message{Value: data})
+	require.NoError(s.T(), err)
+}
+
+func TestSyncConsumer(t *testing.T) {
+	suite.Run(t, new(SyncConsumerTestSuite))
+}
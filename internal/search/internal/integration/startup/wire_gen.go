@@ -0,0 +1,21 @@
+// Code generated by Wire. DO NOT EDIT.
+
+//go:generate go run github.com/google/wire/cmd/wire
+//go:build !wireinject
+// +build !wireinject
+
+package startup
+
+import (
+	"github.com/ecodeclub/webook/internal/search/internal/index"
+	"github.com/ecodeclub/webook/internal/search/internal/service"
+	"github.com/ecodeclub/webook/internal/search/internal/web"
+)
+
+// Injectors from wire.go:
+
+func InitHandler(idx index.Index) *web.Handler {
+	serviceService := service.NewService(idx)
+	handler := web.NewHandler(serviceService)
+	return handler
+}
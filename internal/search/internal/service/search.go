@@ -0,0 +1,58 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+	"github.com/ecodeclub/webook/internal/search/internal/index"
+)
+
+const (
+	defaultLimit = 10
+	maxLimit     = 100
+)
+
+type Service interface {
+	Search(ctx context.Context, q domain.Query) (domain.Result, error)
+	// Index 新增或者更新文档
+	Index(ctx context.Context, doc domain.Document) error
+	Delete(ctx context.Context, biz string, bizID int64) error
+}
+
+type service struct {
+	idx index.Index
+}
+
+func NewService(idx index.Index) Service {
+	return &service{idx: idx}
+}
+
+func (s *service) Search(ctx context.Context, q domain.Query) (domain.Result, error) {
+	if q.Limit <= 0 {
+		q.Limit = defaultLimit
+	}
+	q.Limit = min(q.Limit, maxLimit)
+	return s.idx.Search(ctx, q)
+}
+
+func (s *service) Index(ctx context.Context, doc domain.Document) error {
+	return s.idx.Put(ctx, doc)
+}
+
+func (s *service) Delete(ctx context.Context, biz string, bizID int64) error {
+	return s.idx.Delete(ctx, biz, bizID)
+}
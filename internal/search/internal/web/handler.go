@@ -0,0 +1,56 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import (
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/ginx"
+	"github.com/ecodeclub/webook/internal/search/internal/domain"
+	"github.com/ecodeclub/webook/internal/search/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	svc service.Service
+}
+
+func NewHandler(svc service.Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+func (h *Handler) PublicRoutes(server *gin.Engine) {
+	server.POST("/search", ginx.B[SearchReq](h.Search))
+}
+
+func (h *Handler) Search(ctx *ginx.Context, req SearchReq) (ginx.Result, error) {
+	res, err := h.svc.Search(ctx, domain.Query{
+		Keywords: req.Keywords,
+		Biz:      req.Biz,
+		Labels:   req.Labels,
+		Offset:   req.Offset,
+		Limit:    req.Limit,
+	})
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: SearchResult{
+			Total: res.Total,
+			Hits: slice.Map(res.Hits, func(idx int, src domain.Hit) Hit {
+				return newHit(src)
+			}),
+		},
+	}, nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package web
+
+import "github.com/ecodeclub/webook/internal/search/internal/domain"
+
+type SearchReq struct {
+	Keywords string `json:"keywords"`
+	// question, case, skill，为空表示全部
+	Biz    string   `json:"biz,omitempty"`
+	Labels []string `json:"labels,omitempty"`
+	Offset int      `json:"offset,omitempty"`
+	Limit  int      `json:"limit,omitempty"`
+}
+
+type SearchResult struct {
+	Total int64 `json:"total,omitempty"`
+	Hits  []Hit `json:"hits,omitempty"`
+}
+
+type Hit struct {
+	Biz    string   `json:"biz"`
+	BizId  int64    `json:"bizId"`
+	Title  string   `json:"title"`
+	Labels []string `json:"labels,omitempty"`
+	// 命中的部分用 <em></em> 包起来，没有命中的字段为空
+	Highlight Highlight `json:"highlight"`
+}
+
+type Highlight struct {
+	Title    string `json:"title,omitempty"`
+	Content  string `json:"content,omitempty"`
+	Keywords string `json:"keywords,omitempty"`
+}
+
+func newHit(h domain.Hit) Hit {
+	return Hit{
+		Biz:    h.Biz,
+		BizId:  h.BizID,
+		Title:  h.Title,
+		Labels: h.Labels,
+		Highlight: Highlight{
+			Title:    h.Highlight.Title,
+			Content:  h.Highlight.Content,
+			Keywords: h.Highlight.Keywords,
+		},
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build wireinject
+
+package search
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/ecodeclub/mq-api"
+	"github.com/ecodeclub/webook/internal/search/internal/event"
+	"github.com/ecodeclub/webook/internal/search/internal/index"
+	"github.com/ecodeclub/webook/internal/search/internal/service"
+	"github.com/ecodeclub/webook/internal/search/internal/web"
+	"github.com/google/wire"
+	"github.com/gotomicro/ego/core/econf"
+)
+
+func InitModule(q mq.MQ) (*Module, error) {
+	wire.Build(
+		initIndex,
+		service.NewService,
+		web.NewHandler,
+		initSyncConsumer,
+		wire.Struct(new(Module), "*"),
+	)
+	return new(Module), nil
+}
+
+// initIndex 必须配置 Elasticsearch，进程内的索引只在测试里面使用
+func initIndex() index.Index {
+	type Config struct {
+		Addr  string `yaml:"addr"`
+		Index string `yaml:"index"`
+	}
+	var cfg Config
+	err := econf.UnmarshalKey("search.elasticsearch", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	if cfg.Addr == "" {
+		panic("search.elasticsearch.addr 不能为空")
+	}
+	if cfg.Index == "" {
+		cfg.Index = "webook"
+	}
+	idx := index.NewESIndex(&http.Client{Timeout: 3 * time.Second}, cfg.Addr, cfg.Index)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	err = idx.InitIndex(ctx)
+	if err != nil {
+		panic(err)
+	}
+	return idx
+}
+
+func initSyncConsumer(svc service.Service, q mq.MQ) *event.SyncConsumer {
+	c, err := event.NewSyncConsumer(svc, q)
+	if err != nil {
+		panic(err)
+	}
+	c.Start(context.Background())
+	return c
+}
+
+type Handler = web.Handler
+type Service = service.Service
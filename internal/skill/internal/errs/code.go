@@ -1,7 +1,8 @@
 package errs
 
 var (
-	SystemError = ErrorCode{Code: 507001, Msg: "系统错误"}
+	SystemError       = ErrorCode{Code: 507001, Msg: "系统错误"}
+	SkillInRecycleBin = ErrorCode{Code: 507002, Msg: "回收站里面有同名的技能，请先恢复"}
 )
 
 type ErrorCode struct {
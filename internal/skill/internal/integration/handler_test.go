@@ -13,6 +13,9 @@ import (
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/cases"
 	casemocks "github.com/ecodeclub/webook/internal/cases/mocks"
+	"github.com/ecodeclub/webook/internal/pkg/middleware"
+	"github.com/ecodeclub/webook/internal/practice"
+	practicemocks "github.com/ecodeclub/webook/internal/practice/mocks"
 	baguwen "github.com/ecodeclub/webook/internal/question"
 	quemocks "github.com/ecodeclub/webook/internal/question/mocks"
 	"go.uber.org/mock/gomock"
@@ -20,6 +23,9 @@ import (
 	"github.com/ecodeclub/ekit/iox"
 	"github.com/ecodeclub/ekit/sqlx"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/ecodeclub/webook/internal/skill/internal/event"
+	evtmocks "github.com/ecodeclub/webook/internal/skill/internal/event/mocks"
 	"github.com/ecodeclub/webook/internal/skill/internal/integration/startup"
 	"github.com/ecodeclub/webook/internal/skill/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/skill/internal/web"
@@ -29,18 +35,23 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/gotomicro/ego/core/econf"
 	"github.com/gotomicro/ego/server/egin"
+	"github.com/gotomicro/ego/task/ejob"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 const uid = 2061
 
 type HandlerTestSuite struct {
 	suite.Suite
-	server *egin.Component
-	db     *egorm.Component
-	dao    dao.SkillDAO
+	server     *egin.Component
+	db         *egorm.Component
+	dao        dao.SkillDAO
+	producer   *evtmocks.MockSyncEventProducer
+	purgeJob   *skill.PurgeJob
+	reindexJob *skill.ReindexJob
 }
 
 func (s *HandlerTestSuite) TearDownTest() {
@@ -76,20 +87,38 @@ func (s *HandlerTestSuite) SetupSuite() {
 			}), nil
 		}).AnyTimes()
 
-	handler, err := startup.InitHandler(
+	// 掌握情况只统计题目数量，方便断言
+	practiceSvc := practicemocks.NewMockService(ctrl)
+	practiceSvc.EXPECT().Progress(gomock.Any(), int64(uid), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, uid int64, qids []int64) (practice.Progress, error) {
+			return practice.Progress{Total: len(qids)}, nil
+		}).AnyTimes()
+
+	s.producer = evtmocks.NewMockSyncEventProducer(ctrl)
+	module, err := startup.InitModule(
 		&baguwen.Module{Svc: queSvc},
 		&cases.Module{Svc: caseSvc},
+		&practice.Module{Svc: practiceSvc},
+		s.producer,
 	)
 	require.NoError(s.T(), err)
+	handler := module.Hdl
+	s.purgeJob = module.PurgeJob
+	s.reindexJob = module.ReindexJob
 	econf.Set("server", map[string]any{"contextTimeout": "1s"})
 	server := egin.Load("server").Build()
 	server.Use(func(ctx *gin.Context) {
 		ctx.Set("_session", session.NewMemorySession(session.Claims{
-			Uid:  uid,
-			Data: map[string]string{"creator": "true"},
+			Uid: uid,
+			Data: map[string]string{
+				"creator":   "true",
+				"memberDDL": strconv.FormatInt(time.Now().Add(time.Hour).UnixMilli(), 10),
+			},
 		}))
 	})
 	handler.PrivateRoutes(server.Engine)
+	server.Use(middleware.NewCheckMembershipMiddlewareBuilder(nil).Build())
+	handler.MemberRoutes(server.Engine)
 	s.server = server
 	s.db = testioc.InitDB()
 	err = dao.InitTables(s.db)
@@ -109,7 +138,13 @@ func (s *HandlerTestSuite) TestSave() {
 		{
 			name: "新增",
 			before: func(t *testing.T) {
-
+				s.producer.EXPECT().Produce(gomock.Any(), event.SyncSearchEvent{
+					Biz:     "skill",
+					BizId:   1,
+					Title:   "mysql",
+					Content: "mysql_desc\nmysql_basic\nmysql_intermediate\nmysql_advanced",
+					Labels:  []string{"mysql"},
+				}).Return(nil)
 			},
 			after: func(t *testing.T) {
 				ctx, cancel := context.WithTimeout(context.Background(), time.Second)
@@ -179,6 +214,7 @@ func (s *HandlerTestSuite) TestSave() {
 		{
 			name: "更新",
 			before: func(t *testing.T) {
+				s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
 				err := s.db.Create(&dao.Skill{
 					Id: 2,
 					Labels: sqlx.JsonColumn[[]string]{
@@ -946,6 +982,143 @@ func (s *HandlerTestSuite) TestRefsByLevelIDs() {
 
 }
 
+func (s *HandlerTestSuite) TestProgress() {
+	t := s.T()
+	now := time.Now().UnixMilli()
+	err := s.db.Create(&dao.Skill{Id: 3, Name: "redis", Ctime: now, Utime: now}).Error
+	require.NoError(t, err)
+	err = s.db.Create([]*dao.SkillLevel{
+		{Id: 5, Sid: 3, Level: "basic", Ctime: now, Utime: now},
+		{Id: 6, Sid: 3, Level: "advanced", Ctime: now, Utime: now},
+	}).Error
+	require.NoError(t, err)
+	err = s.db.Create([]*dao.SkillRef{
+		{Slid: 5, Sid: 3, Rtype: "question", Rid: 1, Ctime: now, Utime: now},
+		{Slid: 5, Sid: 3, Rtype: "question", Rid: 2, Ctime: now, Utime: now},
+		{Slid: 5, Sid: 3, Rtype: "case", Rid: 3, Ctime: now, Utime: now},
+		{Slid: 6, Sid: 3, Rtype: "question", Rid: 4, Ctime: now, Utime: now},
+	}).Error
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/skill/progress", iox.NewJSONReader(web.Sid{Sid: 3}))
+	require.NoError(t, err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.SkillProgress]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	// 案例不计入掌握情况
+	assert.Equal(t, web.SkillProgress{
+		Basic:    practice.ProgressVO{Total: 2},
+		Advanced: practice.ProgressVO{Total: 1},
+	}, recorder.MustScan().Data)
+}
+
+func (s *HandlerTestSuite) TestRecycle() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := s.db.WithContext(ctx).Create(&dao.Skill{Id: 1, Name: "mysql", Desc: "mysql_desc"}).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Create(&dao.SkillLevel{Id: 1, Sid: 1, Level: "basic", Desc: "mysql_basic"}).Error
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Create(&dao.SkillRef{Sid: 1, Slid: 1, Rid: 1, Rtype: "question"}).Error
+	require.NoError(s.T(), err)
+
+	// 删除之后同步删除搜索，引用关系也不再算数
+	s.producer.EXPECT().Produce(gomock.Any(), event.SyncSearchEvent{
+		Biz:     "skill",
+		BizId:   1,
+		Deleted: true,
+	}).Return(nil)
+	s.doPost(s.T(), "/skill/delete", web.Sid{Sid: 1}, 200)
+	_, err = s.dao.Info(ctx, 1)
+	assert.Equal(s.T(), gorm.ErrRecordNotFound, err)
+	cnt, err := s.dao.CountRefs(ctx, "question", 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/skill/recycle/list", iox.NewJSONReader(web.Page{Limit: 10}))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	recorder := test.NewJSONResponseRecorder[web.SkillList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(s.T(), 200, recorder.Code)
+	list := recorder.MustScan().Data
+	assert.Equal(s.T(), int64(1), list.Total)
+	assert.Equal(s.T(), "mysql", list.Skills[0].Name)
+
+	// 不能创建和回收站里面的技能同名的技能
+	req, err = http.NewRequest(http.MethodPost,
+		"/skill/save", iox.NewJSONReader(web.SaveReq{Skill: web.Skill{Name: "mysql", Desc: "新的描述"}}))
+	require.NoError(s.T(), err)
+	req.Header.Set("content-type", "application/json")
+	saveRecorder := test.NewJSONResponseRecorder[int64]()
+	s.server.ServeHTTP(saveRecorder, req)
+	require.Equal(s.T(), 200, saveRecorder.Code)
+	assert.Equal(s.T(), 507002, saveRecorder.MustScan().Code)
+	var deleted dao.Skill
+	err = s.db.WithContext(ctx).Where("id = ?", 1).First(&deleted).Error
+	require.NoError(s.T(), err)
+	assert.True(s.T(), deleted.Dtime > 0)
+	assert.Equal(s.T(), "mysql_desc", deleted.Desc)
+
+	// 恢复之后重新同步到搜索
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil)
+	s.doPost(s.T(), "/skill/recycle/restore", web.Sid{Sid: 1}, 200)
+	cnt, err = s.dao.CountRefs(ctx, "question", 1)
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(1), cnt)
+
+	// 超过三十天的彻底删除，连同等级和引用关系
+	err = s.db.WithContext(ctx).Model(&dao.Skill{}).Where("id = ?", 1).
+		Update("dtime", time.Now().AddDate(0, 0, -31).UnixMilli()).Error
+	require.NoError(s.T(), err)
+	err = s.purgeJob.Run()
+	require.NoError(s.T(), err)
+	err = s.db.WithContext(ctx).Model(&dao.Skill{}).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+	err = s.db.WithContext(ctx).Model(&dao.SkillLevel{}).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+	err = s.db.WithContext(ctx).Model(&dao.SkillRef{}).Count(&cnt).Error
+	require.NoError(s.T(), err)
+	assert.Equal(s.T(), int64(0), cnt)
+}
+
+func (s *HandlerTestSuite) TestReindex() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	now := time.Now().UnixMilli()
+	err := s.db.WithContext(ctx).Create(&[]dao.Skill{
+		{Id: 1, Name: "mysql", Desc: "mysql_desc", Ctime: now, Utime: now},
+		{Id: 2, Name: "redis", Desc: "redis_desc", Ctime: now, Utime: now},
+		{Id: 3, Name: "kafka", Desc: "kafka_desc", Ctime: now, Utime: now, Dtime: now},
+	}).Error
+	require.NoError(s.T(), err)
+
+	var ids []int64
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, evt event.SyncSearchEvent) error {
+			ids = append(ids, evt.BizId)
+			return nil
+		}).Times(2)
+	err = s.reindexJob.Run(ejob.Context{Ctx: ctx})
+	require.NoError(s.T(), err)
+	// 回收站里面的技能不会同步到搜索
+	assert.Equal(s.T(), []int64{1, 2}, ids)
+}
+
+func (s *HandlerTestSuite) doPost(t *testing.T, path string, body any, wantCode int) {
+	req, err := http.NewRequest(http.MethodPost, path, iox.NewJSONReader(body))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test.NewJSONResponseRecorder[any]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, wantCode, recorder.Code)
+}
+
 func (s *HandlerTestSuite) assertSkill(wantSKill dao.Skill, actualSkill dao.Skill) {
 	t := s.T()
 	require.True(t, actualSkill.Id > 0)
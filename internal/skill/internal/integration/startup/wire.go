@@ -4,14 +4,15 @@ package startup
 
 import (
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/practice"
 	baguwen "github.com/ecodeclub/webook/internal/question"
 	"github.com/ecodeclub/webook/internal/skill"
-	"github.com/ecodeclub/webook/internal/skill/internal/web"
+	"github.com/ecodeclub/webook/internal/skill/internal/event"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 	"github.com/google/wire"
 )
 
-func InitHandler(bm *baguwen.Module, cm *cases.Module) (*web.Handler, error) {
-	wire.Build(testioc.BaseSet, skill.InitHandler)
-	return new(web.Handler), nil
+func InitModule(bm *baguwen.Module, cm *cases.Module, pm *practice.Module, p event.SyncEventProducer) (*skill.Module, error) {
+	wire.Build(testioc.BaseSet, skill.InitModuleWithProducer)
+	return new(skill.Module), nil
 }
@@ -8,20 +8,21 @@ package startup
 
 import (
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/practice"
 	baguwen "github.com/ecodeclub/webook/internal/question"
 	"github.com/ecodeclub/webook/internal/skill"
-	"github.com/ecodeclub/webook/internal/skill/internal/web"
+	"github.com/ecodeclub/webook/internal/skill/internal/event"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 )
 
 // Injectors from wire.go:
 
-func InitHandler(bm *baguwen.Module, cm *cases.Module) (*web.Handler, error) {
+func InitModule(bm *baguwen.Module, cm *cases.Module, pm *practice.Module, p event.SyncEventProducer) (*skill.Module, error) {
 	db := testioc.InitDB()
 	cache := testioc.InitCache()
-	handler, err := skill.InitHandler(db, cache, bm, cm)
+	module, err := skill.InitModuleWithProducer(db, cache, bm, cm, pm, p)
 	if err != nil {
 		return nil, err
 	}
-	return handler, nil
+	return module, nil
 }
@@ -0,0 +1,66 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package job
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/skill/internal/service"
+	"github.com/gotomicro/ego/core/elog"
+)
+
+// PurgeJob 彻底删除在回收站里面放了超过 days 天的技能
+type PurgeJob struct {
+	svc     service.SkillService
+	days    int
+	limit   int
+	timeout time.Duration
+	logger  *elog.Component
+}
+
+func NewPurgeJob(svc service.SkillService, days int, limit int, timeout time.Duration) *PurgeJob {
+	return &PurgeJob{
+		svc:     svc,
+		days:    days,
+		limit:   limit,
+		timeout: timeout,
+		logger:  elog.DefaultLogger,
+	}
+}
+
+func (j *PurgeJob) Name() string {
+	return "SkillPurgeJob"
+}
+
+func (j *PurgeJob) Run() error {
+	ctx, cancelFunc := context.WithTimeout(context.Background(), j.timeout)
+	defer cancelFunc()
+	before := time.Now().AddDate(0, 0, -j.days)
+	var total int64
+	for {
+		cnt, err := j.svc.Purge(ctx, before, j.limit)
+		if err != nil {
+			return fmt.Errorf("清理回收站中的技能失败: %w", err)
+		}
+		total += cnt
+		if cnt < int64(j.limit) {
+			break
+		}
+	}
+	j.logger.Info("清理回收站成功", elog.Int64("skills", total))
+	return nil
+}
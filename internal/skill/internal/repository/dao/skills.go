@@ -2,6 +2,7 @@ package dao
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/ego-component/egorm"
@@ -9,8 +10,11 @@ import (
 	"gorm.io/gorm/clause"
 )
 
+// ErrSkillInRecycleBin 回收站里面有同名的技能，需要先恢复或者等它被彻底删除
+var ErrSkillInRecycleBin = errors.New("回收站里面有同名的技能")
+
 type SkillDAO interface {
-	// Create 管理端接口
+	// Create 管理端接口，和回收站里面的技能同名的时候返回 ErrSkillInRecycleBin
 	Create(ctx context.Context, skill Skill, skillLevels []SkillLevel) (int64, error)
 	// Update 管理端接口
 	Update(ctx context.Context, skill Skill, skillLevels []SkillLevel) error
@@ -26,6 +30,21 @@ type SkillDAO interface {
 	// RefsByLevelIDs ids 为 SkillLevel 的 ID
 	RefsByLevelIDs(ctx context.Context, ids []int64) ([]SkillRef, error)
 	Count(ctx context.Context) (int64, error)
+	// IDs 按照 id 升序返回 id 大于 minID 的技能 id，回收站里面的技能不算，用于批量同步
+	IDs(ctx context.Context, minID int64, limit int) ([]int64, error)
+	// CountRefs 有多少个技能引用了 rid，回收站里面的技能不算
+	CountRefs(ctx context.Context, rtype string, rid int64) (int64, error)
+
+	// 回收站，删除的时候技能会先放到回收站里面，过一段时间之后才会彻底删除
+	// Delete 技能不存在或者已经被删除的时候返回 gorm.ErrRecordNotFound
+	Delete(ctx context.Context, id int64) error
+	ListDeleted(ctx context.Context, offset, limit int) ([]Skill, error)
+	CountDeleted(ctx context.Context) (int64, error)
+	// Restore 技能不在回收站里面的时候返回 gorm.ErrRecordNotFound
+	Restore(ctx context.Context, id int64) error
+	// Purge 彻底删除在 before 之前放进回收站的技能，连同等级和关联关系，
+	// 一次最多删除 limit 个，返回删除的个数
+	Purge(ctx context.Context, before int64, limit int) (int64, error)
 }
 
 type skillDAO struct {
@@ -52,7 +71,18 @@ func (s *skillDAO) Create(ctx context.Context, skill Skill, skillLevels []SkillL
 func (s *skillDAO) create(tx *gorm.DB, skill Skill, skillLevels []SkillLevel) (Skill, []SkillLevel, error) {
 	skill.Utime = time.Now().UnixMilli()
 	skill.Ctime = time.Now().UnixMilli()
-	err := tx.Clauses(clause.OnConflict{
+	// 同名的技能还在回收站里面的时候不能创建，只能从回收站恢复，
+	// 避免创建的时候悄悄把回收站里面的技能恢复出来
+	var cnt int64
+	err := tx.Model(&Skill{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("name = ? AND dtime > 0", skill.Name).Count(&cnt).Error
+	if err != nil {
+		return skill, nil, err
+	}
+	if cnt > 0 {
+		return skill, nil, ErrSkillInRecycleBin
+	}
+	err = tx.Clauses(clause.OnConflict{
 		DoUpdates: clause.AssignmentColumns([]string{
 			"labels", "desc", "utime",
 		}),
@@ -86,7 +116,7 @@ func (s *skillDAO) Update(ctx context.Context, skill Skill, skillLevels []SkillL
 }
 
 func (s *skillDAO) update(tx *gorm.DB, skill Skill, skillLevels []SkillLevel) (Skill, []SkillLevel, error) {
-	err := tx.Model(&skill).Where("id = ?", skill.Id).Updates(map[string]any{
+	err := tx.Model(&skill).Where("id = ? AND dtime = 0", skill.Id).Updates(map[string]any{
 		"labels": skill.Labels,
 		"name":   skill.Name,
 		"desc":   skill.Desc,
@@ -125,14 +155,24 @@ func (s *skillDAO) SaveRefs(ctx context.Context, refs []SkillRef) error {
 func (s *skillDAO) List(ctx context.Context, offset, limit int) ([]Skill, error) {
 	var skills []Skill
 	err := s.db.WithContext(ctx).Model(&Skill{}).
+		Where("dtime = 0").
 		Order("id desc").
 		Offset(offset).Limit(limit).Find(&skills).Error
 	return skills, err
 }
 
+func (s *skillDAO) IDs(ctx context.Context, minID int64, limit int) ([]int64, error) {
+	var res []int64
+	err := s.db.WithContext(ctx).Model(&Skill{}).
+		Where("id > ? AND dtime = 0", minID).
+		Order("id ASC").Limit(limit).
+		Pluck("id", &res).Error
+	return res, err
+}
+
 func (s *skillDAO) Info(ctx context.Context, id int64) (Skill, error) {
 	var skill Skill
-	err := s.db.WithContext(ctx).Model(&Skill{}).Where("id = ? ", id).First(&skill).Error
+	err := s.db.WithContext(ctx).Model(&Skill{}).Where("id = ? AND dtime = 0", id).First(&skill).Error
 	return skill, err
 }
 
@@ -157,10 +197,91 @@ func (s *skillDAO) Refs(ctx context.Context, id int64) ([]SkillRef, error) {
 
 func (s *skillDAO) Count(ctx context.Context) (int64, error) {
 	var count int64
-	err := s.db.WithContext(ctx).Model(&Skill{}).Count(&count).Error
+	err := s.db.WithContext(ctx).Model(&Skill{}).Where("dtime = 0").Count(&count).Error
 	return count, err
 }
 
+func (s *skillDAO) CountRefs(ctx context.Context, rtype string, rid int64) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&SkillRef{}).
+		Joins("JOIN skill ON skill.id = skill_refs.sid").
+		Where("skill_refs.rtype = ? AND skill_refs.rid = ? AND skill.dtime = 0", rtype, rid).
+		Count(&count).Error
+	return count, err
+}
+
+func (s *skillDAO) Delete(ctx context.Context, id int64) error {
+	now := time.Now().UnixMilli()
+	res := s.db.WithContext(ctx).Model(&Skill{}).
+		Where("id = ? AND dtime = 0", id).
+		Updates(map[string]any{
+			"dtime": now,
+			"utime": now,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *skillDAO) ListDeleted(ctx context.Context, offset, limit int) ([]Skill, error) {
+	var skills []Skill
+	err := s.db.WithContext(ctx).Model(&Skill{}).
+		Where("dtime > 0").
+		Order("dtime desc, id desc").
+		Offset(offset).Limit(limit).Find(&skills).Error
+	return skills, err
+}
+
+func (s *skillDAO) CountDeleted(ctx context.Context) (int64, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&Skill{}).Where("dtime > 0").Count(&count).Error
+	return count, err
+}
+
+func (s *skillDAO) Restore(ctx context.Context, id int64) error {
+	res := s.db.WithContext(ctx).Model(&Skill{}).
+		Where("id = ? AND dtime > 0", id).
+		Updates(map[string]any{
+			"dtime": 0,
+			"utime": time.Now().UnixMilli(),
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (s *skillDAO) Purge(ctx context.Context, before int64, limit int) (int64, error) {
+	var ids []int64
+	err := s.db.WithContext(ctx).Model(&Skill{}).
+		Where("dtime > 0 AND dtime < ?", before).
+		Order("id asc").Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("sid IN ?", ids).Delete(&SkillRef{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("sid IN ?", ids).Delete(&SkillLevel{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id IN ?", ids).Delete(&Skill{}).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(ids)), nil
+}
+
 func NewSkillDAO(db *egorm.Component) SkillDAO {
 	return &skillDAO{
 		db: db,
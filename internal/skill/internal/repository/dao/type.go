@@ -11,6 +11,8 @@ type Skill struct {
 	Desc  string
 	Ctime int64
 	Utime int64 `gorm:"index"`
+	// 删除时间，0 代表没有删除，删除之后会先放在回收站里面
+	Dtime int64 `gorm:"index"`
 }
 
 func (Skill) TableName() string {
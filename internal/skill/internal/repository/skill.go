@@ -16,6 +16,8 @@ import (
 	"golang.org/x/sync/errgroup"
 )
 
+var ErrSkillInRecycleBin = dao.ErrSkillInRecycleBin
+
 type SkillRepo interface {
 	// Save 管理端接口
 	// 和 Update 返回值为  skill 的 id
@@ -26,7 +28,17 @@ type SkillRepo interface {
 	// Info 详情
 	Info(ctx context.Context, id int64) (domain.Skill, error)
 	Count(ctx context.Context) (int64, error)
+	IDs(ctx context.Context, minID int64, limit int) ([]int64, error)
 	RefsByLevelIDs(ctx context.Context, ids []int64) ([]domain.SkillLevel, error)
+	// Referenced rtype 是 question 或者 case，回收站里面的技能不算
+	Referenced(ctx context.Context, rtype string, rid int64) (bool, error)
+
+	// 回收站
+	Delete(ctx context.Context, id int64) error
+	ListDeleted(ctx context.Context, offset, limit int) ([]domain.Skill, error)
+	TotalDeleted(ctx context.Context) (int64, error)
+	Restore(ctx context.Context, id int64) error
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 type skillRepo struct {
 	skillDao dao.SkillDAO
@@ -35,6 +47,10 @@ type skillRepo struct {
 	logger *elog.Component
 }
 
+func (s *skillRepo) IDs(ctx context.Context, minID int64, limit int) ([]int64, error) {
+	return s.skillDao.IDs(ctx, minID, limit)
+}
+
 func (s *skillRepo) RefsByLevelIDs(ctx context.Context, ids []int64) ([]domain.SkillLevel, error) {
 	refs, err := s.skillDao.RefsByLevelIDs(ctx, ids)
 	if err != nil {
@@ -166,6 +182,34 @@ func (s *skillRepo) Count(ctx context.Context) (int64, error) {
 	return s.skillDao.Count(ctx)
 }
 
+func (s *skillRepo) Referenced(ctx context.Context, rtype string, rid int64) (bool, error) {
+	cnt, err := s.skillDao.CountRefs(ctx, rtype, rid)
+	return cnt > 0, err
+}
+
+func (s *skillRepo) Delete(ctx context.Context, id int64) error {
+	return s.skillDao.Delete(ctx, id)
+}
+
+func (s *skillRepo) ListDeleted(ctx context.Context, offset, limit int) ([]domain.Skill, error) {
+	skills, err := s.skillDao.ListDeleted(ctx, offset, limit)
+	return slice.Map(skills, func(idx int, src dao.Skill) domain.Skill {
+		return s.skillToListDomain(src)
+	}), err
+}
+
+func (s *skillRepo) TotalDeleted(ctx context.Context) (int64, error) {
+	return s.skillDao.CountDeleted(ctx)
+}
+
+func (s *skillRepo) Restore(ctx context.Context, id int64) error {
+	return s.skillDao.Restore(ctx, id)
+}
+
+func (s *skillRepo) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return s.skillDao.Purge(ctx, before.UnixMilli(), limit)
+}
+
 func (s *skillRepo) skillToListDomain(skill dao.Skill) domain.Skill {
 	return domain.Skill{
 		ID:     skill.Id,
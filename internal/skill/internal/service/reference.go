@@ -0,0 +1,41 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/ecodeclub/webook/internal/skill/internal/repository"
+)
+
+// ReferenceService 技能对题目或者案例的引用，
+// 提供给题目和案例模块，被技能引用的内容不能删除
+type ReferenceService struct {
+	repo repository.SkillRepo
+	// 引用的类型，question 或者 case
+	rtype string
+}
+
+func NewReferenceService(repo repository.SkillRepo, rtype string) *ReferenceService {
+	return &ReferenceService{
+		repo:  repo,
+		rtype: rtype,
+	}
+}
+
+// Referenced 是否还有技能引用了 rid，回收站里面的技能不算
+func (r *ReferenceService) Referenced(ctx context.Context, rid int64) (bool, error) {
+	return r.repo.Referenced(ctx, r.rtype, rid)
+}
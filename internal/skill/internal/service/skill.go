@@ -2,11 +2,17 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/ecodeclub/webook/internal/skill/internal/domain"
+	"github.com/ecodeclub/webook/internal/skill/internal/event"
 	"github.com/ecodeclub/webook/internal/skill/internal/repository"
+	"github.com/gotomicro/ego/core/elog"
 )
 
+//go:generate mockgen -source=./skill.go -destination=../../mocks/skill.mock.go -package=skillmocks -typed SkillService
 type SkillService interface {
 	// Save 保存基本信息
 	Save(ctx context.Context, skill domain.Skill) (int64, error)
@@ -15,10 +21,29 @@ type SkillService interface {
 	List(ctx context.Context, offset, limit int) ([]domain.Skill, int64, error)
 	Info(ctx context.Context, id int64) (domain.Skill, error)
 	RefsByLevelIDs(ctx context.Context, ids []int64) ([]domain.SkillLevel, error)
+
+	// Delete 删除技能，技能会先放进回收站
+	Delete(ctx context.Context, id int64) error
+	// ListDeleted 回收站里面的技能，按照删除时间倒序
+	ListDeleted(ctx context.Context, offset, limit int) ([]domain.Skill, int64, error)
+	// Restore 从回收站恢复
+	Restore(ctx context.Context, id int64) error
+	// Purge 彻底删除在 before 之前放进回收站的技能，一次最多 limit 个，返回删除的个数
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
+	// Reindex 将 id 大于 minID 的技能重新同步到搜索，一次最多 limit 个
+	// 返回最后一个技能的 id，没有更多技能的时候返回 0
+	Reindex(ctx context.Context, minID int64, limit int) (int64, error)
 }
 
+// 在搜索等模块里面代表技能
+const biz = "skill"
+
+var ErrSkillInRecycleBin = repository.ErrSkillInRecycleBin
+
 type skillService struct {
-	repo repository.SkillRepo
+	repo     repository.SkillRepo
+	producer event.SyncEventProducer
+	logger   *elog.Component
 }
 
 func (s *skillService) RefsByLevelIDs(ctx context.Context, ids []int64) ([]domain.SkillLevel, error) {
@@ -30,7 +55,90 @@ func (s *skillService) SaveRefs(ctx context.Context, skill domain.Skill) error {
 }
 
 func (s *skillService) Save(ctx context.Context, skill domain.Skill) (int64, error) {
-	return s.repo.Save(ctx, skill)
+	id, err := s.repo.Save(ctx, skill)
+	if err != nil {
+		return 0, err
+	}
+	// 技能没有发布的概念，保存之后就同步到搜索，失败了也不影响保存
+	skill.ID = id
+	s.produceSyncEvent(ctx, s.toSyncEvent(skill))
+	return id, nil
+}
+
+func (s *skillService) Delete(ctx context.Context, id int64) error {
+	err := s.repo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+	s.produceSyncEvent(ctx, event.SyncSearchEvent{Biz: biz, BizId: id, Deleted: true})
+	return nil
+}
+
+func (s *skillService) ListDeleted(ctx context.Context, offset, limit int) ([]domain.Skill, int64, error) {
+	count, err := s.repo.TotalDeleted(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	skills, err := s.repo.ListDeleted(ctx, offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return skills, count, nil
+}
+
+func (s *skillService) Restore(ctx context.Context, id int64) error {
+	err := s.repo.Restore(ctx, id)
+	if err != nil {
+		return err
+	}
+	// 删除的时候从搜索里面移除了，这里要重新同步回去
+	skill, err := s.repo.Info(ctx, id)
+	if err != nil {
+		s.logger.Error("恢复之后查询技能失败", elog.FieldErr(err), elog.Int64("id", id))
+		return nil
+	}
+	s.produceSyncEvent(ctx, s.toSyncEvent(skill))
+	return nil
+}
+
+func (s *skillService) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return s.repo.Purge(ctx, before, limit)
+}
+
+func (s *skillService) Reindex(ctx context.Context, minID int64, limit int) (int64, error) {
+	ids, err := s.repo.IDs(ctx, minID, limit)
+	if err != nil || len(ids) == 0 {
+		return 0, err
+	}
+	for _, id := range ids {
+		skill, err := s.repo.Info(ctx, id)
+		if err != nil {
+			return 0, fmt.Errorf("查询技能失败 id %d: %w", id, err)
+		}
+		err = s.producer.Produce(ctx, s.toSyncEvent(skill))
+		if err != nil {
+			return 0, fmt.Errorf("发送搜索同步消息失败 id %d: %w", id, err)
+		}
+	}
+	return ids[len(ids)-1], nil
+}
+
+func (s *skillService) toSyncEvent(skill domain.Skill) event.SyncSearchEvent {
+	return event.SyncSearchEvent{
+		Biz:   biz,
+		BizId: skill.ID,
+		Title: skill.Name,
+		Content: strings.Join([]string{skill.Desc, skill.Basic.Desc,
+			skill.Intermediate.Desc, skill.Advanced.Desc}, "\n"),
+		Labels: skill.Labels,
+	}
+}
+
+func (s *skillService) produceSyncEvent(ctx context.Context, evt event.SyncSearchEvent) {
+	err := s.producer.Produce(ctx, evt)
+	if err != nil {
+		s.logger.Error("发送搜索同步消息失败", elog.FieldErr(err), elog.Int64("id", evt.BizId))
+	}
 }
 
 func (s *skillService) List(ctx context.Context, offset, limit int) ([]domain.Skill, int64, error) {
@@ -49,8 +157,10 @@ func (s *skillService) Info(ctx context.Context, id int64) (domain.Skill, error)
 	return s.repo.Info(ctx, id)
 }
 
-func NewSkillService(repo repository.SkillRepo) SkillService {
+func NewSkillService(repo repository.SkillRepo, producer event.SyncEventProducer) SkillService {
 	return &skillService{
-		repo: repo,
+		repo:     repo,
+		producer: producer,
+		logger:   elog.DefaultLogger,
 	}
 }
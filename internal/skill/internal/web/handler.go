@@ -1,6 +1,7 @@
 package web
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/ginx"
 	"github.com/ecodeclub/ginx/session"
+	"github.com/ecodeclub/webook/internal/practice"
 	"github.com/ecodeclub/webook/internal/skill/internal/domain"
 	"github.com/ecodeclub/webook/internal/skill/internal/service"
 	"github.com/gin-gonic/gin"
@@ -18,18 +20,21 @@ import (
 )
 
 type Handler struct {
-	svc     service.SkillService
-	queSvc  baguwen.Service
-	caseSvc cases.Service
-	logger  *elog.Component
+	svc         service.SkillService
+	queSvc      baguwen.Service
+	caseSvc     cases.Service
+	practiceSvc practice.Service
+	logger      *elog.Component
 }
 
-func NewHandler(svc service.SkillService, queSvc baguwen.Service, caseSvc cases.Service) *Handler {
+func NewHandler(svc service.SkillService, queSvc baguwen.Service,
+	caseSvc cases.Service, practiceSvc practice.Service) *Handler {
 	return &Handler{
-		svc:     svc,
-		logger:  elog.DefaultLogger,
-		queSvc:  queSvc,
-		caseSvc: caseSvc,
+		svc:         svc,
+		logger:      elog.DefaultLogger,
+		queSvc:      queSvc,
+		caseSvc:     caseSvc,
+		practiceSvc: practiceSvc,
 	}
 }
 
@@ -40,11 +45,19 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	server.POST("/skill/detail-refs", ginx.S(h.Permission), ginx.B[Sid](h.DetailRefs))
 	server.POST("/skill/save-refs", ginx.S(h.Permission), ginx.B(h.SaveRefs))
 	server.POST("/skill/level-refs", ginx.S(h.Permission), ginx.B(h.RefsByLevelIDs))
+
+	server.POST("/skill/delete", ginx.S(h.Permission), ginx.B[Sid](h.Delete))
+	server.POST("/skill/recycle/list", ginx.S(h.Permission), ginx.B[Page](h.ListDeleted))
+	server.POST("/skill/recycle/restore", ginx.S(h.Permission), ginx.B[Sid](h.Restore))
 }
 
 func (h *Handler) PublicRoutes(server *gin.Engine) {
 }
 
+func (h *Handler) MemberRoutes(server *gin.Engine) {
+	server.POST("/skill/progress", ginx.BS[Sid](h.Progress))
+}
+
 func (h *Handler) Permission(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
 	if sess.Claims().Get("creator").StringOrDefault("") != "true" {
 		ctx.AbortWithStatus(http.StatusInternalServerError)
@@ -56,7 +69,10 @@ func (h *Handler) Permission(ctx *ginx.Context, sess session.Session) (ginx.Resu
 func (h *Handler) Save(ctx *ginx.Context, req SaveReq) (ginx.Result, error) {
 	skill := req.Skill.toDomain()
 	id, err := h.svc.Save(ctx, skill)
-	if err != nil {
+	switch {
+	case errors.Is(err, service.ErrSkillInRecycleBin):
+		return skillInRecycleBinResult, nil
+	case err != nil:
 		return systemErrorResult, err
 	}
 	return ginx.Result{
@@ -85,6 +101,41 @@ func (h *Handler) Detail(ctx *ginx.Context, req Sid) (ginx.Result, error) {
 	}, nil
 }
 
+// Progress 当前用户在技能每个等级的题目上的掌握情况
+func (h *Handler) Progress(ctx *ginx.Context, req Sid, sess session.Session) (ginx.Result, error) {
+	skill, err := h.svc.Info(ctx, req.Sid)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	uid := sess.Claims().Uid
+	var (
+		eg  errgroup.Group
+		res SkillProgress
+	)
+	levels := []struct {
+		qids []int64
+		dst  *practice.ProgressVO
+	}{
+		{qids: skill.Basic.Questions, dst: &res.Basic},
+		{qids: skill.Intermediate.Questions, dst: &res.Intermediate},
+		{qids: skill.Advanced.Questions, dst: &res.Advanced},
+	}
+	for _, l := range levels {
+		l := l
+		eg.Go(func() error {
+			p, err1 := h.practiceSvc.Progress(ctx, uid, l.qids)
+			*l.dst = practice.NewProgressVO(p)
+			return err1
+		})
+	}
+	if err = eg.Wait(); err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: res,
+	}, nil
+}
+
 func (h *Handler) List(ctx *ginx.Context, page Page) (ginx.Result, error) {
 	skills, count, err := h.svc.List(ctx, page.Offset, page.Limit)
 	if err != nil {
@@ -97,6 +148,32 @@ func (h *Handler) List(ctx *ginx.Context, page Page) (ginx.Result, error) {
 
 }
 
+func (h *Handler) Delete(ctx *ginx.Context, req Sid) (ginx.Result, error) {
+	err := h.svc.Delete(ctx, req.Sid)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{}, nil
+}
+
+func (h *Handler) ListDeleted(ctx *ginx.Context, page Page) (ginx.Result, error) {
+	skills, count, err := h.svc.ListDeleted(ctx, page.Offset, page.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: h.toSkillList(skills, count),
+	}, nil
+}
+
+func (h *Handler) Restore(ctx *ginx.Context, req Sid) (ginx.Result, error) {
+	err := h.svc.Restore(ctx, req.Sid)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{}, nil
+}
+
 func (h *Handler) toSkillList(data []domain.Skill, cnt int64) SkillList {
 	return SkillList{
 		Total: cnt,
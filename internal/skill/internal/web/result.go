@@ -24,4 +24,8 @@ var (
 		Code: errs.SystemError.Code,
 		Msg:  errs.SystemError.Msg,
 	}
+	skillInRecycleBinResult = ginx.Result{
+		Code: errs.SkillInRecycleBin.Code,
+		Msg:  errs.SkillInRecycleBin.Msg,
+	}
 )
@@ -5,6 +5,7 @@ import (
 
 	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/practice"
 	baguwen "github.com/ecodeclub/webook/internal/question"
 
 	"github.com/ecodeclub/webook/internal/skill/internal/domain"
@@ -62,6 +63,13 @@ func (s *SkillLevel) setQuestions(qm map[int64]baguwen.Question) {
 type Sid struct {
 	Sid int64 `json:"sid"`
 }
+
+// SkillProgress 每个等级的掌握情况
+type SkillProgress struct {
+	Basic        practice.ProgressVO `json:"basic"`
+	Intermediate practice.ProgressVO `json:"intermediate"`
+	Advanced     practice.ProgressVO `json:"advanced"`
+}
 type Page struct {
 	Offset int `json:"offset,omitempty"`
 	Limit  int `json:"limit,omitempty"`
@@ -0,0 +1,430 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./skill.go
+//
+// Generated by this command:
+//
+//	mockgen -source=./skill.go -destination=../../mocks/skill.mock.go -package=skillmocks -typed SkillService
+//
+// Package skillmocks is a generated GoMock package.
+package skillmocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	domain "github.com/ecodeclub/webook/internal/skill/internal/domain"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSkillService is a mock of SkillService interface.
+type MockSkillService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSkillServiceMockRecorder
+}
+
+// MockSkillServiceMockRecorder is the mock recorder for MockSkillService.
+type MockSkillServiceMockRecorder struct {
+	mock *MockSkillService
+}
+
+// NewMockSkillService creates a new mock instance.
+func NewMockSkillService(ctrl *gomock.Controller) *MockSkillService {
+	mock := &MockSkillService{ctrl: ctrl}
+	mock.recorder = &MockSkillServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSkillService) EXPECT() *MockSkillServiceMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockSkillService) Delete(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockSkillServiceMockRecorder) Delete(ctx, id any) *SkillServiceDeleteCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockSkillService)(nil).Delete), ctx, id)
+	return &SkillServiceDeleteCall{Call: call}
+}
+
+// SkillServiceDeleteCall wrap *gomock.Call
+type SkillServiceDeleteCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceDeleteCall) Return(arg0 error) *SkillServiceDeleteCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceDeleteCall) Do(f func(context.Context, int64) error) *SkillServiceDeleteCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceDeleteCall) DoAndReturn(f func(context.Context, int64) error) *SkillServiceDeleteCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Info mocks base method.
+func (m *MockSkillService) Info(ctx context.Context, id int64) (domain.Skill, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Info", ctx, id)
+	ret0, _ := ret[0].(domain.Skill)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Info indicates an expected call of Info.
+func (mr *MockSkillServiceMockRecorder) Info(ctx, id any) *SkillServiceInfoCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Info", reflect.TypeOf((*MockSkillService)(nil).Info), ctx, id)
+	return &SkillServiceInfoCall{Call: call}
+}
+
+// SkillServiceInfoCall wrap *gomock.Call
+type SkillServiceInfoCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceInfoCall) Return(arg0 domain.Skill, arg1 error) *SkillServiceInfoCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceInfoCall) Do(f func(context.Context, int64) (domain.Skill, error)) *SkillServiceInfoCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceInfoCall) DoAndReturn(f func(context.Context, int64) (domain.Skill, error)) *SkillServiceInfoCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// List mocks base method.
+func (m *MockSkillService) List(ctx context.Context, offset, limit int) ([]domain.Skill, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Skill)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// List indicates an expected call of List.
+func (mr *MockSkillServiceMockRecorder) List(ctx, offset, limit any) *SkillServiceListCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockSkillService)(nil).List), ctx, offset, limit)
+	return &SkillServiceListCall{Call: call}
+}
+
+// SkillServiceListCall wrap *gomock.Call
+type SkillServiceListCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceListCall) Return(arg0 []domain.Skill, arg1 int64, arg2 error) *SkillServiceListCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceListCall) Do(f func(context.Context, int, int) ([]domain.Skill, int64, error)) *SkillServiceListCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceListCall) DoAndReturn(f func(context.Context, int, int) ([]domain.Skill, int64, error)) *SkillServiceListCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// ListDeleted mocks base method.
+func (m *MockSkillService) ListDeleted(ctx context.Context, offset, limit int) ([]domain.Skill, int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeleted", ctx, offset, limit)
+	ret0, _ := ret[0].([]domain.Skill)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// ListDeleted indicates an expected call of ListDeleted.
+func (mr *MockSkillServiceMockRecorder) ListDeleted(ctx, offset, limit any) *SkillServiceListDeletedCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeleted", reflect.TypeOf((*MockSkillService)(nil).ListDeleted), ctx, offset, limit)
+	return &SkillServiceListDeletedCall{Call: call}
+}
+
+// SkillServiceListDeletedCall wrap *gomock.Call
+type SkillServiceListDeletedCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceListDeletedCall) Return(arg0 []domain.Skill, arg1 int64, arg2 error) *SkillServiceListDeletedCall {
+	c.Call = c.Call.Return(arg0, arg1, arg2)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceListDeletedCall) Do(f func(context.Context, int, int) ([]domain.Skill, int64, error)) *SkillServiceListDeletedCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceListDeletedCall) DoAndReturn(f func(context.Context, int, int) ([]domain.Skill, int64, error)) *SkillServiceListDeletedCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Purge mocks base method.
+func (m *MockSkillService) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockSkillServiceMockRecorder) Purge(ctx, before, limit any) *SkillServicePurgeCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockSkillService)(nil).Purge), ctx, before, limit)
+	return &SkillServicePurgeCall{Call: call}
+}
+
+// SkillServicePurgeCall wrap *gomock.Call
+type SkillServicePurgeCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServicePurgeCall) Return(arg0 int64, arg1 error) *SkillServicePurgeCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServicePurgeCall) Do(f func(context.Context, time.Time, int) (int64, error)) *SkillServicePurgeCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServicePurgeCall) DoAndReturn(f func(context.Context, time.Time, int) (int64, error)) *SkillServicePurgeCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// RefsByLevelIDs mocks base method.
+func (m *MockSkillService) RefsByLevelIDs(ctx context.Context, ids []int64) ([]domain.SkillLevel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RefsByLevelIDs", ctx, ids)
+	ret0, _ := ret[0].([]domain.SkillLevel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RefsByLevelIDs indicates an expected call of RefsByLevelIDs.
+func (mr *MockSkillServiceMockRecorder) RefsByLevelIDs(ctx, ids any) *SkillServiceRefsByLevelIDsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefsByLevelIDs", reflect.TypeOf((*MockSkillService)(nil).RefsByLevelIDs), ctx, ids)
+	return &SkillServiceRefsByLevelIDsCall{Call: call}
+}
+
+// SkillServiceRefsByLevelIDsCall wrap *gomock.Call
+type SkillServiceRefsByLevelIDsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceRefsByLevelIDsCall) Return(arg0 []domain.SkillLevel, arg1 error) *SkillServiceRefsByLevelIDsCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceRefsByLevelIDsCall) Do(f func(context.Context, []int64) ([]domain.SkillLevel, error)) *SkillServiceRefsByLevelIDsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceRefsByLevelIDsCall) DoAndReturn(f func(context.Context, []int64) ([]domain.SkillLevel, error)) *SkillServiceRefsByLevelIDsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Reindex mocks base method.
+func (m *MockSkillService) Reindex(ctx context.Context, minID int64, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reindex", ctx, minID, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Reindex indicates an expected call of Reindex.
+func (mr *MockSkillServiceMockRecorder) Reindex(ctx, minID, limit any) *SkillServiceReindexCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reindex", reflect.TypeOf((*MockSkillService)(nil).Reindex), ctx, minID, limit)
+	return &SkillServiceReindexCall{Call: call}
+}
+
+// SkillServiceReindexCall wrap *gomock.Call
+type SkillServiceReindexCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceReindexCall) Return(arg0 int64, arg1 error) *SkillServiceReindexCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceReindexCall) Do(f func(context.Context, int64, int) (int64, error)) *SkillServiceReindexCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceReindexCall) DoAndReturn(f func(context.Context, int64, int) (int64, error)) *SkillServiceReindexCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Restore mocks base method.
+func (m *MockSkillService) Restore(ctx context.Context, id int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, id)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockSkillServiceMockRecorder) Restore(ctx, id any) *SkillServiceRestoreCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockSkillService)(nil).Restore), ctx, id)
+	return &SkillServiceRestoreCall{Call: call}
+}
+
+// SkillServiceRestoreCall wrap *gomock.Call
+type SkillServiceRestoreCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceRestoreCall) Return(arg0 error) *SkillServiceRestoreCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceRestoreCall) Do(f func(context.Context, int64) error) *SkillServiceRestoreCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceRestoreCall) DoAndReturn(f func(context.Context, int64) error) *SkillServiceRestoreCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// Save mocks base method.
+func (m *MockSkillService) Save(ctx context.Context, skill domain.Skill) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, skill)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockSkillServiceMockRecorder) Save(ctx, skill any) *SkillServiceSaveCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockSkillService)(nil).Save), ctx, skill)
+	return &SkillServiceSaveCall{Call: call}
+}
+
+// SkillServiceSaveCall wrap *gomock.Call
+type SkillServiceSaveCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceSaveCall) Return(arg0 int64, arg1 error) *SkillServiceSaveCall {
+	c.Call = c.Call.Return(arg0, arg1)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceSaveCall) Do(f func(context.Context, domain.Skill) (int64, error)) *SkillServiceSaveCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceSaveCall) DoAndReturn(f func(context.Context, domain.Skill) (int64, error)) *SkillServiceSaveCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
+
+// SaveRefs mocks base method.
+func (m *MockSkillService) SaveRefs(ctx context.Context, skill domain.Skill) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRefs", ctx, skill)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRefs indicates an expected call of SaveRefs.
+func (mr *MockSkillServiceMockRecorder) SaveRefs(ctx, skill any) *SkillServiceSaveRefsCall {
+	mr.mock.ctrl.T.Helper()
+	call := mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRefs", reflect.TypeOf((*MockSkillService)(nil).SaveRefs), ctx, skill)
+	return &SkillServiceSaveRefsCall{Call: call}
+}
+
+// SkillServiceSaveRefsCall wrap *gomock.Call
+type SkillServiceSaveRefsCall struct {
+	*gomock.Call
+}
+
+// Return rewrite *gomock.Call.Return
+func (c *SkillServiceSaveRefsCall) Return(arg0 error) *SkillServiceSaveRefsCall {
+	c.Call = c.Call.Return(arg0)
+	return c
+}
+
+// Do rewrite *gomock.Call.Do
+func (c *SkillServiceSaveRefsCall) Do(f func(context.Context, domain.Skill) error) *SkillServiceSaveRefsCall {
+	c.Call = c.Call.Do(f)
+	return c
+}
+
+// DoAndReturn rewrite *gomock.Call.DoAndReturn
+func (c *SkillServiceSaveRefsCall) DoAndReturn(f func(context.Context, domain.Skill) error) *SkillServiceSaveRefsCall {
+	c.Call = c.Call.DoAndReturn(f)
+	return c
+}
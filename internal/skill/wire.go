@@ -18,10 +18,15 @@ package skill
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/practice"
 	baguwen "github.com/ecodeclub/webook/internal/question"
 
+	"github.com/ecodeclub/webook/internal/skill/internal/domain"
+	"github.com/ecodeclub/webook/internal/skill/internal/event"
+	"github.com/ecodeclub/webook/internal/skill/internal/job"
 	"github.com/ecodeclub/webook/internal/skill/internal/repository"
 	"github.com/ecodeclub/webook/internal/skill/internal/repository/cache"
 	dao2 "github.com/ecodeclub/webook/internal/skill/internal/repository/dao"
@@ -29,27 +34,70 @@ import (
 	"github.com/ecodeclub/webook/internal/skill/internal/web"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/mq-api"
 
 	"github.com/ego-component/egorm"
 	"github.com/google/wire"
 	"gorm.io/gorm"
 )
 
-func InitHandler(
+func InitModule(
 	db *egorm.Component,
 	ec ecache.Cache,
 	queModule *baguwen.Module,
-	caseModule *cases.Module) (*Handler, error) {
+	caseModule *cases.Module,
+	practiceModule *practice.Module,
+	q mq.MQ) (*Module, error) {
+	wire.Build(initSyncEventProducer, InitModuleWithProducer)
+	return new(Module), nil
+}
+
+// InitModuleWithProducer 测试的时候可以传入 mock 的 producer
+func InitModuleWithProducer(
+	db *egorm.Component,
+	ec ecache.Cache,
+	queModule *baguwen.Module,
+	caseModule *cases.Module,
+	practiceModule *practice.Module,
+	p event.SyncEventProducer) (*Module, error) {
 	wire.Build(
 		InitSkillDAO,
 		wire.FieldsOf(new(*baguwen.Module), "Svc"),
 		wire.FieldsOf(new(*cases.Module), "Svc"),
+		wire.FieldsOf(new(*practice.Module), "Svc"),
 		cache.NewSkillCache,
 		repository.NewSkillRepo,
 		service.NewSkillService,
 		web.NewHandler,
+		initPurgeJob,
+		initReindexJob,
+		wire.Struct(new(Module), "*"),
 	)
-	return new(Handler), nil
+	return new(Module), nil
+}
+
+// InitQuestionRefService 题目模块构造的时候需要，这个时候还没有技能模块，所以不能用 InitModule
+func InitQuestionRefService(db *egorm.Component, ec ecache.Cache) baguwen.ReferenceService {
+	return initRefService(db, ec, dao2.RTypeQuestion)
+}
+
+// InitCaseRefService 和 InitQuestionRefService 一样，提供给案例模块
+func InitCaseRefService(db *egorm.Component, ec ecache.Cache) cases.ReferenceService {
+	return initRefService(db, ec, dao2.RTypeCase)
+}
+
+func initRefService(db *egorm.Component, ec ecache.Cache, rtype string) *service.ReferenceService {
+	repo := repository.NewSkillRepo(InitSkillDAO(db), cache.NewSkillCache(ec))
+	return service.NewReferenceService(repo, rtype)
+}
+
+// initPurgeJob 回收站里面的技能保留 30 天
+func initPurgeJob(svc service.SkillService) *PurgeJob {
+	return job.NewPurgeJob(svc, 30, 100, time.Hour)
+}
+
+func initReindexJob(svc service.SkillService) *ReindexJob {
+	return job.NewReindexJob(svc, 100)
 }
 
 var daoOnce = sync.Once{}
@@ -63,9 +111,22 @@ func InitTableOnce(db *gorm.DB) {
 	})
 }
 
+func initSyncEventProducer(q mq.MQ) event.SyncEventProducer {
+	producer, err := event.NewSyncEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
+}
+
 func InitSkillDAO(db *egorm.Component) dao2.SkillDAO {
 	InitTableOnce(db)
 	return dao2.NewSkillDAO(db)
 }
 
 type Handler = web.Handler
+type PurgeJob = job.PurgeJob
+type ReindexJob = job.ReindexJob
+type Service = service.SkillService
+type Skill = domain.Skill
+type SkillLevel = domain.SkillLevel
@@ -8,10 +8,16 @@ package skill
 
 import (
 	"sync"
+	"time"
 
 	"github.com/ecodeclub/ecache"
+	"github.com/ecodeclub/mq-api"
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/practice"
 	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/skill/internal/domain"
+	"github.com/ecodeclub/webook/internal/skill/internal/event"
+	"github.com/ecodeclub/webook/internal/skill/internal/job"
 	"github.com/ecodeclub/webook/internal/skill/internal/repository"
 	"github.com/ecodeclub/webook/internal/skill/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/skill/internal/repository/dao"
@@ -23,19 +29,62 @@ import (
 
 // Injectors from wire.go:
 
-func InitHandler(db *gorm.DB, ec ecache.Cache, queModule *baguwen.Module, caseModule *cases.Module) (*web.Handler, error) {
+func InitModule(db *gorm.DB, ec ecache.Cache, queModule *baguwen.Module, caseModule *cases.Module, practiceModule *practice.Module, q mq.MQ) (*Module, error) {
+	syncEventProducer := initSyncEventProducer(q)
+	module, err := InitModuleWithProducer(db, ec, queModule, caseModule, practiceModule, syncEventProducer)
+	if err != nil {
+		return nil, err
+	}
+	return module, nil
+}
+
+// InitModuleWithProducer 测试的时候可以传入 mock 的 producer
+func InitModuleWithProducer(db *gorm.DB, ec ecache.Cache, queModule *baguwen.Module, caseModule *cases.Module, practiceModule *practice.Module, p event.SyncEventProducer) (*Module, error) {
 	skillDAO := InitSkillDAO(db)
 	skillCache := cache.NewSkillCache(ec)
 	skillRepo := repository.NewSkillRepo(skillDAO, skillCache)
-	skillService := service.NewSkillService(skillRepo)
+	skillService := service.NewSkillService(skillRepo, p)
 	serviceService := queModule.Svc
 	service2 := caseModule.Svc
-	handler := web.NewHandler(skillService, serviceService, service2)
-	return handler, nil
+	service3 := practiceModule.Svc
+	handler := web.NewHandler(skillService, serviceService, service2, service3)
+	purgeJob := initPurgeJob(skillService)
+	reindexJob := initReindexJob(skillService)
+	module := &Module{
+		Svc:        skillService,
+		Hdl:        handler,
+		PurgeJob:   purgeJob,
+		ReindexJob: reindexJob,
+	}
+	return module, nil
 }
 
 // wire.go:
 
+// InitQuestionRefService 题目模块构造的时候需要，这个时候还没有技能模块，所以不能用 InitModule
+func InitQuestionRefService(db *egorm.Component, ec ecache.Cache) baguwen.ReferenceService {
+	return initRefService(db, ec, dao.RTypeQuestion)
+}
+
+// InitCaseRefService 和 InitQuestionRefService 一样，提供给案例模块
+func InitCaseRefService(db *egorm.Component, ec ecache.Cache) cases.ReferenceService {
+	return initRefService(db, ec, dao.RTypeCase)
+}
+
+func initRefService(db *egorm.Component, ec ecache.Cache, rtype string) *service.ReferenceService {
+	repo := repository.NewSkillRepo(InitSkillDAO(db), cache.NewSkillCache(ec))
+	return service.NewReferenceService(repo, rtype)
+}
+
+// initPurgeJob 回收站里面的技能保留 30 天
+func initPurgeJob(svc service.SkillService) *PurgeJob {
+	return job.NewPurgeJob(svc, 30, 100, time.Hour)
+}
+
+func initReindexJob(svc service.SkillService) *ReindexJob {
+	return job.NewReindexJob(svc, 100)
+}
+
 var daoOnce = sync.Once{}
 
 func InitTableOnce(db *gorm.DB) {
@@ -47,9 +96,27 @@ func InitTableOnce(db *gorm.DB) {
 	})
 }
 
+func initSyncEventProducer(q mq.MQ) event.SyncEventProducer {
+	producer, err := event.NewSyncEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
+}
+
 func InitSkillDAO(db *egorm.Component) dao.SkillDAO {
 	InitTableOnce(db)
 	return dao.NewSkillDAO(db)
 }
 
 type Handler = web.Handler
+
+type PurgeJob = job.PurgeJob
+
+type ReindexJob = job.ReindexJob
+
+type Service = service.SkillService
+
+type Skill = domain.Skill
+
+type SkillLevel = domain.SkillLevel
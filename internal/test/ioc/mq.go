@@ -79,6 +79,14 @@ func initMQ() (mq.MQ, error) {
 			Name:       "credit_increase_events",
 			Partitions: 1,
 		},
+		{
+			Name:       "sync_search_events",
+			Partitions: 1,
+		},
+		{
+			Name:       "order_fulfillment_events",
+			Partitions: 1,
+		},
 	})
 	err := econf.UnmarshalKey("kafka", &cfg)
 	if err != nil {
@@ -6,17 +6,27 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-var cache ecache.Cache
+var (
+	cache ecache.Cache
+	cmd   redis.Cmdable
+)
 
 func InitCache() ecache.Cache {
 	if cache != nil {
 		return cache
 	}
-	cmd := redis.NewClient(&redis.Options{
-		Addr: "localhost:6379",
-	})
 	return &ecache.NamespaceCache{
-		C:         eredis.NewCache(cmd),
+		C:         eredis.NewCache(InitRedis()),
 		Namespace: "webook:",
 	}
 }
+
+func InitRedis() redis.Cmdable {
+	if cmd != nil {
+		return cmd
+	}
+	cmd = redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+	return cmd
+}
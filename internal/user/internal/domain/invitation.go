@@ -0,0 +1,44 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package domain
+
+// InvitationCode 用户的邀请码，每个用户只有一个
+type InvitationCode struct {
+	Uid  int64
+	Code string
+}
+
+// Invitation 一条邀请记录
+type Invitation struct {
+	ID      int64
+	Inviter int64
+	Invitee User
+	Code    string
+	// Rewarded 是否发放了邀请奖励，超过防刷限制的邀请只记录关系不发奖励
+	Rewarded bool
+	Ctime    int64
+}
+
+// InvitationConfig 邀请奖励以及防刷配置
+type InvitationConfig struct {
+	// InviterCredits 邀请人获得的积分
+	InviterCredits uint64
+	// InviteeCredits 被邀请人获得的积分
+	InviteeCredits uint64
+	// DailyLimit 邀请人每天最多获得奖励的次数
+	DailyLimit int64
+	// TotalLimit 邀请人累计最多获得奖励的次数
+	TotalLimit int64
+}
@@ -17,15 +17,22 @@
 package integration
 
 import (
+	"context"
 	"net/http"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/ecodeclub/ekit/iox"
 	"github.com/ecodeclub/ginx/session"
 	test2 "github.com/ecodeclub/webook/internal/test"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ecodeclub/webook/internal/user/internal/domain"
+	"github.com/ecodeclub/webook/internal/user/internal/event"
+	evtmocks "github.com/ecodeclub/webook/internal/user/internal/event/mocks"
 	"github.com/ecodeclub/webook/internal/user/internal/integration/startup"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/dao"
+	"github.com/ecodeclub/webook/internal/user/internal/service"
 	"github.com/ecodeclub/webook/internal/user/internal/web"
 	"github.com/ego-component/egorm"
 	"github.com/gin-gonic/gin"
@@ -34,12 +41,16 @@ import (
 	"github.com/gotomicro/ego/server/egin"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
+	"go.uber.org/mock/gomock"
 )
 
 type HandleTestSuite struct {
 	suite.Suite
-	db     *egorm.Component
-	server *egin.Component
+	db            *egorm.Component
+	server        *egin.Component
+	ctrl          *gomock.Controller
+	producer      *evtmocks.MockIncreaseCreditsEventProducer
+	invitationSvc service.InvitationService
 }
 
 func (s *HandleTestSuite) SetupSuite() {
@@ -49,7 +60,16 @@ func (s *HandleTestSuite) SetupSuite() {
 	require.NoError(s.T(), err)
 	econf.Set("server", map[string]string{})
 	server := egin.Load("server").Build()
-	hdl := startup.InitHandler(nil, nil, nil)
+	s.ctrl = gomock.NewController(s.T())
+	s.producer = evtmocks.NewMockIncreaseCreditsEventProducer(s.ctrl)
+	cfg := domain.InvitationConfig{
+		InviterCredits: 100,
+		InviteeCredits: 50,
+		DailyLimit:     1,
+		TotalLimit:     10,
+	}
+	hdl := startup.InitHandler(nil, nil, nil, s.producer, cfg)
+	s.invitationSvc = startup.InitInvitationService(s.producer, cfg)
 	server.Use(func(ctx *gin.Context) {
 		ctx.Set("_session", session.NewMemorySession(session.Claims{
 			Uid: 123,
@@ -62,6 +82,11 @@ func (s *HandleTestSuite) SetupSuite() {
 func (s *HandleTestSuite) TearDownSuite() {
 	err := s.db.Exec("TRUNCATE table `users`").Error
 	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE table `invitation_codes`").Error
+	require.NoError(s.T(), err)
+	err = s.db.Exec("TRUNCATE table `invitation_records`").Error
+	require.NoError(s.T(), err)
+	s.ctrl.Finish()
 }
 
 func (s *HandleTestSuite) TestEditProfile() {
@@ -196,6 +221,135 @@ func (s *HandleTestSuite) TestProfile() {
 	}
 }
 
+func (s *HandleTestSuite) TestInvitationCode() {
+	t := s.T()
+	code := s.getInvitationCode(t)
+	require.Len(t, code, 8)
+	// 再次获取的时候邀请码不变
+	assert.Equal(t, code, s.getInvitationCode(t))
+
+	var c dao.InvitationCode
+	err := s.db.Where("uid = ?", 123).First(&c).Error
+	require.NoError(t, err)
+	assert.Equal(t, code, c.Code)
+}
+
+func (s *HandleTestSuite) getInvitationCode(t *testing.T) string {
+	req, err := http.NewRequest(http.MethodGet,
+		"/users/invitation/code", nil)
+	require.NoError(t, err)
+	recorder := test2.NewJSONResponseRecorder[string]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	return recorder.MustScan().Data
+}
+
+func (s *HandleTestSuite) TestAttributeInvitation() {
+	t := s.T()
+	ctx := context.Background()
+	err := s.db.Create(&dao.InvitationCode{Uid: 200, Code: "abcdefgh"}).Error
+	require.NoError(t, err)
+
+	// 邀请码非法
+	_, err = s.invitationSvc.Attribute(ctx, 201, "not-exist")
+	require.ErrorIs(t, err, service.ErrInvalidInvitationCode)
+
+	// 不能邀请自己
+	_, err = s.invitationSvc.Attribute(ctx, 200, "abcdefgh")
+	require.ErrorIs(t, err, service.ErrInvalidInvitationCode)
+
+	// 第一次邀请，双方都有奖励
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, evt event.CreditIncreaseEvent) error {
+			assert.Equal(t, int64(200), evt.Uid)
+			assert.Equal(t, uint64(100), evt.Amount)
+			return nil
+		})
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, evt event.CreditIncreaseEvent) error {
+			assert.Equal(t, int64(201), evt.Uid)
+			assert.Equal(t, uint64(50), evt.Amount)
+			return nil
+		})
+	inv, err := s.invitationSvc.Attribute(ctx, 201, "abcdefgh")
+	require.NoError(t, err)
+	require.True(t, inv.Rewarded)
+
+	// 同一个人不能被邀请两次
+	_, err = s.invitationSvc.Attribute(ctx, 201, "abcdefgh")
+	require.ErrorIs(t, err, service.ErrAlreadyInvited)
+
+	// 超过每日上限，只记录关系，没有奖励
+	inv, err = s.invitationSvc.Attribute(ctx, 202, "abcdefgh")
+	require.NoError(t, err)
+	require.False(t, inv.Rewarded)
+
+	var cnt int64
+	err = s.db.Model(&dao.InvitationRecord{}).Where("inviter = ?", 200).Count(&cnt).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), cnt)
+}
+
+func (s *HandleTestSuite) TestAttributeInvitation_Concurrent() {
+	t := s.T()
+	ctx := context.Background()
+	err := s.db.Create(&dao.InvitationCode{Uid: 300, Code: "concurre"}).Error
+	require.NoError(t, err)
+
+	// 每日上限是 1，并发邀请也只有一次奖励，也就是两条消息
+	s.producer.EXPECT().Produce(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+	var wg sync.WaitGroup
+	for i := int64(1); i <= 10; i++ {
+		wg.Add(1)
+		go func(invitee int64) {
+			defer wg.Done()
+			_, er := s.invitationSvc.Attribute(ctx, invitee, "concurre")
+			assert.Equal(t, nil, er)
+		}(300 + i)
+	}
+	wg.Wait()
+
+	var cnt int64
+	err = s.db.Model(&dao.InvitationRecord{}).Where("inviter = ?", 300).Count(&cnt).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), cnt)
+	err = s.db.Model(&dao.InvitationRecord{}).
+		Where("inviter = ? AND rewarded = ?", 300, true).Count(&cnt).Error
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), cnt)
+}
+
+func (s *HandleTestSuite) TestListInvitations() {
+	t := s.T()
+	now := time.Now().UnixMilli()
+	err := s.db.Create([]dao.User{
+		{Id: 301, Nickname: "invitee1", Avatar: "avatar1"},
+		{Id: 302, Nickname: "invitee2", Avatar: "avatar2"},
+	}).Error
+	require.NoError(t, err)
+	err = s.db.Create([]dao.InvitationRecord{
+		{Inviter: 123, Invitee: 301, Code: "code", Rewarded: true, Ctime: now, Utime: now},
+		{Inviter: 123, Invitee: 302, Code: "code", Rewarded: false, Ctime: now + 1, Utime: now + 1},
+		{Inviter: 124, Invitee: 303, Code: "code2", Rewarded: true, Ctime: now, Utime: now},
+	}).Error
+	require.NoError(t, err)
+
+	req, err := http.NewRequest(http.MethodPost,
+		"/users/invitation/list", iox.NewJSONReader(web.ListInvitationsReq{Offset: 0, Limit: 10}))
+	req.Header.Set("content-type", "application/json")
+	require.NoError(t, err)
+	recorder := test2.NewJSONResponseRecorder[web.InvitationList]()
+	s.server.ServeHTTP(recorder, req)
+	require.Equal(t, 200, recorder.Code)
+	assert.Equal(t, web.InvitationList{
+		Total: 2,
+		Invitations: []web.Invitation{
+			{Nickname: "invitee2", Avatar: "avatar2", Ctime: now + 1},
+			{Nickname: "invitee1", Avatar: "avatar1", Rewarded: true, Ctime: now},
+		},
+	}, recorder.MustScan().Data)
+}
+
 func TestUserHandler(t *testing.T) {
 	suite.Run(t, new(HandleTestSuite))
 }
@@ -21,6 +21,7 @@ import (
 	"github.com/ecodeclub/webook/internal/member"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
 	"github.com/ecodeclub/webook/internal/user"
+	"github.com/ecodeclub/webook/internal/user/internal/domain"
 	"github.com/ecodeclub/webook/internal/user/internal/event"
 	"github.com/ecodeclub/webook/internal/user/internal/repository"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/cache"
@@ -30,10 +31,17 @@ import (
 	"github.com/google/wire"
 )
 
-func InitHandler(weSvc service.OAuth2Service, memberSvc member.Service, creators []string) *user.Handler {
+var invitationSet = wire.NewSet(
+	dao.NewInvitationGORMDAO,
+	repository.NewInvitationRepository,
+	service.NewInvitationService)
+
+func InitHandler(weSvc service.OAuth2Service, memberSvc member.Service, creators []string,
+	p event.IncreaseCreditsEventProducer, cfg domain.InvitationConfig) *user.Handler {
 	wire.Build(web.NewHandler,
 		testioc.BaseSet,
 		InitRegistrationEventProducer,
+		invitationSet,
 		service.NewUserService,
 		dao.NewGORMUserDAO,
 		cache.NewUserECache,
@@ -41,6 +49,15 @@ func InitHandler(weSvc service.OAuth2Service, memberSvc member.Service, creators
 	return new(user.Handler)
 }
 
+func InitInvitationService(p event.IncreaseCreditsEventProducer, cfg domain.InvitationConfig) service.InvitationService {
+	wire.Build(testioc.BaseSet,
+		invitationSet,
+		dao.NewGORMUserDAO,
+		cache.NewUserECache,
+		repository.NewCachedUserRepository)
+	return nil
+}
+
 func InitRegistrationEventProducer(q mq.MQ) *event.RegistrationEventProducer {
 	return nil
 }
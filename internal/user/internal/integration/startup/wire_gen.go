@@ -8,19 +8,21 @@ package startup
 
 import (
 	"github.com/ecodeclub/mq-api"
-	service2 "github.com/ecodeclub/webook/internal/member"
+	"github.com/ecodeclub/webook/internal/member"
 	testioc "github.com/ecodeclub/webook/internal/test/ioc"
+	"github.com/ecodeclub/webook/internal/user/internal/domain"
 	"github.com/ecodeclub/webook/internal/user/internal/event"
 	"github.com/ecodeclub/webook/internal/user/internal/repository"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/dao"
 	"github.com/ecodeclub/webook/internal/user/internal/service"
 	"github.com/ecodeclub/webook/internal/user/internal/web"
+	"github.com/google/wire"
 )
 
 // Injectors from wire.go:
 
-func InitHandler(weSvc service.OAuth2Service, memberSvc service2.Service, creators []string) *web.Handler {
+func InitHandler(weSvc service.OAuth2Service, memberSvc member.Service, creators []string, p event.IncreaseCreditsEventProducer, cfg domain.InvitationConfig) *web.Handler {
 	db := testioc.InitDB()
 	userDAO := dao.NewGORMUserDAO(db)
 	ecacheCache := testioc.InitCache()
@@ -28,13 +30,30 @@ func InitHandler(weSvc service.OAuth2Service, memberSvc service2.Service, creato
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
 	mq := testioc.InitMQ()
 	registrationEventProducer := InitRegistrationEventProducer(mq)
-	userService := service.NewUserService(userRepository, registrationEventProducer)
-	handler := web.NewHandler(weSvc, userService, memberSvc, creators)
+	invitationDAO := dao.NewInvitationGORMDAO(db)
+	invitationRepository := repository.NewInvitationRepository(invitationDAO)
+	invitationService := service.NewInvitationService(invitationRepository, userRepository, p, cfg)
+	userService := service.NewUserService(userRepository, registrationEventProducer, invitationService)
+	handler := web.NewHandler(weSvc, userService, invitationService, memberSvc, creators)
 	return handler
 }
 
+func InitInvitationService(p event.IncreaseCreditsEventProducer, cfg domain.InvitationConfig) service.InvitationService {
+	db := testioc.InitDB()
+	invitationDAO := dao.NewInvitationGORMDAO(db)
+	invitationRepository := repository.NewInvitationRepository(invitationDAO)
+	userDAO := dao.NewGORMUserDAO(db)
+	ecacheCache := testioc.InitCache()
+	userCache := cache.NewUserECache(ecacheCache)
+	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
+	invitationService := service.NewInvitationService(invitationRepository, userRepository, p, cfg)
+	return invitationService
+}
+
 // wire.go:
 
+var invitationSet = wire.NewSet(dao.NewInvitationGORMDAO, repository.NewInvitationRepository, service.NewInvitationService)
+
 func InitRegistrationEventProducer(q mq.MQ) *event.RegistrationEventProducer {
 	return nil
 }
@@ -7,5 +7,7 @@ import (
 func InitTables(db *egorm.Component) error {
 	return db.AutoMigrate(
 		&User{},
+		&InvitationCode{},
+		&InvitationRecord{},
 	)
 }
@@ -0,0 +1,161 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dao
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/ego-component/egorm"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrInvitationDuplicate = errors.New("邀请码或者邀请记录已经存在")
+
+type InvitationDAO interface {
+	CreateCode(ctx context.Context, c InvitationCode) error
+	FindCodeByUid(ctx context.Context, uid int64) (InvitationCode, error)
+	FindCodeByCode(ctx context.Context, code string) (InvitationCode, error)
+
+	// CreateRecord 创建邀请记录，并且判定能不能发放奖励。
+	// totalLimit 是累计的奖励次数上限，dailyLimit 是从 since 开始的奖励次数上限，0 代表不限制。
+	// 判定和插入在同一个事务里面，并且锁住了邀请人的邀请码，所以并发邀请也不会超过限制
+	CreateRecord(ctx context.Context, r InvitationRecord, totalLimit, dailyLimit int64, since int64) (InvitationRecord, error)
+	ListRecords(ctx context.Context, inviter int64, offset, limit int) ([]InvitationRecord, error)
+	CountRecords(ctx context.Context, inviter int64) (int64, error)
+}
+
+type InvitationGORMDAO struct {
+	db *egorm.Component
+}
+
+func NewInvitationGORMDAO(db *egorm.Component) InvitationDAO {
+	return &InvitationGORMDAO{db: db}
+}
+
+func (dao *InvitationGORMDAO) CreateCode(ctx context.Context, c InvitationCode) error {
+	now := time.Now().UnixMilli()
+	c.Ctime, c.Utime = now, now
+	return dao.wrapDuplicate(dao.db.WithContext(ctx).Create(&c).Error)
+}
+
+func (dao *InvitationGORMDAO) FindCodeByUid(ctx context.Context, uid int64) (InvitationCode, error) {
+	var c InvitationCode
+	err := dao.db.WithContext(ctx).Where("uid = ?", uid).First(&c).Error
+	return c, err
+}
+
+func (dao *InvitationGORMDAO) FindCodeByCode(ctx context.Context, code string) (InvitationCode, error) {
+	var c InvitationCode
+	err := dao.db.WithContext(ctx).Where("code = ?", code).First(&c).Error
+	return c, err
+}
+
+func (dao *InvitationGORMDAO) CreateRecord(ctx context.Context, r InvitationRecord,
+	totalLimit, dailyLimit int64, since int64) (InvitationRecord, error) {
+	now := time.Now().UnixMilli()
+	r.Ctime, r.Utime = now, now
+	err := dao.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// 同一个邀请人的邀请排队处理
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("uid = ?", r.Inviter).First(&InvitationCode{}).Error
+		if err != nil {
+			return err
+		}
+		r.Rewarded, err = dao.withinLimits(tx, r.Inviter, totalLimit, dailyLimit, since)
+		if err != nil {
+			return err
+		}
+		return tx.Create(&r).Error
+	})
+	return r, dao.wrapDuplicate(err)
+}
+
+// withinLimits 邀请人是否还能获得奖励
+func (dao *InvitationGORMDAO) withinLimits(tx *gorm.DB, inviter int64,
+	totalLimit, dailyLimit int64, since int64) (bool, error) {
+	if totalLimit > 0 {
+		total, err := dao.countRewarded(tx, inviter, 0)
+		if err != nil || total >= totalLimit {
+			return false, err
+		}
+	}
+	if dailyLimit > 0 {
+		cnt, err := dao.countRewarded(tx, inviter, since)
+		if err != nil || cnt >= dailyLimit {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (dao *InvitationGORMDAO) countRewarded(tx *gorm.DB, inviter int64, since int64) (int64, error) {
+	var res int64
+	err := tx.Model(&InvitationRecord{}).
+		Where("inviter = ? AND rewarded = ? AND ctime >= ?", inviter, true, since).
+		Count(&res).Error
+	return res, err
+}
+
+func (dao *InvitationGORMDAO) ListRecords(ctx context.Context, inviter int64, offset, limit int) ([]InvitationRecord, error) {
+	var res []InvitationRecord
+	err := dao.db.WithContext(ctx).
+		Where("inviter = ?", inviter).
+		Order("id DESC").
+		Offset(offset).Limit(limit).
+		Find(&res).Error
+	return res, err
+}
+
+func (dao *InvitationGORMDAO) CountRecords(ctx context.Context, inviter int64) (int64, error) {
+	var res int64
+	err := dao.db.WithContext(ctx).Model(&InvitationRecord{}).
+		Where("inviter = ?", inviter).
+		Count(&res).Error
+	return res, err
+}
+
+func (dao *InvitationGORMDAO) wrapDuplicate(err error) error {
+	if me, ok := err.(*mysql.MySQLError); ok {
+		const uniqueIndexErrNo uint16 = 1062
+		if me.Number == uniqueIndexErrNo {
+			return ErrInvitationDuplicate
+		}
+	}
+	return err
+}
+
+// InvitationCode 邀请码
+type InvitationCode struct {
+	Id    int64  `gorm:"primaryKey,autoIncrement"`
+	Uid   int64  `gorm:"not null;uniqueIndex;comment:邀请码所属的用户"`
+	Code  string `gorm:"type:varchar(32);not null;uniqueIndex;comment:邀请码"`
+	Ctime int64
+	Utime int64
+}
+
+// InvitationRecord 邀请记录，一个用户只能被邀请一次
+type InvitationRecord struct {
+	Id       int64  `gorm:"primaryKey,autoIncrement"`
+	Inviter  int64  `gorm:"not null;index:idx_inviter_ctime;comment:邀请人"`
+	Invitee  int64  `gorm:"not null;uniqueIndex;comment:被邀请人"`
+	Code     string `gorm:"type:varchar(32);not null;comment:使用的邀请码"`
+	Rewarded bool   `gorm:"not null;default:false;comment:是否发放了奖励"`
+	Ctime    int64  `gorm:"index:idx_inviter_ctime"`
+	Utime    int64
+}
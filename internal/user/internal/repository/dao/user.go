@@ -23,6 +23,7 @@ type UserDAO interface {
 	UpdateNonZeroFields(ctx context.Context, u User) error
 	FindByWechat(ctx context.Context, openId string) (User, error)
 	FindById(ctx context.Context, id int64) (User, error)
+	FindByIds(ctx context.Context, ids []int64) ([]User, error)
 }
 
 type GORMUserDAO struct {
@@ -65,6 +66,12 @@ func (ud *GORMUserDAO) FindById(ctx context.Context, id int64) (User, error) {
 	return u, err
 }
 
+func (ud *GORMUserDAO) FindByIds(ctx context.Context, ids []int64) ([]User, error) {
+	var res []User
+	err := ud.db.WithContext(ctx).Where("id IN ?", ids).Find(&res).Error
+	return res, err
+}
+
 type User struct {
 	Id            int64 `gorm:"primaryKey,autoIncrement"`
 	Nickname      string
@@ -0,0 +1,110 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package repository
+
+import (
+	"context"
+
+	"github.com/ecodeclub/ekit/slice"
+	"github.com/ecodeclub/webook/internal/user/internal/domain"
+	"github.com/ecodeclub/webook/internal/user/internal/repository/dao"
+)
+
+var (
+	ErrInvitationDuplicate = dao.ErrInvitationDuplicate
+	ErrInvitationNotFound  = dao.ErrDataNotFound
+)
+
+type InvitationRepository interface {
+	CreateCode(ctx context.Context, c domain.InvitationCode) error
+	FindCodeByUid(ctx context.Context, uid int64) (domain.InvitationCode, error)
+	FindCodeByCode(ctx context.Context, code string) (domain.InvitationCode, error)
+
+	// CreateInvitation 创建邀请记录，按照 cfg 中的防刷限制判定是否发放奖励，since 是当天的开始时间。
+	// 返回的邀请记录带上了 ID 和是否发放奖励
+	CreateInvitation(ctx context.Context, inv domain.Invitation, cfg domain.InvitationConfig, since int64) (domain.Invitation, error)
+	// ListInvitations 邀请记录，只会填充被邀请人的 Id
+	ListInvitations(ctx context.Context, inviter int64, offset, limit int) ([]domain.Invitation, error)
+	CountInvitations(ctx context.Context, inviter int64) (int64, error)
+}
+
+type invitationRepository struct {
+	dao dao.InvitationDAO
+}
+
+func NewInvitationRepository(d dao.InvitationDAO) InvitationRepository {
+	return &invitationRepository{dao: d}
+}
+
+func (repo *invitationRepository) CreateCode(ctx context.Context, c domain.InvitationCode) error {
+	return repo.dao.CreateCode(ctx, dao.InvitationCode{
+		Uid:  c.Uid,
+		Code: c.Code,
+	})
+}
+
+func (repo *invitationRepository) FindCodeByUid(ctx context.Context, uid int64) (domain.InvitationCode, error) {
+	c, err := repo.dao.FindCodeByUid(ctx, uid)
+	return repo.codeToDomain(c), err
+}
+
+func (repo *invitationRepository) FindCodeByCode(ctx context.Context, code string) (domain.InvitationCode, error) {
+	c, err := repo.dao.FindCodeByCode(ctx, code)
+	return repo.codeToDomain(c), err
+}
+
+func (repo *invitationRepository) CreateInvitation(ctx context.Context, inv domain.Invitation,
+	cfg domain.InvitationConfig, since int64) (domain.Invitation, error) {
+	r, err := repo.dao.CreateRecord(ctx, dao.InvitationRecord{
+		Inviter: inv.Inviter,
+		Invitee: inv.Invitee.Id,
+		Code:    inv.Code,
+	}, cfg.TotalLimit, cfg.DailyLimit, since)
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+	inv.ID = r.Id
+	inv.Rewarded = r.Rewarded
+	inv.Ctime = r.Ctime
+	return inv, nil
+}
+
+func (repo *invitationRepository) ListInvitations(ctx context.Context, inviter int64, offset, limit int) ([]domain.Invitation, error) {
+	records, err := repo.dao.ListRecords(ctx, inviter, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(records, func(idx int, src dao.InvitationRecord) domain.Invitation {
+		return domain.Invitation{
+			ID:       src.Id,
+			Inviter:  src.Inviter,
+			Invitee:  domain.User{Id: src.Invitee},
+			Code:     src.Code,
+			Rewarded: src.Rewarded,
+			Ctime:    src.Ctime,
+		}
+	}), nil
+}
+
+func (repo *invitationRepository) CountInvitations(ctx context.Context, inviter int64) (int64, error) {
+	return repo.dao.CountRecords(ctx, inviter)
+}
+
+func (repo *invitationRepository) codeToDomain(c dao.InvitationCode) domain.InvitationCode {
+	return domain.InvitationCode{
+		Uid:  c.Uid,
+		Code: c.Code,
+	}
+}
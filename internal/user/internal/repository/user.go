@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/ecodeclub/ekit/slice"
 	"github.com/ecodeclub/webook/internal/user/internal/domain"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/cache"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/dao"
@@ -18,6 +19,7 @@ type UserRepository interface {
 	// 将来可能需要按照 unionId 来查询
 	FindByWechat(ctx context.Context, openId string) (domain.User, error)
 	FindById(ctx context.Context, id int64) (domain.User, error)
+	FindByIds(ctx context.Context, ids []int64) ([]domain.User, error)
 }
 
 // CachedUserRepository 使用了缓存的 repository 实现
@@ -80,6 +82,16 @@ func (ur *CachedUserRepository) FindById(ctx context.Context,
 	return u, nil
 }
 
+func (ur *CachedUserRepository) FindByIds(ctx context.Context, ids []int64) ([]domain.User, error) {
+	users, err := ur.dao.FindByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return slice.Map(users, func(idx int, src dao.User) domain.User {
+		return ur.entityToDomain(src)
+	}), nil
+}
+
 func (ur *CachedUserRepository) domainToEntity(u domain.User) dao.User {
 	return dao.User{
 		Id:       u.Id,
@@ -0,0 +1,199 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ecodeclub/webook/internal/user/internal/domain"
+	"github.com/ecodeclub/webook/internal/user/internal/event"
+	"github.com/ecodeclub/webook/internal/user/internal/repository"
+	"github.com/gotomicro/ego/core/elog"
+	"github.com/lithammer/shortuuid/v4"
+	"golang.org/x/sync/errgroup"
+)
+
+var (
+	ErrInvalidInvitationCode = errors.New("邀请码非法")
+	ErrAlreadyInvited        = errors.New("用户已经被邀请过")
+)
+
+const (
+	invitationCodeLength  = 8
+	invitationCodeRetries = 3
+)
+
+type InvitationService interface {
+	// Code 获取用户的邀请码，还没有的话就生成一个
+	Code(ctx context.Context, uid int64) (domain.InvitationCode, error)
+	// Attribute 将新注册的用户 invitee 归属到邀请码 code 的主人名下，并且发放奖励
+	Attribute(ctx context.Context, invitee int64, code string) (domain.Invitation, error)
+	// ListInvitations 用户邀请过的人
+	ListInvitations(ctx context.Context, uid int64, offset, limit int) ([]domain.Invitation, int64, error)
+}
+
+type invitationService struct {
+	repo     repository.InvitationRepository
+	userRepo repository.UserRepository
+	producer event.IncreaseCreditsEventProducer
+	cfg      domain.InvitationConfig
+	logger   *elog.Component
+}
+
+func NewInvitationService(repo repository.InvitationRepository,
+	userRepo repository.UserRepository,
+	producer event.IncreaseCreditsEventProducer,
+	cfg domain.InvitationConfig) InvitationService {
+	return &invitationService{
+		repo:     repo,
+		userRepo: userRepo,
+		producer: producer,
+		cfg:      cfg,
+		logger:   elog.DefaultLogger,
+	}
+}
+
+func (s *invitationService) Code(ctx context.Context, uid int64) (domain.InvitationCode, error) {
+	c, err := s.repo.FindCodeByUid(ctx, uid)
+	if !errors.Is(err, repository.ErrInvitationNotFound) {
+		return c, err
+	}
+	for i := 0; i < invitationCodeRetries; i++ {
+		c = domain.InvitationCode{
+			Uid:  uid,
+			Code: shortuuid.New()[:invitationCodeLength],
+		}
+		err = s.repo.CreateCode(ctx, c)
+		if err == nil {
+			return c, nil
+		}
+		if !errors.Is(err, repository.ErrInvitationDuplicate) {
+			return domain.InvitationCode{}, err
+		}
+		// 并发请求的时候别的请求已经生成了邀请码，或者邀请码冲突了，重新试一次
+		c, err = s.repo.FindCodeByUid(ctx, uid)
+		if err == nil {
+			return c, nil
+		}
+	}
+	return domain.InvitationCode{}, fmt.Errorf("生成邀请码失败 uid: %d", uid)
+}
+
+func (s *invitationService) Attribute(ctx context.Context, invitee int64, code string) (domain.Invitation, error) {
+	c, err := s.repo.FindCodeByCode(ctx, code)
+	if errors.Is(err, repository.ErrInvitationNotFound) {
+		return domain.Invitation{}, fmt.Errorf("%w, code: %s", ErrInvalidInvitationCode, code)
+	}
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+	if c.Uid == invitee {
+		return domain.Invitation{}, fmt.Errorf("%w, 不能邀请自己", ErrInvalidInvitationCode)
+	}
+	inv := domain.Invitation{
+		Inviter: c.Uid,
+		Invitee: domain.User{Id: invitee},
+		Code:    code,
+	}
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	// 超过防刷限制的邀请依旧会被记录下来，只是不发奖励
+	inv, err = s.repo.CreateInvitation(ctx, inv, s.cfg, today.UnixMilli())
+	if errors.Is(err, repository.ErrInvitationDuplicate) {
+		return domain.Invitation{}, ErrAlreadyInvited
+	}
+	if err != nil {
+		return domain.Invitation{}, err
+	}
+	if inv.Rewarded {
+		s.reward(ctx, inv)
+	}
+	return inv, nil
+}
+
+func (s *invitationService) reward(ctx context.Context, inv domain.Invitation) {
+	const biz = 1
+	evts := []event.CreditIncreaseEvent{
+		{
+			Key:    fmt.Sprintf("invitation:inviter:%d", inv.ID),
+			Uid:    inv.Inviter,
+			Amount: s.cfg.InviterCredits,
+			Biz:    biz,
+			BizId:  inv.Invitee.Id,
+			Action: "邀请注册",
+		},
+		{
+			Key:    fmt.Sprintf("invitation:invitee:%d", inv.ID),
+			Uid:    inv.Invitee.Id,
+			Amount: s.cfg.InviteeCredits,
+			Biz:    biz,
+			BizId:  inv.Inviter,
+			Action: "受邀注册",
+		},
+	}
+	for _, evt := range evts {
+		if evt.Amount == 0 {
+			continue
+		}
+		if err := s.producer.Produce(ctx, evt); err != nil {
+			s.logger.Error("发送邀请奖励消息失败",
+				elog.FieldErr(err),
+				elog.Any("event", evt),
+			)
+		}
+	}
+}
+
+func (s *invitationService) ListInvitations(ctx context.Context, uid int64, offset, limit int) ([]domain.Invitation, int64, error) {
+	var (
+		eg    errgroup.Group
+		invs  []domain.Invitation
+		total int64
+	)
+	eg.Go(func() error {
+		var err error
+		invs, err = s.repo.ListInvitations(ctx, uid, offset, limit)
+		if err != nil || len(invs) == 0 {
+			return err
+		}
+		ids := make([]int64, 0, len(invs))
+		for _, inv := range invs {
+			ids = append(ids, inv.Invitee.Id)
+		}
+		users, err := s.userRepo.FindByIds(ctx, ids)
+		if err != nil {
+			return err
+		}
+		userMap := make(map[int64]domain.User, len(users))
+		for _, u := range users {
+			userMap[u.Id] = u
+		}
+		for i := range invs {
+			if u, ok := userMap[invs[i].Invitee.Id]; ok {
+				invs[i].Invitee = u
+			}
+		}
+		return nil
+	})
+	eg.Go(func() error {
+		var err error
+		total, err = s.repo.CountInvitations(ctx, uid)
+		return err
+	})
+	return invs, total, eg.Wait()
+}
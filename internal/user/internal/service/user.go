@@ -31,7 +31,8 @@ type UserService interface {
 	Profile(ctx context.Context, id int64) (domain.User, error)
 	// FindOrCreateByWechat 查找或者初始化
 	// 随着业务增长，这边可以考虑拆分出去作为一个新的 Service
-	FindOrCreateByWechat(ctx context.Context, info domain.WechatInfo) (domain.User, error)
+	// inviteCode 是邀请码，只有新注册的用户才会使用
+	FindOrCreateByWechat(ctx context.Context, info domain.WechatInfo, inviteCode string) (domain.User, error)
 
 	// UpdateNonSensitiveInfo 更新非敏感数据
 	// 你可以在这里进一步补充究竟哪些数据会被更新
@@ -39,16 +40,20 @@ type UserService interface {
 }
 
 type userService struct {
-	repo     repository.UserRepository
-	producer *event.RegistrationEventProducer
-	logger   *elog.Component
+	repo          repository.UserRepository
+	producer      *event.RegistrationEventProducer
+	invitationSvc InvitationService
+	logger        *elog.Component
 }
 
-func NewUserService(repo repository.UserRepository, p *event.RegistrationEventProducer) UserService {
+func NewUserService(repo repository.UserRepository,
+	p *event.RegistrationEventProducer,
+	invitationSvc InvitationService) UserService {
 	return &userService{
-		repo:     repo,
-		producer: p,
-		logger:   elog.DefaultLogger,
+		repo:          repo,
+		producer:      p,
+		invitationSvc: invitationSvc,
+		logger:        elog.DefaultLogger,
 	}
 }
 
@@ -59,7 +64,7 @@ func (svc *userService) UpdateNonSensitiveInfo(ctx context.Context, user domain.
 }
 
 func (svc *userService) FindOrCreateByWechat(ctx context.Context,
-	info domain.WechatInfo) (domain.User, error) {
+	info domain.WechatInfo, inviteCode string) (domain.User, error) {
 	// 类似于手机号的过程，大部分人只是扫码登录，也就是数据在我们这里是有的
 	u, err := svc.repo.FindByWechat(ctx, info.OpenId)
 	if !errors.Is(err, repository.ErrUserNotFound) {
@@ -86,6 +91,17 @@ func (svc *userService) FindOrCreateByWechat(ctx context.Context,
 		)
 	}
 
+	// 邀请关系不影响注册本身
+	if inviteCode != "" {
+		if _, e := svc.invitationSvc.Attribute(ctx, id, inviteCode); e != nil {
+			svc.logger.Error("记录邀请关系失败",
+				elog.FieldErr(e),
+				elog.Int64("uid", id),
+				elog.String("inviteCode", inviteCode),
+			)
+		}
+	}
+
 	return domain.User{
 		Id:         id,
 		WechatInfo: info,
@@ -31,23 +31,29 @@ import (
 
 var _ ginx.Handler = &Handler{}
 
+const maxLimit = 100
+
 type Handler struct {
-	weSvc     service.OAuth2Service
-	userSvc   service.UserService
-	memberSvc member.Service
+	weSvc         service.OAuth2Service
+	userSvc       service.UserService
+	invitationSvc service.InvitationService
+	memberSvc     member.Service
 	// 白名单
 	creators []string
 	logger   *elog.Component
 }
 
 func NewHandler(weSvc service.OAuth2Service,
-	userSvc service.UserService, memberSvc member.Service, creators []string) *Handler {
+	userSvc service.UserService,
+	invitationSvc service.InvitationService,
+	memberSvc member.Service, creators []string) *Handler {
 	return &Handler{
-		weSvc:     weSvc,
-		userSvc:   userSvc,
-		memberSvc: memberSvc,
-		creators:  creators,
-		logger:    elog.DefaultLogger,
+		weSvc:         weSvc,
+		userSvc:       userSvc,
+		invitationSvc: invitationSvc,
+		memberSvc:     memberSvc,
+		creators:      creators,
+		logger:        elog.DefaultLogger,
 	}
 }
 
@@ -55,6 +61,8 @@ func (h *Handler) PrivateRoutes(server *gin.Engine) {
 	users := server.Group("/users")
 	users.GET("/profile", ginx.S(h.Profile))
 	users.POST("/profile", ginx.BS[EditReq](h.Edit))
+	users.GET("/invitation/code", ginx.S(h.InvitationCode))
+	users.POST("/invitation/list", ginx.BS[ListInvitationsReq](h.ListInvitations))
 }
 
 func (h *Handler) PublicRoutes(server *gin.Engine) {
@@ -123,7 +131,7 @@ func (h *Handler) Callback(ctx *ginx.Context, req WechatCallback) (ginx.Result,
 	if err != nil {
 		return systemErrorResult, err
 	}
-	user, err := h.userSvc.FindOrCreateByWechat(ctx, info)
+	user, err := h.userSvc.FindOrCreateByWechat(ctx, info, req.InviteCode)
 	if err != nil {
 		return systemErrorResult, err
 	}
@@ -150,6 +158,37 @@ func (h *Handler) Callback(ctx *ginx.Context, req WechatCallback) (ginx.Result,
 	}, nil
 }
 
+// InvitationCode 获取自己的邀请码
+func (h *Handler) InvitationCode(ctx *ginx.Context, sess session.Session) (ginx.Result, error) {
+	c, err := h.invitationSvc.Code(ctx, sess.Claims().Uid)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: c.Code,
+	}, nil
+}
+
+// ListInvitations 自己邀请过的人
+func (h *Handler) ListInvitations(ctx *ginx.Context, req ListInvitationsReq, sess session.Session) (ginx.Result, error) {
+	if req.Limit <= 0 || req.Limit > maxLimit {
+		req.Limit = maxLimit
+	}
+	req.Offset = max(req.Offset, 0)
+	invs, total, err := h.invitationSvc.ListInvitations(ctx, sess.Claims().Uid, req.Offset, req.Limit)
+	if err != nil {
+		return systemErrorResult, err
+	}
+	return ginx.Result{
+		Data: InvitationList{
+			Total: total,
+			Invitations: slice.Map(invs, func(idx int, src domain.Invitation) Invitation {
+				return newInvitation(src)
+			}),
+		},
+	}, nil
+}
+
 func (h *Handler) getMemberDDL(ctx context.Context, userID int64) int64 {
 	mem, err := h.memberSvc.GetMembershipInfo(ctx, userID)
 	if err != nil {
@@ -37,9 +37,38 @@ func newProfile(u domain.User) Profile {
 type WechatCallback struct {
 	Code  string `json:"code"`
 	State string `json:"state"`
+	// InviteCode 邀请码，只对新注册的用户生效
+	InviteCode string `json:"inviteCode"`
 }
 
 type EditReq struct {
 	Avatar   string `json:"avatar"`
 	Nickname string `json:"nickname"`
 }
+
+type ListInvitationsReq struct {
+	Offset int `json:"offset,omitempty"`
+	Limit  int `json:"limit,omitempty"`
+}
+
+type InvitationList struct {
+	Total       int64        `json:"total"`
+	Invitations []Invitation `json:"invitations,omitempty"`
+}
+
+type Invitation struct {
+	Nickname string `json:"nickname,omitempty"`
+	Avatar   string `json:"avatar,omitempty"`
+	Rewarded bool   `json:"rewarded,omitempty"`
+	// 邀请时间，毫秒数
+	Ctime int64 `json:"ctime,omitempty"`
+}
+
+func newInvitation(inv domain.Invitation) Invitation {
+	return Invitation{
+		Nickname: inv.Invitee.Nickname,
+		Avatar:   inv.Invitee.Avatar,
+		Rewarded: inv.Rewarded,
+		Ctime:    inv.Ctime,
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"github.com/ecodeclub/ecache"
 	"github.com/ecodeclub/mq-api"
 	"github.com/ecodeclub/webook/internal/member"
+	"github.com/ecodeclub/webook/internal/user/internal/domain"
 	"github.com/ecodeclub/webook/internal/user/internal/event"
 	"github.com/ecodeclub/webook/internal/user/internal/repository"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/cache"
@@ -36,6 +37,11 @@ var ProviderSet = wire.NewSet(web.NewHandler,
 	InitDAO,
 	InitWechatService,
 	InitRegistrationEventProducer,
+	InitIncreaseCreditsEventProducer,
+	InitInvitationConfig,
+	dao.NewInvitationGORMDAO,
+	repository.NewInvitationRepository,
+	service.NewInvitationService,
 	service.NewUserService,
 	repository.NewCachedUserRepository)
 
@@ -78,5 +84,39 @@ func InitRegistrationEventProducer(q mq.MQ) *event.RegistrationEventProducer {
 	return event.NewRegistrationEventProducer(producer)
 }
 
+func InitIncreaseCreditsEventProducer(q mq.MQ) event.IncreaseCreditsEventProducer {
+	producer, err := event.NewIncreaseCreditsEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
+}
+
+// InitInvitationConfig 邀请奖励配置，没有配置的时候双方各奖励 100 积分
+func InitInvitationConfig() domain.InvitationConfig {
+	type Config struct {
+		InviterCredits uint64 `yaml:"inviterCredits"`
+		InviteeCredits uint64 `yaml:"inviteeCredits"`
+		DailyLimit     int64  `yaml:"dailyLimit"`
+		TotalLimit     int64  `yaml:"totalLimit"`
+	}
+	cfg := Config{
+		InviterCredits: 100,
+		InviteeCredits: 100,
+		DailyLimit:     10,
+		TotalLimit:     100,
+	}
+	err := econf.UnmarshalKey("invitation", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return domain.InvitationConfig{
+		InviterCredits: cfg.InviterCredits,
+		InviteeCredits: cfg.InviteeCredits,
+		DailyLimit:     cfg.DailyLimit,
+		TotalLimit:     cfg.TotalLimit,
+	}
+}
+
 // Handler 暴露出去给 ioc 使用
 type Handler = web.Handler
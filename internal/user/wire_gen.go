@@ -10,6 +10,7 @@ import (
 	"github.com/ecodeclub/ecache"
 	"github.com/ecodeclub/mq-api"
 	"github.com/ecodeclub/webook/internal/member"
+	"github.com/ecodeclub/webook/internal/user/internal/domain"
 	"github.com/ecodeclub/webook/internal/user/internal/event"
 	"github.com/ecodeclub/webook/internal/user/internal/repository"
 	"github.com/ecodeclub/webook/internal/user/internal/repository/cache"
@@ -30,9 +31,14 @@ func InitHandler(db *gorm.DB, cache2 ecache.Cache, q mq.MQ, creators []string, m
 	userCache := cache.NewUserECache(cache2)
 	userRepository := repository.NewCachedUserRepository(userDAO, userCache)
 	registrationEventProducer := InitRegistrationEventProducer(q)
-	userService := service.NewUserService(userRepository, registrationEventProducer)
+	invitationDAO := dao.NewInvitationGORMDAO(db)
+	invitationRepository := repository.NewInvitationRepository(invitationDAO)
+	increaseCreditsEventProducer := InitIncreaseCreditsEventProducer(q)
+	invitationConfig := InitInvitationConfig()
+	invitationService := service.NewInvitationService(invitationRepository, userRepository, increaseCreditsEventProducer, invitationConfig)
+	userService := service.NewUserService(userRepository, registrationEventProducer, invitationService)
 	serviceService := memberSvc.Svc
-	handler := web.NewHandler(oAuth2Service, userService, serviceService, creators)
+	handler := web.NewHandler(oAuth2Service, userService, invitationService, serviceService, creators)
 	return handler
 }
 
@@ -40,7 +46,9 @@ func InitHandler(db *gorm.DB, cache2 ecache.Cache, q mq.MQ, creators []string, m
 
 var ProviderSet = wire.NewSet(web.NewHandler, cache.NewUserECache, InitDAO,
 	InitWechatService,
-	InitRegistrationEventProducer, service.NewUserService, repository.NewCachedUserRepository,
+	InitRegistrationEventProducer,
+	InitIncreaseCreditsEventProducer,
+	InitInvitationConfig, dao.NewInvitationGORMDAO, repository.NewInvitationRepository, service.NewInvitationService, service.NewUserService, repository.NewCachedUserRepository,
 )
 
 func InitWechatService() service.OAuth2Service {
@@ -73,5 +81,39 @@ func InitRegistrationEventProducer(q mq.MQ) *event.RegistrationEventProducer {
 	return event.NewRegistrationEventProducer(producer)
 }
 
+func InitIncreaseCreditsEventProducer(q mq.MQ) event.IncreaseCreditsEventProducer {
+	producer, err := event.NewIncreaseCreditsEventProducer(q)
+	if err != nil {
+		panic(err)
+	}
+	return producer
+}
+
+// InitInvitationConfig 邀请奖励配置，没有配置的时候双方各奖励 100 积分
+func InitInvitationConfig() domain.InvitationConfig {
+	type Config struct {
+		InviterCredits uint64 `yaml:"inviterCredits"`
+		InviteeCredits uint64 `yaml:"inviteeCredits"`
+		DailyLimit     int64  `yaml:"dailyLimit"`
+		TotalLimit     int64  `yaml:"totalLimit"`
+	}
+	cfg := Config{
+		InviterCredits: 100,
+		InviteeCredits: 100,
+		DailyLimit:     10,
+		TotalLimit:     100,
+	}
+	err := econf.UnmarshalKey("invitation", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return domain.InvitationConfig{
+		InviterCredits: cfg.InviterCredits,
+		InviteeCredits: cfg.InviteeCredits,
+		DailyLimit:     cfg.DailyLimit,
+		TotalLimit:     cfg.TotalLimit,
+	}
+}
+
 // Handler 暴露出去给 ioc 使用
 type Handler = web.Handler
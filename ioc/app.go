@@ -2,8 +2,14 @@ package ioc
 
 import (
 	"github.com/gotomicro/ego/server/egin"
+	"github.com/gotomicro/ego/task/ecron"
+	"github.com/gotomicro/ego/task/ejob"
 )
 
 type App struct {
 	Web *egin.Component
+	// 只有通过 --job 指定的时候才会执行
+	Jobs []ejob.Ejob
+	// 定时任务
+	Crons []ecron.Ecron
 }
@@ -18,9 +18,16 @@ import (
 	"net/http"
 	"strings"
 
+	"github.com/ecodeclub/webook/internal/checkin"
+	"github.com/ecodeclub/webook/internal/comment"
+	"github.com/ecodeclub/webook/internal/evaluation"
 	"github.com/ecodeclub/webook/internal/feedback"
+	"github.com/ecodeclub/webook/internal/interview"
+	"github.com/ecodeclub/webook/internal/note"
 
 	"github.com/ecodeclub/webook/internal/pkg/middleware"
+	"github.com/ecodeclub/webook/internal/practice"
+	"github.com/ecodeclub/webook/internal/review"
 	"github.com/ecodeclub/webook/internal/skill"
 
 	"github.com/ecodeclub/webook-private/nonsense"
@@ -32,6 +39,7 @@ import (
 	"github.com/ecodeclub/webook/internal/cos"
 
 	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/search"
 
 	"github.com/gin-gonic/gin"
 
@@ -51,11 +59,19 @@ func initGinxServer(sp session.Provider,
 	caseHdl *cases.Handler,
 	skillHdl *skill.Handler,
 	fbHdl *feedback.Handler,
+	checkinHdl *checkin.Handler,
+	searchHdl *search.Handler,
+	practiceHdl *practice.Handler,
+	reviewHdl *review.Handler,
+	interviewHdl *interview.Handler,
+	evaluationHdl *evaluation.Handler,
+	noteHdl *note.Handler,
+	commentHdl *comment.Handler,
 ) *egin.Component {
 	session.SetDefaultProvider(sp)
 	res := egin.Load("web").Build()
 	res.Use(cors.New(cors.Config{
-		ExposeHeaders:    []string{"X-Refresh-Token", "X-Access-Token"},
+		ExposeHeaders:    []string{"X-Refresh-Token", "X-Access-Token", middleware.GraceHeader},
 		AllowCredentials: true,
 		AllowHeaders:     []string{"X-Timestamp", "Authorization", "Content-Type"},
 		AllowOriginFunc: func(origin string) bool {
@@ -79,6 +95,9 @@ func initGinxServer(sp session.Provider,
 	cosHdl.PublicRoutes(res.Engine)
 	caseHdl.PublicRoutes(res.Engine)
 	skillHdl.PublicRoutes(res.Engine)
+	searchHdl.PublicRoutes(res.Engine)
+	lhdl.PublicRoutes(res.Engine)
+	commentHdl.PublicRoutes(res.Engine)
 	// 登录校验
 	res.Use(session.CheckLoginMiddleware())
 	user.PrivateRoutes(res.Engine)
@@ -88,11 +107,19 @@ func initGinxServer(sp session.Provider,
 	cosHdl.PrivateRoutes(res.Engine)
 	caseHdl.PrivateRoutes(res.Engine)
 	skillHdl.PrivateRoutes(res.Engine)
+	checkinHdl.PrivateRoutes(res.Engine)
+	commentHdl.PrivateRoutes(res.Engine)
 	// 会员校验
 	res.Use(checkMembershipMiddleware.Build())
 	qh.MemberRoutes(res.Engine)
 	qsh.MemberRoutes(res.Engine)
 	caseHdl.MemberRoutes(res.Engine)
 	fbHdl.MemberRoutes(res.Engine)
+	practiceHdl.MemberRoutes(res.Engine)
+	reviewHdl.MemberRoutes(res.Engine)
+	interviewHdl.MemberRoutes(res.Engine)
+	evaluationHdl.MemberRoutes(res.Engine)
+	noteHdl.MemberRoutes(res.Engine)
+	skillHdl.MemberRoutes(res.Engine)
 	return res
 }
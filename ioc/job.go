@@ -15,17 +15,76 @@
 package ioc
 
 import (
+	"context"
+	"fmt"
+
+	"github.com/ecodeclub/webook/internal/cases"
 	"github.com/ecodeclub/webook/internal/job"
+	"github.com/ecodeclub/webook/internal/member"
 	"github.com/ecodeclub/webook/internal/order"
+	"github.com/ecodeclub/webook/internal/product"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend"
+	"github.com/ecodeclub/webook/internal/review"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/gotomicro/ego/task/ecron"
+	"github.com/gotomicro/ego/task/ejob"
 	"github.com/robfig/cron/v3"
 )
 
-func InitCronJobs(cjob *order.CloseExpiredOrdersJob) *cron.Cron {
+// InitCronJobs 定时任务，随着 Web 服务一起启动
+func InitCronJobs(cjob *order.CloseExpiredOrdersJob, fjob *order.FulfillPresaleOrdersJob, rjob *member.ExpiryReminderJob, sjob *product.SaleWindowJob,
+	qjob *review.DueQueueJob, rljob *recommend.RelatedJob,
+	qpjob *baguwen.PurgeJob, cpjob *cases.PurgeJob, spjob *skill.PurgeJob) []ecron.Ecron {
 	builder := job.NewCronJobBuilder()
-	expr := cron.New(cron.WithSeconds())
-	_, err := expr.AddJob("@midnight", builder.Build(cjob))
+	return []ecron.Ecron{
+		initCronJob(builder, "@midnight", cjob),
+		// 每分钟履约一次到了发售时间的预售订单
+		initCronJob(builder, "@every 1m", fjob),
+		// 每天早上九点发送会员到期提醒
+		initCronJob(builder, "0 0 9 * * *", rjob),
+		// 每分钟同步一次商品的销售时间窗口
+		initCronJob(builder, "@every 1m", sjob),
+		// 每天零点过后预先计算当天的复习队列
+		initCronJob(builder, "0 5 0 * * *", qjob),
+		// 每天凌晨三点重新计算题目的相关推荐
+		initCronJob(builder, "0 0 3 * * *", rljob),
+		// 每天凌晨四点清理回收站里面过期的题目、题集、案例和技能
+		initCronJob(builder, "0 0 4 * * *", qpjob),
+		initCronJob(builder, "0 0 4 * * *", cpjob),
+		initCronJob(builder, "0 0 4 * * *", spjob),
+	}
+}
+
+var cronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour |
+	cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+func initCronJob(builder *job.CronJobBuilder, spec string, j job.Job) ecron.Ecron {
+	// 启动的时候就暴露写错了的表达式，而不是等到定时任务开始调度
+	_, err := cronParser.Parse(spec)
 	if err != nil {
-		panic(err)
+		panic(fmt.Errorf("定时任务 %s 的表达式 %s 非法: %w", j.Name(), spec, err))
+	}
+	cj := builder.Build(j)
+	return ecron.DefaultContainer().Build(
+		ecron.WithSeconds(),
+		ecron.WithSpec(spec),
+		ecron.WithJob(func(ctx context.Context) error {
+			cj.Run()
+			return nil
+		}),
+	)
+}
+
+// InitEgoJobs 一次性的命令行任务，例如：
+// go run main.go --config=config/config.yaml --job=question-import --job-data='{"uid":1,"path":"questions.zip"}'
+func InitEgoJobs(qm *baguwen.Module, cm *cases.Module, sm *skill.Module) []ejob.Ejob {
+	return []ejob.Ejob{
+		ejob.Job(qm.ImportJob.Name(), qm.ImportJob.Run),
+		ejob.Job(qm.ExportJob.Name(), qm.ExportJob.Run),
+		// 已有的内容重新同步到搜索
+		ejob.Job(qm.ReindexJob.Name(), qm.ReindexJob.Run),
+		ejob.Job(cm.ReindexJob.Name(), cm.ReindexJob.Run),
+		ejob.Job(sm.ReindexJob.Name(), sm.ReindexJob.Run),
 	}
-	return expr
 }
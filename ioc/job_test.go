@@ -0,0 +1,51 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioc
+
+import (
+	"testing"
+
+	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/member"
+	"github.com/ecodeclub/webook/internal/order"
+	"github.com/ecodeclub/webook/internal/product"
+	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend"
+	"github.com/ecodeclub/webook/internal/review"
+	"github.com/ecodeclub/webook/internal/skill"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitCronJobs(t *testing.T) {
+	crons := InitCronJobs(&order.CloseExpiredOrdersJob{}, &order.FulfillPresaleOrdersJob{}, &member.ExpiryReminderJob{}, &product.SaleWindowJob{},
+		&review.DueQueueJob{}, &recommend.RelatedJob{},
+		&baguwen.PurgeJob{}, &cases.PurgeJob{}, &skill.PurgeJob{})
+	// 每个任务都注册了，并且表达式都是合法的
+	assert.Len(t, crons, 9)
+}
+
+func TestInitEgoJobs(t *testing.T) {
+	jobs := InitEgoJobs(&baguwen.Module{ReindexJob: &baguwen.ReindexJob{}},
+		&cases.Module{ReindexJob: &cases.ReindexJob{}},
+		&skill.Module{ReindexJob: &skill.ReindexJob{}})
+	// 搜索的重新同步都可以通过命令行执行
+	assert.Len(t, jobs, 5)
+}
+
+func TestInitCronJob_InvalidSpec(t *testing.T) {
+	assert.Panics(t, func() {
+		initCronJob(nil, "0 0 25 * * *", &review.DueQueueJob{})
+	})
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 ecodeclub
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ioc
+
+import (
+	"time"
+
+	"github.com/ecodeclub/webook/internal/member"
+	"github.com/ecodeclub/webook/internal/pkg/middleware"
+	"github.com/gotomicro/ego/core/econf"
+)
+
+func InitCheckMembershipMiddlewareBuilder(svc member.Service) *middleware.CheckMembershipMiddlewareBuilder {
+	type Config struct {
+		GraceDays int `yaml:"graceDays"`
+	}
+	var cfg Config
+	err := econf.UnmarshalKey("member", &cfg)
+	if err != nil {
+		panic(err)
+	}
+	return middleware.NewCheckMembershipMiddlewareBuilder(svc).
+		GracePeriod(time.Duration(cfg.GraceDays) * time.Hour * 24)
+}
@@ -18,12 +18,23 @@ package ioc
 
 import (
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/checkin"
+	"github.com/ecodeclub/webook/internal/comment"
 	"github.com/ecodeclub/webook/internal/cos"
+	"github.com/ecodeclub/webook/internal/credit"
+	"github.com/ecodeclub/webook/internal/evaluation"
 	"github.com/ecodeclub/webook/internal/feedback"
+	"github.com/ecodeclub/webook/internal/interview"
 	"github.com/ecodeclub/webook/internal/label"
 	"github.com/ecodeclub/webook/internal/member"
-	"github.com/ecodeclub/webook/internal/pkg/middleware"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/order"
+	"github.com/ecodeclub/webook/internal/practice"
+	"github.com/ecodeclub/webook/internal/product"
 	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend"
+	"github.com/ecodeclub/webook/internal/review"
+	"github.com/ecodeclub/webook/internal/search"
 	"github.com/ecodeclub/webook/internal/skill"
 	"github.com/google/wire"
 )
@@ -35,19 +46,51 @@ func InitApp() (*App, error) {
 		BaseSet,
 		InitSession,
 		cos.InitHandler,
+		recommend.InitRelatedService,
+		skill.InitQuestionRefService,
+		skill.InitCaseRefService,
+		recommend.InitCaseRelatedService,
 		baguwen.InitModule,
-		wire.FieldsOf(new(*baguwen.Module), "Hdl", "QsHdl"),
+		wire.FieldsOf(new(*baguwen.Module), "Hdl", "QsHdl", "PurgeJob"),
 		InitUserHandler,
-		label.InitHandler,
+		label.InitModule,
+		wire.FieldsOf(new(*label.Module), "Hdl"),
 		cases.InitModule,
-		wire.FieldsOf(new(*cases.Module), "Hdl"),
-		skill.InitHandler,
+		wire.FieldsOf(new(*cases.Module), "Hdl", "PurgeJob"),
+		skill.InitModule,
+		wire.FieldsOf(new(*skill.Module), "Hdl", "PurgeJob"),
 		feedback.InitHandler,
+		checkin.InitHandler,
+		search.InitModule,
+		wire.FieldsOf(new(*search.Module), "Hdl"),
+		practice.InitModule,
+		wire.FieldsOf(new(*practice.Module), "Hdl"),
+		review.InitModule,
+		wire.FieldsOf(new(*review.Module), "Hdl", "DueQueueJob"),
+		interview.InitModule,
+		wire.FieldsOf(new(*interview.Module), "Hdl"),
+		credit.InitService,
+		evaluation.InitModule,
+		wire.FieldsOf(new(*evaluation.Module), "Hdl"),
+		note.InitModule,
+		wire.FieldsOf(new(*note.Module), "Hdl"),
+		comment.InitModule,
+		wire.FieldsOf(new(*comment.Module), "Hdl"),
 		// 会员服务
 		member.InitModule,
 		wire.FieldsOf(new(*member.Module), "Svc"),
 		// 会员检查中间件
-		middleware.NewCheckMembershipMiddlewareBuilder,
-		initGinxServer)
+		InitCheckMembershipMiddlewareBuilder,
+		initGinxServer,
+		InitEgoJobs,
+		// 定时任务
+		recommend.InitModule,
+		wire.FieldsOf(new(*recommend.Module), "RelatedJob"),
+		product.InitService,
+		product.InitSaleWindowJob,
+		order.InitCloseExpiredOrdersJob,
+		order.InitFulfillPresaleOrdersJob,
+		member.InitExpiryReminderJob,
+		InitCronJobs)
 	return new(App), nil
 }
@@ -8,12 +8,23 @@ package ioc
 
 import (
 	"github.com/ecodeclub/webook/internal/cases"
+	"github.com/ecodeclub/webook/internal/checkin"
+	"github.com/ecodeclub/webook/internal/comment"
 	"github.com/ecodeclub/webook/internal/cos"
+	"github.com/ecodeclub/webook/internal/credit"
+	"github.com/ecodeclub/webook/internal/evaluation"
 	"github.com/ecodeclub/webook/internal/feedback"
+	"github.com/ecodeclub/webook/internal/interview"
 	"github.com/ecodeclub/webook/internal/label"
 	"github.com/ecodeclub/webook/internal/member"
-	"github.com/ecodeclub/webook/internal/pkg/middleware"
+	"github.com/ecodeclub/webook/internal/note"
+	"github.com/ecodeclub/webook/internal/order"
+	"github.com/ecodeclub/webook/internal/practice"
+	"github.com/ecodeclub/webook/internal/product"
 	baguwen "github.com/ecodeclub/webook/internal/question"
+	"github.com/ecodeclub/webook/internal/recommend"
+	"github.com/ecodeclub/webook/internal/review"
+	"github.com/ecodeclub/webook/internal/search"
 	"github.com/ecodeclub/webook/internal/skill"
 	"github.com/google/wire"
 )
@@ -25,39 +36,82 @@ func InitApp() (*App, error) {
 	provider := InitSession(cmdable)
 	db := InitDB()
 	mq := InitMQ()
-	module, err := member.InitModule(db, mq)
+	cache := InitCache(cmdable)
+	module, err := member.InitModule(db, mq, cache)
 	if err != nil {
 		return nil, err
 	}
 	service := module.Svc
-	checkMembershipMiddlewareBuilder := middleware.NewCheckMembershipMiddlewareBuilder(service)
-	cache := InitCache(cmdable)
-	baguwenModule, err := baguwen.InitModule(db, cache)
+	checkMembershipMiddlewareBuilder := InitCheckMembershipMiddlewareBuilder(service)
+	labelModule := label.InitModule(db)
+	practiceModule := practice.InitModule(db)
+	noteModule := note.InitModule(db)
+	relatedService := recommend.InitRelatedService(db)
+	referenceService := skill.InitQuestionRefService(db, cache)
+	baguwenModule, err := baguwen.InitModule(db, cache, mq, labelModule, practiceModule, noteModule, relatedService, referenceService)
 	if err != nil {
 		return nil, err
 	}
 	handler := baguwenModule.Hdl
 	questionSetHandler := baguwenModule.QsHdl
-	webHandler := label.InitHandler(db)
+	webHandler := labelModule.Hdl
 	handler2 := InitUserHandler(db, cache, mq, module)
 	config := InitCosConfig()
 	handler3 := cos.InitHandler(config)
-	casesModule, err := cases.InitModule(db, cache)
+	serviceReferenceService := skill.InitCaseRefService(db, cache)
+	serviceRelatedService := recommend.InitCaseRelatedService(db)
+	casesModule, err := cases.InitModule(db, cache, mq, labelModule, noteModule, serviceReferenceService, serviceRelatedService)
 	if err != nil {
 		return nil, err
 	}
 	handler4 := casesModule.Hdl
-	handler5, err := skill.InitHandler(db, cache, baguwenModule, casesModule)
+	skillModule, err := skill.InitModule(db, cache, baguwenModule, casesModule, practiceModule, mq)
 	if err != nil {
 		return nil, err
 	}
+	handler5 := skillModule.Hdl
 	handler6, err := feedback.InitHandler(db, mq)
 	if err != nil {
 		return nil, err
 	}
-	component := initGinxServer(provider, checkMembershipMiddlewareBuilder, handler, questionSetHandler, webHandler, handler2, handler3, handler4, handler5, handler6)
+	handler7, err := checkin.InitHandler(db, cmdable, mq)
+	if err != nil {
+		return nil, err
+	}
+	searchModule, err := search.InitModule(mq)
+	if err != nil {
+		return nil, err
+	}
+	handler8 := searchModule.Hdl
+	handler9 := practiceModule.Hdl
+	reviewModule := review.InitModule(db, cmdable, baguwenModule)
+	handler10 := reviewModule.Hdl
+	interviewModule := interview.InitModule(db, skillModule, baguwenModule, casesModule)
+	handler11 := interviewModule.Hdl
+	serviceService := credit.InitService(db)
+	evaluationModule := evaluation.InitModule(db, cmdable, baguwenModule, serviceService)
+	handler12 := evaluationModule.Hdl
+	handler13 := noteModule.Hdl
+	commentModule := comment.InitModule(db, baguwenModule, casesModule, skillModule)
+	handler14 := commentModule.Hdl
+	component := initGinxServer(provider, checkMembershipMiddlewareBuilder, handler, questionSetHandler, webHandler, handler2, handler3, handler4, handler5, handler6, handler7, handler8, handler9, handler10, handler11, handler12, handler13, handler14)
+	v := InitEgoJobs(baguwenModule, casesModule, skillModule)
+	service2 := product.InitService(db, cmdable)
+	closeExpiredOrdersJob := order.InitCloseExpiredOrdersJob(db, mq, service2)
+	fulfillPresaleOrdersJob := order.InitFulfillPresaleOrdersJob(db, mq, service2)
+	expiryReminderJob := member.InitExpiryReminderJob(db, mq, cache)
+	saleWindowJob := product.InitSaleWindowJob(db, cmdable)
+	dueQueueJob := reviewModule.DueQueueJob
+	recommendModule := recommend.InitModule(db, baguwenModule, casesModule, skillModule)
+	relatedJob := recommendModule.RelatedJob
+	purgeJob := baguwenModule.PurgeJob
+	jobPurgeJob := casesModule.PurgeJob
+	purgeJob2 := skillModule.PurgeJob
+	v2 := InitCronJobs(closeExpiredOrdersJob, fulfillPresaleOrdersJob, expiryReminderJob, saleWindowJob, dueQueueJob, relatedJob, purgeJob, jobPurgeJob, purgeJob2)
 	app := &App{
-		Web: component,
+		Web:   component,
+		Jobs:  v,
+		Crons: v2,
 	}
 	return app, nil
 }
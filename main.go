@@ -18,6 +18,9 @@ func main() {
 	err = egoApp.
 		// Invoker 在 Ego 里面，应该叫做初始化函数
 		Invoker().
+		// 指定了 --job 的时候只执行任务，不会启动 Web 服务
+		Job(app.Jobs...).
+		Cron(app.Crons...).
 		Serve(app.Web).
 		Run()
 	panic(err)